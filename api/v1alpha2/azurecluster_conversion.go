@@ -59,14 +59,54 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Status.FailureDomains = restored.Status.FailureDomains
+	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Spec.ResourceGroupID = restored.Spec.ResourceGroupID
+	dst.Spec.NetworkResourceGroup = restored.Spec.NetworkResourceGroup
+	dst.Spec.IdentityRef = restored.Spec.IdentityRef
+	dst.Spec.AzureEnvironment = restored.Spec.AzureEnvironment
+	dst.Spec.FailureDomains = restored.Spec.FailureDomains
+	dst.Spec.ProximityPlacementGroup = restored.Spec.ProximityPlacementGroup
+	dst.Spec.DiskEncryptionSetID = restored.Spec.DiskEncryptionSetID
+	dst.Spec.DefaultImage = restored.Spec.DefaultImage
+	dst.Spec.EnforcedTags = restored.Spec.EnforcedTags
+	dst.Spec.CloudProviderConfig = restored.Spec.CloudProviderConfig
+	dst.Status.Network.APIServerIPv6 = restored.Status.Network.APIServerIPv6
+	dst.Status.Network.InternalLBIPAddress = restored.Status.Network.InternalLBIPAddress
+	dst.Status.Network.InternalLBZones = restored.Status.Network.InternalLBZones
+	dst.Status.Network.NodeOutboundIPs = restored.Status.Network.NodeOutboundIPs
+	dst.Status.Network.PrivateLinkServiceAlias = restored.Status.Network.PrivateLinkServiceAlias
+	dst.Status.Network.ResourceIDs = restored.Status.Network.ResourceIDs
+	dst.Spec.NetworkSpec.Vnet.IPv6CidrBlock = restored.Spec.NetworkSpec.Vnet.IPv6CidrBlock
+	dst.Spec.NetworkSpec.Vnet.DNSServers = restored.Spec.NetworkSpec.Vnet.DNSServers
+	dst.Spec.NetworkSpec.APIServerLB = restored.Spec.NetworkSpec.APIServerLB
+	dst.Spec.NetworkSpec.LoadBalancerSKU = restored.Spec.NetworkSpec.LoadBalancerSKU
+	dst.Spec.NetworkSpec.NodeOutboundLB = restored.Spec.NetworkSpec.NodeOutboundLB
+	dst.Spec.NetworkSpec.NodeOutboundLBDisabled = restored.Spec.NetworkSpec.NodeOutboundLBDisabled
+	dst.Spec.NetworkSpec.ControlPlaneOutboundLB = restored.Spec.NetworkSpec.ControlPlaneOutboundLB
+	dst.Spec.NetworkSpec.PrivateDNSZoneName = restored.Spec.NetworkSpec.PrivateDNSZoneName
+	dst.Spec.NetworkSpec.VnetPeerings = restored.Spec.NetworkSpec.VnetPeerings
+	dst.Spec.NetworkSpec.PrivateEndpoints = restored.Spec.NetworkSpec.PrivateEndpoints
+	dst.Spec.NetworkSpec.Bastion = restored.Spec.NetworkSpec.Bastion
+	dst.Spec.NetworkSpec.ApplicationGateway = restored.Spec.NetworkSpec.ApplicationGateway
+	dst.Spec.NetworkSpec.AllowedAPIServerCIDRs = restored.Spec.NetworkSpec.AllowedAPIServerCIDRs
+	dst.Spec.NetworkSpec.SSHDisabled = restored.Spec.NetworkSpec.SSHDisabled
+	dst.Status.Bastion.Evicted = restored.Status.Bastion.Evicted
+	dst.Status.Bastion.OSDisk.DiffDiskSettings = restored.Status.Bastion.OSDisk.DiffDiskSettings
+	dst.Spec.NetworkSpec.AcceleratedNetworking = restored.Spec.NetworkSpec.AcceleratedNetworking
 
 	for _, restoredSubnet := range restored.Spec.NetworkSpec.Subnets {
 		if restoredSubnet != nil {
 			for _, dstSubnet := range dst.Spec.NetworkSpec.Subnets {
 				if dstSubnet != nil && dstSubnet.Name == restoredSubnet.Name {
 					dstSubnet.RouteTable = restoredSubnet.RouteTable
+					dstSubnet.NatGateway = restoredSubnet.NatGateway
+					dstSubnet.IPv6CidrBlock = restoredSubnet.IPv6CidrBlock
+					dstSubnet.ServiceEndpoints = restoredSubnet.ServiceEndpoints
+					dstSubnet.Delegations = restoredSubnet.Delegations
+					dstSubnet.Zones = restoredSubnet.Zones
 
 					dstSubnet.SecurityGroup.IngressRules = restoredSubnet.SecurityGroup.IngressRules
+					dstSubnet.SecurityGroup.SecurityRules = restoredSubnet.SecurityGroup.SecurityRules
 				}
 			}
 		}
@@ -203,6 +243,21 @@ func Convert_v1alpha3_NetworkSpec_To_v1alpha2_NetworkSpec(in *infrav1alpha3.Netw
 	return nil
 }
 
+// Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec.
+func Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in *infrav1alpha3.VnetSpec, out *VnetSpec, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in, out, s)
+}
+
+// Convert_v1alpha3_VM_To_v1alpha2_VM.
+func Convert_v1alpha3_VM_To_v1alpha2_VM(in *infrav1alpha3.VM, out *VM, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_VM_To_v1alpha2_VM(in, out, s)
+}
+
+// Convert_v1alpha3_Network_To_v1alpha2_Network.
+func Convert_v1alpha3_Network_To_v1alpha2_Network(in *infrav1alpha3.Network, out *Network, s apiconversion.Scope) error { //nolint
+	return autoConvert_v1alpha3_Network_To_v1alpha2_Network(in, out, s)
+}
+
 // Convert_v1alpha2_SubnetSpec_To_v1alpha3_SubnetSpec.
 func Convert_v1alpha2_SubnetSpec_To_v1alpha3_SubnetSpec(in *SubnetSpec, out *infrav1alpha3.SubnetSpec, s apiconversion.Scope) error { //nolint
 	return autoConvert_v1alpha2_SubnetSpec_To_v1alpha3_SubnetSpec(in, out, s)
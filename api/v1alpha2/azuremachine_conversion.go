@@ -38,6 +38,9 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	restoreAzureMachineSpec(&restored.Spec, &dst.Spec)
+	dst.Status.OSDisk = restored.Status.OSDisk
+	dst.Status.Image = restored.Status.Image
+	dst.Status.VMExtensions = restored.Status.VMExtensions
 
 	// Manual conversion for conditions
 	dst.SetConditions(restored.GetConditions())
@@ -59,9 +62,27 @@ func restoreAzureMachineSpec(restored, dst *infrav1alpha3.AzureMachineSpec) {
 	if restored.SpotVMOptions != nil {
 		dst.SpotVMOptions = restored.SpotVMOptions.DeepCopy()
 	}
+	if restored.DedicatedHost != nil {
+		dst.DedicatedHost = restored.DedicatedHost.DeepCopy()
+	}
+	dst.DiskEncryptionSetID = restored.DiskEncryptionSetID
+	dst.SubnetName = restored.SubnetName
+	dst.AdminUsername = restored.AdminUsername
+	if restored.BootDiagnostics != nil {
+		dst.BootDiagnostics = restored.BootDiagnostics.DeepCopy()
+	}
+	if len(restored.VMExtensions) != 0 {
+		dst.VMExtensions = restored.VMExtensions
+	}
 	if len(restored.DataDisks) != 0 {
 		dst.DataDisks = restored.DataDisks
 	}
+	if restored.OSDisk.DiffDiskSettings != nil {
+		dst.OSDisk.DiffDiskSettings = restored.OSDisk.DiffDiskSettings.DeepCopy()
+	}
+	if restored.Image != nil && restored.Image.Marketplace != nil && dst.Image != nil && dst.Image.Marketplace != nil {
+		dst.Image.Marketplace.Plan = restored.Image.Marketplace.Plan.DeepCopy()
+	}
 }
 
 // ConvertFrom converts from the Hub version (v1alpha3) to this version.
@@ -162,6 +183,11 @@ func Convert_v1alpha2_Image_To_v1alpha3_Image(in *Image, out *infrav1alpha3.Imag
 	return nil
 }
 
+// Convert_v1alpha3_OSDisk_To_v1alpha2_OSDisk converts an OSDisk from v1alpha3 to v1alpha2
+func Convert_v1alpha3_OSDisk_To_v1alpha2_OSDisk(in *infrav1alpha3.OSDisk, out *OSDisk, s apiconversion.Scope) error { // nolint
+	return autoConvert_v1alpha3_OSDisk_To_v1alpha2_OSDisk(in, out, s)
+}
+
 // Convert_v1alpha3_Image_To_v1alpha2_Image converts Images from v1alpha3 to v1alpha2
 func Convert_v1alpha3_Image_To_v1alpha2_Image(in *infrav1alpha3.Image, out *Image, s apiconversion.Scope) error { // nolint
 	if in.ID != nil {
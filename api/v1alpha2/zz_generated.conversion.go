@@ -176,21 +176,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha3.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_Network_To_v1alpha2_Network(a.(*v1alpha3.Network), b.(*Network), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OSDisk)(nil), (*v1alpha3.OSDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_OSDisk_To_v1alpha3_OSDisk(a.(*OSDisk), b.(*v1alpha3.OSDisk), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha3.OSDisk)(nil), (*OSDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_OSDisk_To_v1alpha2_OSDisk(a.(*v1alpha3.OSDisk), b.(*OSDisk), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*PublicIP)(nil), (*v1alpha3.PublicIP)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_PublicIP_To_v1alpha3_PublicIP(a.(*PublicIP), b.(*v1alpha3.PublicIP), scope)
 	}); err != nil {
@@ -206,21 +196,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha3.VM)(nil), (*VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_VM_To_v1alpha2_VM(a.(*v1alpha3.VM), b.(*VM), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VnetSpec)(nil), (*v1alpha3.VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VnetSpec_To_v1alpha3_VnetSpec(a.(*VnetSpec), b.(*v1alpha3.VnetSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha3.VnetSpec)(nil), (*VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(a.(*v1alpha3.VnetSpec), b.(*VnetSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddConversionFunc((*AzureClusterSpec)(nil), (*v1alpha3.AzureClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AzureClusterSpec_To_v1alpha3_AzureClusterSpec(a.(*AzureClusterSpec), b.(*v1alpha3.AzureClusterSpec), scope)
 	}); err != nil {
@@ -306,6 +286,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha3.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_Network_To_v1alpha2_Network(a.(*v1alpha3.Network), b.(*Network), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha3.OSDisk)(nil), (*OSDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_OSDisk_To_v1alpha2_OSDisk(a.(*v1alpha3.OSDisk), b.(*OSDisk), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha3.SecurityGroup)(nil), (*SecurityGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_SecurityGroup_To_v1alpha2_SecurityGroup(a.(*v1alpha3.SecurityGroup), b.(*SecurityGroup), scope)
 	}); err != nil {
@@ -316,6 +306,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha3.VM)(nil), (*VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VM_To_v1alpha2_VM(a.(*v1alpha3.VM), b.(*VM), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha3.VnetSpec)(nil), (*VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(a.(*v1alpha3.VnetSpec), b.(*VnetSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -430,10 +430,20 @@ func autoConvert_v1alpha3_AzureClusterSpec_To_v1alpha2_AzureClusterSpec(in *v1al
 		return err
 	}
 	out.ResourceGroup = in.ResourceGroup
+	// WARNING: in.ResourceGroupID requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkResourceGroup requires manual conversion: does not exist in peer-type
 	// WARNING: in.SubscriptionID requires manual conversion: does not exist in peer-type
 	out.Location = in.Location
+	// WARNING: in.AzureEnvironment requires manual conversion: does not exist in peer-type
 	// WARNING: in.ControlPlaneEndpoint requires manual conversion: does not exist in peer-type
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
+	// WARNING: in.EnforcedTags requires manual conversion: does not exist in peer-type
+	// WARNING: in.IdentityRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProximityPlacementGroup requires manual conversion: does not exist in peer-type
+	// WARNING: in.DiskEncryptionSetID requires manual conversion: does not exist in peer-type
+	// WARNING: in.DefaultImage requires manual conversion: does not exist in peer-type
+	// WARNING: in.CloudProviderConfig requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -459,6 +469,7 @@ func autoConvert_v1alpha3_AzureClusterStatus_To_v1alpha2_AzureClusterStatus(in *
 	}
 	out.Ready = in.Ready
 	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -585,10 +596,16 @@ func autoConvert_v1alpha3_AzureMachineSpec_To_v1alpha2_AzureMachineSpec(in *v1al
 	// WARNING: in.DataDisks requires manual conversion: does not exist in peer-type
 	out.Location = in.Location
 	out.SSHPublicKey = in.SSHPublicKey
+	// WARNING: in.AdminUsername requires manual conversion: does not exist in peer-type
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
 	out.AllocatePublicIP = in.AllocatePublicIP
 	// WARNING: in.AcceleratedNetworking requires manual conversion: does not exist in peer-type
+	// WARNING: in.SubnetName requires manual conversion: does not exist in peer-type
 	// WARNING: in.SpotVMOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.DedicatedHost requires manual conversion: does not exist in peer-type
+	// WARNING: in.DiskEncryptionSetID requires manual conversion: does not exist in peer-type
+	// WARNING: in.BootDiagnostics requires manual conversion: does not exist in peer-type
+	// WARNING: in.VMExtensions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -605,6 +622,9 @@ func autoConvert_v1alpha3_AzureMachineStatus_To_v1alpha2_AzureMachineStatus(in *
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.VMState = (*VMState)(unsafe.Pointer(in.VMState))
+	// WARNING: in.OSDisk requires manual conversion: does not exist in peer-type
+	// WARNING: in.Image requires manual conversion: does not exist in peer-type
+	// WARNING: in.VMExtensions requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureReason requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureMessage requires manual conversion: does not exist in peer-type
 	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
@@ -915,14 +935,15 @@ func autoConvert_v1alpha3_Network_To_v1alpha2_Network(in *v1alpha3.Network, out
 	if err := Convert_v1alpha3_PublicIP_To_v1alpha2_PublicIP(&in.APIServerIP, &out.APIServerIP, s); err != nil {
 		return err
 	}
+	// WARNING: in.APIServerIPv6 requires manual conversion: does not exist in peer-type
+	// WARNING: in.InternalLBIPAddress requires manual conversion: does not exist in peer-type
+	// WARNING: in.InternalLBZones requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeOutboundIPs requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrivateLinkServiceAlias requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceIDs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha3_Network_To_v1alpha2_Network is an autogenerated conversion function.
-func Convert_v1alpha3_Network_To_v1alpha2_Network(in *v1alpha3.Network, out *Network, s conversion.Scope) error {
-	return autoConvert_v1alpha3_Network_To_v1alpha2_Network(in, out, s)
-}
-
 func autoConvert_v1alpha2_NetworkSpec_To_v1alpha3_NetworkSpec(in *NetworkSpec, out *v1alpha3.NetworkSpec, s conversion.Scope) error {
 	if err := Convert_v1alpha2_VnetSpec_To_v1alpha3_VnetSpec(&in.Vnet, &out.Vnet, s); err != nil {
 		return err
@@ -958,6 +979,20 @@ func autoConvert_v1alpha3_NetworkSpec_To_v1alpha2_NetworkSpec(in *v1alpha3.Netwo
 	} else {
 		out.Subnets = nil
 	}
+	// WARNING: in.APIServerLB requires manual conversion: does not exist in peer-type
+	// WARNING: in.LoadBalancerSKU requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeOutboundLB requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeOutboundLBDisabled requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneOutboundLB requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrivateDNSZoneName requires manual conversion: does not exist in peer-type
+	// WARNING: in.AcceleratedNetworking requires manual conversion: does not exist in peer-type
+	// WARNING: in.SubnetName requires manual conversion: does not exist in peer-type
+	// WARNING: in.VnetPeerings requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrivateEndpoints requires manual conversion: does not exist in peer-type
+	// WARNING: in.Bastion requires manual conversion: does not exist in peer-type
+	// WARNING: in.ApplicationGateway requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowedAPIServerCIDRs requires manual conversion: does not exist in peer-type
+	// WARNING: in.SSHDisabled requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -981,14 +1016,10 @@ func autoConvert_v1alpha3_OSDisk_To_v1alpha2_OSDisk(in *v1alpha3.OSDisk, out *OS
 	if err := Convert_v1alpha3_ManagedDisk_To_v1alpha2_ManagedDisk(&in.ManagedDisk, &out.ManagedDisk, s); err != nil {
 		return err
 	}
+	// WARNING: in.DiffDiskSettings requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha3_OSDisk_To_v1alpha2_OSDisk is an autogenerated conversion function.
-func Convert_v1alpha3_OSDisk_To_v1alpha2_OSDisk(in *v1alpha3.OSDisk, out *OSDisk, s conversion.Scope) error {
-	return autoConvert_v1alpha3_OSDisk_To_v1alpha2_OSDisk(in, out, s)
-}
-
 func autoConvert_v1alpha2_PublicIP_To_v1alpha3_PublicIP(in *PublicIP, out *v1alpha3.PublicIP, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Name = in.Name
@@ -1049,6 +1080,7 @@ func autoConvert_v1alpha3_SecurityGroup_To_v1alpha2_SecurityGroup(in *v1alpha3.S
 	} else {
 		out.IngressRules = nil
 	}
+	// WARNING: in.SecurityRules requires manual conversion: does not exist in peer-type
 	out.Tags = *(*Tags)(unsafe.Pointer(&in.Tags))
 	return nil
 }
@@ -1070,11 +1102,16 @@ func autoConvert_v1alpha3_SubnetSpec_To_v1alpha2_SubnetSpec(in *v1alpha3.SubnetS
 	out.ID = in.ID
 	out.Name = in.Name
 	out.CidrBlock = in.CidrBlock
+	// WARNING: in.IPv6CidrBlock requires manual conversion: does not exist in peer-type
 	out.InternalLBIPAddress = in.InternalLBIPAddress
 	if err := Convert_v1alpha3_SecurityGroup_To_v1alpha2_SecurityGroup(&in.SecurityGroup, &out.SecurityGroup, s); err != nil {
 		return err
 	}
 	// WARNING: in.RouteTable requires manual conversion: does not exist in peer-type
+	// WARNING: in.NatGateway requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServiceEndpoints requires manual conversion: does not exist in peer-type
+	// WARNING: in.Delegations requires manual conversion: does not exist in peer-type
+	// WARNING: in.Zones requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1118,14 +1155,10 @@ func autoConvert_v1alpha3_VM_To_v1alpha2_VM(in *v1alpha3.VM, out *VM, s conversi
 	out.Identity = VMIdentity(in.Identity)
 	out.Tags = *(*Tags)(unsafe.Pointer(&in.Tags))
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
+	// WARNING: in.Evicted requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha3_VM_To_v1alpha2_VM is an autogenerated conversion function.
-func Convert_v1alpha3_VM_To_v1alpha2_VM(in *v1alpha3.VM, out *VM, s conversion.Scope) error {
-	return autoConvert_v1alpha3_VM_To_v1alpha2_VM(in, out, s)
-}
-
 func autoConvert_v1alpha2_VnetSpec_To_v1alpha3_VnetSpec(in *VnetSpec, out *v1alpha3.VnetSpec, s conversion.Scope) error {
 	out.ResourceGroup = in.ResourceGroup
 	out.ID = in.ID
@@ -1145,11 +1178,8 @@ func autoConvert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in *v1alpha3.VnetSpec, o
 	out.ID = in.ID
 	out.Name = in.Name
 	out.CidrBlock = in.CidrBlock
+	// WARNING: in.IPv6CidrBlock requires manual conversion: does not exist in peer-type
+	// WARNING: in.DNSServers requires manual conversion: does not exist in peer-type
 	out.Tags = *(*Tags)(unsafe.Pointer(&in.Tags))
 	return nil
 }
-
-// Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec is an autogenerated conversion function.
-func Convert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in *v1alpha3.VnetSpec, out *VnetSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha3_VnetSpec_To_v1alpha2_VnetSpec(in, out, s)
-}
@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"encoding/binary"
 	"fmt"
+	"net"
+	"strings"
 )
 
 const (
@@ -27,6 +30,11 @@ const (
 	DefaultControlPlaneSubnetCIDR = "10.0.0.0/16"
 	// DefaultNodeSubnetCIDR is the default Node Subnet CIDR
 	DefaultNodeSubnetCIDR = "10.1.0.0/16"
+
+	// bastionSubnetName is the name Azure requires for the subnet of a bastion host
+	bastionSubnetName = "AzureBastionSubnet"
+	// bastionSubnetPrefix is the prefix of the default bastion subnet, the smallest subnet Azure allows for a bastion host
+	bastionSubnetPrefix = 26
 )
 
 func (c *AzureCluster) setDefaults() {
@@ -36,11 +44,17 @@ func (c *AzureCluster) setDefaults() {
 func (c *AzureCluster) setNetworkSpecDefaults() {
 	c.setVnetDefaults()
 	c.setSubnetDefaults()
+	c.setBastionDefaults()
+	c.setAPIServerLBDefaults()
+	c.setLoadBalancerSKUDefaults()
 }
 
 func (c *AzureCluster) setVnetDefaults() {
 	if c.Spec.NetworkSpec.Vnet.ResourceGroup == "" {
 		c.Spec.NetworkSpec.Vnet.ResourceGroup = c.Spec.ResourceGroup
+		if c.Spec.NetworkResourceGroup != "" {
+			c.Spec.NetworkSpec.Vnet.ResourceGroup = c.Spec.NetworkResourceGroup
+		}
 	}
 	if c.Spec.NetworkSpec.Vnet.Name == "" {
 		c.Spec.NetworkSpec.Vnet.Name = generateVnetName(c.ObjectMeta.Name)
@@ -72,8 +86,18 @@ func (c *AzureCluster) setSubnetDefaults() {
 	if cpSubnet.SecurityGroup.Name == "" {
 		cpSubnet.SecurityGroup.Name = generateControlPlaneSecurityGroupName(c.ObjectMeta.Name)
 	}
-	if cpSubnet.RouteTable.Name == "" {
-		cpSubnet.RouteTable.Name = generateRouteTableName(c.ObjectMeta.Name)
+	cpSubnet.RouteTable.setDefaultName(generateRouteTableName(c.ObjectMeta.Name))
+
+	// Additional control plane subnets share the security group and route table of the internal LB subnet unless specified.
+	for _, subnet := range c.Spec.NetworkSpec.GetControlPlaneSubnets() {
+		if subnet.SecurityGroup.Name == "" {
+			subnet.SecurityGroup.Name = cpSubnet.SecurityGroup.Name
+		}
+		if subnet.RouteTable.Name == "" && subnet.RouteTable.ID == "" {
+			subnet.RouteTable.Name = cpSubnet.RouteTable.Name
+			subnet.RouteTable.ID = cpSubnet.RouteTable.ID
+		}
+		subnet.RouteTable.setDefaultName(cpSubnet.RouteTable.Name)
 	}
 
 	if nodeSubnet.Name == "" {
@@ -85,8 +109,68 @@ func (c *AzureCluster) setSubnetDefaults() {
 	if nodeSubnet.SecurityGroup.Name == "" {
 		nodeSubnet.SecurityGroup.Name = generateNodeSecurityGroupName(c.ObjectMeta.Name)
 	}
-	if nodeSubnet.RouteTable.Name == "" {
-		nodeSubnet.RouteTable.Name = generateRouteTableName(c.ObjectMeta.Name)
+	nodeSubnet.RouteTable.setDefaultName(generateRouteTableName(c.ObjectMeta.Name))
+
+	// Additional node subnets share the security group and route table of the first node subnet unless specified.
+	for _, subnet := range c.Spec.NetworkSpec.GetNodeSubnets() {
+		if subnet.SecurityGroup.Name == "" {
+			subnet.SecurityGroup.Name = nodeSubnet.SecurityGroup.Name
+		}
+		if subnet.RouteTable.Name == "" && subnet.RouteTable.ID == "" {
+			subnet.RouteTable.Name = nodeSubnet.RouteTable.Name
+			subnet.RouteTable.ID = nodeSubnet.RouteTable.ID
+		}
+		subnet.RouteTable.setDefaultName(nodeSubnet.RouteTable.Name)
+	}
+}
+
+// setBastionDefaults defaults the CIDR block of the bastion subnet to the last /26 of the vnet, so it fits a vnet
+// with another CIDR block than the default one.
+func (c *AzureCluster) setBastionDefaults() {
+	bastion := c.Spec.NetworkSpec.Bastion
+	if bastion != nil && bastion.Subnet.CidrBlock == "" {
+		bastion.Subnet.CidrBlock = c.Spec.NetworkSpec.Vnet.DefaultBastionSubnetCIDR()
+	}
+}
+
+// DefaultBastionSubnetCIDR returns the last /26 of the IPv4 CIDR block of the vnet, the default CIDR block of the
+// bastion subnet, or an empty string when the vnet has no such CIDR block or it is smaller than a /26.
+func (v *VnetSpec) DefaultBastionSubnetCIDR() string {
+	_, vnet, err := net.ParseCIDR(v.CidrBlock)
+	if err != nil || vnet.IP.To4() == nil {
+		return ""
+	}
+	ones, bits := vnet.Mask.Size()
+	if ones > bastionSubnetPrefix {
+		return ""
+	}
+	last := binary.BigEndian.Uint32(vnet.IP.To4()) + uint32(1)<<uint(bits-ones) - uint32(1)<<uint(bits-bastionSubnetPrefix)
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, last)
+	return fmt.Sprintf("%s/%d", ip, bastionSubnetPrefix)
+}
+
+// setDefaultName names the route table after its ID when it is set, or the default name otherwise.
+func (r *RouteTable) setDefaultName(defaultName string) {
+	if r.Name != "" {
+		return
+	}
+	if r.ID != "" {
+		r.Name = r.ID[strings.LastIndex(r.ID, "/")+1:]
+		return
+	}
+	r.Name = defaultName
+}
+
+func (c *AzureCluster) setAPIServerLBDefaults() {
+	if c.Spec.NetworkSpec.APIServerLB.Type == "" {
+		c.Spec.NetworkSpec.APIServerLB.Type = Public
+	}
+}
+
+func (c *AzureCluster) setLoadBalancerSKUDefaults() {
+	if c.Spec.NetworkSpec.LoadBalancerSKU == "" {
+		c.Spec.NetworkSpec.LoadBalancerSKU = SKUStandard
 	}
 }
 
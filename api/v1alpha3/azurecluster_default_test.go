@@ -132,6 +132,60 @@ func TestVnetDefaults(t *testing.T) {
 	}
 }
 
+func TestVnetResourceGroupDefaultsToNetworkResourceGroup(t *testing.T) {
+	cluster := &AzureCluster{Spec: AzureClusterSpec{ResourceGroup: "cluster-test"}}
+	cluster.setVnetDefaults()
+	if rg := cluster.Spec.NetworkSpec.Vnet.ResourceGroup; rg != "cluster-test" {
+		t.Errorf("Expected vnet resource group cluster-test, got %s", rg)
+	}
+
+	cluster = &AzureCluster{Spec: AzureClusterSpec{ResourceGroup: "cluster-test", NetworkResourceGroup: "network-test"}}
+	cluster.setVnetDefaults()
+	if rg := cluster.Spec.NetworkSpec.Vnet.ResourceGroup; rg != "network-test" {
+		t.Errorf("Expected vnet resource group network-test, got %s", rg)
+	}
+}
+
+func TestBastionDefaults(t *testing.T) {
+	cases := []struct {
+		name         string
+		vnetCIDR     string
+		bastion      *BastionSpec
+		expectedCIDR string
+	}{
+		{name: "default vnet", vnetCIDR: DefaultVnetCIDR, bastion: &BastionSpec{}, expectedCIDR: "10.255.255.192/26"},
+		{name: "vnet with another CIDR block", vnetCIDR: "172.16.0.0/16", bastion: &BastionSpec{}, expectedCIDR: "172.16.255.192/26"},
+		{name: "vnet of a /26", vnetCIDR: "172.16.0.64/26", bastion: &BastionSpec{}, expectedCIDR: "172.16.0.64/26"},
+		{name: "vnet smaller than a /26", vnetCIDR: "172.16.0.0/27", bastion: &BastionSpec{}, expectedCIDR: ""},
+		{name: "IPv6 vnet", vnetCIDR: "2001:1234:5678:9a00::/56", bastion: &BastionSpec{}, expectedCIDR: ""},
+		{
+			name:         "bastion subnet with a CIDR block",
+			vnetCIDR:     "172.16.0.0/16",
+			bastion:      &BastionSpec{Subnet: BastionSubnetSpec{CidrBlock: "172.16.1.0/24"}},
+			expectedCIDR: "172.16.1.0/24",
+		},
+	}
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &AzureCluster{Spec: AzureClusterSpec{NetworkSpec: NetworkSpec{
+				Vnet:    VnetSpec{CidrBlock: tc.vnetCIDR},
+				Bastion: tc.bastion,
+			}}}
+			cluster.setBastionDefaults()
+			if cidr := cluster.Spec.NetworkSpec.Bastion.Subnet.CidrBlock; cidr != tc.expectedCIDR {
+				t.Errorf("Expected bastion subnet CIDR block %q, got %q", tc.expectedCIDR, cidr)
+			}
+		})
+	}
+
+	cluster := &AzureCluster{Spec: AzureClusterSpec{NetworkSpec: NetworkSpec{Vnet: VnetSpec{CidrBlock: DefaultVnetCIDR}}}}
+	cluster.setBastionDefaults()
+	if cluster.Spec.NetworkSpec.Bastion != nil {
+		t.Errorf("Expected no bastion, got %v", cluster.Spec.NetworkSpec.Bastion)
+	}
+}
+
 func TestSubnetDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -223,6 +277,130 @@ func TestSubnetDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "multiple node subnets",
+			cluster: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Role:      SubnetNode,
+								Name:      "my-node-subnet-1",
+								CidrBlock: "10.1.0.0/16",
+							},
+							{
+								Role:          SubnetNode,
+								Name:          "my-node-subnet-2",
+								CidrBlock:     "10.2.0.0/16",
+								SecurityGroup: SecurityGroup{Name: "my-node-nsg"},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Role:          SubnetNode,
+								Name:          "my-node-subnet-1",
+								CidrBlock:     "10.1.0.0/16",
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+							{
+								Role:          SubnetNode,
+								Name:          "my-node-subnet-2",
+								CidrBlock:     "10.2.0.0/16",
+								SecurityGroup: SecurityGroup{Name: "my-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+							{
+								Role:          SubnetControlPlane,
+								Name:          "cluster-test-controlplane-subnet",
+								CidrBlock:     DefaultControlPlaneSubnetCIDR,
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "multiple control plane subnets",
+			cluster: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{InternalLBZones: []string{"2"}},
+						Subnets: Subnets{
+							{
+								Role:      SubnetControlPlane,
+								Name:      "my-controlplane-subnet-1",
+								CidrBlock: "10.0.1.0/24",
+								Zones:     []string{"1"},
+							},
+							{
+								Role:          SubnetControlPlane,
+								Name:          "my-controlplane-subnet-2",
+								CidrBlock:     "10.0.2.0/24",
+								SecurityGroup: SecurityGroup{Name: "my-controlplane-nsg"},
+								Zones:         []string{"2"},
+							},
+							{
+								Role: SubnetNode,
+								Name: "my-node-subnet",
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{InternalLBZones: []string{"2"}},
+						Subnets: Subnets{
+							{
+								Role:          SubnetControlPlane,
+								Name:          "my-controlplane-subnet-1",
+								CidrBlock:     "10.0.1.0/24",
+								SecurityGroup: SecurityGroup{Name: "my-controlplane-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+								Zones:         []string{"1"},
+							},
+							{
+								Role:          SubnetControlPlane,
+								Name:          "my-controlplane-subnet-2",
+								CidrBlock:     "10.0.2.0/24",
+								SecurityGroup: SecurityGroup{Name: "my-controlplane-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+								Zones:         []string{"2"},
+							},
+							{
+								Role:          SubnetNode,
+								Name:          "my-node-subnet",
+								CidrBlock:     DefaultNodeSubnetCIDR,
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "subnets specified",
 			cluster: &AzureCluster{
@@ -313,6 +491,68 @@ func TestSubnetDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "node subnet with a pre-existing route table",
+			cluster: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Role: SubnetNode,
+								Name: "my-node-subnet",
+								RouteTable: RouteTable{
+									ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/routeTables/hub-routetable",
+								},
+							},
+							{
+								Role: SubnetNode,
+								Name: "my-node-subnet-2",
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{
+								Role:          SubnetNode,
+								Name:          "my-node-subnet",
+								CidrBlock:     DefaultNodeSubnetCIDR,
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable: RouteTable{
+									ID:   "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/routeTables/hub-routetable",
+									Name: "hub-routetable",
+								},
+							},
+							{
+								Role:          SubnetNode,
+								Name:          "my-node-subnet-2",
+								SecurityGroup: SecurityGroup{Name: "cluster-test-node-nsg"},
+								RouteTable: RouteTable{
+									ID:   "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/routeTables/hub-routetable",
+									Name: "hub-routetable",
+								},
+							},
+							{
+								Role:          SubnetControlPlane,
+								Name:          "cluster-test-controlplane-subnet",
+								CidrBlock:     DefaultControlPlaneSubnetCIDR,
+								SecurityGroup: SecurityGroup{Name: "cluster-test-controlplane-nsg"},
+								RouteTable:    RouteTable{Name: "cluster-test-node-routetable"},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -328,3 +568,53 @@ func TestSubnetDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIServerLBDefaults(t *testing.T) {
+	cases := []struct {
+		name    string
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		{
+			name:    "no lb type",
+			cluster: &AzureCluster{},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Type: Public},
+					},
+				},
+			},
+		},
+		{
+			name: "internal lb",
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Type: Internal},
+					},
+				},
+			},
+			output: &AzureCluster{
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{Type: Internal},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.cluster.setAPIServerLBDefaults()
+			if !reflect.DeepEqual(tc.cluster, tc.output) {
+				expected, _ := json.MarshalIndent(tc.output, "", "\t")
+				actual, _ := json.MarshalIndent(tc.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
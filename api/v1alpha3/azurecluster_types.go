@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 )
@@ -25,6 +28,22 @@ const (
 	// ClusterFinalizer allows ReconcileAzureCluster to clean up Azure resources associated with AzureCluster before
 	// removing it from the apiserver.
 	ClusterFinalizer = "azurecluster.infrastructure.cluster.x-k8s.io"
+
+	// DryRunAnnotation set to "true" on an AzureCluster makes its reconcile plan the changes to its Azure resources,
+	// and report them as events and in the DryRun condition, without making them.
+	DryRunAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/dry-run"
+
+	// ResyncPeriodAnnotation set to a duration on an AzureCluster, e.g. "5m", makes the controller reconcile it at this
+	// interval instead of the sync period of the controller. It must be between MinResyncPeriod and MaxResyncPeriod.
+	ResyncPeriodAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/resync-period"
+
+	// MinResyncPeriod is the shortest resync period of an AzureCluster, below which its reconciles would mostly add load
+	// on the Azure APIs and risk their throttling.
+	MinResyncPeriod = time.Minute
+
+	// MaxResyncPeriod is the longest resync period of an AzureCluster, so the drift of its Azure resources is still
+	// corrected the same day.
+	MaxResyncPeriod = 24 * time.Hour
 )
 
 // AzureClusterSpec defines the desired state of AzureCluster
@@ -34,10 +53,30 @@ type AzureClusterSpec struct {
 
 	ResourceGroup string `json:"resourceGroup"`
 
+	// ResourceGroupID is the ID of a pre-existing resource group the cluster is created in.
+	// The provider sets it when the resource group already exists and is not owned by the cluster.
+	// A pre-existing resource group is not deleted with the cluster, only the resources the cluster owns in it are.
+	// +optional
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
+
+	// NetworkResourceGroup is the resource group of the networking resources of the cluster, its security groups,
+	// route tables, NAT gateways, public IPs and prefixes, load balancers and bastion host. The vnet defaults to it.
+	// It defaults to the cluster resource group. A different group must already exist, and is not deleted with the
+	// cluster, only the resources the cluster owns in it are.
+	// +optional
+	NetworkResourceGroup string `json:"networkResourceGroup,omitempty"`
+
 	SubscriptionID string `json:"subscriptionID,omitempty"`
 
 	Location string `json:"location"`
 
+	// AzureEnvironment is the name of the Azure cloud environment of the cluster, it determines the
+	// ARM endpoint, the AAD authority and the DNS suffix of the public IPs.
+	// The AZURE_ENVIRONMENT of the controller is used when it is not set, AzurePublicCloud by default.
+	// +kubebuilder:validation:Enum=AzurePublicCloud;AzureUSGovernmentCloud;AzureChinaCloud;AzureGermanCloud
+	// +optional
+	AzureEnvironment string `json:"azureEnvironment,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
@@ -46,6 +85,44 @@ type AzureClusterSpec struct {
 	// ones added by default.
 	// +optional
 	AdditionalTags Tags `json:"additionalTags,omitempty"`
+
+	// EnforcedTags is an optional set of tags, e.g. for cost allocation, which are guaranteed on the Azure resources of
+	// the cluster and of its machines. Unlike the additional tags, which are only set when they change in the spec,
+	// enforced tags are checked on every reconcile, and set again when they were removed or changed out-of-band.
+	// They take precedence over the additional tags of the AzureCluster and of its machines.
+	// +optional
+	EnforcedTags Tags `json:"enforcedTags,omitempty"`
+
+	// IdentityRef is a reference to an AzureClusterIdentity to be used when reconciling this cluster.
+	// The AzureClusterIdentity must be in the namespace of the AzureCluster, which is used if the reference has no namespace.
+	// The credentials of the controller are used when it is not set.
+	// +optional
+	IdentityRef *corev1.ObjectReference `json:"identityRef,omitempty"`
+
+	// FailureDomains configures which availability zones of the location are reported as failure domains
+	// and which of them can host control plane machines. Every zone is eligible when it is not set.
+	// +optional
+	FailureDomains *FailureDomainsSpec `json:"failureDomains,omitempty"`
+
+	// ProximityPlacementGroup places the VMs of the cluster in a proximity placement group of the cluster
+	// resource group, for a low network latency between them.
+	// +optional
+	ProximityPlacementGroup *ProximityPlacementGroupSpec `json:"proximityPlacementGroup,omitempty"`
+
+	// DiskEncryptionSetID is the resource ID of the disk encryption set encrypting the OS and data disks of the
+	// machines of the cluster with a customer-managed key. Machines can override it.
+	// +optional
+	DiskEncryptionSetID string `json:"diskEncryptionSetID,omitempty"`
+
+	// DefaultImage is the image of the machines of the cluster which don't set an image. The image of a machine takes
+	// precedence over it.
+	// +optional
+	DefaultImage *Image `json:"defaultImage,omitempty"`
+
+	// CloudProviderConfig makes the provider render the azure.json configuration of the Azure cloud provider and of
+	// the Azure disk and file CSI drivers into the <cluster name>-azure-json secret, to be delivered to the nodes.
+	// +optional
+	CloudProviderConfig *CloudProviderConfigSpec `json:"cloudProviderConfig,omitempty"`
 }
 
 // AzureClusterStatus defines the observed state of AzureCluster
@@ -69,6 +146,11 @@ type AzureClusterStatus struct {
 	// Conditions defines current service state of the AzureCluster.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// LongRunningOperationStates saves the states of the long-running operations in progress on the Azure resources
+	// of the cluster, so they are polled across reconciles instead of being started again.
+	// +optional
+	LongRunningOperationStates []Future `json:"longRunningOperationStates,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -18,11 +18,15 @@ package v1alpha3
 
 import (
 	"fmt"
+	"net"
 	"regexp"
+	"strings"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 )
 
 const (
@@ -31,12 +35,63 @@ const (
 	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
 	subnetRegex = `^[-\w\._]+$`
 	ipv4Regex   = `^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`
+	// the resource ID of a virtual network
+	vnetIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/[-\w\._\(\)]+/providers/Microsoft\.Network/virtualNetworks/[-\w\._]+$`
+	// the resource ID of a subnet, capturing the resource group, vnet and subnet names
+	subnetIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/([-\w\._\(\)]+)/providers/Microsoft\.Network/virtualNetworks/([-\w\._]+)/subnets/([-\w\._]+)$`
+	// the resource ID of a route table
+	routeTableIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/[-\w\._\(\)]+/providers/Microsoft\.Network/routeTables/[-\w\._]+$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
+	publicIPNameRegex = `^[a-zA-Z0-9]([-\w\.]{0,78}[a-zA-Z0-9_])?$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
+	privateEndpointNameRegex = `^[a-zA-Z0-9]([-\w\.]{0,62}[a-zA-Z0-9_])?$`
+	// the resource ID of an Azure resource a private endpoint can connect to, e.g. a key vault
+	privateLinkResourceIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/[-\w\._\(\)]+/providers/[-\w\.]+(/[-\w\.]+/[-\w\._]+)+$`
+	// described in https://docs.microsoft.com/en-us/rest/api/virtualnetwork/publicipaddresses/createorupdate#publicipaddressdnssettings
+	dnsLabelRegex = `^[a-z][a-z0-9-]{1,61}[a-z0-9]$`
+	// the resource ID of a public IP
+	publicIPIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/[-\w\._\(\)]+/providers/Microsoft\.Network/publicIPAddresses/[-\w\.]+$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules
+	inboundNatRuleNameRegex = `^[a-zA-Z0-9]([-\w\.]{0,78}[a-zA-Z0-9_])?$`
+	// the resource ID of a private DNS zone
+	privateDNSZoneIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/[-\w\._\(\)]+/providers/Microsoft\.Network/privateDnsZones/[-\w\._]+$`
+	// the ID of a subscription
+	subscriptionIDRegex = `^[0-9a-fA-F]{8}-([0-9a-fA-F]{4}-){3}[0-9a-fA-F]{12}$`
+	// the resource ID of a user-assigned identity
+	userAssignedIdentityIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/[-\w\._\(\)]+/providers/Microsoft\.ManagedIdentity/userAssignedIdentities/[-\w\._]+$`
+	// the resource ID of a web application firewall policy
+	firewallPolicyIDRegex = `^(?i)/subscriptions/[^/]+/resourceGroups/[-\w\._\(\)]+/providers/Microsoft\.Network/ApplicationGatewayWebApplicationFirewallPolicies/[-\w\._]+$`
+	// the ID of a Key Vault secret, with or without its version
+	keyVaultSecretIDRegex = `^https://[-\w\.]+/secrets/[-a-zA-Z0-9]+(/[0-9a-fA-F]*)?$`
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources#limitations
+	maxTagKeyLength    = 512
+	maxTagValueLength  = 256
+	invalidTagKeyChars = `<>%&\?/`
 )
 
 // validateCluster validates a cluster
 func (c *AzureCluster) validateCluster() error {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, c.validateClusterSpec()...)
+	allErrs = append(allErrs, validateResyncPeriod(c.Annotations)...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "infrastructure.cluster.x-k8s.io", Kind: "AzureCluster"},
+		c.Name, allErrs)
+}
+
+// validateClusterUpdate validates an update of a cluster
+func (c *AzureCluster) validateClusterUpdate(old *AzureCluster) error {
+	allErrs := c.validateClusterSpec()
+	allErrs = append(allErrs, validateResyncPeriod(c.Annotations)...)
+	if !strings.EqualFold(networkResourceGroup(c.Spec), networkResourceGroup(old.Spec)) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("networkResourceGroup"), c.Spec.NetworkResourceGroup,
+			"the network resource group is immutable"))
+	}
+	allErrs = append(allErrs, c.validateImmutableFields(old)...)
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -46,11 +101,170 @@ func (c *AzureCluster) validateCluster() error {
 		c.Name, allErrs)
 }
 
+// validateResyncPeriod validates the resync period annotation of a cluster, when it is set.
+func validateResyncPeriod(annotations map[string]string) field.ErrorList {
+	value, ok := annotations[ResyncPeriodAnnotation]
+	if !ok {
+		return nil
+	}
+	fldPath := field.NewPath("metadata", "annotations").Key(ResyncPeriodAnnotation)
+	period, err := time.ParseDuration(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, value, "must be a duration, e.g. 5m")}
+	}
+	if period < MinResyncPeriod || period > MaxResyncPeriod {
+		return field.ErrorList{field.Invalid(fldPath, value,
+			fmt.Sprintf("must be between %s and %s", MinResyncPeriod, MaxResyncPeriod))}
+	}
+	return nil
+}
+
+// networkResourceGroup returns the resource group of the networking resources of a cluster, which defaults to the
+// cluster resource group.
+func networkResourceGroup(spec AzureClusterSpec) string {
+	if spec.NetworkResourceGroup != "" {
+		return spec.NetworkResourceGroup
+	}
+	return spec.ResourceGroup
+}
+
+// validateImmutableFields validates that the fields of a provisioned cluster which identify its Azure resources are
+// unchanged. Changing them would point the cluster to other resources, or to resources Azure can't update.
+func (c *AzureCluster) validateImmutableFields(old *AzureCluster) field.ErrorList {
+	if !old.Status.Ready {
+		return nil
+	}
+	specPath := field.NewPath("spec")
+	vnetPath := specPath.Child("networkSpec").Child("vnet")
+	var allErrs field.ErrorList
+	for _, f := range []struct {
+		path     *field.Path
+		old, new string
+	}{
+		{specPath.Child("location"), old.Spec.Location, c.Spec.Location},
+		{specPath.Child("resourceGroup"), old.Spec.ResourceGroup, c.Spec.ResourceGroup},
+		{vnetPath.Child("resourceGroup"), old.Spec.NetworkSpec.Vnet.ResourceGroup, c.Spec.NetworkSpec.Vnet.ResourceGroup},
+		{vnetPath.Child("name"), old.Spec.NetworkSpec.Vnet.Name, c.Spec.NetworkSpec.Vnet.Name},
+		{vnetPath.Child("cidrBlock"), old.Spec.NetworkSpec.Vnet.CidrBlock, c.Spec.NetworkSpec.Vnet.CidrBlock},
+		{specPath.Child("networkSpec").Child("apiServerLB").Child("dnsLabel"), old.Spec.NetworkSpec.APIServerLB.DNSLabel, c.Spec.NetworkSpec.APIServerLB.DNSLabel},
+		{specPath.Child("networkSpec").Child("apiServerLB").Child("publicIPID"), old.Spec.NetworkSpec.APIServerLB.PublicIPID, c.Spec.NetworkSpec.APIServerLB.PublicIPID},
+		{specPath.Child("networkSpec").Child("apiServerLB").Child("internalLBZones"), strings.Join(old.Spec.NetworkSpec.APIServerLB.InternalLBZones, ","), strings.Join(c.Spec.NetworkSpec.APIServerLB.InternalLBZones, ",")},
+	} {
+		if !strings.EqualFold(f.old, f.new) {
+			allErrs = append(allErrs, field.Invalid(f.path, f.new,
+				fmt.Sprintf("cannot be changed from %q once the cluster is provisioned", f.old)))
+		}
+	}
+	return allErrs
+}
+
 // validateClusterSpec validates a ClusterSpec
 func (c *AzureCluster) validateClusterSpec() field.ErrorList {
-	return validateNetworkSpec(
-		c.Spec.NetworkSpec,
-		field.NewPath("spec").Child("networkSpec"))
+	fldPath := field.NewPath("spec").Child("networkSpec")
+	allErrs := validateNetworkSpec(c.Spec.NetworkSpec, fldPath)
+	if c.Spec.NetworkResourceGroup != "" {
+		if err := validateResourceGroup(c.Spec.NetworkResourceGroup, field.NewPath("spec").Child("networkResourceGroup")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	allErrs = append(allErrs, validateAdditionalAPIServerIPs(c.Spec.NetworkSpec, c.Status.Network.APIServerIP.Name,
+		fldPath.Child("apiServerLB").Child("additionalPublicIPNames"))...)
+	allErrs = append(allErrs, validateFailureDomains(c.Spec.FailureDomains, c.Spec.NetworkSpec.LoadBalancerSKU, field.NewPath("spec").Child("failureDomains"))...)
+	allErrs = append(allErrs, validateProximityPlacementGroup(c.Spec.ProximityPlacementGroup, c.Spec.FailureDomains, c.Spec.NetworkSpec.LoadBalancerSKU,
+		field.NewPath("spec").Child("proximityPlacementGroup"))...)
+	allErrs = append(allErrs, ValidateDiskEncryptionSetID(c.Spec.DiskEncryptionSetID, field.NewPath("spec").Child("diskEncryptionSetID"))...)
+	allErrs = append(allErrs, ValidateImage(c.Spec.DefaultImage, field.NewPath("spec").Child("defaultImage"))...)
+	allErrs = append(allErrs, validateCloudProviderConfig(c.Spec.CloudProviderConfig, field.NewPath("spec").Child("cloudProviderConfig"))...)
+	return allErrs
+}
+
+// validateCloudProviderConfig validates that the user-assigned identity ID of the cloud provider configuration is set
+// with, and only with, the UserAssigned identity.
+func validateCloudProviderConfig(config *CloudProviderConfigSpec, fldPath *field.Path) field.ErrorList {
+	if config == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if config.Identity == VMIdentityUserAssigned && config.UserAssignedIdentityID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("userAssignedIdentityID"),
+			fmt.Sprintf("is required with the %s identity", VMIdentityUserAssigned)))
+	}
+	if config.Identity != VMIdentityUserAssigned && config.UserAssignedIdentityID != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("userAssignedIdentityID"),
+			fmt.Sprintf("can only be set with the %s identity", VMIdentityUserAssigned)))
+	}
+	return allErrs
+}
+
+// validateFailureDomains validates that no zone is both included and excluded, and that the control plane zones are
+// unique, not excluded, and not combined with included zones or with a Basic load balancer, which has no failure domains.
+func validateFailureDomains(failureDomains *FailureDomainsSpec, sku SKU, fldPath *field.Path) field.ErrorList {
+	if failureDomains == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	included := make(map[string]bool, len(failureDomains.Include))
+	for _, zone := range failureDomains.Include {
+		included[zone] = true
+	}
+	excluded := make(map[string]bool, len(failureDomains.Exclude))
+	for i, zone := range failureDomains.Exclude {
+		if included[zone] {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("exclude").Index(i), zone,
+				"a zone cannot be both included and excluded"))
+		}
+		excluded[zone] = true
+	}
+	if len(failureDomains.ControlPlaneZones) > 0 && sku == SKUBasic {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("controlPlaneZones"),
+			fmt.Sprintf("a cluster with the %s load balancer SKU has no failure domains", SKUBasic)))
+	}
+	if len(failureDomains.ControlPlaneZones) > 0 && len(failureDomains.Include) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("include"),
+			"included zones cannot be combined with control plane zones, which are the only zones eligible for control plane machines"))
+	}
+	pinned := make(map[string]bool, len(failureDomains.ControlPlaneZones))
+	for i, zone := range failureDomains.ControlPlaneZones {
+		zonePath := fldPath.Child("controlPlaneZones").Index(i)
+		if pinned[zone] {
+			allErrs = append(allErrs, field.Duplicate(zonePath, zone))
+		}
+		pinned[zone] = true
+		if excluded[zone] {
+			allErrs = append(allErrs, field.Invalid(zonePath, zone, "a control plane zone cannot be excluded"))
+		}
+	}
+	return allErrs
+}
+
+// validateProximityPlacementGroup validates that the failure domain of a proximity placement group can be a failure
+// domain of the cluster: it can't be excluded, and a cluster with a Basic load balancer has no failure domains.
+func validateProximityPlacementGroup(ppg *ProximityPlacementGroupSpec, failureDomains *FailureDomainsSpec, sku SKU, fldPath *field.Path) field.ErrorList {
+	if ppg == nil || ppg.FailureDomain == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if sku == SKUBasic {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("failureDomain"), *ppg.FailureDomain,
+			fmt.Sprintf("a cluster with the %s load balancer SKU has no failure domains", SKUBasic)))
+	}
+	if failureDomains != nil {
+		for _, zone := range failureDomains.Exclude {
+			if zone == *ppg.FailureDomain {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("failureDomain"), *ppg.FailureDomain,
+					"the failure domain of the proximity placement group cannot be excluded"))
+			}
+		}
+		pinned := len(failureDomains.ControlPlaneZones) == 0
+		for _, zone := range failureDomains.ControlPlaneZones {
+			pinned = pinned || zone == *ppg.FailureDomain
+		}
+		if !pinned {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("failureDomain"), *ppg.FailureDomain,
+				"the failure domain of the proximity placement group must be one of the control plane zones"))
+		}
+	}
+	return allErrs
 }
 
 // validateNetworkSpec validates a NetworkSpec
@@ -66,6 +280,43 @@ func validateNetworkSpec(networkSpec NetworkSpec, fldPath *field.Path) field.Err
 		}
 		allErrs = append(allErrs, validateSubnets(networkSpec.Subnets, fldPath.Child("subnets"))...)
 	}
+	allErrs = append(allErrs, validateNatGateways(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validateControlPlaneSubnets(networkSpec, fldPath.Child("subnets"))...)
+	allErrs = append(allErrs, validateServiceEndpoints(networkSpec.Subnets, fldPath.Child("subnets"))...)
+	allErrs = append(allErrs, validateDelegations(networkSpec.Subnets, fldPath.Child("subnets"))...)
+	allErrs = append(allErrs, validateIPv6(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validateSubnetCIDRs(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validateNodeOutboundLB(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validateControlPlaneOutboundLB(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validatePublicIPZones(networkSpec, fldPath)...)
+	allErrs = append(allErrs, validateHealthProbe(networkSpec.APIServerLB.HealthProbe, fldPath.Child("apiServerLB").Child("healthProbe"))...)
+	allErrs = append(allErrs, validateLoadDistribution(networkSpec.APIServerLB.LoadDistribution, fldPath.Child("apiServerLB").Child("loadDistribution"))...)
+	allErrs = append(allErrs, validatePublicIPTags(networkSpec.APIServerLB.PublicIPTags, fldPath.Child("apiServerLB").Child("publicIPTags"))...)
+	allErrs = append(allErrs, validateInternalLBInboundNatRules(networkSpec.APIServerLB.InternalLBInboundNatRules, fldPath.Child("apiServerLB").Child("internalLBInboundNatRules"))...)
+	allErrs = append(allErrs, validateAPIServerDNSLabel(networkSpec.APIServerLB, fldPath.Child("apiServerLB").Child("dnsLabel"))...)
+	allErrs = append(allErrs, validateAPIServerPublicIPID(networkSpec, fldPath.Child("apiServerLB"))...)
+	allErrs = append(allErrs, validatePrivateLinkService(networkSpec, fldPath.Child("apiServerLB").Child("privateLinkService"))...)
+	allErrs = append(allErrs, validateApplicationGateway(networkSpec.ApplicationGateway, fldPath.Child("applicationGateway"))...)
+	allErrs = append(allErrs, validateVnetPeerings(networkSpec.VnetPeerings, fldPath.Child("vnetPeerings"))...)
+	allErrs = append(allErrs, validatePrivateEndpoints(networkSpec, fldPath.Child("privateEndpoints"))...)
+	allErrs = append(allErrs, validateAllowedAPIServerCIDRs(networkSpec.AllowedAPIServerCIDRs, fldPath.Child("allowedAPIServerCIDRs"))...)
+	allErrs = append(allErrs, validateDNSServers(networkSpec.Vnet.DNSServers, fldPath.Child("vnet").Child("dnsServers"))...)
+	for i, subnet := range networkSpec.Subnets {
+		allErrs = append(allErrs, validateSecurityRules(subnet.SecurityGroup,
+			fldPath.Child("subnets").Index(i).Child("securityGroup"))...)
+		if subnet.Role == SubnetControlPlane {
+			allErrs = append(allErrs, validateLBHealthProbeSecurityRule(subnet.SecurityGroup,
+				fldPath.Child("subnets").Index(i).Child("securityGroup"))...)
+		}
+		allErrs = append(allErrs, validateRouteTable(subnet.RouteTable,
+			fldPath.Child("subnets").Index(i).Child("routeTable"))...)
+		if subnet.ID != "" {
+			if err := validateSubnetID(subnet.ID, subnet.Name, networkSpec.Vnet,
+				fldPath.Child("subnets").Index(i).Child("id")); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -132,6 +383,855 @@ func validateSubnets(subnets Subnets, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// validateControlPlaneSubnets validates the zones of the control plane subnets, and that the static IP of the internal
+// load balancer is only set on the control plane subnet of its frontend.
+func validateControlPlaneSubnets(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	lbSubnet := networkSpec.GetControlPlaneSubnet()
+	zoneSubnets := make(map[string]string)
+	for i, subnet := range networkSpec.Subnets {
+		zonesPath := fldPath.Index(i).Child("zones")
+		if subnet.Role != SubnetControlPlane {
+			if len(subnet.Zones) > 0 {
+				allErrs = append(allErrs, field.Forbidden(zonesPath, "only control plane subnets have zones"))
+			}
+			continue
+		}
+		allErrs = append(allErrs, validateZones(networkSpec.LoadBalancerSKU, subnet.Zones, zonesPath)...)
+		for j, zone := range subnet.Zones {
+			if other, ok := zoneSubnets[zone]; ok && other != subnet.Name {
+				allErrs = append(allErrs, field.Invalid(zonesPath.Index(j), zone,
+					fmt.Sprintf("zone is already listed by control plane subnet %s", other)))
+			}
+			zoneSubnets[zone] = subnet.Name
+		}
+		if subnet != lbSubnet && subnet.InternalLBIPAddress != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("internalLBIPAddress"), subnet.InternalLBIPAddress,
+				fmt.Sprintf("the frontend of the internal load balancer is in control plane subnet %s", lbSubnet.Name)))
+		}
+	}
+	return allErrs
+}
+
+// validateNatGateways validates the NAT gateways of the subnets.
+// A NAT gateway replaces the node outbound load balancer, so either every node subnet has one or none does.
+func validateNatGateways(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	var withNatGateway, withoutNatGateway int
+	for i, subnet := range networkSpec.Subnets {
+		if subnet.Role == SubnetControlPlane {
+			if subnet.NatGateway.Name != "" {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("subnets").Index(i).Child("natGateway").Child("name"), subnet.NatGateway.Name,
+					"a NAT gateway can only be attached to node subnets"))
+			}
+			continue
+		}
+		if subnet.NatGateway.Name != "" {
+			withNatGateway++
+		} else {
+			withoutNatGateway++
+		}
+	}
+	if withNatGateway == 0 {
+		return allErrs
+	}
+	if withoutNatGateway > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnets"), withoutNatGateway,
+			"node subnets without a NAT gateway would need the node outbound load balancer, which is not created when NAT gateways are used; all node subnets must have a NAT gateway or none"))
+	}
+	if networkSpec.LoadBalancerSKU == SKUBasic {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("loadBalancerSku"), networkSpec.LoadBalancerSKU,
+			fmt.Sprintf("NAT gateways cannot be used with the %s load balancer SKU", SKUBasic)))
+	}
+	return allErrs
+}
+
+var serviceEndpointRegexp = regexp.MustCompile(`^Microsoft\.[A-Za-z]+(\.[A-Za-z]+)*$`)
+
+// validateServiceEndpoints validates the service endpoints of the subnets. Whether a service is available in the
+// location of the cluster is only known to Azure, and is checked when the subnet is reconciled.
+func validateServiceEndpoints(subnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, subnet := range subnets {
+		services := make(map[string]bool, len(subnet.ServiceEndpoints))
+		for j, service := range subnet.ServiceEndpoints {
+			servicePath := fldPath.Index(i).Child("serviceEndpoints").Index(j)
+			if !serviceEndpointRegexp.MatchString(service) {
+				allErrs = append(allErrs, field.Invalid(servicePath, service, "a service endpoint must be the name of an Azure service, e.g. Microsoft.Storage"))
+			}
+			if services[strings.ToLower(service)] {
+				allErrs = append(allErrs, field.Duplicate(servicePath, service))
+			}
+			services[strings.ToLower(service)] = true
+		}
+	}
+	return allErrs
+}
+
+var delegationRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(\.[A-Za-z][A-Za-z0-9]*)+(/[A-Za-z][A-Za-z0-9]*)+$`)
+
+// validateDelegations validates the delegations of the subnets. Whether a subnet can be delegated to a service in the
+// location of the cluster is only known to Azure, and is checked when the subnet is reconciled.
+func validateDelegations(subnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, subnet := range subnets {
+		services := make(map[string]bool, len(subnet.Delegations))
+		for j, service := range subnet.Delegations {
+			servicePath := fldPath.Index(i).Child("delegations").Index(j)
+			if !delegationRegexp.MatchString(service) {
+				allErrs = append(allErrs, field.Invalid(servicePath, service, "a delegation must be the resource type of an Azure service, e.g. Microsoft.Netapp/volumes"))
+			}
+			if services[strings.ToLower(service)] {
+				allErrs = append(allErrs, field.Duplicate(servicePath, service))
+			}
+			services[strings.ToLower(service)] = true
+		}
+	}
+	return allErrs
+}
+
+// validateIPv6 validates the IPv6 CIDR blocks of a dual-stack network.
+// Either the vnet and all of its subnets have an IPv6 CIDR block or none of them do.
+func validateIPv6(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	dualStack := networkSpec.Vnet.IPv6CidrBlock != ""
+	for _, subnet := range networkSpec.Subnets {
+		dualStack = dualStack || subnet.IPv6CidrBlock != ""
+	}
+	if !dualStack {
+		return nil
+	}
+	if !feature.Gates.Enabled(feature.IPv6DualStack) {
+		return field.ErrorList{field.Forbidden(fldPath.Child("vnet").Child("ipv6CidrBlock"),
+			"can be set only if the IPv6DualStack feature flag is enabled")}
+	}
+
+	if networkSpec.LoadBalancerSKU == SKUBasic {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("loadBalancerSku"), networkSpec.LoadBalancerSKU,
+			fmt.Sprintf("dual-stack networks require the %s load balancer SKU", SKUStandard)))
+	}
+	if err := validateIPv6CIDR(networkSpec.Vnet.IPv6CidrBlock, fldPath.Child("vnet").Child("ipv6CidrBlock")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	for i, subnet := range networkSpec.Subnets {
+		if err := validateIPv6CIDR(subnet.IPv6CidrBlock, fldPath.Child("subnets").Index(i).Child("ipv6CidrBlock")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return allErrs
+}
+
+// validateSubnetCIDRs validates that the CIDR blocks of the subnets, and of the bastion subnet, are within the CIDR
+// block of the vnet and don't overlap each other, in the IPv4 address space and, for dual-stack networks, in the IPv6
+// address space.
+func validateSubnetCIDRs(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	ipv4 := make([]subnetCIDR, 0, len(networkSpec.Subnets)+1)
+	ipv6 := make([]subnetCIDR, 0, len(networkSpec.Subnets))
+	if networkSpec.Vnet.CidrBlock != "" {
+		if _, _, err := net.ParseCIDR(networkSpec.Vnet.CidrBlock); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("vnet").Child("cidrBlock"), networkSpec.Vnet.CidrBlock,
+				"must be a valid CIDR block"))
+		}
+	}
+	for i, subnet := range networkSpec.Subnets {
+		subnetPath := fldPath.Child("subnets").Index(i)
+		if subnet.CidrBlock != "" {
+			if _, _, err := net.ParseCIDR(subnet.CidrBlock); err != nil {
+				allErrs = append(allErrs, field.Invalid(subnetPath.Child("cidrBlock"), subnet.CidrBlock,
+					"must be a valid CIDR block"))
+			}
+		}
+		ipv4 = append(ipv4, subnetCIDR{name: subnet.Name, cidr: subnet.CidrBlock, path: subnetPath.Child("cidrBlock")})
+		ipv6 = append(ipv6, subnetCIDR{name: subnet.Name, cidr: subnet.IPv6CidrBlock, path: subnetPath.Child("ipv6CidrBlock")})
+	}
+	if bastion := networkSpec.Bastion; bastion != nil {
+		cidrPath := fldPath.Child("bastion").Child("subnet").Child("cidrBlock")
+		name := bastion.Subnet.Name
+		if name == "" {
+			name = bastionSubnetName
+		}
+		if bastion.Subnet.CidrBlock == "" {
+			allErrs = append(allErrs, field.Required(cidrPath,
+				fmt.Sprintf("the CIDR block %s of the vnet has no room for a /%d bastion subnet", networkSpec.Vnet.CidrBlock, bastionSubnetPrefix)))
+		} else if _, _, err := net.ParseCIDR(bastion.Subnet.CidrBlock); err != nil {
+			allErrs = append(allErrs, field.Invalid(cidrPath, bastion.Subnet.CidrBlock, "must be a valid CIDR block"))
+		}
+		ipv4 = append(ipv4, subnetCIDR{name: name, cidr: bastion.Subnet.CidrBlock, path: cidrPath})
+	}
+	allErrs = append(allErrs, validateAddressSpace(networkSpec.Vnet.CidrBlock, ipv4)...)
+	if networkSpec.Vnet.IsIPv6Enabled() {
+		// the format of the IPv6 CIDR blocks is validated with the rest of the dual-stack configuration
+		allErrs = append(allErrs, validateAddressSpace(networkSpec.Vnet.IPv6CidrBlock, ipv6)...)
+	}
+	return allErrs
+}
+
+// subnetCIDR is a CIDR block of a subnet, with the path of the field it is set in.
+type subnetCIDR struct {
+	name string
+	cidr string
+	path *field.Path
+}
+
+// validateAddressSpace validates that the given CIDR blocks of the subnets are within the CIDR block of the vnet and
+// don't overlap each other. Empty and invalid CIDR blocks are skipped.
+func validateAddressSpace(vnetCIDR string, subnets []subnetCIDR) field.ErrorList {
+	var allErrs field.ErrorList
+	_, vnet, _ := net.ParseCIDR(vnetCIDR)
+	parsed := make([]*net.IPNet, len(subnets))
+	for i, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.cidr)
+		if err != nil {
+			continue
+		}
+		if vnet != nil && !cidrContains(vnet, ipNet) {
+			allErrs = append(allErrs, field.Invalid(subnet.path, subnet.cidr,
+				fmt.Sprintf("subnet %s must be within the CIDR block %s of the vnet", subnet.name, vnetCIDR)))
+		}
+		for j, other := range parsed {
+			if other != nil && (other.Contains(ipNet.IP) || ipNet.Contains(other.IP)) {
+				allErrs = append(allErrs, field.Invalid(subnet.path, subnet.cidr,
+					fmt.Sprintf("subnet %s overlaps with the CIDR block %s of subnet %s", subnet.name, subnets[j].cidr, subnets[j].name)))
+			}
+		}
+		parsed[i] = ipNet
+	}
+	return allErrs
+}
+
+// cidrContains returns whether the inner CIDR block is entirely within the outer CIDR block.
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+// validateNodeOutboundLB validates the outbound rule configuration of the node outbound load balancer.
+// Outbound rules are only supported by the Standard load balancer SKU.
+func validateNodeOutboundLB(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	outboundLB := networkSpec.NodeOutboundLB
+	if networkSpec.NodeOutboundLBDisabled {
+		return validateNodeOutboundPaths(networkSpec, fldPath)
+	}
+	if outboundLB == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if networkSpec.LoadBalancerSKU == SKUBasic {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("loadBalancerSku"), networkSpec.LoadBalancerSKU,
+			fmt.Sprintf("the node outbound load balancer can only be configured with the %s load balancer SKU", SKUStandard)))
+	}
+	fldPath = fldPath.Child("nodeOutboundLB")
+	allErrs = append(allErrs, validateOutboundRule(outboundLB.AllocatedOutboundPorts, outboundLB.IdleTimeoutInMinutes, fldPath)...)
+	if count := outboundLB.FrontendIPsCount; count != nil && (*count < 1 || *count > MaxNodeOutboundIPs) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *count,
+			fmt.Sprintf("the %s load balancer SKU supports between 1 and %d node outbound IPs", SKUStandard, MaxNodeOutboundIPs)))
+	}
+	if length := outboundLB.PublicIPPrefixLength; length != nil && (*length < 28 || *length > 31) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("publicIPPrefixLength"), *length,
+			"the public IP prefix length must be between 28 and 31"))
+	} else if count := outboundLB.FrontendIPsCount; length != nil && count != nil && *count > 1<<uint(32-*length) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *count,
+			fmt.Sprintf("a public IP prefix of length %d provides at most %d node outbound IPs", *length, 1<<uint(32-*length))))
+	}
+	allErrs = append(allErrs, validatePublicIPTags(outboundLB.PublicIPTags, fldPath.Child("publicIPTags"))...)
+	return allErrs
+}
+
+// validateNodeOutboundPaths validates that the nodes of a cluster without a node outbound load balancer still have
+// outbound connectivity.
+func validateNodeOutboundPaths(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if networkSpec.NodeOutboundLB != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeOutboundLB"), networkSpec.NodeOutboundLB,
+			"the node outbound load balancer cannot be configured when it is disabled"))
+	}
+	for i, subnet := range networkSpec.Subnets {
+		if subnet.Role != SubnetNode || hasOutboundPath(subnet) {
+			continue
+		}
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnets").Index(i), subnet.Name,
+			"a node subnet needs a NAT gateway, a pre-existing subnet or route table, or a default route to a virtual appliance or a virtual network gateway when the node outbound load balancer is disabled"))
+	}
+	return allErrs
+}
+
+// hasOutboundPath returns true if the subnet reaches the internet without the node outbound load balancer.
+// The routes of a pre-existing subnet or route table are not known, they are expected to provide an outbound path.
+func hasOutboundPath(subnet *SubnetSpec) bool {
+	if subnet.NatGateway.Name != "" || subnet.ID != "" || subnet.RouteTable.ID != "" {
+		return true
+	}
+	for _, route := range subnet.RouteTable.Routes {
+		if route.AddressPrefix == "0.0.0.0/0" && (route.NextHopType == RouteNextHopTypeVirtualAppliance || route.NextHopType == RouteNextHopTypeVirtualNetworkGateway) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateControlPlaneOutboundLB validates the control plane outbound load balancer, which is only created for
+// clusters with an internal API server load balancer of the Standard SKU.
+func validateControlPlaneOutboundLB(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	outboundLB := networkSpec.ControlPlaneOutboundLB
+	if outboundLB == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if networkSpec.LoadBalancerSKU == SKUBasic {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("loadBalancerSku"), networkSpec.LoadBalancerSKU,
+			fmt.Sprintf("the control plane outbound load balancer can only be configured with the %s load balancer SKU", SKUStandard)))
+	}
+	if networkSpec.APIServerLB.Type != Internal {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiServerLB").Child("type"), networkSpec.APIServerLB.Type,
+			fmt.Sprintf("the control plane outbound load balancer can only be configured with an API server load balancer of type %s", Internal)))
+	}
+	return append(allErrs, validateOutboundRule(outboundLB.AllocatedOutboundPorts, outboundLB.IdleTimeoutInMinutes, fldPath.Child("controlPlaneOutboundLB"))...)
+}
+
+// validateOutboundRule validates the SNAT port allocation and idle timeout of the outbound rule of a load balancer.
+func validateOutboundRule(allocatedOutboundPorts, idleTimeoutInMinutes *int32, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ports := allocatedOutboundPorts; ports != nil {
+		if *ports < 0 || *ports > MaxOutboundPortsPerIP {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allocatedOutboundPorts"), *ports,
+				fmt.Sprintf("the allocated outbound ports must be between 0 and %d", MaxOutboundPortsPerIP)))
+		} else if *ports%8 != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allocatedOutboundPorts"), *ports,
+				"the allocated outbound ports must be a multiple of 8"))
+		}
+	}
+	if timeout := idleTimeoutInMinutes; timeout != nil && (*timeout < 4 || *timeout > 30) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), *timeout,
+			"the idle timeout must be between 4 and 30 minutes"))
+	}
+	return allErrs
+}
+
+// validatePublicIPZones validates the availability zones of the API server and node outbound public IPs, and of the
+// frontend of the internal API server load balancer. Zonal frontends require the Standard load balancer SKU.
+func validatePublicIPZones(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateZones(networkSpec.LoadBalancerSKU, networkSpec.APIServerLB.PublicIPZones,
+		fldPath.Child("apiServerLB").Child("publicIPZones"))...)
+	allErrs = append(allErrs, validateZones(networkSpec.LoadBalancerSKU, networkSpec.APIServerLB.InternalLBZones,
+		fldPath.Child("apiServerLB").Child("internalLBZones"))...)
+	if networkSpec.NodeOutboundLB != nil {
+		allErrs = append(allErrs, validateZones(networkSpec.LoadBalancerSKU, networkSpec.NodeOutboundLB.PublicIPZones,
+			fldPath.Child("nodeOutboundLB").Child("publicIPZones"))...)
+	}
+	return allErrs
+}
+
+// validateZones validates a list of availability zones.
+func validateZones(sku SKU, zones []string, fldPath *field.Path) field.ErrorList {
+	if len(zones) == 0 {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if sku == SKUBasic {
+		allErrs = append(allErrs, field.Invalid(fldPath, zones,
+			fmt.Sprintf("availability zones require the %s load balancer SKU", SKUStandard)))
+	}
+	seen := make(map[string]bool)
+	for i, zone := range zones {
+		if zone != "1" && zone != "2" && zone != "3" {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), zone, []string{"1", "2", "3"}))
+		}
+		if seen[zone] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), zone))
+		}
+		seen[zone] = true
+	}
+	return allErrs
+}
+
+// validateHealthProbe validates the health probe of the API server load balancers.
+func validateHealthProbe(probe *HealthProbe, fldPath *field.Path) field.ErrorList {
+	if probe == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if probe.Protocol == ProbeProtocolTCP && probe.RequestPath != "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestPath"), probe.RequestPath,
+			fmt.Sprintf("a request path cannot be set for %s probes", ProbeProtocolTCP)))
+	}
+	if probe.RequestPath != "" && !strings.HasPrefix(probe.RequestPath, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestPath"), probe.RequestPath,
+			"the request path must start with /"))
+	}
+	if probe.IntervalSeconds != nil && *probe.IntervalSeconds < 5 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("intervalSeconds"), *probe.IntervalSeconds,
+			"the probe interval must be at least 5 seconds"))
+	}
+	if probe.UnhealthyThreshold != nil && *probe.UnhealthyThreshold < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("unhealthyThreshold"), *probe.UnhealthyThreshold,
+			"the unhealthy threshold must be at least 1"))
+	}
+	return allErrs
+}
+
+// validateAPIServerDNSLabel validates the DNS label of the API server public IP, which an internal API server doesn't have.
+func validateAPIServerDNSLabel(lb LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
+	if lb.DNSLabel == "" {
+		return nil
+	}
+	if lb.Type == Internal {
+		return field.ErrorList{field.Forbidden(fldPath, "an internal API server has no public IP to set the DNS label of")}
+	}
+	if !regexp.MustCompile(dnsLabelRegex).MatchString(lb.DNSLabel) {
+		return field.ErrorList{field.Invalid(fldPath, lb.DNSLabel,
+			fmt.Sprintf("dnsLabel doesn't match regex %s", dnsLabelRegex))}
+	}
+	return nil
+}
+
+// validateLoadDistribution validates the load distribution of the API server load balancing rules.
+func validateLoadDistribution(distribution LoadDistribution, fldPath *field.Path) field.ErrorList {
+	switch distribution {
+	case "", LoadDistributionDefault, LoadDistributionSourceIP, LoadDistributionSourceIPProtocol:
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, distribution,
+		[]string{string(LoadDistributionDefault), string(LoadDistributionSourceIP), string(LoadDistributionSourceIPProtocol)})}
+}
+
+// validatePublicIPTags validates the tags of public IPs against the limits of Azure tags. The tags set by the
+// provider can't be overridden, as Azure tag names are case-insensitive they are compared in lowercase.
+func validatePublicIPTags(tags Tags, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for key, value := range tags {
+		lower := strings.ToLower(key)
+		switch {
+		case key == "" || len(key) > maxTagKeyLength:
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key,
+				fmt.Sprintf("tag names must be between 1 and %d characters", maxTagKeyLength)))
+		case strings.ContainsAny(key, invalidTagKeyChars):
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key,
+				fmt.Sprintf("tag names can't contain any of the characters %s", invalidTagKeyChars)))
+		case lower == "name" || strings.HasPrefix(lower, strings.ToLower(NameAzureProviderPrefix)) ||
+			strings.HasPrefix(lower, strings.ToLower(NameKubernetesAzureCloudProviderPrefix)):
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(key), "the tag is set by the provider and can't be overridden"))
+		}
+		if len(value) > maxTagValueLength {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value,
+				fmt.Sprintf("tag values must be at most %d characters", maxTagValueLength)))
+		}
+	}
+	return allErrs
+}
+
+// validateInternalLBInboundNatRules validates the inbound NAT rules of the internal API server load balancer. The
+// frontend port ranges of the rules can't overlap, so each control plane machine gets a port of each range.
+func validateInternalLBInboundNatRules(rules []InboundNatRule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool)
+	for i, rule := range rules {
+		if success, _ := regexp.MatchString(inboundNatRuleNameRegex, rule.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), rule.Name,
+				fmt.Sprintf("inbound NAT rule names must match the regex %s", inboundNatRuleNameRegex)))
+		}
+		if names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), rule.Name))
+		}
+		names[rule.Name] = true
+		for _, port := range []struct {
+			name  string
+			value int32
+		}{
+			{"frontendPortRangeStart", rule.FrontendPortRangeStart},
+			{"frontendPortRangeEnd", rule.FrontendPortRangeEnd},
+			{"backendPort", rule.BackendPort},
+		} {
+			if port.value < 1 || port.value > 65535 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child(port.name), port.value,
+					"the port must be between 1 and 65535"))
+			}
+		}
+		if rule.FrontendPortRangeEnd < rule.FrontendPortRangeStart {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("frontendPortRangeEnd"), rule.FrontendPortRangeEnd,
+				fmt.Sprintf("the frontend port range must end at or after its start %d", rule.FrontendPortRangeStart)))
+			continue
+		}
+		for j, other := range rules[:i] {
+			if rule.FrontendPortRangeStart <= other.FrontendPortRangeEnd && other.FrontendPortRangeStart <= rule.FrontendPortRangeEnd {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), fmt.Sprintf("%d-%d", rule.FrontendPortRangeStart, rule.FrontendPortRangeEnd),
+					fmt.Sprintf("the frontend port range overlaps with the range of rule %d", j)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateAPIServerPublicIPID validates the resource ID of an existing API server public IP. The DNS label and zones
+// of an existing public IP can't be set, and a Standard SKU public IP can't be used by a Basic SKU load balancer.
+func validateAPIServerPublicIPID(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	lb := networkSpec.APIServerLB
+	if lb.PublicIPID == "" {
+		return nil
+	}
+	idPath := fldPath.Child("publicIPID")
+	if lb.Type == Internal {
+		return field.ErrorList{field.Forbidden(idPath, "an internal API server has no public IP")}
+	}
+	var allErrs field.ErrorList
+	if success, _ := regexp.MatchString(publicIPIDRegex, lb.PublicIPID); !success {
+		allErrs = append(allErrs, field.Invalid(idPath, lb.PublicIPID,
+			"publicIPID must be the resource ID of a public IP, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/publicIPAddresses/<name>"))
+	}
+	if lb.DNSLabel != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("dnsLabel"), "the DNS label of an existing API server public IP cannot be set"))
+	}
+	if len(lb.PublicIPZones) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("publicIPZones"), "the zones of an existing API server public IP cannot be set"))
+	}
+	if networkSpec.LoadBalancerSKU == SKUBasic {
+		allErrs = append(allErrs, field.Forbidden(idPath,
+			fmt.Sprintf("an existing API server public IP must be of the %s SKU, which a load balancer of the %s SKU cannot use", SKUStandard, SKUBasic)))
+	}
+	return allErrs
+}
+
+// validatePrivateLinkService validates the private link service of the internal API server load balancer. Private
+// link services are only supported by the Standard load balancer SKU.
+func validatePrivateLinkService(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	pls := networkSpec.APIServerLB.PrivateLinkService
+	if pls == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if networkSpec.LoadBalancerSKU == SKUBasic {
+		allErrs = append(allErrs, field.Invalid(fldPath, networkSpec.LoadBalancerSKU,
+			fmt.Sprintf("a private link service can only be created with the %s load balancer SKU", SKUStandard)))
+	}
+	if pls.SubnetName != "" && !hasSubnet(networkSpec.Subnets, pls.SubnetName) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnetName"), pls.SubnetName,
+			"the subnet of the private link service must be a subnet of the cluster vnet"))
+	}
+	for i, subscription := range pls.VisibilitySubscriptions {
+		if subscription == "*" {
+			continue
+		}
+		if success, _ := regexp.MatchString(subscriptionIDRegex, subscription); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("visibilitySubscriptions").Index(i), subscription,
+				"must be the ID of a subscription, or * for any subscription"))
+		}
+	}
+	for i, subscription := range pls.AutoApprovalSubscriptions {
+		if success, _ := regexp.MatchString(subscriptionIDRegex, subscription); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("autoApprovalSubscriptions").Index(i), subscription,
+				"must be the ID of a subscription"))
+		}
+	}
+	return allErrs
+}
+
+// validateApplicationGateway validates the references of the application gateway to its TLS certificate, identity and
+// web application firewall policy. Its subnet is validated once defaulted, when the cluster is reconciled.
+func validateApplicationGateway(appGateway *ApplicationGatewaySpec, fldPath *field.Path) field.ErrorList {
+	if appGateway == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if success, _ := regexp.MatchString(keyVaultSecretIDRegex, appGateway.SSLCertificateSecretID); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("sslCertificateSecretID"), appGateway.SSLCertificateSecretID,
+			"sslCertificateSecretID must be the ID of a Key Vault secret, e.g. https://<vault>.vault.azure.net/secrets/<name>"))
+	}
+	if success, _ := regexp.MatchString(userAssignedIdentityIDRegex, appGateway.IdentityID); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("identityID"), appGateway.IdentityID,
+			"identityID must be the resource ID of a user-assigned identity, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.ManagedIdentity/userAssignedIdentities/<name>"))
+	}
+	if appGateway.FirewallPolicyID != "" {
+		if success, _ := regexp.MatchString(firewallPolicyIDRegex, appGateway.FirewallPolicyID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("firewallPolicyID"), appGateway.FirewallPolicyID,
+				"firewallPolicyID must be the resource ID of a web application firewall policy, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/ApplicationGatewayWebApplicationFirewallPolicies/<name>"))
+		}
+	}
+	if appGateway.Subnet.Name != "" {
+		if success, _ := regexp.MatchString(subnetRegex, appGateway.Subnet.Name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet").Child("name"), appGateway.Subnet.Name,
+				fmt.Sprintf("name of subnet doesn't match regex %s", subnetRegex)))
+		}
+	}
+	return allErrs
+}
+
+// validateAdditionalAPIServerIPs validates the names of the additional API server public IPs.
+// The names must be unique and differ from the default API server public IP once it is known,
+// so the default frontend of the load balancer remains when the list is edited.
+func validateAdditionalAPIServerIPs(networkSpec NetworkSpec, apiServerIPName string, fldPath *field.Path) field.ErrorList {
+	names := networkSpec.APIServerLB.AdditionalPublicIPNames
+	if len(names) == 0 {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if networkSpec.APIServerLB.Type == Internal {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("additional public IPs cannot be set on a load balancer of type %s", Internal)))
+	}
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		if success, _ := regexp.MatchString(publicIPNameRegex, name); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), name,
+				fmt.Sprintf("public IP names must match the regex %s", publicIPNameRegex)))
+			continue
+		}
+		key := strings.ToLower(name)
+		if seen[key] || (apiServerIPName != "" && strings.EqualFold(name, apiServerIPName)) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), name))
+		}
+		seen[key] = true
+	}
+	return allErrs
+}
+
+// validateVnetPeerings validates the remote virtual networks of the vnet peerings.
+func validateVnetPeerings(peerings []VnetPeeringSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(peerings))
+	for i, peering := range peerings {
+		idPath := fldPath.Index(i).Child("remoteVnetID")
+		if success, _ := regexp.MatchString(vnetIDRegex, peering.RemoteVnetID); !success {
+			allErrs = append(allErrs, field.Invalid(idPath, peering.RemoteVnetID,
+				"remoteVnetID must be the resource ID of a virtual network, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/virtualNetworks/<name>"))
+			continue
+		}
+		id := strings.ToLower(peering.RemoteVnetID)
+		if seen[id] {
+			allErrs = append(allErrs, field.Duplicate(idPath, peering.RemoteVnetID))
+		}
+		seen[id] = true
+	}
+	return allErrs
+}
+
+// validatePrivateEndpoints validates the names, subnets and resource IDs of the private endpoints.
+// Whether their subnets are delegated, and allow private endpoints, is only known to Azure when the
+// vnet is pre-existing, and is checked when the private endpoints are reconciled.
+func validatePrivateEndpoints(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool, len(networkSpec.PrivateEndpoints))
+	for i, endpoint := range networkSpec.PrivateEndpoints {
+		endpointPath := fldPath.Index(i)
+		if success, _ := regexp.MatchString(privateEndpointNameRegex, endpoint.Name); !success {
+			allErrs = append(allErrs, field.Invalid(endpointPath.Child("name"), endpoint.Name,
+				fmt.Sprintf("name of private endpoint doesn't match regex %s", privateEndpointNameRegex)))
+		}
+		if names[strings.ToLower(endpoint.Name)] {
+			allErrs = append(allErrs, field.Duplicate(endpointPath.Child("name"), endpoint.Name))
+		}
+		names[strings.ToLower(endpoint.Name)] = true
+
+		if !hasSubnet(networkSpec.Subnets, endpoint.SubnetName) {
+			allErrs = append(allErrs, field.Invalid(endpointPath.Child("subnetName"), endpoint.SubnetName,
+				"subnetName must be the name of a subnet of the network spec"))
+		}
+		if success, _ := regexp.MatchString(privateLinkResourceIDRegex, endpoint.PrivateLinkServiceID); !success {
+			allErrs = append(allErrs, field.Invalid(endpointPath.Child("privateLinkServiceID"), endpoint.PrivateLinkServiceID,
+				"privateLinkServiceID must be the resource ID of an Azure resource, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.KeyVault/vaults/<name>"))
+		}
+		if len(endpoint.GroupIDs) == 0 {
+			allErrs = append(allErrs, field.Required(endpointPath.Child("groupIDs"),
+				"groupIDs must name at least one sub-resource of the Azure resource, e.g. vault"))
+		}
+
+		zones := make(map[string]bool, len(endpoint.PrivateDNSZoneIDs))
+		for j, zoneID := range endpoint.PrivateDNSZoneIDs {
+			zonePath := endpointPath.Child("privateDNSZoneIDs").Index(j)
+			if success, _ := regexp.MatchString(privateDNSZoneIDRegex, zoneID); !success {
+				allErrs = append(allErrs, field.Invalid(zonePath, zoneID,
+					"a private DNS zone ID must be the resource ID of a private DNS zone, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/privateDnsZones/<name>"))
+				continue
+			}
+			if zones[strings.ToLower(zoneID)] {
+				allErrs = append(allErrs, field.Duplicate(zonePath, zoneID))
+			}
+			zones[strings.ToLower(zoneID)] = true
+		}
+	}
+	return allErrs
+}
+
+// hasSubnet returns true if one of the subnets has the name.
+func hasSubnet(subnets Subnets, name string) bool {
+	for _, subnet := range subnets {
+		if subnet.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAllowedAPIServerCIDRs validates the CIDR blocks allowed to reach the API server port.
+func validateAllowedAPIServerCIDRs(cidrs []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(cidrs))
+	for i, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), cidr, "must be a valid CIDR block"))
+			continue
+		}
+		if seen[ipNet.String()] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), cidr))
+		}
+		seen[ipNet.String()] = true
+	}
+	return allErrs
+}
+
+// validateDNSServers validates that the DNS servers of a virtual network are unique IP addresses.
+func validateDNSServers(servers []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(servers))
+	for i, server := range servers {
+		ip := net.ParseIP(server)
+		if ip == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), server, "must be a valid IP address"))
+			continue
+		}
+		if seen[ip.String()] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), server))
+		}
+		seen[ip.String()] = true
+	}
+	return allErrs
+}
+
+// validateRouteTable validates the ID and the routes of a route table.
+func validateRouteTable(routeTable RouteTable, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if routeTable.ID != "" {
+		if success, _ := regexp.MatchString(routeTableIDRegex, routeTable.ID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), routeTable.ID,
+				"id must be the resource ID of a route table, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/routeTables/<name>"))
+		}
+	}
+	names := make(map[string]bool, len(routeTable.Routes))
+	for i, route := range routeTable.Routes {
+		routePath := fldPath.Child("routes").Index(i)
+		if route.Name == "" {
+			allErrs = append(allErrs, field.Required(routePath.Child("name"), "route names are required"))
+		} else if names[route.Name] {
+			allErrs = append(allErrs, field.Duplicate(routePath.Child("name"), route.Name))
+		}
+		names[route.Name] = true
+		if _, _, err := net.ParseCIDR(route.AddressPrefix); err != nil {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("addressPrefix"), route.AddressPrefix,
+				"addressPrefix must be a valid CIDR block"))
+		}
+		switch {
+		case route.NextHopType == RouteNextHopTypeVirtualAppliance && net.ParseIP(route.NextHopIPAddress) == nil:
+			allErrs = append(allErrs, field.Invalid(routePath.Child("nextHopIPAddress"), route.NextHopIPAddress,
+				fmt.Sprintf("nextHopIPAddress must be a valid IP address for the %s next hop type", RouteNextHopTypeVirtualAppliance)))
+		case route.NextHopType != RouteNextHopTypeVirtualAppliance && route.NextHopIPAddress != "":
+			allErrs = append(allErrs, field.Invalid(routePath.Child("nextHopIPAddress"), route.NextHopIPAddress,
+				fmt.Sprintf("nextHopIPAddress is only allowed for the %s next hop type", RouteNextHopTypeVirtualAppliance)))
+		}
+	}
+	return allErrs
+}
+
+// validateIPv6CIDR validates an IPv6 CIDR block.
+func validateIPv6CIDR(cidr string, fldPath *field.Path) *field.Error {
+	if cidr == "" {
+		return field.Required(fldPath, "an IPv6 CIDR block is required on the vnet and all subnets of a dual-stack network")
+	}
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() != nil {
+		return field.Invalid(fldPath, cidr, "must be a valid IPv6 CIDR block")
+	}
+	return nil
+}
+
+// validateSecurityRules validates the additional security rules of a security group.
+// Rule names must be unique, and so must priorities within a direction, including the ingress rules.
+func validateSecurityRules(securityGroup SecurityGroup, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool)
+	priorities := make(map[SecurityRuleDirection]map[int32]string)
+	priorities[SecurityRuleDirectionInbound] = make(map[int32]string)
+	priorities[SecurityRuleDirectionOutbound] = make(map[int32]string)
+	for _, ingressRule := range securityGroup.IngressRules {
+		names[ingressRule.Name] = true
+		priorities[SecurityRuleDirectionInbound][ingressRule.Priority] = ingressRule.Name
+	}
+
+	for i, rule := range securityGroup.SecurityRules {
+		rulePath := fldPath.Child("securityRules").Index(i)
+		if names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = true
+		if rule.Priority < 100 || rule.Priority > 4096 {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("priority"), rule.Priority,
+				"security rule priorities should be between 100 and 4096"))
+		}
+		byPriority, ok := priorities[rule.Direction]
+		if !ok {
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("direction"), rule.Direction,
+				[]string{string(SecurityRuleDirectionInbound), string(SecurityRuleDirectionOutbound)}))
+			continue
+		}
+		if other, ok := byPriority[rule.Priority]; ok {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("priority"), rule.Priority,
+				fmt.Sprintf("priority is already used by %s rule %s", rule.Direction, other)))
+			continue
+		}
+		byPriority[rule.Priority] = rule.Name
+	}
+	return allErrs
+}
+
+// validateLBHealthProbeSecurityRule validates that the rules of the control plane security group don't use the name
+// or the inbound priority of the health probe rule managed by the provider.
+func validateLBHealthProbeSecurityRule(securityGroup SecurityGroup, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, ingressRule := range securityGroup.IngressRules {
+		rulePath := fldPath.Child("ingressRule").Index(i)
+		if ingressRule.Name == LBHealthProbeSecurityRuleName {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("name"), ingressRule.Name,
+				"name is reserved for the load balancer health probe rule"))
+		}
+		if ingressRule.Priority == LBHealthProbeSecurityRulePriority {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("priority"), ingressRule.Priority,
+				"priority is reserved for the load balancer health probe rule"))
+		}
+	}
+	for i, rule := range securityGroup.SecurityRules {
+		rulePath := fldPath.Child("securityRules").Index(i)
+		if rule.Name == LBHealthProbeSecurityRuleName {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("name"), rule.Name,
+				"name is reserved for the load balancer health probe rule"))
+		}
+		if rule.Direction == SecurityRuleDirectionInbound && rule.Priority == LBHealthProbeSecurityRulePriority {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("priority"), rule.Priority,
+				"priority is reserved for the load balancer health probe rule"))
+		}
+	}
+	return allErrs
+}
+
+// validateSubnetID validates that the ID of a subnet references the subnet of the same name in the vnet of the cluster.
+func validateSubnetID(id, name string, vnet VnetSpec, fldPath *field.Path) *field.Error {
+	match := regexp.MustCompile(subnetIDRegex).FindStringSubmatch(id)
+	if match == nil {
+		return field.Invalid(fldPath, id,
+			"id must be the resource ID of a subnet, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/virtualNetworks/<vnet>/subnets/<name>")
+	}
+	resourceGroup, vnetName, subnetName := match[1], match[2], match[3]
+	if (vnet.ResourceGroup != "" && !strings.EqualFold(resourceGroup, vnet.ResourceGroup)) ||
+		(vnet.Name != "" && !strings.EqualFold(vnetName, vnet.Name)) {
+		return field.Invalid(fldPath, id,
+			fmt.Sprintf("subnet must be in vnet %s of resource group %s", vnet.Name, vnet.ResourceGroup))
+	}
+	if !strings.EqualFold(subnetName, name) {
+		return field.Invalid(fldPath, id, fmt.Sprintf("id must reference the subnet named %s", name))
+	}
+	return nil
+}
+
 // validateSubnetName validates the Name of a Subnet
 func validateSubnetName(name string, fldPath *field.Path) *field.Error {
 	if success, _ := regexp.Match(subnetRegex, []byte(name)); !success {
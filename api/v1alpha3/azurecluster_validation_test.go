@@ -17,10 +17,14 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 )
 
 func TestClusterWithPreexistingVnetValid(t *testing.T) {
@@ -309,6 +313,26 @@ func TestSubnetsValid(t *testing.T) {
 	})
 }
 
+func TestSubnetsValidMultipleNodeSubnets(t *testing.T) {
+	g := NewWithT(t)
+
+	type test struct {
+		name    string
+		subnets Subnets
+	}
+
+	testCase := test{
+		name:    "subnets - valid with multiple node subnets",
+		subnets: append(createValidSubnets(), &SubnetSpec{Name: "node-subnet-2", Role: "node"}),
+	}
+
+	t.Run(testCase.name, func(t *testing.T) {
+		errs := validateSubnets(testCase.subnets,
+			field.NewPath("spec").Child("networkSpec").Child("subnets"))
+		g.Expect(errs).To(BeNil())
+	})
+}
+
 func TestSubnetsInvalidSubnetName(t *testing.T) {
 	g := NewWithT(t)
 
@@ -537,33 +561,2204 @@ func TestIngressRules(t *testing.T) {
 	}
 }
 
-func createValidCluster() *AzureCluster {
-	return &AzureCluster{
-		Spec: AzureClusterSpec{
-			NetworkSpec: createValidNetworkSpec(),
+func TestNatGateways(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "natGateway - valid without NAT gateways",
+			networkSpec: createValidNetworkSpec(),
+			wantErr:     false,
+		},
+		{
+			name: "natGateway - valid on all node subnets",
+			networkSpec: NetworkSpec{
+				Subnets: Subnets{
+					{Name: "control-plane-subnet", Role: "control-plane"},
+					{Name: "node-subnet", Role: "node", NatGateway: NatGateway{Name: "node-natgw"}},
+					{Name: "node-subnet-2", Role: "node", NatGateway: NatGateway{Name: "node-natgw"}},
+				},
+			},
+			wantErr: false,
 		},
+		{
+			name: "natGateway - invalid on control plane subnet",
+			networkSpec: NetworkSpec{
+				Subnets: Subnets{
+					{Name: "control-plane-subnet", Role: "control-plane", NatGateway: NatGateway{Name: "cp-natgw"}},
+					{Name: "node-subnet", Role: "node"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "natGateway - invalid with node subnet relying on the node outbound load balancer",
+			networkSpec: NetworkSpec{
+				Subnets: Subnets{
+					{Name: "control-plane-subnet", Role: "control-plane"},
+					{Name: "node-subnet", Role: "node", NatGateway: NatGateway{Name: "node-natgw"}},
+					{Name: "node-subnet-2", Role: "node"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "natGateway - invalid with basic load balancer SKU",
+			networkSpec: NetworkSpec{
+				LoadBalancerSKU: SKUBasic,
+				Subnets: Subnets{
+					{Name: "control-plane-subnet", Role: "control-plane"},
+					{Name: "node-subnet", Role: "node", NatGateway: NatGateway{Name: "node-natgw"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateNatGateways(testCase.networkSpec, field.NewPath("spec").Child("networkSpec"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
 	}
 }
 
-func createValidNetworkSpec() NetworkSpec {
-	return NetworkSpec{
-		Vnet: VnetSpec{
-			ResourceGroup: "custom-vnet",
-			Name:          "my-vnet",
+func TestServiceEndpoints(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name             string
+		serviceEndpoints []string
+		wantErr          bool
+	}{
+		{
+			name:             "serviceEndpoints - valid without service endpoints",
+			serviceEndpoints: nil,
+			wantErr:          false,
 		},
-		Subnets: createValidSubnets(),
+		{
+			name:             "serviceEndpoints - valid",
+			serviceEndpoints: []string{"Microsoft.Storage", "Microsoft.Sql", "Microsoft.Storage.Global"},
+			wantErr:          false,
+		},
+		{
+			name:             "serviceEndpoints - invalid service name",
+			serviceEndpoints: []string{"Storage"},
+			wantErr:          true,
+		},
+		{
+			name:             "serviceEndpoints - invalid duplicate service",
+			serviceEndpoints: []string{"Microsoft.Storage", "microsoft.storage"},
+			wantErr:          true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			subnets := Subnets{
+				{Name: "control-plane-subnet", Role: "control-plane"},
+				{Name: "node-subnet", Role: "node", ServiceEndpoints: testCase.serviceEndpoints},
+			}
+			errs := validateServiceEndpoints(subnets, field.NewPath("spec").Child("networkSpec").Child("subnets"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
 	}
 }
 
-func createValidSubnets() Subnets {
-	return Subnets{
+func TestDelegations(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		delegations []string
+		wantErr     bool
+	}{
 		{
-			Name: "control-plane-subnet",
-			Role: "control-plane",
+			name:        "delegations - valid without delegations",
+			delegations: nil,
+			wantErr:     false,
 		},
 		{
-			Name: "node-subnet",
-			Role: "node",
+			name:        "delegations - valid",
+			delegations: []string{"Microsoft.Netapp/volumes", "Microsoft.ContainerInstance/containerGroups", "PaloAltoNetworks.Cloudngfw/firewalls"},
+			wantErr:     false,
+		},
+		{
+			name:        "delegations - invalid service without resource type",
+			delegations: []string{"Microsoft.Netapp"},
+			wantErr:     true,
+		},
+		{
+			name:        "delegations - invalid duplicate service",
+			delegations: []string{"Microsoft.Netapp/volumes", "microsoft.netapp/volumes"},
+			wantErr:     true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			subnets := Subnets{
+				{Name: "control-plane-subnet", Role: "control-plane"},
+				{Name: "node-subnet", Role: "node", Delegations: testCase.delegations},
+			}
+			errs := validateDelegations(subnets, field.NewPath("spec").Child("networkSpec").Child("subnets"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestControlPlaneSubnets(t *testing.T) {
+	tests := []struct {
+		name            string
+		sku             SKU
+		internalLBZones []string
+		subnets         Subnets
+		wantErr         string
+	}{
+		{
+			name: "single control plane subnet",
+			subnets: Subnets{
+				{Name: "cp-subnet", Role: SubnetControlPlane, InternalLBIPAddress: "10.0.0.100"},
+				{Name: "node-subnet", Role: SubnetNode},
+			},
+		},
+		{
+			name:            "control plane subnet per zone",
+			internalLBZones: []string{"2"},
+			subnets: Subnets{
+				{Name: "cp-subnet-1", Role: SubnetControlPlane, Zones: []string{"1"}},
+				{Name: "cp-subnet-2", Role: SubnetControlPlane, Zones: []string{"2"}, InternalLBIPAddress: "10.0.0.100"},
+				{Name: "cp-subnet-3", Role: SubnetControlPlane, Zones: []string{"3"}},
+				{Name: "node-subnet", Role: SubnetNode},
+			},
+		},
+		{
+			name: "zones of a node subnet",
+			subnets: Subnets{
+				{Name: "cp-subnet", Role: SubnetControlPlane},
+				{Name: "node-subnet", Role: SubnetNode, Zones: []string{"1"}},
+			},
+			wantErr: "only control plane subnets have zones",
+		},
+		{
+			name: "invalid zone",
+			subnets: Subnets{
+				{Name: "cp-subnet", Role: SubnetControlPlane, Zones: []string{"4"}},
+				{Name: "node-subnet", Role: SubnetNode},
+			},
+			wantErr: `Unsupported value: "4"`,
+		},
+		{
+			name: "zones with the Basic SKU",
+			sku:  SKUBasic,
+			subnets: Subnets{
+				{Name: "cp-subnet", Role: SubnetControlPlane, Zones: []string{"1"}},
+				{Name: "node-subnet", Role: SubnetNode},
+			},
+			wantErr: "availability zones require the Standard load balancer SKU",
+		},
+		{
+			name: "zone listed by two subnets",
+			subnets: Subnets{
+				{Name: "cp-subnet-1", Role: SubnetControlPlane, Zones: []string{"1", "2"}},
+				{Name: "cp-subnet-2", Role: SubnetControlPlane, Zones: []string{"2"}},
+				{Name: "node-subnet", Role: SubnetNode},
+			},
+			wantErr: "zone is already listed by control plane subnet cp-subnet-1",
+		},
+		{
+			name:            "internal LB IP address outside of the frontend subnet",
+			internalLBZones: []string{"2"},
+			subnets: Subnets{
+				{Name: "cp-subnet-1", Role: SubnetControlPlane, Zones: []string{"1"}, InternalLBIPAddress: "10.0.0.100"},
+				{Name: "cp-subnet-2", Role: SubnetControlPlane, Zones: []string{"2"}},
+				{Name: "node-subnet", Role: SubnetNode},
+			},
+			wantErr: "the frontend of the internal load balancer is in control plane subnet cp-subnet-2",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sku := tc.sku
+			if sku == "" {
+				sku = SKUStandard
+			}
+			networkSpec := NetworkSpec{
+				LoadBalancerSKU: sku,
+				APIServerLB:     LoadBalancerSpec{InternalLBZones: tc.internalLBZones},
+				Subnets:         tc.subnets,
+			}
+			errs := validateControlPlaneSubnets(networkSpec, field.NewPath("spec").Child("networkSpec").Child("subnets"))
+			if tc.wantErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestSecurityRules(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name          string
+		securityGroup SecurityGroup
+		wantErr       bool
+	}{
+		{
+			name: "securityRules - valid inbound and outbound rules with the same priority",
+			securityGroup: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "allow_50000", Direction: SecurityRuleDirectionInbound, Priority: 200},
+					{Name: "deny_smtp", Direction: SecurityRuleDirectionOutbound, Priority: 200},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "securityRules - invalid priority",
+			securityGroup: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "allow_50000", Direction: SecurityRuleDirectionInbound, Priority: 99},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "securityRules - invalid direction",
+			securityGroup: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "allow_50000", Direction: "Sideways", Priority: 200},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "securityRules - duplicate name",
+			securityGroup: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "allow_50000", Direction: SecurityRuleDirectionInbound, Priority: 200},
+					{Name: "allow_50000", Direction: SecurityRuleDirectionOutbound, Priority: 201},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "securityRules - priority collision with an ingress rule",
+			securityGroup: SecurityGroup{
+				IngressRules: IngressRules{
+					{Name: "allow_ssh", Priority: 100},
+				},
+				SecurityRules: SecurityRules{
+					{Name: "allow_50000", Direction: SecurityRuleDirectionInbound, Priority: 100},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateSecurityRules(testCase.securityGroup,
+				field.NewPath("spec").Child("networkSpec").Child("subnets").Index(0).Child("securityGroup"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestLBHealthProbeSecurityRule(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name          string
+		securityGroup SecurityGroup
+		wantErr       bool
+	}{
+		{
+			name: "lbHealthProbeSecurityRule - valid rules",
+			securityGroup: SecurityGroup{
+				IngressRules: IngressRules{
+					{Name: "allow_ssh", Priority: 100},
+				},
+				SecurityRules: SecurityRules{
+					{Name: "deny_all_inbound", Direction: SecurityRuleDirectionInbound, Priority: 4096},
+					{Name: "deny_smtp", Direction: SecurityRuleDirectionOutbound, Priority: 103},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "lbHealthProbeSecurityRule - reserved name",
+			securityGroup: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "allow_lb_health_probes", Direction: SecurityRuleDirectionInbound, Priority: 200},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "lbHealthProbeSecurityRule - reserved inbound priority",
+			securityGroup: SecurityGroup{
+				SecurityRules: SecurityRules{
+					{Name: "deny_all_inbound", Direction: SecurityRuleDirectionInbound, Priority: 103},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "lbHealthProbeSecurityRule - reserved priority of an ingress rule",
+			securityGroup: SecurityGroup{
+				IngressRules: IngressRules{
+					{Name: "allow_port_50000", Priority: 103},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateLBHealthProbeSecurityRule(testCase.securityGroup,
+				field.NewPath("spec").Child("networkSpec").Child("subnets").Index(0).Child("securityGroup"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestIPv6(t *testing.T) {
+	g := NewWithT(t)
+
+	dualStackNetworkSpec := func() NetworkSpec {
+		return NetworkSpec{
+			Vnet: VnetSpec{CidrBlock: "10.0.0.0/8", IPv6CidrBlock: "2001:1234:5678:9a00::/56"},
+			Subnets: Subnets{
+				{Name: "control-plane-subnet", Role: "control-plane", CidrBlock: "10.0.0.0/16", IPv6CidrBlock: "2001:1234:5678:9abc::/64"},
+				{Name: "node-subnet", Role: "node", CidrBlock: "10.1.0.0/16", IPv6CidrBlock: "2001:1234:5678:9abd::/64"},
+			},
+		}
+	}
+
+	t.Run("ipv6 - forbidden without the feature gate", func(t *testing.T) {
+		errs := validateIPv6(dualStackNetworkSpec(), field.NewPath("spec").Child("networkSpec"))
+		g.Expect(errs).To(HaveLen(1))
+		g.Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+	})
+
+	defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.IPv6DualStack, true)()
+
+	tests := []struct {
+		name        string
+		networkSpec func() NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "ipv6 - valid single-stack network",
+			networkSpec: createValidNetworkSpec,
+			wantErr:     false,
+		},
+		{
+			name:        "ipv6 - valid dual-stack network",
+			networkSpec: dualStackNetworkSpec,
+			wantErr:     false,
+		},
+		{
+			name: "ipv6 - invalid subnet without an IPv6 CIDR block",
+			networkSpec: func() NetworkSpec {
+				n := dualStackNetworkSpec()
+				n.Subnets[1].IPv6CidrBlock = ""
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "ipv6 - invalid vnet without an IPv6 CIDR block",
+			networkSpec: func() NetworkSpec {
+				n := dualStackNetworkSpec()
+				n.Vnet.IPv6CidrBlock = ""
+				return n
+			},
+			wantErr: true,
 		},
+		{
+			name: "ipv6 - invalid IPv4 CIDR block",
+			networkSpec: func() NetworkSpec {
+				n := dualStackNetworkSpec()
+				n.Subnets[0].IPv6CidrBlock = "10.2.0.0/16"
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "ipv6 - invalid with the Basic load balancer SKU",
+			networkSpec: func() NetworkSpec {
+				n := dualStackNetworkSpec()
+				n.LoadBalancerSKU = SKUBasic
+				return n
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateIPv6(testCase.networkSpec(), field.NewPath("spec").Child("networkSpec"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestSubnetCIDRs(t *testing.T) {
+	g := NewWithT(t)
+
+	networkSpec := func() NetworkSpec {
+		return NetworkSpec{
+			Vnet: VnetSpec{CidrBlock: "10.0.0.0/8"},
+			Subnets: Subnets{
+				{Name: "control-plane-subnet", Role: "control-plane", CidrBlock: "10.0.0.0/16"},
+				{Name: "node-subnet", Role: "node", CidrBlock: "10.1.0.0/16"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		networkSpec func() NetworkSpec
+		wantErrs    []string
+	}{
+		{
+			name:        "subnet cidrs - valid",
+			networkSpec: networkSpec,
+		},
+		{
+			name: "subnet cidrs - valid without CIDR blocks",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Subnets = append(n.Subnets, &SubnetSpec{Name: "node-subnet-2", Role: "node"})
+				return n
+			},
+		},
+		{
+			name: "subnet cidrs - invalid CIDR block",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Subnets[1].CidrBlock = "10.1.0.0"
+				return n
+			},
+			wantErrs: []string{`spec.networkSpec.subnets[1].cidrBlock: Invalid value: "10.1.0.0": must be a valid CIDR block`},
+		},
+		{
+			name: "subnet cidrs - subnet outside of the vnet",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Vnet.CidrBlock = "10.0.0.0/16"
+				return n
+			},
+			wantErrs: []string{`spec.networkSpec.subnets[1].cidrBlock: Invalid value: "10.1.0.0/16": subnet node-subnet must be within the CIDR block 10.0.0.0/16 of the vnet`},
+		},
+		{
+			name: "subnet cidrs - subnet larger than the vnet",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Subnets[0].CidrBlock = "10.0.0.0/7"
+				return n
+			},
+			wantErrs: []string{
+				`spec.networkSpec.subnets[0].cidrBlock: Invalid value: "10.0.0.0/7": subnet control-plane-subnet must be within the CIDR block 10.0.0.0/8 of the vnet`,
+				`spec.networkSpec.subnets[1].cidrBlock: Invalid value: "10.1.0.0/16": subnet node-subnet overlaps with the CIDR block 10.0.0.0/7 of subnet control-plane-subnet`,
+			},
+		},
+		{
+			name: "subnet cidrs - overlapping subnets",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Subnets[1].CidrBlock = "10.0.128.0/24"
+				return n
+			},
+			wantErrs: []string{`spec.networkSpec.subnets[1].cidrBlock: Invalid value: "10.0.128.0/24": subnet node-subnet overlaps with the CIDR block 10.0.0.0/16 of subnet control-plane-subnet`},
+		},
+		{
+			name: "subnet cidrs - overlapping IPv6 subnets",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Vnet.IPv6CidrBlock = "2001:1234:5678:9a00::/56"
+				n.Subnets[0].IPv6CidrBlock = "2001:1234:5678:9abc::/64"
+				n.Subnets[1].IPv6CidrBlock = "2001:1234:5678:9abc::/64"
+				return n
+			},
+			wantErrs: []string{`spec.networkSpec.subnets[1].ipv6CidrBlock: Invalid value: "2001:1234:5678:9abc::/64": subnet node-subnet overlaps with the CIDR block 2001:1234:5678:9abc::/64 of subnet control-plane-subnet`},
+		},
+		{
+			name: "subnet cidrs - valid bastion subnet",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Bastion = &BastionSpec{Subnet: BastionSubnetSpec{CidrBlock: "10.255.255.192/26"}}
+				return n
+			},
+		},
+		{
+			name: "subnet cidrs - bastion subnet outside of the vnet",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Bastion = &BastionSpec{Subnet: BastionSubnetSpec{CidrBlock: "172.16.255.192/26"}}
+				return n
+			},
+			wantErrs: []string{`spec.networkSpec.bastion.subnet.cidrBlock: Invalid value: "172.16.255.192/26": subnet AzureBastionSubnet must be within the CIDR block 10.0.0.0/8 of the vnet`},
+		},
+		{
+			name: "subnet cidrs - bastion subnet overlapping a subnet",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Bastion = &BastionSpec{Subnet: BastionSubnetSpec{Name: "AzureBastionSubnet", CidrBlock: "10.1.255.192/26"}}
+				return n
+			},
+			wantErrs: []string{`spec.networkSpec.bastion.subnet.cidrBlock: Invalid value: "10.1.255.192/26": subnet AzureBastionSubnet overlaps with the CIDR block 10.1.0.0/16 of subnet node-subnet`},
+		},
+		{
+			name: "subnet cidrs - bastion subnet without CIDR block in a vnet too small for it",
+			networkSpec: func() NetworkSpec {
+				n := networkSpec()
+				n.Vnet.CidrBlock = "10.0.0.0/27"
+				n.Subnets = Subnets{{Name: "node-subnet", Role: "node", CidrBlock: "10.0.0.0/28"}}
+				n.Bastion = &BastionSpec{}
+				return n
+			},
+			wantErrs: []string{`spec.networkSpec.bastion.subnet.cidrBlock: Required value: the CIDR block 10.0.0.0/27 of the vnet has no room for a /26 bastion subnet`},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateSubnetCIDRs(testCase.networkSpec(), field.NewPath("spec").Child("networkSpec"))
+			g.Expect(errs).To(HaveLen(len(testCase.wantErrs)))
+			for i, err := range errs {
+				g.Expect(err.Error()).To(Equal(testCase.wantErrs[i]))
+			}
+		})
+	}
+}
+
+func TestNodeOutboundLB(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec func() NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "nodeoutboundlb - valid default outbound rule",
+			networkSpec: createValidNetworkSpec,
+			wantErr:     false,
+		},
+		{
+			name: "nodeoutboundlb - valid allocated ports and idle timeout",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(1024), IdleTimeoutInMinutes: to.Int32Ptr(30)}
+				return n
+			},
+			wantErr: false,
+		},
+		{
+			name: "nodeoutboundlb - invalid allocated ports not a multiple of 8",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(1001)}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - invalid allocated ports above the ports of a frontend IP",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(64008)}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - invalid public IP prefix length",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{PublicIPPrefixLength: to.Int32Ptr(24)}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - valid several frontend IPs from a prefix",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(4), PublicIPPrefixLength: to.Int32Ptr(30)}
+				return n
+			},
+			wantErr: false,
+		},
+		{
+			name: "nodeoutboundlb - invalid frontend IPs count above the SKU limit",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(17)}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - invalid frontend IPs count above the prefix size",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(3), PublicIPPrefixLength: to.Int32Ptr(31)}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - invalid idle timeout",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{IdleTimeoutInMinutes: to.Int32Ptr(60)}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - valid disabled with a default route to a firewall",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLBDisabled = true
+				for _, subnet := range n.Subnets {
+					if subnet.Role == SubnetNode {
+						subnet.RouteTable.Routes = []Route{{Name: "default", AddressPrefix: "0.0.0.0/0", NextHopType: RouteNextHopTypeVirtualAppliance, NextHopIPAddress: "10.0.100.4"}}
+					}
+				}
+				return n
+			},
+			wantErr: false,
+		},
+		{
+			name: "nodeoutboundlb - valid disabled with pre-existing route tables",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLBDisabled = true
+				for _, subnet := range n.Subnets {
+					if subnet.Role == SubnetNode {
+						subnet.RouteTable.ID = "/subscriptions/123/resourceGroups/custom-vnet/providers/Microsoft.Network/routeTables/firewall-routes"
+					}
+				}
+				return n
+			},
+			wantErr: false,
+		},
+		{
+			name: "nodeoutboundlb - invalid disabled without an outbound path for the nodes",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLBDisabled = true
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - invalid disabled with an outbound rule configuration",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLBDisabled = true
+				n.NodeOutboundLB = &NodeOutboundLBSpec{IdleTimeoutInMinutes: to.Int32Ptr(30)}
+				for _, subnet := range n.Subnets {
+					if subnet.Role == SubnetNode {
+						subnet.NatGateway = NatGateway{Name: "node-natgw"}
+					}
+				}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodeoutboundlb - invalid with the Basic load balancer SKU",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.LoadBalancerSKU = SKUBasic
+				n.NodeOutboundLB = &NodeOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(1024)}
+				return n
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateNodeOutboundLB(testCase.networkSpec(), field.NewPath("spec").Child("networkSpec"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestControlPlaneOutboundLB(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec func() NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "controlplaneoutboundlb - valid without a control plane outbound load balancer",
+			networkSpec: createValidNetworkSpec,
+			wantErr:     false,
+		},
+		{
+			name: "controlplaneoutboundlb - valid with an internal API server load balancer",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.Type = Internal
+				n.ControlPlaneOutboundLB = &ControlPlaneOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(1024), IdleTimeoutInMinutes: to.Int32Ptr(30)}
+				return n
+			},
+			wantErr: false,
+		},
+		{
+			name: "controlplaneoutboundlb - invalid with a public API server load balancer",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.Type = Public
+				n.ControlPlaneOutboundLB = &ControlPlaneOutboundLBSpec{}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "controlplaneoutboundlb - invalid with the Basic load balancer SKU",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.Type = Internal
+				n.LoadBalancerSKU = SKUBasic
+				n.ControlPlaneOutboundLB = &ControlPlaneOutboundLBSpec{}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "controlplaneoutboundlb - invalid allocated ports not a multiple of 8",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.Type = Internal
+				n.ControlPlaneOutboundLB = &ControlPlaneOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(1001)}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "controlplaneoutboundlb - invalid idle timeout",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.Type = Internal
+				n.ControlPlaneOutboundLB = &ControlPlaneOutboundLBSpec{IdleTimeoutInMinutes: to.Int32Ptr(2)}
+				return n
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateControlPlaneOutboundLB(testCase.networkSpec(), field.NewPath("spec").Child("networkSpec"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestPublicIPZones(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		networkSpec func() NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "publicipzones - valid non-zonal public IPs",
+			networkSpec: createValidNetworkSpec,
+			wantErr:     false,
+		},
+		{
+			name: "publicipzones - valid zone-redundant public IPs",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.PublicIPZones = []string{"1", "2", "3"}
+				n.NodeOutboundLB = &NodeOutboundLBSpec{PublicIPZones: []string{"1", "2", "3"}}
+				return n
+			},
+			wantErr: false,
+		},
+		{
+			name: "publicipzones - invalid zone",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.PublicIPZones = []string{"4"}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "publicipzones - invalid duplicate zone",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.NodeOutboundLB = &NodeOutboundLBSpec{PublicIPZones: []string{"1", "1"}}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "publicipzones - invalid with the Basic load balancer SKU",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.LoadBalancerSKU = SKUBasic
+				n.APIServerLB.PublicIPZones = []string{"1"}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "publicipzones - valid zone-redundant internal load balancer",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.InternalLBZones = []string{"1", "2", "3"}
+				return n
+			},
+			wantErr: false,
+		},
+		{
+			name: "publicipzones - invalid internal load balancer zone",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.InternalLBZones = []string{"1", "zone-2"}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "publicipzones - invalid zonal internal load balancer with the Basic load balancer SKU",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.LoadBalancerSKU = SKUBasic
+				n.APIServerLB.InternalLBZones = []string{"1", "2", "3"}
+				return n
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validatePublicIPZones(testCase.networkSpec(), field.NewPath("spec").Child("networkSpec"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestHealthProbe(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		probe   *HealthProbe
+		wantErr bool
+	}{
+		{
+			name:    "healthprobe - valid default probe",
+			probe:   nil,
+			wantErr: false,
+		},
+		{
+			name: "healthprobe - valid HTTPS probe",
+			probe: &HealthProbe{
+				Protocol:           ProbeProtocolHTTPS,
+				RequestPath:        "/readyz",
+				IntervalSeconds:    to.Int32Ptr(30),
+				UnhealthyThreshold: to.Int32Ptr(8),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "healthprobe - valid TCP probe",
+			probe:   &HealthProbe{Protocol: ProbeProtocolTCP},
+			wantErr: false,
+		},
+		{
+			name:    "healthprobe - invalid request path for a TCP probe",
+			probe:   &HealthProbe{Protocol: ProbeProtocolTCP, RequestPath: "/healthz"},
+			wantErr: true,
+		},
+		{
+			name:    "healthprobe - invalid relative request path",
+			probe:   &HealthProbe{RequestPath: "healthz"},
+			wantErr: true,
+		},
+		{
+			name:    "healthprobe - invalid interval",
+			probe:   &HealthProbe{IntervalSeconds: to.Int32Ptr(1)},
+			wantErr: true,
+		},
+		{
+			name:    "healthprobe - invalid unhealthy threshold",
+			probe:   &HealthProbe{UnhealthyThreshold: to.Int32Ptr(0)},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateHealthProbe(testCase.probe, field.NewPath("spec").Child("networkSpec").Child("apiServerLB").Child("healthProbe"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func createValidCluster() *AzureCluster {
+	return &AzureCluster{
+		Spec: AzureClusterSpec{
+			NetworkSpec: createValidNetworkSpec(),
+		},
+	}
+}
+
+func createValidNetworkSpec() NetworkSpec {
+	return NetworkSpec{
+		Vnet: VnetSpec{
+			ResourceGroup: "custom-vnet",
+			Name:          "my-vnet",
+		},
+		Subnets: createValidSubnets(),
+	}
+}
+
+func createValidSubnets() Subnets {
+	return Subnets{
+		{
+			Name: "control-plane-subnet",
+			Role: "control-plane",
+		},
+		{
+			Name: "node-subnet",
+			Role: "node",
+		},
+	}
+}
+
+func TestVnetPeerings(t *testing.T) {
+	g := NewWithT(t)
+
+	hubID := "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworks/hub-vnet"
+	tests := []struct {
+		name     string
+		peerings []VnetPeeringSpec
+		wantErr  bool
+	}{
+		{
+			name:     "vnetpeerings - valid without peerings",
+			peerings: nil,
+			wantErr:  false,
+		},
+		{
+			name: "vnetpeerings - valid peering",
+			peerings: []VnetPeeringSpec{
+				{RemoteVnetID: hubID, AllowForwardedTraffic: true, UseRemoteGateways: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "vnetpeerings - invalid remote vnet ID",
+			peerings: []VnetPeeringSpec{
+				{RemoteVnetID: "hub-vnet"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "vnetpeerings - invalid resource type",
+			peerings: []VnetPeeringSpec{
+				{RemoteVnetID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/publicIPAddresses/hub-ip"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "vnetpeerings - invalid duplicate remote vnet",
+			peerings: []VnetPeeringSpec{
+				{RemoteVnetID: hubID},
+				{RemoteVnetID: strings.ToUpper(hubID)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateVnetPeerings(testCase.peerings, field.NewPath("spec").Child("networkSpec").Child("vnetPeerings"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestPrivateEndpoints(t *testing.T) {
+	g := NewWithT(t)
+
+	vaultID := "/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.KeyVault/vaults/my-vault"
+	zoneID := "/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.Network/privateDnsZones/privatelink.vaultcore.azure.net"
+	subnets := Subnets{{Name: "node-subnet", Role: SubnetNode}}
+	tests := []struct {
+		name      string
+		endpoints []PrivateEndpointSpec
+		wantErr   bool
+	}{
+		{
+			name:      "privateendpoints - valid without private endpoints",
+			endpoints: nil,
+			wantErr:   false,
+		},
+		{
+			name: "privateendpoints - valid private endpoint",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "my-vault-pe", SubnetName: "node-subnet", PrivateLinkServiceID: vaultID, GroupIDs: []string{"vault"}, PrivateDNSZoneIDs: []string{zoneID}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "privateendpoints - invalid name",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "-my-vault-pe", SubnetName: "node-subnet", PrivateLinkServiceID: vaultID, GroupIDs: []string{"vault"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateendpoints - invalid duplicate name",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "my-vault-pe", SubnetName: "node-subnet", PrivateLinkServiceID: vaultID, GroupIDs: []string{"vault"}},
+				{Name: "My-Vault-PE", SubnetName: "node-subnet", PrivateLinkServiceID: vaultID, GroupIDs: []string{"vault"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateendpoints - invalid subnet not in the network spec",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "my-vault-pe", SubnetName: "other-subnet", PrivateLinkServiceID: vaultID, GroupIDs: []string{"vault"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateendpoints - invalid private link service ID",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "my-vault-pe", SubnetName: "node-subnet", PrivateLinkServiceID: "my-vault", GroupIDs: []string{"vault"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateendpoints - invalid without group IDs",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "my-vault-pe", SubnetName: "node-subnet", PrivateLinkServiceID: vaultID},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateendpoints - invalid private DNS zone resource type",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "my-vault-pe", SubnetName: "node-subnet", PrivateLinkServiceID: vaultID, GroupIDs: []string{"vault"},
+					PrivateDNSZoneIDs: []string{"/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.Network/dnszones/vaultcore.azure.net"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateendpoints - invalid duplicate private DNS zone",
+			endpoints: []PrivateEndpointSpec{
+				{Name: "my-vault-pe", SubnetName: "node-subnet", PrivateLinkServiceID: vaultID, GroupIDs: []string{"vault"},
+					PrivateDNSZoneIDs: []string{zoneID, strings.ToUpper(zoneID)}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			networkSpec := NetworkSpec{Subnets: subnets, PrivateEndpoints: testCase.endpoints}
+			errs := validatePrivateEndpoints(networkSpec, field.NewPath("spec").Child("networkSpec").Child("privateEndpoints"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAllowedAPIServerCIDRs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		cidrs   []string
+		wantErr bool
+	}{
+		{
+			name:    "allowedapiservercidrs - valid without CIDRs",
+			cidrs:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "allowedapiservercidrs - valid IPv4 and IPv6 CIDRs",
+			cidrs:   []string{"203.0.113.0/24", "198.51.100.7/32", "2001:db8::/32"},
+			wantErr: false,
+		},
+		{
+			name:    "allowedapiservercidrs - invalid IP address without prefix length",
+			cidrs:   []string{"203.0.113.7"},
+			wantErr: true,
+		},
+		{
+			name:    "allowedapiservercidrs - invalid service tag",
+			cidrs:   []string{"Internet"},
+			wantErr: true,
+		},
+		{
+			name:    "allowedapiservercidrs - invalid duplicate CIDR",
+			cidrs:   []string{"203.0.113.0/24", "203.0.113.1/24"},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateAllowedAPIServerCIDRs(testCase.cidrs, field.NewPath("spec").Child("networkSpec").Child("allowedAPIServerCIDRs"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestDNSServers(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		servers []string
+		wantErr bool
+	}{
+		{
+			name:    "dnsservers - valid without servers",
+			servers: nil,
+			wantErr: false,
+		},
+		{
+			name:    "dnsservers - valid IPv4 and IPv6 addresses",
+			servers: []string{"10.0.0.4", "168.63.129.16", "2001:db8::4"},
+			wantErr: false,
+		},
+		{
+			name:    "dnsservers - invalid CIDR",
+			servers: []string{"10.0.0.4/32"},
+			wantErr: true,
+		},
+		{
+			name:    "dnsservers - invalid hostname",
+			servers: []string{"dns.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "dnsservers - invalid duplicate address",
+			servers: []string{"10.0.0.4", "10.0.0.5", "10.0.0.4"},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateDNSServers(testCase.servers, field.NewPath("spec").Child("networkSpec").Child("vnet").Child("dnsServers"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestRouteTable(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name       string
+		routeTable RouteTable
+		wantErr    bool
+	}{
+		{
+			name:       "routetable - valid without routes",
+			routeTable: RouteTable{Name: "my-routetable"},
+			wantErr:    false,
+		},
+		{
+			name: "routetable - valid routes",
+			routeTable: RouteTable{
+				Name: "my-routetable",
+				Routes: []Route{
+					{Name: "to-firewall", AddressPrefix: "0.0.0.0/0", NextHopType: RouteNextHopTypeVirtualAppliance, NextHopIPAddress: "10.100.0.4"},
+					{Name: "to-internet", AddressPrefix: "20.0.0.0/8", NextHopType: RouteNextHopTypeInternet},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "routetable - valid pre-existing route table",
+			routeTable: RouteTable{
+				ID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/routeTables/hub-routetable",
+			},
+			wantErr: false,
+		},
+		{
+			name:       "routetable - invalid route table ID",
+			routeTable: RouteTable{ID: "hub-routetable"},
+			wantErr:    true,
+		},
+		{
+			name: "routetable - invalid address prefix",
+			routeTable: RouteTable{
+				Routes: []Route{{Name: "to-internet", AddressPrefix: "20.0.0.0", NextHopType: RouteNextHopTypeInternet}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "routetable - invalid virtual appliance route without next hop IP",
+			routeTable: RouteTable{
+				Routes: []Route{{Name: "to-firewall", AddressPrefix: "0.0.0.0/0", NextHopType: RouteNextHopTypeVirtualAppliance}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "routetable - invalid next hop IP for another next hop type",
+			routeTable: RouteTable{
+				Routes: []Route{{Name: "to-internet", AddressPrefix: "0.0.0.0/0", NextHopType: RouteNextHopTypeInternet, NextHopIPAddress: "10.100.0.4"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "routetable - invalid duplicate route names",
+			routeTable: RouteTable{
+				Routes: []Route{
+					{Name: "default", AddressPrefix: "0.0.0.0/0", NextHopType: RouteNextHopTypeInternet},
+					{Name: "default", AddressPrefix: "10.0.0.0/8", NextHopType: RouteNextHopTypeVnetLocal},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateRouteTable(testCase.routeTable, field.NewPath("spec").Child("networkSpec").Child("subnets").Index(0).Child("routeTable"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAPIServerDNSLabel(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		lb      LoadBalancerSpec
+		wantErr bool
+	}{
+		{
+			name:    "dnslabel - valid without a DNS label",
+			lb:      LoadBalancerSpec{},
+			wantErr: false,
+		},
+		{
+			name:    "dnslabel - valid DNS label",
+			lb:      LoadBalancerSpec{DNSLabel: "my-cluster-1"},
+			wantErr: false,
+		},
+		{
+			name:    "dnslabel - invalid uppercase letters",
+			lb:      LoadBalancerSpec{DNSLabel: "My-Cluster"},
+			wantErr: true,
+		},
+		{
+			name:    "dnslabel - invalid leading digit",
+			lb:      LoadBalancerSpec{DNSLabel: "1-cluster"},
+			wantErr: true,
+		},
+		{
+			name:    "dnslabel - invalid trailing hyphen",
+			lb:      LoadBalancerSpec{DNSLabel: "my-cluster-"},
+			wantErr: true,
+		},
+		{
+			name:    "dnslabel - invalid too short",
+			lb:      LoadBalancerSpec{DNSLabel: "ab"},
+			wantErr: true,
+		},
+		{
+			name:    "dnslabel - invalid too long",
+			lb:      LoadBalancerSpec{DNSLabel: "a" + strings.Repeat("b", 63)},
+			wantErr: true,
+		},
+		{
+			name:    "dnslabel - invalid on an internal load balancer",
+			lb:      LoadBalancerSpec{Type: Internal, DNSLabel: "my-cluster"},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateAPIServerDNSLabel(testCase.lb, field.NewPath("spec").Child("networkSpec").Child("apiServerLB").Child("dnsLabel"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAdditionalAPIServerIPs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name            string
+		networkSpec     func() NetworkSpec
+		apiServerIPName string
+		wantErr         bool
+	}{
+		{
+			name:        "additionalpublicips - valid without additional public IPs",
+			networkSpec: createValidNetworkSpec,
+			wantErr:     false,
+		},
+		{
+			name: "additionalpublicips - valid additional public IPs",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.AdditionalPublicIPNames = []string{"my-cluster-api-2", "my-cluster-api-3"}
+				return n
+			},
+			apiServerIPName: "my-cluster-api",
+			wantErr:         false,
+		},
+		{
+			name: "additionalpublicips - invalid name",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.AdditionalPublicIPNames = []string{"-my-cluster-api"}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "additionalpublicips - invalid duplicate names",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.AdditionalPublicIPNames = []string{"my-cluster-api-2", "My-Cluster-API-2"}
+				return n
+			},
+			wantErr: true,
+		},
+		{
+			name: "additionalpublicips - invalid default API server public IP",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.AdditionalPublicIPNames = []string{"my-cluster-api"}
+				return n
+			},
+			apiServerIPName: "my-cluster-api",
+			wantErr:         true,
+		},
+		{
+			name: "additionalpublicips - invalid on an internal load balancer",
+			networkSpec: func() NetworkSpec {
+				n := createValidNetworkSpec()
+				n.APIServerLB.Type = Internal
+				n.APIServerLB.AdditionalPublicIPNames = []string{"my-cluster-api-2"}
+				return n
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateAdditionalAPIServerIPs(testCase.networkSpec(), testCase.apiServerIPName,
+				field.NewPath("spec").Child("networkSpec").Child("apiServerLB").Child("additionalPublicIPNames"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestSubnetID(t *testing.T) {
+	g := NewWithT(t)
+
+	vnet := VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet"}
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{
+			name:    "subnet id - valid",
+			id:      "/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/cp-subnet",
+			wantErr: false,
+		},
+		{
+			name:    "subnet id - valid with a different case",
+			id:      "/subscriptions/123/resourcegroups/Custom-Vnet-RG/providers/Microsoft.Network/virtualNetworks/Custom-Vnet/subnets/CP-Subnet",
+			wantErr: false,
+		},
+		{
+			name:    "subnet id - invalid format",
+			id:      "cp-subnet",
+			wantErr: true,
+		},
+		{
+			name:    "subnet id - invalid resource group",
+			id:      "/subscriptions/123/resourceGroups/other-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/cp-subnet",
+			wantErr: true,
+		},
+		{
+			name:    "subnet id - invalid vnet",
+			id:      "/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/other-vnet/subnets/cp-subnet",
+			wantErr: true,
+		},
+		{
+			name:    "subnet id - invalid subnet name",
+			id:      "/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/other-subnet",
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateSubnetID(testCase.id, "cp-subnet", vnet, field.NewPath("spec").Child("networkSpec").Child("subnets").Index(0).Child("id"))
+			if testCase.wantErr {
+				g.Expect(err).NotTo(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestFailureDomains(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name           string
+		failureDomains *FailureDomainsSpec
+		sku            SKU
+		wantErr        bool
+	}{
+		{
+			name:    "failuredomains - valid when not set",
+			wantErr: false,
+		},
+		{
+			name: "failuredomains - valid overrides",
+			failureDomains: &FailureDomainsSpec{
+				ControlPlaneVMSize: "Standard_D2s_v3",
+				Include:            []string{"1"},
+				Exclude:            []string{"3"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failuredomains - invalid zone both included and excluded",
+			failureDomains: &FailureDomainsSpec{
+				Include: []string{"1", "2"},
+				Exclude: []string{"2"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failuredomains - valid control plane zones",
+			failureDomains: &FailureDomainsSpec{
+				ControlPlaneVMSize: "Standard_D2s_v3",
+				ControlPlaneZones:  []string{"3", "1", "2"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failuredomains - invalid control plane zones with included zones",
+			failureDomains: &FailureDomainsSpec{
+				Include:           []string{"1"},
+				ControlPlaneZones: []string{"1", "2"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failuredomains - invalid duplicate control plane zone",
+			failureDomains: &FailureDomainsSpec{
+				ControlPlaneZones: []string{"1", "2", "1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failuredomains - invalid control plane zones with Basic load balancer",
+			failureDomains: &FailureDomainsSpec{
+				ControlPlaneZones: []string{"1", "2", "3"},
+			},
+			sku:     SKUBasic,
+			wantErr: true,
+		},
+		{
+			name: "failuredomains - invalid excluded control plane zone",
+			failureDomains: &FailureDomainsSpec{
+				Exclude:           []string{"3"},
+				ControlPlaneZones: []string{"1", "3"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateFailureDomains(testCase.failureDomains, testCase.sku, field.NewPath("spec").Child("failureDomains"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestProximityPlacementGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name           string
+		ppg            *ProximityPlacementGroupSpec
+		failureDomains *FailureDomainsSpec
+		sku            SKU
+		wantErr        bool
+	}{
+		{
+			name:    "proximityplacementgroup - valid when not set",
+			sku:     SKUStandard,
+			wantErr: false,
+		},
+		{
+			name:    "proximityplacementgroup - valid without failure domain",
+			ppg:     &ProximityPlacementGroupSpec{Name: "my-ppg"},
+			sku:     SKUBasic,
+			wantErr: false,
+		},
+		{
+			name: "proximityplacementgroup - valid failure domain",
+			ppg:  &ProximityPlacementGroupSpec{FailureDomain: to.StringPtr("1")},
+			failureDomains: &FailureDomainsSpec{
+				Exclude: []string{"3"},
+			},
+			sku:     SKUStandard,
+			wantErr: false,
+		},
+		{
+			name: "proximityplacementgroup - invalid excluded failure domain",
+			ppg:  &ProximityPlacementGroupSpec{FailureDomain: to.StringPtr("3")},
+			failureDomains: &FailureDomainsSpec{
+				Exclude: []string{"3"},
+			},
+			sku:     SKUStandard,
+			wantErr: true,
+		},
+		{
+			name: "proximityplacementgroup - invalid failure domain outside the control plane zones",
+			ppg:  &ProximityPlacementGroupSpec{FailureDomain: to.StringPtr("3")},
+			failureDomains: &FailureDomainsSpec{
+				ControlPlaneZones: []string{"1", "2"},
+			},
+			sku:     SKUStandard,
+			wantErr: true,
+		},
+		{
+			name:    "proximityplacementgroup - invalid failure domain with Basic load balancer",
+			ppg:     &ProximityPlacementGroupSpec{FailureDomain: to.StringPtr("1")},
+			sku:     SKUBasic,
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateProximityPlacementGroup(testCase.ppg, testCase.failureDomains, testCase.sku, field.NewPath("spec").Child("proximityPlacementGroup"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAPIServerPublicIPID(t *testing.T) {
+	g := NewWithT(t)
+
+	const publicIPID = "/subscriptions/123/resourceGroups/my-ips/providers/Microsoft.Network/publicIPAddresses/my-static-ip"
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "publicipid - valid without a public IP ID",
+			networkSpec: NetworkSpec{},
+			wantErr:     false,
+		},
+		{
+			name:        "publicipid - valid public IP ID",
+			networkSpec: NetworkSpec{APIServerLB: LoadBalancerSpec{PublicIPID: publicIPID}},
+			wantErr:     false,
+		},
+		{
+			name: "publicipid - invalid resource ID of another resource type",
+			networkSpec: NetworkSpec{APIServerLB: LoadBalancerSpec{
+				PublicIPID: "/subscriptions/123/resourceGroups/my-ips/providers/Microsoft.Network/loadBalancers/my-lb",
+			}},
+			wantErr: true,
+		},
+		{
+			name:        "publicipid - invalid on an internal load balancer",
+			networkSpec: NetworkSpec{APIServerLB: LoadBalancerSpec{Type: Internal, PublicIPID: publicIPID}},
+			wantErr:     true,
+		},
+		{
+			name:        "publicipid - invalid with a DNS label",
+			networkSpec: NetworkSpec{APIServerLB: LoadBalancerSpec{PublicIPID: publicIPID, DNSLabel: "my-cluster"}},
+			wantErr:     true,
+		},
+		{
+			name:        "publicipid - invalid with public IP zones",
+			networkSpec: NetworkSpec{APIServerLB: LoadBalancerSpec{PublicIPID: publicIPID, PublicIPZones: []string{"1"}}},
+			wantErr:     true,
+		},
+		{
+			name:        "publicipid - invalid with a Basic SKU load balancer",
+			networkSpec: NetworkSpec{LoadBalancerSKU: SKUBasic, APIServerLB: LoadBalancerSpec{PublicIPID: publicIPID}},
+			wantErr:     true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateAPIServerPublicIPID(testCase.networkSpec, field.NewPath("spec").Child("networkSpec").Child("apiServerLB"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestCloudProviderConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		config  *CloudProviderConfigSpec
+		wantErr bool
+	}{
+		{
+			name:    "cloudproviderconfig - valid when not set",
+			wantErr: false,
+		},
+		{
+			name:    "cloudproviderconfig - valid service principal",
+			config:  &CloudProviderConfigSpec{},
+			wantErr: false,
+		},
+		{
+			name:    "cloudproviderconfig - valid system-assigned identity",
+			config:  &CloudProviderConfigSpec{Identity: VMIdentitySystemAssigned},
+			wantErr: false,
+		},
+		{
+			name:    "cloudproviderconfig - valid user-assigned identity",
+			config:  &CloudProviderConfigSpec{Identity: VMIdentityUserAssigned, UserAssignedIdentityID: "00000000-0000-0000-0000-000000000000"},
+			wantErr: false,
+		},
+		{
+			name:    "cloudproviderconfig - invalid user-assigned identity without ID",
+			config:  &CloudProviderConfigSpec{Identity: VMIdentityUserAssigned},
+			wantErr: true,
+		},
+		{
+			name:    "cloudproviderconfig - invalid user-assigned identity ID with system-assigned identity",
+			config:  &CloudProviderConfigSpec{Identity: VMIdentitySystemAssigned, UserAssignedIdentityID: "00000000-0000-0000-0000-000000000000"},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateCloudProviderConfig(testCase.config, field.NewPath("spec").Child("cloudProviderConfig"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestPublicIPTags(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		tags    Tags
+		wantErr bool
+	}{
+		{
+			name:    "publicIPTags - unset",
+			tags:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "publicIPTags - valid tags",
+			tags:    Tags{"firewall": "allow-egress", "team": "platform"},
+			wantErr: false,
+		},
+		{
+			name:    "publicIPTags - empty tag name",
+			tags:    Tags{"": "value"},
+			wantErr: true,
+		},
+		{
+			name:    "publicIPTags - tag name too long",
+			tags:    Tags{strings.Repeat("a", 513): "value"},
+			wantErr: true,
+		},
+		{
+			name:    "publicIPTags - invalid character in tag name",
+			tags:    Tags{"firewall/allowlist": "true"},
+			wantErr: true,
+		},
+		{
+			name:    "publicIPTags - tag value too long",
+			tags:    Tags{"firewall": strings.Repeat("a", 257)},
+			wantErr: true,
+		},
+		{
+			name:    "publicIPTags - Name tag set by the provider",
+			tags:    Tags{"name": "my-ip"},
+			wantErr: true,
+		},
+		{
+			name:    "publicIPTags - ownership tag set by the provider",
+			tags:    Tags{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "shared"},
+			wantErr: true,
+		},
+		{
+			name:    "publicIPTags - cloud provider tag",
+			tags:    Tags{"Kubernetes.io_cluster_my-cluster": "owned"},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validatePublicIPTags(testCase.tags,
+				field.NewPath("spec").Child("networkSpec").Child("nodeOutboundLB").Child("publicIPTags"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestPrivateLinkService(t *testing.T) {
+	g := NewWithT(t)
+
+	subscriptionID := "00000000-1111-2222-3333-444444444444"
+	tests := []struct {
+		name        string
+		networkSpec NetworkSpec
+		wantErr     bool
+	}{
+		{
+			name:        "privateLinkService - unset",
+			networkSpec: NetworkSpec{},
+			wantErr:     false,
+		},
+		{
+			name: "privateLinkService - valid",
+			networkSpec: NetworkSpec{
+				Subnets: Subnets{{Name: "pls-subnet", Role: SubnetNode}},
+				APIServerLB: LoadBalancerSpec{PrivateLinkService: &PrivateLinkServiceSpec{
+					SubnetName:                "pls-subnet",
+					VisibilitySubscriptions:   []string{"*"},
+					AutoApprovalSubscriptions: []string{subscriptionID},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "privateLinkService - basic load balancer SKU",
+			networkSpec: NetworkSpec{
+				LoadBalancerSKU: SKUBasic,
+				APIServerLB:     LoadBalancerSpec{PrivateLinkService: &PrivateLinkServiceSpec{}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateLinkService - subnet not in the cluster vnet",
+			networkSpec: NetworkSpec{
+				Subnets:     Subnets{{Name: "node-subnet", Role: SubnetNode}},
+				APIServerLB: LoadBalancerSpec{PrivateLinkService: &PrivateLinkServiceSpec{SubnetName: "pls-subnet"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateLinkService - invalid visibility subscription",
+			networkSpec: NetworkSpec{
+				APIServerLB: LoadBalancerSpec{PrivateLinkService: &PrivateLinkServiceSpec{VisibilitySubscriptions: []string{"my-subscription"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "privateLinkService - any subscription can't be auto-approved",
+			networkSpec: NetworkSpec{
+				APIServerLB: LoadBalancerSpec{PrivateLinkService: &PrivateLinkServiceSpec{AutoApprovalSubscriptions: []string{"*"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validatePrivateLinkService(testCase.networkSpec,
+				field.NewPath("spec").Child("networkSpec").Child("apiServerLB").Child("privateLinkService"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestLoadDistribution(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name         string
+		distribution LoadDistribution
+		wantErr      bool
+	}{
+		{
+			name:         "loadDistribution - unset",
+			distribution: "",
+			wantErr:      false,
+		},
+		{
+			name:         "loadDistribution - valid Default",
+			distribution: LoadDistributionDefault,
+			wantErr:      false,
+		},
+		{
+			name:         "loadDistribution - valid SourceIP",
+			distribution: LoadDistributionSourceIP,
+			wantErr:      false,
+		},
+		{
+			name:         "loadDistribution - valid SourceIPProtocol",
+			distribution: LoadDistributionSourceIPProtocol,
+			wantErr:      false,
+		},
+		{
+			name:         "loadDistribution - invalid value",
+			distribution: "Sticky",
+			wantErr:      true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateLoadDistribution(testCase.distribution,
+				field.NewPath("spec").Child("networkSpec").Child("apiServerLB").Child("loadDistribution"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestInternalLBInboundNatRules(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		rules   []InboundNatRule
+		wantErr bool
+	}{
+		{
+			name: "internalLBInboundNatRules - valid rules",
+			rules: []InboundNatRule{
+				{Name: "etcd", FrontendPortRangeStart: 12379, FrontendPortRangeEnd: 12381, BackendPort: 2379},
+				{Name: "etcd-peer", FrontendPortRangeStart: 12382, FrontendPortRangeEnd: 12384, BackendPort: 2380},
+			},
+			wantErr: false,
+		},
+		{
+			name: "internalLBInboundNatRules - invalid name",
+			rules: []InboundNatRule{
+				{Name: "etcd/client", FrontendPortRangeStart: 12379, FrontendPortRangeEnd: 12381, BackendPort: 2379},
+			},
+			wantErr: true,
+		},
+		{
+			name: "internalLBInboundNatRules - duplicate names",
+			rules: []InboundNatRule{
+				{Name: "etcd", FrontendPortRangeStart: 12379, FrontendPortRangeEnd: 12381, BackendPort: 2379},
+				{Name: "etcd", FrontendPortRangeStart: 12382, FrontendPortRangeEnd: 12384, BackendPort: 2380},
+			},
+			wantErr: true,
+		},
+		{
+			name: "internalLBInboundNatRules - backend port out of range",
+			rules: []InboundNatRule{
+				{Name: "etcd", FrontendPortRangeStart: 12379, FrontendPortRangeEnd: 12381, BackendPort: 70000},
+			},
+			wantErr: true,
+		},
+		{
+			name: "internalLBInboundNatRules - range ending before its start",
+			rules: []InboundNatRule{
+				{Name: "etcd", FrontendPortRangeStart: 12381, FrontendPortRangeEnd: 12379, BackendPort: 2379},
+			},
+			wantErr: true,
+		},
+		{
+			name: "internalLBInboundNatRules - overlapping ranges",
+			rules: []InboundNatRule{
+				{Name: "etcd", FrontendPortRangeStart: 12379, FrontendPortRangeEnd: 12381, BackendPort: 2379},
+				{Name: "etcd-peer", FrontendPortRangeStart: 12381, FrontendPortRangeEnd: 12383, BackendPort: 2380},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateInternalLBInboundNatRules(testCase.rules,
+				field.NewPath("spec").Child("networkSpec").Child("apiServerLB").Child("internalLBInboundNatRules"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestResyncPeriod(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "resync period - unset",
+			annotations: map[string]string{},
+			wantErr:     false,
+		},
+		{
+			name:        "resync period - valid",
+			annotations: map[string]string{ResyncPeriodAnnotation: "5m"},
+			wantErr:     false,
+		},
+		{
+			name:        "resync period - longest",
+			annotations: map[string]string{ResyncPeriodAnnotation: "24h"},
+			wantErr:     false,
+		},
+		{
+			name:        "resync period - not a duration",
+			annotations: map[string]string{ResyncPeriodAnnotation: "5"},
+			wantErr:     true,
+		},
+		{
+			name:        "resync period - too short",
+			annotations: map[string]string{ResyncPeriodAnnotation: "30s"},
+			wantErr:     true,
+		},
+		{
+			name:        "resync period - too long",
+			annotations: map[string]string{ResyncPeriodAnnotation: "48h"},
+			wantErr:     true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateResyncPeriod(testCase.annotations)
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestApplicationGateway(t *testing.T) {
+	g := NewWithT(t)
+
+	secretID := "https://my-vault.vault.azure.net/secrets/my-certificate"
+	identityID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity"
+	tests := []struct {
+		name       string
+		appGateway *ApplicationGatewaySpec
+		wantErr    bool
+	}{
+		{
+			name:       "applicationGateway - unset",
+			appGateway: nil,
+			wantErr:    false,
+		},
+		{
+			name: "applicationGateway - valid",
+			appGateway: &ApplicationGatewaySpec{
+				SSLCertificateSecretID: secretID,
+				IdentityID:             identityID,
+				FirewallPolicyID:       "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/ApplicationGatewayWebApplicationFirewallPolicies/my-policy",
+				Subnet:                 ApplicationGatewaySubnetSpec{Name: "my-appgw-subnet", CidrBlock: "10.1.0.0/24"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "applicationGateway - versioned certificate secret",
+			appGateway: &ApplicationGatewaySpec{
+				SSLCertificateSecretID: secretID + "/0123456789abcdef0123456789abcdef",
+				IdentityID:             identityID,
+			},
+			wantErr: false,
+		},
+		{
+			name:       "applicationGateway - missing certificate secret and identity",
+			appGateway: &ApplicationGatewaySpec{},
+			wantErr:    true,
+		},
+		{
+			name: "applicationGateway - certificate secret not in Key Vault",
+			appGateway: &ApplicationGatewaySpec{
+				SSLCertificateSecretID: "https://example.com/my-certificate.pfx",
+				IdentityID:             identityID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "applicationGateway - identity isn't user-assigned",
+			appGateway: &ApplicationGatewaySpec{
+				SSLCertificateSecretID: secretID,
+				IdentityID:             "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+			},
+			wantErr: true,
+		},
+		{
+			name: "applicationGateway - invalid firewall policy",
+			appGateway: &ApplicationGatewaySpec{
+				SSLCertificateSecretID: secretID,
+				IdentityID:             identityID,
+				FirewallPolicyID:       "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/firewallPolicies/my-policy",
+			},
+			wantErr: true,
+		},
+		{
+			name: "applicationGateway - invalid subnet name",
+			appGateway: &ApplicationGatewaySpec{
+				SSLCertificateSecretID: secretID,
+				IdentityID:             identityID,
+				Subnet:                 ApplicationGatewaySubnetSpec{Name: "my appgw subnet"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := validateApplicationGateway(testCase.appGateway, field.NewPath("spec").Child("networkSpec").Child("applicationGateway"))
+			if testCase.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
 	}
 }
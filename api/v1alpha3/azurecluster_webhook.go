@@ -56,7 +56,7 @@ func (c *AzureCluster) ValidateCreate() error {
 func (c *AzureCluster) ValidateUpdate(old runtime.Object) error {
 	clusterlog.Info("validate update", "name", c.Name)
 
-	return c.validateCluster()
+	return c.validateClusterUpdate(old.(*AzureCluster))
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
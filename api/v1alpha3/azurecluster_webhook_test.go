@@ -19,6 +19,7 @@ package v1alpha3
 import (
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
 )
 
@@ -92,6 +93,35 @@ func TestAzureCluster_ValidateCreate(t *testing.T) {
 			}(),
 			wantErr: true,
 		},
+		{
+			name: "azurecluster with a default image",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.DefaultImage = &Image{
+					SharedGallery: &AzureSharedGalleryImage{
+						SubscriptionID: "123",
+						ResourceGroup:  "my-images",
+						Gallery:        "my-gallery",
+						Name:           "my-image",
+						Version:        "latest",
+					},
+				}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster with an invalid default image",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.DefaultImage = &Image{
+					ID:          to.StringPtr("/subscriptions/123/resourceGroups/my-images/providers/Microsoft.Compute/images/my-image"),
+					Marketplace: &AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot19dot1-ubuntu-1804", Version: "latest"},
+				}
+				return cluster
+			}(),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -175,6 +205,33 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			}(),
 			wantErr: true,
 		},
+		{
+			name: "azurecluster with an invalid network resource group name",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkResourceGroup = "invalid-name###"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster with a changed network resource group",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkResourceGroup = "network-rg"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster with the network resource group set to the cluster resource group",
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkResourceGroup = cluster.Spec.ResourceGroup
+				return cluster
+			}(),
+			wantErr: false,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -187,3 +244,136 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureCluster_ValidateUpdateImmutableFields(t *testing.T) {
+	g := NewWithT(t)
+
+	provisionedCluster := func() *AzureCluster {
+		cluster := createValidCluster()
+		cluster.Spec.Location = "westus2"
+		cluster.Spec.ResourceGroup = "my-rg"
+		cluster.Spec.NetworkSpec.Vnet.CidrBlock = "10.0.0.0/8"
+		cluster.Status.Ready = true
+		return cluster
+	}
+
+	tests := []struct {
+		name    string
+		old     *AzureCluster
+		cluster *AzureCluster
+		wantErr string
+	}{
+		{
+			name: "changed additional tags",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.AdditionalTags = Tags{"env": "prod"}
+				return cluster
+			}(),
+		},
+		{
+			name: "changed location",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.Location = "eastus"
+				return cluster
+			}(),
+			wantErr: `spec.location: Invalid value: "eastus": cannot be changed from "westus2" once the cluster is provisioned`,
+		},
+		{
+			name: "changed resource group",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.ResourceGroup = "other-rg"
+				return cluster
+			}(),
+			wantErr: `spec.resourceGroup: Invalid value: "other-rg": cannot be changed from "my-rg" once the cluster is provisioned`,
+		},
+		{
+			name: "changed vnet name",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.NetworkSpec.Vnet.Name = "other-vnet"
+				return cluster
+			}(),
+			wantErr: `spec.networkSpec.vnet.name: Invalid value: "other-vnet": cannot be changed from "my-vnet" once the cluster is provisioned`,
+		},
+		{
+			name: "changed vnet CIDR",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.NetworkSpec.Vnet.CidrBlock = "10.1.0.0/16"
+				return cluster
+			}(),
+			wantErr: `spec.networkSpec.vnet.cidrBlock: Invalid value: "10.1.0.0/16": cannot be changed from "10.0.0.0/8" once the cluster is provisioned`,
+		},
+		{
+			name: "changed API server DNS label",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.NetworkSpec.APIServerLB.DNSLabel = "my-cluster"
+				return cluster
+			}(),
+			wantErr: `spec.networkSpec.apiServerLB.dnsLabel: Invalid value: "my-cluster": cannot be changed from "" once the cluster is provisioned`,
+		},
+		{
+			name: "changed API server public IP ID",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.NetworkSpec.APIServerLB.PublicIPID = "/subscriptions/123/resourceGroups/my-ips/providers/Microsoft.Network/publicIPAddresses/my-static-ip"
+				return cluster
+			}(),
+			wantErr: `spec.networkSpec.apiServerLB.publicIPID: Invalid value: "/subscriptions/123/resourceGroups/my-ips/providers/Microsoft.Network/publicIPAddresses/my-static-ip": cannot be changed from "" once the cluster is provisioned`,
+		},
+		{
+			name: "changed internal load balancer zones",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.NetworkSpec.APIServerLB.InternalLBZones = []string{"1", "2", "3"}
+				return cluster
+			}(),
+			wantErr: `spec.networkSpec.apiServerLB.internalLBZones: Invalid value: "1,2,3": cannot be changed from "" once the cluster is provisioned`,
+		},
+		{
+			name: "changed case of the location",
+			old:  provisionedCluster(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.Location = "WestUS2"
+				return cluster
+			}(),
+		},
+		{
+			name: "changed location of a cluster not provisioned yet",
+			old: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Status.Ready = false
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := provisionedCluster()
+				cluster.Spec.Location = "eastus"
+				return cluster
+			}(),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cluster.ValidateUpdate(tc.old)
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AzureClusterIdentityClientSecretKey is the key of the client secret in the secret referenced by an AzureClusterIdentity.
+	AzureClusterIdentityClientSecretKey = "clientSecret"
+)
+
+// AzureClusterIdentitySpec defines the service principal used by the provider to manage the resources of a cluster.
+type AzureClusterIdentitySpec struct {
+	// ClientID is the client ID of the service principal.
+	ClientID string `json:"clientID"`
+
+	// TenantID is the ID of the tenant of the service principal.
+	TenantID string `json:"tenantID"`
+
+	// ClientSecret references the secret holding the client secret of the service principal in its clientSecret key.
+	// The secret must be in the namespace of the AzureClusterIdentity, which is used if the reference has no namespace.
+	ClientSecret corev1.SecretReference `json:"clientSecret"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="ClientID",type="string",JSONPath=".spec.clientID"
+// +kubebuilder:printcolumn:name="TenantID",type="string",JSONPath=".spec.tenantID"
+// +kubebuilder:resource:path=azureclusteridentities,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// AzureClusterIdentity is the Schema for the azureclusteridentities API
+type AzureClusterIdentity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureClusterIdentitySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AzureClusterIdentityList contains a list of AzureClusterIdentity
+type AzureClusterIdentityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureClusterIdentity `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureClusterIdentity{}, &AzureClusterIdentityList{})
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -112,6 +114,28 @@ func validateMarketplaceImage(image *Image, fldPath *field.Path) field.ErrorList
 	if image.Marketplace.Version == "" {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("Version"), "", "Version cannot be empty when specifying an AzureMarketplaceImage"))
 	}
+	if image.Marketplace.Plan != nil {
+		allErrs = append(allErrs, validateImagePlan(image.Marketplace, fldPath.Child("Plan"))...)
+	}
+	return allErrs
+}
+
+func validateImagePlan(image *AzureMarketplaceImage, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if image.Plan.Publisher == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Publisher"), "", "Publisher cannot be empty when specifying an ImagePlan"))
+	} else if !strings.EqualFold(image.Plan.Publisher, image.Publisher) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Publisher"), image.Plan.Publisher, "Publisher must be the publisher of the image"))
+	}
+	if image.Plan.Product == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Product"), "", "Product cannot be empty when specifying an ImagePlan"))
+	} else if !strings.EqualFold(image.Plan.Product, image.Offer) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Product"), image.Plan.Product, "Product must be the offer of the image"))
+	}
+	if image.Plan.Name == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Name"), "", "Name cannot be empty when specifying an ImagePlan"))
+	}
 	return allErrs
 }
 
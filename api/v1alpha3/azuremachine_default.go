@@ -43,6 +43,13 @@ func (m *AzureMachine) SetDefaultSSHPublicKey() error {
 	return nil
 }
 
+// SetDefaultOSType sets the default OS type of an AzureMachine, Linux
+func (m *AzureMachine) SetDefaultOSType() {
+	if m.Spec.OSDisk.OSType == "" {
+		m.Spec.OSDisk.OSType = LinuxOS
+	}
+}
+
 // SetDefaultsDataDisks sets the data disk defaults for an AzureMachine
 func (m *AzureMachine) SetDataDisksDefaults() {
 	set := make(map[int32]struct{})
@@ -45,6 +45,19 @@ func TestAzureMachine_SetDefaultSSHPublicKey(t *testing.T) {
 	g.Expect(publicKeyNotExistTest.machine.Spec.SSHPublicKey).To(Not(BeEmpty()))
 }
 
+func TestAzureMachine_SetDefaultOSType(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := hardcodedAzureMachineWithSSHKey(generateSSHPublicKey())
+	machine.Spec.OSDisk.OSType = ""
+	machine.SetDefaultOSType()
+	g.Expect(machine.Spec.OSDisk.OSType).To(Equal(LinuxOS))
+
+	machine.Spec.OSDisk.OSType = WindowsOS
+	machine.SetDefaultOSType()
+	g.Expect(machine.Spec.OSDisk.OSType).To(Equal(WindowsOS))
+}
+
 func TestAzureMachine_SetDataDisksDefaults(t *testing.T) {
 	cases := []struct {
 		name   string
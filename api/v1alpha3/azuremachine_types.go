@@ -74,8 +74,16 @@ type AzureMachineSpec struct {
 
 	Location string `json:"location"`
 
+	// SSHPublicKey is the base64 encoded SSH public key added to the authorized_keys of the admin user of the VM.
+	// It must be a single authorized_keys entry. A key is generated and its private key discarded when it isn't set.
 	SSHPublicKey string `json:"sshPublicKey"`
 
+	// AdminUsername is the username of the admin user of the VM, whose authorized_keys holds the SSH public key of
+	// Linux VMs. It can't be one of the usernames Azure disallows, such as admin or root, and can be up to 64
+	// characters long for Linux VMs and 20 for Windows VMs. Defaults to capi.
+	// +optional
+	AdminUsername string `json:"adminUsername,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// Azure provider. If both the AzureCluster and the AzureMachine specify the same tag name with different values, the
 	// AzureMachine's value takes precedence.
@@ -86,16 +94,40 @@ type AzureMachineSpec struct {
 	// +optional
 	AllocatePublicIP bool `json:"allocatePublicIP,omitempty"`
 
-	// AcceleratedNetworking enables or disables Azure accelerated networking. If omitted, it will be set based on
-	// whether the requested VMSize supports accelerated networking.
-	// If AcceleratedNetworking is set to true with a VMSize that does not support it, Azure will return an error.
+	// AcceleratedNetworking enables or disables Azure accelerated networking. If omitted, the default of the cluster
+	// is used, or it will be set based on whether the requested VMSize supports accelerated networking.
+	// If AcceleratedNetworking is enabled with a VMSize that does not support it, the machine fails to be created.
 	// +kubebuilder:validation:nullable
 	// +optional
 	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
 
+	// SubnetName is the name of the subnet of the cluster the network interfaces of the VM are placed in, which must
+	// have the role of the machine. Defaults to a node subnet of the cluster for nodes, and to the control plane
+	// subnet of the availability zone of the machine for control plane machines.
+	// +optional
+	SubnetName string `json:"subnetName,omitempty"`
+
 	// SpotVMOptions allows the ability to specify the Machine should use a Spot VM
 	// +optional
 	SpotVMOptions *SpotVMOptions `json:"spotVMOptions,omitempty"`
+
+	// DedicatedHost places the VM on an Azure Dedicated Host.
+	// +optional
+	DedicatedHost *DedicatedHost `json:"dedicatedHost,omitempty"`
+
+	// DiskEncryptionSetID is the resource ID of the disk encryption set encrypting the OS and data disks of the VM
+	// with a customer-managed key. Defaults to the disk encryption set of the cluster.
+	// +optional
+	DiskEncryptionSetID string `json:"diskEncryptionSetID,omitempty"`
+
+	// BootDiagnostics configures the boot diagnostics of the VM, which provide its serial console and screenshots.
+	// +optional
+	BootDiagnostics *BootDiagnostics `json:"bootDiagnostics,omitempty"`
+
+	// VMExtensions are the VM extensions installed on the VM once it is created. Extensions removed from the list are
+	// uninstalled from the VM.
+	// +optional
+	VMExtensions []VMExtension `json:"vmExtensions,omitempty"`
 }
 
 // SpotVMOptions defines the options relevant to running the Machine on Spot VMs
@@ -104,6 +136,86 @@ type SpotVMOptions struct {
 	// +optional
 	// +kubebuilder:validation:Type=number
 	MaxPrice *string `json:"maxPrice,omitempty"`
+
+	// EvictionPolicy defines what happens to a Spot VM when it is evicted: Deallocate stops it and keeps its disks,
+	// Delete deletes it with its disks. Defaults to Deallocate.
+	// +kubebuilder:validation:Enum=Deallocate;Delete
+	// +optional
+	EvictionPolicy *SpotEvictionPolicy `json:"evictionPolicy,omitempty"`
+}
+
+// SpotEvictionPolicy defines the eviction policy of a Spot VM.
+type SpotEvictionPolicy string
+
+const (
+	// SpotEvictionPolicyDeallocate stops an evicted Spot VM and keeps its disks.
+	SpotEvictionPolicyDeallocate SpotEvictionPolicy = "Deallocate"
+	// SpotEvictionPolicyDelete deletes an evicted Spot VM with its disks.
+	SpotEvictionPolicyDelete SpotEvictionPolicy = "Delete"
+)
+
+// DedicatedHost defines the Azure Dedicated Host group, and optionally the host of the group, a VM is placed on.
+type DedicatedHost struct {
+	// HostGroupID is the resource ID of the dedicated host group of the VM.
+	HostGroupID string `json:"hostGroupID"`
+
+	// HostID is the resource ID of the dedicated host of the VM, a host of the host group. When it isn't set,
+	// Azure places the VM on a host of the group, which must support automatic placement.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+}
+
+// BootDiagnostics defines the boot diagnostics of a VM.
+type BootDiagnostics struct {
+	// Enabled enables the boot diagnostics of the VM.
+	Enabled bool `json:"enabled"`
+
+	// StorageAccountURI is the blob endpoint of the storage account storing the boot diagnostics, e.g.
+	// https://mystorageaccount.blob.core.windows.net/. The storage account must be a Standard storage account of the
+	// subscription of the cluster. The boot diagnostics are stored in a managed storage account when it isn't set.
+	// +optional
+	StorageAccountURI string `json:"storageAccountURI,omitempty"`
+}
+
+// VMExtension defines a VM extension installed on a VM.
+type VMExtension struct {
+	// Name is the name of the extension on the VM.
+	Name string `json:"name"`
+
+	// Publisher is the publisher of the extension handler, e.g. Microsoft.Azure.Extensions.
+	Publisher string `json:"publisher"`
+
+	// Type is the type of the extension handler, e.g. CustomScript.
+	Type string `json:"type"`
+
+	// Version is the major and minor version of the extension handler, e.g. 2.1. Newer minor versions are installed
+	// when they are available.
+	Version string `json:"version"`
+
+	// Settings are the public settings of the extension, which are visible in the VM properties.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// VMExtensionStatus defines the observed provisioning state of a VM extension.
+type VMExtensionStatus struct {
+	// Name is the name of the extension on the VM.
+	Name string `json:"name"`
+
+	// ProvisioningState is the provisioning state of the extension, e.g. Succeeded or Failed.
+	// +optional
+	ProvisioningState string `json:"provisioningState,omitempty"`
+}
+
+// OSDiskStatus defines the observed size and storage account type of the OS disk of a VM.
+type OSDiskStatus struct {
+	// DiskSizeGB is the size of the OS disk in GB.
+	// +optional
+	DiskSizeGB int32 `json:"diskSizeGB,omitempty"`
+
+	// StorageAccountType is the storage account type of the OS disk.
+	// +optional
+	StorageAccountType string `json:"storageAccountType,omitempty"`
 }
 
 // AzureMachineStatus defines the observed state of AzureMachine
@@ -119,6 +231,19 @@ type AzureMachineStatus struct {
 	// +optional
 	VMState *VMState `json:"vmState,omitempty"`
 
+	// OSDisk is the effective OS disk of the Azure virtual machine, with the defaults applied at its creation.
+	// +optional
+	OSDisk *OSDiskStatus `json:"osDisk,omitempty"`
+
+	// Image is the effective image of the Azure virtual machine, either the image of the machine or the default image
+	// of the cluster, resolved at its creation.
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
+	// VMExtensions are the provisioning states of the VM extensions of the Azure virtual machine.
+	// +optional
+	VMExtensions []VMExtensionStatus `json:"vmExtensions,omitempty"`
+
 	// ErrorReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -17,27 +17,39 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
 	"golang.org/x/crypto/ssh"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-// ValidateSSHKey validates an SSHKey
+// ValidateSSHKey validates an SSHKey, which must be a single base64 encoded authorized_keys entry
 func ValidateSSHKey(sshKey string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	if sshKey == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "the SSH public key cannot be empty"))
+		return allErrs
+	}
+
 	decoded, err := base64.StdEncoding.DecodeString(sshKey)
 	if err != nil {
 		allErrs = append(allErrs, field.Invalid(fldPath, sshKey, "the SSH public key is not properly base64 encoded"))
 		return allErrs
 	}
 
-	if _, _, _, _, err := ssh.ParseAuthorizedKey(decoded); err != nil {
+	if _, _, _, rest, err := ssh.ParseAuthorizedKey(decoded); err != nil {
 		allErrs = append(allErrs, field.Invalid(fldPath, sshKey, "the SSH public key is not valid"))
 		return allErrs
+	} else if len(bytes.TrimSpace(rest)) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, sshKey, "the SSH public key must be a single authorized_keys entry"))
+		return allErrs
 	}
 
 	return allErrs
@@ -84,6 +96,187 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 		} else {
 			lunSet[*disk.Lun] = struct{}{}
 		}
+
+		if disk.ManagedDisk != nil {
+			allErrs = append(allErrs, validateStorageAccountType(disk.ManagedDisk.StorageAccountType, fieldPath)...)
+		}
+
+		if disk.CachingType != "" {
+			allErrs = append(allErrs, validateCachingType(disk.CachingType, fieldPath)...)
+		}
+	}
+	return allErrs
+}
+
+func validateCachingType(cachingType string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, possibleCachingType := range compute.PossibleCachingTypesValues() {
+		if string(possibleCachingType) == cachingType {
+			return allErrs
+		}
+	}
+	allErrs = append(allErrs, field.Invalid(fieldPath.Child("CachingType"), cachingType, fmt.Sprintf("allowed values are %v", compute.PossibleCachingTypesValues())))
+	return allErrs
+}
+
+// ValidateSpotVMOptions validates the Spot VM options. The max price is either -1, to pay up to the on-demand
+// price, or a positive price in US dollars with at most 5 decimal places.
+func ValidateSpotVMOptions(spotVMOptions *SpotVMOptions, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spotVMOptions == nil || spotVMOptions.MaxPrice == nil {
+		return allErrs
+	}
+
+	maxPrice := *spotVMOptions.MaxPrice
+	price, err := strconv.ParseFloat(maxPrice, 64)
+	if err != nil || strings.ContainsAny(maxPrice, "eE") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxPrice"), maxPrice, "the max price must be a decimal number"))
+		return allErrs
+	}
+	if price != -1 && price <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxPrice"), maxPrice, "the max price must be -1 or greater than 0"))
+	}
+	if i := strings.Index(maxPrice, "."); i >= 0 && len(maxPrice)-i-1 > 5 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxPrice"), maxPrice, "the max price can have at most 5 decimal places"))
+	}
+	return allErrs
+}
+
+var hostGroupIDRegexp = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/hostGroups/[^/]+$`)
+
+// ValidateDedicatedHost validates the dedicated host of a VM. The host group ID must be the resource ID of a host group,
+// and the host ID the resource ID of a host of that group.
+func ValidateDedicatedHost(dedicatedHost *DedicatedHost, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if dedicatedHost == nil {
+		return allErrs
+	}
+
+	if !hostGroupIDRegexp.MatchString(dedicatedHost.HostGroupID) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("hostGroupID"), dedicatedHost.HostGroupID, "the host group ID must be the resource ID of a Microsoft.Compute/hostGroups resource"))
+		return allErrs
+	}
+	if dedicatedHost.HostID == "" {
+		return allErrs
+	}
+	prefix := dedicatedHost.HostGroupID + "/hosts/"
+	if len(dedicatedHost.HostID) <= len(prefix) || !strings.EqualFold(dedicatedHost.HostID[:len(prefix)], prefix) || strings.Contains(dedicatedHost.HostID[len(prefix):], "/") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("hostID"), dedicatedHost.HostID, "the host ID must be the resource ID of a host of the host group"))
+	}
+	return allErrs
+}
+
+var diskEncryptionSetIDRegexp = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/diskEncryptionSets/[^/]+$`)
+
+// ValidateDiskEncryptionSetID validates the disk encryption set ID of the disks of a VM.
+func ValidateDiskEncryptionSetID(diskEncryptionSetID string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if diskEncryptionSetID != "" && !diskEncryptionSetIDRegexp.MatchString(diskEncryptionSetID) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, diskEncryptionSetID, "the disk encryption set ID must be the resource ID of a Microsoft.Compute/diskEncryptionSets resource"))
+	}
+	return allErrs
+}
+
+var storageAccountURIRegexp = regexp.MustCompile(`^https://[a-z0-9]{3,24}\.blob\.[^/]+/?$`)
+
+// ValidateBootDiagnostics validates the boot diagnostics of a VM. The storage account URI must be the blob endpoint
+// of a storage account.
+func ValidateBootDiagnostics(bootDiagnostics *BootDiagnostics, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if bootDiagnostics == nil || bootDiagnostics.StorageAccountURI == "" {
+		return allErrs
+	}
+
+	if !bootDiagnostics.Enabled {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("storageAccountURI"), "the storage account URI can only be set when the boot diagnostics are enabled"))
+	}
+	if !storageAccountURIRegexp.MatchString(bootDiagnostics.StorageAccountURI) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("storageAccountURI"), bootDiagnostics.StorageAccountURI,
+			"the storage account URI must be the blob endpoint of a storage account, e.g. https://mystorageaccount.blob.core.windows.net/"))
+	}
+	return allErrs
+}
+
+// ValidateVMExtensions validates the VM extensions of a VM. Each extension must have a unique name, a publisher, a
+// type and a version.
+func ValidateVMExtensions(extensions []VMExtension, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := make(map[string]struct{}, len(extensions))
+	for i, extension := range extensions {
+		extensionPath := fieldPath.Index(i)
+		if extension.Name == "" {
+			allErrs = append(allErrs, field.Required(extensionPath.Child("name"), "the name of the extension cannot be empty"))
+		} else if _, ok := names[extension.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(extensionPath.Child("name"), extension.Name))
+		}
+		names[extension.Name] = struct{}{}
+		if extension.Publisher == "" {
+			allErrs = append(allErrs, field.Required(extensionPath.Child("publisher"), "the publisher of the extension cannot be empty"))
+		}
+		if extension.Type == "" {
+			allErrs = append(allErrs, field.Required(extensionPath.Child("type"), "the type of the extension cannot be empty"))
+		}
+		if extension.Version == "" {
+			allErrs = append(allErrs, field.Required(extensionPath.Child("version"), "the version of the extension cannot be empty"))
+		}
+	}
+	return allErrs
+}
+
+const (
+	// linuxAdminUsernameMaxLength and windowsAdminUsernameMaxLength are the maximum lengths of the admin username of
+	// Linux and Windows VMs.
+	linuxAdminUsernameMaxLength   = 64
+	windowsAdminUsernameMaxLength = 20
+)
+
+// adminUsernameRegexp matches the admin usernames Azure accepts: letters, digits, hyphens and underscores, not
+// starting with a hyphen or a digit.
+var adminUsernameRegexp = regexp.MustCompile(`^[a-zA-Z_][-a-zA-Z0-9_]*$`)
+
+// disallowedAdminUsernames are the admin usernames Azure rejects, described in
+// https://docs.microsoft.com/en-us/rest/api/compute/virtualmachines/createorupdate#osprofile
+var disallowedAdminUsernames = map[string]bool{
+	"1": true, "123": true, "a": true, "actuser": true, "adm": true, "admin": true, "admin1": true, "admin2": true,
+	"administrator": true, "aspnet": true, "backup": true, "console": true, "david": true, "guest": true, "john": true,
+	"owner": true, "root": true, "server": true, "sql": true, "support": true, "support_388945a0": true, "sys": true,
+	"test": true, "test1": true, "test2": true, "test3": true, "user": true, "user1": true, "user2": true,
+	"user3": true, "user4": true, "user5": true,
+}
+
+// ValidateAdminUsername validates the admin username of a VM. An empty username defaults to capi. Azure rejects a
+// list of common usernames, and usernames longer than 64 characters for Linux VMs or 20 for Windows VMs.
+func ValidateAdminUsername(username string, osType string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if username == "" {
+		return allErrs
+	}
+	if disallowedAdminUsernames[strings.ToLower(username)] {
+		allErrs = append(allErrs, field.Invalid(fieldPath, username, "the admin username is disallowed by Azure"))
+	}
+	if !adminUsernameRegexp.MatchString(username) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, username,
+			"the admin username must only contain letters, digits, hyphens and underscores, and cannot start with a hyphen or a digit"))
+	}
+	maxLength := linuxAdminUsernameMaxLength
+	if osType == WindowsOS {
+		maxLength = windowsAdminUsernameMaxLength
+	}
+	if len(username) > maxLength {
+		allErrs = append(allErrs, field.TooLong(fieldPath, username, maxLength))
+	}
+	return allErrs
+}
+
+// windowsComputerNameMaxLength is the maximum length of the computer name of a Windows VM.
+const windowsComputerNameMaxLength = 15
+
+// ValidateComputerName validates the name of a machine, which is the computer name of its VM. The computer name of
+// a Windows VM can't be longer than 15 characters.
+func ValidateComputerName(name string, osType string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if osType == WindowsOS && len(name) > windowsComputerNameMaxLength {
+		allErrs = append(allErrs, field.TooLong(fieldPath, name, windowsComputerNameMaxLength))
 	}
 	return allErrs
 }
@@ -92,16 +285,50 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if osDisk.DiskSizeGB <= 0 || osDisk.DiskSizeGB > 2048 {
+	// an empty disk size defaults to the size of the OS disk of the image
+	if osDisk.DiskSizeGB < 0 || osDisk.DiskSizeGB > 2048 {
 		allErrs = append(allErrs, field.Invalid(fieldPath.Child("DiskSizeGB"), "", "the Disk size should be a value between 1 and 2048"))
 	}
+	if osDisk.DiskSizeGB == 0 && osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("DiskSizeGB"), "the disk size of an ephemeral OS disk cannot be empty"))
+	}
 
 	if osDisk.OSType == "" {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("OSType"), "the OS type cannot be empty"))
+	} else if osDisk.OSType != LinuxOS && osDisk.OSType != WindowsOS {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("OSType"), osDisk.OSType, []string{LinuxOS, WindowsOS}))
+	}
+
+	// an empty storage account type is defaulted from the VM size
+	if osDisk.ManagedDisk.StorageAccountType != "" {
+		allErrs = append(allErrs, validateStorageAccountType(osDisk.ManagedDisk.StorageAccountType, fieldPath)...)
+		if osDisk.ManagedDisk.StorageAccountType == string(compute.UltraSSDLRS) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("ManagedDisk").Child("StorageAccountType"), osDisk.ManagedDisk.StorageAccountType, "UltraSSD_LRS can only be used for data disks"))
+		}
+	}
+
+	if osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, validateDiffDiskSettings(*osDisk.DiffDiskSettings, fieldPath.Child("DiffDiskSettings"))...)
 	}
 
-	allErrs = append(allErrs, validateStorageAccountType(osDisk.ManagedDisk.StorageAccountType, fieldPath)...)
+	return allErrs
+}
+
+func validateDiffDiskSettings(diffDiskSettings DiffDiskSettings, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if diffDiskSettings.Option != string(compute.Local) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("Option"), diffDiskSettings.Option, fmt.Sprintf("allowed values are %v", compute.PossibleDiffDiskOptionsValues())))
+	}
 
+	if diffDiskSettings.Placement != "" {
+		for _, possiblePlacement := range compute.PossibleDiffDiskPlacementValues() {
+			if string(possiblePlacement) == diffDiskSettings.Placement {
+				return allErrs
+			}
+		}
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("Placement"), diffDiskSettings.Placement, fmt.Sprintf("allowed values are %v", compute.PossibleDiffDiskPlacementValues())))
+	}
 	return allErrs
 }
 
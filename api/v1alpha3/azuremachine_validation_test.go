@@ -21,6 +21,7 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"github.com/Azure/go-autorest/autorest/to"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -46,6 +47,16 @@ func TestAzureMachine_ValidateSSHKey(t *testing.T) {
 			sshKey:  "invalid ssh key",
 			wantErr: true,
 		},
+		{
+			name:    "empty ssh key",
+			sshKey:  "",
+			wantErr: true,
+		},
+		{
+			name:    "several ssh keys",
+			sshKey:  base64.StdEncoding.EncodeToString(append(decodeSSHPublicKey(generateSSHPublicKey()), decodeSSHPublicKey(generateSSHPublicKey())...)),
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -66,6 +77,11 @@ func generateSSHPublicKey() string {
 	return base64.StdEncoding.EncodeToString(ssh.MarshalAuthorizedKey(publicRsaKey))
 }
 
+func decodeSSHPublicKey(sshKey string) []byte {
+	decoded, _ := base64.StdEncoding.DecodeString(sshKey)
+	return decoded
+}
+
 type osDiskTestInput struct {
 	name    string
 	wantErr bool
@@ -81,6 +97,42 @@ func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 			wantErr: false,
 			osDisk:  generateValidOSDisk(),
 		},
+		{
+			name:    "valid ephemeral os disk spec",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB: 30,
+				OSType:     "Linux",
+				ManagedDisk: ManagedDisk{
+					StorageAccountType: "Standard_LRS",
+				},
+				DiffDiskSettings: &DiffDiskSettings{
+					Option:    "Local",
+					Placement: "ResourceDisk",
+				},
+			},
+		},
+		{
+			name:    "valid os disk spec with the default size and storage account type",
+			wantErr: false,
+			osDisk: OSDisk{
+				OSType: "Linux",
+			},
+		},
+		{
+			name:    "valid Windows os disk spec",
+			wantErr: false,
+			osDisk: OSDisk{
+				OSType: "Windows",
+			},
+		},
+		{
+			name:    "unsupported os type",
+			wantErr: true,
+			osDisk: OSDisk{
+				OSType: "MacOS",
+			},
+		},
 	}
 	testcases = append(testcases, generateNegativeTestCases()...)
 
@@ -102,10 +154,6 @@ func generateNegativeTestCases() []osDiskTestInput {
 
 	invalidDiskSpecs := []OSDisk{
 		{},
-		{
-			DiskSizeGB: 0,
-			OSType:     "blah",
-		},
 		{
 			DiskSizeGB: -10,
 			OSType:     "blah",
@@ -118,16 +166,20 @@ func generateNegativeTestCases() []osDiskTestInput {
 			DiskSizeGB: 20,
 			OSType:     "",
 		},
-		{
-			DiskSizeGB:  30,
-			OSType:      "blah",
-			ManagedDisk: ManagedDisk{},
-		},
 		{
 			DiskSizeGB: 30,
 			OSType:     "blah",
 			ManagedDisk: ManagedDisk{
-				StorageAccountType: "",
+				StorageAccountType: "UltraSSD_LRS",
+			},
+		},
+		{
+			OSType: "Linux",
+			ManagedDisk: ManagedDisk{
+				StorageAccountType: "Standard_LRS",
+			},
+			DiffDiskSettings: &DiffDiskSettings{
+				Option: "Local",
 			},
 		},
 		{
@@ -137,6 +189,27 @@ func generateNegativeTestCases() []osDiskTestInput {
 				StorageAccountType: "invalid_type",
 			},
 		},
+		{
+			DiskSizeGB: 30,
+			OSType:     "Linux",
+			ManagedDisk: ManagedDisk{
+				StorageAccountType: "Standard_LRS",
+			},
+			DiffDiskSettings: &DiffDiskSettings{
+				Option: "Remote",
+			},
+		},
+		{
+			DiskSizeGB: 30,
+			OSType:     "Linux",
+			ManagedDisk: ManagedDisk{
+				StorageAccountType: "Standard_LRS",
+			},
+			DiffDiskSettings: &DiffDiskSettings{
+				Option:    "Local",
+				Placement: "DataDisk",
+			},
+		},
 	}
 
 	for _, input := range invalidDiskSpecs {
@@ -248,6 +321,43 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid storage account type and caching",
+			disks: []DataDisk{
+				{
+					NameSuffix:  "my_disk",
+					DiskSizeGB:  64,
+					Lun:         to.Int32Ptr(0),
+					ManagedDisk: &ManagedDisk{StorageAccountType: "Premium_LRS"},
+					CachingType: "ReadWrite",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid storage account type",
+			disks: []DataDisk{
+				{
+					NameSuffix:  "my_disk",
+					DiskSizeGB:  64,
+					Lun:         to.Int32Ptr(0),
+					ManagedDisk: &ManagedDisk{StorageAccountType: "Fast_LRS"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid caching type",
+			disks: []DataDisk{
+				{
+					NameSuffix:  "my_disk",
+					DiskSizeGB:  64,
+					Lun:         to.Int32Ptr(0),
+					CachingType: "WriteOnly",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range testcases {
@@ -261,3 +371,400 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureMachine_ValidateSpotVMOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name          string
+		spotVMOptions *SpotVMOptions
+		wantErr       bool
+	}{
+		{
+			name:          "regular VM",
+			spotVMOptions: nil,
+			wantErr:       false,
+		},
+		{
+			name:          "no max price",
+			spotVMOptions: &SpotVMOptions{},
+			wantErr:       false,
+		},
+		{
+			name:          "valid max price",
+			spotVMOptions: &SpotVMOptions{MaxPrice: to.StringPtr("0.04325")},
+			wantErr:       false,
+		},
+		{
+			name:          "on-demand max price",
+			spotVMOptions: &SpotVMOptions{MaxPrice: to.StringPtr("-1")},
+			wantErr:       false,
+		},
+		{
+			name:          "max price is not a number",
+			spotVMOptions: &SpotVMOptions{MaxPrice: to.StringPtr("cheap")},
+			wantErr:       true,
+		},
+		{
+			name:          "max price in scientific notation",
+			spotVMOptions: &SpotVMOptions{MaxPrice: to.StringPtr("4e-2")},
+			wantErr:       true,
+		},
+		{
+			name:          "zero max price",
+			spotVMOptions: &SpotVMOptions{MaxPrice: to.StringPtr("0")},
+			wantErr:       true,
+		},
+		{
+			name:          "max price with too many decimal places",
+			spotVMOptions: &SpotVMOptions{MaxPrice: to.StringPtr("0.000001")},
+			wantErr:       true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateSpotVMOptions(test.spotVMOptions, field.NewPath("spotVMOptions"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDedicatedHost(t *testing.T) {
+	g := NewWithT(t)
+
+	hostGroupID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group"
+	testcases := []struct {
+		name          string
+		dedicatedHost *DedicatedHost
+		wantErr       bool
+	}{
+		{
+			name:          "no dedicated host",
+			dedicatedHost: nil,
+			wantErr:       false,
+		},
+		{
+			name:          "host group with automatic placement",
+			dedicatedHost: &DedicatedHost{HostGroupID: hostGroupID},
+			wantErr:       false,
+		},
+		{
+			name:          "host of the host group",
+			dedicatedHost: &DedicatedHost{HostGroupID: hostGroupID, HostID: hostGroupID + "/hosts/my-host"},
+			wantErr:       false,
+		},
+		{
+			name:          "host group ID is not a resource ID",
+			dedicatedHost: &DedicatedHost{HostGroupID: "my-host-group"},
+			wantErr:       true,
+		},
+		{
+			name:          "host group ID of another resource type",
+			dedicatedHost: &DedicatedHost{HostGroupID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/availabilitySets/my-set"},
+			wantErr:       true,
+		},
+		{
+			name:          "host of another host group",
+			dedicatedHost: &DedicatedHost{HostGroupID: hostGroupID, HostID: hostGroupID + "-2/hosts/my-host"},
+			wantErr:       true,
+		},
+		{
+			name:          "host ID without a host name",
+			dedicatedHost: &DedicatedHost{HostGroupID: hostGroupID, HostID: hostGroupID + "/hosts/"},
+			wantErr:       true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDedicatedHost(test.dedicatedHost, field.NewPath("dedicatedHost"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDiskEncryptionSetID(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name                string
+		diskEncryptionSetID string
+		wantErr             bool
+	}{
+		{
+			name:                "platform-managed keys",
+			diskEncryptionSetID: "",
+			wantErr:             false,
+		},
+		{
+			name:                "disk encryption set",
+			diskEncryptionSetID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des",
+			wantErr:             false,
+		},
+		{
+			name:                "disk encryption set ID is not a resource ID",
+			diskEncryptionSetID: "my-des",
+			wantErr:             true,
+		},
+		{
+			name:                "key vault ID",
+			diskEncryptionSetID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+			wantErr:             true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDiskEncryptionSetID(test.diskEncryptionSetID, field.NewPath("diskEncryptionSetID"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateBootDiagnostics(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name            string
+		bootDiagnostics *BootDiagnostics
+		wantErr         bool
+	}{
+		{
+			name:            "no boot diagnostics",
+			bootDiagnostics: nil,
+			wantErr:         false,
+		},
+		{
+			name:            "managed storage account",
+			bootDiagnostics: &BootDiagnostics{Enabled: true},
+			wantErr:         false,
+		},
+		{
+			name:            "user storage account",
+			bootDiagnostics: &BootDiagnostics{Enabled: true, StorageAccountURI: "https://mystorageaccount.blob.core.windows.net/"},
+			wantErr:         false,
+		},
+		{
+			name:            "user storage account of another cloud",
+			bootDiagnostics: &BootDiagnostics{Enabled: true, StorageAccountURI: "https://mystorageaccount.blob.core.chinacloudapi.cn"},
+			wantErr:         false,
+		},
+		{
+			name:            "user storage account with disabled boot diagnostics",
+			bootDiagnostics: &BootDiagnostics{Enabled: false, StorageAccountURI: "https://mystorageaccount.blob.core.windows.net/"},
+			wantErr:         true,
+		},
+		{
+			name:            "file endpoint",
+			bootDiagnostics: &BootDiagnostics{Enabled: true, StorageAccountURI: "https://mystorageaccount.file.core.windows.net/"},
+			wantErr:         true,
+		},
+		{
+			name:            "container URI",
+			bootDiagnostics: &BootDiagnostics{Enabled: true, StorageAccountURI: "https://mystorageaccount.blob.core.windows.net/bootdiagnostics"},
+			wantErr:         true,
+		},
+		{
+			name:            "http endpoint",
+			bootDiagnostics: &BootDiagnostics{Enabled: true, StorageAccountURI: "http://mystorageaccount.blob.core.windows.net/"},
+			wantErr:         true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateBootDiagnostics(test.bootDiagnostics, field.NewPath("bootDiagnostics"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateComputerName(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name        string
+		machineName string
+		osType      string
+		wantErr     bool
+	}{
+		{
+			name:        "long Linux machine name",
+			machineName: "my-cluster-md-0-6bc7c9f4f9-x8x2w",
+			osType:      LinuxOS,
+			wantErr:     false,
+		},
+		{
+			name:        "short Windows machine name",
+			machineName: "my-win-md-x8x2w",
+			osType:      WindowsOS,
+			wantErr:     false,
+		},
+		{
+			name:        "long Windows machine name",
+			machineName: "my-cluster-md-0-6bc7c9f4f9-x8x2w",
+			osType:      WindowsOS,
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateComputerName(test.machineName, test.osType, field.NewPath("metadata", "name"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateAdminUsername(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name     string
+		username string
+		osType   string
+		wantErr  bool
+	}{
+		{
+			name:     "empty username",
+			username: "",
+			osType:   LinuxOS,
+			wantErr:  false,
+		},
+		{
+			name:     "valid username",
+			username: "k8s_admin-1",
+			osType:   LinuxOS,
+			wantErr:  false,
+		},
+		{
+			name:     "username disallowed by Azure",
+			username: "Administrator",
+			osType:   WindowsOS,
+			wantErr:  true,
+		},
+		{
+			name:     "username starting with a digit",
+			username: "1admin",
+			osType:   LinuxOS,
+			wantErr:  true,
+		},
+		{
+			name:     "username with a period",
+			username: "k8s.admin",
+			osType:   LinuxOS,
+			wantErr:  true,
+		},
+		{
+			name:     "long Linux username",
+			username: "kubernetes-cluster-administrator",
+			osType:   LinuxOS,
+			wantErr:  false,
+		},
+		{
+			name:     "long Windows username",
+			username: "kubernetes-cluster-administrator",
+			osType:   WindowsOS,
+			wantErr:  true,
+		},
+		{
+			name:     "too long Linux username",
+			username: strings.Repeat("a", 65),
+			osType:   LinuxOS,
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAdminUsername(test.username, test.osType, field.NewPath("adminUsername"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateVMExtensions(t *testing.T) {
+	g := NewWithT(t)
+
+	customScript := VMExtension{
+		Name:      "custom-script",
+		Publisher: "Microsoft.Azure.Extensions",
+		Type:      "CustomScript",
+		Version:   "2.1",
+		Settings:  map[string]string{"commandToExecute": "echo hello"},
+	}
+	withName := func(name string) VMExtension {
+		extension := customScript
+		extension.Name = name
+		return extension
+	}
+
+	testcases := []struct {
+		name       string
+		extensions []VMExtension
+		wantErr    bool
+	}{
+		{
+			name:       "no extensions",
+			extensions: nil,
+			wantErr:    false,
+		},
+		{
+			name:       "extensions with unique names",
+			extensions: []VMExtension{customScript, withName("other-script")},
+			wantErr:    false,
+		},
+		{
+			name:       "extensions with the same name",
+			extensions: []VMExtension{customScript, customScript},
+			wantErr:    true,
+		},
+		{
+			name:       "extension without a name",
+			extensions: []VMExtension{withName("")},
+			wantErr:    true,
+		},
+		{
+			name:       "extension without a publisher, type and version",
+			extensions: []VMExtension{{Name: "custom-script"}},
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateVMExtensions(test.extensions, field.NewPath("vmExtensions"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
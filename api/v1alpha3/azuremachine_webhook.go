@@ -52,10 +52,18 @@ func (m *AzureMachine) ValidateCreate() error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateComputerName(m.Name, m.Spec.OSDisk.OSType, field.NewPath("metadata", "name")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateSSHKey(m.Spec.SSHPublicKey, field.NewPath("sshPublicKey")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateAdminUsername(m.Spec.AdminUsername, m.Spec.OSDisk.OSType, field.NewPath("adminUsername")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateUserAssignedIdentity(m.Spec.Identity, m.Spec.UserAssignedIdentities, field.NewPath("userAssignedIdentities")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -64,6 +72,26 @@ func (m *AzureMachine) ValidateCreate() error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateSpotVMOptions(m.Spec.SpotVMOptions, field.NewPath("spotVMOptions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDedicatedHost(m.Spec.DedicatedHost, field.NewPath("dedicatedHost")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDiskEncryptionSetID(m.Spec.DiskEncryptionSetID, field.NewPath("diskEncryptionSetID")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBootDiagnostics(m.Spec.BootDiagnostics, field.NewPath("bootDiagnostics")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateVMExtensions(m.Spec.VMExtensions, field.NewPath("vmExtensions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -79,6 +107,10 @@ func (m *AzureMachine) ValidateUpdate(old runtime.Object) error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateAdminUsername(m.Spec.AdminUsername, m.Spec.OSDisk.OSType, field.NewPath("adminUsername")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateUserAssignedIdentity(m.Spec.Identity, m.Spec.UserAssignedIdentities, field.NewPath("userAssignedIdentities")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -87,6 +119,26 @@ func (m *AzureMachine) ValidateUpdate(old runtime.Object) error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateSpotVMOptions(m.Spec.SpotVMOptions, field.NewPath("spotVMOptions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDedicatedHost(m.Spec.DedicatedHost, field.NewPath("dedicatedHost")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDiskEncryptionSetID(m.Spec.DiskEncryptionSetID, field.NewPath("diskEncryptionSetID")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBootDiagnostics(m.Spec.BootDiagnostics, field.NewPath("bootDiagnostics")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateVMExtensions(m.Spec.VMExtensions, field.NewPath("vmExtensions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -109,4 +161,5 @@ func (m *AzureMachine) Default() {
 		machinelog.Error(err, "SetDefaultSshPublicKey failed")
 	}
 	m.SetDataDisksDefaults()
+	m.SetDefaultOSType()
 }
@@ -26,6 +26,38 @@ const (
 	LoadBalancerProvisioningReason = "LoadBalancerProvisioning"
 	// LoadBalancerProvisioningFailedReason used for failure during provisioning of loadbalancer.
 	LoadBalancerProvisioningFailedReason = "LoadBalancerProvisioningFailed"
+
+	// VNetReadyCondition reports on the reconciliation of the cluster virtual network.
+	VNetReadyCondition clusterv1.ConditionType = "VNetReady"
+	// VNetReconcileFailedReason used when the virtual network couldn't be reconciled.
+	VNetReconcileFailedReason = "VNetReconcileFailed"
+	// SubnetsReadyCondition reports on the reconciliation of the cluster subnets.
+	SubnetsReadyCondition clusterv1.ConditionType = "SubnetsReady"
+	// SubnetsReconcileFailedReason used when a subnet couldn't be reconciled.
+	SubnetsReconcileFailedReason = "SubnetsReconcileFailed"
+	// SecurityGroupsReadyCondition reports on the reconciliation of the cluster network security groups.
+	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
+	// SecurityGroupsReconcileFailedReason used when a network security group couldn't be reconciled.
+	SecurityGroupsReconcileFailedReason = "SecurityGroupsReconcileFailed"
+	// LoadBalancersReadyCondition reports on the reconciliation of the cluster load balancers.
+	LoadBalancersReadyCondition clusterv1.ConditionType = "LoadBalancersReady"
+	// LoadBalancersReconcileFailedReason used when a load balancer couldn't be reconciled.
+	LoadBalancersReconcileFailedReason = "LoadBalancersReconcileFailed"
+	// PublicIPsReadyCondition reports on the reconciliation of the cluster public IPs.
+	PublicIPsReadyCondition clusterv1.ConditionType = "PublicIPsReady"
+	// PublicIPsReconcileFailedReason used when a public IP couldn't be reconciled.
+	PublicIPsReconcileFailedReason = "PublicIPsReconcileFailed"
+	// VCPUQuotaAvailableCondition reports whether the vCPU quota of the subscription in the cluster location
+	// is enough for the machines of the cluster which are not provisioned yet.
+	VCPUQuotaAvailableCondition clusterv1.ConditionType = "VCPUQuotaAvailable"
+	// InsufficientVCPUQuotaReason used when the machines to provision need more vCPUs than the available quota.
+	InsufficientVCPUQuotaReason = "InsufficientVCPUQuota"
+	// OperationInProgressReason used while a long-running operation on an Azure resource of the cluster is in progress.
+	OperationInProgressReason = "OperationInProgress"
+	// DryRunCondition reports whether the dry run of the cluster planned no changes to its Azure resources.
+	DryRunCondition clusterv1.ConditionType = "DryRun"
+	// ChangesPlannedReason used when the dry run of the cluster planned changes to its Azure resources.
+	ChangesPlannedReason = "ChangesPlanned"
 )
 
 // AzureMachine Conditions and Reasons
@@ -42,10 +74,16 @@ const (
 	VMDDeletingReason = "VMDeleting"
 	// VMStoppedReason vm is in a stopped state.
 	VMStoppedReason = "VMStopped"
+	// VMEvictedReason used when a Spot VM was evicted.
+	VMEvictedReason = "VMEvicted"
 	// VMProvisionFailedReason used for failures during vm provisioning.
 	VMProvisionFailedReason = "VMProvisionFailed"
 	// WaitingForClusterInfrastructureReason used when machine is waiting for cluster infrastructure to be ready before proceeding.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// VMExtensionsReadyCondition reports on the provisioning of the VM extensions of the Azure VM.
+	VMExtensionsReadyCondition clusterv1.ConditionType = "VMExtensionsReady"
+	// VMExtensionProvisioningFailedReason used when a VM extension failed to be installed on the VM.
+	VMExtensionProvisioningFailedReason = "VMExtensionProvisioningFailed"
 )
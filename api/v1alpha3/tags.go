@@ -104,6 +104,11 @@ const (
 	// dedicated to this cluster api provider implementation.
 	NameAzureClusterAPIRole = NameAzureProviderPrefix + "role"
 
+	// UserManagedTagKey is the tag name we use on a load balancer to list, separated by commas,
+	// the names of its frontends, backend pools, probes and rules added outside of the provider,
+	// which are kept as they are when the load balancer is reconciled.
+	UserManagedTagKey = NameAzureProviderPrefix + "user-managed"
+
 	// APIServerRole describes the value for the apiserver role
 	APIServerRole = "apiserver"
 
@@ -113,6 +118,9 @@ const (
 	// NodeOutboundRole describes the value for the node outbound LB role
 	NodeOutboundRole = "nodeOutbound"
 
+	// ControlPlaneOutboundRole describes the value for the control plane outbound LB role
+	ControlPlaneOutboundRole = "controlPlaneOutbound"
+
 	// BastionRole describes the value for the bastion role
 	BastionRole = "bastion"
 
@@ -34,6 +34,55 @@ type Network struct {
 
 	// APIServerIP is the Kubernetes API server public IP address.
 	APIServerIP PublicIP `json:"apiServerIp,omitempty"`
+
+	// APIServerIPv6 is the Kubernetes API server public IPv6 address of dual-stack clusters.
+	// +optional
+	APIServerIPv6 PublicIP `json:"apiServerIpv6,omitempty"`
+
+	// InternalLBIPAddress is the private IP address assigned to the frontend of the internal API server load balancer.
+	// +optional
+	InternalLBIPAddress string `json:"internalLBIPAddress,omitempty"`
+
+	// InternalLBZones are the availability zones of the frontend of the internal API server load balancer.
+	// +optional
+	InternalLBZones []string `json:"internalLBZones,omitempty"`
+
+	// NodeOutboundIPs are the public IP addresses assigned to the frontends of the node outbound load balancer.
+	// +optional
+	NodeOutboundIPs []string `json:"nodeOutboundIPs,omitempty"`
+
+	// PrivateLinkServiceAlias is the alias of the private link service of the internal API server load balancer,
+	// which its consumers create their private endpoints with.
+	// +optional
+	PrivateLinkServiceAlias string `json:"privateLinkServiceAlias,omitempty"`
+
+	// ResourceIDs are the Azure resource IDs of the networking resources of the cluster.
+	// +optional
+	ResourceIDs NetworkResourceIDs `json:"resourceIDs,omitempty"`
+}
+
+// NetworkResourceIDs are the Azure resource IDs of the networking resources of a cluster, as reported by Azure.
+// The subnets, security groups, load balancers and public IPs are keyed by their name.
+type NetworkResourceIDs struct {
+	// Vnet is the resource ID of the virtual network.
+	// +optional
+	Vnet string `json:"vnet,omitempty"`
+
+	// Subnets are the resource IDs of the subnets.
+	// +optional
+	Subnets map[string]string `json:"subnets,omitempty"`
+
+	// SecurityGroups are the resource IDs of the network security groups.
+	// +optional
+	SecurityGroups map[string]string `json:"securityGroups,omitempty"`
+
+	// LoadBalancers are the resource IDs of the load balancers.
+	// +optional
+	LoadBalancers map[string]string `json:"loadBalancers,omitempty"`
+
+	// PublicIPs are the resource IDs of the public IPs.
+	// +optional
+	PublicIPs map[string]string `json:"publicIPs,omitempty"`
 }
 
 // NetworkSpec specifies what the Azure networking resources should look like.
@@ -45,6 +94,277 @@ type NetworkSpec struct {
 	// Subnets is the configuration for the control-plane subnet and the node subnet.
 	// +optional
 	Subnets Subnets `json:"subnets,omitempty"`
+
+	// APIServerLB is the configuration for the control-plane load balancer.
+	// +optional
+	APIServerLB LoadBalancerSpec `json:"apiServerLB,omitempty"`
+
+	// LoadBalancerSKU is the SKU of the load balancers and public IPs created for the cluster.
+	// The Basic SKU does not support availability zones.
+	// +kubebuilder:validation:Enum=Basic;Standard
+	// +optional
+	LoadBalancerSKU SKU `json:"loadBalancerSku,omitempty"`
+
+	// NodeOutboundLB is the configuration for the outbound rule of the node outbound load balancer.
+	// +optional
+	NodeOutboundLB *NodeOutboundLBSpec `json:"nodeOutboundLB,omitempty"`
+
+	// NodeOutboundLBDisabled skips the node outbound load balancer and its public IPs, for clusters whose nodes
+	// egress through another path, e.g. a firewall reached through a route table of the node subnets. Every node
+	// subnet must then have a NAT gateway, a pre-existing subnet or route table, or a default route to a virtual
+	// appliance or a virtual network gateway.
+	// +optional
+	NodeOutboundLBDisabled bool `json:"nodeOutboundLBDisabled,omitempty"`
+
+	// ControlPlaneOutboundLB is the configuration of a dedicated load balancer for the outbound connections of the
+	// control plane machines of a cluster with an internal API server load balancer. Without it, the control plane
+	// machines of such a cluster have no explicit outbound connectivity, and rely on a route table of the control
+	// plane subnet to reach the internet. It is not supported with a public API server load balancer, whose outbound
+	// rule already gives the control plane machines outbound connectivity.
+	// +optional
+	ControlPlaneOutboundLB *ControlPlaneOutboundLBSpec `json:"controlPlaneOutboundLB,omitempty"`
+
+	// PrivateDNSZoneName is the name of the private DNS zone resolving the API server of a cluster
+	// with an internal API server load balancer. Defaults to <cluster name>.capz.io.
+	// A zone that already exists in the cluster resource group is reused and left in place when the cluster is deleted.
+	// +optional
+	PrivateDNSZoneName string `json:"privateDNSZoneName,omitempty"`
+
+	// AcceleratedNetworking is the default of the machines of the cluster that do not enable or disable
+	// Azure accelerated networking themselves. If omitted, it is set based on whether the VM size of each machine supports it.
+	// +optional
+	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+
+	// VnetPeerings are the virtual networks the cluster vnet is peered with, e.g. the hub of a hub-and-spoke topology.
+	// +optional
+	VnetPeerings []VnetPeeringSpec `json:"vnetPeerings,omitempty"`
+
+	// PrivateEndpoints are the private endpoints created in the subnets of the cluster vnet, giving its machines
+	// private access to Azure resources such as key vaults or container registries.
+	// +optional
+	PrivateEndpoints []PrivateEndpointSpec `json:"privateEndpoints,omitempty"`
+
+	// Bastion is the configuration of an Azure Bastion host giving SSH access to the machines of the cluster.
+	// If omitted, no bastion host is created.
+	// +optional
+	Bastion *BastionSpec `json:"bastion,omitempty"`
+
+	// ApplicationGateway is the configuration of an Azure Application Gateway in front of the API server, e.g. for
+	// its web application firewall. If omitted, no application gateway is created.
+	// +optional
+	ApplicationGateway *ApplicationGatewaySpec `json:"applicationGateway,omitempty"`
+
+	// AllowedAPIServerCIDRs restricts the sources of the default rule of the control plane security group allowing the
+	// API server port, which otherwise allows any source. The public IPs of the cluster, through which its machines
+	// reach a public API server, are always allowed.
+	// +optional
+	AllowedAPIServerCIDRs []string `json:"allowedAPIServerCIDRs,omitempty"`
+
+	// SSHDisabled removes the default rule of the control plane security group allowing SSH from any source.
+	// SSH from the cluster vnet, e.g. through a bastion host, is still allowed by the default rules of Azure.
+	// +optional
+	SSHDisabled bool `json:"sshDisabled,omitempty"`
+}
+
+// BastionSpec configures an Azure Bastion host in the cluster vnet.
+type BastionSpec struct {
+	// Name is the name of the bastion host. Defaults to <cluster name>-bastion.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Subnet is the configuration of the dedicated subnet of the bastion host.
+	// +optional
+	Subnet BastionSubnetSpec `json:"subnet,omitempty"`
+}
+
+// BastionSubnetSpec configures the subnet of an Azure Bastion host.
+type BastionSubnetSpec struct {
+	// Name is the name of the subnet, which Azure requires to be AzureBastionSubnet. Defaults to AzureBastionSubnet.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CidrBlock is the CIDR block of the subnet, with a prefix of at most /26. Defaults to the last /26 of the CIDR
+	// block of the vnet, e.g. 10.255.255.192/26 for the default vnet.
+	// +optional
+	CidrBlock string `json:"cidrBlock,omitempty"`
+}
+
+// ApplicationGatewaySpec configures an Azure Application Gateway serving the API server of the cluster over HTTPS, with
+// the control plane machines as its backend.
+type ApplicationGatewaySpec struct {
+	// Name is the name of the application gateway. Defaults to <cluster name>-appgw.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Subnet is the configuration of the dedicated subnet of the application gateway.
+	// +optional
+	Subnet ApplicationGatewaySubnetSpec `json:"subnet,omitempty"`
+
+	// Capacity is the number of instances of the application gateway. Defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=125
+	// +optional
+	Capacity *int32 `json:"capacity,omitempty"`
+
+	// FrontendPort is the port the application gateway serves the API server on. Defaults to 443.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	FrontendPort int32 `json:"frontendPort,omitempty"`
+
+	// SSLCertificateSecretID is the ID of the Key Vault secret of the TLS certificate served by the application
+	// gateway, e.g. https://my-vault.vault.azure.net/secrets/my-certificate.
+	SSLCertificateSecretID string `json:"sslCertificateSecretID"`
+
+	// IdentityID is the resource ID of the user-assigned identity the application gateway reads its TLS certificate
+	// from Key Vault with.
+	IdentityID string `json:"identityID"`
+
+	// FirewallPolicyID is the resource ID of the web application firewall policy of the application gateway. The
+	// application gateway is of the WAF_v2 tier when it is set, and of the Standard_v2 tier otherwise.
+	// +optional
+	FirewallPolicyID string `json:"firewallPolicyID,omitempty"`
+}
+
+// ApplicationGatewaySubnetSpec configures the subnet of an Azure Application Gateway.
+type ApplicationGatewaySubnetSpec struct {
+	// Name is the name of the subnet, which can't be one of the subnets of the cluster. Defaults to
+	// <cluster name>-appgw-subnet.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CidrBlock is the CIDR block of the subnet, with a prefix of at most /26, which can't overlap the other subnets
+	// of the cluster. Defaults to 10.255.254.0/24.
+	// +optional
+	CidrBlock string `json:"cidrBlock,omitempty"`
+}
+
+// VnetPeeringSpec configures a bidirectional peering between the cluster vnet and a remote virtual network.
+type VnetPeeringSpec struct {
+	// RemoteVnetID is the resource ID of the remote virtual network.
+	RemoteVnetID string `json:"remoteVnetID"`
+
+	// AllowForwardedTraffic allows the traffic forwarded by a network virtual appliance in either vnet
+	// to reach the other one.
+	// +optional
+	AllowForwardedTraffic bool `json:"allowForwardedTraffic,omitempty"`
+
+	// UseRemoteGateways routes the traffic of the cluster vnet through the gateways of the remote vnet,
+	// which in turns allows gateway transit on its side of the peering.
+	// +optional
+	UseRemoteGateways bool `json:"useRemoteGateways,omitempty"`
+}
+
+// PrivateEndpointSpec configures a private endpoint of an Azure resource in a subnet of the cluster vnet.
+type PrivateEndpointSpec struct {
+	// Name is the name of the private endpoint.
+	Name string `json:"name"`
+
+	// SubnetName is the name of the subnet of the cluster vnet the private endpoint gets its private IP from.
+	// The subnet can't be delegated to a service.
+	SubnetName string `json:"subnetName"`
+
+	// PrivateLinkServiceID is the resource ID of the Azure resource the private endpoint connects to, e.g. a key vault.
+	PrivateLinkServiceID string `json:"privateLinkServiceID"`
+
+	// GroupIDs are the sub-resources of the Azure resource the private endpoint connects to, e.g. vault for a key
+	// vault or registry for a container registry.
+	GroupIDs []string `json:"groupIDs"`
+
+	// PrivateDNSZoneIDs are the resource IDs of the private DNS zones the private IP of the endpoint is registered in,
+	// e.g. the privatelink.vaultcore.azure.net zone of key vaults. The zones must be linked to the cluster vnet for
+	// its machines to resolve the Azure resource to the private IP.
+	// +optional
+	PrivateDNSZoneIDs []string `json:"privateDNSZoneIDs,omitempty"`
+}
+
+// PrivateLinkServiceSpec configures the private link service of the internal API server load balancer.
+type PrivateLinkServiceSpec struct {
+	// SubnetName is the name of the subnet of the cluster vnet the private link service allocates the private IPs
+	// the connections of its consumers are translated to from. Defaults to the control plane subnet. The private link
+	// service network policies of the subnet must be disabled, they are disabled on the subnets of a vnet created
+	// by the provider.
+	// +optional
+	SubnetName string `json:"subnetName,omitempty"`
+
+	// VisibilitySubscriptions are the IDs of the subscriptions which can find the private link service by its alias
+	// and request a connection to it, or * for any subscription. If omitted, only the subscriptions with access to
+	// the private link service through role-based access control can.
+	// +optional
+	VisibilitySubscriptions []string `json:"visibilitySubscriptions,omitempty"`
+
+	// AutoApprovalSubscriptions are the IDs of the subscriptions whose connection requests are approved automatically.
+	// The connection requests of the other subscriptions must be approved manually.
+	// +optional
+	AutoApprovalSubscriptions []string `json:"autoApprovalSubscriptions,omitempty"`
+}
+
+// MaxOutboundPortsPerIP is the number of SNAT ports provided by each frontend IP of an outbound rule.
+const MaxOutboundPortsPerIP = 64000
+
+// MaxNodeOutboundIPs is the maximum number of frontend IPs of the outbound rule of a Standard load balancer.
+const MaxNodeOutboundIPs = 16
+
+// ControlPlaneOutboundLBSpec configures the outbound connections of the control plane machines through the control
+// plane outbound load balancer.
+type ControlPlaneOutboundLBSpec struct {
+	// AllocatedOutboundPorts is the number of SNAT ports allocated to each control plane machine.
+	// It must be a multiple of 8. Defaults to the automatic allocation of Azure, which depends on the size of the backend pool.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=64000
+	// +optional
+	AllocatedOutboundPorts *int32 `json:"allocatedOutboundPorts,omitempty"`
+
+	// IdleTimeoutInMinutes is the idle timeout of the outbound connections. Defaults to 4.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+}
+
+// NodeOutboundLBSpec configures the outbound connections of the nodes through the node outbound load balancer.
+type NodeOutboundLBSpec struct {
+	// AllocatedOutboundPorts is the number of SNAT ports allocated to each node.
+	// It must be a multiple of 8, and each frontend IP provides 64000 ports to share between the nodes.
+	// Defaults to the automatic allocation of Azure, which depends on the size of the backend pool.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=64000
+	// +optional
+	AllocatedOutboundPorts *int32 `json:"allocatedOutboundPorts,omitempty"`
+
+	// IdleTimeoutInMinutes is the idle timeout of the outbound connections. Defaults to 4.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+
+	// FrontendIPsCount is the number of public IPs of the node outbound load balancer. Each of them provides
+	// 64000 SNAT ports to the outbound rule, so more IPs allow more outbound connections from the nodes.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +optional
+	FrontendIPsCount *int32 `json:"frontendIPsCount,omitempty"`
+
+	// PublicIPPrefixLength is the length of a public IP prefix to allocate the node outbound IPs from,
+	// so the outbound traffic of the nodes comes from a known, contiguous range. The prefix must be large
+	// enough for the FrontendIPsCount. The node outbound IPs are allocated without a prefix when it is not set.
+	// +kubebuilder:validation:Minimum=28
+	// +kubebuilder:validation:Maximum=31
+	// +optional
+	PublicIPPrefixLength *int32 `json:"publicIPPrefixLength,omitempty"`
+
+	// PublicIPZones are the availability zones of the node outbound public IPs, and of their public IP prefix.
+	// List all the zones of the region, e.g. 1, 2 and 3, for a zone-redundant public IP.
+	// The public IP is not zonal when no zones are set.
+	// +optional
+	PublicIPZones []string `json:"publicIPZones,omitempty"`
+
+	// PublicIPTags are tags of the node outbound public IPs, merged on top of the additionalTags of the cluster,
+	// e.g. to identify the IPs to allow in a firewall. The enforcedTags of the cluster and the tags set by the
+	// provider take precedence over them.
+	// +optional
+	PublicIPTags Tags `json:"publicIPTags,omitempty"`
 }
 
 // VnetSpec configures an Azure virtual network.
@@ -54,6 +374,7 @@ type VnetSpec struct {
 	ResourceGroup string `json:"resourceGroup,omitempty"`
 
 	// ID is the identifier of the virtual network this provider should use to create resources.
+	// Setting the ID marks the virtual network as pre-existing: it will not be created, updated or deleted by this provider.
 	ID string `json:"id,omitempty"`
 
 	// Name defines a name for the virtual network resource.
@@ -62,15 +383,35 @@ type VnetSpec struct {
 	// CidrBlock is the CIDR block to be used when the provider creates a managed virtual network.
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// IPv6CidrBlock is the IPv6 CIDR block of a dual-stack virtual network.
+	// Requires the IPv6DualStack feature gate.
+	// +optional
+	IPv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
+
+	// DNSServers are the IP addresses of the DNS servers of a managed virtual network, in order of preference.
+	// The virtual network uses the Azure-provided DNS when none are set. Changes are applied to the existing virtual network.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
 	// Tags is a collection of tags describing the resource.
 	Tags Tags `json:"tags,omitempty"`
 }
 
+// IsIPv6Enabled returns true if the vnet is dual-stack.
+func (v *VnetSpec) IsIPv6Enabled() bool {
+	return v.IPv6CidrBlock != ""
+}
+
 // IsManaged returns true if the vnet is managed.
 func (v *VnetSpec) IsManaged(clusterName string) bool {
 	return v.ID == "" || v.Tags.HasOwned(clusterName)
 }
 
+// IsPreExisting returns true if the subnet references by ID a subnet of a pre-existing vnet, which is used as-is.
+func (s *SubnetSpec) IsPreExisting(vnet *VnetSpec, clusterName string) bool {
+	return s.ID != "" && !vnet.IsManaged(clusterName)
+}
+
 // Subnets is a slice of Subnet.
 type Subnets []*SubnetSpec
 
@@ -90,11 +431,66 @@ type SecurityGroup struct {
 	ID           string       `json:"id,omitempty"`
 	Name         string       `json:"name,omitempty"`
 	IngressRules IngressRules `json:"ingressRule,omitempty"`
-	Tags         Tags         `json:"tags,omitempty"`
+	// SecurityRules are additional inbound or outbound rules added to the security group,
+	// on top of the ingress rules managed by the provider.
+	// +optional
+	SecurityRules SecurityRules `json:"securityRules,omitempty"`
+	Tags          Tags          `json:"tags,omitempty"`
 }
 
 // RouteTable defines an Azure route table.
 type RouteTable struct {
+	// ID is the identifier of the route table. Setting the ID of a pre-existing route table that is not owned by
+	// the cluster associates it with the subnet as is: its routes are not managed and it is not deleted with the cluster.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the route table. Defaults to the name in the ID when it is set.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Routes are the user-defined routes of a route table owned by the cluster.
+	// Routes added by other components, e.g. the cloud provider, are preserved.
+	// +optional
+	Routes []Route `json:"routes,omitempty"`
+}
+
+// Route defines a user-defined route of a route table.
+type Route struct {
+	// Name is the name of the route.
+	Name string `json:"name"`
+
+	// AddressPrefix is the destination CIDR block the route applies to.
+	AddressPrefix string `json:"addressPrefix"`
+
+	// NextHopType is the type of Azure hop the packets should be sent to.
+	// +kubebuilder:validation:Enum=VirtualNetworkGateway;VnetLocal;Internet;VirtualAppliance;None
+	NextHopType RouteNextHopType `json:"nextHopType"`
+
+	// NextHopIPAddress is the IP address packets should be forwarded to.
+	// Only allowed, and required, for the VirtualAppliance next hop type.
+	// +optional
+	NextHopIPAddress string `json:"nextHopIPAddress,omitempty"`
+}
+
+// RouteNextHopType defines the type of the next hop of a route.
+type RouteNextHopType string
+
+const (
+	// RouteNextHopTypeVirtualNetworkGateway sends the packets to the virtual network gateway.
+	RouteNextHopTypeVirtualNetworkGateway = RouteNextHopType("VirtualNetworkGateway")
+	// RouteNextHopTypeVnetLocal keeps the packets within the virtual network.
+	RouteNextHopTypeVnetLocal = RouteNextHopType("VnetLocal")
+	// RouteNextHopTypeInternet sends the packets to the Internet.
+	RouteNextHopTypeInternet = RouteNextHopType("Internet")
+	// RouteNextHopTypeVirtualAppliance sends the packets to a network virtual appliance, e.g. a firewall.
+	RouteNextHopTypeVirtualAppliance = RouteNextHopType("VirtualAppliance")
+	// RouteNextHopTypeNone drops the packets.
+	RouteNextHopTypeNone = RouteNextHopType("None")
+)
+
+// NatGateway defines an Azure NAT gateway.
+type NatGateway struct {
 	ID   string `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
 }
@@ -138,6 +534,63 @@ type IngressRule struct {
 // IngressRules is a slice of Azure ingress rules for security groups.
 type IngressRules []*IngressRule
 
+// SecurityRuleDirection defines the direction of the traffic a security rule applies to.
+type SecurityRuleDirection string
+
+const (
+	// SecurityRuleDirectionInbound defines a rule for inbound traffic
+	SecurityRuleDirectionInbound = SecurityRuleDirection("Inbound")
+
+	// SecurityRuleDirectionOutbound defines a rule for outbound traffic
+	SecurityRuleDirectionOutbound = SecurityRuleDirection("Outbound")
+)
+
+const (
+	// LBHealthProbeSecurityRuleName is the name of the rule of the control plane security group allowing the health
+	// probes of the Azure load balancers to the API server port. The rule is always managed by the provider.
+	LBHealthProbeSecurityRuleName = "allow_lb_health_probes"
+
+	// LBHealthProbeSecurityRulePriority is the priority of the health probe rule, reserved for it among the inbound
+	// rules of the control plane security group.
+	LBHealthProbeSecurityRulePriority = 103
+)
+
+// SecurityRule defines an additional Azure security rule for security groups.
+type SecurityRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Protocol - The network protocol this rule applies to.
+	// +kubebuilder:validation:Enum=*;Tcp;Udp
+	Protocol SecurityGroupProtocol `json:"protocol"`
+
+	// Direction - Whether the rule applies to inbound or outbound traffic.
+	// +kubebuilder:validation:Enum=Inbound;Outbound
+	Direction SecurityRuleDirection `json:"direction"`
+
+	// Priority - A number between 100 and 4096. Each rule should have a unique value for priority in its direction, including the rules created by the provider.
+	Priority int32 `json:"priority"`
+
+	// SourcePorts - The source port or range. Integer or range between 0 and 65535. Asterix '*' can also be used to match all ports.
+	// +optional
+	SourcePorts *string `json:"sourcePorts,omitempty"`
+
+	// DestinationPorts - The destination port or range. Integer or range between 0 and 65535. Asterix '*' can also be used to match all ports.
+	// +optional
+	DestinationPorts *string `json:"destinationPorts,omitempty"`
+
+	// Source - The CIDR or source IP range. Asterix '*' can also be used to match all source IPs. Default tags such as 'VirtualNetwork', 'AzureLoadBalancer' and 'Internet' can also be used.
+	// +optional
+	Source *string `json:"source,omitempty"`
+
+	// Destination - The destination address prefix. CIDR or destination IP range. Asterix '*' can also be used to match all destination IPs. Default tags such as 'VirtualNetwork', 'AzureLoadBalancer' and 'Internet' can also be used.
+	// +optional
+	Destination *string `json:"destination,omitempty"`
+}
+
+// SecurityRules is a slice of additional Azure security rules for security groups.
+type SecurityRules []*SecurityRule
+
 // PublicIP defines an Azure public IP address.
 type PublicIP struct {
 	ID        string `json:"id,omitempty"`
@@ -156,6 +609,154 @@ type LoadBalancer struct {
 	Tags             Tags             `json:"tags,omitempty"`
 }
 
+// LoadBalancerSpec defines the desired state of an Azure load balancer.
+type LoadBalancerSpec struct {
+	// Type is the type of the load balancer. Public exposes the API server through a public IP,
+	// Internal only exposes it on the control plane subnet.
+	// +kubebuilder:validation:Enum=Public;Internal
+	// +optional
+	Type LBType `json:"type,omitempty"`
+
+	// HealthProbe configures the health probe of the API server load balancers.
+	// +optional
+	HealthProbe *HealthProbe `json:"healthProbe,omitempty"`
+
+	// LoadDistribution is the load distribution of the load balancing rules of the API server load balancers:
+	// Default spreads the connections over the control plane machines, SourceIP and SourceIPProtocol keep the
+	// connections of a client on the same machine. The API server should generally stay on Default. Defaults to Default.
+	// +kubebuilder:validation:Enum=Default;SourceIP;SourceIPProtocol
+	// +optional
+	LoadDistribution LoadDistribution `json:"loadDistribution,omitempty"`
+
+	// PublicIPZones are the availability zones of the API server public IP. List all the zones of the region,
+	// e.g. 1, 2 and 3, for a zone-redundant public IP. The public IP is not zonal when no zones are set.
+	// +optional
+	PublicIPZones []string `json:"publicIPZones,omitempty"`
+
+	// PublicIPTags are tags of the API server public IPs created for the cluster, merged on top of the
+	// additionalTags of the cluster. The enforcedTags of the cluster and the tags set by the provider take
+	// precedence over them.
+	// +optional
+	PublicIPTags Tags `json:"publicIPTags,omitempty"`
+
+	// InternalLBZones are the availability zones of the frontend of the internal API server load balancer. List all
+	// the zones of the region, e.g. 1, 2 and 3, for a zone-redundant frontend surviving the outage of a zone. The
+	// frontend is not zonal when no zones are set. Zones require the Standard load balancer SKU, and can't be changed
+	// once the cluster is provisioned.
+	// +optional
+	InternalLBZones []string `json:"internalLBZones,omitempty"`
+
+	// InternalLBInboundNatRules are inbound NAT rules of the internal API server load balancer forwarding a frontend
+	// port to a port of each control plane machine, e.g. to give direct access to etcd. The rules of a machine are
+	// created with it and deleted with it.
+	// +optional
+	InternalLBInboundNatRules []InboundNatRule `json:"internalLBInboundNatRules,omitempty"`
+
+	// AdditionalPublicIPNames are the names of public IPs exposing the API server in addition to the default one,
+	// e.g. to serve it behind several custom domains. Each public IP gets its own frontend and load balancing rule
+	// on the public API server load balancer, the default frontend is always kept.
+	// +optional
+	AdditionalPublicIPNames []string `json:"additionalPublicIPNames,omitempty"`
+
+	// DNSLabel is the DNS label of the API server public IP, which gives the API server the name
+	// <dnsLabel>.<location>.cloudapp.azure.com in the Azure public cloud. It must be 3 to 63 lowercase letters,
+	// digits and hyphens, start with a letter and be unique in the location. Defaults to the name of the public IP.
+	// +optional
+	DNSLabel string `json:"dnsLabel,omitempty"`
+
+	// PublicIPID is the resource ID of an existing public IP used as the API server public IP, instead of one
+	// created for the cluster, e.g. a static IP allowed by a firewall. It must be a Standard SKU public IP in the
+	// location of the cluster. The public IP is left as is, and isn't deleted with the cluster.
+	// +optional
+	PublicIPID string `json:"publicIPID,omitempty"`
+
+	// PrivateLinkService exposes the API server through a private link service on the frontend of the internal API
+	// server load balancer, so consumers in other virtual networks can reach it through private endpoints. It requires
+	// the Standard load balancer SKU. If omitted, no private link service is created.
+	// +optional
+	PrivateLinkService *PrivateLinkServiceSpec `json:"privateLinkService,omitempty"`
+}
+
+// ProbeProtocol defines the protocol of a load balancer health probe.
+type ProbeProtocol string
+
+const (
+	// ProbeProtocolHTTPS probes the API server with an HTTPS request.
+	ProbeProtocolHTTPS = ProbeProtocol("HTTPS")
+	// ProbeProtocolTCP probes the API server by opening a TCP connection.
+	ProbeProtocolTCP = ProbeProtocol("TCP")
+)
+
+// LoadDistribution defines how the connections of a load balancing rule are distributed over its backend pool.
+type LoadDistribution string
+
+const (
+	// LoadDistributionDefault distributes the connections by a hash of their source IP, source port, destination IP,
+	// destination port and protocol.
+	LoadDistributionDefault = LoadDistribution("Default")
+	// LoadDistributionSourceIP sends the connections of a client IP to the same backend.
+	LoadDistributionSourceIP = LoadDistribution("SourceIP")
+	// LoadDistributionSourceIPProtocol sends the connections of a client IP with the same protocol to the same backend.
+	LoadDistributionSourceIPProtocol = LoadDistribution("SourceIPProtocol")
+)
+
+// HealthProbe defines the health probe of a load balancer.
+type HealthProbe struct {
+	// Protocol is the protocol of the probe. Defaults to HTTPS.
+	// +kubebuilder:validation:Enum=HTTPS;TCP
+	// +optional
+	Protocol ProbeProtocol `json:"protocol,omitempty"`
+
+	// RequestPath is the path requested by HTTPS probes. Defaults to /healthz.
+	// +optional
+	RequestPath string `json:"requestPath,omitempty"`
+
+	// IntervalSeconds is the interval between two probes. Defaults to 15.
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed probes after which
+	// an instance stops receiving traffic. Defaults to 4.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	UnhealthyThreshold *int32 `json:"unhealthyThreshold,omitempty"`
+}
+
+// InboundNatRule defines an inbound NAT rule of the internal API server load balancer, created for each control
+// plane machine.
+type InboundNatRule struct {
+	// Name is the name of the rule. The rule of a control plane machine is named <machine name>-<name>.
+	Name string `json:"name"`
+
+	// FrontendPortRangeStart is the first frontend port of the rules of the control plane machines. Each machine gets
+	// the first port of the range which isn't used by another rule of the load balancer.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	FrontendPortRangeStart int32 `json:"frontendPortRangeStart"`
+
+	// FrontendPortRangeEnd is the last frontend port of the rules of the control plane machines. The range must have
+	// a port for each control plane machine.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	FrontendPortRangeEnd int32 `json:"frontendPortRangeEnd"`
+
+	// BackendPort is the port of the control plane machines the frontend port is forwarded to, e.g. 2379 for etcd.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	BackendPort int32 `json:"backendPort"`
+}
+
+// LBType defines an Azure load balancer Type.
+type LBType string
+
+const (
+	// Internal is the value for the Azure load balancer internal type.
+	Internal = LBType("Internal")
+	// Public is the value for the Azure load balancer public type.
+	Public = LBType("Public")
+)
+
 // FrontendIPConfig - DO NOT USE
 // this empty struct is here to preserve backwards compatibility and should be removed in v1alpha4
 type FrontendIPConfig struct{}
@@ -209,6 +810,8 @@ type VM struct {
 	State    VMState    `json:"vmState,omitempty"`
 	Identity VMIdentity `json:"identity,omitempty"`
 	Tags     Tags       `json:"tags,omitempty"`
+	// Evicted is true when the VM is a Spot VM that was evicted by Azure.
+	Evicted bool `json:"evicted,omitempty"`
 
 	// Addresses contains the addresses associated with the Azure VM.
 	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
@@ -251,6 +854,23 @@ type AzureMarketplaceImage struct {
 	// time even if a new version becomes available.
 	// +kubebuilder:validation:MinLength=1
 	Version string `json:"version"`
+	// Plan specifies the purchase plan of a paid image. It must be set for the images having a plan, whose
+	// marketplace terms are accepted before creating the VMs.
+	// +optional
+	Plan *ImagePlan `json:"plan,omitempty"`
+}
+
+// ImagePlan defines the purchase plan of a marketplace image
+type ImagePlan struct {
+	// Publisher is the publisher ID of the plan
+	// +kubebuilder:validation:MinLength=1
+	Publisher string `json:"publisher"`
+	// Product is the offer of the image in the marketplace
+	// +kubebuilder:validation:MinLength=1
+	Product string `json:"product"`
+	// Name is the plan ID, such as the SKU of the image
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
 }
 
 // AzureSharedGalleryImage defines an image in a Shared Image Gallery to use for VM creation
@@ -297,6 +917,19 @@ const (
 	VMIdentityUserAssigned VMIdentity = "UserAssigned"
 )
 
+// CloudProviderConfigSpec configures the azure.json cloud provider configuration rendered for the nodes of a cluster.
+type CloudProviderConfigSpec struct {
+	// Identity is the identity the nodes authenticate to Azure with. None uses the service principal credentials of
+	// the cluster, SystemAssigned and UserAssigned the managed identity of the VMs. Defaults to None.
+	// +optional
+	Identity VMIdentity `json:"identity,omitempty"`
+
+	// UserAssignedIdentityID is the client ID or the resource ID of the user-assigned identity of the nodes. It is
+	// required with the UserAssigned identity.
+	// +optional
+	UserAssignedIdentityID string `json:"userAssignedIdentityID,omitempty"`
+}
+
 // UserAssignedIdentity defines the user-assigned identities provided
 // by the user to be assigned to Azure resources.
 type UserAssignedIdentity struct {
@@ -305,11 +938,64 @@ type UserAssignedIdentity struct {
 	ProviderID string `json:"providerID"`
 }
 
+const (
+	// LinuxOS is the OS type of Linux VMs.
+	LinuxOS = "Linux"
+	// WindowsOS is the OS type of Windows VMs.
+	WindowsOS = "Windows"
+)
+
 // OSDisk defines the operating system disk for a VM.
 type OSDisk struct {
-	OSType      string      `json:"osType"`
-	DiskSizeGB  int32       `json:"diskSizeGB"`
-	ManagedDisk ManagedDisk `json:"managedDisk"`
+	// OSType is the OS type of the VM: Linux or Windows. Defaults to Linux.
+	OSType string `json:"osType"`
+	// DiskSizeGB is the size of the OS disk in GB. Defaults to the size of the OS disk of the image.
+	// +optional
+	DiskSizeGB int32 `json:"diskSizeGB,omitempty"`
+	// +optional
+	ManagedDisk ManagedDisk `json:"managedDisk,omitempty"`
+	// DiffDiskSettings configures an ephemeral OS disk, stored on the VM host instead of in Azure Storage.
+	// +optional
+	DiffDiskSettings *DiffDiskSettings `json:"diffDiskSettings,omitempty"`
+}
+
+// DiffDiskSettings configures an ephemeral OS disk.
+type DiffDiskSettings struct {
+	// Option enables the ephemeral OS disk. Local is the only supported value.
+	// +kubebuilder:validation:Enum=Local
+	Option string `json:"option"`
+	// Placement is the disk of the VM host storing the ephemeral OS disk: CacheDisk or ResourceDisk.
+	// Defaults to CacheDisk when the VM size has a cache disk, otherwise to ResourceDisk.
+	// +kubebuilder:validation:Enum=CacheDisk;ResourceDisk
+	// +optional
+	Placement string `json:"placement,omitempty"`
+}
+
+const (
+	// PutFuture is a future returned by a create or update operation.
+	PutFuture = "PUT"
+	// DeleteFuture is a future returned by a delete operation.
+	DeleteFuture = "DELETE"
+)
+
+// Future is the state of a long-running operation on an Azure resource.
+type Future struct {
+	// Type is the type of the operation, PUT or DELETE.
+	Type string `json:"type"`
+	// ServiceName is the name of the service that started the operation.
+	ServiceName string `json:"serviceName"`
+	// ResourceGroup is the resource group of the resource.
+	ResourceGroup string `json:"resourceGroup"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Data is the serialized polling state of the operation, used to resume polling it.
+	Data string `json:"data"`
+	// State is the last polled status of the operation, such as InProgress.
+	// +optional
+	State string `json:"state,omitempty"`
+	// PercentComplete is the last polled progress of the operation, when Azure reports it.
+	// +optional
+	PercentComplete *int32 `json:"percentComplete,omitempty"`
 }
 
 // DataDisk specifies the parameters that are used to add one or more data disks to the machine.
@@ -322,11 +1008,22 @@ type DataDisk struct {
 	// Lun Specifies the logical unit number of the data disk. This value is used to identify data disks within the VM and therefore must be unique for each data disk attached to a VM.
 	// The value must be between 0 and 63.
 	Lun *int32 `json:"lun,omitempty"`
+	// ManagedDisk specifies the storage account type of the data disk. Defaults to the default storage account type
+	// of the VM size.
+	// +optional
+	ManagedDisk *ManagedDisk `json:"managedDisk,omitempty"`
+	// CachingType specifies the caching of the data disk. Defaults to None.
+	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
+	// +optional
+	CachingType string `json:"cachingType,omitempty"`
 }
 
 // ManagedDisk defines the managed disk options for a VM.
 type ManagedDisk struct {
-	StorageAccountType string `json:"storageAccountType"`
+	// StorageAccountType is the storage account type of the managed disk, such as Premium_LRS or StandardSSD_LRS.
+	// Defaults to Premium_LRS for an OS disk of a VM size supporting premium storage, otherwise to Standard_LRS.
+	// +optional
+	StorageAccountType string `json:"storageAccountType,omitempty"`
 }
 
 // SubnetRole defines the unique role of a subnet.
@@ -346,6 +1043,9 @@ type SubnetSpec struct {
 	Role SubnetRole `json:"role,omitempty"`
 
 	// ID defines a unique identifier to reference this resource.
+	// When set on a subnet of a pre-existing vnet, the subnet is used as-is: its address space and
+	// network security group are not managed by the provider, and it is not deleted with the cluster.
+	// It must reference the subnet of the same name in the vnet of the cluster.
 	// +optional
 	ID string `json:"id,omitempty"`
 
@@ -356,8 +1056,14 @@ type SubnetSpec struct {
 	// +optional
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// IPv6CidrBlock is the IPv6 CIDR block of the subnet in a dual-stack virtual network.
+	// Requires the IPv6DualStack feature gate.
+	// +optional
+	IPv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
+
 	// InternalLBIPAddress is the IP address that will be used as the internal LB private IP.
-	// For the control plane subnet only.
+	// For the control plane subnet of the internal LB frontend only. It must belong to the subnet CIDR block and can't
+	// be an address reserved by Azure. When empty, Azure allocates an available IP of the subnet.
 	// +optional
 	InternalLBIPAddress string `json:"internalLBIPAddress,omitempty"`
 
@@ -368,19 +1074,72 @@ type SubnetSpec struct {
 	// RouteTable defines the route table that should be attached to this subnet.
 	// +optional
 	RouteTable RouteTable `json:"routeTable,omitempty"`
+
+	// NatGateway defines the NAT gateway that should be attached to this subnet for outbound connectivity.
+	// A NAT gateway can only be attached to node subnets.
+	// +optional
+	NatGateway NatGateway `json:"natGateway,omitempty"`
+
+	// ServiceEndpoints are the Azure services reachable from the subnet through service endpoints, e.g.
+	// Microsoft.Storage or Microsoft.Sql, without public egress. They are only managed on the subnets of a vnet
+	// created by the provider.
+	// +optional
+	ServiceEndpoints []string `json:"serviceEndpoints,omitempty"`
+
+	// Delegations are the Azure services the subnet is delegated to, e.g. Microsoft.Netapp/volumes or
+	// Microsoft.ContainerInstance/containerGroups, which can then deploy their resources in it. The service must
+	// support the security group and route table of the subnet. They are only managed on the subnets of a vnet
+	// created by the provider.
+	// +optional
+	Delegations []string `json:"delegations,omitempty"`
+
+	// Zones are the availability zones of the control plane machines placed in the subnet, for control plane subnets
+	// only. A control plane machine is placed in the control plane subnet listing the zone of its failure domain, or
+	// else in the first control plane subnet. A zone can only be listed by one subnet.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
 }
 
-// GetControlPlaneSubnet returns the cluster control plane subnet.
+// GetControlPlaneSubnet returns the control plane subnet of the frontend of the internal API server load balancer:
+// the control plane subnet of the first of its internal LB zones, else the first control plane subnet.
 func (n *NetworkSpec) GetControlPlaneSubnet() *SubnetSpec {
+	if len(n.APIServerLB.InternalLBZones) > 0 {
+		return n.GetControlPlaneSubnetForZone(n.APIServerLB.InternalLBZones[0])
+	}
+	return n.GetControlPlaneSubnetForZone("")
+}
+
+// GetControlPlaneSubnetForZone returns the control plane subnet listing an availability zone,
+// or the first control plane subnet when none does.
+func (n *NetworkSpec) GetControlPlaneSubnetForZone(zone string) *SubnetSpec {
+	subnets := n.GetControlPlaneSubnets()
+	if len(subnets) == 0 {
+		return nil
+	}
+	if zone != "" {
+		for _, sn := range subnets {
+			for _, z := range sn.Zones {
+				if z == zone {
+					return sn
+				}
+			}
+		}
+	}
+	return subnets[0]
+}
+
+// GetControlPlaneSubnets returns all the cluster control plane subnets.
+func (n *NetworkSpec) GetControlPlaneSubnets() Subnets {
+	var subnets Subnets
 	for _, sn := range n.Subnets {
 		if sn.Role == SubnetControlPlane {
-			return sn
+			subnets = append(subnets, sn)
 		}
 	}
-	return nil
+	return subnets
 }
 
-// GetNodeSubnet returns the cluster node subnet.
+// GetNodeSubnet returns the first cluster node subnet.
 func (n *NetworkSpec) GetNodeSubnet() *SubnetSpec {
 	for _, sn := range n.Subnets {
 		if sn.Role == SubnetNode {
@@ -389,3 +1148,59 @@ func (n *NetworkSpec) GetNodeSubnet() *SubnetSpec {
 	}
 	return nil
 }
+
+// GetNodeSubnets returns all the cluster node subnets.
+func (n *NetworkSpec) GetNodeSubnets() Subnets {
+	var subnets Subnets
+	for _, sn := range n.Subnets {
+		if sn.Role == SubnetNode {
+			subnets = append(subnets, sn)
+		}
+	}
+	return subnets
+}
+
+// FailureDomainsSpec configures the availability zones reported as failure domains of a cluster.
+type FailureDomainsSpec struct {
+	// ControlPlaneVMSize is the VM size of the control plane machines. When set, the zones where the subscription
+	// can't deploy it are reported as failure domains that are not eligible for control plane machines.
+	// +optional
+	ControlPlaneVMSize string `json:"controlPlaneVMSize,omitempty"`
+
+	// Include are zones always eligible for control plane machines, whatever the VM size availability.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude are zones never reported as failure domains, so no machine is placed in them.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// ControlPlaneZones pins the control plane machines to these zones, in order of preference. The other zones are
+	// still failure domains of the worker machines, but are not eligible for control plane machines, so a control
+	// plane of as many machines as zones gets one machine per zone. Every zone must be an availability zone of the
+	// location which can deploy the control plane VM size when it is set. It can't be combined with include.
+	// +optional
+	ControlPlaneZones []string `json:"controlPlaneZones,omitempty"`
+}
+
+// ControlPlaneZoneOrderAttribute is the attribute of the failure domains of the pinned control plane zones reporting
+// their position in the order of preference, starting at 1.
+const ControlPlaneZoneOrderAttribute = "controlPlaneZoneOrder"
+
+// AvailabilitySetAttribute is the attribute of the failure domains of a cluster in a location without availability
+// zones, which are the availability sets of its control plane and node machines.
+const AvailabilitySetAttribute = "availabilitySet"
+
+// ProximityPlacementGroupSpec configures the proximity placement group of a cluster.
+type ProximityPlacementGroupSpec struct {
+	// Name of the proximity placement group. Defaults to <cluster name>-ppg. A proximity placement group
+	// of this name that wasn't created by the cluster is used as is, and isn't deleted with the cluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// FailureDomain is the availability zone of the VMs of the proximity placement group, which is then the
+	// only failure domain of the cluster. When it isn't set, the cluster has no failure domains and its VMs
+	// aren't placed in an availability zone.
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+}
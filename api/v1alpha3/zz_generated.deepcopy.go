@@ -27,6 +27,42 @@ import (
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationGatewaySpec) DeepCopyInto(out *ApplicationGatewaySpec) {
+	*out = *in
+	out.Subnet = in.Subnet
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationGatewaySpec.
+func (in *ApplicationGatewaySpec) DeepCopy() *ApplicationGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationGatewaySubnetSpec) DeepCopyInto(out *ApplicationGatewaySubnetSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationGatewaySubnetSpec.
+func (in *ApplicationGatewaySubnetSpec) DeepCopy() *ApplicationGatewaySubnetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationGatewaySubnetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AvailabilityZone) DeepCopyInto(out *AvailabilityZone) {
 	*out = *in
@@ -79,6 +115,80 @@ func (in *AzureCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureClusterIdentity) DeepCopyInto(out *AzureClusterIdentity) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterIdentity.
+func (in *AzureClusterIdentity) DeepCopy() *AzureClusterIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureClusterIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureClusterIdentity) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureClusterIdentityList) DeepCopyInto(out *AzureClusterIdentityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureClusterIdentity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterIdentityList.
+func (in *AzureClusterIdentityList) DeepCopy() *AzureClusterIdentityList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureClusterIdentityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureClusterIdentityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureClusterIdentitySpec) DeepCopyInto(out *AzureClusterIdentitySpec) {
+	*out = *in
+	out.ClientSecret = in.ClientSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterIdentitySpec.
+func (in *AzureClusterIdentitySpec) DeepCopy() *AzureClusterIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureClusterIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureClusterList) DeepCopyInto(out *AzureClusterList) {
 	*out = *in
@@ -123,6 +233,38 @@ func (in *AzureClusterSpec) DeepCopyInto(out *AzureClusterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.EnforcedTags != nil {
+		in, out := &in.EnforcedTags, &out.EnforcedTags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = new(FailureDomainsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProximityPlacementGroup != nil {
+		in, out := &in.ProximityPlacementGroup, &out.ProximityPlacementGroup
+		*out = new(ProximityPlacementGroupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultImage != nil {
+		in, out := &in.DefaultImage, &out.DefaultImage
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudProviderConfig != nil {
+		in, out := &in.CloudProviderConfig, &out.CloudProviderConfig
+		*out = new(CloudProviderConfigSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterSpec.
@@ -154,6 +296,13 @@ func (in *AzureClusterStatus) DeepCopyInto(out *AzureClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LongRunningOperationStates != nil {
+		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
+		*out = make([]Future, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterStatus.
@@ -249,7 +398,7 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = make([]UserAssignedIdentity, len(*in))
 		copy(*out, *in)
 	}
-	out.OSDisk = in.OSDisk
+	in.OSDisk.DeepCopyInto(&out.OSDisk)
 	if in.DataDisks != nil {
 		in, out := &in.DataDisks, &out.DataDisks
 		*out = make([]DataDisk, len(*in))
@@ -274,6 +423,23 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(SpotVMOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DedicatedHost != nil {
+		in, out := &in.DedicatedHost, &out.DedicatedHost
+		*out = new(DedicatedHost)
+		**out = **in
+	}
+	if in.BootDiagnostics != nil {
+		in, out := &in.BootDiagnostics, &out.BootDiagnostics
+		*out = new(BootDiagnostics)
+		**out = **in
+	}
+	if in.VMExtensions != nil {
+		in, out := &in.VMExtensions, &out.VMExtensions
+		*out = make([]VMExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineSpec.
@@ -299,6 +465,21 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = new(VMState)
 		**out = **in
 	}
+	if in.OSDisk != nil {
+		in, out := &in.OSDisk, &out.OSDisk
+		*out = new(OSDiskStatus)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VMExtensions != nil {
+		in, out := &in.VMExtensions, &out.VMExtensions
+		*out = make([]VMExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -421,6 +602,11 @@ func (in *AzureMachineTemplateSpec) DeepCopy() *AzureMachineTemplateSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMarketplaceImage) DeepCopyInto(out *AzureMarketplaceImage) {
 	*out = *in
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ImagePlan)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMarketplaceImage.
@@ -463,6 +649,52 @@ func (in *BackendPool) DeepCopy() *BackendPool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BastionSpec) DeepCopyInto(out *BastionSpec) {
+	*out = *in
+	out.Subnet = in.Subnet
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionSpec.
+func (in *BastionSpec) DeepCopy() *BastionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BastionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BastionSubnetSpec) DeepCopyInto(out *BastionSubnetSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionSubnetSpec.
+func (in *BastionSubnetSpec) DeepCopy() *BastionSubnetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BastionSubnetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootDiagnostics) DeepCopyInto(out *BootDiagnostics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootDiagnostics.
+func (in *BootDiagnostics) DeepCopy() *BootDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(BootDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -496,150 +728,382 @@ func (in *BuildParams) DeepCopy() *BuildParams {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataDisk) DeepCopyInto(out *DataDisk) {
+func (in *CloudProviderConfigSpec) DeepCopyInto(out *CloudProviderConfigSpec) {
 	*out = *in
-	if in.Lun != nil {
-		in, out := &in.Lun, &out.Lun
-		*out = new(int32)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
-func (in *DataDisk) DeepCopy() *DataDisk {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudProviderConfigSpec.
+func (in *CloudProviderConfigSpec) DeepCopy() *CloudProviderConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DataDisk)
+	out := new(CloudProviderConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FrontendIPConfig) DeepCopyInto(out *FrontendIPConfig) {
+func (in *ControlPlaneOutboundLBSpec) DeepCopyInto(out *ControlPlaneOutboundLBSpec) {
 	*out = *in
+	if in.AllocatedOutboundPorts != nil {
+		in, out := &in.AllocatedOutboundPorts, &out.AllocatedOutboundPorts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPConfig.
-func (in *FrontendIPConfig) DeepCopy() *FrontendIPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneOutboundLBSpec.
+func (in *ControlPlaneOutboundLBSpec) DeepCopy() *ControlPlaneOutboundLBSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FrontendIPConfig)
+	out := new(ControlPlaneOutboundLBSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *DataDisk) DeepCopyInto(out *DataDisk) {
 	*out = *in
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(string)
-		**out = **in
-	}
-	if in.SharedGallery != nil {
-		in, out := &in.SharedGallery, &out.SharedGallery
-		*out = new(AzureSharedGalleryImage)
+	if in.Lun != nil {
+		in, out := &in.Lun, &out.Lun
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Marketplace != nil {
-		in, out := &in.Marketplace, &out.Marketplace
-		*out = new(AzureMarketplaceImage)
+	if in.ManagedDisk != nil {
+		in, out := &in.ManagedDisk, &out.ManagedDisk
+		*out = new(ManagedDisk)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
-func (in *Image) DeepCopy() *Image {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
+func (in *DataDisk) DeepCopy() *DataDisk {
 	if in == nil {
 		return nil
 	}
-	out := new(Image)
+	out := new(DataDisk)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressRule) DeepCopyInto(out *IngressRule) {
+func (in *DedicatedHost) DeepCopyInto(out *DedicatedHost) {
 	*out = *in
-	if in.SourcePorts != nil {
-		in, out := &in.SourcePorts, &out.SourcePorts
-		*out = new(string)
-		**out = **in
-	}
-	if in.DestinationPorts != nil {
-		in, out := &in.DestinationPorts, &out.DestinationPorts
-		*out = new(string)
-		**out = **in
-	}
-	if in.Source != nil {
-		in, out := &in.Source, &out.Source
-		*out = new(string)
-		**out = **in
-	}
-	if in.Destination != nil {
-		in, out := &in.Destination, &out.Destination
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRule.
-func (in *IngressRule) DeepCopy() *IngressRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedHost.
+func (in *DedicatedHost) DeepCopy() *DedicatedHost {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressRule)
+	out := new(DedicatedHost)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in IngressRules) DeepCopyInto(out *IngressRules) {
-	{
-		in := &in
-		*out = make(IngressRules, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(IngressRule)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
+func (in *DiffDiskSettings) DeepCopyInto(out *DiffDiskSettings) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRules.
-func (in IngressRules) DeepCopy() IngressRules {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiffDiskSettings.
+func (in *DiffDiskSettings) DeepCopy() *DiffDiskSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressRules)
+	out := new(DiffDiskSettings)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadBalancer) DeepCopyInto(out *LoadBalancer) {
+func (in *FailureDomainsSpec) DeepCopyInto(out *FailureDomainsSpec) {
 	*out = *in
-	out.FrontendIPConfig = in.FrontendIPConfig
-	out.BackendPool = in.BackendPool
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make(Tags, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControlPlaneZones != nil {
+		in, out := &in.ControlPlaneZones, &out.ControlPlaneZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancer.
-func (in *LoadBalancer) DeepCopy() *LoadBalancer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureDomainsSpec.
+func (in *FailureDomainsSpec) DeepCopy() *FailureDomainsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadBalancer)
+	out := new(FailureDomainsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIPConfig) DeepCopyInto(out *FrontendIPConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPConfig.
+func (in *FrontendIPConfig) DeepCopy() *FrontendIPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Future) DeepCopyInto(out *Future) {
+	*out = *in
+	if in.PercentComplete != nil {
+		in, out := &in.PercentComplete, &out.PercentComplete
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Future.
+func (in *Future) DeepCopy() *Future {
+	if in == nil {
+		return nil
+	}
+	out := new(Future)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthProbe) DeepCopyInto(out *HealthProbe) {
+	*out = *in
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UnhealthyThreshold != nil {
+		in, out := &in.UnhealthyThreshold, &out.UnhealthyThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthProbe.
+func (in *HealthProbe) DeepCopy() *HealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Image) DeepCopyInto(out *Image) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SharedGallery != nil {
+		in, out := &in.SharedGallery, &out.SharedGallery
+		*out = new(AzureSharedGalleryImage)
+		**out = **in
+	}
+	if in.Marketplace != nil {
+		in, out := &in.Marketplace, &out.Marketplace
+		*out = new(AzureMarketplaceImage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
+func (in *Image) DeepCopy() *Image {
+	if in == nil {
+		return nil
+	}
+	out := new(Image)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePlan) DeepCopyInto(out *ImagePlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePlan.
+func (in *ImagePlan) DeepCopy() *ImagePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InboundNatRule) DeepCopyInto(out *InboundNatRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InboundNatRule.
+func (in *InboundNatRule) DeepCopy() *InboundNatRule {
+	if in == nil {
+		return nil
+	}
+	out := new(InboundNatRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRule) DeepCopyInto(out *IngressRule) {
+	*out = *in
+	if in.SourcePorts != nil {
+		in, out := &in.SourcePorts, &out.SourcePorts
+		*out = new(string)
+		**out = **in
+	}
+	if in.DestinationPorts != nil {
+		in, out := &in.DestinationPorts, &out.DestinationPorts
+		*out = new(string)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(string)
+		**out = **in
+	}
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRule.
+func (in *IngressRule) DeepCopy() *IngressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in IngressRules) DeepCopyInto(out *IngressRules) {
+	{
+		in := &in
+		*out = make(IngressRules, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(IngressRule)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRules.
+func (in IngressRules) DeepCopy() IngressRules {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRules)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancer) DeepCopyInto(out *LoadBalancer) {
+	*out = *in
+	out.FrontendIPConfig = in.FrontendIPConfig
+	out.BackendPool = in.BackendPool
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancer.
+func (in *LoadBalancer) DeepCopy() *LoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
+	*out = *in
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(HealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PublicIPZones != nil {
+		in, out := &in.PublicIPZones, &out.PublicIPZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicIPTags != nil {
+		in, out := &in.PublicIPTags, &out.PublicIPTags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InternalLBZones != nil {
+		in, out := &in.InternalLBZones, &out.InternalLBZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InternalLBInboundNatRules != nil {
+		in, out := &in.InternalLBInboundNatRules, &out.InternalLBInboundNatRules
+		*out = make([]InboundNatRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalPublicIPNames != nil {
+		in, out := &in.AdditionalPublicIPNames, &out.AdditionalPublicIPNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateLinkService != nil {
+		in, out := &in.PrivateLinkService, &out.PrivateLinkService
+		*out = new(PrivateLinkServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerSpec.
+func (in *LoadBalancerSpec) DeepCopy() *LoadBalancerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -659,11 +1123,38 @@ func (in *ManagedDisk) DeepCopy() *ManagedDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NatGateway) DeepCopyInto(out *NatGateway) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGateway.
+func (in *NatGateway) DeepCopy() *NatGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(NatGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Network) DeepCopyInto(out *Network) {
 	*out = *in
 	in.APIServerLB.DeepCopyInto(&out.APIServerLB)
 	out.APIServerIP = in.APIServerIP
+	out.APIServerIPv6 = in.APIServerIPv6
+	if in.InternalLBZones != nil {
+		in, out := &in.InternalLBZones, &out.InternalLBZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeOutboundIPs != nil {
+		in, out := &in.NodeOutboundIPs, &out.NodeOutboundIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ResourceIDs.DeepCopyInto(&out.ResourceIDs)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
@@ -676,6 +1167,49 @@ func (in *Network) DeepCopy() *Network {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkResourceIDs) DeepCopyInto(out *NetworkResourceIDs) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancers != nil {
+		in, out := &in.LoadBalancers, &out.LoadBalancers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PublicIPs != nil {
+		in, out := &in.PublicIPs, &out.PublicIPs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkResourceIDs.
+func (in *NetworkResourceIDs) DeepCopy() *NetworkResourceIDs {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkResourceIDs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 	*out = *in
@@ -691,6 +1225,49 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 			}
 		}
 	}
+	in.APIServerLB.DeepCopyInto(&out.APIServerLB)
+	if in.NodeOutboundLB != nil {
+		in, out := &in.NodeOutboundLB, &out.NodeOutboundLB
+		*out = new(NodeOutboundLBSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneOutboundLB != nil {
+		in, out := &in.ControlPlaneOutboundLB, &out.ControlPlaneOutboundLB
+		*out = new(ControlPlaneOutboundLBSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AcceleratedNetworking != nil {
+		in, out := &in.AcceleratedNetworking, &out.AcceleratedNetworking
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VnetPeerings != nil {
+		in, out := &in.VnetPeerings, &out.VnetPeerings
+		*out = make([]VnetPeeringSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateEndpoints != nil {
+		in, out := &in.PrivateEndpoints, &out.PrivateEndpoints
+		*out = make([]PrivateEndpointSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(BastionSpec)
+		**out = **in
+	}
+	if in.ApplicationGateway != nil {
+		in, out := &in.ApplicationGateway, &out.ApplicationGateway
+		*out = new(ApplicationGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedAPIServerCIDRs != nil {
+		in, out := &in.AllowedAPIServerCIDRs, &out.AllowedAPIServerCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -703,10 +1280,62 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOutboundLBSpec) DeepCopyInto(out *NodeOutboundLBSpec) {
+	*out = *in
+	if in.AllocatedOutboundPorts != nil {
+		in, out := &in.AllocatedOutboundPorts, &out.AllocatedOutboundPorts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FrontendIPsCount != nil {
+		in, out := &in.FrontendIPsCount, &out.FrontendIPsCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PublicIPPrefixLength != nil {
+		in, out := &in.PublicIPPrefixLength, &out.PublicIPPrefixLength
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PublicIPZones != nil {
+		in, out := &in.PublicIPZones, &out.PublicIPZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicIPTags != nil {
+		in, out := &in.PublicIPTags, &out.PublicIPTags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOutboundLBSpec.
+func (in *NodeOutboundLBSpec) DeepCopy() *NodeOutboundLBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOutboundLBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 	*out = *in
 	out.ManagedDisk = in.ManagedDisk
+	if in.DiffDiskSettings != nil {
+		in, out := &in.DiffDiskSettings, &out.DiffDiskSettings
+		*out = new(DiffDiskSettings)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDisk.
@@ -719,6 +1348,91 @@ func (in *OSDisk) DeepCopy() *OSDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDiskStatus) DeepCopyInto(out *OSDiskStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDiskStatus.
+func (in *OSDiskStatus) DeepCopy() *OSDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateEndpointSpec) DeepCopyInto(out *PrivateEndpointSpec) {
+	*out = *in
+	if in.GroupIDs != nil {
+		in, out := &in.GroupIDs, &out.GroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateDNSZoneIDs != nil {
+		in, out := &in.PrivateDNSZoneIDs, &out.PrivateDNSZoneIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateEndpointSpec.
+func (in *PrivateEndpointSpec) DeepCopy() *PrivateEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateLinkServiceSpec) DeepCopyInto(out *PrivateLinkServiceSpec) {
+	*out = *in
+	if in.VisibilitySubscriptions != nil {
+		in, out := &in.VisibilitySubscriptions, &out.VisibilitySubscriptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoApprovalSubscriptions != nil {
+		in, out := &in.AutoApprovalSubscriptions, &out.AutoApprovalSubscriptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateLinkServiceSpec.
+func (in *PrivateLinkServiceSpec) DeepCopy() *PrivateLinkServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateLinkServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProximityPlacementGroupSpec) DeepCopyInto(out *ProximityPlacementGroupSpec) {
+	*out = *in
+	if in.FailureDomain != nil {
+		in, out := &in.FailureDomain, &out.FailureDomain
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProximityPlacementGroupSpec.
+func (in *ProximityPlacementGroupSpec) DeepCopy() *ProximityPlacementGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProximityPlacementGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublicIP) DeepCopyInto(out *PublicIP) {
 	*out = *in
@@ -734,9 +1448,29 @@ func (in *PublicIP) DeepCopy() *PublicIP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
+func (in *Route) DeepCopy() *Route {
+	if in == nil {
+		return nil
+	}
+	out := new(Route)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]Route, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTable.
@@ -763,6 +1497,17 @@ func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 			}
 		}
 	}
+	if in.SecurityRules != nil {
+		in, out := &in.SecurityRules, &out.SecurityRules
+		*out = make(SecurityRules, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SecurityRule)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(Tags, len(*in))
@@ -782,6 +1527,66 @@ func (in *SecurityGroup) DeepCopy() *SecurityGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityRule) DeepCopyInto(out *SecurityRule) {
+	*out = *in
+	if in.SourcePorts != nil {
+		in, out := &in.SourcePorts, &out.SourcePorts
+		*out = new(string)
+		**out = **in
+	}
+	if in.DestinationPorts != nil {
+		in, out := &in.DestinationPorts, &out.DestinationPorts
+		*out = new(string)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(string)
+		**out = **in
+	}
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityRule.
+func (in *SecurityRule) DeepCopy() *SecurityRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in SecurityRules) DeepCopyInto(out *SecurityRules) {
+	{
+		in := &in
+		*out = make(SecurityRules, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SecurityRule)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityRules.
+func (in SecurityRules) DeepCopy() SecurityRules {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityRules)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 	*out = *in
@@ -790,6 +1595,11 @@ func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.EvictionPolicy != nil {
+		in, out := &in.EvictionPolicy, &out.EvictionPolicy
+		*out = new(SpotEvictionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotVMOptions.
@@ -806,7 +1616,23 @@ func (in *SpotVMOptions) DeepCopy() *SpotVMOptions {
 func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
 	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
-	out.RouteTable = in.RouteTable
+	in.RouteTable.DeepCopyInto(&out.RouteTable)
+	out.NatGateway = in.NatGateway
+	if in.ServiceEndpoints != nil {
+		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Delegations != nil {
+		in, out := &in.Delegations, &out.Delegations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
@@ -884,7 +1710,7 @@ func (in *UserAssignedIdentity) DeepCopy() *UserAssignedIdentity {
 func (in *VM) DeepCopyInto(out *VM) {
 	*out = *in
 	in.Image.DeepCopyInto(&out.Image)
-	out.OSDisk = in.OSDisk
+	in.OSDisk.DeepCopyInto(&out.OSDisk)
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(Tags, len(*in))
@@ -909,9 +1735,66 @@ func (in *VM) DeepCopy() *VM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VnetPeeringSpec) DeepCopyInto(out *VnetPeeringSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VnetPeeringSpec.
+func (in *VnetPeeringSpec) DeepCopy() *VnetPeeringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VnetPeeringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExtension) DeepCopyInto(out *VMExtension) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExtension.
+func (in *VMExtension) DeepCopy() *VMExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExtensionStatus) DeepCopyInto(out *VMExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExtensionStatus.
+func (in *VMExtensionStatus) DeepCopy() *VMExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetSpec) DeepCopyInto(out *VnetSpec) {
 	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(Tags, len(*in))
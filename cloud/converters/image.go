@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -42,6 +43,19 @@ func ImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 
 }
 
+// ImageToPlan converts the purchase plan of a CAPZ marketplace image to an Azure SDK Plan. It is nil for the other
+// images and for the marketplace images without a plan.
+func ImageToPlan(image *infrav1.Image) *compute.Plan {
+	if image == nil || image.Marketplace == nil || image.Marketplace.Plan == nil {
+		return nil
+	}
+	return &compute.Plan{
+		Publisher: to.StringPtr(image.Marketplace.Plan.Publisher),
+		Product:   to.StringPtr(image.Marketplace.Plan.Product),
+		Name:      to.StringPtr(image.Marketplace.Plan.Name),
+	}
+}
+
 func mpImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 	return &compute.ImageReference{
 		Publisher: &image.Marketplace.Publisher,
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters_test
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+func Test_GetSpotVMOptions(t *testing.T) {
+	deletePolicy := infrav1.SpotEvictionPolicyDelete
+	cases := []struct {
+		Name                   string
+		SpotVMOptions          *infrav1.SpotVMOptions
+		ExpectedPriority       compute.VirtualMachinePriorityTypes
+		ExpectedEvictionPolicy compute.VirtualMachineEvictionPolicyTypes
+		ExpectedBillingProfile *compute.BillingProfile
+		ExpectErr              bool
+	}{
+		{
+			Name:          "regular VM",
+			SpotVMOptions: nil,
+		},
+		{
+			Name:                   "Spot VM without max price",
+			SpotVMOptions:          &infrav1.SpotVMOptions{},
+			ExpectedPriority:       compute.Spot,
+			ExpectedEvictionPolicy: compute.Deallocate,
+		},
+		{
+			Name:                   "Spot VM with max price and delete eviction policy",
+			SpotVMOptions:          &infrav1.SpotVMOptions{MaxPrice: to.StringPtr("0.04"), EvictionPolicy: &deletePolicy},
+			ExpectedPriority:       compute.Spot,
+			ExpectedEvictionPolicy: compute.Delete,
+			ExpectedBillingProfile: &compute.BillingProfile{MaxPrice: to.Float64Ptr(0.04)},
+		},
+		{
+			Name:          "invalid max price",
+			SpotVMOptions: &infrav1.SpotVMOptions{MaxPrice: to.StringPtr("cheap")},
+			ExpectErr:     true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(c.SpotVMOptions)
+			if c.ExpectErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(priority).To(gomega.Equal(c.ExpectedPriority))
+			g.Expect(evictionPolicy).To(gomega.Equal(c.ExpectedEvictionPolicy))
+			g.Expect(billingProfile).To(gomega.Equal(c.ExpectedBillingProfile))
+		})
+	}
+}
+
+func Test_SDKToVM_Evicted(t *testing.T) {
+	cases := []struct {
+		Name            string
+		Priority        compute.VirtualMachinePriorityTypes
+		PowerState      string
+		ExpectedEvicted bool
+	}{
+		{
+			Name:            "deallocated Spot VM",
+			Priority:        compute.Spot,
+			PowerState:      "PowerState/deallocated",
+			ExpectedEvicted: true,
+		},
+		{
+			Name:            "running Spot VM",
+			Priority:        compute.Spot,
+			PowerState:      "PowerState/running",
+			ExpectedEvicted: false,
+		},
+		{
+			Name:            "deallocated regular VM",
+			Priority:        compute.Regular,
+			PowerState:      "PowerState/deallocated",
+			ExpectedEvicted: false,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			vm, err := converters.SDKToVM(compute.VirtualMachine{
+				Name: to.StringPtr("my-vm"),
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					Priority: c.Priority,
+					InstanceView: &compute.VirtualMachineInstanceView{
+						Statuses: &[]compute.InstanceViewStatus{
+							{Code: to.StringPtr("ProvisioningState/succeeded")},
+							{Code: to.StringPtr(c.PowerState)},
+						},
+					},
+				},
+			})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(vm.Evicted).To(gomega.Equal(c.ExpectedEvicted))
+		})
+	}
+}
@@ -42,3 +42,25 @@ func TagsToMap(src infrav1.Tags) map[string]*string {
 
 	return tags
 }
+
+// UpdateTags applies the desired tags to the tags of an existing resource and reports whether they changed.
+// Tags from the last applied set which are no longer desired are removed, while tags set out-of-band are kept.
+func UpdateTags(existing map[string]*string, desired, lastApplied infrav1.Tags) (map[string]*string, bool) {
+	tags := MapToTags(existing)
+	changed := false
+	for k := range lastApplied {
+		if _, ok := desired[k]; !ok {
+			if _, ok := tags[k]; ok {
+				delete(tags, k)
+				changed = true
+			}
+		}
+	}
+	for k, v := range desired {
+		if current, ok := tags[k]; !ok || current != v {
+			tags[k] = v
+			changed = true
+		}
+	}
+	return TagsToMap(tags), changed
+}
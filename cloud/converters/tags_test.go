@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+func TestUpdateTags(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name            string
+		existing        map[string]*string
+		desired         infrav1.Tags
+		lastApplied     infrav1.Tags
+		expectedTags    map[string]*string
+		expectedChanged bool
+	}{
+		{
+			name:            "tags are up to date",
+			existing:        map[string]*string{"env": to.StringPtr("prod"), "owner": to.StringPtr("ops")},
+			desired:         infrav1.Tags{"env": "prod"},
+			lastApplied:     infrav1.Tags{"env": "prod"},
+			expectedTags:    map[string]*string{"env": to.StringPtr("prod"), "owner": to.StringPtr("ops")},
+			expectedChanged: false,
+		},
+		{
+			name:            "tags are added and updated",
+			existing:        map[string]*string{"env": to.StringPtr("dev")},
+			desired:         infrav1.Tags{"env": "prod", "team": "infra"},
+			lastApplied:     infrav1.Tags{"env": "dev"},
+			expectedTags:    map[string]*string{"env": to.StringPtr("prod"), "team": to.StringPtr("infra")},
+			expectedChanged: true,
+		},
+		{
+			name:            "tags removed from the spec are removed while tags set out-of-band are kept",
+			existing:        map[string]*string{"env": to.StringPtr("prod"), "team": to.StringPtr("infra"), "owner": to.StringPtr("ops")},
+			desired:         infrav1.Tags{"env": "prod"},
+			lastApplied:     infrav1.Tags{"env": "prod", "team": "infra"},
+			expectedTags:    map[string]*string{"env": to.StringPtr("prod"), "owner": to.StringPtr("ops")},
+			expectedChanged: true,
+		},
+		{
+			name:            "tags already removed out-of-band",
+			existing:        map[string]*string{"env": to.StringPtr("prod")},
+			desired:         infrav1.Tags{"env": "prod"},
+			lastApplied:     infrav1.Tags{"env": "prod", "team": "infra"},
+			expectedTags:    map[string]*string{"env": to.StringPtr("prod")},
+			expectedChanged: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tags, changed := UpdateTags(tc.existing, tc.desired, tc.lastApplied)
+			g.Expect(tags).To(Equal(tc.expectedTags))
+			g.Expect(changed).To(Equal(tc.expectedChanged))
+		})
+	}
+}
@@ -34,6 +34,15 @@ func SDKToVM(v compute.VirtualMachine) (*infrav1.VM, error) {
 		vm.VMSize = string(v.VirtualMachineProperties.HardwareProfile.VMSize)
 	}
 
+	if v.VirtualMachineProperties != nil && v.StorageProfile != nil && v.StorageProfile.OsDisk != nil {
+		osDisk := v.StorageProfile.OsDisk
+		vm.OSDisk.OSType = string(osDisk.OsType)
+		vm.OSDisk.DiskSizeGB = to.Int32(osDisk.DiskSizeGB)
+		if osDisk.ManagedDisk != nil {
+			vm.OSDisk.ManagedDisk.StorageAccountType = string(osDisk.ManagedDisk.StorageAccountType)
+		}
+	}
+
 	if v.Zones != nil && len(*v.Zones) > 0 {
 		vm.AvailabilityZone = to.StringSlice(v.Zones)[0]
 	}
@@ -42,5 +51,14 @@ func SDKToVM(v compute.VirtualMachine) (*infrav1.VM, error) {
 		vm.Tags = MapToTags(v.Tags)
 	}
 
+	// a Spot VM with the Deallocate eviction policy is stopped and deallocated when it is evicted
+	if v.VirtualMachineProperties != nil && v.Priority == compute.Spot && v.InstanceView != nil && v.InstanceView.Statuses != nil {
+		for _, status := range *v.InstanceView.Statuses {
+			if to.String(status.Code) == "PowerState/deallocated" {
+				vm.Evicted = true
+			}
+		}
+	}
+
 	return vm, nil
 }
@@ -18,7 +18,10 @@ package azure
 
 import (
 	"fmt"
+	"hash/fnv"
+	"strings"
 
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
@@ -28,13 +31,43 @@ import (
 const (
 	// DefaultUserName is the default username for created vm
 	DefaultUserName = "capi"
-	// DefaultInternalLBIPAddress is the default internal load balancer ip address
-	DefaultInternalLBIPAddress = "10.0.0.100"
+	// PrivateAPIServerHostname is the host name of the API server for clusters with an internal API server load balancer
+	PrivateAPIServerHostname = "apiserver"
+	// TagsLastAppliedAnnotation is the key for the AzureCluster annotation which tracks the additional tags
+	// applied to the cluster resources, so tags removed from the spec can be removed from Azure.
+	TagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags"
+	// AdditionalAPIServerIPsLastAppliedAnnotation is the key for the AzureCluster annotation which tracks the additional
+	// public IPs of the API server load balancer, so public IPs removed from the spec can be deleted from Azure.
+	AdditionalAPIServerIPsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-additional-api-server-ips"
+	// BastionSubnetName is the name Azure requires for the subnet of a bastion host
+	BastionSubnetName = "AzureBastionSubnet"
+	// MaxBastionSubnetPrefix is the longest prefix Azure accepts for the subnet of a bastion host
+	MaxBastionSubnetPrefix = 26
+	// DefaultApplicationGatewaySubnetCIDR is the default CIDR block of the subnet of an application gateway
+	DefaultApplicationGatewaySubnetCIDR = "10.255.254.0/24"
+	// MaxApplicationGatewaySubnetPrefix is the longest prefix accepted for the subnet of an application gateway, so
+	// it has room to scale out
+	MaxApplicationGatewaySubnetPrefix = 26
+	// DefaultApplicationGatewayCapacity is the default number of instances of an application gateway
+	DefaultApplicationGatewayCapacity = 2
+	// DefaultApplicationGatewayFrontendPort is the default port an application gateway listens on
+	DefaultApplicationGatewayFrontendPort = 443
+	// AvailabilitySetFaultDomainCount is the number of fault domains of the availability sets, which every location supports
+	AvailabilitySetFaultDomainCount = 2
+	// AvailabilitySetUpdateDomainCount is the number of update domains of the availability sets
+	AvailabilitySetUpdateDomainCount = 5
+	// MaxResourceNameLength is the longest name Azure accepts for the resources of a cluster, such as load balancers,
+	// public IPs, public IP prefixes, availability sets, proximity placement groups and bastion hosts
+	MaxResourceNameLength = 80
+	// MaxDNSLabelLength is the longest label of a DNS name
+	MaxDNSLabelLength = 63
 )
 
 const (
 	// DefaultImageOfferID is the default Azure Marketplace offer ID
 	DefaultImageOfferID = "capi"
+	// DefaultWindowsImageOfferID is the default Azure Marketplace offer ID of Windows images
+	DefaultWindowsImageOfferID = "capi-windows"
 	// DefaultImagePublisherID is the default Azure Marketplace publisher ID
 	DefaultImagePublisherID = "cncf-upstream"
 	// LatestVersion is the image version latest
@@ -62,24 +95,171 @@ var SupportedAvailabilityZoneLocations = []string{
 	"southeastasia",
 }
 
+// SupportsAvailabilityZones returns true if Availability Zones are supported in the location.
+func SupportsAvailabilityZones(location string) bool {
+	for _, supportedLocation := range SupportedAvailabilityZoneLocations {
+		if location == supportedLocation {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateResourceName generates the name of a resource from a prefix, a base name, such as the cluster name, and a
+// suffix, in at most maxLength characters. When the name is longer, the base name is truncated and followed by a hash
+// of the whole name, so that the names generated from long base names sharing a beginning stay unique.
+func GenerateResourceName(prefix, base, suffix string, maxLength int) string {
+	name := prefix + base + suffix
+	if len(name) <= maxLength {
+		return name
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	hash := fmt.Sprintf("%08x", h.Sum32())
+	keep := maxLength - len(prefix) - len(suffix) - len(hash) - 1
+	if keep < 0 {
+		keep = 0
+	}
+	return fmt.Sprintf("%s%s-%s%s", prefix, strings.TrimRight(base[:keep], "-."), hash, suffix)
+}
+
 // GenerateInternalLBName generates a internal load balancer name, based on the cluster name.
 func GenerateInternalLBName(clusterName string) string {
-	return fmt.Sprintf("%s-%s", clusterName, "internal-lb")
+	return GenerateResourceName("", clusterName, "-internal-lb", MaxResourceNameLength)
+}
+
+// GenerateControlPlaneOutboundLBName generates the name of the control plane outbound LB.
+func GenerateControlPlaneOutboundLBName(clusterName string) string {
+	return GenerateResourceName("", clusterName, "-outbound-lb", MaxResourceNameLength)
 }
 
 // GeneratePublicLBName generates a public load balancer name, based on the cluster name.
 func GeneratePublicLBName(clusterName string) string {
-	return fmt.Sprintf("%s-%s", clusterName, "public-lb")
+	return GenerateResourceName("", clusterName, "-public-lb", MaxResourceNameLength)
+}
+
+// GenerateDefaultPrivateDNSZoneName generates the default private DNS zone name, based on the cluster name.
+func GenerateDefaultPrivateDNSZoneName(clusterName string) string {
+	return GenerateResourceName("", clusterName, "", MaxDNSLabelLength) + ".capz.io"
+}
+
+// GenerateVNetLinkName generates the name of the link between a private DNS zone and a virtual network, based on the vnet name.
+func GenerateVNetLinkName(vnetName string) string {
+	return fmt.Sprintf("%s-link", vnetName)
+}
+
+// GenerateVnetPeeringName generates the name of the peering from a virtual network to a remote one.
+func GenerateVnetPeeringName(vnetName, remoteVnetName string) string {
+	return fmt.Sprintf("%s-to-%s", vnetName, remoteVnetName)
+}
+
+// GenerateCloudProviderConfigSecretName generates the name of the secret holding the cloud provider configuration of a cluster.
+func GenerateCloudProviderConfigSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-azure-json", clusterName)
+}
+
+// GenerateBastionName generates the default name of the bastion host, based on the cluster name.
+func GenerateBastionName(clusterName string) string {
+	return GenerateResourceName("", clusterName, "-bastion", MaxResourceNameLength)
+}
+
+// GeneratePrivateLinkServiceName generates the name of the private link service of the internal API server load
+// balancer, based on the cluster name.
+func GeneratePrivateLinkServiceName(clusterName string) string {
+	return GenerateResourceName("", clusterName, "-apiserver-pls", MaxResourceNameLength)
+}
+
+// GenerateBastionIPName generates the name of the public IP of a bastion host, based on the bastion host name.
+func GenerateBastionIPName(bastionName string) string {
+	return fmt.Sprintf("pip-%s", bastionName)
+}
+
+// GenerateApplicationGatewayName generates the default name of the application gateway, based on the cluster name.
+func GenerateApplicationGatewayName(clusterName string) string {
+	return GenerateResourceName("", clusterName, "-appgw", MaxResourceNameLength)
+}
+
+// GenerateApplicationGatewaySubnetName generates the default name of the subnet of the application gateway, based on
+// the cluster name.
+func GenerateApplicationGatewaySubnetName(clusterName string) string {
+	return fmt.Sprintf("%s-appgw-subnet", clusterName)
+}
+
+// GenerateApplicationGatewayIPName generates the name of the public IP of an application gateway, based on the
+// application gateway name.
+func GenerateApplicationGatewayIPName(appGatewayName string) string {
+	return fmt.Sprintf("pip-%s", appGatewayName)
+}
+
+// GenerateApplicationGatewayBackendPoolName generates the name of the backend address pool of an application gateway,
+// holding the control plane machines.
+func GenerateApplicationGatewayBackendPoolName(appGatewayName string) string {
+	return fmt.Sprintf("%s-backendPool", appGatewayName)
+}
+
+// GenerateProximityPlacementGroupName generates the default name of the proximity placement group, based on the cluster name.
+func GenerateProximityPlacementGroupName(clusterName string) string {
+	return GenerateResourceName("", clusterName, "-ppg", MaxResourceNameLength)
+}
+
+// GenerateAvailabilitySetName generates the name of the availability set of the machines of a role, based on the cluster name.
+func GenerateAvailabilitySetName(clusterName, role string) string {
+	return GenerateResourceName("", clusterName, fmt.Sprintf("-%s-as", role), MaxResourceNameLength)
 }
 
 // GeneratePublicIPName generates a public IP name, based on the cluster name and a hash.
 func GeneratePublicIPName(clusterName, hash string) string {
-	return fmt.Sprintf("%s-%s", clusterName, hash)
+	return GenerateResourceName("", clusterName, "-"+hash, MaxResourceNameLength)
+}
+
+// GenerateIPv6PublicIPName generates the name of the IPv6 counterpart of a public IP in a dual-stack cluster.
+func GenerateIPv6PublicIPName(publicIPName string) string {
+	return fmt.Sprintf("%s-v6", publicIPName)
+}
+
+// GenerateIPv6FrontendIPConfigName generates the name of the IPv6 frontend IP configuration of a dual-stack load balancer.
+func GenerateIPv6FrontendIPConfigName(lbName string) string {
+	return fmt.Sprintf("%s-frontEnd-ipv6", lbName)
+}
+
+// GenerateAdditionalFrontendIPConfigName generates the name of the frontend IP configuration of an additional public IP of a load balancer.
+func GenerateAdditionalFrontendIPConfigName(lbName, publicIPName string) string {
+	return fmt.Sprintf("%s-frontEnd-%s", lbName, publicIPName)
+}
+
+// GenerateIPv6BackendPoolName generates the name of the IPv6 backend address pool of a dual-stack load balancer.
+func GenerateIPv6BackendPoolName(lbName string) string {
+	return fmt.Sprintf("%s-backendPool-ipv6", lbName)
+}
+
+// GenerateControlPlaneOutboundIPName generates the name of the public IP of the control plane outbound LB.
+func GenerateControlPlaneOutboundIPName(clusterName string) string {
+	return GenerateResourceName("pip-", clusterName, "-controlplane-outbound", MaxResourceNameLength)
 }
 
 // GenerateNodeOutboundIPName generates a public IP name, based on the cluster name.
 func GenerateNodeOutboundIPName(clusterName string) string {
-	return fmt.Sprintf("pip-%s-node-outbound", clusterName)
+	return GenerateResourceName("pip-", clusterName, "-node-outbound", MaxResourceNameLength)
+}
+
+// GenerateNodeOutboundIPNames generates the names of the public IPs of the node outbound load balancer.
+// The first one keeps the name of the single node outbound IP, so existing clusters can add more IPs.
+func GenerateNodeOutboundIPNames(clusterName string, count int32) []string {
+	names := []string{GenerateNodeOutboundIPName(clusterName)}
+	for i := int32(2); i <= count; i++ {
+		names = append(names, GenerateResourceName("pip-", clusterName, fmt.Sprintf("-node-outbound-%d", i), MaxResourceNameLength))
+	}
+	return names
+}
+
+// GenerateNodeOutboundIPPrefixName generates a public IP prefix name, based on the cluster name.
+func GenerateNodeOutboundIPPrefixName(clusterName string) string {
+	return GenerateResourceName("ippre-", clusterName, "-node-outbound", MaxResourceNameLength)
+}
+
+// GenerateNatGatewayIPName generates a NAT gateway public IP name, based on the NAT gateway name.
+func GenerateNatGatewayIPName(natGatewayName string) string {
+	return fmt.Sprintf("pip-%s", natGatewayName)
 }
 
 // GenerateNodePublicIPName generates a node public IP name, based on the machine name.
@@ -97,6 +277,12 @@ func GeneratePublicNICName(machineName string) string {
 	return fmt.Sprintf("%s-public-nic", machineName)
 }
 
+// GenerateInternalLBNatRuleName generates the name of the inbound NAT rule of a control plane machine on the internal
+// API server LB, based on the names of the machine and of the rule.
+func GenerateInternalLBNatRuleName(machineName, ruleName string) string {
+	return GenerateResourceName("", machineName, "-"+ruleName, MaxResourceNameLength)
+}
+
 // GenerateOSDiskName generates the name of an OS disk based on the name of a VM.
 func GenerateOSDiskName(machineName string) string {
 	return fmt.Sprintf("%s_OSDisk", machineName)
@@ -107,18 +293,18 @@ func GenerateDataDiskName(machineName, nameSuffix string) string {
 	return fmt.Sprintf("%s_%s", machineName, nameSuffix)
 }
 
-// GetDefaultImageSKUID gets the SKU ID of the image to use for the provided version of Kubernetes.
-func getDefaultImageSKUID(k8sVersion string) (string, error) {
+// GetDefaultImageSKUID gets the SKU ID of the image of an OS to use for the provided version of Kubernetes.
+func getDefaultImageSKUID(k8sVersion string, osAndVersion string) (string, error) {
 	version, err := semver.ParseTolerant(k8sVersion)
 	if err != nil {
 		return "", errors.Wrapf(err, "unable to parse Kubernetes version \"%s\" in spec, expected valid SemVer string", k8sVersion)
 	}
-	return fmt.Sprintf("k8s-%ddot%ddot%d-ubuntu-1804", version.Major, version.Minor, version.Patch), nil
+	return fmt.Sprintf("k8s-%ddot%ddot%d-%s", version.Major, version.Minor, version.Patch, osAndVersion), nil
 }
 
 // GetDefaultUbuntuImage returns the default image spec for Ubuntu.
 func GetDefaultUbuntuImage(k8sVersion string) (*infrav1.Image, error) {
-	skuID, err := getDefaultImageSKUID(k8sVersion)
+	skuID, err := getDefaultImageSKUID(k8sVersion, "ubuntu-1804")
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get default image")
 	}
@@ -135,7 +321,37 @@ func GetDefaultUbuntuImage(k8sVersion string) (*infrav1.Image, error) {
 	return defaultImage, nil
 }
 
+// GetDefaultWindowsImage returns the default image spec for Windows Server 2019.
+func GetDefaultWindowsImage(k8sVersion string) (*infrav1.Image, error) {
+	skuID, err := getDefaultImageSKUID(k8sVersion, "windows-2019")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get default Windows image")
+	}
+
+	defaultImage := &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Publisher: DefaultImagePublisherID,
+			Offer:     DefaultWindowsImageOfferID,
+			SKU:       skuID,
+			Version:   LatestVersion,
+		},
+	}
+
+	return defaultImage, nil
+}
+
 // UserAgent specifies a string to append to the agent identifier.
 func UserAgent() string {
 	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
 }
+
+// SetAutoRestClientDefaults sets the authorizer and user agent of an Azure SDK client, records the metrics of its
+// requests, and retries its requests throttled by Azure.
+func SetAutoRestClientDefaults(c *autorest.Client, auth autorest.Authorizer) {
+	c.Authorizer = auth
+	_ = c.AddToUserAgent(UserAgent()) // intentionally ignore error as it doesn't matter
+	// the metrics decorator is the innermost one, so each attempt of a throttled request is recorded
+	c.Sender = autorest.DecorateSender(autorest.CreateSender(),
+		RecordAPIMetrics(),
+		DoRetryForThrottling(DefaultThrottlingRetryDelay, DefaultThrottlingMaxRetryDelay, DefaultThrottlingMaxRetryDuration))
+}
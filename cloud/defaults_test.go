@@ -17,9 +17,12 @@ limitations under the License.
 package azure
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 )
 
 func TestGetDefaultImageSKUID(t *testing.T) {
@@ -74,7 +77,7 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.k8sVersion, func(t *testing.T) {
-			id, err := getDefaultImageSKUID(test.k8sVersion)
+			id, err := getDefaultImageSKUID(test.k8sVersion, "ubuntu-1804")
 
 			if test.expectedError {
 				g.Expect(err).To(HaveOccurred())
@@ -85,3 +88,98 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDefaultWindowsImage(t *testing.T) {
+	g := NewWithT(t)
+
+	image, err := GetDefaultWindowsImage("v1.19.1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(image.Marketplace).To(Equal(&infrav1.AzureMarketplaceImage{
+		Publisher: "cncf-upstream",
+		Offer:     "capi-windows",
+		SKU:       "k8s-1dot19dot1-windows-2019",
+		Version:   "latest",
+	}))
+
+	_, err = GetDefaultWindowsImage("1.1.notvalid.semver")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGenerateResourceName(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		base     string
+		suffix   string
+		expected string
+	}{
+		{
+			name:     "short name",
+			prefix:   "pip-",
+			base:     "my-cluster",
+			suffix:   "-node-outbound",
+			expected: "pip-my-cluster-node-outbound",
+		},
+		{
+			name:     "name of the maximum length",
+			base:     strings.Repeat("a", 70),
+			suffix:   "-public-lb",
+			expected: strings.Repeat("a", 70) + "-public-lb",
+		},
+		{
+			name:     "long name",
+			prefix:   "pip-",
+			base:     strings.Repeat("a", 80),
+			suffix:   "-node-outbound",
+			expected: "pip-" + strings.Repeat("a", 53) + "-4e5c0937-node-outbound",
+		},
+		{
+			name:     "long name truncated after a dash",
+			base:     strings.Repeat("a", 60) + "-" + strings.Repeat("b", 20),
+			suffix:   "-public-lb",
+			expected: strings.Repeat("a", 60) + "-dded7ec7-public-lb",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			name := GenerateResourceName(tc.prefix, tc.base, tc.suffix, MaxResourceNameLength)
+			g.Expect(name).To(Equal(tc.expected))
+			g.Expect(len(name)).To(BeNumerically("<=", MaxResourceNameLength))
+		})
+	}
+}
+
+func TestGenerateNamesForLongClusterNames(t *testing.T) {
+	g := NewWithT(t)
+	clusterName := strings.Repeat("my-long-cluster-name-", 5)
+	otherClusterName := clusterName + "2"
+
+	generators := map[string]func(clusterName string) string{
+		"internal LB":                 GenerateInternalLBName,
+		"public LB":                   GeneratePublicLBName,
+		"control plane outbound LB":   GenerateControlPlaneOutboundLBName,
+		"control plane outbound IP":   GenerateControlPlaneOutboundIPName,
+		"node outbound IP":            GenerateNodeOutboundIPName,
+		"node outbound IP prefix":     GenerateNodeOutboundIPPrefixName,
+		"bastion":                     GenerateBastionName,
+		"private link service":        GeneratePrivateLinkServiceName,
+		"application gateway":         GenerateApplicationGatewayName,
+		"proximity placement group":   GenerateProximityPlacementGroupName,
+		"node availability set":       func(clusterName string) string { return GenerateAvailabilitySetName(clusterName, infrav1.Node) },
+		"API server public IP":        func(clusterName string) string { return GeneratePublicIPName(clusterName, "e3b0c442") },
+		"additional node outbound IP": func(clusterName string) string { return GenerateNodeOutboundIPNames(clusterName, 2)[1] },
+	}
+	for resource, generate := range generators {
+		name := generate(clusterName)
+		g.Expect(len(name)).To(BeNumerically("<=", MaxResourceNameLength), "name of the %s", resource)
+		g.Expect(generate(otherClusterName)).NotTo(Equal(name), "name of the %s", resource)
+		g.Expect(generate(clusterName)).To(Equal(name), "name of the %s", resource)
+	}
+
+	zoneName := GenerateDefaultPrivateDNSZoneName(clusterName)
+	g.Expect(zoneName).To(HaveSuffix(".capz.io"))
+	g.Expect(len(strings.TrimSuffix(zoneName, ".capz.io"))).To(BeNumerically("<=", MaxDNSLabelLength))
+	g.Expect(GenerateDefaultPrivateDNSZoneName(otherClusterName)).NotTo(Equal(zoneName))
+}
@@ -18,8 +18,10 @@ package azure
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/Azure/go-autorest/autorest"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 )
 
 // ResourceNotFound parses the error to check if it's a resource not found
@@ -27,3 +29,42 @@ func ResourceNotFound(err error) bool {
 	derr := autorest.DetailedError{}
 	return errors.As(err, &derr) && derr.StatusCode == 404
 }
+
+// ResourceForbidden parses the error to check if the access to the resource is denied
+func ResourceForbidden(err error) bool {
+	derr := autorest.DetailedError{}
+	return errors.As(err, &derr) && derr.StatusCode == 403
+}
+
+// ResourceConflict parses the error to check if the operation conflicts with the state of the resource, for example
+// when it is still referenced by another resource being deleted.
+func ResourceConflict(err error) bool {
+	derr := autorest.DetailedError{}
+	return errors.As(err, &derr) && derr.StatusCode == 409
+}
+
+// OperationNotDoneError is returned while a long-running operation on an Azure resource is in progress.
+type OperationNotDoneError struct {
+	Future *infrav1.Future
+}
+
+// NewOperationNotDoneError returns an error reporting the long-running operation isn't done.
+func NewOperationNotDoneError(future *infrav1.Future) OperationNotDoneError {
+	return OperationNotDoneError{Future: future}
+}
+
+func (e OperationNotDoneError) Error() string {
+	msg := fmt.Sprintf("operation type %s on Azure resource %s/%s is not done", e.Future.Type, e.Future.ResourceGroup, e.Future.Name)
+	if e.Future.State != "" {
+		msg += fmt.Sprintf(", state %s", e.Future.State)
+	}
+	if e.Future.PercentComplete != nil {
+		msg += fmt.Sprintf(", %d%% complete", *e.Future.PercentComplete)
+	}
+	return msg
+}
+
+// IsOperationNotDoneError returns true if the error reports a long-running operation in progress.
+func IsOperationNotDoneError(err error) bool {
+	return errors.As(err, &OperationNotDoneError{})
+}
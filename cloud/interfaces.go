@@ -63,10 +63,35 @@ type Authorizer interface {
 type ClusterDescriber interface {
 	Authorizer
 	ResourceGroup() string
+	IsResourceGroupManaged() bool
+	NetworkResourceGroup() string
+	IsNetworkResourceGroupManaged() bool
 	ClusterName() string
 	Location() string
 	AdditionalTags() infrav1.Tags
+	LastAppliedTags() infrav1.Tags
 	Vnet() *infrav1.VnetSpec
+	Subnets() infrav1.Subnets
 	NodeSubnet() *infrav1.SubnetSpec
+	NodeSubnets() infrav1.Subnets
 	ControlPlaneSubnet() *infrav1.SubnetSpec
+	ControlPlaneSubnets() infrav1.Subnets
+	ControlPlaneSubnetForZone(zone string) *infrav1.SubnetSpec
+	IsAPIServerPrivate() bool
+	InternalLBInboundNatRules() []infrav1.InboundNatRule
+	ApplicationGatewayName() string
+	ControlPlaneOutboundLBName() string
+	NodeOutboundLBName() string
+	AcceleratedNetworking() *bool
+	DiskEncryptionSetID() string
+	DefaultImage() *infrav1.Image
+	EnforcedTags() infrav1.Tags
+}
+
+// FutureScope is an interface which can save and get the states of the long-running operations on Azure resources,
+// so they are polled across reconciles.
+type FutureScope interface {
+	GetLongRunningOperationState(name, service string) *infrav1.Future
+	SetLongRunningOperationState(future *infrav1.Future)
+	DeleteLongRunningOperationState(name, service string)
 }
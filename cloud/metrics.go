@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// apiRequests counts the Azure API requests by resource type, method and HTTP status code of the response.
+	apiRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "capz_azure_api_requests_total",
+			Help: "Number of Azure API requests, by resource type, method and HTTP status code, or error when no response was received.",
+		},
+		[]string{"resource_type", "method", "code"},
+	)
+
+	// apiRequestDuration observes the latency of the Azure API requests by resource type, method and HTTP status code.
+	apiRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "capz_azure_api_request_duration_seconds",
+			Help:    "Latency of the Azure API requests, by resource type, method and HTTP status code, or error when no response was received.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+		},
+		[]string{"resource_type", "method", "code"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestDuration)
+}
+
+// RecordAPIMetrics returns a SendDecorator which records the count and the latency of the requests in the
+// capz_azure_api_requests_total and capz_azure_api_request_duration_seconds metrics. Every attempt of a retried
+// request is recorded.
+func RecordAPIMetrics() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := s.Do(r)
+			code := "error"
+			if resp != nil {
+				code = strconv.Itoa(resp.StatusCode)
+			}
+			resourceType := apiResourceType(r.URL.Path)
+			apiRequests.WithLabelValues(resourceType, r.Method, code).Inc()
+			apiRequestDuration.WithLabelValues(resourceType, r.Method, code).Observe(time.Since(start).Seconds())
+			return resp, err
+		})
+	}
+}
+
+// apiResourceType returns the type of the Azure resource of a request path, e.g. microsoft.network/virtualnetworks/subnets
+// for a subnet. The names of the resources are left out, so the metrics have a bounded number of label values.
+func apiResourceType(path string) string {
+	segments := strings.Split(strings.Trim(strings.ToLower(path), "/"), "/")
+	start := 0
+	for i, segment := range segments {
+		if segment == "providers" && i+1 < len(segments) {
+			start = i + 1
+		}
+	}
+	var types []string
+	if start > 0 {
+		// the namespace of the resource provider is followed by alternating resource types and names
+		types = append(types, segments[start])
+		start++
+	} else if len(segments) > 0 && segments[0] == "subscriptions" {
+		start = 2
+	}
+	for i := start; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	if len(types) == 0 {
+		return "subscriptions"
+	}
+	return strings.Join(types, "/")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAPIResourceType(t *testing.T) {
+	testcases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "resource",
+			path:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb",
+			expected: "microsoft.network/loadbalancers",
+		},
+		{
+			name:     "child resource",
+			path:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+			expected: "microsoft.network/virtualnetworks/subnets",
+		},
+		{
+			name:     "list of resources",
+			path:     "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines",
+			expected: "microsoft.compute/virtualmachines",
+		},
+		{
+			name:     "status of an asynchronous operation",
+			path:     "/subscriptions/123/providers/Microsoft.Network/locations/westus2/operations/abc",
+			expected: "microsoft.network/locations/operations",
+		},
+		{
+			name:     "resource group",
+			path:     "/subscriptions/123/resourcegroups/my-rg",
+			expected: "resourcegroups",
+		},
+		{
+			name:     "subscription",
+			path:     "/subscriptions/123",
+			expected: "subscriptions",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(apiResourceType(tc.path)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRecordAPIMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	path := "https://management.azure.com/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-rt"
+	statusCodes := []int{http.StatusOK, http.StatusNotFound}
+	attempts := 0
+	sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		defer func() { attempts++ }()
+		if attempts == len(statusCodes) {
+			return nil, errors.New("connection reset")
+		}
+		return &http.Response{
+			StatusCode: statusCodes[attempts],
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Request:    r,
+		}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, _ = autorest.SendWithSender(sender, req, RecordAPIMetrics())
+	}
+
+	for _, code := range []string{"200", "404", "error"} {
+		g.Expect(testutil.ToFloat64(apiRequests.WithLabelValues("microsoft.network/routetables", http.MethodGet, code))).To(Equal(1.0))
+	}
+}
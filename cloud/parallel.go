@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// DefaultMaxConcurrentOperations caps the number of Azure operations a reconcile runs concurrently, so that creating
+// the resources of a cluster doesn't trigger the throttling of Azure Resource Manager.
+const DefaultMaxConcurrentOperations = 4
+
+// ForEachParallel calls fn for each index in [0, n), running at most maxConcurrency calls at once. It waits for all
+// the calls to return, and returns the aggregate of their errors in index order. fn must only be used for independent
+// operations: the order in which the calls start isn't guaranteed.
+func ForEachParallel(n, maxConcurrency int, fn func(i int) error) error {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	errs := make([]error, n)
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return kerrors.NewAggregate(errs)
+}
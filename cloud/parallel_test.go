@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestForEachParallel(t *testing.T) {
+	testcases := []struct {
+		name           string
+		n              int
+		maxConcurrency int
+		failing        map[int]bool
+		expectedError  string
+	}{
+		{
+			name:           "no operation",
+			n:              0,
+			maxConcurrency: 4,
+		},
+		{
+			name:           "more operations than the concurrency cap",
+			n:              10,
+			maxConcurrency: 3,
+		},
+		{
+			name:           "concurrency cap below one runs the operations one at a time",
+			n:              3,
+			maxConcurrency: 0,
+		},
+		{
+			name:           "errors of all the operations are returned in order",
+			n:              5,
+			maxConcurrency: 2,
+			failing:        map[int]bool{3: true, 1: true},
+			expectedError:  "[operation 1 failed, operation 3 failed]",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var running, maxRunning, calls int32
+			err := ForEachParallel(tc.n, tc.maxConcurrency, func(i int) error {
+				atomic.AddInt32(&calls, 1)
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				if tc.failing[i] {
+					return errors.Errorf("operation %d failed", i)
+				}
+				return nil
+			})
+
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(calls).To(Equal(int32(tc.n)))
+			expectedMax := int32(tc.maxConcurrency)
+			if expectedMax < 1 {
+				expectedMax = 1
+			}
+			g.Expect(maxRunning).To(BeNumerically("<=", expectedMax))
+		})
+	}
+}
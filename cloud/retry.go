@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DefaultThrottlingRetryDelay is the delay before the first retry of a throttled request without a Retry-After header.
+	// It doubles at each retry.
+	DefaultThrottlingRetryDelay = 5 * time.Second
+	// DefaultThrottlingMaxRetryDelay caps the delay between two retries of a throttled request without a Retry-After header.
+	DefaultThrottlingMaxRetryDelay = time.Minute
+	// DefaultThrottlingMaxRetryDuration caps the total time spent retrying a throttled request, after which
+	// the throttling error is returned so the reconcile is requeued instead of hanging.
+	DefaultThrottlingMaxRetryDuration = 3 * time.Minute
+	// DefaultConflictMaxRetries is the number of times an operation failing with a 409 Conflict response is retried,
+	// for example a deletion failing while a dependent resource is still being deleted.
+	DefaultConflictMaxRetries = 3
+	// DefaultConflictRetryDelay is the delay before retrying an operation failing with a 409 Conflict response.
+	DefaultConflictRetryDelay = 10 * time.Second
+)
+
+// throttledRequests counts the Azure API requests throttled with a 429 Too Many Requests response.
+var throttledRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "capz_azure_throttled_requests_total",
+		Help: "Number of Azure API requests throttled with a 429 Too Many Requests response.",
+	},
+	[]string{"host", "method"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(throttledRequests)
+}
+
+// DoRetryForThrottling returns a SendDecorator which retries the requests throttled with a 429 Too Many Requests
+// response. It waits for the duration of the Retry-After header of the response, or for an exponential backoff
+// with jitter starting at delay and capped at maxDelay when there is none. It stops retrying when the next
+// attempt would happen after maxDuration, and returns the last response.
+func DoRetryForThrottling(delay, maxDelay, maxDuration time.Duration) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			rr := autorest.NewRetriableRequest(r)
+			deadline := time.Now().Add(maxDuration)
+			for attempt := 0; ; attempt++ {
+				if err := rr.Prepare(); err != nil {
+					return nil, err
+				}
+				resp, err := s.Do(rr.Request())
+				if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+					return resp, err
+				}
+				throttledRequests.WithLabelValues(r.URL.Host, r.Method).Inc()
+				wait := throttlingDelay(resp, delay, maxDelay, attempt)
+				if time.Now().Add(wait).After(deadline) {
+					klog.Warningf("Azure API request %s %s is still throttled after %d retries, giving up", r.Method, r.URL.Path, attempt)
+					return resp, nil
+				}
+				klog.Warningf("Azure API request %s %s was throttled, retrying in %s", r.Method, r.URL.Path, wait)
+				select {
+				case <-time.After(wait):
+				case <-r.Context().Done():
+					return resp, r.Context().Err()
+				}
+				// the throttled response is discarded before retrying
+				_ = autorest.Respond(resp, autorest.ByDiscardingBody(), autorest.ByClosing())
+			}
+		})
+	}
+}
+
+// throttlingDelay returns the delay before retrying a throttled request. The Retry-After header of the response
+// is honored when present, in seconds or as an HTTP date.
+func throttlingDelay(resp *http.Response, delay, maxDelay time.Duration, attempt int) time.Duration {
+	if retryAfter := resp.Header.Get(autorest.HeaderRetryAfter); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			if d := time.Until(date); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	backoff := delay
+	for i := 0; i < attempt && backoff < maxDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > maxDelay {
+		backoff = maxDelay
+	}
+	// the jitter spreads the retries of concurrent reconciles, so they don't all hit the API again at once
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// RetryOnConflict calls fn, and calls it again up to maxRetries times while it fails with a 409 Conflict response,
+// waiting for delay between the calls. It returns the error of the last call.
+func RetryOnConflict(ctx context.Context, maxRetries int, delay time.Duration, fn func() error) error {
+	err := fn()
+	for retry := 0; retry < maxRetries && ResourceConflict(err); retry++ {
+		klog.Warningf("Azure operation conflicts with the state of the resource, retrying in %s: %v", delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		err = fn()
+	}
+	return err
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/gomega"
+)
+
+func TestDoRetryForThrottling(t *testing.T) {
+	testcases := []struct {
+		name             string
+		statusCodes      []int
+		retryAfter       string
+		maxDuration      time.Duration
+		expectedStatus   int
+		expectedAttempts int
+	}{
+		{
+			name:             "request is not throttled",
+			statusCodes:      []int{http.StatusOK},
+			maxDuration:      time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "throttled request is retried until it succeeds",
+			statusCodes:      []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			maxDuration:      time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "throttled request honors the Retry-After header",
+			statusCodes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:       "0",
+			maxDuration:      time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "throttled request is not retried past the max duration",
+			statusCodes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:       "10",
+			maxDuration:      time.Second,
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "other errors are not retried",
+			statusCodes:      []int{http.StatusInternalServerError, http.StatusOK},
+			maxDuration:      time.Second,
+			expectedStatus:   http.StatusInternalServerError,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			attempts := 0
+			sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+				body, err := ioutil.ReadAll(r.Body)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(body)).To(Equal("payload"))
+				resp := &http.Response{
+					StatusCode: tc.statusCodes[attempts],
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+					Request:    r,
+				}
+				if tc.retryAfter != "" {
+					resp.Header.Set(autorest.HeaderRetryAfter, tc.retryAfter)
+				}
+				attempts++
+				return resp, nil
+			})
+
+			req, err := http.NewRequest(http.MethodPut, "https://management.azure.com/subscriptions/123", strings.NewReader("payload"))
+			g.Expect(err).NotTo(HaveOccurred())
+			resp, err := autorest.SendWithSender(sender, req, DoRetryForThrottling(time.Millisecond, 10*time.Millisecond, tc.maxDuration))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resp.StatusCode).To(Equal(tc.expectedStatus))
+			g.Expect(attempts).To(Equal(tc.expectedAttempts))
+		})
+	}
+}
+
+func TestThrottlingDelay(t *testing.T) {
+	testcases := []struct {
+		name       string
+		retryAfter string
+		attempt    int
+		min        time.Duration
+		max        time.Duration
+	}{
+		{
+			name:    "first retry",
+			attempt: 0,
+			min:     5 * time.Second,
+			max:     10 * time.Second,
+		},
+		{
+			name:    "backoff grows exponentially",
+			attempt: 2,
+			min:     20 * time.Second,
+			max:     40 * time.Second,
+		},
+		{
+			name:    "backoff is capped",
+			attempt: 100,
+			min:     30 * time.Second,
+			max:     time.Minute,
+		},
+		{
+			name:       "Retry-After in seconds",
+			retryAfter: "120",
+			attempt:    3,
+			min:        2 * time.Minute,
+			max:        2 * time.Minute,
+		},
+		{
+			name:       "Retry-After as a date in the past",
+			retryAfter: "Mon, 02 Jan 2006 15:04:05 GMT",
+			min:        0,
+			max:        0,
+		},
+		{
+			name:       "malformed Retry-After",
+			retryAfter: "soon",
+			attempt:    0,
+			min:        5 * time.Second,
+			max:        10 * time.Second,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resp := &http.Response{Header: http.Header{}}
+			if tc.retryAfter != "" {
+				resp.Header.Set(autorest.HeaderRetryAfter, tc.retryAfter)
+			}
+			delay := throttlingDelay(resp, 10*time.Second, time.Minute, tc.attempt)
+			g.Expect(delay).To(BeNumerically(">=", tc.min))
+			g.Expect(delay).To(BeNumerically("<=", tc.max))
+		})
+	}
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	conflict := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusConflict}, "Conflict")
+	internalError := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: http.StatusInternalServerError}, "Internal Server Error")
+
+	testcases := []struct {
+		name          string
+		errs          []error
+		expectedError error
+		expectedCalls int
+	}{
+		{
+			name:          "operation succeeds",
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:          "conflicting operation is retried until it succeeds",
+			errs:          []error{conflict, conflict, nil},
+			expectedCalls: 3,
+		},
+		{
+			name:          "other errors are not retried",
+			errs:          []error{internalError},
+			expectedError: internalError,
+			expectedCalls: 1,
+		},
+		{
+			name:          "conflict is returned once the retries are exhausted",
+			errs:          []error{conflict, conflict, conflict, conflict, nil},
+			expectedError: conflict,
+			expectedCalls: 4,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			calls := 0
+			err := RetryOnConflict(context.TODO(), 3, time.Millisecond, func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+			if tc.expectedError != nil {
+				g.Expect(err).To(Equal(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(calls).To(Equal(tc.expectedCalls))
+		})
+	}
+}
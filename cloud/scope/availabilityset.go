@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+// AvailabilitySetSpecs returns the availability sets of the control plane and node machines of the cluster, or nil
+// if the cluster uses availability zones.
+func (s *ClusterScope) AvailabilitySetSpecs() []azure.AvailabilitySetSpec {
+	var specs []azure.AvailabilitySetSpec
+	for _, role := range []string{infrav1.ControlPlane, infrav1.Node} {
+		if spec := s.AvailabilitySetSpec(role); spec != nil {
+			specs = append(specs, *spec)
+		}
+	}
+	return specs
+}
+
+// AvailabilitySetSpec returns the availability set of the machines of a role, or nil if the cluster uses availability
+// zones. A cluster in a location without availability zones has an availability set per role, each one surfaced as a
+// failure domain of the cluster.
+func (s *ClusterScope) AvailabilitySetSpec(role string) *azure.AvailabilitySetSpec {
+	name := azure.GenerateAvailabilitySetName(s.ClusterName(), role)
+	if _, ok := s.AzureCluster.Status.FailureDomains[name]; !ok {
+		return nil
+	}
+	return &azure.AvailabilitySetSpec{
+		Name: name,
+		ID: fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/availabilitySets/%s",
+			s.SubscriptionID(), s.ResourceGroup(), name),
+		Role: role,
+	}
+}
+
+// SetAvailabilitySetFailureDomains sets the availability sets of the control plane and node machines as the failure
+// domains of a cluster in a location without availability zones.
+func (s *ClusterScope) SetAvailabilitySetFailureDomains() {
+	for _, role := range []string{infrav1.ControlPlane, infrav1.Node} {
+		s.SetFailureDomain(azure.GenerateAvailabilitySetName(s.ClusterName(), role), clusterv1.FailureDomainSpec{
+			ControlPlane: role == infrav1.ControlPlane,
+			Attributes: map[string]string{
+				infrav1.AvailabilitySetAttribute: "true",
+			},
+		})
+	}
+}
+
+// ValidateAvailabilitySet checks that the machine VM can be placed in the availability set of its role: Azure doesn't
+// allow a VM in an availability set to be in an availability zone, and a machine can't be in the availability set of
+// another role.
+func (m *MachineScope) ValidateAvailabilitySet(as *azure.AvailabilitySetSpec) error {
+	if as == nil {
+		return nil
+	}
+	for _, failureDomain := range []*string{m.Machine.Spec.FailureDomain, m.AzureMachine.Spec.FailureDomain, m.AzureMachine.Spec.AvailabilityZone.ID} {
+		switch {
+		case failureDomain == nil || *failureDomain == "" || *failureDomain == as.Name:
+		case m.isAvailabilitySet(*failureDomain):
+			return errors.Errorf("%s machine %s can't be in availability set %s", as.Role, m.Name(), *failureDomain)
+		default:
+			return errors.Errorf("machine %s in availability zone %s can't be in availability set %s", m.Name(), *failureDomain, as.Name)
+		}
+	}
+	return nil
+}
+
+// isAvailabilitySet returns true if a failure domain is one of the availability sets of the cluster.
+func (m *MachineScope) isAvailabilitySet(failureDomain string) bool {
+	return failureDomain == azure.GenerateAvailabilitySetName(m.ClusterName(), infrav1.ControlPlane) ||
+		failureDomain == azure.GenerateAvailabilitySetName(m.ClusterName(), infrav1.Node)
+}
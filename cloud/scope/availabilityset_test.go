@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+var testAvailabilitySetNetworkSpec = infrav1.NetworkSpec{
+	Subnets: infrav1.Subnets{
+		{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+		{Name: "node-subnet", Role: infrav1.SubnetNode},
+	},
+}
+
+func TestAvailabilitySetSpecs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, testAvailabilitySetNetworkSpec)
+	s.SetFailureDomain("1", clusterv1.FailureDomainSpec{ControlPlane: true})
+	g.Expect(s.AvailabilitySetSpecs()).To(BeEmpty())
+	g.Expect(s.AvailabilitySetSpec(infrav1.ControlPlane)).To(BeNil())
+
+	s.AzureCluster.Status.FailureDomains = nil
+	s.SetAvailabilitySetFailureDomains()
+	g.Expect(s.AzureCluster.Status.FailureDomains).To(Equal(clusterv1.FailureDomains{
+		"my-cluster-control-plane-as": clusterv1.FailureDomainSpec{
+			ControlPlane: true,
+			Attributes:   map[string]string{infrav1.AvailabilitySetAttribute: "true"},
+		},
+		"my-cluster-node-as": clusterv1.FailureDomainSpec{
+			Attributes: map[string]string{infrav1.AvailabilitySetAttribute: "true"},
+		},
+	}))
+	g.Expect(s.AvailabilitySetSpecs()).To(Equal([]azure.AvailabilitySetSpec{
+		{
+			Name: "my-cluster-control-plane-as",
+			ID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/availabilitySets/my-cluster-control-plane-as",
+			Role: infrav1.ControlPlane,
+		},
+		{
+			Name: "my-cluster-node-as",
+			ID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/availabilitySets/my-cluster-node-as",
+			Role: infrav1.Node,
+		},
+	}))
+}
+
+func TestValidateAvailabilitySet(t *testing.T) {
+	controlPlaneAS := &azure.AvailabilitySetSpec{Name: "my-cluster-control-plane-as", Role: infrav1.ControlPlane}
+
+	testcases := []struct {
+		name                      string
+		as                        *azure.AvailabilitySetSpec
+		failureDomain             *string
+		azureMachineFailureDomain *string
+		availabilityZoneID        *string
+		expectedZone              string
+		expectedError             string
+	}{
+		{
+			name:          "no availability set",
+			failureDomain: to.StringPtr("1"),
+			expectedZone:  "1",
+		},
+		{
+			name: "machine without failure domain",
+			as:   controlPlaneAS,
+		},
+		{
+			name:          "machine in the failure domain of its availability set",
+			as:            controlPlaneAS,
+			failureDomain: to.StringPtr("my-cluster-control-plane-as"),
+		},
+		{
+			name:          "machine in the availability set of another role",
+			as:            controlPlaneAS,
+			failureDomain: to.StringPtr("my-cluster-node-as"),
+			expectedError: "control-plane machine my-machine can't be in availability set my-cluster-node-as",
+		},
+		{
+			name:                      "machine in an availability zone",
+			as:                        controlPlaneAS,
+			failureDomain:             to.StringPtr("my-cluster-control-plane-as"),
+			azureMachineFailureDomain: to.StringPtr("2"),
+			expectedError:             "machine my-machine in availability zone 2 can't be in availability set my-cluster-control-plane-as",
+		},
+		{
+			name:               "machine in a deprecated availability zone",
+			as:                 controlPlaneAS,
+			availabilityZoneID: to.StringPtr("3"),
+			expectedZone:       "3",
+			expectedError:      "machine my-machine in availability zone 3 can't be in availability set my-cluster-control-plane-as",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachineScope{
+				ClusterDescriber: newTestClusterScope(t, testAvailabilitySetNetworkSpec),
+				Machine:          &clusterv1.Machine{Spec: clusterv1.MachineSpec{FailureDomain: tc.failureDomain}},
+				AzureMachine: &infrav1.AzureMachine{Spec: infrav1.AzureMachineSpec{
+					FailureDomain:    tc.azureMachineFailureDomain,
+					AvailabilityZone: infrav1.AvailabilityZone{ID: tc.availabilityZoneID},
+				}},
+			}
+			m.AzureMachine.Name = "my-machine"
+
+			// an availability set isn't an availability zone
+			if tc.azureMachineFailureDomain == nil {
+				g.Expect(m.AvailabilityZone()).To(Equal(tc.expectedZone))
+			}
+
+			err := m.ValidateAvailabilitySet(tc.as)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// maxCustomDataBytes is the maximum size of the custom data of a VM or a scale set, before base64 encoding.
+const maxCustomDataBytes = 65535
+
+// encodeCustomData returns the base64 encoded custom data of a VM or a scale set for its bootstrap data. Bootstrap
+// data larger than the custom data limit of Azure is gzip compressed, which cloud-init and cloudbase-init detect and
+// decompress. Bootstrap data still too large once compressed is an error.
+func encodeCustomData(log logr.Logger, bootstrapData []byte) (string, error) {
+	if len(bootstrapData) <= maxCustomDataBytes {
+		log.V(2).Info("Using the bootstrap data as custom data", "size", len(bootstrapData))
+		return base64.StdEncoding.EncodeToString(bootstrapData), nil
+	}
+
+	var compressed bytes.Buffer
+	w, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compress the bootstrap data")
+	}
+	if _, err := w.Write(bootstrapData); err != nil {
+		return "", errors.Wrap(err, "failed to compress the bootstrap data")
+	}
+	if err := w.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to compress the bootstrap data")
+	}
+	if compressed.Len() > maxCustomDataBytes {
+		return "", errors.Errorf("bootstrap data of %d bytes is %d bytes once compressed, larger than the %d bytes of custom data Azure accepts",
+			len(bootstrapData), compressed.Len(), maxCustomDataBytes)
+	}
+
+	log.Info("Compressed the bootstrap data larger than the custom data limit", "size", len(bootstrapData), "compressedSize", compressed.Len())
+	return base64.StdEncoding.EncodeToString(compressed.Bytes()), nil
+}
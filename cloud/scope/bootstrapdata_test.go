@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+)
+
+func TestEncodeCustomData(t *testing.T) {
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		if _, err := rand.Read(b); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name           string
+		bootstrapData  []byte
+		expectCompress bool
+		expectedError  string
+	}{
+		{
+			name:          "bootstrap data within the limit",
+			bootstrapData: bytes.Repeat([]byte("#cloud-config\n"), 100),
+		},
+		{
+			name:          "bootstrap data of the limit",
+			bootstrapData: random(maxCustomDataBytes),
+		},
+		{
+			name:           "compressible bootstrap data over the limit",
+			bootstrapData:  bytes.Repeat([]byte("#cloud-config\n"), 10000),
+			expectCompress: true,
+		},
+		{
+			name:          "incompressible bootstrap data over the limit",
+			bootstrapData: random(maxCustomDataBytes + 1),
+			expectedError: "bootstrap data of 65536 bytes is",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			encoded, err := encodeCustomData(klogr.New(), tc.bootstrapData)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(HavePrefix(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			customData, err := base64.StdEncoding.DecodeString(encoded)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(len(customData)).To(BeNumerically("<=", maxCustomDataBytes))
+			if !tc.expectCompress {
+				g.Expect(customData).To(Equal(tc.bootstrapData))
+				return
+			}
+			r, err := gzip.NewReader(bytes.NewReader(customData))
+			g.Expect(err).NotTo(HaveOccurred())
+			decompressed, err := ioutil.ReadAll(r)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(decompressed).To(Equal(tc.bootstrapData))
+		})
+	}
+}
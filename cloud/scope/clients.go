@@ -17,11 +17,20 @@ limitations under the License.
 package scope
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
 	"os"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/pkg/errors"
+	"k8s.io/klog"
 )
 
 const (
@@ -35,29 +44,244 @@ const (
 	USGovernmentCloud = "AzureUSGovernmentCloud"
 )
 
+// IdentityType is the type of identity used to authenticate against Azure.
+type IdentityType string
+
+const (
+	// ServicePrincipalIdentity authenticates with the service principal credentials from the environment.
+	ServicePrincipalIdentity IdentityType = "ServicePrincipal"
+	// ManagedIdentity authenticates with the managed identity of the VM the controller runs on,
+	// through the instance metadata service (IMDS).
+	ManagedIdentity IdentityType = "ManagedIdentity"
+	// WorkloadIdentity authenticates by exchanging a federated service account token for an AAD token.
+	WorkloadIdentity IdentityType = "WorkloadIdentity"
+)
+
+const (
+	// IdentityTypeEnvVar is the environment variable selecting the identity type, ServicePrincipal by default.
+	IdentityTypeEnvVar = "AZURE_IDENTITY_TYPE"
+	// FederatedTokenFileEnvVar is the environment variable with the path of the projected service account token
+	// used by workload identity.
+	FederatedTokenFileEnvVar = "AZURE_FEDERATED_TOKEN_FILE"
+
+	// imdsTimeout bounds the check that the instance metadata service is reachable.
+	imdsTimeout = 2 * time.Second
+)
+
+// imdsEndpoint is the managed identity token endpoint of the instance metadata service.
+var imdsEndpoint, _ = adal.GetMSIVMEndpoint()
+
+// authorizers caches the authorizers built from the credentials of the environment, so tokens are reused and
+// refreshed across reconciles instead of being acquired on every reconcile.
+var authorizers = struct {
+	sync.Mutex
+	entries map[authorizerKey]cachedAuthorizer
+}{entries: make(map[authorizerKey]cachedAuthorizer)}
+
+// authorizerKey identifies the identity an authorizer authenticates as.
+type authorizerKey struct {
+	identityType   IdentityType
+	environment    string
+	subscriptionID string
+	tenantID       string
+	clientID       string
+}
+
+// cachedAuthorizer is an authorizer built from a given version of the credentials of an identity.
+type cachedAuthorizer struct {
+	credentialsHash string
+	authorizer      autorest.Authorizer
+}
+
 // AzureClients contains all the Azure clients used by the scopes.
 type AzureClients struct {
 	SubscriptionID             string
 	ResourceManagerEndpoint    string
 	ResourceManagerVMDNSSuffix string
 	Authorizer                 autorest.Authorizer
+
+	// EnvironmentName is the name of the Azure cloud environment of the clients, e.g. AzurePublicCloud.
+	EnvironmentName string
+	// TenantID is the AAD tenant of the identity of the clients.
+	TenantID string
+	// ClientID and ClientSecret are the credentials of the service principal of the clients, empty when they
+	// authenticate with a managed or workload identity.
+	ClientID     string
+	ClientSecret string
 }
 
-func (c *AzureClients) setCredentials(subscriptionID string) error {
+func (c *AzureClients) setCredentials(subscriptionID, environmentName string) error {
 	subID, err := getSubscriptionID(subscriptionID)
 	if err != nil {
 		return err
 	}
 	c.SubscriptionID = subID
-	settings, err := auth.GetSettingsFromEnvironment()
+	settings, err := getSettings(environmentName)
 	if err != nil {
 		return err
 	}
 	c.ResourceManagerEndpoint = settings.Environment.ResourceManagerEndpoint
 	c.ResourceManagerVMDNSSuffix = GetAzureDNSZoneForEnvironment(settings.Environment.Name)
+	c.EnvironmentName = settings.Environment.Name
 	settings.Values[auth.SubscriptionID] = subscriptionID
-	c.Authorizer, err = settings.GetAuthorizer()
-	return err
+	identityType, err := GetIdentityType()
+	if err != nil {
+		return err
+	}
+	c.TenantID = settings.Values[auth.TenantID]
+	if identityType == ServicePrincipalIdentity {
+		c.ClientID = settings.Values[auth.ClientID]
+		c.ClientSecret = settings.Values[auth.ClientSecret]
+	}
+
+	key := authorizerKey{
+		identityType:   identityType,
+		environment:    settings.Environment.Name,
+		subscriptionID: subID,
+		tenantID:       settings.Values[auth.TenantID],
+		clientID:       settings.Values[auth.ClientID],
+	}
+	hash := credentialsHash(settings)
+	authorizers.Lock()
+	cached, ok := authorizers.entries[key]
+	authorizers.Unlock()
+	// the token is checked outside of the lock, so a slow token refresh doesn't block the reconciles of other clusters
+	if ok && cached.credentialsHash == hash && isAuthorizerValid(cached.authorizer) {
+		c.Authorizer = cached.authorizer
+		return nil
+	}
+
+	var authorizer autorest.Authorizer
+	switch identityType {
+	case ManagedIdentity:
+		authorizer, err = getManagedIdentityAuthorizer(settings)
+	case WorkloadIdentity:
+		authorizer, err = getWorkloadIdentityAuthorizer(settings)
+	default:
+		authorizer, err = settings.GetAuthorizer()
+	}
+	if err != nil {
+		return err
+	}
+	authorizers.Lock()
+	authorizers.entries[key] = cachedAuthorizer{
+		credentialsHash: hash,
+		authorizer:      authorizer,
+	}
+	authorizers.Unlock()
+	c.Authorizer = authorizer
+	return nil
+}
+
+// credentialsHash returns a hash of the authentication settings, to tell when the credentials of an identity change
+// without keeping the secrets in the cache.
+func credentialsHash(settings auth.EnvironmentSettings) string {
+	keys := make([]string, 0, len(settings.Values))
+	for k := range settings.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, settings.Values[k])
+	}
+	fmt.Fprintf(h, "%s=%s\n", FederatedTokenFileEnvVar, os.Getenv(FederatedTokenFileEnvVar))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// isAuthorizerValid returns false when the token of a cached authorizer has expired and cannot be refreshed,
+// for example because its credentials were revoked, so that the authorizer is built again.
+// Tokens that were never acquired are acquired on the first request, as for a new authorizer. Refreshing a token is a
+// request to AAD, so it must not be called with the lock of an authorizer cache held.
+func isAuthorizerValid(authorizer autorest.Authorizer) bool {
+	bearer, ok := authorizer.(*autorest.BearerAuthorizer)
+	if !ok {
+		return true
+	}
+	token, ok := bearer.TokenProvider().(*adal.ServicePrincipalToken)
+	if !ok || token.Token().AccessToken == "" || !token.Token().IsExpired() {
+		return true
+	}
+	return token.EnsureFresh() == nil
+}
+
+// getSettings returns the authentication settings from the environment, for the given cloud environment
+// if set and the one in AZURE_ENVIRONMENT otherwise.
+func getSettings(environmentName string) (auth.EnvironmentSettings, error) {
+	settings, err := auth.GetSettingsFromEnvironment()
+	if err != nil || environmentName == "" {
+		return settings, err
+	}
+	environment, err := azure.EnvironmentFromName(environmentName)
+	if err != nil {
+		return settings, err
+	}
+	if settings.Values[auth.Resource] == settings.Environment.ResourceManagerEndpoint {
+		// the resource defaults to the ARM endpoint of the environment
+		settings.Values[auth.Resource] = environment.ResourceManagerEndpoint
+	}
+	settings.Values[auth.EnvironmentName] = environmentName
+	settings.Environment = environment
+	return settings, nil
+}
+
+// GetIdentityType returns the identity type selected by the AZURE_IDENTITY_TYPE environment variable.
+// When it is not set, workload identity is used if AZURE_FEDERATED_TOKEN_FILE is set, and a service principal otherwise.
+func GetIdentityType() (IdentityType, error) {
+	switch identityType := IdentityType(os.Getenv(IdentityTypeEnvVar)); identityType {
+	case "":
+		if os.Getenv(FederatedTokenFileEnvVar) != "" {
+			return WorkloadIdentity, nil
+		}
+		return ServicePrincipalIdentity, nil
+	case ServicePrincipalIdentity, ManagedIdentity, WorkloadIdentity:
+		return identityType, nil
+	default:
+		return "", errors.Errorf("invalid identity type %q in environment variable %s, must be one of %s, %s, %s",
+			identityType, IdentityTypeEnvVar, ServicePrincipalIdentity, ManagedIdentity, WorkloadIdentity)
+	}
+}
+
+// getManagedIdentityAuthorizer returns an authorizer using the managed identity of the VM, the user-assigned
+// identity set in AZURE_CLIENT_ID if any. It falls back to the service principal credentials when IMDS is unreachable.
+func getManagedIdentityAuthorizer(settings auth.EnvironmentSettings) (autorest.Authorizer, error) {
+	if !isIMDSReachable(imdsEndpoint) {
+		credentials, err := settings.GetClientCredentials()
+		if err != nil {
+			return nil, errors.Errorf("managed identity endpoint %s is unreachable and no service principal credentials are configured", imdsEndpoint)
+		}
+		klog.Warningf("Managed identity endpoint %s is unreachable, falling back to service principal credentials", imdsEndpoint)
+		return credentials.Authorizer()
+	}
+
+	msi := settings.GetMSI()
+	var token *adal.ServicePrincipalToken
+	var err error
+	if msi.ClientID == "" {
+		token, err = adal.NewServicePrincipalTokenFromMSI(imdsEndpoint, msi.Resource)
+	} else {
+		token, err = adal.NewServicePrincipalTokenFromMSIWithUserAssignedID(imdsEndpoint, msi.Resource, msi.ClientID)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get managed identity token")
+	}
+	return autorest.NewBearerAuthorizer(token), nil
+}
+
+// isIMDSReachable returns true if the instance metadata service answers at the given endpoint.
+func isIMDSReachable(endpoint string) bool {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Metadata", "true")
+	client := &http.Client{Timeout: imdsTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return true
 }
 
 func getSubscriptionID(subscriptionID string) (string, error) {
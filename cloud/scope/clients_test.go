@@ -17,10 +17,21 @@ limitations under the License.
 package scope
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 )
 
 func TestGettingEnvironment(t *testing.T) {
@@ -65,19 +76,211 @@ func TestGettingEnvironment(t *testing.T) {
 			expectedError:        true,
 			expectedErrorMessage: "There is no cloud environment matching the name \"AZUREINSPACE\"",
 		}}
-	for name, test := range tests {
+	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			os.Setenv("AZURE_ENVIRONMENT", test.azureEnv)
+			defer test.SetEnv("AZURE_ENVIRONMENT", tc.azureEnv)()
 			c := AzureClients{}
-			err := c.setCredentials("1234")
-			if test.expectedError {
+			err := c.setCredentials("1234", "")
+			if tc.expectedError {
 				g.Expect(err).To(HaveOccurred())
-				g.Expect(err.Error()).To(ContainSubstring(test.expectedErrorMessage))
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedErrorMessage))
 			} else {
 				g.Expect(err).ToNot(HaveOccurred())
-				g.Expect(c.ResourceManagerEndpoint).To(Equal(test.expectedEndpoint))
-				g.Expect(c.ResourceManagerVMDNSSuffix).To(Equal(test.expectedDNSSuffix))
+				g.Expect(c.ResourceManagerEndpoint).To(Equal(tc.expectedEndpoint))
+				g.Expect(c.ResourceManagerVMDNSSuffix).To(Equal(tc.expectedDNSSuffix))
 			}
 		})
 	}
 }
+
+func TestGetIdentityType(t *testing.T) {
+	g := NewWithT(t)
+
+	var tests = map[string]struct {
+		identityType       string
+		federatedTokenFile string
+		expected           IdentityType
+		expectedError      bool
+	}{
+		"AZURE_IDENTITY_TYPE is empty": {
+			identityType: "",
+			expected:     ServicePrincipalIdentity,
+		}, "AZURE_IDENTITY_TYPE is empty with a federated token": {
+			identityType:       "",
+			federatedTokenFile: "/var/run/secrets/azure/tokens/azure-identity-token",
+			expected:           WorkloadIdentity,
+		}, "AZURE_IDENTITY_TYPE is ServicePrincipal with a federated token": {
+			identityType:       "ServicePrincipal",
+			federatedTokenFile: "/var/run/secrets/azure/tokens/azure-identity-token",
+			expected:           ServicePrincipalIdentity,
+		}, "AZURE_IDENTITY_TYPE is ServicePrincipal": {
+			identityType: "ServicePrincipal",
+			expected:     ServicePrincipalIdentity,
+		}, "AZURE_IDENTITY_TYPE is ManagedIdentity": {
+			identityType: "ManagedIdentity",
+			expected:     ManagedIdentity,
+		}, "AZURE_IDENTITY_TYPE has an invalid value": {
+			identityType:  "Certificate",
+			expectedError: true,
+		}}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(IdentityTypeEnvVar, tc.identityType)
+			defer os.Unsetenv(IdentityTypeEnvVar)
+			os.Setenv(FederatedTokenFileEnvVar, tc.federatedTokenFile)
+			defer os.Unsetenv(FederatedTokenFileEnvVar)
+			identityType, err := GetIdentityType()
+			if tc.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(identityType).To(Equal(tc.expected))
+			}
+		})
+	}
+}
+
+func TestManagedIdentityCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer imds.Close()
+	defaultEndpoint := imdsEndpoint
+	defer func() { imdsEndpoint = defaultEndpoint }()
+
+	var tests = map[string]struct {
+		endpoint      string
+		clientSecret  string
+		expectedError bool
+	}{
+		"IMDS is reachable": {
+			endpoint: imds.URL,
+		}, "IMDS is unreachable, falls back to the service principal": {
+			endpoint:     "http://127.0.0.1:0/metadata/identity/oauth2/token",
+			clientSecret: "secret",
+		}, "IMDS is unreachable without service principal": {
+			endpoint:      "http://127.0.0.1:0/metadata/identity/oauth2/token",
+			expectedError: true,
+		}}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer test.SetEnv("AZURE_ENVIRONMENT", "AzurePublicCloud")()
+			os.Setenv(IdentityTypeEnvVar, string(ManagedIdentity))
+			defer os.Unsetenv(IdentityTypeEnvVar)
+			if tc.clientSecret != "" {
+				os.Setenv("AZURE_CLIENT_ID", "my-client")
+				os.Setenv("AZURE_CLIENT_SECRET", tc.clientSecret)
+				os.Setenv("AZURE_TENANT_ID", "my-tenant")
+				defer os.Unsetenv("AZURE_CLIENT_ID")
+				defer os.Unsetenv("AZURE_CLIENT_SECRET")
+				defer os.Unsetenv("AZURE_TENANT_ID")
+			}
+			imdsEndpoint = tc.endpoint
+			authorizers.entries = make(map[authorizerKey]cachedAuthorizer)
+
+			c := AzureClients{}
+			err := c.setCredentials("1234", "")
+			if tc.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(c.Authorizer).NotTo(BeNil())
+			}
+		})
+	}
+}
+
+func TestClusterAzureEnvironment(t *testing.T) {
+	g := NewWithT(t)
+
+	var tests = map[string]struct {
+		azureEnvironment    string
+		expectedEndpoint    string
+		expectedAADEndpoint string
+		expectedFQDN        string
+	}{
+		"AzurePublicCloud": {
+			azureEnvironment:    "AzurePublicCloud",
+			expectedEndpoint:    "https://management.azure.com/",
+			expectedAADEndpoint: "https://login.microsoftonline.com/",
+			expectedFQDN:        "my-cluster-api.westus2.cloudapp.azure.com",
+		}, "AzureUSGovernmentCloud": {
+			azureEnvironment:    "AzureUSGovernmentCloud",
+			expectedEndpoint:    "https://management.usgovcloudapi.net/",
+			expectedAADEndpoint: "https://login.microsoftonline.us/",
+			expectedFQDN:        "my-cluster-api.westus2.cloudapp.usgovcloudapi.net",
+		}, "AzureChinaCloud": {
+			azureEnvironment:    "AzureChinaCloud",
+			expectedEndpoint:    "https://management.chinacloudapi.cn/",
+			expectedAADEndpoint: "https://login.chinacloudapi.cn/",
+			expectedFQDN:        "my-cluster-api.westus2.cloudapp.chinacloudapi.cn",
+		}}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// the environment of the cluster takes precedence over the one of the controller
+			defer test.SetEnv("AZURE_ENVIRONMENT", "")()
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+			}
+			s, err := NewClusterScope(ClusterScopeParams{
+				Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						Location:         "westus2",
+						SubscriptionID:   "123",
+						AzureEnvironment: tc.azureEnvironment,
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
+						},
+					},
+					Status: infrav1.AzureClusterStatus{
+						Network: infrav1.Network{
+							APIServerIP: infrav1.PublicIP{Name: "my-cluster-api"},
+						},
+					},
+				},
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.BaseURI()).To(Equal(tc.expectedEndpoint))
+			g.Expect(s.GenerateFQDN()).To(Equal(tc.expectedFQDN))
+
+			settings, err := getSettings(tc.azureEnvironment)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(settings.Environment.ActiveDirectoryEndpoint).To(Equal(tc.expectedAADEndpoint))
+			g.Expect(settings.Values[auth.Resource]).To(Equal(tc.expectedEndpoint))
+		})
+	}
+}
+
+func TestCachedAuthorizer(t *testing.T) {
+	g := NewWithT(t)
+
+	defer test.SetEnv("AZURE_ENVIRONMENT", "AzurePublicCloud")()
+	os.Setenv("AZURE_CLIENT_ID", "my-client")
+	os.Setenv("AZURE_CLIENT_SECRET", "my-secret")
+	os.Setenv("AZURE_TENANT_ID", "my-tenant")
+	defer os.Unsetenv("AZURE_CLIENT_ID")
+	defer os.Unsetenv("AZURE_CLIENT_SECRET")
+	defer os.Unsetenv("AZURE_TENANT_ID")
+	authorizers.entries = make(map[authorizerKey]cachedAuthorizer)
+
+	newAuthorizer := func(subscriptionID string) autorest.Authorizer {
+		c := AzureClients{}
+		g.Expect(c.setCredentials(subscriptionID, "")).To(Succeed())
+		g.Expect(c.Authorizer).NotTo(BeNil())
+		return c.Authorizer
+	}
+
+	authorizer := newAuthorizer("123")
+	g.Expect(newAuthorizer("123")).To(BeIdenticalTo(authorizer), "the authorizer is reused across reconciles")
+	g.Expect(newAuthorizer("456")).NotTo(BeIdenticalTo(authorizer), "each subscription has its own authorizer")
+
+	os.Setenv("AZURE_CLIENT_SECRET", "my-new-secret")
+	rotated := newAuthorizer("123")
+	g.Expect(rotated).NotTo(BeIdenticalTo(authorizer), "the authorizer is built again when the credentials change")
+	g.Expect(newAuthorizer("123")).To(BeIdenticalTo(rotated))
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+)
+
+// CloudProviderConfigKey is the key of the azure.json configuration in the cloud provider config secret of a cluster.
+const CloudProviderConfigKey = "azure.json"
+
+// CloudProviderConfig is the azure.json configuration of the Azure cloud provider, which the Azure disk and file CSI
+// drivers read too.
+type CloudProviderConfig struct {
+	Cloud                        string `json:"cloud"`
+	TenantID                     string `json:"tenantId"`
+	SubscriptionID               string `json:"subscriptionId"`
+	AADClientID                  string `json:"aadClientId,omitempty"`
+	AADClientSecret              string `json:"aadClientSecret,omitempty"`
+	ResourceGroup                string `json:"resourceGroup"`
+	SecurityGroupName            string `json:"securityGroupName"`
+	SecurityGroupResourceGroup   string `json:"securityGroupResourceGroup,omitempty"`
+	Location                     string `json:"location"`
+	VMType                       string `json:"vmType"`
+	VnetName                     string `json:"vnetName"`
+	VnetResourceGroup            string `json:"vnetResourceGroup"`
+	SubnetName                   string `json:"subnetName"`
+	RouteTableName               string `json:"routeTableName,omitempty"`
+	RouteTableResourceGroup      string `json:"routeTableResourceGroup,omitempty"`
+	LoadBalancerSku              string `json:"loadBalancerSku"`
+	MaximumLoadBalancerRuleCount int    `json:"maximumLoadBalancerRuleCount"`
+	UseManagedIdentityExtension  bool   `json:"useManagedIdentityExtension"`
+	UserAssignedIdentityID       string `json:"userAssignedIdentityID,omitempty"`
+	UseInstanceMetadata          bool   `json:"useInstanceMetadata"`
+}
+
+// CloudProviderConfigSecretName returns the name of the secret holding the cloud provider configuration of the cluster.
+func (s *ClusterScope) CloudProviderConfigSecretName() string {
+	return azure.GenerateCloudProviderConfigSecretName(s.ClusterName())
+}
+
+// CloudProviderConfig renders the azure.json configuration of the nodes of the cluster, in its subscription, resource
+// group, location and node subnet. The networking resources are those reconciled for the cluster: the vnet, the first
+// node subnet, and its security group and route table, referenced in the network resource group unless the route
+// table has an ID in another resource group. It is rendered again on every reconcile, so that it follows the changes
+// of the network spec. The nodes are scale set instances when the cluster has AzureMachinePools, and standalone VMs
+// otherwise. Nodes with a managed identity authenticate through the instance metadata service, the user-assigned
+// identity being referenced by its client ID or resource ID. The other nodes authenticate with the service principal
+// of the AzureClusterIdentity of the cluster, which the configuration then holds; the credentials of the controller
+// are never handed out to the nodes.
+func (s *ClusterScope) CloudProviderConfig(ctx context.Context) (string, error) {
+	spec := s.AzureCluster.Spec.CloudProviderConfig
+	if spec == nil {
+		spec = &infrav1.CloudProviderConfigSpec{}
+	}
+	vmType := "standard"
+	hasMachinePools, err := s.hasMachinePools(ctx)
+	if err != nil {
+		return "", err
+	}
+	if hasMachinePools {
+		vmType = "vmss"
+	}
+	nodeSubnet := s.NodeSubnet()
+	config := CloudProviderConfig{
+		Cloud:                        s.AzureClients.EnvironmentName,
+		TenantID:                     s.AzureClients.TenantID,
+		SubscriptionID:               s.SubscriptionID(),
+		ResourceGroup:                s.ResourceGroup(),
+		SecurityGroupName:            nodeSubnet.SecurityGroup.Name,
+		Location:                     s.Location(),
+		VMType:                       vmType,
+		VnetName:                     s.Vnet().Name,
+		VnetResourceGroup:            s.Vnet().ResourceGroup,
+		SubnetName:                   nodeSubnet.Name,
+		RouteTableName:               nodeSubnet.RouteTable.Name,
+		LoadBalancerSku:              strings.ToLower(string(s.LoadBalancerSKU())),
+		MaximumLoadBalancerRuleCount: 250,
+		UseInstanceMetadata:          true,
+	}
+	if config.VnetResourceGroup == "" {
+		config.VnetResourceGroup = s.NetworkResourceGroup()
+	}
+	if s.NetworkResourceGroup() != s.ResourceGroup() {
+		config.SecurityGroupResourceGroup = s.NetworkResourceGroup()
+	}
+	if config.RouteTableName != "" {
+		routeTableResourceGroup := s.NetworkResourceGroup()
+		if resource, err := autorestazure.ParseResourceID(nodeSubnet.RouteTable.ID); err == nil {
+			routeTableResourceGroup = resource.ResourceGroup
+		}
+		if routeTableResourceGroup != s.ResourceGroup() {
+			config.RouteTableResourceGroup = routeTableResourceGroup
+		}
+	}
+	switch spec.Identity {
+	case infrav1.VMIdentitySystemAssigned:
+		config.UseManagedIdentityExtension = true
+	case infrav1.VMIdentityUserAssigned:
+		config.UseManagedIdentityExtension = true
+		config.UserAssignedIdentityID = spec.UserAssignedIdentityID
+	default:
+		if s.AzureCluster.Spec.IdentityRef == nil {
+			return "", errors.Errorf("cluster %s needs an AzureClusterIdentity for the cloud provider configuration of nodes without a managed identity", s.ClusterName())
+		}
+		if s.AzureClients.ClientID == "" || s.AzureClients.ClientSecret == "" {
+			return "", errors.Errorf("cluster %s has no service principal credentials for the cloud provider configuration of nodes without a managed identity", s.ClusterName())
+		}
+		config.AADClientID = s.AzureClients.ClientID
+		config.AADClientSecret = s.AzureClients.ClientSecret
+	}
+	b, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal cloud provider configuration")
+	}
+	return string(b), nil
+}
+
+// hasMachinePools reports whether the cluster has AzureMachinePools, which it can only have when the MachinePool
+// feature is enabled.
+func (s *ClusterScope) hasMachinePools(ctx context.Context) (bool, error) {
+	if !feature.Gates.Enabled(capifeature.MachinePool) {
+		return false, nil
+	}
+	machinePools := &infrav1exp.AzureMachinePoolList{}
+	if err := s.client.List(ctx, machinePools, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return false, errors.Wrapf(err, "failed to list the AzureMachinePools of cluster %s", s.ClusterName())
+	}
+	return len(machinePools.Items) > 0, nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capifeature "sigs.k8s.io/cluster-api/feature"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+)
+
+func TestCloudProviderConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *infrav1.CloudProviderConfigSpec
+		identityRef    *corev1.ObjectReference
+		clientID       string
+		clientSecret   string
+		expectedConfig func(config *CloudProviderConfig)
+		expectedError  string
+	}{
+		{
+			name:         "service principal",
+			config:       &infrav1.CloudProviderConfigSpec{},
+			identityRef:  &corev1.ObjectReference{Kind: "AzureClusterIdentity", Name: "my-identity"},
+			clientID:     "my-client-id",
+			clientSecret: "my-client-secret",
+			expectedConfig: func(config *CloudProviderConfig) {
+				config.AADClientID = "my-client-id"
+				config.AADClientSecret = "my-client-secret"
+			},
+		},
+		{
+			name:          "service principal of the controller",
+			config:        &infrav1.CloudProviderConfigSpec{Identity: infrav1.VMIdentityNone},
+			clientID:      "my-client-id",
+			clientSecret:  "my-client-secret",
+			expectedError: "cluster my-cluster needs an AzureClusterIdentity for the cloud provider configuration of nodes without a managed identity",
+		},
+		{
+			name:          "service principal without credentials",
+			config:        &infrav1.CloudProviderConfigSpec{Identity: infrav1.VMIdentityNone},
+			identityRef:   &corev1.ObjectReference{Kind: "AzureClusterIdentity", Name: "my-identity"},
+			expectedError: "cluster my-cluster has no service principal credentials for the cloud provider configuration of nodes without a managed identity",
+		},
+		{
+			name:         "system-assigned identity",
+			config:       &infrav1.CloudProviderConfigSpec{Identity: infrav1.VMIdentitySystemAssigned},
+			clientID:     "my-client-id",
+			clientSecret: "my-client-secret",
+			expectedConfig: func(config *CloudProviderConfig) {
+				config.UseManagedIdentityExtension = true
+			},
+		},
+		{
+			name:   "user-assigned identity",
+			config: &infrav1.CloudProviderConfigSpec{Identity: infrav1.VMIdentityUserAssigned, UserAssignedIdentityID: "my-identity-client-id"},
+			expectedConfig: func(config *CloudProviderConfig) {
+				config.UseManagedIdentityExtension = true
+				config.UserAssignedIdentityID = "my-identity-client-id"
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-network-rg"},
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+					{
+						Name:          "node-subnet",
+						Role:          infrav1.SubnetNode,
+						SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"},
+						RouteTable:    infrav1.RouteTable{Name: "node-routetable"},
+					},
+				},
+			})
+			s.AzureCluster.Spec.CloudProviderConfig = tc.config
+			s.AzureCluster.Spec.IdentityRef = tc.identityRef
+			s.AzureClients.EnvironmentName = "AzurePublicCloud"
+			s.AzureClients.TenantID = "my-tenant-id"
+			s.AzureClients.ClientID = tc.clientID
+			s.AzureClients.ClientSecret = tc.clientSecret
+
+			rendered, err := s.CloudProviderConfig(context.Background())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			expected := CloudProviderConfig{
+				Cloud:                        "AzurePublicCloud",
+				TenantID:                     "my-tenant-id",
+				SubscriptionID:               "123",
+				ResourceGroup:                "my-rg",
+				SecurityGroupName:            "node-nsg",
+				Location:                     "westus2",
+				VMType:                       "standard",
+				VnetName:                     "my-vnet",
+				VnetResourceGroup:            "my-network-rg",
+				SubnetName:                   "node-subnet",
+				RouteTableName:               "node-routetable",
+				LoadBalancerSku:              "standard",
+				MaximumLoadBalancerRuleCount: 250,
+				UseInstanceMetadata:          true,
+			}
+			tc.expectedConfig(&expected)
+			var config CloudProviderConfig
+			g.Expect(json.Unmarshal([]byte(rendered), &config)).To(Succeed())
+			g.Expect(config).To(Equal(expected))
+		})
+	}
+}
+
+func TestCloudProviderConfigNetworking(t *testing.T) {
+	tests := []struct {
+		name                 string
+		networkResourceGroup string
+		vnetResourceGroup    string
+		routeTable           infrav1.RouteTable
+		expectedConfig       func(config *CloudProviderConfig)
+	}{
+		{
+			name:       "networking in the cluster resource group",
+			routeTable: infrav1.RouteTable{Name: "node-routetable"},
+			expectedConfig: func(config *CloudProviderConfig) {
+				config.VnetResourceGroup = "my-rg"
+				config.RouteTableName = "node-routetable"
+			},
+		},
+		{
+			name:                 "networking in a network resource group",
+			networkResourceGroup: "my-network-rg",
+			routeTable:           infrav1.RouteTable{Name: "node-routetable"},
+			expectedConfig: func(config *CloudProviderConfig) {
+				config.SecurityGroupResourceGroup = "my-network-rg"
+				config.VnetResourceGroup = "my-network-rg"
+				config.RouteTableName = "node-routetable"
+				config.RouteTableResourceGroup = "my-network-rg"
+			},
+		},
+		{
+			name:              "existing vnet and route table",
+			vnetResourceGroup: "my-vnet-rg",
+			routeTable: infrav1.RouteTable{
+				ID:   "/subscriptions/123/resourceGroups/my-routes-rg/providers/Microsoft.Network/routeTables/node-routetable",
+				Name: "node-routetable",
+			},
+			expectedConfig: func(config *CloudProviderConfig) {
+				config.VnetResourceGroup = "my-vnet-rg"
+				config.RouteTableName = "node-routetable"
+				config.RouteTableResourceGroup = "my-routes-rg"
+			},
+		},
+		{
+			name: "no route table",
+			expectedConfig: func(config *CloudProviderConfig) {
+				config.VnetResourceGroup = "my-rg"
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: tc.vnetResourceGroup},
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+					{
+						Name:          "node-subnet",
+						Role:          infrav1.SubnetNode,
+						SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"},
+						RouteTable:    tc.routeTable,
+					},
+				},
+			})
+			s.AzureCluster.Spec.NetworkResourceGroup = tc.networkResourceGroup
+			s.AzureCluster.Spec.CloudProviderConfig = &infrav1.CloudProviderConfigSpec{Identity: infrav1.VMIdentitySystemAssigned}
+
+			rendered, err := s.CloudProviderConfig(context.Background())
+			g.Expect(err).NotTo(HaveOccurred())
+
+			expected := CloudProviderConfig{
+				Cloud:                        "AzurePublicCloud",
+				SubscriptionID:               "123",
+				ResourceGroup:                "my-rg",
+				SecurityGroupName:            "node-nsg",
+				Location:                     "westus2",
+				VMType:                       "standard",
+				VnetName:                     "my-vnet",
+				SubnetName:                   "node-subnet",
+				LoadBalancerSku:              "standard",
+				MaximumLoadBalancerRuleCount: 250,
+				UseManagedIdentityExtension:  true,
+				UseInstanceMetadata:          true,
+			}
+			tc.expectedConfig(&expected)
+			var config CloudProviderConfig
+			g.Expect(json.Unmarshal([]byte(rendered), &config)).To(Succeed())
+			g.Expect(config).To(Equal(expected))
+		})
+	}
+}
+
+func TestCloudProviderConfigFollowsNetworkSpec(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet"},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode, SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"}},
+		},
+	})
+	s.AzureCluster.Spec.CloudProviderConfig = &infrav1.CloudProviderConfigSpec{Identity: infrav1.VMIdentitySystemAssigned}
+	before, err := s.CloudProviderConfig(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s.AzureCluster.Spec.NetworkSpec.Subnets[1].RouteTable = infrav1.RouteTable{Name: "node-routetable"}
+	s.AzureCluster.Spec.NetworkSpec.Subnets[1].SecurityGroup.Name = "other-nsg"
+	after, err := s.CloudProviderConfig(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(after).NotTo(Equal(before))
+	var config CloudProviderConfig
+	g.Expect(json.Unmarshal([]byte(after), &config)).To(Succeed())
+	g.Expect(config.RouteTableName).To(Equal("node-routetable"))
+	g.Expect(config.SecurityGroupName).To(Equal("other-nsg"))
+}
+
+func TestCloudProviderConfigVMType(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	g := NewWithT(t)
+	_ = infrav1exp.AddToScheme(scheme.Scheme)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet"},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode, SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"}},
+		},
+	})
+	s.AzureCluster.Spec.CloudProviderConfig = &infrav1.CloudProviderConfigSpec{Identity: infrav1.VMIdentitySystemAssigned}
+
+	rendered, err := s.CloudProviderConfig(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	var config CloudProviderConfig
+	g.Expect(json.Unmarshal([]byte(rendered), &config)).To(Succeed())
+	g.Expect(config.VMType).To(Equal("standard"))
+
+	g.Expect(s.client.Create(context.Background(), &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "my-machine-pool",
+			Labels: map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+	})).To(Succeed())
+	rendered, err = s.CloudProviderConfig(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(json.Unmarshal([]byte(rendered), &config)).To(Succeed())
+	g.Expect(config.VMType).To(Equal("vmss"))
+}
@@ -18,16 +18,26 @@ package scope
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"k8s.io/klog/klogr"
+	"net"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"strings"
 )
 
 // ClusterScopeParams defines the input parameters used to create a new Scope.
@@ -49,12 +59,30 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 	if params.AzureCluster == nil {
 		return nil, errors.New("failed to generate new scope from nil AzureCluster")
 	}
+	// the subnet accessors of the scope return an empty subnet for a missing role, so both subnets are required up front
+	if params.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnet() == nil {
+		return nil, errors.Errorf("failed to generate new scope: AzureCluster %s/%s has no subnet with the %s role",
+			params.AzureCluster.Namespace, params.AzureCluster.Name, infrav1.SubnetControlPlane)
+	}
+	if params.AzureCluster.Spec.NetworkSpec.GetNodeSubnet() == nil {
+		return nil, errors.Errorf("failed to generate new scope: AzureCluster %s/%s has no subnet with the %s role",
+			params.AzureCluster.Namespace, params.AzureCluster.Name, infrav1.SubnetNode)
+	}
 
 	if params.Logger == nil {
 		params.Logger = klogr.New()
 	}
 
-	err := params.AzureClients.setCredentials(params.AzureCluster.Spec.SubscriptionID)
+	var err error
+	if params.AzureCluster.Spec.IdentityRef != nil {
+		ctx := params.Context
+		if ctx == nil {
+			ctx = context.TODO()
+		}
+		err = params.AzureClients.setIdentityCredentials(ctx, params.Client, params.AzureCluster)
+	} else {
+		err = params.AzureClients.setCredentials(params.AzureCluster.Spec.SubscriptionID, params.AzureCluster.Spec.AzureEnvironment)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create Azure session")
 	}
@@ -106,20 +134,118 @@ func (s *ClusterScope) Network() *infrav1.Network {
 
 // PublicIPSpec returns the public IP specs.
 func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
-	return []azure.PublicIPSpec{
-		{
-			Name: azure.GenerateNodeOutboundIPName(s.ClusterName()),
-		},
+	var specs []azure.PublicIPSpec
+	if s.NodeOutboundLBName() != "" {
+		for _, name := range s.NodeOutboundIPNames() {
+			nodeOutboundIP := azure.PublicIPSpec{
+				Name: name,
+				SKU:  s.LoadBalancerSKU(),
+			}
+			if outboundLB := s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB; outboundLB != nil {
+				nodeOutboundIP.Zones = outboundLB.PublicIPZones
+				nodeOutboundIP.AdditionalTags = outboundLB.PublicIPTags
+			}
+			for _, prefix := range s.PublicIPPrefixSpecs() {
+				nodeOutboundIP.PublicIPPrefixName = prefix.Name
+			}
+			specs = append(specs, nodeOutboundIP)
+		}
+	}
+	if !s.IsAPIServerPrivate() {
+		// a pre-existing API server public IP is neither created nor deleted
+		if !s.IsAPIServerIPPreExisting() {
+			specs = append(specs, azure.PublicIPSpec{
+				Name:           s.Network().APIServerIP.Name,
+				DNSName:        s.Network().APIServerIP.DNSName,
+				DNSLabel:       s.AzureCluster.Spec.NetworkSpec.APIServerLB.DNSLabel,
+				SKU:            s.LoadBalancerSKU(),
+				Zones:          s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPZones,
+				AdditionalTags: s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPTags,
+			})
+		}
+		if s.IsIPv6Enabled() {
+			specs = append(specs, azure.PublicIPSpec{
+				Name:           s.Network().APIServerIPv6.Name,
+				DNSName:        s.Network().APIServerIPv6.DNSName,
+				SKU:            s.LoadBalancerSKU(),
+				IsIPv6:         true,
+				Zones:          s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPZones,
+				AdditionalTags: s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPTags,
+			})
+		}
+		for _, name := range s.AzureCluster.Spec.NetworkSpec.APIServerLB.AdditionalPublicIPNames {
+			specs = append(specs, azure.PublicIPSpec{
+				Name:           name,
+				SKU:            s.LoadBalancerSKU(),
+				Zones:          s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPZones,
+				AdditionalTags: s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPTags,
+			})
+		}
+	}
+	if name := s.ControlPlaneOutboundLBName(); name != "" {
+		specs = append(specs, azure.PublicIPSpec{
+			Name: azure.GenerateControlPlaneOutboundIPName(s.ClusterName()),
+			SKU:  s.LoadBalancerSKU(),
+		})
+	}
+	if bastion := s.BastionSpec(); bastion != nil {
+		// bastion hosts only support Standard public IPs
+		specs = append(specs, azure.PublicIPSpec{
+			Name: bastion.PublicIPName,
+			SKU:  infrav1.SKUStandard,
+		})
+	}
+	if appGateway := s.ApplicationGatewaySpec(); appGateway != nil {
+		// v2 application gateways only support Standard public IPs
+		specs = append(specs, azure.PublicIPSpec{
+			Name: appGateway.PublicIPName,
+			SKU:  infrav1.SKUStandard,
+		})
+	}
+	natGatewayIPs := make(map[string]struct{})
+	for _, natGateway := range s.NatGatewaySpecs() {
+		// several subnets can share a NAT gateway
+		if _, ok := natGatewayIPs[natGateway.PublicIPName]; ok {
+			continue
+		}
+		natGatewayIPs[natGateway.PublicIPName] = struct{}{}
+		// NAT gateways only support Standard public IPs
+		specs = append(specs, azure.PublicIPSpec{
+			Name: natGateway.PublicIPName,
+			SKU:  infrav1.SKUStandard,
+		})
+	}
+	return specs
+}
+
+// NodeOutboundIPNames returns the names of the public IPs of the node outbound load balancer.
+func (s *ClusterScope) NodeOutboundIPNames() []string {
+	count := int32(1)
+	if outboundLB := s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB; outboundLB != nil && outboundLB.FrontendIPsCount != nil {
+		count = *outboundLB.FrontendIPsCount
+	}
+	return azure.GenerateNodeOutboundIPNames(s.ClusterName(), count)
+}
+
+// PublicIPPrefixSpecs returns the public IP prefix specs.
+// A prefix is only created for the node outbound IPs, when a prefix length is configured.
+func (s *ClusterScope) PublicIPPrefixSpecs() []azure.PublicIPPrefixSpec {
+	outboundLB := s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB
+	if s.NodeOutboundLBName() == "" || outboundLB == nil || outboundLB.PublicIPPrefixLength == nil {
+		return nil
+	}
+	return []azure.PublicIPPrefixSpec{
 		{
-			Name:    s.Network().APIServerIP.Name,
-			DNSName: s.Network().APIServerIP.DNSName,
+			Name:         azure.GenerateNodeOutboundIPPrefixName(s.ClusterName()),
+			PrefixLength: *outboundLB.PublicIPPrefixLength,
+			Zones:        outboundLB.PublicIPZones,
 		},
 	}
 }
 
 // LBSpecs returns the load balancer specs.
 func (s *ClusterScope) LBSpecs() []azure.LBSpec {
-	return []azure.LBSpec{
+	specs := []azure.LBSpec{
 		{
 			// Internal control plane LB
 			Name:             azure.GenerateInternalLBName(s.ClusterName()),
@@ -128,21 +254,401 @@ func (s *ClusterScope) LBSpecs() []azure.LBSpec {
 			PrivateIPAddress: s.ControlPlaneSubnet().InternalLBIPAddress,
 			APIServerPort:    s.APIServerPort(),
 			Role:             infrav1.InternalRole,
+			SKU:              s.LoadBalancerSKU(),
+			Probe:            s.APIServerProbe(),
+			Zones:            s.AzureCluster.Spec.NetworkSpec.APIServerLB.InternalLBZones,
+			LoadDistribution: s.AzureCluster.Spec.NetworkSpec.APIServerLB.LoadDistribution,
+			InboundNatRules:  s.InternalLBInboundNatRules(),
 		},
-		{
+	}
+	if !s.IsAPIServerPrivate() {
+		apiServerLB := azure.LBSpec{
 			// Public API Server LB
-			Name:          azure.GeneratePublicLBName(s.ClusterName()),
-			PublicIPName:  s.Network().APIServerIP.Name,
-			APIServerPort: s.APIServerPort(),
-			Role:          infrav1.APIServerRole,
-		},
-		{
+			Name:                    azure.GeneratePublicLBName(s.ClusterName()),
+			PublicIPName:            s.Network().APIServerIP.Name,
+			PublicIPResourceGroup:   s.APIServerIPResourceGroup(),
+			AdditionalPublicIPNames: s.AzureCluster.Spec.NetworkSpec.APIServerLB.AdditionalPublicIPNames,
+			APIServerPort:           s.APIServerPort(),
+			Role:                    infrav1.APIServerRole,
+			SKU:                     s.LoadBalancerSKU(),
+			Probe:                   s.APIServerProbe(),
+			LoadDistribution:        s.AzureCluster.Spec.NetworkSpec.APIServerLB.LoadDistribution,
+		}
+		if s.IsIPv6Enabled() {
+			apiServerLB.IPv6PublicIPName = s.Network().APIServerIPv6.Name
+		}
+		specs = append(specs, apiServerLB)
+	}
+	if name := s.NodeOutboundLBName(); name != "" {
+		nodeOutboundIPNames := s.NodeOutboundIPNames()
+		nodeOutboundLB := azure.LBSpec{
 			// Public Node outbound LB
-			Name:         s.ClusterName(),
-			PublicIPName: azure.GenerateNodeOutboundIPName(s.ClusterName()),
-			Role:         infrav1.NodeOutboundRole,
-		},
+			Name:                    name,
+			PublicIPName:            nodeOutboundIPNames[0],
+			AdditionalPublicIPNames: nodeOutboundIPNames[1:],
+			Role:                    infrav1.NodeOutboundRole,
+			SKU:                     s.LoadBalancerSKU(),
+		}
+		if outboundLB := s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB; outboundLB != nil {
+			if outboundLB.AllocatedOutboundPorts != nil {
+				nodeOutboundLB.AllocatedOutboundPorts = *outboundLB.AllocatedOutboundPorts
+			}
+			if outboundLB.IdleTimeoutInMinutes != nil {
+				nodeOutboundLB.IdleTimeoutInMinutes = *outboundLB.IdleTimeoutInMinutes
+			}
+		}
+		specs = append(specs, nodeOutboundLB)
+	}
+	if name := s.ControlPlaneOutboundLBName(); name != "" {
+		controlPlaneOutboundLB := azure.LBSpec{
+			// Public control plane outbound LB
+			Name:         name,
+			PublicIPName: azure.GenerateControlPlaneOutboundIPName(s.ClusterName()),
+			Role:         infrav1.ControlPlaneOutboundRole,
+			SKU:          s.LoadBalancerSKU(),
+		}
+		outboundLB := s.AzureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB
+		if outboundLB.AllocatedOutboundPorts != nil {
+			controlPlaneOutboundLB.AllocatedOutboundPorts = *outboundLB.AllocatedOutboundPorts
+		}
+		if outboundLB.IdleTimeoutInMinutes != nil {
+			controlPlaneOutboundLB.IdleTimeoutInMinutes = *outboundLB.IdleTimeoutInMinutes
+		}
+		specs = append(specs, controlPlaneOutboundLB)
+	}
+	return specs
+}
+
+// NodeOutboundLBName returns the name of the load balancer for the outbound connections of the nodes, or an empty
+// string when the cluster has none. It isn't created when the nodes use NAT gateways, or when it is disabled.
+func (s *ClusterScope) NodeOutboundLBName() string {
+	if s.IsNatGatewayEnabled() || s.AzureCluster.Spec.NetworkSpec.NodeOutboundLBDisabled {
+		return ""
+	}
+	return s.ResourceName("", "")
+}
+
+// ControlPlaneOutboundLBName returns the name of the load balancer for the outbound connections of the control
+// plane machines, or an empty string when the cluster has none. It is only created for clusters with an internal
+// API server load balancer, as the public one already provides the outbound connections of the control plane.
+func (s *ClusterScope) ControlPlaneOutboundLBName() string {
+	if !s.IsAPIServerPrivate() || s.AzureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB == nil {
+		return ""
+	}
+	return azure.GenerateControlPlaneOutboundLBName(s.ClusterName())
+}
+
+// ValidateInternalLBIPAddress checks that the static private IP of the internal load balancer, when one is set,
+// belongs to the control plane subnet and isn't one of the five addresses Azure reserves in every subnet:
+// the network address, the default gateway, the two DNS addresses and the broadcast address.
+func (s *ClusterScope) ValidateInternalLBIPAddress() error {
+	subnet := s.ControlPlaneSubnet()
+	if subnet.InternalLBIPAddress == "" || subnet.CidrBlock == "" {
+		return nil
+	}
+	ip := net.ParseIP(subnet.InternalLBIPAddress).To4()
+	if ip == nil {
+		return errors.Errorf("internal load balancer IP %s is not a valid IPv4 address", subnet.InternalLBIPAddress)
+	}
+	_, ipNet, err := net.ParseCIDR(subnet.CidrBlock)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse CIDR block %s of subnet %s", subnet.CidrBlock, subnet.Name)
+	}
+	if !ipNet.Contains(ip) || ipNet.IP.To4() == nil {
+		return errors.Errorf("internal load balancer IP %s is not in the CIDR block %s of subnet %s", subnet.InternalLBIPAddress, subnet.CidrBlock, subnet.Name)
+	}
+	ones, bits := ipNet.Mask.Size()
+	offset := binary.BigEndian.Uint32(ip) - binary.BigEndian.Uint32(ipNet.IP.To4())
+	if offset < 4 || uint64(offset) == uint64(1)<<uint(bits-ones)-1 {
+		return errors.Errorf("internal load balancer IP %s is reserved by Azure in subnet %s", subnet.InternalLBIPAddress, subnet.Name)
+	}
+	return nil
+}
+
+// BastionSpec returns the bastion host spec, or nil if the cluster has no bastion host.
+func (s *ClusterScope) BastionSpec() *azure.BastionSpec {
+	bastion := s.AzureCluster.Spec.NetworkSpec.Bastion
+	if bastion == nil {
+		return nil
+	}
+	spec := &azure.BastionSpec{
+		Name:       bastion.Name,
+		SubnetName: bastion.Subnet.Name,
+		SubnetCIDR: bastion.Subnet.CidrBlock,
+		VNetName:   s.Vnet().Name,
+	}
+	if spec.Name == "" {
+		spec.Name = azure.GenerateBastionName(s.ClusterName())
+	}
+	if spec.SubnetName == "" {
+		spec.SubnetName = azure.BastionSubnetName
+	}
+	if spec.SubnetCIDR == "" {
+		spec.SubnetCIDR = s.Vnet().DefaultBastionSubnetCIDR()
+	}
+	spec.PublicIPName = azure.GenerateBastionIPName(spec.Name)
+	return spec
+}
+
+// ProximityPlacementGroupSpec returns the proximity placement group spec, or nil if the VMs of the cluster aren't
+// placed in a proximity placement group.
+func (s *ClusterScope) ProximityPlacementGroupSpec() *azure.ProximityPlacementGroupSpec {
+	ppg := s.AzureCluster.Spec.ProximityPlacementGroup
+	if ppg == nil {
+		return nil
+	}
+	name := ppg.Name
+	if name == "" {
+		name = azure.GenerateProximityPlacementGroupName(s.ClusterName())
+	}
+	return &azure.ProximityPlacementGroupSpec{
+		Name: name,
+		ID: fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/proximityPlacementGroups/%s",
+			s.SubscriptionID(), s.ResourceGroup(), name),
+		FailureDomain: to.String(ppg.FailureDomain),
+	}
+}
+
+// ValidateBastion checks that the subnet of the bastion host, when there is one, has the name and size Azure
+// requires: it must be named AzureBastionSubnet and have a prefix of at most /26.
+func (s *ClusterScope) ValidateBastion() error {
+	bastion := s.BastionSpec()
+	if bastion == nil {
+		return nil
+	}
+	if bastion.SubnetName != azure.BastionSubnetName {
+		return errors.Errorf("subnet %s of bastion host %s must be named %s", bastion.SubnetName, bastion.Name, azure.BastionSubnetName)
+	}
+	if bastion.SubnetCIDR == "" {
+		return errors.Errorf("subnet %s of bastion host %s needs a CIDR block, the CIDR block %s of the vnet has no room for a /%d subnet",
+			bastion.SubnetName, bastion.Name, s.Vnet().CidrBlock, azure.MaxBastionSubnetPrefix)
+	}
+	_, ipNet, err := net.ParseCIDR(bastion.SubnetCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse CIDR block %s of subnet %s", bastion.SubnetCIDR, bastion.SubnetName)
+	}
+	if ones, _ := ipNet.Mask.Size(); ones > azure.MaxBastionSubnetPrefix {
+		return errors.Errorf("CIDR block %s of subnet %s must have a prefix of at most /%d", bastion.SubnetCIDR, bastion.SubnetName, azure.MaxBastionSubnetPrefix)
+	}
+	return nil
+}
+
+// ApplicationGatewayName returns the name of the application gateway fronting the API server, or an empty string if
+// the cluster has no application gateway.
+func (s *ClusterScope) ApplicationGatewayName() string {
+	if spec := s.ApplicationGatewaySpec(); spec != nil {
+		return spec.Name
+	}
+	return ""
+}
+
+// ApplicationGatewaySpec returns the spec of the application gateway fronting the API server, or nil if the cluster
+// has no application gateway. Its backend is reached at the host of the control plane endpoint, which the certificate
+// of the API server is issued for.
+func (s *ClusterScope) ApplicationGatewaySpec() *azure.ApplicationGatewaySpec {
+	appGateway := s.AzureCluster.Spec.NetworkSpec.ApplicationGateway
+	if appGateway == nil {
+		return nil
+	}
+	spec := &azure.ApplicationGatewaySpec{
+		Name:                   appGateway.Name,
+		SubnetName:             appGateway.Subnet.Name,
+		SubnetCIDR:             appGateway.Subnet.CidrBlock,
+		VNetName:               s.Vnet().Name,
+		Capacity:               azure.DefaultApplicationGatewayCapacity,
+		FrontendPort:           appGateway.FrontendPort,
+		BackendPort:            s.APIServerPort(),
+		HostName:               s.ControlPlaneEndpoint().Host,
+		SSLCertificateSecretID: appGateway.SSLCertificateSecretID,
+		IdentityID:             appGateway.IdentityID,
+		FirewallPolicyID:       appGateway.FirewallPolicyID,
+	}
+	if spec.Name == "" {
+		spec.Name = azure.GenerateApplicationGatewayName(s.ClusterName())
+	}
+	if spec.SubnetName == "" {
+		spec.SubnetName = azure.GenerateApplicationGatewaySubnetName(s.ClusterName())
+	}
+	if spec.SubnetCIDR == "" {
+		spec.SubnetCIDR = azure.DefaultApplicationGatewaySubnetCIDR
+	}
+	if appGateway.Capacity != nil {
+		spec.Capacity = *appGateway.Capacity
+	}
+	if spec.FrontendPort == 0 {
+		spec.FrontendPort = azure.DefaultApplicationGatewayFrontendPort
+	}
+	spec.PublicIPName = azure.GenerateApplicationGatewayIPName(spec.Name)
+	return spec
+}
+
+// ValidateApplicationGateway checks that the subnet of the application gateway, when there is one, is dedicated to it:
+// it can't be one of the subnets of the cluster or the subnet of the bastion host, nor overlap them. Its IPv4 CIDR
+// block must have a prefix of at most /26, and room for an IP address per instance besides the 5 Azure reserves.
+func (s *ClusterScope) ValidateApplicationGateway() error {
+	appGateway := s.ApplicationGatewaySpec()
+	if appGateway == nil {
+		return nil
+	}
+	_, ipNet, err := net.ParseCIDR(appGateway.SubnetCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse CIDR block %s of subnet %s", appGateway.SubnetCIDR, appGateway.SubnetName)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits != 32 {
+		return errors.Errorf("CIDR block %s of subnet %s must be an IPv4 CIDR block", appGateway.SubnetCIDR, appGateway.SubnetName)
+	}
+	if ones > azure.MaxApplicationGatewaySubnetPrefix {
+		return errors.Errorf("CIDR block %s of subnet %s must have a prefix of at most /%d", appGateway.SubnetCIDR, appGateway.SubnetName, azure.MaxApplicationGatewaySubnetPrefix)
+	}
+	if available := int64(1)<<uint(bits-ones) - 5; available < int64(appGateway.Capacity) {
+		return errors.Errorf("CIDR block %s of subnet %s has %d available IP addresses, less than the %d instances of application gateway %s",
+			appGateway.SubnetCIDR, appGateway.SubnetName, available, appGateway.Capacity, appGateway.Name)
+	}
+
+	others := make(map[string]string)
+	for _, subnet := range s.Subnets() {
+		others[subnet.Name] = subnet.CidrBlock
+	}
+	if bastion := s.BastionSpec(); bastion != nil {
+		others[bastion.SubnetName] = bastion.SubnetCIDR
+	}
+	for name, cidr := range others {
+		if name == appGateway.SubnetName {
+			return errors.Errorf("subnet %s of application gateway %s must be dedicated to it", appGateway.SubnetName, appGateway.Name)
+		}
+		if cidr == "" {
+			continue
+		}
+		_, otherNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse CIDR block %s of subnet %s", cidr, name)
+		}
+		if ipNet.Contains(otherNet.IP) || otherNet.Contains(ipNet.IP) {
+			return errors.Errorf("CIDR block %s of subnet %s overlaps CIDR block %s of subnet %s", appGateway.SubnetCIDR, appGateway.SubnetName, cidr, name)
+		}
+	}
+	return nil
+}
+
+// ClusterCACertificate returns the PEM encoded certificate of the CA of the cluster, which issues the certificate of
+// the API server.
+func (s *ClusterScope) ClusterCACertificate(ctx context.Context) ([]byte, error) {
+	caSecret, err := secret.GetFromNamespacedName(ctx, s.client, client.ObjectKey{Namespace: s.Namespace(), Name: s.ClusterName()}, secret.ClusterCA)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the CA secret of cluster %s", s.ClusterName())
+	}
+	cert, ok := caSecret.Data[secret.TLSCrtDataName]
+	if !ok || len(cert) == 0 {
+		return nil, errors.Errorf("CA secret of cluster %s has no %s key", s.ClusterName(), secret.TLSCrtDataName)
+	}
+	return cert, nil
+}
+
+// NatGatewaySpecs returns the NAT gateway specs, one for each node subnet with a NAT gateway.
+// NAT gateways of subnets in a custom vnet are expected to already exist, so no specs are returned for them.
+func (s *ClusterScope) NatGatewaySpecs() []azure.NatGatewaySpec {
+	if !s.Vnet().IsManaged(s.ClusterName()) {
+		return nil
 	}
+	var specs []azure.NatGatewaySpec
+	for _, subnet := range s.NodeSubnets() {
+		if subnet.NatGateway.Name == "" {
+			continue
+		}
+		specs = append(specs, azure.NatGatewaySpec{
+			Name:         subnet.NatGateway.Name,
+			SubnetName:   subnet.Name,
+			PublicIPName: azure.GenerateNatGatewayIPName(subnet.NatGateway.Name),
+		})
+	}
+	return specs
+}
+
+// IsNatGatewayEnabled returns true if the node subnets use NAT gateways for outbound traffic,
+// in which case the node outbound load balancer is not created.
+func (s *ClusterScope) IsNatGatewayEnabled() bool {
+	for _, subnet := range s.NodeSubnets() {
+		if subnet.NatGateway.Name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadBalancerSKU returns the SKU of the cluster load balancers and public IPs, Standard unless specified.
+func (s *ClusterScope) LoadBalancerSKU() infrav1.SKU {
+	if s.AzureCluster.Spec.NetworkSpec.LoadBalancerSKU == "" {
+		return infrav1.SKUStandard
+	}
+	return s.AzureCluster.Spec.NetworkSpec.LoadBalancerSKU
+}
+
+// APIServerProbe returns the health probe spec of the API server load balancers.
+func (s *ClusterScope) APIServerProbe() azure.ProbeSpec {
+	probe := s.AzureCluster.Spec.NetworkSpec.APIServerLB.HealthProbe
+	if probe == nil {
+		return azure.ProbeSpec{}
+	}
+	spec := azure.ProbeSpec{
+		Protocol:    probe.Protocol,
+		RequestPath: probe.RequestPath,
+	}
+	if probe.IntervalSeconds != nil {
+		spec.IntervalInSeconds = *probe.IntervalSeconds
+	}
+	if probe.UnhealthyThreshold != nil {
+		spec.UnhealthyThreshold = *probe.UnhealthyThreshold
+	}
+	return spec
+}
+
+// IsAPIServerPrivate returns true if the API server is only exposed through the internal load balancer.
+func (s *ClusterScope) IsAPIServerPrivate() bool {
+	return s.AzureCluster.Spec.NetworkSpec.APIServerLB.Type == infrav1.Internal
+}
+
+// InternalLBInboundNatRules returns the inbound NAT rules of the internal API server load balancer, created for each
+// control plane machine.
+func (s *ClusterScope) InternalLBInboundNatRules() []infrav1.InboundNatRule {
+	return s.AzureCluster.Spec.NetworkSpec.APIServerLB.InternalLBInboundNatRules
+}
+
+// IsAPIServerIPPreExisting returns true if the API server public IP is an existing public IP referenced by its resource
+// ID, which the cluster neither creates nor deletes.
+func (s *ClusterScope) IsAPIServerIPPreExisting() bool {
+	return !s.IsAPIServerPrivate() && s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPID != ""
+}
+
+// APIServerIPResourceGroup returns the resource group of the API server public IP, which is the network resource group
+// unless the public IP is pre-existing.
+func (s *ClusterScope) APIServerIPResourceGroup() string {
+	if s.IsAPIServerIPPreExisting() {
+		if resource, err := autorestazure.ParseResourceID(s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPID); err == nil {
+			return resource.ResourceGroup
+		}
+	}
+	return s.NetworkResourceGroup()
+}
+
+// AcceleratedNetworking returns the accelerated networking default of the machines of the cluster.
+func (s *ClusterScope) AcceleratedNetworking() *bool {
+	return s.AzureCluster.Spec.NetworkSpec.AcceleratedNetworking
+}
+
+// DiskEncryptionSetID returns the disk encryption set default of the disks of the machines of the cluster.
+func (s *ClusterScope) DiskEncryptionSetID() string {
+	return s.AzureCluster.Spec.DiskEncryptionSetID
+}
+
+// DefaultImage returns the default image of the machines of the cluster.
+func (s *ClusterScope) DefaultImage() *infrav1.Image {
+	return s.AzureCluster.Spec.DefaultImage
+}
+
+// IsIPv6Enabled returns true if the cluster network is dual-stack.
+func (s *ClusterScope) IsIPv6Enabled() bool {
+	return s.Vnet().IsIPv6Enabled()
 }
 
 // Vnet returns the cluster Vnet.
@@ -150,19 +656,159 @@ func (s *ClusterScope) Vnet() *infrav1.VnetSpec {
 	return &s.AzureCluster.Spec.NetworkSpec.Vnet
 }
 
+// VnetPeeringSpecs returns the specs of both directions of the vnet peerings.
+// The peering from the remote vnet comes first, so that it allows gateway transit before the cluster vnet uses its gateways.
+func (s *ClusterScope) VnetPeeringSpecs() []azure.VnetPeeringSpec {
+	var specs []azure.VnetPeeringSpec
+	vnetID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s",
+		s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name)
+	for _, peering := range s.AzureCluster.Spec.NetworkSpec.VnetPeerings {
+		remote, err := autorestazure.ParseResourceID(peering.RemoteVnetID)
+		if err != nil {
+			// the remote vnet ID is validated by the webhook
+			s.Error(err, "skipping vnet peering with an invalid remote vnet ID", "remote vnet id", peering.RemoteVnetID)
+			continue
+		}
+		specs = append(specs,
+			azure.VnetPeeringSpec{
+				Name:                  azure.GenerateVnetPeeringName(remote.ResourceName, s.Vnet().Name),
+				SubscriptionID:        remote.SubscriptionID,
+				ResourceGroup:         remote.ResourceGroup,
+				VnetName:              remote.ResourceName,
+				RemoteVnetID:          vnetID,
+				AllowForwardedTraffic: peering.AllowForwardedTraffic,
+				AllowGatewayTransit:   peering.UseRemoteGateways,
+			},
+			azure.VnetPeeringSpec{
+				Name:                  azure.GenerateVnetPeeringName(s.Vnet().Name, remote.ResourceName),
+				SubscriptionID:        s.SubscriptionID(),
+				ResourceGroup:         s.Vnet().ResourceGroup,
+				VnetName:              s.Vnet().Name,
+				RemoteVnetID:          peering.RemoteVnetID,
+				AllowForwardedTraffic: peering.AllowForwardedTraffic,
+				UseRemoteGateways:     peering.UseRemoteGateways,
+			})
+	}
+	return specs
+}
+
+// PrivateEndpointSpecs returns the specs of the private endpoints in the subnets of the cluster vnet.
+func (s *ClusterScope) PrivateEndpointSpecs() []azure.PrivateEndpointSpec {
+	var specs []azure.PrivateEndpointSpec
+	for _, endpoint := range s.AzureCluster.Spec.NetworkSpec.PrivateEndpoints {
+		specs = append(specs, azure.PrivateEndpointSpec{
+			Name:                 endpoint.Name,
+			SubnetName:           endpoint.SubnetName,
+			VnetName:             s.Vnet().Name,
+			PrivateLinkServiceID: endpoint.PrivateLinkServiceID,
+			GroupIDs:             endpoint.GroupIDs,
+			PrivateDNSZoneIDs:    endpoint.PrivateDNSZoneIDs,
+		})
+	}
+	return specs
+}
+
+// PrivateLinkServiceName returns the name of the private link service of the internal API server load balancer.
+func (s *ClusterScope) PrivateLinkServiceName() string {
+	return azure.GeneratePrivateLinkServiceName(s.ClusterName())
+}
+
+// PrivateLinkServiceSpec returns the spec of the private link service on the frontend of the internal API server load
+// balancer, or nil if it isn't configured. Its NAT IPs are allocated from the control plane subnet by default.
+func (s *ClusterScope) PrivateLinkServiceSpec() *azure.PrivateLinkServiceSpec {
+	pls := s.AzureCluster.Spec.NetworkSpec.APIServerLB.PrivateLinkService
+	if pls == nil {
+		return nil
+	}
+	subnetName := pls.SubnetName
+	if subnetName == "" {
+		subnetName = s.ControlPlaneSubnet().Name
+	}
+	return &azure.PrivateLinkServiceSpec{
+		Name:                      s.PrivateLinkServiceName(),
+		SubnetName:                subnetName,
+		VnetName:                  s.Vnet().Name,
+		LoadBalancerName:          azure.GenerateInternalLBName(s.ClusterName()),
+		VisibilitySubscriptions:   pls.VisibilitySubscriptions,
+		AutoApprovalSubscriptions: pls.AutoApprovalSubscriptions,
+	}
+}
+
+// SetPrivateLinkServiceAlias records the alias of the private link service of the internal API server load balancer.
+func (s *ClusterScope) SetPrivateLinkServiceAlias(alias string) {
+	s.Network().PrivateLinkServiceAlias = alias
+}
+
+// ValidatePrivateEndpoints checks that the private endpoints connect to Azure resources, register their private IPs
+// in private DNS zones, and get them from subnets of the cluster vnet. Whether these subnets are delegated to a
+// service is only known to Azure, and is checked when the private endpoints are reconciled.
+func (s *ClusterScope) ValidatePrivateEndpoints() error {
+	for _, endpoint := range s.PrivateEndpointSpecs() {
+		if _, err := autorestazure.ParseResourceID(endpoint.PrivateLinkServiceID); err != nil {
+			return errors.Wrapf(err, "invalid resource ID %s of private endpoint %s", endpoint.PrivateLinkServiceID, endpoint.Name)
+		}
+		for _, zoneID := range endpoint.PrivateDNSZoneIDs {
+			zone, err := autorestazure.ParseResourceID(zoneID)
+			if err != nil {
+				return errors.Wrapf(err, "invalid private DNS zone ID %s of private endpoint %s", zoneID, endpoint.Name)
+			}
+			if !strings.EqualFold(zone.Provider, "Microsoft.Network") || !strings.EqualFold(zone.ResourceType, "privateDnsZones") {
+				return errors.Errorf("%s of private endpoint %s isn't the resource ID of a private DNS zone", zoneID, endpoint.Name)
+			}
+		}
+		found := false
+		for _, subnet := range s.Subnets() {
+			if subnet.Name == endpoint.SubnetName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("subnet %s of private endpoint %s isn't a subnet of vnet %s", endpoint.SubnetName, endpoint.Name, endpoint.VnetName)
+		}
+	}
+	return nil
+}
+
 // Subnets returns the cluster subnets.
 func (s *ClusterScope) Subnets() infrav1.Subnets {
 	return s.AzureCluster.Spec.NetworkSpec.Subnets
 }
 
-// ControlPlaneSubnet returns the cluster control plane subnet.
+// ControlPlaneSubnet returns the control plane subnet of the internal load balancer frontend, or an empty subnet if the
+// cluster has no control plane subnet.
 func (s *ClusterScope) ControlPlaneSubnet() *infrav1.SubnetSpec {
-	return s.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnet()
+	return subnetOrEmpty(s.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnet())
+}
+
+// ControlPlaneSubnets returns all the cluster control plane subnets.
+func (s *ClusterScope) ControlPlaneSubnets() infrav1.Subnets {
+	return s.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnets()
+}
+
+// ControlPlaneSubnetForZone returns the control plane subnet of the control plane machines of an availability zone, or
+// an empty subnet if the cluster has no control plane subnet.
+func (s *ClusterScope) ControlPlaneSubnetForZone(zone string) *infrav1.SubnetSpec {
+	return subnetOrEmpty(s.AzureCluster.Spec.NetworkSpec.GetControlPlaneSubnetForZone(zone))
 }
 
-// NodeSubnet returns the cluster node subnet.
+// NodeSubnet returns the first cluster node subnet, or an empty subnet if the cluster has no node subnet.
 func (s *ClusterScope) NodeSubnet() *infrav1.SubnetSpec {
-	return s.AzureCluster.Spec.NetworkSpec.GetNodeSubnet()
+	return subnetOrEmpty(s.AzureCluster.Spec.NetworkSpec.GetNodeSubnet())
+}
+
+// subnetOrEmpty returns the subnet, or an empty subnet if it is nil, so the fields of the subnet of a role can be read
+// without nil checks.
+func subnetOrEmpty(subnet *infrav1.SubnetSpec) *infrav1.SubnetSpec {
+	if subnet == nil {
+		return &infrav1.SubnetSpec{}
+	}
+	return subnet
+}
+
+// NodeSubnets returns all the cluster node subnets.
+func (s *ClusterScope) NodeSubnets() infrav1.Subnets {
+	return s.AzureCluster.Spec.NetworkSpec.GetNodeSubnets()
 }
 
 // ResourceGroup returns the cluster resource group.
@@ -170,11 +816,42 @@ func (s *ClusterScope) ResourceGroup() string {
 	return s.AzureCluster.Spec.ResourceGroup
 }
 
+// IsResourceGroupManaged returns true if the cluster resource group is created and deleted by the provider.
+func (s *ClusterScope) IsResourceGroupManaged() bool {
+	return s.AzureCluster.Spec.ResourceGroupID == ""
+}
+
+// NetworkResourceGroup returns the resource group of the networking resources of the cluster, the cluster resource
+// group by default.
+func (s *ClusterScope) NetworkResourceGroup() string {
+	if s.AzureCluster.Spec.NetworkResourceGroup != "" {
+		return s.AzureCluster.Spec.NetworkResourceGroup
+	}
+	return s.ResourceGroup()
+}
+
+// IsNetworkResourceGroupManaged returns true if the networking resources are in the cluster resource group and it is
+// managed by the provider. A network resource group different from the cluster resource group is never managed.
+func (s *ClusterScope) IsNetworkResourceGroupManaged() bool {
+	return strings.EqualFold(s.NetworkResourceGroup(), s.ResourceGroup()) && s.IsResourceGroupManaged()
+}
+
+// SetResourceGroupID records the ID of a pre-existing resource group which is not managed by the provider.
+func (s *ClusterScope) SetResourceGroupID(id string) {
+	s.AzureCluster.Spec.ResourceGroupID = id
+}
+
 // ClusterName returns the cluster name.
 func (s *ClusterScope) ClusterName() string {
 	return s.Cluster.Name
 }
 
+// ResourceName returns the name of a resource of the cluster, made of a prefix, the cluster name and a suffix, within
+// the length Azure accepts. The cluster name is truncated and hashed when the name would be longer.
+func (s *ClusterScope) ResourceName(prefix, suffix string) string {
+	return azure.GenerateResourceName(prefix, s.ClusterName(), suffix, azure.MaxResourceNameLength)
+}
+
 // Namespace returns the cluster namespace.
 func (s *ClusterScope) Namespace() string {
 	return s.Cluster.Namespace
@@ -185,9 +862,54 @@ func (s *ClusterScope) Location() string {
 	return s.AzureCluster.Spec.Location
 }
 
-// GenerateFQDN generates a fully qualified domain name, based on the public IP name and cluster location.
+// GenerateFQDN generates a fully qualified domain name, based on the DNS label of the API server public IP and cluster
+// location. Clusters with a private API server get a name in the cluster's private DNS zone instead.
 func (s *ClusterScope) GenerateFQDN() string {
-	return fmt.Sprintf("%s.%s.%s", s.Network().APIServerIP.Name, s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix)
+	if s.IsAPIServerPrivate() {
+		return s.GeneratePrivateFQDN()
+	}
+	return fmt.Sprintf("%s.%s.%s", s.APIServerDNSLabel(), s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix)
+}
+
+// APIServerDNSLabel returns the DNS label of the API server public IP, which defaults to the name of the public IP.
+func (s *ClusterScope) APIServerDNSLabel() string {
+	if label := s.AzureCluster.Spec.NetworkSpec.APIServerLB.DNSLabel; label != "" {
+		return label
+	}
+	return strings.ToLower(s.Network().APIServerIP.Name)
+}
+
+// GeneratePrivateFQDN generates the fully qualified domain name of the API server in the private DNS zone of the cluster.
+func (s *ClusterScope) GeneratePrivateFQDN() string {
+	return fmt.Sprintf("%s.%s", azure.PrivateAPIServerHostname, s.PrivateDNSZoneName())
+}
+
+// PrivateDNSZoneName returns the name of the private DNS zone of the cluster.
+func (s *ClusterScope) PrivateDNSZoneName() string {
+	if s.AzureCluster.Spec.NetworkSpec.PrivateDNSZoneName != "" {
+		return s.AzureCluster.Spec.NetworkSpec.PrivateDNSZoneName
+	}
+	return azure.GenerateDefaultPrivateDNSZoneName(s.ClusterName())
+}
+
+// PrivateDNSSpec returns the private DNS zone spec, or nil if the API server is not private.
+func (s *ClusterScope) PrivateDNSSpec() *azure.PrivateDNSSpec {
+	if !s.IsAPIServerPrivate() {
+		return nil
+	}
+	return &azure.PrivateDNSSpec{
+		ZoneName:          s.PrivateDNSZoneName(),
+		LinkName:          azure.GenerateVNetLinkName(s.Vnet().Name),
+		VNetName:          s.Vnet().Name,
+		VNetResourceGroup: s.Vnet().ResourceGroup,
+		RecordName:        azure.PrivateAPIServerHostname,
+		RecordIP:          s.ControlPlaneSubnet().InternalLBIPAddress,
+	}
+}
+
+// GenerateIPv6FQDN generates a fully qualified domain name for the IPv6 API server public IP of a dual-stack cluster.
+func (s *ClusterScope) GenerateIPv6FQDN() string {
+	return fmt.Sprintf("%s.%s.%s", s.Network().APIServerIPv6.Name, s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix)
 }
 
 // ListOptionsLabelSelector returns a ListOptions with a label selector for clusterName.
@@ -199,23 +921,151 @@ func (s *ClusterScope) ListOptionsLabelSelector() client.ListOption {
 
 // PatchObject persists the cluster configuration and status.
 func (s *ClusterScope) PatchObject(ctx context.Context) error {
-	return s.patchHelper.Patch(ctx, s.AzureCluster)
+	return s.patchHelper.Patch(ctx, s.AzureCluster, patch.WithOwnedConditions{Conditions: ownedClusterConditions})
 }
 
 // Close closes the current scope persisting the cluster configuration and status.
 func (s *ClusterScope) Close(ctx context.Context) error {
-	return s.patchHelper.Patch(ctx, s.AzureCluster)
+	return s.patchHelper.Patch(ctx, s.AzureCluster, patch.WithOwnedConditions{Conditions: ownedClusterConditions})
+}
+
+// ownedClusterConditions are the AzureCluster conditions set by this controller, which win over
+// the ones of the cluster in the API server when patching.
+var ownedClusterConditions = []clusterv1.ConditionType{
+	clusterv1.ReadyCondition,
+	infrav1.NetworkInfrastructureReadyCondition,
+	infrav1.VNetReadyCondition,
+	infrav1.SubnetsReadyCondition,
+	infrav1.SecurityGroupsReadyCondition,
+	infrav1.LoadBalancersReadyCondition,
+	infrav1.PublicIPsReadyCondition,
+	infrav1.VCPUQuotaAvailableCondition,
+}
+
+// SetConditionTrue marks the AzureCluster condition as true.
+func (s *ClusterScope) SetConditionTrue(conditionType clusterv1.ConditionType) {
+	conditions.MarkTrue(s.AzureCluster, conditionType)
+}
+
+// SetConditionFalse marks the AzureCluster condition as false with the reason and the message of the error
+// which made the reconciliation of the resource fail.
+func (s *ClusterScope) SetConditionFalse(conditionType clusterv1.ConditionType, reason string, err error) {
+	conditions.MarkFalse(s.AzureCluster, conditionType, reason, clusterv1.ConditionSeverityError, err.Error())
+}
+
+// SetConditionInProgress sets the condition of the AzureCluster to false while a long-running operation of its
+// service is in progress.
+func (s *ClusterScope) SetConditionInProgress(conditionType clusterv1.ConditionType, err error) {
+	conditions.MarkFalse(s.AzureCluster, conditionType, infrav1.OperationInProgressReason, clusterv1.ConditionSeverityInfo, err.Error())
+}
+
+// Event records an event on the AzureCluster.
+func (s *ClusterScope) Event(reason, message string) {
+	record.Event(s.AzureCluster, reason, message)
 }
 
-// AdditionalTags returns AdditionalTags from the scope's AzureCluster.
+// AdditionalTags returns AdditionalTags from the scope's AzureCluster, merged with its EnforcedTags, which take
+// precedence.
 func (s *ClusterScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
 	if s.AzureCluster.Spec.AdditionalTags != nil {
 		tags = s.AzureCluster.Spec.AdditionalTags.DeepCopy()
 	}
+	tags.Merge(s.EnforcedTags())
+	return tags
+}
+
+// EnforcedTags returns EnforcedTags from the scope's AzureCluster.
+func (s *ClusterScope) EnforcedTags() infrav1.Tags {
+	tags := make(infrav1.Tags)
+	if s.AzureCluster.Spec.EnforcedTags != nil {
+		tags = s.AzureCluster.Spec.EnforcedTags.DeepCopy()
+	}
 	return tags
 }
 
+// LastAppliedTags returns the additional tags applied to the cluster resources by the last successful reconcile.
+func (s *ClusterScope) LastAppliedTags() infrav1.Tags {
+	tags := make(infrav1.Tags)
+	value, ok := s.AzureCluster.GetAnnotations()[azure.TagsLastAppliedAnnotation]
+	if !ok {
+		return tags
+	}
+	if err := json.Unmarshal([]byte(value), &tags); err != nil {
+		// without the last applied tags, tags removed from the spec are left on the resources
+		s.Error(err, "failed to unmarshal annotation", "annotation", azure.TagsLastAppliedAnnotation)
+		return make(infrav1.Tags)
+	}
+	return tags
+}
+
+// UpdateLastAppliedTags records the additional tags of the cluster as applied to the cluster resources.
+func (s *ClusterScope) UpdateLastAppliedTags() error {
+	annotations := s.AzureCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	tags := s.AdditionalTags()
+	if len(tags) == 0 {
+		delete(annotations, azure.TagsLastAppliedAnnotation)
+	} else {
+		b, err := json.Marshal(tags)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal last applied tags")
+		}
+		annotations[azure.TagsLastAppliedAnnotation] = string(b)
+	}
+	s.AzureCluster.SetAnnotations(annotations)
+	return nil
+}
+
+// RemovedAdditionalAPIServerIPNames returns the additional public IPs of the API server load balancer applied by a
+// previous reconcile which were removed from the spec since.
+func (s *ClusterScope) RemovedAdditionalAPIServerIPNames() []string {
+	value, ok := s.AzureCluster.GetAnnotations()[azure.AdditionalAPIServerIPsLastAppliedAnnotation]
+	if !ok {
+		return nil
+	}
+	var applied []string
+	if err := json.Unmarshal([]byte(value), &applied); err != nil {
+		// without the last applied public IPs, public IPs removed from the spec are left in Azure
+		s.Error(err, "failed to unmarshal annotation", "annotation", azure.AdditionalAPIServerIPsLastAppliedAnnotation)
+		return nil
+	}
+	current := make(map[string]bool)
+	for _, name := range s.AzureCluster.Spec.NetworkSpec.APIServerLB.AdditionalPublicIPNames {
+		current[name] = true
+	}
+	var removed []string
+	for _, name := range applied {
+		if !current[name] {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// UpdateLastAppliedAdditionalAPIServerIPNames records the additional public IPs of the API server load balancer as
+// applied.
+func (s *ClusterScope) UpdateLastAppliedAdditionalAPIServerIPNames() error {
+	annotations := s.AzureCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	names := s.AzureCluster.Spec.NetworkSpec.APIServerLB.AdditionalPublicIPNames
+	if len(names) == 0 {
+		delete(annotations, azure.AdditionalAPIServerIPsLastAppliedAnnotation)
+	} else {
+		b, err := json.Marshal(names)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal last applied additional API server public IPs")
+		}
+		annotations[azure.AdditionalAPIServerIPsLastAppliedAnnotation] = string(b)
+	}
+	s.AzureCluster.SetAnnotations(annotations)
+	return nil
+}
+
 // APIServerPort returns the APIServerPort to use when creating the load balancer.
 func (s *ClusterScope) APIServerPort() int32 {
 	if s.Cluster.Spec.ClusterNetwork != nil && s.Cluster.Spec.ClusterNetwork.APIServerPort != nil {
@@ -224,6 +1074,88 @@ func (s *ClusterScope) APIServerPort() int32 {
 	return 6443
 }
 
+// ControlPlaneEndpoint returns the endpoint of the API server of the cluster. The host is the FQDN of the public IP
+// of the API server for public clusters, or the DNS name read from a pre-existing public IP. Private clusters are
+// reached through the internal load balancer frontend: the host is the FQDN of the API server in the private DNS zone
+// when a custom zone is set, otherwise the IP address of the frontend.
+func (s *ClusterScope) ControlPlaneEndpoint() clusterv1.APIEndpoint {
+	endpoint := clusterv1.APIEndpoint{
+		Host: s.GenerateFQDN(),
+		Port: s.APIServerPort(),
+	}
+	if s.IsAPIServerIPPreExisting() {
+		endpoint.Host = s.Network().APIServerIP.DNSName
+	}
+	if s.IsAPIServerPrivate() && s.AzureCluster.Spec.NetworkSpec.PrivateDNSZoneName == "" {
+		endpoint.Host = s.ControlPlaneSubnet().InternalLBIPAddress
+	}
+	return endpoint
+}
+
+// KubeconfigServer returns the URL of the API server to set in the kubeconfigs of the cluster. Public clusters also
+// have an internal load balancer frontend, which is only reachable from within the vnet, so their kubeconfigs use the
+// public endpoint. Private clusters only have the internal frontend, reached at the name of the API server in the
+// custom private DNS zone when one is set, or else at the IP address of the frontend.
+func (s *ClusterScope) KubeconfigServer() (string, error) {
+	endpoint := s.ControlPlaneEndpoint()
+	if endpoint.Host == "" {
+		return "", errors.Errorf("control plane endpoint of cluster %s is not known yet", s.ClusterName())
+	}
+	return fmt.Sprintf("https://%s", net.JoinHostPort(endpoint.Host, strconv.Itoa(int(endpoint.Port)))), nil
+}
+
+// ValidateAPIServerPort checks that the API server port of the cluster can be used as the frontend port of
+// the API server load balancing rule. The port must be in the 1-65535 range and can't be one of the frontend
+// ports of the inbound NAT rules created for SSH access to the control plane machines on the public load
+// balancer: 22, then 2201 to 2219 when several control plane machines share the load balancer, nor one of the
+// frontend ports of the inbound NAT rules of the internal load balancer.
+func (s *ClusterScope) ValidateAPIServerPort() error {
+	port := s.APIServerPort()
+	if port < 1 || port > 65535 {
+		return errors.Errorf("API server port %d of cluster %s must be between 1 and 65535", port, s.ClusterName())
+	}
+	if !s.IsAPIServerPrivate() && (port == 22 || (port >= 2201 && port <= 2219)) {
+		return errors.Errorf("API server port %d of cluster %s collides with the ports 22 and 2201-2219 of the SSH inbound NAT rules of the API server load balancer", port, s.ClusterName())
+	}
+	for _, rule := range s.InternalLBInboundNatRules() {
+		if port >= rule.FrontendPortRangeStart && port <= rule.FrontendPortRangeEnd {
+			return errors.Errorf("API server port %d of cluster %s collides with the ports %d-%d of the inbound NAT rule %s of the internal API server load balancer",
+				port, s.ClusterName(), rule.FrontendPortRangeStart, rule.FrontendPortRangeEnd, rule.Name)
+		}
+	}
+	return nil
+}
+
+// ControlPlaneZones returns the zones the control plane machines are pinned to, in order of preference, or nil when
+// they aren't pinned. Every pinned zone must be one of the availability zones of the location.
+func (s *ClusterScope) ControlPlaneZones(availableZones []string) ([]string, error) {
+	if s.AzureCluster.Spec.FailureDomains == nil || len(s.AzureCluster.Spec.FailureDomains.ControlPlaneZones) == 0 {
+		return nil, nil
+	}
+	available := make(map[string]bool, len(availableZones))
+	for _, zone := range availableZones {
+		available[zone] = true
+	}
+	zones := s.AzureCluster.Spec.FailureDomains.ControlPlaneZones
+	for _, zone := range zones {
+		if !available[zone] {
+			return nil, errors.Errorf("control plane zone %s is not an availability zone of location %s", zone, s.Location())
+		}
+	}
+	return zones, nil
+}
+
+// SetControlPlaneFailureDomain sets a pinned control plane zone as a failure domain eligible for control plane
+// machines, reporting its position in the order of preference.
+func (s *ClusterScope) SetControlPlaneFailureDomain(zone string, order int) {
+	s.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
+		ControlPlane: true,
+		Attributes: map[string]string{
+			infrav1.ControlPlaneZoneOrderAttribute: strconv.Itoa(order),
+		},
+	})
+}
+
 // SetFailureDomain will set the spec for a for a given key
 func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainSpec) {
 	if s.AzureCluster.Status.FailureDomains == nil {
@@ -231,3 +1163,38 @@ func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainS
 	}
 	s.AzureCluster.Status.FailureDomains[id] = spec
 }
+
+// GetLongRunningOperationState returns the state of the long-running operation in progress on a resource of the
+// service, or nil if there is none.
+func (s *ClusterScope) GetLongRunningOperationState(name, service string) *infrav1.Future {
+	for i, future := range s.AzureCluster.Status.LongRunningOperationStates {
+		if future.Name == name && future.ServiceName == service {
+			return &s.AzureCluster.Status.LongRunningOperationStates[i]
+		}
+	}
+	return nil
+}
+
+// SetLongRunningOperationState saves the state of a long-running operation in the status of the AzureCluster,
+// replacing the previous state of the operation on the same resource.
+func (s *ClusterScope) SetLongRunningOperationState(future *infrav1.Future) {
+	if existing := s.GetLongRunningOperationState(future.Name, future.ServiceName); existing != nil {
+		*existing = *future
+		return
+	}
+	s.AzureCluster.Status.LongRunningOperationStates = append(s.AzureCluster.Status.LongRunningOperationStates, *future)
+}
+
+// DeleteLongRunningOperationState deletes the state of the long-running operation on a resource of the service.
+func (s *ClusterScope) DeleteLongRunningOperationState(name, service string) {
+	states := []infrav1.Future{}
+	for _, future := range s.AzureCluster.Status.LongRunningOperationStates {
+		if future.Name != name || future.ServiceName != service {
+			states = append(states, future)
+		}
+	}
+	if len(states) == 0 {
+		states = nil
+	}
+	s.AzureCluster.Status.LongRunningOperationStates = states
+}
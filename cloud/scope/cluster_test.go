@@ -0,0 +1,1382 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+)
+
+func init() {
+	_ = clusterv1.AddToScheme(scheme.Scheme)
+}
+
+func newTestClusterScope(t *testing.T, networkSpec infrav1.NetworkSpec) *ClusterScope {
+	g := NewWithT(t)
+
+	// setCredentials resolves the cloud environment from the process environment when the scope is created
+	defer test.SetEnv("AZURE_ENVIRONMENT", "AzurePublicCloud")()
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+	}
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, cluster)
+
+	clusterScope, err := NewClusterScope(ClusterScopeParams{
+		AzureClients: AzureClients{
+			Authorizer:                 autorest.NullAuthorizer{},
+			ResourceManagerVMDNSSuffix: "cloudapp.azure.com",
+		},
+		Client:  client,
+		Cluster: cluster,
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				Location:       "westus2",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				NetworkSpec:    networkSpec,
+			},
+			Status: infrav1.AzureClusterStatus{
+				Network: infrav1.Network{
+					APIServerIP: infrav1.PublicIP{Name: "my-cluster-api"},
+				},
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	return clusterScope
+}
+
+func TestNewClusterScopeRequiresSubnets(t *testing.T) {
+	tests := []struct {
+		name          string
+		subnets       infrav1.Subnets
+		expectedError string
+	}{
+		{
+			name: "both subnets",
+			subnets: infrav1.Subnets{
+				{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+				{Name: "node-subnet", Role: infrav1.SubnetNode},
+			},
+		},
+		{
+			name: "missing control plane subnet",
+			subnets: infrav1.Subnets{
+				{Name: "node-subnet", Role: infrav1.SubnetNode},
+			},
+			expectedError: "failed to generate new scope: AzureCluster default/my-cluster has no subnet with the control-plane role",
+		},
+		{
+			name: "missing node subnet",
+			subnets: infrav1.Subnets{
+				{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			},
+			expectedError: "failed to generate new scope: AzureCluster default/my-cluster has no subnet with the node role",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer test.SetEnv("AZURE_ENVIRONMENT", "AzurePublicCloud")()
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			}
+			_, err := NewClusterScope(ClusterScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+					Spec: infrav1.AzureClusterSpec{
+						SubscriptionID: "123",
+						NetworkSpec:    infrav1.NetworkSpec{Subnets: tc.subnets},
+					},
+				},
+			})
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestSubnetAccessorsWithoutSubnets(t *testing.T) {
+	g := NewWithT(t)
+	s := &ClusterScope{
+		Cluster:      &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{},
+	}
+
+	// a scope built without its subnets returns empty subnets, and the specs built from them don't panic
+	g.Expect(s.ControlPlaneSubnet()).To(Equal(&infrav1.SubnetSpec{}))
+	g.Expect(s.ControlPlaneSubnetForZone("1")).To(Equal(&infrav1.SubnetSpec{}))
+	g.Expect(s.NodeSubnet()).To(Equal(&infrav1.SubnetSpec{}))
+	g.Expect(func() { s.LBSpecs() }).NotTo(Panic())
+	g.Expect(s.ValidateInternalLBIPAddress()).To(Succeed())
+}
+
+func TestAPIServerLBSpecs(t *testing.T) {
+	tests := []struct {
+		name            string
+		lbType          infrav1.LBType
+		expectedLBs     []string
+		expectedIPs     []string
+		expectedFQDN    string
+		expectedPrivate bool
+	}{
+		{
+			name:            "public API server",
+			lbType:          infrav1.Public,
+			expectedLBs:     []string{"my-cluster-internal-lb", "my-cluster-public-lb", "my-cluster"},
+			expectedIPs:     []string{"pip-my-cluster-node-outbound", "my-cluster-api"},
+			expectedFQDN:    "my-cluster-api.westus2.cloudapp.azure.com",
+			expectedPrivate: false,
+		},
+		{
+			name:            "internal API server",
+			lbType:          infrav1.Internal,
+			expectedLBs:     []string{"my-cluster-internal-lb", "my-cluster"},
+			expectedIPs:     []string{"pip-my-cluster-node-outbound"},
+			expectedFQDN:    "apiserver.my-cluster.capz.io",
+			expectedPrivate: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+					{Name: "node-subnet", Role: infrav1.SubnetNode},
+				},
+				APIServerLB: infrav1.LoadBalancerSpec{Type: tc.lbType},
+			})
+
+			var lbNames []string
+			for _, lb := range s.LBSpecs() {
+				lbNames = append(lbNames, lb.Name)
+			}
+			var ipNames []string
+			for _, ip := range s.PublicIPSpecs() {
+				ipNames = append(ipNames, ip.Name)
+			}
+
+			g.Expect(s.IsAPIServerPrivate()).To(Equal(tc.expectedPrivate))
+			g.Expect(lbNames).To(Equal(tc.expectedLBs))
+			g.Expect(ipNames).To(Equal(tc.expectedIPs))
+			g.Expect(s.GenerateFQDN()).To(Equal(tc.expectedFQDN))
+		})
+	}
+}
+
+func TestAPIServerDNSLabel(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.APIServerDNSLabel()).To(Equal("my-cluster-api"))
+
+	s.AzureCluster.Spec.NetworkSpec.APIServerLB.DNSLabel = "my-label"
+	g.Expect(s.APIServerDNSLabel()).To(Equal("my-label"))
+	g.Expect(s.GenerateFQDN()).To(Equal("my-label.westus2.cloudapp.azure.com"))
+	for _, ip := range s.PublicIPSpecs() {
+		if ip.Name == "my-cluster-api" {
+			g.Expect(ip.DNSLabel).To(Equal("my-label"))
+		} else {
+			g.Expect(ip.DNSLabel).To(BeEmpty())
+		}
+	}
+}
+
+func TestAdditionalAPIServerIPs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+		APIServerLB: infrav1.LoadBalancerSpec{
+			AdditionalPublicIPNames: []string{"my-cluster-api-2", "my-cluster-api-3"},
+		},
+	})
+
+	var ipNames []string
+	for _, ip := range s.PublicIPSpecs() {
+		ipNames = append(ipNames, ip.Name)
+	}
+	g.Expect(ipNames).To(Equal([]string{"pip-my-cluster-node-outbound", "my-cluster-api", "my-cluster-api-2", "my-cluster-api-3"}))
+	for _, lb := range s.LBSpecs() {
+		if lb.Role == infrav1.APIServerRole {
+			g.Expect(lb.AdditionalPublicIPNames).To(Equal([]string{"my-cluster-api-2", "my-cluster-api-3"}))
+		} else {
+			g.Expect(lb.AdditionalPublicIPNames).To(BeEmpty())
+		}
+	}
+}
+
+func TestAPIServerLoadDistribution(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+		APIServerLB: infrav1.LoadBalancerSpec{
+			LoadDistribution: infrav1.LoadDistributionSourceIP,
+		},
+	})
+
+	for _, lb := range s.LBSpecs() {
+		if lb.Role == infrav1.APIServerRole || lb.Role == infrav1.InternalRole {
+			g.Expect(lb.LoadDistribution).To(Equal(infrav1.LoadDistributionSourceIP))
+		} else {
+			g.Expect(lb.LoadDistribution).To(BeEmpty())
+		}
+	}
+}
+
+func TestNodeSubnets(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet-1", Role: infrav1.SubnetNode},
+			{Name: "node-subnet-2", Role: infrav1.SubnetNode},
+		},
+	})
+
+	g.Expect(s.NodeSubnet().Name).To(Equal("node-subnet-1"))
+	g.Expect(s.NodeSubnets()).To(HaveLen(2))
+	g.Expect(s.NodeSubnets()[1].Name).To(Equal("node-subnet-2"))
+}
+
+func TestValidateInternalLBIPAddress(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ipAddress     string
+		cidrBlock     string
+		expectedError string
+	}{
+		{
+			name:      "no static IP",
+			cidrBlock: "10.0.0.0/16",
+		},
+		{
+			name:      "static IP in the subnet",
+			ipAddress: "10.0.0.100",
+			cidrBlock: "10.0.0.0/16",
+		},
+		{
+			name:          "static IP outside the subnet",
+			ipAddress:     "10.1.0.100",
+			cidrBlock:     "10.0.0.0/16",
+			expectedError: "internal load balancer IP 10.1.0.100 is not in the CIDR block 10.0.0.0/16 of subnet cp-subnet",
+		},
+		{
+			name:          "network address",
+			ipAddress:     "10.0.1.0",
+			cidrBlock:     "10.0.1.0/24",
+			expectedError: "internal load balancer IP 10.0.1.0 is reserved by Azure in subnet cp-subnet",
+		},
+		{
+			name:          "DNS address",
+			ipAddress:     "10.0.1.3",
+			cidrBlock:     "10.0.1.0/24",
+			expectedError: "internal load balancer IP 10.0.1.3 is reserved by Azure in subnet cp-subnet",
+		},
+		{
+			name:      "first usable address",
+			ipAddress: "10.0.1.4",
+			cidrBlock: "10.0.1.0/24",
+		},
+		{
+			name:          "broadcast address",
+			ipAddress:     "10.0.1.255",
+			cidrBlock:     "10.0.1.0/24",
+			expectedError: "internal load balancer IP 10.0.1.255 is reserved by Azure in subnet cp-subnet",
+		},
+		{
+			name:          "invalid IP",
+			ipAddress:     "fd00::4",
+			cidrBlock:     "10.0.1.0/24",
+			expectedError: "internal load balancer IP fd00::4 is not a valid IPv4 address",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, CidrBlock: tc.cidrBlock, InternalLBIPAddress: tc.ipAddress},
+					{Name: "node-subnet", Role: infrav1.SubnetNode},
+				},
+			})
+			err := s.ValidateInternalLBIPAddress()
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerPort(t *testing.T) {
+	testcases := []struct {
+		name          string
+		port          *int32
+		lbType        infrav1.LBType
+		expectedError string
+	}{
+		{
+			name:   "default port",
+			lbType: infrav1.Public,
+		},
+		{
+			name:   "custom port",
+			port:   to.Int32Ptr(443),
+			lbType: infrav1.Public,
+		},
+		{
+			name:          "port out of range",
+			port:          to.Int32Ptr(0),
+			lbType:        infrav1.Public,
+			expectedError: "API server port 0 of cluster my-cluster must be between 1 and 65535",
+		},
+		{
+			name:          "port of the SSH NAT rules",
+			port:          to.Int32Ptr(2210),
+			lbType:        infrav1.Public,
+			expectedError: "API server port 2210 of cluster my-cluster collides with the ports 22 and 2201-2219 of the SSH inbound NAT rules of the API server load balancer",
+		},
+		{
+			name:   "SSH port of a private cluster",
+			port:   to.Int32Ptr(22),
+			lbType: infrav1.Internal,
+		},
+		{
+			name:          "port of an inbound NAT rule of the internal LB",
+			port:          to.Int32Ptr(12380),
+			lbType:        infrav1.Internal,
+			expectedError: "API server port 12380 of cluster my-cluster collides with the ports 12379-12381 of the inbound NAT rule etcd of the internal API server load balancer",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+					{Name: "node-subnet", Role: infrav1.SubnetNode},
+				},
+				APIServerLB: infrav1.LoadBalancerSpec{
+					Type: tc.lbType,
+					InternalLBInboundNatRules: []infrav1.InboundNatRule{
+						{Name: "etcd", FrontendPortRangeStart: 12379, FrontendPortRangeEnd: 12381, BackendPort: 2379},
+					},
+				},
+			})
+			s.Cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{APIServerPort: tc.port}
+			err := s.ValidateAPIServerPort()
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestControlPlaneEndpoint(t *testing.T) {
+	testcases := []struct {
+		name             string
+		lbType           infrav1.LBType
+		dnsZoneName      string
+		port             *int32
+		expectedEndpoint clusterv1.APIEndpoint
+	}{
+		{
+			name:             "public cluster",
+			lbType:           infrav1.Public,
+			expectedEndpoint: clusterv1.APIEndpoint{Host: "my-cluster-api.westus2.cloudapp.azure.com", Port: 6443},
+		},
+		{
+			name:             "public cluster with a custom API server port",
+			lbType:           infrav1.Public,
+			port:             to.Int32Ptr(443),
+			expectedEndpoint: clusterv1.APIEndpoint{Host: "my-cluster-api.westus2.cloudapp.azure.com", Port: 443},
+		},
+		{
+			name:             "private cluster",
+			lbType:           infrav1.Internal,
+			expectedEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.100", Port: 6443},
+		},
+		{
+			name:             "private cluster with a custom private DNS zone",
+			lbType:           infrav1.Internal,
+			dnsZoneName:      "example.internal",
+			expectedEndpoint: clusterv1.APIEndpoint{Host: "apiserver.example.internal", Port: 6443},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, InternalLBIPAddress: "10.0.0.100"},
+					{Name: "node-subnet", Role: infrav1.SubnetNode},
+				},
+				APIServerLB:        infrav1.LoadBalancerSpec{Type: tc.lbType},
+				PrivateDNSZoneName: tc.dnsZoneName,
+			})
+			s.Cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{APIServerPort: tc.port}
+			g.Expect(s.ControlPlaneEndpoint()).To(Equal(tc.expectedEndpoint))
+		})
+	}
+}
+
+func TestKubeconfigServer(t *testing.T) {
+	testcases := []struct {
+		name           string
+		apiServerLB    infrav1.LoadBalancerSpec
+		dnsZoneName    string
+		internalLBIP   string
+		dnsName        string
+		port           *int32
+		expectedServer string
+		expectedError  string
+	}{
+		{
+			name:           "public cluster",
+			apiServerLB:    infrav1.LoadBalancerSpec{Type: infrav1.Public},
+			internalLBIP:   "10.0.0.100",
+			expectedServer: "https://my-cluster-api.westus2.cloudapp.azure.com:6443",
+		},
+		{
+			name:           "public cluster with a custom API server port",
+			apiServerLB:    infrav1.LoadBalancerSpec{Type: infrav1.Public},
+			internalLBIP:   "10.0.0.100",
+			port:           to.Int32Ptr(443),
+			expectedServer: "https://my-cluster-api.westus2.cloudapp.azure.com:443",
+		},
+		{
+			name:           "public cluster with a pre-existing public IP",
+			apiServerLB:    infrav1.LoadBalancerSpec{Type: infrav1.Public, PublicIPID: "/subscriptions/123/resourceGroups/my-ips/providers/Microsoft.Network/publicIPAddresses/my-static-ip"},
+			internalLBIP:   "10.0.0.100",
+			dnsName:        "api.example.com",
+			expectedServer: "https://api.example.com:6443",
+		},
+		{
+			name:          "public cluster with a pre-existing public IP not validated yet",
+			apiServerLB:   infrav1.LoadBalancerSpec{Type: infrav1.Public, PublicIPID: "/subscriptions/123/resourceGroups/my-ips/providers/Microsoft.Network/publicIPAddresses/my-static-ip"},
+			internalLBIP:  "10.0.0.100",
+			expectedError: "control plane endpoint of cluster my-cluster is not known yet",
+		},
+		{
+			name:           "private cluster",
+			apiServerLB:    infrav1.LoadBalancerSpec{Type: infrav1.Internal},
+			internalLBIP:   "10.0.0.100",
+			expectedServer: "https://10.0.0.100:6443",
+		},
+		{
+			name:           "private cluster with an IPv6 internal load balancer address",
+			apiServerLB:    infrav1.LoadBalancerSpec{Type: infrav1.Internal},
+			internalLBIP:   "fd00::100",
+			expectedServer: "https://[fd00::100]:6443",
+		},
+		{
+			name:           "private cluster with a custom private DNS zone",
+			apiServerLB:    infrav1.LoadBalancerSpec{Type: infrav1.Internal},
+			dnsZoneName:    "example.internal",
+			internalLBIP:   "10.0.0.100",
+			expectedServer: "https://apiserver.example.internal:6443",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, InternalLBIPAddress: tc.internalLBIP},
+					{Name: "node-subnet", Role: infrav1.SubnetNode},
+				},
+				APIServerLB:        tc.apiServerLB,
+				PrivateDNSZoneName: tc.dnsZoneName,
+			})
+			s.Cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{APIServerPort: tc.port}
+			s.Network().APIServerIP.DNSName = tc.dnsName
+			server, err := s.KubeconfigServer()
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(server).To(Equal(tc.expectedServer))
+		})
+	}
+}
+
+func TestEnforcedTags(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	s.AzureCluster.Spec.AdditionalTags = infrav1.Tags{"env": "dev", "team": "platform"}
+	s.AzureCluster.Spec.EnforcedTags = infrav1.Tags{"env": "prod", "costCenter": "1234"}
+	machineScope := &MachineScope{
+		ClusterDescriber: s,
+		AzureMachine: &infrav1.AzureMachine{
+			Spec: infrav1.AzureMachineSpec{AdditionalTags: infrav1.Tags{"costCenter": "5678", "role": "gpu"}},
+		},
+	}
+
+	// the enforced tags take precedence over the additional tags of the cluster and of its machines
+	g.Expect(s.AdditionalTags()).To(Equal(infrav1.Tags{"env": "prod", "team": "platform", "costCenter": "1234"}))
+	g.Expect(machineScope.AdditionalTags()).To(Equal(infrav1.Tags{"env": "prod", "team": "platform", "costCenter": "1234", "role": "gpu"}))
+}
+
+func TestControlPlaneSubnets(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		APIServerLB: infrav1.LoadBalancerSpec{InternalLBZones: []string{"2"}},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet-1", Role: infrav1.SubnetControlPlane, CidrBlock: "10.0.1.0/24", Zones: []string{"1"}},
+			{Name: "cp-subnet-2", Role: infrav1.SubnetControlPlane, CidrBlock: "10.0.2.0/24", Zones: []string{"2", "3"}},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+
+	g.Expect(s.ControlPlaneSubnets()).To(HaveLen(2))
+	g.Expect(s.ControlPlaneSubnetForZone("1").Name).To(Equal("cp-subnet-1"))
+	g.Expect(s.ControlPlaneSubnetForZone("3").Name).To(Equal("cp-subnet-2"))
+	g.Expect(s.ControlPlaneSubnetForZone("").Name).To(Equal("cp-subnet-1"))
+
+	// the internal load balancer frontend is in the control plane subnet of its zone
+	g.Expect(s.ControlPlaneSubnet().Name).To(Equal("cp-subnet-2"))
+	for _, lb := range s.LBSpecs() {
+		if lb.Role == infrav1.InternalRole {
+			g.Expect(lb.SubnetName).To(Equal("cp-subnet-2"))
+			g.Expect(lb.SubnetCidr).To(Equal("10.0.2.0/24"))
+		}
+	}
+
+	// a control plane machine is placed in the control plane subnet of its failure domain
+	machineScope := &MachineScope{
+		ClusterDescriber: s,
+		Machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{clusterv1.MachineControlPlaneLabelName: ""}},
+			Spec:       clusterv1.MachineSpec{FailureDomain: to.StringPtr("1")},
+		},
+		AzureMachine: &infrav1.AzureMachine{},
+	}
+	g.Expect(machineScope.Subnet().Name).To(Equal("cp-subnet-1"))
+	machineScope.Machine.Spec.FailureDomain = to.StringPtr("3")
+	g.Expect(machineScope.Subnet().Name).To(Equal("cp-subnet-2"))
+}
+
+func TestNodeOutboundIPs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+		NodeOutboundLB: &infrav1.NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(3)},
+	})
+
+	expectedNames := []string{"pip-my-cluster-node-outbound", "pip-my-cluster-node-outbound-2", "pip-my-cluster-node-outbound-3"}
+	g.Expect(s.NodeOutboundIPNames()).To(Equal(expectedNames))
+	var ipNames []string
+	for _, ip := range s.PublicIPSpecs() {
+		ipNames = append(ipNames, ip.Name)
+	}
+	g.Expect(ipNames).To(ContainElements(expectedNames))
+	for _, lb := range s.LBSpecs() {
+		if lb.Role == infrav1.NodeOutboundRole {
+			g.Expect(lb.PublicIPName).To(Equal("pip-my-cluster-node-outbound"))
+			g.Expect(lb.AdditionalPublicIPNames).To(Equal(expectedNames[1:]))
+		}
+	}
+}
+
+func TestNodeOutboundIPTags(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+		NodeOutboundLB: &infrav1.NodeOutboundLBSpec{PublicIPTags: infrav1.Tags{"firewall": "allow-egress"}},
+	})
+	for _, ip := range s.PublicIPSpecs() {
+		if ip.Name == "pip-my-cluster-node-outbound" {
+			g.Expect(ip.AdditionalTags).To(Equal(infrav1.Tags{"firewall": "allow-egress"}))
+		} else {
+			g.Expect(ip.AdditionalTags).To(BeEmpty())
+		}
+	}
+}
+
+func TestNodeOutboundLBOutboundRule(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+		NodeOutboundLB: &infrav1.NodeOutboundLBSpec{AllocatedOutboundPorts: to.Int32Ptr(1024), IdleTimeoutInMinutes: to.Int32Ptr(30)},
+	})
+	g.Expect(s.LBSpecs()).To(ContainElement(azure.LBSpec{
+		Name:                    "my-cluster",
+		PublicIPName:            "pip-my-cluster-node-outbound",
+		AdditionalPublicIPNames: []string{},
+		Role:                    infrav1.NodeOutboundRole,
+		SKU:                     infrav1.SKUStandard,
+		AllocatedOutboundPorts:  1024,
+		IdleTimeoutInMinutes:    30,
+	}))
+}
+
+func TestNodeOutboundLBDisabled(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.NodeOutboundLBName()).To(Equal("my-cluster"))
+
+	s.AzureCluster.Spec.NetworkSpec.NodeOutboundLBDisabled = true
+	g.Expect(s.NodeOutboundLBName()).To(BeEmpty())
+	for _, ip := range s.PublicIPSpecs() {
+		g.Expect(ip.Name).NotTo(Equal("pip-my-cluster-node-outbound"))
+	}
+	for _, lb := range s.LBSpecs() {
+		g.Expect(lb.Role).NotTo(Equal(infrav1.NodeOutboundRole))
+	}
+}
+
+func TestResourceNamesOfLongClusterName(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+		NodeOutboundLB: &infrav1.NodeOutboundLBSpec{FrontendIPsCount: to.Int32Ptr(2)},
+	})
+	g.Expect(s.ResourceName("pip-", "-node-outbound")).To(Equal("pip-my-cluster-node-outbound"))
+
+	s.Cluster.Name = strings.Repeat("my-long-cluster-name-", 5)
+	g.Expect(len(s.NodeOutboundLBName())).To(BeNumerically("<=", azure.MaxResourceNameLength))
+	names := map[string]bool{}
+	for _, lb := range s.LBSpecs() {
+		g.Expect(len(lb.Name)).To(BeNumerically("<=", azure.MaxResourceNameLength), "name of load balancer %s", lb.Name)
+		g.Expect(names).NotTo(HaveKey(lb.Name))
+		names[lb.Name] = true
+	}
+	for _, ip := range s.PublicIPSpecs() {
+		g.Expect(len(ip.Name)).To(BeNumerically("<=", azure.MaxResourceNameLength), "name of public IP %s", ip.Name)
+		g.Expect(names).NotTo(HaveKey(ip.Name))
+		names[ip.Name] = true
+	}
+}
+
+func TestNetworkResourceGroup(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.NetworkResourceGroup()).To(Equal("my-rg"))
+	g.Expect(s.IsNetworkResourceGroupManaged()).To(Equal(s.IsResourceGroupManaged()))
+
+	s.AzureCluster.Spec.NetworkResourceGroup = "MY-RG"
+	g.Expect(s.IsNetworkResourceGroupManaged()).To(Equal(s.IsResourceGroupManaged()))
+
+	s.AzureCluster.Spec.NetworkResourceGroup = "my-network-rg"
+	g.Expect(s.NetworkResourceGroup()).To(Equal("my-network-rg"))
+	g.Expect(s.IsNetworkResourceGroupManaged()).To(BeFalse())
+}
+
+func TestControlPlaneOutboundLB(t *testing.T) {
+	g := NewWithT(t)
+	networkSpec := infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+		ControlPlaneOutboundLB: &infrav1.ControlPlaneOutboundLBSpec{IdleTimeoutInMinutes: to.Int32Ptr(15)},
+	}
+
+	s := newTestClusterScope(t, networkSpec)
+	g.Expect(s.ControlPlaneOutboundLBName()).To(BeEmpty())
+	for _, lb := range s.LBSpecs() {
+		g.Expect(lb.Role).NotTo(Equal(infrav1.ControlPlaneOutboundRole))
+	}
+
+	networkSpec.APIServerLB.Type = infrav1.Internal
+	s = newTestClusterScope(t, networkSpec)
+	g.Expect(s.ControlPlaneOutboundLBName()).To(Equal("my-cluster-outbound-lb"))
+	var ipNames []string
+	for _, ip := range s.PublicIPSpecs() {
+		ipNames = append(ipNames, ip.Name)
+	}
+	g.Expect(ipNames).To(ContainElement("pip-my-cluster-controlplane-outbound"))
+	g.Expect(s.LBSpecs()).To(ContainElement(azure.LBSpec{
+		Name:                 "my-cluster-outbound-lb",
+		PublicIPName:         "pip-my-cluster-controlplane-outbound",
+		Role:                 infrav1.ControlPlaneOutboundRole,
+		SKU:                  infrav1.SKUStandard,
+		IdleTimeoutInMinutes: 15,
+	}))
+}
+
+func TestSetConditions(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+
+	s.SetConditionTrue(infrav1.VNetReadyCondition)
+	s.SetConditionFalse(infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, errors.New("subnet is in use"))
+
+	g.Expect(conditions.IsTrue(s.AzureCluster, infrav1.VNetReadyCondition)).To(BeTrue())
+	g.Expect(conditions.IsFalse(s.AzureCluster, infrav1.SubnetsReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(s.AzureCluster, infrav1.SubnetsReadyCondition)).To(Equal(infrav1.SubnetsReconcileFailedReason))
+	g.Expect(conditions.GetMessage(s.AzureCluster, infrav1.SubnetsReadyCondition)).To(Equal("subnet is in use"))
+	g.Expect(*conditions.GetSeverity(s.AzureCluster, infrav1.SubnetsReadyCondition)).To(Equal(clusterv1.ConditionSeverityError))
+}
+
+func TestLongRunningOperationStates(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.GetLongRunningOperationState("my-lb", "loadbalancers")).To(BeNil())
+
+	s.SetLongRunningOperationState(&infrav1.Future{Type: infrav1.PutFuture, ServiceName: "loadbalancers", Name: "my-lb", State: "InProgress"})
+	s.SetLongRunningOperationState(&infrav1.Future{Type: infrav1.PutFuture, ServiceName: "virtualnetworks", Name: "my-lb"})
+	s.SetLongRunningOperationState(&infrav1.Future{Type: infrav1.PutFuture, ServiceName: "loadbalancers", Name: "my-lb", PercentComplete: to.Int32Ptr(50)})
+	g.Expect(s.AzureCluster.Status.LongRunningOperationStates).To(HaveLen(2))
+	g.Expect(s.GetLongRunningOperationState("my-lb", "loadbalancers").PercentComplete).To(Equal(to.Int32Ptr(50)))
+	g.Expect(s.GetLongRunningOperationState("my-lb", "loadbalancers").State).To(BeEmpty())
+
+	s.DeleteLongRunningOperationState("my-lb", "loadbalancers")
+	g.Expect(s.GetLongRunningOperationState("my-lb", "loadbalancers")).To(BeNil())
+	g.Expect(s.GetLongRunningOperationState("my-lb", "virtualnetworks")).NotTo(BeNil())
+	s.DeleteLongRunningOperationState("my-lb", "virtualnetworks")
+	g.Expect(s.AzureCluster.Status.LongRunningOperationStates).To(BeNil())
+
+	s.SetConditionInProgress(infrav1.LoadBalancersReadyCondition, errors.New("operation is not done"))
+	g.Expect(conditions.GetReason(s.AzureCluster, infrav1.LoadBalancersReadyCondition)).To(Equal(infrav1.OperationInProgressReason))
+	g.Expect(*conditions.GetSeverity(s.AzureCluster, infrav1.LoadBalancersReadyCondition)).To(Equal(clusterv1.ConditionSeverityInfo))
+}
+
+func TestLoadBalancerSKU(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.LoadBalancerSKU()).To(Equal(infrav1.SKUStandard))
+
+	s.AzureCluster.Spec.NetworkSpec.LoadBalancerSKU = infrav1.SKUBasic
+	for _, lb := range s.LBSpecs() {
+		g.Expect(lb.SKU).To(Equal(infrav1.SKUBasic))
+	}
+	for _, ip := range s.PublicIPSpecs() {
+		g.Expect(ip.SKU).To(Equal(infrav1.SKUBasic))
+	}
+}
+
+func TestNatGatewaySpecs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet-1", Role: infrav1.SubnetNode, NatGateway: infrav1.NatGateway{Name: "node-natgw"}},
+			{Name: "node-subnet-2", Role: infrav1.SubnetNode, NatGateway: infrav1.NatGateway{Name: "node-natgw"}},
+		},
+	})
+
+	g.Expect(s.IsNatGatewayEnabled()).To(BeTrue())
+	g.Expect(s.NatGatewaySpecs()).To(Equal([]azure.NatGatewaySpec{
+		{Name: "node-natgw", SubnetName: "node-subnet-1", PublicIPName: "pip-node-natgw"},
+		{Name: "node-natgw", SubnetName: "node-subnet-2", PublicIPName: "pip-node-natgw"},
+	}))
+
+	var lbNames []string
+	for _, lb := range s.LBSpecs() {
+		lbNames = append(lbNames, lb.Name)
+	}
+	g.Expect(lbNames).To(Equal([]string{"my-cluster-internal-lb", "my-cluster-public-lb"}))
+
+	var ipNames []string
+	for _, ip := range s.PublicIPSpecs() {
+		ipNames = append(ipNames, ip.Name)
+	}
+	g.Expect(ipNames).To(Equal([]string{"my-cluster-api", "pip-node-natgw"}))
+
+	// NAT gateways of a custom vnet are not managed by the provider
+	s.AzureCluster.Spec.NetworkSpec.Vnet.ID = "my-vnet-id"
+	g.Expect(s.IsNatGatewayEnabled()).To(BeTrue())
+	g.Expect(s.NatGatewaySpecs()).To(BeEmpty())
+}
+
+func TestIPv6DualStack(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{IPv6CidrBlock: "2001:1234:5678:9a00::/56"},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, IPv6CidrBlock: "2001:1234:5678:9abc::/64"},
+			{Name: "node-subnet", Role: infrav1.SubnetNode, IPv6CidrBlock: "2001:1234:5678:9abd::/64"},
+		},
+	})
+	s.AzureCluster.Status.Network.APIServerIPv6 = infrav1.PublicIP{Name: "my-cluster-api-v6"}
+
+	g.Expect(s.IsIPv6Enabled()).To(BeTrue())
+	g.Expect(s.GenerateIPv6FQDN()).To(Equal("my-cluster-api-v6.westus2.cloudapp.azure.com"))
+
+	var ipv6IPs []string
+	for _, ip := range s.PublicIPSpecs() {
+		if ip.IsIPv6 {
+			ipv6IPs = append(ipv6IPs, ip.Name)
+		}
+	}
+	g.Expect(ipv6IPs).To(Equal([]string{"my-cluster-api-v6"}))
+
+	for _, lb := range s.LBSpecs() {
+		if lb.Role == infrav1.APIServerRole {
+			g.Expect(lb.IPv6PublicIPName).To(Equal("my-cluster-api-v6"))
+		} else {
+			g.Expect(lb.IPv6PublicIPName).To(BeEmpty())
+		}
+	}
+}
+
+func TestPublicIPPrefixSpecs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.PublicIPPrefixSpecs()).To(BeEmpty())
+
+	length := int32(30)
+	s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB = &infrav1.NodeOutboundLBSpec{PublicIPPrefixLength: &length}
+	g.Expect(s.PublicIPPrefixSpecs()).To(Equal([]azure.PublicIPPrefixSpec{
+		{Name: "ippre-my-cluster-node-outbound", PrefixLength: 30},
+	}))
+
+	prefixes := make(map[string]string)
+	for _, ip := range s.PublicIPSpecs() {
+		prefixes[ip.Name] = ip.PublicIPPrefixName
+	}
+	g.Expect(prefixes).To(Equal(map[string]string{
+		"pip-my-cluster-node-outbound": "ippre-my-cluster-node-outbound",
+		"my-cluster-api":               "",
+	}))
+
+	// there is no node outbound IP when the nodes use NAT gateways
+	s.AzureCluster.Spec.NetworkSpec.Subnets[1].NatGateway = infrav1.NatGateway{Name: "node-natgw"}
+	g.Expect(s.PublicIPPrefixSpecs()).To(BeEmpty())
+}
+
+func TestPublicIPZones(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	for _, ip := range s.PublicIPSpecs() {
+		g.Expect(ip.Zones).To(BeEmpty())
+	}
+
+	s.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPZones = []string{"1", "2", "3"}
+	s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB = &infrav1.NodeOutboundLBSpec{PublicIPZones: []string{"2"}}
+	zones := make(map[string][]string)
+	for _, ip := range s.PublicIPSpecs() {
+		zones[ip.Name] = ip.Zones
+	}
+	g.Expect(zones).To(Equal(map[string][]string{
+		"pip-my-cluster-node-outbound": {"2"},
+		"my-cluster-api":               {"1", "2", "3"},
+	}))
+}
+
+func TestPrivateDNSSpec(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, InternalLBIPAddress: "10.0.0.100"},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.PrivateDNSSpec()).To(BeNil())
+
+	s.AzureCluster.Spec.NetworkSpec.APIServerLB.Type = infrav1.Internal
+	g.Expect(s.GenerateFQDN()).To(Equal("apiserver.my-cluster.capz.io"))
+	g.Expect(s.PrivateDNSSpec()).To(Equal(&azure.PrivateDNSSpec{
+		ZoneName:          "my-cluster.capz.io",
+		LinkName:          "my-vnet-link",
+		VNetName:          "my-vnet",
+		VNetResourceGroup: "vnet-rg",
+		RecordName:        "apiserver",
+		RecordIP:          "10.0.0.100",
+	}))
+
+	s.AzureCluster.Spec.NetworkSpec.PrivateDNSZoneName = "example.internal"
+	g.Expect(s.GeneratePrivateFQDN()).To(Equal("apiserver.example.internal"))
+	g.Expect(s.GenerateFQDN()).To(Equal("apiserver.example.internal"))
+	g.Expect(s.PrivateDNSSpec().ZoneName).To(Equal("example.internal"))
+}
+
+func TestVnetPeeringSpecs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+		VnetPeerings: []infrav1.VnetPeeringSpec{
+			{
+				RemoteVnetID:          "/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworks/hub-vnet",
+				AllowForwardedTraffic: true,
+				UseRemoteGateways:     true,
+			},
+		},
+		Subnets: infrav1.Subnets{
+			{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+			{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.VnetPeeringSpecs()).To(Equal([]azure.VnetPeeringSpec{
+		{
+			Name:                  "hub-vnet-to-my-vnet",
+			SubscriptionID:        "456",
+			ResourceGroup:         "hub-rg",
+			VnetName:              "hub-vnet",
+			RemoteVnetID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+			AllowForwardedTraffic: true,
+			AllowGatewayTransit:   true,
+		},
+		{
+			Name:                  "my-vnet-to-hub-vnet",
+			SubscriptionID:        "123",
+			ResourceGroup:         "my-rg",
+			VnetName:              "my-vnet",
+			RemoteVnetID:          "/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworks/hub-vnet",
+			AllowForwardedTraffic: true,
+			UseRemoteGateways:     true,
+		},
+	}))
+}
+
+func TestPrivateEndpointSpecs(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+		PrivateEndpoints: []infrav1.PrivateEndpointSpec{
+			{
+				Name:                 "my-vault-pe",
+				SubnetName:           "my-subnet-node",
+				PrivateLinkServiceID: "/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+				GroupIDs:             []string{"vault"},
+				PrivateDNSZoneIDs:    []string{"/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.Network/privateDnsZones/privatelink.vaultcore.azure.net"},
+			},
+		},
+		Subnets: infrav1.Subnets{
+			{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+			{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.PrivateEndpointSpecs()).To(Equal([]azure.PrivateEndpointSpec{
+		{
+			Name:                 "my-vault-pe",
+			SubnetName:           "my-subnet-node",
+			VnetName:             "my-vnet",
+			PrivateLinkServiceID: "/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+			GroupIDs:             []string{"vault"},
+			PrivateDNSZoneIDs:    []string{"/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.Network/privateDnsZones/privatelink.vaultcore.azure.net"},
+		},
+	}))
+	g.Expect(s.ValidatePrivateEndpoints()).To(Succeed())
+}
+
+func TestPrivateLinkServiceSpec(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+		Subnets: infrav1.Subnets{
+			{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+			{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.PrivateLinkServiceSpec()).To(BeNil())
+
+	s.AzureCluster.Spec.NetworkSpec.APIServerLB.PrivateLinkService = &infrav1.PrivateLinkServiceSpec{
+		VisibilitySubscriptions: []string{"*"},
+	}
+	g.Expect(s.PrivateLinkServiceSpec()).To(Equal(&azure.PrivateLinkServiceSpec{
+		Name:                    "my-cluster-apiserver-pls",
+		SubnetName:              "my-subnet-cp",
+		VnetName:                "my-vnet",
+		LoadBalancerName:        "my-cluster-internal-lb",
+		VisibilitySubscriptions: []string{"*"},
+	}))
+
+	s.AzureCluster.Spec.NetworkSpec.APIServerLB.PrivateLinkService.SubnetName = "my-subnet-node"
+	g.Expect(s.PrivateLinkServiceSpec().SubnetName).To(Equal("my-subnet-node"))
+
+	s.SetPrivateLinkServiceAlias("my-cluster-apiserver-pls.1234.westus2.azure.privatelinkservice")
+	g.Expect(s.AzureCluster.Status.Network.PrivateLinkServiceAlias).To(Equal("my-cluster-apiserver-pls.1234.westus2.azure.privatelinkservice"))
+}
+
+func TestValidatePrivateEndpoints(t *testing.T) {
+	vaultID := "/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.KeyVault/vaults/my-vault"
+	tests := []struct {
+		name          string
+		endpoint      infrav1.PrivateEndpointSpec
+		expectedError string
+	}{
+		{
+			name:          "invalid private link service ID",
+			endpoint:      infrav1.PrivateEndpointSpec{Name: "my-vault-pe", SubnetName: "my-subnet-node", PrivateLinkServiceID: "my-vault"},
+			expectedError: "invalid resource ID my-vault of private endpoint my-vault-pe",
+		},
+		{
+			name: "private DNS zone ID of another resource type",
+			endpoint: infrav1.PrivateEndpointSpec{Name: "my-vault-pe", SubnetName: "my-subnet-node", PrivateLinkServiceID: vaultID,
+				PrivateDNSZoneIDs: []string{vaultID}},
+			expectedError: vaultID + " of private endpoint my-vault-pe isn't the resource ID of a private DNS zone",
+		},
+		{
+			name:          "subnet outside of the cluster vnet",
+			endpoint:      infrav1.PrivateEndpointSpec{Name: "my-vault-pe", SubnetName: "other-subnet", PrivateLinkServiceID: vaultID},
+			expectedError: "subnet other-subnet of private endpoint my-vault-pe isn't a subnet of vnet my-vnet",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Vnet:             infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+				PrivateEndpoints: []infrav1.PrivateEndpointSpec{tc.endpoint},
+				Subnets: infrav1.Subnets{
+					{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+					{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+				},
+			})
+			err := s.ValidatePrivateEndpoints()
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(HavePrefix(tc.expectedError))
+		})
+	}
+}
+
+func TestBastionSpec(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg", CidrBlock: "10.0.0.0/8"},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	g.Expect(s.BastionSpec()).To(BeNil())
+	g.Expect(s.ValidateBastion()).To(Succeed())
+
+	s.AzureCluster.Spec.NetworkSpec.Bastion = &infrav1.BastionSpec{}
+	g.Expect(s.BastionSpec()).To(Equal(&azure.BastionSpec{
+		Name:         "my-cluster-bastion",
+		SubnetName:   "AzureBastionSubnet",
+		SubnetCIDR:   "10.255.255.192/26",
+		PublicIPName: "pip-my-cluster-bastion",
+		VNetName:     "my-vnet",
+	}))
+	g.Expect(s.PublicIPSpecs()).To(ContainElement(azure.PublicIPSpec{
+		Name: "pip-my-cluster-bastion",
+		SKU:  infrav1.SKUStandard,
+	}))
+	g.Expect(s.ValidateBastion()).To(Succeed())
+
+	// the default bastion subnet is the last /26 of the vnet
+	s.AzureCluster.Spec.NetworkSpec.Vnet.CidrBlock = "172.16.0.0/16"
+	g.Expect(s.BastionSpec().SubnetCIDR).To(Equal("172.16.255.192/26"))
+	s.AzureCluster.Spec.NetworkSpec.Vnet.CidrBlock = "172.16.0.0/27"
+	g.Expect(s.ValidateBastion()).To(MatchError("subnet AzureBastionSubnet of bastion host my-cluster-bastion needs a CIDR block, the CIDR block 172.16.0.0/27 of the vnet has no room for a /26 subnet"))
+}
+
+func TestValidateBastion(t *testing.T) {
+	testcases := []struct {
+		name          string
+		subnet        infrav1.BastionSubnetSpec
+		expectedError string
+	}{
+		{
+			name:   "large enough subnet",
+			subnet: infrav1.BastionSubnetSpec{Name: "AzureBastionSubnet", CidrBlock: "10.2.0.0/24"},
+		},
+		{
+			name:          "subnet with another name",
+			subnet:        infrav1.BastionSubnetSpec{Name: "bastion-subnet", CidrBlock: "10.2.0.0/26"},
+			expectedError: "subnet bastion-subnet of bastion host my-bastion must be named AzureBastionSubnet",
+		},
+		{
+			name:          "subnet too small",
+			subnet:        infrav1.BastionSubnetSpec{Name: "AzureBastionSubnet", CidrBlock: "10.2.0.0/27"},
+			expectedError: "CIDR block 10.2.0.0/27 of subnet AzureBastionSubnet must have a prefix of at most /26",
+		},
+		{
+			name:          "invalid CIDR block",
+			subnet:        infrav1.BastionSubnetSpec{Name: "AzureBastionSubnet", CidrBlock: "10.2.0.0"},
+			expectedError: "failed to parse CIDR block 10.2.0.0 of subnet AzureBastionSubnet: invalid CIDR address: 10.2.0.0",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+					{Name: "node-subnet", Role: infrav1.SubnetNode},
+				},
+				Bastion: &infrav1.BastionSpec{Name: "my-bastion", Subnet: tc.subnet},
+			})
+			err := s.ValidateBastion()
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestApplicationGatewaySpec(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, CidrBlock: "10.0.0.0/16"},
+			{Name: "node-subnet", Role: infrav1.SubnetNode, CidrBlock: "10.1.0.0/16"},
+		},
+	})
+	g.Expect(s.ApplicationGatewaySpec()).To(BeNil())
+	g.Expect(s.ApplicationGatewayName()).To(BeEmpty())
+	g.Expect(s.ValidateApplicationGateway()).To(Succeed())
+
+	s.AzureCluster.Spec.NetworkSpec.ApplicationGateway = &infrav1.ApplicationGatewaySpec{
+		SSLCertificateSecretID: "https://my-vault.vault.azure.net/secrets/my-certificate",
+		IdentityID:             "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+	}
+	g.Expect(s.ApplicationGatewaySpec()).To(Equal(&azure.ApplicationGatewaySpec{
+		Name:                   "my-cluster-appgw",
+		SubnetName:             "my-cluster-appgw-subnet",
+		SubnetCIDR:             "10.255.254.0/24",
+		VNetName:               "my-vnet",
+		PublicIPName:           "pip-my-cluster-appgw",
+		Capacity:               2,
+		FrontendPort:           443,
+		BackendPort:            6443,
+		HostName:               s.ControlPlaneEndpoint().Host,
+		SSLCertificateSecretID: "https://my-vault.vault.azure.net/secrets/my-certificate",
+		IdentityID:             "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+	}))
+	g.Expect(s.ApplicationGatewayName()).To(Equal("my-cluster-appgw"))
+	g.Expect(s.PublicIPSpecs()).To(ContainElement(azure.PublicIPSpec{
+		Name: "pip-my-cluster-appgw",
+		SKU:  infrav1.SKUStandard,
+	}))
+	g.Expect(s.ValidateApplicationGateway()).To(Succeed())
+
+	s.AzureCluster.Spec.NetworkSpec.ApplicationGateway.Capacity = to.Int32Ptr(10)
+	s.AzureCluster.Spec.NetworkSpec.ApplicationGateway.FrontendPort = 8443
+	g.Expect(s.ApplicationGatewaySpec().Capacity).To(Equal(int32(10)))
+	g.Expect(s.ApplicationGatewaySpec().FrontendPort).To(Equal(int32(8443)))
+}
+
+func TestValidateApplicationGateway(t *testing.T) {
+	testcases := []struct {
+		name          string
+		subnet        infrav1.ApplicationGatewaySubnetSpec
+		capacity      *int32
+		bastion       *infrav1.BastionSpec
+		expectedError string
+	}{
+		{
+			name:   "dedicated subnet",
+			subnet: infrav1.ApplicationGatewaySubnetSpec{Name: "appgw-subnet", CidrBlock: "10.2.0.0/24"},
+		},
+		{
+			name:          "invalid CIDR block",
+			subnet:        infrav1.ApplicationGatewaySubnetSpec{Name: "appgw-subnet", CidrBlock: "10.2.0.0"},
+			expectedError: "failed to parse CIDR block 10.2.0.0 of subnet appgw-subnet: invalid CIDR address: 10.2.0.0",
+		},
+		{
+			name:          "IPv6 CIDR block",
+			subnet:        infrav1.ApplicationGatewaySubnetSpec{Name: "appgw-subnet", CidrBlock: "2001:1234:5678:9abd::/64"},
+			expectedError: "CIDR block 2001:1234:5678:9abd::/64 of subnet appgw-subnet must be an IPv4 CIDR block",
+		},
+		{
+			name:          "subnet too small",
+			subnet:        infrav1.ApplicationGatewaySubnetSpec{Name: "appgw-subnet", CidrBlock: "10.2.0.0/27"},
+			expectedError: "CIDR block 10.2.0.0/27 of subnet appgw-subnet must have a prefix of at most /26",
+		},
+		{
+			name:          "subnet too small for the capacity",
+			subnet:        infrav1.ApplicationGatewaySubnetSpec{Name: "appgw-subnet", CidrBlock: "10.2.0.0/26"},
+			capacity:      to.Int32Ptr(100),
+			expectedError: "CIDR block 10.2.0.0/26 of subnet appgw-subnet has 59 available IP addresses, less than the 100 instances of application gateway my-appgw",
+		},
+		{
+			name:          "control plane subnet",
+			subnet:        infrav1.ApplicationGatewaySubnetSpec{Name: "cp-subnet", CidrBlock: "10.2.0.0/24"},
+			expectedError: "subnet cp-subnet of application gateway my-appgw must be dedicated to it",
+		},
+		{
+			name:          "subnet overlapping the node subnet",
+			subnet:        infrav1.ApplicationGatewaySubnetSpec{Name: "appgw-subnet", CidrBlock: "10.1.255.0/24"},
+			expectedError: "CIDR block 10.1.255.0/24 of subnet appgw-subnet overlaps CIDR block 10.1.0.0/16 of subnet node-subnet",
+		},
+		{
+			name:          "subnet containing the bastion subnet",
+			subnet:        infrav1.ApplicationGatewaySubnetSpec{Name: "appgw-subnet", CidrBlock: "10.255.0.0/16"},
+			bastion:       &infrav1.BastionSpec{},
+			expectedError: "CIDR block 10.255.0.0/16 of subnet appgw-subnet overlaps CIDR block 10.255.255.192/26 of subnet AzureBastionSubnet",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newTestClusterScope(t, infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{CidrBlock: "10.0.0.0/8"},
+				Subnets: infrav1.Subnets{
+					{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, CidrBlock: "10.0.0.0/16"},
+					{Name: "node-subnet", Role: infrav1.SubnetNode, CidrBlock: "10.1.0.0/16"},
+				},
+				Bastion:            tc.bastion,
+				ApplicationGateway: &infrav1.ApplicationGatewaySpec{Name: "my-appgw", Subnet: tc.subnet, Capacity: tc.capacity},
+			})
+			err := s.ValidateApplicationGateway()
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClusterCACertificate(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+	_, err := s.ClusterCACertificate(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(s.client.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-ca"},
+		Data:       map[string][]byte{"tls.crt": []byte("my-ca-cert"), "tls.key": []byte("my-ca-key")},
+	})).To(Succeed())
+	cert, err := s.ClusterCACertificate(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cert).To(Equal([]byte("my-ca-cert")))
+}
+
+func TestControlPlaneZones(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+			{Name: "node-subnet", Role: infrav1.SubnetNode},
+		},
+	})
+
+	zones, err := s.ControlPlaneZones([]string{"1", "2", "3"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zones).To(BeNil())
+
+	s.AzureCluster.Spec.FailureDomains = &infrav1.FailureDomainsSpec{ControlPlaneZones: []string{"3", "1"}}
+	zones, err = s.ControlPlaneZones([]string{"1", "2", "3"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zones).To(Equal([]string{"3", "1"}))
+
+	_, err = s.ControlPlaneZones([]string{"1", "2"})
+	g.Expect(err).To(MatchError("control plane zone 3 is not an availability zone of location westus2"))
+
+	s.SetControlPlaneFailureDomain("3", 1)
+	g.Expect(s.AzureCluster.Status.FailureDomains).To(HaveKeyWithValue("3", clusterv1.FailureDomainSpec{
+		ControlPlane: true,
+		Attributes:   map[string]string{infrav1.ControlPlaneZoneOrderAttribute: "1"},
+	}))
+}
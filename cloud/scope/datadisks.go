@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus"
+)
+
+// ValidateDataDisks checks that the VM size of the machine can have its data disks attached.
+func (m *MachineScope) ValidateDataDisks(ctx context.Context) error {
+	if len(m.AzureMachine.Spec.DataDisks) == 0 {
+		return nil
+	}
+	return validateDataDiskCount(ctx, resourceskus.NewClient(m), m.Location(), m.AzureMachine.Spec.VMSize, len(m.AzureMachine.Spec.DataDisks))
+}
+
+func validateDataDiskCount(ctx context.Context, skusClient resourceskus.Client, location string, vmSize string, count int) error {
+	capabilities, err := vmSizeCapabilities(ctx, skusClient, location, vmSize)
+	if err != nil {
+		return err
+	}
+	maxDataDiskCount, err := strconv.Atoi(capabilities["MaxDataDiskCount"])
+	if err != nil {
+		// the VM size doesn't report its maximum, let Azure check it
+		return nil
+	}
+	if count > maxDataDiskCount {
+		return errors.Errorf("VM size %s can have at most %d data disks, got %d", vmSize, maxDataDiskCount, count)
+	}
+	return nil
+}
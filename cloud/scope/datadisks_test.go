@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus/mock_resourceskus"
+)
+
+func TestValidateDataDiskCount(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("Standard_D2s_v3"),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr("MaxDataDiskCount"), Value: to.StringPtr("4")},
+			},
+		},
+		{
+			Name:         to.StringPtr("Standard_Legacy"),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		vmSize        string
+		count         int
+		expectedError string
+	}{
+		{
+			name:   "data disks within the maximum",
+			vmSize: "Standard_D2s_v3",
+			count:  4,
+		},
+		{
+			name:          "too many data disks",
+			vmSize:        "Standard_D2s_v3",
+			count:         5,
+			expectedError: "VM size Standard_D2s_v3 can have at most 4 data disks, got 5",
+		},
+		{
+			name:   "VM size without maximum",
+			vmSize: "Standard_Legacy",
+			count:  5,
+		},
+		{
+			name:          "unknown VM size",
+			vmSize:        "Standard_Unknown",
+			count:         1,
+			expectedError: "VM size Standard_Unknown isn't available in location westus2",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			skusMock := mock_resourceskus.NewMockClient(mockCtrl)
+			skusMock.EXPECT().List(context.TODO(), "location eq 'westus2'").Return(skus, nil)
+
+			err := validateDataDiskCount(context.TODO(), skusMock, "westus2", tc.vmSize, tc.count)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/diskencryptionsets"
+)
+
+// DiskEncryptionSetID returns the disk encryption set of the disks of the machine VM, falling back to the default
+// of the cluster. An empty value encrypts the disks with platform-managed keys.
+func (m *MachineScope) DiskEncryptionSetID() string {
+	if m.AzureMachine.Spec.DiskEncryptionSetID != "" {
+		return m.AzureMachine.Spec.DiskEncryptionSetID
+	}
+	return m.ClusterDescriber.DiskEncryptionSetID()
+}
+
+// ValidateDiskEncryptionSet checks that the disk encryption set of the machine VM exists, can be read by the
+// identity of the cluster, and is in the location of the VM.
+func (m *MachineScope) ValidateDiskEncryptionSet(ctx context.Context) error {
+	return validateDiskEncryptionSet(ctx, diskencryptionsets.NewClient(m), m.DiskEncryptionSetID(), m.Location())
+}
+
+// DiskEncryptionSetID returns the disk encryption set of the disks of the scale set instances, falling back to the
+// default of the cluster. An empty value encrypts the disks with platform-managed keys.
+func (m *MachinePoolScope) DiskEncryptionSetID() string {
+	if m.AzureMachinePool.Spec.Template.DiskEncryptionSetID != "" {
+		return m.AzureMachinePool.Spec.Template.DiskEncryptionSetID
+	}
+	return m.ClusterDescriber.DiskEncryptionSetID()
+}
+
+// ValidateDiskEncryptionSet checks that the disk encryption set of the scale set instances exists, can be read by
+// the identity of the cluster, and is in the location of the scale set.
+func (m *MachinePoolScope) ValidateDiskEncryptionSet(ctx context.Context) error {
+	return validateDiskEncryptionSet(ctx, diskencryptionsets.NewClient(m), m.DiskEncryptionSetID(), m.Location())
+}
+
+func validateDiskEncryptionSet(ctx context.Context, client diskencryptionsets.Client, id string, location string) error {
+	if id == "" {
+		return nil
+	}
+	des, err := client.Get(ctx, id)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return errors.Errorf("disk encryption set %s does not exist", id)
+		}
+		if azure.ResourceForbidden(err) {
+			return errors.Errorf("access to disk encryption set %s is denied", id)
+		}
+		return errors.Wrapf(err, "failed to get disk encryption set %s", id)
+	}
+	if !strings.EqualFold(to.String(des.Location), location) {
+		return errors.Errorf("disk encryption set %s in location %s can't encrypt the disks of VMs in location %s", id, to.String(des.Location), location)
+	}
+	return nil
+}
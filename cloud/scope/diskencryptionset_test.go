@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/diskencryptionsets/mock_diskencryptionsets"
+)
+
+func TestValidateDiskEncryptionSet(t *testing.T) {
+	desID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des"
+
+	testcases := []struct {
+		name          string
+		id            string
+		expect        func(m *mock_diskencryptionsets.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:   "platform-managed keys",
+			id:     "",
+			expect: func(m *mock_diskencryptionsets.MockClientMockRecorder) {},
+		},
+		{
+			name: "disk encryption set in the location of the VM",
+			id:   desID,
+			expect: func(m *mock_diskencryptionsets.MockClientMockRecorder) {
+				m.Get(gomock.Any(), desID).Return(compute.DiskEncryptionSet{Location: to.StringPtr("westus2")}, nil)
+			},
+		},
+		{
+			name: "disk encryption set in another location",
+			id:   desID,
+			expect: func(m *mock_diskencryptionsets.MockClientMockRecorder) {
+				m.Get(gomock.Any(), desID).Return(compute.DiskEncryptionSet{Location: to.StringPtr("eastus")}, nil)
+			},
+			expectedError: "disk encryption set " + desID + " in location eastus can't encrypt the disks of VMs in location westus2",
+		},
+		{
+			name: "disk encryption set does not exist",
+			id:   desID,
+			expect: func(m *mock_diskencryptionsets.MockClientMockRecorder) {
+				m.Get(gomock.Any(), desID).Return(compute.DiskEncryptionSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "disk encryption set " + desID + " does not exist",
+		},
+		{
+			name: "access to the disk encryption set is denied",
+			id:   desID,
+			expect: func(m *mock_diskencryptionsets.MockClientMockRecorder) {
+				m.Get(gomock.Any(), desID).Return(compute.DiskEncryptionSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+			expectedError: "access to disk encryption set " + desID + " is denied",
+		},
+		{
+			name: "disk encryption set retrieval fails",
+			id:   desID,
+			expect: func(m *mock_diskencryptionsets.MockClientMockRecorder) {
+				m.Get(gomock.Any(), desID).Return(compute.DiskEncryptionSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+			expectedError: "failed to get disk encryption set " + desID + ": #: Internal Server Error: StatusCode=500",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			desMock := mock_diskencryptionsets.NewMockClient(mockCtrl)
+			tc.expect(desMock.EXPECT())
+
+			err := validateDiskEncryptionSet(context.TODO(), desMock, tc.id, "westus2")
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
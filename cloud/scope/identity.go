@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// identityAuthorizers caches the authorizers built from AzureClusterIdentities, so the service principal
+// token is reused and refreshed across reconciles instead of being acquired on every reconcile.
+var identityAuthorizers = struct {
+	sync.Mutex
+	entries map[identityAuthorizerKey]identityAuthorizer
+}{entries: make(map[identityAuthorizerKey]identityAuthorizer)}
+
+// identityAuthorizerKey identifies the AzureClusterIdentity an authorizer authenticates as, and the cloud environment
+// it authenticates against, as clusters of different environments can share an identity.
+type identityAuthorizerKey struct {
+	identity                types.NamespacedName
+	environment             string
+	resourceManagerEndpoint string
+}
+
+// identityAuthorizer is an authorizer built from a given version of an AzureClusterIdentity and of its secret.
+type identityAuthorizer struct {
+	identityVersion string
+	secretVersion   string
+	authorizer      autorest.Authorizer
+}
+
+// setIdentityCredentials sets the credentials from the AzureClusterIdentity referenced by the AzureCluster.
+func (c *AzureClients) setIdentityCredentials(ctx context.Context, kubeClient client.Client, azureCluster *infrav1.AzureCluster) error {
+	subID, err := getSubscriptionID(azureCluster.Spec.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	c.SubscriptionID = subID
+	settings, err := getSettings(azureCluster.Spec.AzureEnvironment)
+	if err != nil {
+		return err
+	}
+	c.ResourceManagerEndpoint = settings.Environment.ResourceManagerEndpoint
+	c.ResourceManagerVMDNSSuffix = GetAzureDNSZoneForEnvironment(settings.Environment.Name)
+	c.EnvironmentName = settings.Environment.Name
+
+	// the identity and its secret are only resolved in the namespace of the AzureCluster, so a cluster can't use the
+	// credentials of another namespace
+	ref := azureCluster.Spec.IdentityRef
+	if ref.Namespace != "" && ref.Namespace != azureCluster.Namespace {
+		return errors.Errorf("AzureClusterIdentity %s/%s must be in namespace %s of the AzureCluster", ref.Namespace, ref.Name, azureCluster.Namespace)
+	}
+	identityKey := types.NamespacedName{Namespace: azureCluster.Namespace, Name: ref.Name}
+	identity := &infrav1.AzureClusterIdentity{}
+	if err := kubeClient.Get(ctx, identityKey, identity); err != nil {
+		return errors.Wrapf(err, "failed to get AzureClusterIdentity %s", identityKey)
+	}
+	if identity.Spec.ClientID == "" || identity.Spec.TenantID == "" {
+		return errors.Errorf("AzureClusterIdentity %s must have a clientID and a tenantID", identityKey)
+	}
+
+	secretRef := identity.Spec.ClientSecret
+	if secretRef.Namespace != "" && secretRef.Namespace != identity.Namespace {
+		return errors.Errorf("client secret %s/%s of AzureClusterIdentity %s must be in namespace %s of the AzureClusterIdentity", secretRef.Namespace, secretRef.Name, identityKey, identity.Namespace)
+	}
+	secretKey := types.NamespacedName{Namespace: identity.Namespace, Name: secretRef.Name}
+	secret := &corev1.Secret{}
+	if err := kubeClient.Get(ctx, secretKey, secret); err != nil {
+		return errors.Wrapf(err, "failed to get client secret %s of AzureClusterIdentity %s", secretKey, identityKey)
+	}
+	clientSecret, ok := secret.Data[infrav1.AzureClusterIdentityClientSecretKey]
+	if !ok || len(clientSecret) == 0 {
+		return errors.Errorf("secret %s of AzureClusterIdentity %s has no %s key", secretKey, identityKey, infrav1.AzureClusterIdentityClientSecretKey)
+	}
+	c.TenantID = identity.Spec.TenantID
+	c.ClientID = identity.Spec.ClientID
+	c.ClientSecret = string(clientSecret)
+
+	key := identityAuthorizerKey{
+		identity:                identityKey,
+		environment:             settings.Environment.Name,
+		resourceManagerEndpoint: settings.Environment.ResourceManagerEndpoint,
+	}
+	identityAuthorizers.Lock()
+	cached, ok := identityAuthorizers.entries[key]
+	identityAuthorizers.Unlock()
+	if ok &&
+		cached.identityVersion == identity.ResourceVersion && cached.secretVersion == secret.ResourceVersion &&
+		isAuthorizerValid(cached.authorizer) {
+		c.Authorizer = cached.authorizer
+		return nil
+	}
+
+	credentials := auth.NewClientCredentialsConfig(identity.Spec.ClientID, string(clientSecret), identity.Spec.TenantID)
+	credentials.AADEndpoint = settings.Environment.ActiveDirectoryEndpoint
+	credentials.Resource = settings.Environment.ResourceManagerEndpoint
+	authorizer, err := credentials.Authorizer()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create authorizer from AzureClusterIdentity %s", identityKey)
+	}
+	identityAuthorizers.Lock()
+	identityAuthorizers.entries[key] = identityAuthorizer{
+		identityVersion: identity.ResourceVersion,
+		secretVersion:   secret.ResourceVersion,
+		authorizer:      authorizer,
+	}
+	identityAuthorizers.Unlock()
+	c.Authorizer = authorizer
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+)
+
+func TestClusterScopeWithIdentity(t *testing.T) {
+	g := NewWithT(t)
+	_ = infrav1.AddToScheme(scheme.Scheme)
+	defer test.SetEnv("AZURE_ENVIRONMENT", "AzurePublicCloud")()
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+	}
+	newAzureCluster := func(identityNamespace string) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			Spec: infrav1.AzureClusterSpec{
+				SubscriptionID: "123",
+				IdentityRef:    &corev1.ObjectReference{Name: "my-identity", Namespace: identityNamespace},
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+						{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+					},
+				},
+			},
+		}
+	}
+	identity := func(clientID string) *infrav1.AzureClusterIdentity {
+		return &infrav1.AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-identity", Namespace: "default"},
+			Spec: infrav1.AzureClusterIdentitySpec{
+				ClientID:     clientID,
+				TenantID:     "my-tenant",
+				ClientSecret: corev1.SecretReference{Name: "my-secret"},
+			},
+		}
+	}
+	secret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: "default"},
+			Data:       data,
+		}
+	}
+
+	otherNamespaceIdentity := identity("my-client")
+	otherNamespaceIdentity.Namespace = "other"
+	otherNamespaceSecret := identity("my-client")
+	otherNamespaceSecret.Spec.ClientSecret.Namespace = "other"
+
+	tests := []struct {
+		name              string
+		identityNamespace string
+		objects           []runtime.Object
+		expectedError     string
+	}{
+		{
+			name:    "valid identity",
+			objects: []runtime.Object{identity("my-client"), secret(map[string][]byte{"clientSecret": []byte("my-secret")})},
+		},
+		{
+			name:          "missing identity",
+			objects:       []runtime.Object{secret(map[string][]byte{"clientSecret": []byte("my-secret")})},
+			expectedError: "failed to create Azure session: failed to get AzureClusterIdentity default/my-identity",
+		},
+		{
+			name:          "identity without a client ID",
+			objects:       []runtime.Object{identity(""), secret(map[string][]byte{"clientSecret": []byte("my-secret")})},
+			expectedError: "failed to create Azure session: AzureClusterIdentity default/my-identity must have a clientID and a tenantID",
+		},
+		{
+			name:          "missing secret",
+			objects:       []runtime.Object{identity("my-client")},
+			expectedError: "failed to create Azure session: failed to get client secret default/my-secret of AzureClusterIdentity default/my-identity",
+		},
+		{
+			name:              "identity in the namespace of the cluster",
+			identityNamespace: "default",
+			objects:           []runtime.Object{identity("my-client"), secret(map[string][]byte{"clientSecret": []byte("my-secret")})},
+		},
+		{
+			name:              "identity in another namespace",
+			identityNamespace: "other",
+			objects:           []runtime.Object{otherNamespaceIdentity, secret(map[string][]byte{"clientSecret": []byte("my-secret")})},
+			expectedError:     "failed to create Azure session: AzureClusterIdentity other/my-identity must be in namespace default of the AzureCluster",
+		},
+		{
+			name:          "secret in another namespace",
+			objects:       []runtime.Object{otherNamespaceSecret, secret(map[string][]byte{"clientSecret": []byte("my-secret")})},
+			expectedError: "failed to create Azure session: client secret other/my-secret of AzureClusterIdentity default/my-identity must be in namespace default of the AzureClusterIdentity",
+		},
+		{
+			name:          "secret without a client secret",
+			objects:       []runtime.Object{identity("my-client"), secret(map[string][]byte{"password": []byte("my-secret")})},
+			expectedError: "failed to create Azure session: secret default/my-secret of AzureClusterIdentity default/my-identity has no clientSecret key",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			azureCluster := newAzureCluster(tc.identityNamespace)
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, append(tc.objects, cluster, azureCluster)...)
+			s, err := NewClusterScope(ClusterScopeParams{
+				Client:       client,
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+			})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(HavePrefix(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s.Authorizer()).NotTo(BeNil())
+			g.Expect(s.SubscriptionID()).To(Equal("123"))
+
+			// the authorizer of an unchanged identity is reused
+			other, err := NewClusterScope(ClusterScopeParams{
+				Client:       client,
+				Cluster:      cluster,
+				AzureCluster: newAzureCluster(tc.identityNamespace),
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(other.Authorizer()).To(BeIdenticalTo(s.Authorizer()))
+
+			// a cluster of another cloud environment sharing the identity gets its own authorizer
+			govCluster := newAzureCluster(tc.identityNamespace)
+			govCluster.Spec.AzureEnvironment = "AzureUSGovernmentCloud"
+			gov, err := NewClusterScope(ClusterScopeParams{
+				Client:       client,
+				Cluster:      cluster,
+				AzureCluster: govCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(gov.Authorizer()).NotTo(BeIdenticalTo(s.Authorizer()))
+		})
+	}
+}
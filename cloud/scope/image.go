@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachineimages"
+)
+
+// latestImageVersion is the image version resolving to the latest version of a shared image gallery image.
+const latestImageVersion = "latest"
+
+// Image returns the image of the machine VM, falling back to the default image of the cluster. A nil image is
+// defaulted to the reference image of the Kubernetes version of the machine.
+func (m *MachineScope) Image() *infrav1.Image {
+	if m.AzureMachine.Spec.Image != nil {
+		return m.AzureMachine.Spec.Image
+	}
+	return m.ClusterDescriber.DefaultImage()
+}
+
+// Image returns the image of the scale set instances, falling back to the default image of the cluster. A nil image
+// is defaulted to the reference image of the Kubernetes version of the machine pool.
+func (m *MachinePoolScope) Image() *infrav1.Image {
+	if m.AzureMachinePool.Spec.Template.Image != nil {
+		return m.AzureMachinePool.Spec.Template.Image
+	}
+	return m.ClusterDescriber.DefaultImage()
+}
+
+// ResolveDefaultImage resolves the default image of the machines of the cluster in the location of the cluster, like
+// the image of a machine, so that an invalid default image fails the reconcile of the cluster.
+func (s *ClusterScope) ResolveDefaultImage(ctx context.Context) (*infrav1.Image, error) {
+	return resolveImage(ctx, s, s.DefaultImage(), s.Location())
+}
+
+// ResolveImage resolves the shared image gallery image of the machine VM to an existing version replicated to the
+// location of the VM. The latest version resolves to the highest version that isn't excluded from latest. The
+// marketplace terms of a marketplace image with a purchase plan are accepted. Other images are returned as is.
+func (m *MachineScope) ResolveImage(ctx context.Context, image *infrav1.Image) (*infrav1.Image, error) {
+	return resolveImage(ctx, m, image, m.Location())
+}
+
+// ResolveImage resolves the shared image gallery image of the scale set instances to an existing version replicated
+// to the location of the scale set. The latest version resolves to the highest version that isn't excluded from
+// latest. The marketplace terms of a marketplace image with a purchase plan are accepted. Other images are returned
+// as is.
+func (m *MachinePoolScope) ResolveImage(ctx context.Context, image *infrav1.Image) (*infrav1.Image, error) {
+	return resolveImage(ctx, m, image, m.Location())
+}
+
+func resolveImage(ctx context.Context, auth azure.Authorizer, image *infrav1.Image, location string) (*infrav1.Image, error) {
+	if image != nil && image.Marketplace != nil {
+		if err := acceptMarketplaceImageTerms(ctx, virtualmachineimages.NewClient(auth), marketplaceagreements.NewClient(auth), image.Marketplace, location); err != nil {
+			return nil, err
+		}
+		return image, nil
+	}
+	return resolveSharedGalleryImage(ctx, galleryimageversions.NewClient(auth), image, location)
+}
+
+func resolveSharedGalleryImage(ctx context.Context, client galleryimageversions.Client, image *infrav1.Image, location string) (*infrav1.Image, error) {
+	if image == nil || image.SharedGallery == nil {
+		return image, nil
+	}
+	sig := image.SharedGallery
+	if !strings.EqualFold(sig.Version, latestImageVersion) {
+		imageVersion, err := client.Get(ctx, sig.SubscriptionID, sig.ResourceGroup, sig.Gallery, sig.Name, sig.Version)
+		if err != nil {
+			if azure.ResourceNotFound(err) {
+				return nil, errors.Errorf("version %s of shared gallery image %s doesn't exist", sig.Version, sharedGalleryImageName(sig))
+			}
+			return nil, errors.Wrapf(err, "failed to get version %s of shared gallery image %s", sig.Version, sharedGalleryImageName(sig))
+		}
+		if !isReplicatedTo(imageVersion, location) {
+			return nil, errors.Errorf("version %s of shared gallery image %s isn't replicated to location %s", sig.Version, sharedGalleryImageName(sig), location)
+		}
+		return image, nil
+	}
+
+	imageVersions, err := client.List(ctx, sig.SubscriptionID, sig.ResourceGroup, sig.Gallery, sig.Name)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return nil, errors.Errorf("shared gallery image %s doesn't exist", sharedGalleryImageName(sig))
+		}
+		return nil, errors.Wrapf(err, "failed to list the versions of shared gallery image %s", sharedGalleryImageName(sig))
+	}
+	var latestName string
+	var latest *version.Version
+	for _, imageVersion := range imageVersions {
+		if imageVersion.GalleryImageVersionProperties == nil || imageVersion.ProvisioningState != compute.ProvisioningState3Succeeded {
+			continue
+		}
+		if profile := imageVersion.PublishingProfile; profile != nil && to.Bool(profile.ExcludeFromLatest) {
+			continue
+		}
+		if !isReplicatedTo(imageVersion, location) {
+			continue
+		}
+		v, err := version.ParseGeneric(to.String(imageVersion.Name))
+		if err != nil {
+			continue
+		}
+		if latest == nil || latest.LessThan(v) {
+			latestName, latest = to.String(imageVersion.Name), v
+		}
+	}
+	if latest == nil {
+		return nil, errors.Errorf("shared gallery image %s has no version replicated to location %s", sharedGalleryImageName(sig), location)
+	}
+
+	resolved := image.DeepCopy()
+	resolved.SharedGallery.Version = latestName
+	return resolved, nil
+}
+
+// isReplicatedTo returns whether a gallery image version is replicated to a location. The target regions of a
+// version are display names, such as West US 2 for westus2.
+func isReplicatedTo(imageVersion compute.GalleryImageVersion, location string) bool {
+	if imageVersion.GalleryImageVersionProperties == nil || imageVersion.PublishingProfile == nil || imageVersion.PublishingProfile.TargetRegions == nil {
+		return false
+	}
+	for _, region := range *imageVersion.PublishingProfile.TargetRegions {
+		if strings.EqualFold(strings.ReplaceAll(to.String(region.Name), " ", ""), location) {
+			return true
+		}
+	}
+	return false
+}
+
+func sharedGalleryImageName(sig *infrav1.AzureSharedGalleryImage) string {
+	return sig.Gallery + "/" + sig.Name
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/galleryimageversions/mock_galleryimageversions"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
+)
+
+func TestResolveSharedGalleryImage(t *testing.T) {
+	sigImage := func(version string) *infrav1.Image {
+		return &infrav1.Image{
+			SharedGallery: &infrav1.AzureSharedGalleryImage{
+				SubscriptionID: "123",
+				ResourceGroup:  "my-rg",
+				Gallery:        "my-gallery",
+				Name:           "my-image",
+				Version:        version,
+			},
+		}
+	}
+	imageVersion := func(name string, state compute.ProvisioningState3, excludeFromLatest bool, regions ...string) compute.GalleryImageVersion {
+		targetRegions := []compute.TargetRegion{}
+		for _, region := range regions {
+			targetRegions = append(targetRegions, compute.TargetRegion{Name: to.StringPtr(region)})
+		}
+		return compute.GalleryImageVersion{
+			Name: to.StringPtr(name),
+			GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+				ProvisioningState: state,
+				PublishingProfile: &compute.GalleryImageVersionPublishingProfile{
+					TargetRegions:     &targetRegions,
+					ExcludeFromLatest: to.BoolPtr(excludeFromLatest),
+				},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		image         *infrav1.Image
+		expect        func(m *mock_galleryimageversions.MockClientMockRecorder)
+		expectedImage *infrav1.Image
+		expectedError string
+	}{
+		{
+			name: "marketplace image",
+			image: &infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot18dot8-ubuntu-1804", Version: "latest"},
+			},
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {},
+			expectedImage: &infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot18dot8-ubuntu-1804", Version: "latest"},
+			},
+		},
+		{
+			name:  "existing version",
+			image: sigImage("1.0.0"),
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "123", "my-rg", "my-gallery", "my-image", "1.0.0").Return(imageVersion("1.0.0", compute.ProvisioningState3Succeeded, false, "West US 2"), nil)
+			},
+			expectedImage: sigImage("1.0.0"),
+		},
+		{
+			name:  "version not replicated to the location",
+			image: sigImage("1.0.0"),
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "123", "my-rg", "my-gallery", "my-image", "1.0.0").Return(imageVersion("1.0.0", compute.ProvisioningState3Succeeded, false, "East US"), nil)
+			},
+			expectedError: "version 1.0.0 of shared gallery image my-gallery/my-image isn't replicated to location westus2",
+		},
+		{
+			name:  "version does not exist",
+			image: sigImage("1.0.0"),
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "123", "my-rg", "my-gallery", "my-image", "1.0.0").Return(compute.GalleryImageVersion{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "version 1.0.0 of shared gallery image my-gallery/my-image doesn't exist",
+		},
+		{
+			name:  "version retrieval fails",
+			image: sigImage("1.0.0"),
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "123", "my-rg", "my-gallery", "my-image", "1.0.0").Return(compute.GalleryImageVersion{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+			expectedError: "failed to get version 1.0.0 of shared gallery image my-gallery/my-image: #: Internal Server Error: StatusCode=500",
+		},
+		{
+			name:  "latest version",
+			image: sigImage("latest"),
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.List(gomock.Any(), "123", "my-rg", "my-gallery", "my-image").Return([]compute.GalleryImageVersion{
+					imageVersion("1.2.0", compute.ProvisioningState3Succeeded, false, "westus2"),
+					imageVersion("1.10.0", compute.ProvisioningState3Succeeded, false, "West US 2"),
+					imageVersion("1.11.0", compute.ProvisioningState3Succeeded, true, "West US 2"),
+					imageVersion("1.12.0", compute.ProvisioningState3Creating, false, "West US 2"),
+					imageVersion("1.13.0", compute.ProvisioningState3Succeeded, false, "East US"),
+				}, nil)
+			},
+			expectedImage: sigImage("1.10.0"),
+		},
+		{
+			name:  "no version replicated to the location",
+			image: sigImage("latest"),
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.List(gomock.Any(), "123", "my-rg", "my-gallery", "my-image").Return([]compute.GalleryImageVersion{
+					imageVersion("1.0.0", compute.ProvisioningState3Succeeded, false, "East US"),
+				}, nil)
+			},
+			expectedError: "shared gallery image my-gallery/my-image has no version replicated to location westus2",
+		},
+		{
+			name:  "image does not exist",
+			image: sigImage("latest"),
+			expect: func(m *mock_galleryimageversions.MockClientMockRecorder) {
+				m.List(gomock.Any(), "123", "my-rg", "my-gallery", "my-image").Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "shared gallery image my-gallery/my-image doesn't exist",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			versionsMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+			tc.expect(versionsMock.EXPECT())
+
+			image, err := resolveSharedGalleryImage(context.TODO(), versionsMock, tc.image, "westus2")
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(image).To(Equal(tc.expectedImage))
+			}
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+			{Role: infrav1.SubnetNode, Name: "node-subnet"},
+		},
+	})
+	machineScope := &MachineScope{
+		ClusterDescriber: clusterScope,
+		AzureMachine:     &infrav1.AzureMachine{},
+	}
+	machinePoolScope := &MachinePoolScope{
+		ClusterDescriber: clusterScope,
+		AzureMachinePool: &infrav1exp.AzureMachinePool{},
+	}
+
+	// the reference image of the Kubernetes version is used when neither the cluster nor the machine set an image
+	g.Expect(machineScope.Image()).To(BeNil())
+	g.Expect(machinePoolScope.Image()).To(BeNil())
+
+	defaultImage := &infrav1.Image{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-images/providers/Microsoft.Compute/images/default")}
+	clusterScope.AzureCluster.Spec.DefaultImage = defaultImage
+	g.Expect(machineScope.Image()).To(Equal(defaultImage))
+	g.Expect(machinePoolScope.Image()).To(Equal(defaultImage))
+
+	machineImage := &infrav1.Image{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-images/providers/Microsoft.Compute/images/machine")}
+	machineScope.AzureMachine.Spec.Image = machineImage
+	machinePoolScope.AzureMachinePool.Spec.Template.Image = machineImage
+	g.Expect(machineScope.Image()).To(Equal(machineImage))
+	g.Expect(machinePoolScope.Image()).To(Equal(machineImage))
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/locations"
+)
+
+// locationsTTL is how long the locations available to a subscription are cached, so the reconciles of the clusters
+// of a subscription don't list them every time, while new locations are eventually picked up.
+const locationsTTL = time.Hour
+
+// availableLocations caches the names of the locations available to each subscription, keyed by the resource manager
+// endpoint and subscription ID.
+var availableLocations = struct {
+	sync.Mutex
+	entries map[string]cachedLocations
+}{entries: make(map[string]cachedLocations)}
+
+// cachedLocations are the names of the locations available to a subscription, listed at a given time.
+type cachedLocations struct {
+	names    []string
+	listedAt time.Time
+}
+
+// ValidateLocation checks that the location of the cluster is one of the locations available to its subscription,
+// so a misspelled location fails the reconcile with the list of valid ones instead of an error of the first
+// resource created in it. The check is skipped when the locations can't be listed, e.g. by a principal whose role is
+// scoped to a resource group.
+func (s *ClusterScope) ValidateLocation(ctx context.Context) error {
+	return validateLocation(ctx, s.Logger, locations.NewClient(s), s.BaseURI()+"/"+s.SubscriptionID(), s.Location())
+}
+
+func validateLocation(ctx context.Context, log logr.Logger, client locations.Client, key, location string) error {
+	names, err := getAvailableLocations(ctx, client, key)
+	if err != nil {
+		log.Info("Skipping the validation of the location", "location", location, "reason", err.Error())
+		return nil
+	}
+	// Azure also accepts the display name of a location, e.g. "West US 2" for westus2
+	normalized := strings.ToLower(strings.ReplaceAll(location, " ", ""))
+	for _, name := range names {
+		if strings.ToLower(name) == normalized {
+			return nil
+		}
+	}
+	return errors.Errorf("location %s is not available in the subscription, the available locations are: %s", location, strings.Join(names, ", "))
+}
+
+// getAvailableLocations returns the sorted names of the locations available to a subscription, listing them when
+// they aren't cached or the cached ones are older than locationsTTL. The locations are listed outside of the lock of
+// the cache, so a slow list doesn't block the reconciles of the clusters of other subscriptions.
+func getAvailableLocations(ctx context.Context, client locations.Client, key string) ([]string, error) {
+	availableLocations.Lock()
+	cached, ok := availableLocations.entries[key]
+	availableLocations.Unlock()
+	if ok && time.Since(cached.listedAt) < locationsTTL {
+		return cached.names, nil
+	}
+
+	list, err := client.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the available locations")
+	}
+	names := make([]string, 0, len(list))
+	for _, location := range list {
+		names = append(names, to.String(location.Name))
+	}
+	sort.Strings(names)
+	availableLocations.Lock()
+	availableLocations.entries[key] = cachedLocations{names: names, listedAt: time.Now()}
+	availableLocations.Unlock()
+	return names, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-06-01/subscriptions"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/locations/mock_locations"
+)
+
+func TestValidateLocation(t *testing.T) {
+	available := []subscriptions.Location{
+		{Name: to.StringPtr("westus2"), DisplayName: to.StringPtr("West US 2")},
+		{Name: to.StringPtr("eastus"), DisplayName: to.StringPtr("East US")},
+	}
+
+	testcases := []struct {
+		name          string
+		location      string
+		expect        func(m *mock_locations.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:     "available location",
+			location: "westus2",
+			expect: func(m *mock_locations.MockClientMockRecorder) {
+				m.List(gomock.Any()).Return(available, nil)
+			},
+		},
+		{
+			name:     "display name of an available location",
+			location: "West US 2",
+			expect: func(m *mock_locations.MockClientMockRecorder) {
+				m.List(gomock.Any()).Return(available, nil)
+			},
+		},
+		{
+			name:     "misspelled location",
+			location: "westus22",
+			expect: func(m *mock_locations.MockClientMockRecorder) {
+				m.List(gomock.Any()).Return(available, nil)
+			},
+			expectedError: "location westus22 is not available in the subscription, the available locations are: eastus, westus2",
+		},
+		{
+			name:     "locations can't be listed, e.g. by a principal scoped to a resource group",
+			location: "westus2",
+			expect: func(m *mock_locations.MockClientMockRecorder) {
+				m.List(gomock.Any()).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_locations.NewMockClient(mockCtrl)
+			tc.expect(clientMock.EXPECT())
+
+			err := validateLocation(context.TODO(), klogr.New(), clientMock, t.Name(), tc.location)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAvailableLocationsAreCached(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_locations.NewMockClient(mockCtrl)
+	clientMock.EXPECT().List(gomock.Any()).Times(2).Return([]subscriptions.Location{{Name: to.StringPtr("westus2")}}, nil)
+
+	g.Expect(validateLocation(context.TODO(), klogr.New(), clientMock, t.Name(), "westus2")).To(Succeed())
+	g.Expect(validateLocation(context.TODO(), klogr.New(), clientMock, t.Name(), "westus2")).To(Succeed())
+
+	// the locations are listed again once the cached ones expire
+	availableLocations.Lock()
+	cached := availableLocations.entries[t.Name()]
+	cached.listedAt = time.Now().Add(-locationsTTL)
+	availableLocations.entries[t.Name()] = cached
+	availableLocations.Unlock()
+	g.Expect(validateLocation(context.TODO(), klogr.New(), clientMock, t.Name(), "westus2")).To(Succeed())
+}
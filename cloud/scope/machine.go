@@ -18,7 +18,8 @@ package scope
 
 import (
 	"context"
-	"encoding/base64"
+	"hash/fnv"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -107,13 +108,24 @@ func (m *MachineScope) NICSpecs() []azure.NICSpec {
 		VNetResourceGroup:     m.Vnet().ResourceGroup,
 		SubnetName:            m.Subnet().Name,
 		VMSize:                m.AzureMachine.Spec.VMSize,
-		AcceleratedNetworking: m.AzureMachine.Spec.AcceleratedNetworking,
+		AcceleratedNetworking: m.AcceleratedNetworking(),
+		IPv6Enabled:           m.Vnet().IsIPv6Enabled(),
 	}
 	if m.Role() == infrav1.ControlPlane {
-		spec.PublicLoadBalancerName = azure.GeneratePublicLBName(m.ClusterName())
+		if !m.IsAPIServerPrivate() {
+			spec.PublicLoadBalancerName = azure.GeneratePublicLBName(m.ClusterName())
+		} else {
+			// control planes behind the internal API server LB get their outbound connections from the control plane outbound LB, when there is one
+			spec.PublicLoadBalancerName = m.ControlPlaneOutboundLBName()
+		}
 		spec.InternalLoadBalancerName = azure.GenerateInternalLBName(m.ClusterName())
+		for _, rule := range m.InternalLBInboundNatRules() {
+			spec.InternalInboundNatRuleNames = append(spec.InternalInboundNatRuleNames, azure.GenerateInternalLBNatRuleName(m.Name(), rule.Name))
+		}
+		spec.ApplicationGatewayName = m.ApplicationGatewayName()
 	} else if m.Role() == infrav1.Node {
-		spec.PublicLoadBalancerName = m.ClusterName()
+		// nodes use NAT gateways or another outbound path instead of the node outbound LB, when there is none
+		spec.PublicLoadBalancerName = m.NodeOutboundLBName()
 	}
 	specs := []azure.NICSpec{spec}
 	if m.AzureMachine.Spec.AllocatePublicIP == true {
@@ -126,27 +138,73 @@ func (m *MachineScope) NICSpecs() []azure.NICSpec {
 			SubnetName:            m.Subnet().Name,
 			PublicIPName:          azure.GenerateNodePublicIPName(m.Name()),
 			VMSize:                m.AzureMachine.Spec.VMSize,
-			AcceleratedNetworking: m.AzureMachine.Spec.AcceleratedNetworking,
+			AcceleratedNetworking: m.AcceleratedNetworking(),
 		})
 	}
 
 	return specs
 }
 
-// DiskSpecs returns the public IP specs.
+// InboundNatSpecs returns the inbound NAT rule specs. Control plane machines behind the public API server
+// load balancer get a rule forwarding a frontend port to their SSH port, and all control plane machines get
+// the inbound NAT rules of the internal API server load balancer.
+func (m *MachineScope) InboundNatSpecs() []azure.InboundNatSpec {
+	if m.Role() != infrav1.ControlPlane {
+		return nil
+	}
+	var specs []azure.InboundNatSpec
+	if !m.IsAPIServerPrivate() {
+		specs = append(specs, azure.InboundNatSpec{
+			Name:             m.Name(),
+			LoadBalancerName: azure.GeneratePublicLBName(m.ClusterName()),
+		})
+	}
+	for _, rule := range m.InternalLBInboundNatRules() {
+		specs = append(specs, azure.InboundNatSpec{
+			Name:                   azure.GenerateInternalLBNatRuleName(m.Name(), rule.Name),
+			LoadBalancerName:       azure.GenerateInternalLBName(m.ClusterName()),
+			FrontendPortRangeStart: rule.FrontendPortRangeStart,
+			FrontendPortRangeEnd:   rule.FrontendPortRangeEnd,
+			BackendPort:            rule.BackendPort,
+		})
+	}
+	return specs
+}
+
+// DiskSpecs returns the specs of the OS and data disks of the machine.
 func (m *MachineScope) DiskSpecs() []azure.DiskSpec {
-	spec := azure.DiskSpec{
-		Name: azure.GenerateOSDiskName(m.Name()),
+	specs := []azure.DiskSpec{
+		{
+			Name: azure.GenerateOSDiskName(m.Name()),
+		},
+	}
+	for _, disk := range m.AzureMachine.Spec.DataDisks {
+		specs = append(specs, azure.DiskSpec{
+			Name: azure.GenerateDataDiskName(m.Name(), disk.NameSuffix),
+		})
 	}
-	return []azure.DiskSpec{spec}
+	return specs
 }
 
-// Subnet returns the machine's subnet based on its role
+// Subnet returns the machine's subnet based on its role.
+// A machine with a subnet name is placed in the subnet of the cluster with this name, when it has the role of the
+// machine. Otherwise, a control plane machine is placed in the control plane subnet of its availability zone.
+// When the cluster has several node subnets, a node is placed in one of them based on a hash of its name,
+// so the selection is stable across reconciles.
 func (m *MachineScope) Subnet() *infrav1.SubnetSpec {
+	if subnet, err := findSubnet(m.Subnets(), m.AzureMachine.Spec.SubnetName, m.Role(), m.Name()); err == nil && subnet != nil {
+		return subnet
+	}
 	if m.IsControlPlane() {
-		return m.ControlPlaneSubnet()
+		return m.ControlPlaneSubnetForZone(m.AvailabilityZone())
+	}
+	nodeSubnets := m.NodeSubnets()
+	if len(nodeSubnets) <= 1 {
+		return m.NodeSubnet()
 	}
-	return m.NodeSubnet()
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(m.Name()))
+	return nodeSubnets[h.Sum32()%uint32(len(nodeSubnets))]
 }
 
 // AvailabilityZone returns the AzureMachine Availability Zone.
@@ -155,7 +213,17 @@ func (m *MachineScope) Subnet() *infrav1.SubnetSpec {
 //   2) AzureMachine.Spec.FailureDomain
 //   3) AzureMachine.Spec.AvailabilityZone.ID (This is DEPRECATED)
 //   4) No AZ
+// A failure domain which is an availability set of the cluster isn't an availability zone.
 func (m *MachineScope) AvailabilityZone() string {
+	zone := m.failureDomain()
+	if zone != "" && m.isAvailabilitySet(zone) {
+		return ""
+	}
+	return zone
+}
+
+// failureDomain returns the failure domain of the machine, in the priority order of AvailabilityZone.
+func (m *MachineScope) failureDomain() string {
 	if m.Machine.Spec.FailureDomain != nil {
 		return *m.Machine.Spec.FailureDomain
 	}
@@ -192,6 +260,38 @@ func (m *MachineScope) Role() string {
 	return infrav1.Node
 }
 
+// SpotVMOptions returns the Spot VM options of the machine, or nil if it isn't a Spot VM.
+func (m *MachineScope) SpotVMOptions() *infrav1.SpotVMOptions {
+	return m.AzureMachine.Spec.SpotVMOptions
+}
+
+// DedicatedHostGroupID returns the resource ID of the dedicated host group of the machine VM, empty when the VM
+// isn't placed on a dedicated host.
+func (m *MachineScope) DedicatedHostGroupID() string {
+	if m.AzureMachine.Spec.DedicatedHost == nil {
+		return ""
+	}
+	return m.AzureMachine.Spec.DedicatedHost.HostGroupID
+}
+
+// DedicatedHostID returns the resource ID of the dedicated host of the machine VM, empty when Azure places the VM
+// on a host of the dedicated host group.
+func (m *MachineScope) DedicatedHostID() string {
+	if m.AzureMachine.Spec.DedicatedHost == nil {
+		return ""
+	}
+	return m.AzureMachine.Spec.DedicatedHost.HostID
+}
+
+// ValidateSpotVMOptions checks that the machine can be a Spot VM. Control plane machines can't, as an eviction
+// would take a control plane member away.
+func (m *MachineScope) ValidateSpotVMOptions() error {
+	if m.SpotVMOptions() != nil && m.IsControlPlane() {
+		return errors.Errorf("control plane machine %s can't be a Spot VM", m.Name())
+	}
+	return nil
+}
+
 // GetVMID returns the AzureMachine instance id by parsing Spec.ProviderID.
 func (m *MachineScope) GetVMID() *string {
 	parsed, err := noderefutil.NewProviderID(m.GetProviderID())
@@ -252,6 +352,22 @@ func (m *MachineScope) SetAnnotation(key, value string) {
 	m.AzureMachine.Annotations[key] = value
 }
 
+// SetOSDisk sets the effective size and storage account type of the OS disk of the VM in the AzureMachine status.
+func (m *MachineScope) SetOSDisk(osDisk infrav1.OSDisk) {
+	if osDisk.DiskSizeGB == 0 && osDisk.ManagedDisk.StorageAccountType == "" {
+		return
+	}
+	m.AzureMachine.Status.OSDisk = &infrav1.OSDiskStatus{
+		DiskSizeGB:         osDisk.DiskSizeGB,
+		StorageAccountType: osDisk.ManagedDisk.StorageAccountType,
+	}
+}
+
+// SetImage sets the effective image of the VM in the AzureMachine status.
+func (m *MachineScope) SetImage(image *infrav1.Image) {
+	m.AzureMachine.Status.Image = image
+}
+
 // SetAddresses sets the Azure address status.
 func (m *MachineScope) SetAddresses(addrs []corev1.NodeAddress) {
 	m.AzureMachine.Status.Addresses = addrs
@@ -267,15 +383,40 @@ func (m *MachineScope) Close(ctx context.Context) error {
 	return m.patchHelper.Patch(ctx, m.AzureMachine)
 }
 
+// UserAssignedIdentities returns the resource IDs of the user-assigned identities of the machine VM,
+// which only has them with the UserAssigned identity type.
+func (m *MachineScope) UserAssignedIdentities() []string {
+	if m.AzureMachine.Spec.Identity != infrav1.VMIdentityUserAssigned {
+		return nil
+	}
+	ids := make([]string, 0, len(m.AzureMachine.Spec.UserAssignedIdentities))
+	for _, identity := range m.AzureMachine.Spec.UserAssignedIdentities {
+		// the provider IDs have the azure:/// prefix, which isn't part of the resource ID
+		ids = append(ids, "/"+strings.TrimLeft(strings.TrimPrefix(identity.ProviderID, "azure://"), "/"))
+	}
+	return ids
+}
+
+// AcceleratedNetworking returns whether accelerated networking is enabled on the machine,
+// falling back to the default of the cluster. A nil value lets the VM size decide.
+func (m *MachineScope) AcceleratedNetworking() *bool {
+	if m.AzureMachine.Spec.AcceleratedNetworking != nil {
+		return m.AzureMachine.Spec.AcceleratedNetworking
+	}
+	return m.ClusterDescriber.AcceleratedNetworking()
+}
+
 // AdditionalTags merges AdditionalTags from the scope's AzureCluster and AzureMachine. If the same key is present in both,
-// the value from AzureMachine takes precedence.
+// the value from AzureMachine takes precedence, unless the key is one of the EnforcedTags of the AzureCluster.
 func (m *MachineScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
 
 	// Start with the cluster-wide tags...
 	tags.Merge(m.ClusterDescriber.AdditionalTags())
-	// ... and merge in the Machine's
+	// ... merge in the Machine's...
 	tags.Merge(m.AzureMachine.Spec.AdditionalTags)
+	// ... and make sure the enforced tags win.
+	tags.Merge(m.ClusterDescriber.EnforcedTags())
 
 	return tags
 }
@@ -295,5 +436,5 @@ func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
-	return base64.StdEncoding.EncodeToString(value), nil
+	return encodeCustomData(m.Logger, value)
 }
@@ -18,7 +18,6 @@ package scope
 
 import (
 	"context"
-	"encoding/base64"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -94,6 +93,40 @@ func (m *MachinePoolScope) Name() string {
 	return m.AzureMachinePool.Name
 }
 
+// Subnet returns the subnet of the scale set instances, machine pools are placed in the node subnet of the cluster.
+func (m *MachinePoolScope) Subnet() *infrav1.SubnetSpec {
+	return m.NodeSubnet()
+}
+
+// AcceleratedNetworking returns whether accelerated networking is enabled on the scale set instances,
+// falling back to the default of the cluster. A nil value lets the VM size decide.
+func (m *MachinePoolScope) AcceleratedNetworking() *bool {
+	if m.AzureMachinePool.Spec.Template.AcceleratedNetworking != nil {
+		return m.AzureMachinePool.Spec.Template.AcceleratedNetworking
+	}
+	return m.ClusterDescriber.AcceleratedNetworking()
+}
+
+// ScaleSetSpec returns the scale set spec.
+func (m *MachinePoolScope) ScaleSetSpec() azure.ScaleSetSpec {
+	var capacity int64
+	if m.MachinePool.Spec.Replicas != nil {
+		capacity = int64(*m.MachinePool.Spec.Replicas)
+	}
+	spec := azure.ScaleSetSpec{
+		Name:                  m.Name(),
+		Size:                  m.AzureMachinePool.Spec.Template.VMSize,
+		Capacity:              capacity,
+		Zones:                 m.MachinePool.Spec.FailureDomains,
+		SubnetID:              m.Subnet().ID,
+		AcceleratedNetworking: m.AcceleratedNetworking(),
+		SpotVMOptions:         m.AzureMachinePool.Spec.Template.SpotVMOptions,
+		// instances use NAT gateways or another outbound path instead of the node outbound LB, when there is none
+		PublicLoadBalancerName: m.NodeOutboundLBName(),
+	}
+	return spec
+}
+
 // GetID returns the AzureMachinePool ID by parsing Spec.ProviderID.
 func (m *MachinePoolScope) GetID() *string {
 	parsed, err := noderefutil.NewProviderID(m.AzureMachinePool.Spec.ProviderID)
@@ -119,11 +152,12 @@ func (m *MachinePoolScope) SetFailureReason(v capierrors.MachineStatusError) {
 }
 
 // AdditionalTags merges AdditionalTags from the scope's AzureCluster and AzureMachinePool. If the same key is present in both,
-// the value from AzureMachinePool takes precedence.
+// the value from AzureMachinePool takes precedence, unless the key is one of the EnforcedTags of the AzureCluster.
 func (m *MachinePoolScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
 	tags.Merge(m.ClusterDescriber.AdditionalTags())
 	tags.Merge(m.AzureMachinePool.Spec.AdditionalTags)
+	tags.Merge(m.ClusterDescriber.EnforcedTags())
 	return tags
 }
 
@@ -145,7 +179,7 @@ func (m *MachinePoolScope) AzureMachineTemplate(ctx context.Context) (*infrav1.A
 	return getAzureMachineTemplate(ctx, m.client, ref.Name, ref.Namespace)
 }
 
-// Close the MachineScope by updating the machine spec, machine status.
+// Close the MachinePoolScope by updating the machine pool spec, machine pool status.
 func (m *MachinePoolScope) Close(ctx context.Context) error {
 	return m.patchHelper.Patch(ctx, m.AzureMachinePool)
 }
@@ -175,5 +209,5 @@ func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error)
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
-	return base64.StdEncoding.EncodeToString(value), nil
+	return encodeCustomData(m.Logger, value)
 }
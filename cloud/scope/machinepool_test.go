@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
+)
+
+func TestScaleSetSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		natGateway infrav1.NatGateway
+		expected   azure.ScaleSetSpec
+	}{
+		{
+			name: "machine pool joins the node outbound LB",
+			expected: azure.ScaleSetSpec{
+				Name:                   "my-pool",
+				Size:                   "Standard_D2s_v3",
+				Capacity:               3,
+				Zones:                  []string{"1", "2"},
+				SubnetID:               "node-subnet-id",
+				PublicLoadBalancerName: "my-cluster",
+				AcceleratedNetworking:  to.BoolPtr(true),
+			},
+		},
+		{
+			name:       "machine pool in a subnet with a NAT gateway",
+			natGateway: infrav1.NatGateway{Name: "my-natgw"},
+			expected: azure.ScaleSetSpec{
+				Name:                  "my-pool",
+				Size:                  "Standard_D2s_v3",
+				Capacity:              3,
+				Zones:                 []string{"1", "2"},
+				SubnetID:              "node-subnet-id",
+				AcceleratedNetworking: to.BoolPtr(true),
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterScope := newTestClusterScope(t, infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+					{Role: infrav1.SubnetNode, Name: "node-subnet", ID: "node-subnet-id", NatGateway: tc.natGateway},
+				},
+			})
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-pool"},
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					Template: infrav1exp.AzureMachineTemplate{
+						VMSize:                "Standard_D2s_v3",
+						AcceleratedNetworking: to.BoolPtr(true),
+					},
+				},
+			}
+			mp := &capiv1exp.MachinePool{
+				Spec: capiv1exp.MachinePoolSpec{
+					Replicas:       to.Int32Ptr(3),
+					FailureDomains: []string{"1", "2"},
+				},
+			}
+			s, err := NewMachinePoolScope(MachinePoolScopeParams{
+				Client:           fake.NewFakeClientWithScheme(scheme.Scheme),
+				MachinePool:      mp,
+				AzureMachinePool: amp,
+				ClusterDescriber: clusterScope,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s.Subnet().Name).To(Equal("node-subnet"))
+			g.Expect(s.ScaleSetSpec()).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestMachinePoolAcceleratedNetworking(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := newTestClusterScope(t, infrav1.NetworkSpec{
+		Subnets: infrav1.Subnets{
+			{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+			{Role: infrav1.SubnetNode, Name: "node-subnet"},
+		},
+	})
+	amp := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pool"},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Template: infrav1exp.AzureMachineTemplate{VMSize: "Standard_D2s_v3"},
+		},
+	}
+	s, err := NewMachinePoolScope(MachinePoolScopeParams{
+		Client:           fake.NewFakeClientWithScheme(scheme.Scheme),
+		MachinePool:      &capiv1exp.MachinePool{},
+		AzureMachinePool: amp,
+		ClusterDescriber: clusterScope,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the VM size decides when neither the cluster nor the machine pool set it
+	g.Expect(s.AcceleratedNetworking()).To(BeNil())
+
+	clusterScope.AzureCluster.Spec.NetworkSpec.AcceleratedNetworking = to.BoolPtr(false)
+	g.Expect(s.AcceleratedNetworking()).To(Equal(to.BoolPtr(false)))
+
+	amp.Spec.Template.AcceleratedNetworking = to.BoolPtr(true)
+	g.Expect(s.AcceleratedNetworking()).To(Equal(to.BoolPtr(true)))
+	g.Expect(s.ScaleSetSpec().AcceleratedNetworking).To(Equal(to.BoolPtr(true)))
+}
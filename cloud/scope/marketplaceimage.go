@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/marketplaceordering/mgmt/2015-06-01/marketplaceordering"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachineimages"
+)
+
+// acceptMarketplaceImageTerms checks that the purchase plan of a marketplace image is set when the image has one, and
+// accepts the marketplace terms of the plan for the subscription when they aren't accepted yet.
+func acceptMarketplaceImageTerms(ctx context.Context, imagesClient virtualmachineimages.Client, agreementsClient marketplaceagreements.Client, image *infrav1.AzureMarketplaceImage, location string) error {
+	plan, err := marketplaceImagePlan(ctx, imagesClient, image, location)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		if image.Plan != nil {
+			return errors.Errorf("marketplace image %s has no purchase plan, but plan %s is set", marketplaceImageName(image), image.Plan.Name)
+		}
+		return nil
+	}
+
+	planPublisher, planProduct, planName := to.String(plan.Publisher), to.String(plan.Product), to.String(plan.Name)
+	if image.Plan == nil {
+		return errors.Errorf("marketplace image %s requires its purchase plan to be set: publisher %s, product %s and name %s", marketplaceImageName(image), planPublisher, planProduct, planName)
+	}
+	if !strings.EqualFold(image.Plan.Publisher, planPublisher) || !strings.EqualFold(image.Plan.Product, planProduct) || !strings.EqualFold(image.Plan.Name, planName) {
+		return errors.Errorf("marketplace image %s has purchase plan %s of product %s of publisher %s, but plan %s of product %s of publisher %s is set",
+			marketplaceImageName(image), planName, planProduct, planPublisher, image.Plan.Name, image.Plan.Product, image.Plan.Publisher)
+	}
+
+	terms, err := agreementsClient.Get(ctx, planPublisher, planProduct, planName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the marketplace terms of plan %s of marketplace image %s", planName, marketplaceImageName(image))
+	}
+	if terms.AgreementProperties != nil && to.Bool(terms.Accepted) {
+		return nil
+	}
+	if terms.AgreementProperties == nil {
+		terms.AgreementProperties = &marketplaceordering.AgreementProperties{}
+	}
+	terms.Accepted = to.BoolPtr(true)
+	if err := agreementsClient.CreateOrUpdate(ctx, planPublisher, planProduct, planName, terms); err != nil {
+		if azure.ResourceForbidden(err) {
+			return errors.Errorf("access to accept the marketplace terms of plan %s of marketplace image %s is denied", planName, marketplaceImageName(image))
+		}
+		return errors.Wrapf(err, "failed to accept the marketplace terms of plan %s of marketplace image %s", planName, marketplaceImageName(image))
+	}
+	return nil
+}
+
+// marketplaceImagePlan returns the purchase plan of a marketplace image in a location, if any. The latest version
+// resolves to the highest version of the image.
+func marketplaceImagePlan(ctx context.Context, client virtualmachineimages.Client, image *infrav1.AzureMarketplaceImage, location string) (*compute.PurchasePlan, error) {
+	imageVersion := image.Version
+	if strings.EqualFold(imageVersion, latestImageVersion) {
+		images, err := client.List(ctx, location, image.Publisher, image.Offer, image.SKU)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the versions of marketplace image %s", marketplaceImageName(image))
+		}
+		var latest *version.Version
+		for _, i := range images {
+			v, err := version.ParseGeneric(to.String(i.Name))
+			if err != nil {
+				continue
+			}
+			if latest == nil || latest.LessThan(v) {
+				imageVersion, latest = to.String(i.Name), v
+			}
+		}
+		if latest == nil {
+			return nil, errors.Errorf("marketplace image %s has no version in location %s", marketplaceImageName(image), location)
+		}
+	}
+
+	vmImage, err := client.Get(ctx, location, image.Publisher, image.Offer, image.SKU, imageVersion)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return nil, errors.Errorf("version %s of marketplace image %s doesn't exist in location %s", imageVersion, marketplaceImageName(image), location)
+		}
+		return nil, errors.Wrapf(err, "failed to get version %s of marketplace image %s", imageVersion, marketplaceImageName(image))
+	}
+	if vmImage.VirtualMachineImageProperties == nil {
+		return nil, nil
+	}
+	return vmImage.Plan, nil
+}
+
+func marketplaceImageName(image *infrav1.AzureMarketplaceImage) string {
+	return fmt.Sprintf("%s:%s:%s:%s", image.Publisher, image.Offer, image.SKU, image.Version)
+}
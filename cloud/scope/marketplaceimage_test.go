@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/marketplaceordering/mgmt/2015-06-01/marketplaceordering"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/marketplaceagreements/mock_marketplaceagreements"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachineimages/mock_virtualmachineimages"
+)
+
+func TestAcceptMarketplaceImageTerms(t *testing.T) {
+	marketplaceImage := func(version string, plan *infrav1.ImagePlan) *infrav1.AzureMarketplaceImage {
+		return &infrav1.AzureMarketplaceImage{
+			Publisher: "my-publisher",
+			Offer:     "my-offer",
+			SKU:       "my-sku",
+			Version:   version,
+			Plan:      plan,
+		}
+	}
+	plan := &infrav1.ImagePlan{Publisher: "my-publisher", Product: "my-offer", Name: "my-plan"}
+	vmImage := func(plan *compute.PurchasePlan) compute.VirtualMachineImage {
+		return compute.VirtualMachineImage{
+			VirtualMachineImageProperties: &compute.VirtualMachineImageProperties{Plan: plan},
+		}
+	}
+	purchasePlan := &compute.PurchasePlan{Publisher: to.StringPtr("my-publisher"), Product: to.StringPtr("my-offer"), Name: to.StringPtr("my-plan")}
+	terms := func(accepted bool) marketplaceordering.AgreementTerms {
+		return marketplaceordering.AgreementTerms{
+			AgreementProperties: &marketplaceordering.AgreementProperties{Accepted: to.BoolPtr(accepted)},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		image         *infrav1.AzureMarketplaceImage
+		expect        func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:  "image without a plan",
+			image: marketplaceImage("1.0.0", nil),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(vmImage(nil), nil)
+			},
+		},
+		{
+			name:  "plan set for an image without a plan",
+			image: marketplaceImage("1.0.0", plan),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(vmImage(nil), nil)
+			},
+			expectedError: "marketplace image my-publisher:my-offer:my-sku:1.0.0 has no purchase plan, but plan my-plan is set",
+		},
+		{
+			name:  "plan not set for an image with a plan",
+			image: marketplaceImage("1.0.0", nil),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(vmImage(purchasePlan), nil)
+			},
+			expectedError: "marketplace image my-publisher:my-offer:my-sku:1.0.0 requires its purchase plan to be set: publisher my-publisher, product my-offer and name my-plan",
+		},
+		{
+			name:  "another plan set",
+			image: marketplaceImage("1.0.0", &infrav1.ImagePlan{Publisher: "my-publisher", Product: "my-offer", Name: "other-plan"}),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(vmImage(purchasePlan), nil)
+			},
+			expectedError: "marketplace image my-publisher:my-offer:my-sku:1.0.0 has purchase plan my-plan of product my-offer of publisher my-publisher, but plan other-plan of product my-offer of publisher my-publisher is set",
+		},
+		{
+			name:  "terms already accepted",
+			image: marketplaceImage("1.0.0", plan),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(vmImage(purchasePlan), nil)
+				a.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(true), nil)
+			},
+		},
+		{
+			name:  "terms accepted for the latest version",
+			image: marketplaceImage("latest", plan),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.List(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku").Return([]compute.VirtualMachineImageResource{
+					{Name: to.StringPtr("1.2.0")},
+					{Name: to.StringPtr("1.10.0")},
+				}, nil)
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.10.0").Return(vmImage(purchasePlan), nil)
+				a.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(false), nil)
+				a.CreateOrUpdate(gomock.Any(), "my-publisher", "my-offer", "my-plan", terms(true)).Return(nil)
+			},
+		},
+		{
+			name:  "access to accept the terms is denied",
+			image: marketplaceImage("1.0.0", plan),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(vmImage(purchasePlan), nil)
+				a.Get(gomock.Any(), "my-publisher", "my-offer", "my-plan").Return(terms(false), nil)
+				a.CreateOrUpdate(gomock.Any(), "my-publisher", "my-offer", "my-plan", terms(true)).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+			expectedError: "access to accept the marketplace terms of plan my-plan of marketplace image my-publisher:my-offer:my-sku:1.0.0 is denied",
+		},
+		{
+			name:  "image version does not exist",
+			image: marketplaceImage("1.0.0", plan),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.Get(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku", "1.0.0").Return(compute.VirtualMachineImage{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "version 1.0.0 of marketplace image my-publisher:my-offer:my-sku:1.0.0 doesn't exist in location westus2",
+		},
+		{
+			name:  "image without versions",
+			image: marketplaceImage("latest", plan),
+			expect: func(i *mock_virtualmachineimages.MockClientMockRecorder, a *mock_marketplaceagreements.MockClientMockRecorder) {
+				i.List(gomock.Any(), "westus2", "my-publisher", "my-offer", "my-sku").Return(nil, nil)
+			},
+			expectedError: "marketplace image my-publisher:my-offer:my-sku:latest has no version in location westus2",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			imagesMock := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			agreementsMock := mock_marketplaceagreements.NewMockClient(mockCtrl)
+			tc.expect(imagesMock.EXPECT(), agreementsMock.EXPECT())
+
+			err := acceptMarketplaceImageTerms(context.TODO(), imagesMock, agreementsMock, tc.image, "westus2")
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/genericresources"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+)
+
+// OrphanedResources returns the resources of the cluster and network resource groups owned by the cluster which aren't
+// represented in the specs of the AzureCluster, of its AzureMachines or of its AzureMachinePools anymore, such as
+// the resources left behind by a failed delete. Only the resources tagged as owned by the cluster and carrying the
+// additional tags of the cluster are reported, so untagged resources are never candidates for cleanup.
+func (s *ClusterScope) OrphanedResources(ctx context.Context) ([]resources.GenericResourceExpanded, error) {
+	names, err := s.ownedResourceNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resourcesClient := genericresources.NewClient(s)
+	orphans, err := findOrphanedResources(ctx, resourcesClient, s.ResourceGroup(), s.ClusterName(), s.AdditionalTags(), names)
+	if err != nil || strings.EqualFold(s.NetworkResourceGroup(), s.ResourceGroup()) {
+		return orphans, err
+	}
+	networkOrphans, err := findOrphanedResources(ctx, resourcesClient, s.NetworkResourceGroup(), s.ClusterName(), s.AdditionalTags(), names)
+	if err != nil {
+		return nil, err
+	}
+	return append(orphans, networkOrphans...), nil
+}
+
+func findOrphanedResources(ctx context.Context, resourcesClient genericresources.Client, resourceGroup, clusterName string, additionalTags infrav1.Tags, names map[string]bool) ([]resources.GenericResourceExpanded, error) {
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", infrav1.ClusterTagKey(clusterName), infrav1.ResourceLifecycleOwned)
+	owned, err := resourcesClient.List(ctx, resourceGroup, filter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the resources owned by cluster %s", clusterName)
+	}
+
+	var orphans []resources.GenericResourceExpanded
+	for _, resource := range owned {
+		// check the tags again rather than trusting the filter, a resource missing any of them is never reported
+		tags := converters.MapToTags(resource.Tags)
+		if !tags.HasOwned(clusterName) || len(additionalTags.Difference(tags)) > 0 {
+			continue
+		}
+		if names[strings.ToLower(to.String(resource.Name))] {
+			continue
+		}
+		orphans = append(orphans, resource)
+	}
+	return orphans, nil
+}
+
+// ownedResourceNames returns the lowercase names of the resources represented in the specs of the AzureCluster and
+// of its AzureMachines and AzureMachinePools.
+func (s *ClusterScope) ownedResourceNames(ctx context.Context) (map[string]bool, error) {
+	names := map[string]bool{}
+	add := func(name string) {
+		if name != "" {
+			names[strings.ToLower(name)] = true
+		}
+	}
+
+	add(s.Vnet().Name)
+	for _, subnet := range s.Subnets() {
+		add(subnet.SecurityGroup.Name)
+		add(subnet.RouteTable.Name)
+	}
+	for _, natGateway := range s.NatGatewaySpecs() {
+		add(natGateway.Name)
+		add(natGateway.PublicIPName)
+	}
+	for _, ip := range s.PublicIPSpecs() {
+		add(ip.Name)
+	}
+	for _, prefix := range s.PublicIPPrefixSpecs() {
+		add(prefix.Name)
+	}
+	for _, lb := range s.LBSpecs() {
+		add(lb.Name)
+	}
+	if dns := s.PrivateDNSSpec(); dns != nil {
+		add(dns.ZoneName)
+		add(dns.ZoneName + "/" + dns.LinkName)
+	}
+	if bastion := s.BastionSpec(); bastion != nil {
+		add(bastion.Name)
+		add(bastion.PublicIPName)
+	}
+	if appGateway := s.ApplicationGatewaySpec(); appGateway != nil {
+		add(appGateway.Name)
+		add(appGateway.PublicIPName)
+	}
+	if ppg := s.ProximityPlacementGroupSpec(); ppg != nil {
+		add(ppg.Name)
+	}
+	for _, as := range s.AvailabilitySetSpecs() {
+		add(as.Name)
+	}
+
+	machines := &infrav1.AzureMachineList{}
+	if err := s.client.List(ctx, machines, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the AzureMachines of cluster %s", s.ClusterName())
+	}
+	for _, machine := range machines.Items {
+		add(machine.Name)
+		add(azure.GenerateNICName(machine.Name))
+		add(azure.GeneratePublicNICName(machine.Name))
+		add(azure.GenerateNodePublicIPName(machine.Name))
+		add(azure.GenerateOSDiskName(machine.Name))
+		for _, disk := range machine.Spec.DataDisks {
+			add(azure.GenerateDataDiskName(machine.Name, disk.NameSuffix))
+		}
+	}
+
+	if feature.Gates.Enabled(capifeature.MachinePool) {
+		machinePools := &infrav1exp.AzureMachinePoolList{}
+		if err := s.client.List(ctx, machinePools, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+			return nil, errors.Wrapf(err, "failed to list the AzureMachinePools of cluster %s", s.ClusterName())
+		}
+		for _, machinePool := range machinePools.Items {
+			add(machinePool.Name)
+		}
+	}
+	return names, nil
+}
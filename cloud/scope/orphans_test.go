@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/genericresources/mock_genericresources"
+)
+
+func TestFindOrphanedResources(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	resource := func(name string, tags map[string]*string) resources.GenericResourceExpanded {
+		return resources.GenericResourceExpanded{Name: to.StringPtr(name), Tags: tags}
+	}
+	owned := map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+		"env": to.StringPtr("prod"),
+	}
+	client := mock_genericresources.NewMockClient(mockCtrl)
+	client.EXPECT().List(context.TODO(), "my-rg", "tagName eq 'sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster' and tagValue eq 'owned'").Return([]resources.GenericResourceExpanded{
+		resource("my-cluster-vnet", owned),
+		resource("My-Cluster-LB", owned),
+		resource("pip-deleted-machine", owned),
+		resource("untagged", nil),
+		resource("shared", map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("shared"), "env": to.StringPtr("prod")}),
+		resource("other-env", map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"), "env": to.StringPtr("dev")}),
+	}, nil)
+
+	orphans, err := findOrphanedResources(context.TODO(), client, "my-rg", "my-cluster", infrav1.Tags{"env": "prod"},
+		map[string]bool{"my-cluster-vnet": true, "my-cluster-lb": true})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(orphans).To(HaveLen(1))
+	g.Expect(to.String(orphans[0].Name)).To(Equal("pip-deleted-machine"))
+
+	client.EXPECT().List(context.TODO(), "my-rg", gomock.Any()).Return(nil, errors.New("forbidden"))
+	_, err = findOrphanedResources(context.TODO(), client, "my-rg", "my-cluster", infrav1.Tags{}, map[string]bool{})
+	g.Expect(err).To(MatchError("failed to list the resources owned by cluster my-cluster: forbidden"))
+}
+
+func TestOwnedResourceNames(t *testing.T) {
+	g := NewWithT(t)
+	_ = infrav1.AddToScheme(scheme.Scheme)
+	s := newTestClusterScope(t, infrav1.NetworkSpec{
+		Vnet: infrav1.VnetSpec{Name: "my-vnet"},
+		Subnets: infrav1.Subnets{
+			{Name: "cp-subnet", Role: infrav1.SubnetControlPlane, SecurityGroup: infrav1.SecurityGroup{Name: "cp-nsg"}},
+			{Name: "node-subnet", Role: infrav1.SubnetNode, SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg"}, RouteTable: infrav1.RouteTable{Name: "node-routetable"}},
+		},
+	})
+	g.Expect(s.client.Create(context.TODO(), &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "my-machine",
+			Labels: map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+		Spec: infrav1.AzureMachineSpec{DataDisks: []infrav1.DataDisk{{NameSuffix: "etcddisk"}}},
+	})).To(Succeed())
+	g.Expect(s.client.Create(context.TODO(), &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "other-machine",
+			Labels: map[string]string{clusterv1.ClusterLabelName: "other-cluster"},
+		},
+	})).To(Succeed())
+
+	names, err := s.ownedResourceNames(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, name := range []string{"my-vnet", "cp-nsg", "node-nsg", "node-routetable", "my-cluster-public-lb", "my-cluster",
+		"my-machine", "my-machine-nic", "my-machine_osdisk", "my-machine_etcddisk", "pip-my-machine"} {
+		g.Expect(names).To(HaveKey(name))
+	}
+	g.Expect(names).NotTo(HaveKey("other-machine"))
+}
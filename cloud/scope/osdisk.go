@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus"
+)
+
+// OSDisk returns the OS disk of the machine VM. The storage account type defaults to Premium_LRS when the VM size
+// supports premium storage, otherwise to Standard_LRS. The VM size of a machine with an ephemeral OS disk must support
+// them and have a cache or resource disk large enough for the OS disk. When the placement of the ephemeral OS disk
+// isn't set, it is the cache disk if the VM size has one, otherwise the resource disk.
+func (m *MachineScope) OSDisk(ctx context.Context) (infrav1.OSDisk, error) {
+	return resolveOSDisk(ctx, resourceskus.NewClient(m), m.Location(), m.AzureMachine.Spec.VMSize, m.AzureMachine.Spec.OSDisk)
+}
+
+// OSDisk returns the OS disk of the scale set instances, with the same defaults and requirements as the OS disk of
+// a machine VM.
+func (m *MachinePoolScope) OSDisk(ctx context.Context) (infrav1.OSDisk, error) {
+	return resolveOSDisk(ctx, resourceskus.NewClient(m), m.Location(), m.AzureMachinePool.Spec.Template.VMSize, m.AzureMachinePool.Spec.Template.OSDisk)
+}
+
+// ValidateOSDiskSize checks that the OS disk of the machine VM isn't smaller than the OS disk of its image. The size
+// of the OS disk of marketplace images isn't known before creating the VM, and isn't checked.
+func (m *MachineScope) ValidateOSDiskSize(ctx context.Context, osDisk infrav1.OSDisk, image *infrav1.Image) error {
+	return validateOSDiskSize(ctx, images.NewClient(m), galleryimageversions.NewClient(m), osDisk, image)
+}
+
+// ValidateOSDiskSize checks that the OS disk of the scale set instances isn't smaller than the OS disk of their
+// image. The size of the OS disk of marketplace images isn't known before creating the scale set, and isn't checked.
+func (m *MachinePoolScope) ValidateOSDiskSize(ctx context.Context, osDisk infrav1.OSDisk, image *infrav1.Image) error {
+	return validateOSDiskSize(ctx, images.NewClient(m), galleryimageversions.NewClient(m), osDisk, image)
+}
+
+func resolveOSDisk(ctx context.Context, skusClient resourceskus.Client, location string, vmSize string, osDisk infrav1.OSDisk) (infrav1.OSDisk, error) {
+	osDisk = *osDisk.DeepCopy()
+	storageAccountType := osDisk.ManagedDisk.StorageAccountType
+	if storageAccountType != "" && storageAccountType != string(compute.PremiumLRS) && osDisk.DiffDiskSettings == nil {
+		return osDisk, nil
+	}
+
+	capabilities, err := vmSizeCapabilities(ctx, skusClient, location, vmSize)
+	if err != nil {
+		return infrav1.OSDisk{}, err
+	}
+	premiumIO := strings.EqualFold(capabilities["PremiumIO"], "True")
+	switch {
+	case storageAccountType == "" && premiumIO:
+		osDisk.ManagedDisk.StorageAccountType = string(compute.PremiumLRS)
+	case storageAccountType == "":
+		osDisk.ManagedDisk.StorageAccountType = string(compute.StandardLRS)
+	case storageAccountType == string(compute.PremiumLRS) && !premiumIO:
+		return infrav1.OSDisk{}, errors.Errorf("VM size %s doesn't support premium storage for an OS disk of storage account type %s", vmSize, storageAccountType)
+	}
+
+	if osDisk.DiffDiskSettings != nil {
+		placement, err := ephemeralOSDiskPlacement(capabilities, vmSize, osDisk)
+		if err != nil {
+			return infrav1.OSDisk{}, err
+		}
+		osDisk.DiffDiskSettings.Placement = placement
+	}
+	return osDisk, nil
+}
+
+// vmSizeCapabilities returns the capabilities of a VM size in a location, by name.
+func vmSizeCapabilities(ctx context.Context, skusClient resourceskus.Client, location string, vmSize string) (map[string]string, error) {
+	skus, err := skusClient.List(ctx, fmt.Sprintf("location eq '%s'", location))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the VM sizes of location %s", location)
+	}
+
+	for _, sku := range skus {
+		if strings.EqualFold(to.String(sku.ResourceType), "virtualMachines") && to.String(sku.Name) == vmSize && sku.Capabilities != nil {
+			capabilities := map[string]string{}
+			for _, c := range *sku.Capabilities {
+				capabilities[to.String(c.Name)] = to.String(c.Value)
+			}
+			return capabilities, nil
+		}
+	}
+	return nil, errors.Errorf("VM size %s isn't available in location %s", vmSize, location)
+}
+
+func ephemeralOSDiskPlacement(capabilities map[string]string, vmSize string, osDisk infrav1.OSDisk) (string, error) {
+	if !strings.EqualFold(capabilities["EphemeralOSDiskSupported"], "True") {
+		return "", errors.Errorf("VM size %s doesn't support ephemeral OS disks", vmSize)
+	}
+
+	cacheDiskBytes, _ := strconv.ParseInt(capabilities["CachedDiskBytes"], 10, 64)
+	resourceDiskMB, _ := strconv.ParseInt(capabilities["MaxResourceVolumeMB"], 10, 64)
+	placement := osDisk.DiffDiskSettings.Placement
+	if placement == "" {
+		placement = string(compute.ResourceDisk)
+		if cacheDiskBytes > 0 {
+			placement = string(compute.CacheDisk)
+		}
+	}
+
+	availableGB := resourceDiskMB / 1024
+	if placement == string(compute.CacheDisk) {
+		if cacheDiskBytes == 0 {
+			return "", errors.Errorf("VM size %s has no cache disk for an ephemeral OS disk", vmSize)
+		}
+		availableGB = cacheDiskBytes / (1024 * 1024 * 1024)
+	}
+	if int64(osDisk.DiskSizeGB) > availableGB {
+		return "", errors.Errorf("ephemeral OS disk of %d GB doesn't fit in the %s of %d GB of VM size %s", osDisk.DiskSizeGB, placement, availableGB, vmSize)
+	}
+	return placement, nil
+}
+
+var (
+	managedImageIDRegexp        = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/images/[^/]+$`)
+	galleryImageVersionIDRegexp = regexp.MustCompile(`(?i)^/subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/Microsoft\.Compute/galleries/([^/]+)/images/([^/]+)/versions/([^/]+)$`)
+)
+
+func validateOSDiskSize(ctx context.Context, imagesClient images.Client, versionsClient galleryimageversions.Client, osDisk infrav1.OSDisk, image *infrav1.Image) error {
+	if osDisk.DiskSizeGB == 0 || image == nil {
+		return nil
+	}
+
+	var imageName string
+	var imageSizeGB int32
+	switch {
+	case image.SharedGallery != nil:
+		sig := image.SharedGallery
+		imageName = sharedGalleryImageName(sig) + "/" + sig.Version
+		sizeGB, err := galleryImageVersionOSDiskSizeGB(ctx, versionsClient, sig.SubscriptionID, sig.ResourceGroup, sig.Gallery, sig.Name, sig.Version)
+		if err != nil {
+			return err
+		}
+		imageSizeGB = sizeGB
+	case image.ID != nil && galleryImageVersionIDRegexp.MatchString(*image.ID):
+		imageName = *image.ID
+		m := galleryImageVersionIDRegexp.FindStringSubmatch(*image.ID)
+		sizeGB, err := galleryImageVersionOSDiskSizeGB(ctx, versionsClient, m[1], m[2], m[3], m[4], m[5])
+		if err != nil {
+			return err
+		}
+		imageSizeGB = sizeGB
+	case image.ID != nil && managedImageIDRegexp.MatchString(*image.ID):
+		imageName = *image.ID
+		managedImage, err := imagesClient.Get(ctx, *image.ID)
+		if err != nil {
+			if azure.ResourceNotFound(err) {
+				return errors.Errorf("managed image %s doesn't exist", *image.ID)
+			}
+			return errors.Wrapf(err, "failed to get managed image %s", *image.ID)
+		}
+		if managedImage.ImageProperties != nil && managedImage.StorageProfile != nil && managedImage.StorageProfile.OsDisk != nil {
+			imageSizeGB = to.Int32(managedImage.StorageProfile.OsDisk.DiskSizeGB)
+		}
+	default:
+		return nil
+	}
+
+	if osDisk.DiskSizeGB < imageSizeGB {
+		return errors.Errorf("OS disk of %d GB is smaller than the OS disk of %d GB of image %s", osDisk.DiskSizeGB, imageSizeGB, imageName)
+	}
+	return nil
+}
+
+func galleryImageVersionOSDiskSizeGB(ctx context.Context, client galleryimageversions.Client, subscriptionID, resourceGroup, gallery, name, imageVersion string) (int32, error) {
+	v, err := client.Get(ctx, subscriptionID, resourceGroup, gallery, name, imageVersion)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get version %s of shared gallery image %s/%s", imageVersion, gallery, name)
+	}
+	if v.GalleryImageVersionProperties == nil || v.StorageProfile == nil || v.StorageProfile.OsDiskImage == nil {
+		return 0, nil
+	}
+	return to.Int32(v.StorageProfile.OsDiskImage.SizeInGB), nil
+}
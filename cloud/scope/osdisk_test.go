@@ -0,0 +1,313 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/galleryimageversions/mock_galleryimageversions"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/images/mock_images"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus/mock_resourceskus"
+)
+
+func TestResolveOSDisk(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("Standard_D4s_v3"),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr("EphemeralOSDiskSupported"), Value: to.StringPtr("True")},
+				{Name: to.StringPtr("PremiumIO"), Value: to.StringPtr("True")},
+				{Name: to.StringPtr("CachedDiskBytes"), Value: to.StringPtr("107374182400")},
+				{Name: to.StringPtr("MaxResourceVolumeMB"), Value: to.StringPtr("32768")},
+			},
+		},
+		{
+			Name:         to.StringPtr("Standard_D4_v4"),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr("EphemeralOSDiskSupported"), Value: to.StringPtr("True")},
+				{Name: to.StringPtr("PremiumIO"), Value: to.StringPtr("False")},
+				{Name: to.StringPtr("MaxResourceVolumeMB"), Value: to.StringPtr("153600")},
+			},
+		},
+		{
+			Name:         to.StringPtr("Standard_B2s"),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr("EphemeralOSDiskSupported"), Value: to.StringPtr("False")},
+			},
+		},
+	}
+	managedOSDisk := func(storageAccountType string) infrav1.OSDisk {
+		return infrav1.OSDisk{
+			OSType: "Linux",
+			ManagedDisk: infrav1.ManagedDisk{
+				StorageAccountType: storageAccountType,
+			},
+		}
+	}
+	osDisk := func(sizeGB int32, placement string) infrav1.OSDisk {
+		return infrav1.OSDisk{
+			OSType:     "Linux",
+			DiskSizeGB: sizeGB,
+			ManagedDisk: infrav1.ManagedDisk{
+				StorageAccountType: "Standard_LRS",
+			},
+			DiffDiskSettings: &infrav1.DiffDiskSettings{
+				Option:    "Local",
+				Placement: placement,
+			},
+		}
+	}
+
+	testcases := []struct {
+		name              string
+		vmSize            string
+		osDisk            infrav1.OSDisk
+		listErr           error
+		skipList          bool
+		expectedPlacement string
+		expectedStorage   string
+		expectedError     string
+	}{
+		{
+			name:            "storage account type defaults to premium storage",
+			vmSize:          "Standard_D4s_v3",
+			osDisk:          managedOSDisk(""),
+			expectedStorage: "Premium_LRS",
+		},
+		{
+			name:            "storage account type defaults to standard storage without premium storage support",
+			vmSize:          "Standard_D4_v4",
+			osDisk:          managedOSDisk(""),
+			expectedStorage: "Standard_LRS",
+		},
+		{
+			name:            "premium storage",
+			vmSize:          "Standard_D4s_v3",
+			osDisk:          managedOSDisk("Premium_LRS"),
+			expectedStorage: "Premium_LRS",
+		},
+		{
+			name:          "premium storage not supported by the VM size",
+			vmSize:        "Standard_D4_v4",
+			osDisk:        managedOSDisk("Premium_LRS"),
+			expectedError: "VM size Standard_D4_v4 doesn't support premium storage for an OS disk of storage account type Premium_LRS",
+		},
+		{
+			name:            "standard SSD storage",
+			vmSize:          "Standard_D4_v4",
+			osDisk:          managedOSDisk("StandardSSD_LRS"),
+			skipList:        true,
+			expectedStorage: "StandardSSD_LRS",
+		},
+		{
+			name:              "placement defaults to the cache disk",
+			vmSize:            "Standard_D4s_v3",
+			osDisk:            osDisk(30, ""),
+			expectedPlacement: "CacheDisk",
+		},
+		{
+			name:              "placement defaults to the resource disk without a cache disk",
+			vmSize:            "Standard_D4_v4",
+			osDisk:            osDisk(128, ""),
+			expectedPlacement: "ResourceDisk",
+		},
+		{
+			name:              "resource disk placement",
+			vmSize:            "Standard_D4s_v3",
+			osDisk:            osDisk(30, "ResourceDisk"),
+			expectedPlacement: "ResourceDisk",
+		},
+		{
+			name:          "OS disk larger than the cache disk",
+			vmSize:        "Standard_D4s_v3",
+			osDisk:        osDisk(128, "CacheDisk"),
+			expectedError: "ephemeral OS disk of 128 GB doesn't fit in the CacheDisk of 100 GB of VM size Standard_D4s_v3",
+		},
+		{
+			name:          "VM size without a cache disk",
+			vmSize:        "Standard_D4_v4",
+			osDisk:        osDisk(30, "CacheDisk"),
+			expectedError: "VM size Standard_D4_v4 has no cache disk for an ephemeral OS disk",
+		},
+		{
+			name:          "VM size without ephemeral OS disk support",
+			vmSize:        "Standard_B2s",
+			osDisk:        osDisk(30, ""),
+			expectedError: "VM size Standard_B2s doesn't support ephemeral OS disks",
+		},
+		{
+			name:          "unknown VM size",
+			vmSize:        "Standard_Unknown",
+			osDisk:        osDisk(30, ""),
+			expectedError: "VM size Standard_Unknown isn't available in location westus2",
+		},
+		{
+			name:          "VM sizes can't be listed",
+			vmSize:        "Standard_D4s_v3",
+			osDisk:        osDisk(30, ""),
+			listErr:       errors.New("#: Internal Server Error: StatusCode=500"),
+			expectedError: "failed to list the VM sizes of location westus2: #: Internal Server Error: StatusCode=500",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			skusMock := mock_resourceskus.NewMockClient(mockCtrl)
+
+			if tc.listErr != nil {
+				skusMock.EXPECT().List(context.TODO(), "location eq 'westus2'").Return(nil, tc.listErr)
+			} else if !tc.skipList {
+				skusMock.EXPECT().List(context.TODO(), "location eq 'westus2'").Return(skus, nil)
+			}
+
+			osDisk, err := resolveOSDisk(context.TODO(), skusMock, "westus2", tc.vmSize, tc.osDisk)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectedStorage != "" {
+				g.Expect(osDisk.ManagedDisk.StorageAccountType).To(Equal(tc.expectedStorage))
+			}
+			if tc.expectedPlacement != "" {
+				g.Expect(osDisk.DiffDiskSettings.Placement).To(Equal(tc.expectedPlacement))
+			}
+		})
+	}
+}
+
+func TestValidateOSDiskSize(t *testing.T) {
+	managedImageID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image"
+	sigImage := &infrav1.Image{
+		SharedGallery: &infrav1.AzureSharedGalleryImage{
+			SubscriptionID: "123",
+			ResourceGroup:  "my-rg",
+			Gallery:        "my-gallery",
+			Name:           "my-image",
+			Version:        "1.0.0",
+		},
+	}
+	imageVersion := compute.GalleryImageVersion{
+		GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+			StorageProfile: &compute.GalleryImageVersionStorageProfile{
+				OsDiskImage: &compute.GalleryOSDiskImage{SizeInGB: to.Int32Ptr(64)},
+			},
+		},
+	}
+	managedImage := compute.Image{
+		ImageProperties: &compute.ImageProperties{
+			StorageProfile: &compute.ImageStorageProfile{
+				OsDisk: &compute.ImageOSDisk{DiskSizeGB: to.Int32Ptr(64)},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		sizeGB        int32
+		image         *infrav1.Image
+		expect        func(i *mock_images.MockClientMockRecorder, v *mock_galleryimageversions.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:   "default size",
+			sizeGB: 0,
+			image:  sigImage,
+			expect: func(i *mock_images.MockClientMockRecorder, v *mock_galleryimageversions.MockClientMockRecorder) {},
+		},
+		{
+			name:   "marketplace image",
+			sizeGB: 30,
+			image: &infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{Publisher: "cncf-upstream", Offer: "capi", SKU: "k8s-1dot18dot8-ubuntu-1804", Version: "latest"},
+			},
+			expect: func(i *mock_images.MockClientMockRecorder, v *mock_galleryimageversions.MockClientMockRecorder) {},
+		},
+		{
+			name:   "shared gallery image",
+			sizeGB: 64,
+			image:  sigImage,
+			expect: func(i *mock_images.MockClientMockRecorder, v *mock_galleryimageversions.MockClientMockRecorder) {
+				v.Get(gomock.Any(), "123", "my-rg", "my-gallery", "my-image", "1.0.0").Return(imageVersion, nil)
+			},
+		},
+		{
+			name:   "OS disk smaller than the shared gallery image",
+			sizeGB: 30,
+			image:  sigImage,
+			expect: func(i *mock_images.MockClientMockRecorder, v *mock_galleryimageversions.MockClientMockRecorder) {
+				v.Get(gomock.Any(), "123", "my-rg", "my-gallery", "my-image", "1.0.0").Return(imageVersion, nil)
+			},
+			expectedError: "OS disk of 30 GB is smaller than the OS disk of 64 GB of image my-gallery/my-image/1.0.0",
+		},
+		{
+			name:   "OS disk smaller than the managed image",
+			sizeGB: 30,
+			image:  &infrav1.Image{ID: to.StringPtr(managedImageID)},
+			expect: func(i *mock_images.MockClientMockRecorder, v *mock_galleryimageversions.MockClientMockRecorder) {
+				i.Get(gomock.Any(), managedImageID).Return(managedImage, nil)
+			},
+			expectedError: "OS disk of 30 GB is smaller than the OS disk of 64 GB of image " + managedImageID,
+		},
+		{
+			name:   "managed image does not exist",
+			sizeGB: 30,
+			image:  &infrav1.Image{ID: to.StringPtr(managedImageID)},
+			expect: func(i *mock_images.MockClientMockRecorder, v *mock_galleryimageversions.MockClientMockRecorder) {
+				i.Get(gomock.Any(), managedImageID).Return(compute.Image{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "managed image " + managedImageID + " doesn't exist",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			imagesMock := mock_images.NewMockClient(mockCtrl)
+			versionsMock := mock_galleryimageversions.NewMockClient(mockCtrl)
+			tc.expect(imagesMock.EXPECT(), versionsMock.EXPECT())
+
+			err := validateOSDiskSize(context.TODO(), imagesMock, versionsMock, infrav1.OSDisk{OSType: "Linux", DiskSizeGB: tc.sizeGB}, tc.image)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
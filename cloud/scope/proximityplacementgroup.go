@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// ValidateProximityPlacementGroup checks that the machine VM can be placed in the proximity placement group of the
+// cluster: it must be in the location of the cluster and, when zonal, in the failure domain of the group.
+func (m *MachineScope) ValidateProximityPlacementGroup(ppg *azure.ProximityPlacementGroupSpec) error {
+	var zones []string
+	if zone := m.AvailabilityZone(); zone != "" {
+		zones = append(zones, zone)
+	}
+	return validateProximityPlacementGroup(ppg, "machine", m.Name(), m.AzureMachine.Spec.Location, m.Location(), zones)
+}
+
+// ValidateProximityPlacementGroup checks that the scale set instances can be placed in the proximity placement group
+// of the cluster: they must be in the location of the cluster and, when zonal, in the failure domain of the group.
+func (m *MachinePoolScope) ValidateProximityPlacementGroup(ppg *azure.ProximityPlacementGroupSpec) error {
+	return validateProximityPlacementGroup(ppg, "machine pool", m.Name(), m.AzureMachinePool.Spec.Location, m.Location(), m.MachinePool.Spec.FailureDomains)
+}
+
+func validateProximityPlacementGroup(ppg *azure.ProximityPlacementGroupSpec, kind, name, location, clusterLocation string, zones []string) error {
+	if ppg == nil {
+		return nil
+	}
+	if location != "" && !strings.EqualFold(location, clusterLocation) {
+		return errors.Errorf("%s %s in location %s can't be in proximity placement group %s of location %s", kind, name, location, ppg.Name, clusterLocation)
+	}
+	for _, zone := range zones {
+		if zone != ppg.FailureDomain {
+			if ppg.FailureDomain == "" {
+				return errors.Errorf("%s %s in availability zone %s can't be in proximity placement group %s without a failure domain", kind, name, zone, ppg.Name)
+			}
+			return errors.Errorf("%s %s in availability zone %s can't be in proximity placement group %s of failure domain %s", kind, name, zone, ppg.Name, ppg.FailureDomain)
+		}
+	}
+	return nil
+}
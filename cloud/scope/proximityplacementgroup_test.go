@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+func TestValidateProximityPlacementGroup(t *testing.T) {
+	ppg := &azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg", FailureDomain: "1"}
+	regionalPPG := &azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"}
+
+	testcases := []struct {
+		name          string
+		ppg           *azure.ProximityPlacementGroupSpec
+		location      string
+		zones         []string
+		expectedError string
+	}{
+		{
+			name:     "no proximity placement group",
+			location: "eastus",
+			zones:    []string{"2"},
+		},
+		{
+			name:     "machine in the failure domain of the proximity placement group",
+			ppg:      ppg,
+			location: "westus2",
+			zones:    []string{"1"},
+		},
+		{
+			name: "machine without location and zone",
+			ppg:  ppg,
+		},
+		{
+			name: "regional machine in a regional proximity placement group",
+			ppg:  regionalPPG,
+		},
+		{
+			name:          "machine in another location",
+			ppg:           ppg,
+			location:      "eastus",
+			expectedError: "machine my-machine in location eastus can't be in proximity placement group my-cluster-ppg of location westus2",
+		},
+		{
+			name:          "machine in another availability zone",
+			ppg:           ppg,
+			zones:         []string{"1", "2"},
+			expectedError: "machine my-machine in availability zone 2 can't be in proximity placement group my-cluster-ppg of failure domain 1",
+		},
+		{
+			name:          "zonal machine in a regional proximity placement group",
+			ppg:           regionalPPG,
+			zones:         []string{"1"},
+			expectedError: "machine my-machine in availability zone 1 can't be in proximity placement group my-cluster-ppg without a failure domain",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateProximityPlacementGroup(tc.ppg, "machine", "my-machine", tc.location, "westus2", tc.zones)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
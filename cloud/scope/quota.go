@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/usages"
+)
+
+// totalRegionalVCPUsUsage is the name of the usage of the vCPUs of all the VM families in a location.
+const totalRegionalVCPUsUsage = "cores"
+
+// CheckVCPUQuota returns an error when the VMs of the given sizes and counts need more vCPUs than the quota
+// available to the subscription in the cluster location, for their VM families or for the whole location.
+// The check is best effort: it is skipped when the quota or the VM sizes can't be retrieved.
+func (s *ClusterScope) CheckVCPUQuota(ctx context.Context, vmSizes map[string]int32) error {
+	if len(vmSizes) == 0 {
+		return nil
+	}
+	return checkVCPUQuota(ctx, s.Logger, resourceskus.NewClient(s), usages.NewClient(s), s.Location(), vmSizes)
+}
+
+func checkVCPUQuota(ctx context.Context, log logr.Logger, skusClient resourceskus.Client, usagesClient usages.Client, location string, vmSizes map[string]int32) error {
+	skus, err := skusClient.List(ctx, fmt.Sprintf("location eq '%s'", location))
+	if err != nil {
+		log.Info("Skipping vCPU quota check, failed to list VM sizes", "location", location, "error", err.Error())
+		return nil
+	}
+
+	required := map[string]int64{}
+	for size, count := range vmSizes {
+		family, vCPUs := "", int64(0)
+		for _, sku := range skus {
+			if !strings.EqualFold(to.String(sku.ResourceType), "virtualMachines") || to.String(sku.Name) != size || sku.Capabilities == nil {
+				continue
+			}
+			family = to.String(sku.Family)
+			for _, c := range *sku.Capabilities {
+				if to.String(c.Name) == "vCPUs" {
+					vCPUs, _ = strconv.ParseInt(to.String(c.Value), 10, 64)
+				}
+			}
+			break
+		}
+		if family == "" || vCPUs == 0 {
+			log.V(2).Info("Skipping vCPU quota check of unknown VM size", "location", location, "vm-size", size)
+			continue
+		}
+		required[family] += vCPUs * int64(count)
+		required[totalRegionalVCPUsUsage] += vCPUs * int64(count)
+	}
+	if len(required) == 0 {
+		return nil
+	}
+
+	quotas, err := usagesClient.List(ctx, location)
+	if err != nil {
+		log.Info("Skipping vCPU quota check, failed to list compute usages", "location", location, "error", err.Error())
+		return nil
+	}
+
+	var exceeded []string
+	for _, quota := range quotas {
+		if quota.Name == nil || quota.Limit == nil || quota.CurrentValue == nil {
+			continue
+		}
+		name := to.String(quota.Name.Value)
+		vCPUs, ok := required[name]
+		if !ok {
+			continue
+		}
+		if available := *quota.Limit - int64(*quota.CurrentValue); vCPUs > available {
+			exceeded = append(exceeded, fmt.Sprintf("%s requires %d vCPUs but only %d are available", name, vCPUs, available))
+		}
+	}
+	if len(exceeded) > 0 {
+		sort.Strings(exceeded)
+		return errors.Errorf("insufficient vCPU quota in location %s: %s", location, strings.Join(exceeded, ", "))
+	}
+	return nil
+}
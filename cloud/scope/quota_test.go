@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus/mock_resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/usages/mock_usages"
+)
+
+func TestCheckVCPUQuota(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("Standard_D2s_v3"),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Family:       to.StringPtr("standardDSv3Family"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr("vCPUs"), Value: to.StringPtr("2")},
+			},
+		},
+		{
+			Name:         to.StringPtr("Standard_F4s_v2"),
+			ResourceType: to.StringPtr("virtualMachines"),
+			Family:       to.StringPtr("standardFSv2Family"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr("vCPUs"), Value: to.StringPtr("4")},
+			},
+		},
+	}
+	usage := func(name string, current int32, limit int64) compute.Usage {
+		return compute.Usage{
+			Name:         &compute.UsageName{Value: to.StringPtr(name)},
+			CurrentValue: to.Int32Ptr(current),
+			Limit:        to.Int64Ptr(limit),
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		vmSizes       map[string]int32
+		expect        func(s *mock_resourceskus.MockClientMockRecorder, u *mock_usages.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:    "quota is available",
+			vmSizes: map[string]int32{"Standard_D2s_v3": 3, "Standard_F4s_v2": 1},
+			expect: func(s *mock_resourceskus.MockClientMockRecorder, u *mock_usages.MockClientMockRecorder) {
+				s.List(context.TODO(), "location eq 'westus2'").Return(skus, nil)
+				u.List(context.TODO(), "westus2").Return([]compute.Usage{
+					usage("cores", 10, 20),
+					usage("standardDSv3Family", 4, 10),
+					usage("standardFSv2Family", 0, 4),
+				}, nil)
+			},
+		},
+		{
+			name:    "family quota is exceeded",
+			vmSizes: map[string]int32{"Standard_D2s_v3": 4},
+			expect: func(s *mock_resourceskus.MockClientMockRecorder, u *mock_usages.MockClientMockRecorder) {
+				s.List(context.TODO(), "location eq 'westus2'").Return(skus, nil)
+				u.List(context.TODO(), "westus2").Return([]compute.Usage{
+					usage("cores", 10, 20),
+					usage("standardDSv3Family", 4, 10),
+				}, nil)
+			},
+			expectedError: "insufficient vCPU quota in location westus2: standardDSv3Family requires 8 vCPUs but only 6 are available",
+		},
+		{
+			name:    "regional and family quotas are exceeded",
+			vmSizes: map[string]int32{"Standard_D2s_v3": 2, "Standard_F4s_v2": 2},
+			expect: func(s *mock_resourceskus.MockClientMockRecorder, u *mock_usages.MockClientMockRecorder) {
+				s.List(context.TODO(), "location eq 'westus2'").Return(skus, nil)
+				u.List(context.TODO(), "westus2").Return([]compute.Usage{
+					usage("cores", 10, 20),
+					usage("standardDSv3Family", 0, 10),
+					usage("standardFSv2Family", 0, 4),
+				}, nil)
+			},
+			expectedError: "insufficient vCPU quota in location westus2: cores requires 12 vCPUs but only 10 are available, standardFSv2Family requires 8 vCPUs but only 4 are available",
+		},
+		{
+			name:    "unknown VM sizes are skipped",
+			vmSizes: map[string]int32{"Standard_Unknown": 100},
+			expect: func(s *mock_resourceskus.MockClientMockRecorder, u *mock_usages.MockClientMockRecorder) {
+				s.List(context.TODO(), "location eq 'westus2'").Return(skus, nil)
+			},
+		},
+		{
+			name:    "check is skipped when the VM sizes can't be listed",
+			vmSizes: map[string]int32{"Standard_D2s_v3": 100},
+			expect: func(s *mock_resourceskus.MockClientMockRecorder, u *mock_usages.MockClientMockRecorder) {
+				s.List(context.TODO(), "location eq 'westus2'").Return(nil, errors.New("#: Internal Server Error: StatusCode=500"))
+			},
+		},
+		{
+			name:    "check is skipped when the usages can't be listed",
+			vmSizes: map[string]int32{"Standard_D2s_v3": 100},
+			expect: func(s *mock_resourceskus.MockClientMockRecorder, u *mock_usages.MockClientMockRecorder) {
+				s.List(context.TODO(), "location eq 'westus2'").Return(skus, nil)
+				u.List(context.TODO(), "westus2").Return(nil, errors.New("#: Forbidden: StatusCode=403"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			skusMock := mock_resourceskus.NewMockClient(mockCtrl)
+			usagesMock := mock_usages.NewMockClient(mockCtrl)
+
+			tc.expect(skusMock.EXPECT(), usagesMock.EXPECT())
+
+			err := checkVCPUQuota(context.TODO(), klogr.New(), skusMock, usagesMock, "westus2", tc.vmSizes)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
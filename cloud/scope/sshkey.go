@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// AdminUsername returns the username of the admin user of the machine VM, which defaults to capi.
+func (m *MachineScope) AdminUsername() string {
+	if m.AzureMachine.Spec.AdminUsername != "" {
+		return m.AzureMachine.Spec.AdminUsername
+	}
+	return azure.DefaultUserName
+}
+
+// AdminUsername returns the username of the admin user of the scale set instances, which defaults to capi.
+func (m *MachinePoolScope) AdminUsername() string {
+	if m.AzureMachinePool.Spec.Template.AdminUsername != "" {
+		return m.AzureMachinePool.Spec.Template.AdminUsername
+	}
+	return azure.DefaultUserName
+}
+
+// SSHPublicKey returns the SSH public key added to the authorized_keys of the admin user of the machine VM, decoded
+// from the base64 encoded key of the AzureMachine.
+func (m *MachineScope) SSHPublicKey() (string, error) {
+	return decodeSSHPublicKey(m.AzureMachine.Spec.SSHPublicKey)
+}
+
+// SSHPublicKey returns the SSH public key added to the authorized_keys of the admin user of the scale set instances,
+// decoded from the base64 encoded key of the AzureMachinePool template.
+func (m *MachinePoolScope) SSHPublicKey() (string, error) {
+	return decodeSSHPublicKey(m.AzureMachinePool.Spec.Template.SSHPublicKey)
+}
+
+// decodeSSHPublicKey decodes a base64 encoded SSH public key, which must be a single authorized_keys entry.
+func decodeSSHPublicKey(encoded string) (string, error) {
+	if encoded == "" {
+		return "", errors.New("the SSH public key is empty")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Wrap(err, "the SSH public key is not properly base64 encoded")
+	}
+	if _, _, _, rest, err := ssh.ParseAuthorizedKey(decoded); err != nil {
+		return "", errors.Wrap(err, "the SSH public key is not a valid authorized_keys entry")
+	} else if len(bytes.TrimSpace(rest)) > 0 {
+		return "", errors.New("the SSH public key must be a single authorized_keys entry")
+	}
+	return string(decoded), nil
+}
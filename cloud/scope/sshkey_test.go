@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestDecodeSSHPublicKey(t *testing.T) {
+	authorizedKey := func() []byte {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ssh.MarshalAuthorizedKey(publicKey)
+	}
+	key := authorizedKey()
+
+	tests := []struct {
+		name          string
+		encoded       string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "valid key",
+			encoded:  base64.StdEncoding.EncodeToString(key),
+			expected: string(key),
+		},
+		{
+			name:     "valid key followed by a blank line",
+			encoded:  base64.StdEncoding.EncodeToString(append(key, '\n')),
+			expected: string(key) + "\n",
+		},
+		{
+			name:          "empty key",
+			encoded:       "",
+			expectedError: "the SSH public key is empty",
+		},
+		{
+			name:          "key which isn't base64 encoded",
+			encoded:       string(key),
+			expectedError: "the SSH public key is not properly base64 encoded: illegal base64 data at input byte 3",
+		},
+		{
+			name:          "key which isn't an authorized_keys entry",
+			encoded:       base64.StdEncoding.EncodeToString([]byte("not a key")),
+			expectedError: "the SSH public key is not a valid authorized_keys entry: ssh: no key found",
+		},
+		{
+			name:          "several keys",
+			encoded:       base64.StdEncoding.EncodeToString(append(authorizedKey(), authorizedKey()...)),
+			expectedError: "the SSH public key must be a single authorized_keys entry",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			decoded, err := decodeSSHPublicKey(tc.encoded)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(decoded).To(Equal(tc.expected))
+		})
+	}
+}
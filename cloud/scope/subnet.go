@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+// ValidateSubnet checks that the subnet the machine is pinned to by its subnet name is a subnet of the cluster with
+// the role of the machine, so a node isn't placed in a control plane subnet or the other way around.
+func (m *MachineScope) ValidateSubnet() error {
+	_, err := findSubnet(m.Subnets(), m.AzureMachine.Spec.SubnetName, m.Role(), m.Name())
+	return err
+}
+
+// findSubnet returns the subnet named name with the given role, or nil when name is empty.
+func findSubnet(subnets infrav1.Subnets, name, role, machineName string) (*infrav1.SubnetSpec, error) {
+	if name == "" {
+		return nil, nil
+	}
+	for _, subnet := range subnets {
+		if subnet.Name != name {
+			continue
+		}
+		if string(subnet.Role) != role {
+			return nil, errors.Errorf("subnet %s of machine %s has role %s, not %s", name, machineName, subnet.Role, role)
+		}
+		return subnet, nil
+	}
+	return nil, errors.Errorf("subnet %s of machine %s isn't a subnet of the cluster", name, machineName)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+func TestFindSubnet(t *testing.T) {
+	subnets := infrav1.Subnets{
+		{Name: "control-plane-subnet", Role: infrav1.SubnetControlPlane},
+		{Name: "node-subnet", Role: infrav1.SubnetNode},
+		{Name: "gpu-node-subnet", Role: infrav1.SubnetNode},
+	}
+
+	testcases := []struct {
+		name           string
+		subnetName     string
+		role           string
+		expectedSubnet *infrav1.SubnetSpec
+		expectedError  string
+	}{
+		{
+			name: "no subnet name",
+			role: infrav1.Node,
+		},
+		{
+			name:           "node in a node subnet",
+			subnetName:     "gpu-node-subnet",
+			role:           infrav1.Node,
+			expectedSubnet: subnets[2],
+		},
+		{
+			name:           "control plane machine in a control plane subnet",
+			subnetName:     "control-plane-subnet",
+			role:           infrav1.ControlPlane,
+			expectedSubnet: subnets[0],
+		},
+		{
+			name:          "node in a control plane subnet",
+			subnetName:    "control-plane-subnet",
+			role:          infrav1.Node,
+			expectedError: "subnet control-plane-subnet of machine my-machine has role control-plane, not node",
+		},
+		{
+			name:          "subnet which isn't a subnet of the cluster",
+			subnetName:    "other-subnet",
+			role:          infrav1.Node,
+			expectedError: "subnet other-subnet of machine my-machine isn't a subnet of the cluster",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			subnet, err := findSubnet(subnets, tc.subnetName, tc.role, "my-machine")
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(subnet).To(Equal(tc.expectedSubnet))
+			}
+		})
+	}
+}
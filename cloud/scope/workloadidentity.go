@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/pkg/errors"
+)
+
+// federatedTokenSecret authenticates a service principal token with a federated service account token
+// as client assertion. The token file is read on every refresh, so rotated tokens are picked up.
+type federatedTokenSecret struct {
+	tokenFile string
+}
+
+// SetAuthenticationValues is a method of the adal.ServicePrincipalSecret interface.
+func (s *federatedTokenSecret) SetAuthenticationValues(_ *adal.ServicePrincipalToken, v *url.Values) error {
+	token, err := ioutil.ReadFile(s.tokenFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read federated token file %s", s.tokenFile)
+	}
+	v.Set("client_assertion", strings.TrimSpace(string(token)))
+	v.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	return nil
+}
+
+// getWorkloadIdentityAuthorizer returns an authorizer exchanging the federated token in AZURE_FEDERATED_TOKEN_FILE
+// for an AAD token of the application in AZURE_CLIENT_ID. The AAD token is refreshed before it expires.
+func getWorkloadIdentityAuthorizer(settings auth.EnvironmentSettings) (autorest.Authorizer, error) {
+	tokenFile := os.Getenv(FederatedTokenFileEnvVar)
+	if tokenFile == "" {
+		return nil, errors.Errorf("environment variable %s is required for workload identity", FederatedTokenFileEnvVar)
+	}
+	clientID := settings.Values[auth.ClientID]
+	tenantID := settings.Values[auth.TenantID]
+	if clientID == "" || tenantID == "" {
+		return nil, errors.New("environment variables AZURE_CLIENT_ID and AZURE_TENANT_ID are required for workload identity")
+	}
+
+	oauthConfig, err := adal.NewOAuthConfig(settings.Environment.ActiveDirectoryEndpoint, tenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create OAuth config for workload identity")
+	}
+	token, err := adal.NewServicePrincipalTokenWithSecret(*oauthConfig, clientID, settings.Values[auth.Resource],
+		&federatedTokenSecret{tokenFile: tokenFile})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create workload identity token")
+	}
+	return autorest.NewBearerAuthorizer(token), nil
+}
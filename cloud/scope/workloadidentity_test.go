@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+)
+
+func TestWorkloadIdentityToken(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := ioutil.TempDir("", "federated-token")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	g.Expect(ioutil.WriteFile(tokenFile, []byte("first-token\n"), 0600)).To(Succeed())
+
+	var assertions []string
+	aad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.ParseForm()).To(Succeed())
+		g.Expect(r.Form.Get("client_id")).To(Equal("my-client"))
+		g.Expect(r.Form.Get("client_assertion_type")).To(Equal("urn:ietf:params:oauth:client-assertion-type:jwt-bearer"))
+		assertions = append(assertions, r.Form.Get("client_assertion"))
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "my-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   "3600",
+			"expires_on":   strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+			"resource":     "https://management.azure.com/",
+		})
+	}))
+	defer aad.Close()
+
+	oauthConfig, err := adal.NewOAuthConfig(aad.URL, "my-tenant")
+	g.Expect(err).NotTo(HaveOccurred())
+	token, err := adal.NewServicePrincipalTokenWithSecret(*oauthConfig, "my-client", "https://management.azure.com/",
+		&federatedTokenSecret{tokenFile: tokenFile})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(token.EnsureFresh()).To(Succeed())
+	g.Expect(token.OAuthToken()).To(Equal("my-access-token"))
+
+	// a rotated federated token is used on the next refresh
+	g.Expect(ioutil.WriteFile(tokenFile, []byte("rotated-token"), 0600)).To(Succeed())
+	g.Expect(token.Refresh()).To(Succeed())
+	g.Expect(assertions).To(Equal([]string{"first-token", "rotated-token"}))
+}
+
+func TestWorkloadIdentityAuthorizer(t *testing.T) {
+	g := NewWithT(t)
+
+	defer test.SetEnv("AZURE_ENVIRONMENT", "AzurePublicCloud")()
+	settings, err := auth.GetSettingsFromEnvironment()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = getWorkloadIdentityAuthorizer(settings)
+	g.Expect(err).To(MatchError("environment variable AZURE_FEDERATED_TOKEN_FILE is required for workload identity"))
+
+	os.Setenv(FederatedTokenFileEnvVar, "/var/run/secrets/azure/tokens/azure-identity-token")
+	defer os.Unsetenv(FederatedTokenFileEnvVar)
+	settings.Values[auth.ClientID] = ""
+	settings.Values[auth.TenantID] = ""
+	_, err = getWorkloadIdentityAuthorizer(settings)
+	g.Expect(err).To(MatchError("environment variables AZURE_CLIENT_ID and AZURE_TENANT_ID are required for workload identity"))
+
+	settings.Values[auth.ClientID] = "my-client"
+	settings.Values[auth.TenantID] = "my-tenant"
+	authorizer, err := getWorkloadIdentityAuthorizer(settings)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(authorizer).NotTo(BeNil())
+}
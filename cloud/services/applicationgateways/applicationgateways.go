@@ -0,0 +1,453 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/async"
+)
+
+// the names of the sub-resources of the application gateway, besides its backend pool.
+const (
+	gatewayIPConfigName     = "gatewayIPConfig"
+	frontendIPConfigName    = "frontendIPConfig"
+	frontendPortName        = "frontendPort"
+	sslCertificateName      = "sslCertificate"
+	trustedRootCertName     = "clusterCA"
+	probeName               = "apiServerProbe"
+	backendHTTPSettingsName = "apiServerHTTPSettings"
+	httpListenerName        = "httpsListener"
+	requestRoutingRuleName  = "apiServerRoutingRule"
+)
+
+// Reconcile gets/creates/updates the application gateway fronting the API server, in its subnet and with its public
+// IP. The control plane machines join its backend pool with their network interfaces.
+func (s *Service) Reconcile(ctx context.Context) error {
+	spec := s.Scope.ApplicationGatewaySpec()
+	if spec == nil {
+		return nil
+	}
+
+	// an application gateway created by an operation done since the last reconcile is not updated again
+	done, err := async.ProcessOngoingOperation(ctx, s.Scope, s.Client, spec.Name, serviceName)
+	if err != nil {
+		return err
+	}
+	if done {
+		s.Scope.V(2).Info("successfully created application gateway", "application gateway", spec.Name)
+		return nil
+	}
+
+	s.Scope.V(2).Info("creating application gateway", "application gateway", spec.Name)
+	subnet, err := s.SubnetsClient.Get(ctx, s.Scope.Vnet().ResourceGroup, spec.VNetName, spec.SubnetName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get subnet %s for application gateway %s", spec.SubnetName, spec.Name)
+	}
+	publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), spec.PublicIPName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get public IP %s for application gateway %s", spec.PublicIPName, spec.Name)
+	}
+	caCert, err := s.Scope.ClusterCACertificate(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the trusted root certificate of application gateway %s", spec.Name)
+	}
+	block, _ := pem.Decode(caCert)
+	if block == nil {
+		return errors.Errorf("failed to decode the trusted root certificate of application gateway %s", spec.Name)
+	}
+
+	tags := converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(spec.Name),
+		Additional:  s.Scope.AdditionalTags(),
+	}))
+	var existing *network.ApplicationGateway
+	appGateway, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), spec.Name)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get application gateway %s in resource group %s", spec.Name, s.Scope.NetworkResourceGroup())
+	case err == nil:
+		if !converters.MapToTags(appGateway.Tags).HasOwned(s.Scope.ClusterName()) {
+			return errors.Errorf("application gateway %s already exists in resource group %s and isn't owned by cluster %s",
+				spec.Name, s.Scope.NetworkResourceGroup(), s.Scope.ClusterName())
+		}
+		existing = &appGateway
+		// keep the tags set out-of-band on the existing application gateway
+		tags, _ = converters.UpdateTags(existing.Tags, converters.MapToTags(tags), s.Scope.LastAppliedTags())
+	}
+
+	desired := s.applicationGateway(*spec, to.String(subnet.ID), to.String(publicIP.ID), base64.StdEncoding.EncodeToString(block.Bytes), tags)
+	if existing != nil && isUpToDate(existing, &desired) {
+		// an update of an application gateway takes minutes, even without changes
+		s.Scope.V(2).Info("application gateway is up to date", "application gateway", spec.Name)
+		return nil
+	}
+
+	future, err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.NetworkResourceGroup(), spec.Name, desired)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create application gateway %s in resource group %s", spec.Name, s.Scope.NetworkResourceGroup())
+	}
+	if err := async.StartOperation(ctx, s.Scope, s.Client, future, infrav1.PutFuture, serviceName, s.Scope.NetworkResourceGroup(), spec.Name); err != nil {
+		return err
+	}
+
+	s.Scope.V(2).Info("successfully created application gateway", "application gateway", spec.Name)
+	return nil
+}
+
+// applicationGateway returns the desired application gateway: it terminates TLS on its frontend port with the
+// certificate read from Key Vault, and forwards the requests over HTTPS to the API server port of the control plane
+// machines, trusting the CA of the cluster.
+func (s *Service) applicationGateway(spec azure.ApplicationGatewaySpec, subnetID, publicIPID, caCertData string, tags map[string]*string) network.ApplicationGateway {
+	idPrefix := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationGateways/%s",
+		s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), spec.Name)
+	subResource := func(kind, name string) *network.SubResource {
+		return &network.SubResource{ID: to.StringPtr(fmt.Sprintf("%s/%s/%s", idPrefix, kind, name))}
+	}
+	backendPoolName := azure.GenerateApplicationGatewayBackendPoolName(spec.Name)
+
+	sku := &network.ApplicationGatewaySku{
+		Name:     network.StandardV2,
+		Tier:     network.ApplicationGatewayTierStandardV2,
+		Capacity: to.Int32Ptr(spec.Capacity),
+	}
+	var firewallPolicy *network.SubResource
+	if spec.FirewallPolicyID != "" {
+		sku.Name = network.WAFV2
+		sku.Tier = network.ApplicationGatewayTierWAFV2
+		firewallPolicy = &network.SubResource{ID: to.StringPtr(spec.FirewallPolicyID)}
+	}
+
+	return network.ApplicationGateway{
+		Name:     to.StringPtr(spec.Name),
+		Location: to.StringPtr(s.Scope.Location()),
+		Tags:     tags,
+		Identity: &network.ManagedServiceIdentity{
+			Type: network.ResourceIdentityTypeUserAssigned,
+			UserAssignedIdentities: map[string]*network.ManagedServiceIdentityUserAssignedIdentitiesValue{
+				spec.IdentityID: {},
+			},
+		},
+		ApplicationGatewayPropertiesFormat: &network.ApplicationGatewayPropertiesFormat{
+			Sku: sku,
+			GatewayIPConfigurations: &[]network.ApplicationGatewayIPConfiguration{
+				{
+					Name: to.StringPtr(gatewayIPConfigName),
+					ApplicationGatewayIPConfigurationPropertiesFormat: &network.ApplicationGatewayIPConfigurationPropertiesFormat{
+						Subnet: &network.SubResource{ID: to.StringPtr(subnetID)},
+					},
+				},
+			},
+			FrontendIPConfigurations: &[]network.ApplicationGatewayFrontendIPConfiguration{
+				{
+					Name: to.StringPtr(frontendIPConfigName),
+					ApplicationGatewayFrontendIPConfigurationPropertiesFormat: &network.ApplicationGatewayFrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &network.SubResource{ID: to.StringPtr(publicIPID)},
+					},
+				},
+			},
+			FrontendPorts: &[]network.ApplicationGatewayFrontendPort{
+				{
+					Name: to.StringPtr(frontendPortName),
+					ApplicationGatewayFrontendPortPropertiesFormat: &network.ApplicationGatewayFrontendPortPropertiesFormat{
+						Port: to.Int32Ptr(spec.FrontendPort),
+					},
+				},
+			},
+			SslCertificates: &[]network.ApplicationGatewaySslCertificate{
+				{
+					Name: to.StringPtr(sslCertificateName),
+					ApplicationGatewaySslCertificatePropertiesFormat: &network.ApplicationGatewaySslCertificatePropertiesFormat{
+						KeyVaultSecretID: to.StringPtr(spec.SSLCertificateSecretID),
+					},
+				},
+			},
+			TrustedRootCertificates: &[]network.ApplicationGatewayTrustedRootCertificate{
+				{
+					Name: to.StringPtr(trustedRootCertName),
+					ApplicationGatewayTrustedRootCertificatePropertiesFormat: &network.ApplicationGatewayTrustedRootCertificatePropertiesFormat{
+						Data: to.StringPtr(caCertData),
+					},
+				},
+			},
+			BackendAddressPools: &[]network.ApplicationGatewayBackendAddressPool{
+				{
+					Name: to.StringPtr(backendPoolName),
+				},
+			},
+			Probes: &[]network.ApplicationGatewayProbe{
+				{
+					Name: to.StringPtr(probeName),
+					ApplicationGatewayProbePropertiesFormat: &network.ApplicationGatewayProbePropertiesFormat{
+						Protocol:                            network.HTTPS,
+						Path:                                to.StringPtr("/readyz"),
+						Interval:                            to.Int32Ptr(15),
+						Timeout:                             to.Int32Ptr(15),
+						UnhealthyThreshold:                  to.Int32Ptr(3),
+						PickHostNameFromBackendHTTPSettings: to.BoolPtr(true),
+					},
+				},
+			},
+			BackendHTTPSettingsCollection: &[]network.ApplicationGatewayBackendHTTPSettings{
+				{
+					Name: to.StringPtr(backendHTTPSettingsName),
+					ApplicationGatewayBackendHTTPSettingsPropertiesFormat: &network.ApplicationGatewayBackendHTTPSettingsPropertiesFormat{
+						Port:                    to.Int32Ptr(spec.BackendPort),
+						Protocol:                network.HTTPS,
+						CookieBasedAffinity:     network.Disabled,
+						RequestTimeout:          to.Int32Ptr(30),
+						Probe:                   subResource("probes", probeName),
+						TrustedRootCertificates: &[]network.SubResource{*subResource("trustedRootCertificates", trustedRootCertName)},
+						HostName:                to.StringPtr(spec.HostName),
+					},
+				},
+			},
+			HTTPListeners: &[]network.ApplicationGatewayHTTPListener{
+				{
+					Name: to.StringPtr(httpListenerName),
+					ApplicationGatewayHTTPListenerPropertiesFormat: &network.ApplicationGatewayHTTPListenerPropertiesFormat{
+						FrontendIPConfiguration: subResource("frontendIPConfigurations", frontendIPConfigName),
+						FrontendPort:            subResource("frontendPorts", frontendPortName),
+						Protocol:                network.HTTPS,
+						SslCertificate:          subResource("sslCertificates", sslCertificateName),
+					},
+				},
+			},
+			RequestRoutingRules: &[]network.ApplicationGatewayRequestRoutingRule{
+				{
+					Name: to.StringPtr(requestRoutingRuleName),
+					ApplicationGatewayRequestRoutingRulePropertiesFormat: &network.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+						RuleType:            network.Basic,
+						BackendAddressPool:  subResource("backendAddressPools", backendPoolName),
+						BackendHTTPSettings: subResource("backendHttpSettingsCollection", backendHTTPSettingsName),
+						HTTPListener:        subResource("httpListeners", httpListenerName),
+					},
+				},
+			},
+			FirewallPolicy: firewallPolicy,
+		},
+	}
+}
+
+// isUpToDate returns whether an existing application gateway, successfully provisioned, has the settings of the
+// desired one.
+func isUpToDate(existing, desired *network.ApplicationGateway) bool {
+	if existing.ApplicationGatewayPropertiesFormat == nil || existing.ProvisioningState != network.Succeeded {
+		return false
+	}
+	have, want := settings(existing), settings(desired)
+	if len(have) != len(want) {
+		return false
+	}
+	for name, value := range want {
+		if v, ok := have[name]; !ok || !strings.EqualFold(v, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// settings returns the settings of an application gateway the provider manages, by name. The unset ones are left out.
+func settings(appGateway *network.ApplicationGateway) map[string]string {
+	s := map[string]string{}
+	set := func(name string, value interface{}) {
+		switch v := value.(type) {
+		case *string:
+			if v != nil {
+				s[name] = *v
+			}
+		case *int32:
+			if v != nil {
+				s[name] = fmt.Sprint(*v)
+			}
+		case *bool:
+			if v != nil {
+				s[name] = fmt.Sprint(*v)
+			}
+		case *network.SubResource:
+			if v != nil && v.ID != nil {
+				s[name] = *v.ID
+			}
+		default:
+			if str := fmt.Sprint(v); str != "" {
+				s[name] = str
+			}
+		}
+	}
+
+	for key, value := range appGateway.Tags {
+		set("tags/"+key, value)
+	}
+	if appGateway.Identity != nil {
+		set("identity/type", appGateway.Identity.Type)
+		for id := range appGateway.Identity.UserAssignedIdentities {
+			s["identity/"+strings.ToLower(id)] = ""
+		}
+	}
+	props := appGateway.ApplicationGatewayPropertiesFormat
+	if props == nil {
+		return s
+	}
+	if props.Sku != nil {
+		set("sku/name", props.Sku.Name)
+		set("sku/tier", props.Sku.Tier)
+		set("sku/capacity", props.Sku.Capacity)
+	}
+	set("firewallPolicy", props.FirewallPolicy)
+	if props.GatewayIPConfigurations != nil {
+		for _, c := range *props.GatewayIPConfigurations {
+			if c.ApplicationGatewayIPConfigurationPropertiesFormat != nil {
+				set("gatewayIPConfigurations/"+to.String(c.Name)+"/subnet", c.Subnet)
+			}
+		}
+	}
+	if props.FrontendIPConfigurations != nil {
+		for _, c := range *props.FrontendIPConfigurations {
+			if c.ApplicationGatewayFrontendIPConfigurationPropertiesFormat != nil {
+				set("frontendIPConfigurations/"+to.String(c.Name)+"/publicIP", c.PublicIPAddress)
+			}
+		}
+	}
+	if props.FrontendPorts != nil {
+		for _, p := range *props.FrontendPorts {
+			if p.ApplicationGatewayFrontendPortPropertiesFormat != nil {
+				set("frontendPorts/"+to.String(p.Name)+"/port", p.Port)
+			}
+		}
+	}
+	if props.SslCertificates != nil {
+		for _, c := range *props.SslCertificates {
+			if c.ApplicationGatewaySslCertificatePropertiesFormat != nil {
+				set("sslCertificates/"+to.String(c.Name)+"/keyVaultSecretID", c.KeyVaultSecretID)
+			}
+		}
+	}
+	if props.TrustedRootCertificates != nil {
+		for _, c := range *props.TrustedRootCertificates {
+			if c.ApplicationGatewayTrustedRootCertificatePropertiesFormat != nil {
+				set("trustedRootCertificates/"+to.String(c.Name)+"/data", c.Data)
+			}
+		}
+	}
+	if props.BackendAddressPools != nil {
+		for _, p := range *props.BackendAddressPools {
+			s["backendAddressPools/"+to.String(p.Name)] = ""
+		}
+	}
+	if props.Probes != nil {
+		for _, p := range *props.Probes {
+			if p.ApplicationGatewayProbePropertiesFormat != nil {
+				prefix := "probes/" + to.String(p.Name)
+				set(prefix+"/protocol", p.Protocol)
+				set(prefix+"/path", p.Path)
+				set(prefix+"/interval", p.Interval)
+				set(prefix+"/timeout", p.Timeout)
+				set(prefix+"/unhealthyThreshold", p.UnhealthyThreshold)
+				set(prefix+"/pickHostName", p.PickHostNameFromBackendHTTPSettings)
+			}
+		}
+	}
+	if props.BackendHTTPSettingsCollection != nil {
+		for _, h := range *props.BackendHTTPSettingsCollection {
+			if h.ApplicationGatewayBackendHTTPSettingsPropertiesFormat != nil {
+				prefix := "backendHTTPSettings/" + to.String(h.Name)
+				set(prefix+"/port", h.Port)
+				set(prefix+"/protocol", h.Protocol)
+				set(prefix+"/cookieBasedAffinity", h.CookieBasedAffinity)
+				set(prefix+"/requestTimeout", h.RequestTimeout)
+				set(prefix+"/probe", h.Probe)
+				set(prefix+"/hostName", h.HostName)
+				if h.TrustedRootCertificates != nil {
+					for i := range *h.TrustedRootCertificates {
+						set(fmt.Sprintf("%s/trustedRootCertificate%d", prefix, i), &(*h.TrustedRootCertificates)[i])
+					}
+				}
+			}
+		}
+	}
+	if props.HTTPListeners != nil {
+		for _, l := range *props.HTTPListeners {
+			if l.ApplicationGatewayHTTPListenerPropertiesFormat != nil {
+				prefix := "httpListeners/" + to.String(l.Name)
+				set(prefix+"/frontendIPConfiguration", l.FrontendIPConfiguration)
+				set(prefix+"/frontendPort", l.FrontendPort)
+				set(prefix+"/protocol", l.Protocol)
+				set(prefix+"/sslCertificate", l.SslCertificate)
+			}
+		}
+	}
+	if props.RequestRoutingRules != nil {
+		for _, r := range *props.RequestRoutingRules {
+			if r.ApplicationGatewayRequestRoutingRulePropertiesFormat != nil {
+				prefix := "requestRoutingRules/" + to.String(r.Name)
+				set(prefix+"/ruleType", r.RuleType)
+				set(prefix+"/backendAddressPool", r.BackendAddressPool)
+				set(prefix+"/backendHTTPSettings", r.BackendHTTPSettings)
+				set(prefix+"/httpListener", r.HTTPListener)
+			}
+		}
+	}
+	return s
+}
+
+// Delete deletes the application gateway of the cluster. It must be deleted before its subnet and public IP.
+func (s *Service) Delete(ctx context.Context) error {
+	spec := s.Scope.ApplicationGatewaySpec()
+	if spec == nil {
+		return nil
+	}
+
+	if !s.Scope.IsNetworkResourceGroupManaged() {
+		// only delete the application gateway owned by the cluster from a pre-existing resource group
+		appGateway, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), spec.Name)
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get application gateway %s in resource group %s", spec.Name, s.Scope.NetworkResourceGroup())
+		}
+		if !converters.MapToTags(appGateway.Tags).HasOwned(s.Scope.ClusterName()) {
+			s.Scope.V(4).Info("Skipping deletion of application gateway not owned by the cluster", "application gateway", spec.Name)
+			return nil
+		}
+	}
+
+	s.Scope.V(2).Info("deleting application gateway", "application gateway", spec.Name)
+	err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), spec.Name)
+	if err != nil && azure.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete application gateway %s in resource group %s", spec.Name, s.Scope.NetworkResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully deleted application gateway", "application gateway", spec.Name)
+	return nil
+}
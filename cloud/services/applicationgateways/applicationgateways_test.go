@@ -0,0 +1,606 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	network201906 "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/applicationgateways/mock_applicationgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
+)
+
+const appGatewayID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-cluster-appgw"
+
+var (
+	fakeApplicationGatewaySpec = &azure.ApplicationGatewaySpec{
+		Name:                   "my-cluster-appgw",
+		SubnetName:             "my-cluster-appgw-subnet",
+		SubnetCIDR:             "10.255.254.0/24",
+		VNetName:               "my-vnet",
+		PublicIPName:           "pip-my-cluster-appgw",
+		Capacity:               2,
+		FrontendPort:           443,
+		BackendPort:            6443,
+		HostName:               "my-cluster.westus2.cloudapp.azure.com",
+		SSLCertificateSecretID: "https://my-vault.vault.azure.net/secrets/my-certificate",
+		IdentityID:             "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+	}
+	fakeCACert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("my-ca-cert")})
+)
+
+// fakeApplicationGateway returns the application gateway created for fakeApplicationGatewaySpec.
+func fakeApplicationGateway() network.ApplicationGateway {
+	return network.ApplicationGateway{
+		Name:     to.StringPtr("my-cluster-appgw"),
+		Location: to.StringPtr("westus2"),
+		Tags: map[string]*string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+			"Name": to.StringPtr("my-cluster-appgw"),
+		},
+		Identity: &network.ManagedServiceIdentity{
+			Type: network.ResourceIdentityTypeUserAssigned,
+			UserAssignedIdentities: map[string]*network.ManagedServiceIdentityUserAssignedIdentitiesValue{
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity": {},
+			},
+		},
+		ApplicationGatewayPropertiesFormat: &network.ApplicationGatewayPropertiesFormat{
+			Sku: &network.ApplicationGatewaySku{
+				Name:     network.StandardV2,
+				Tier:     network.ApplicationGatewayTierStandardV2,
+				Capacity: to.Int32Ptr(2),
+			},
+			GatewayIPConfigurations: &[]network.ApplicationGatewayIPConfiguration{
+				{
+					Name: to.StringPtr("gatewayIPConfig"),
+					ApplicationGatewayIPConfigurationPropertiesFormat: &network.ApplicationGatewayIPConfigurationPropertiesFormat{
+						Subnet: &network.SubResource{ID: to.StringPtr("subnet-id")},
+					},
+				},
+			},
+			FrontendIPConfigurations: &[]network.ApplicationGatewayFrontendIPConfiguration{
+				{
+					Name: to.StringPtr("frontendIPConfig"),
+					ApplicationGatewayFrontendIPConfigurationPropertiesFormat: &network.ApplicationGatewayFrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &network.SubResource{ID: to.StringPtr("pip-id")},
+					},
+				},
+			},
+			FrontendPorts: &[]network.ApplicationGatewayFrontendPort{
+				{
+					Name: to.StringPtr("frontendPort"),
+					ApplicationGatewayFrontendPortPropertiesFormat: &network.ApplicationGatewayFrontendPortPropertiesFormat{
+						Port: to.Int32Ptr(443),
+					},
+				},
+			},
+			SslCertificates: &[]network.ApplicationGatewaySslCertificate{
+				{
+					Name: to.StringPtr("sslCertificate"),
+					ApplicationGatewaySslCertificatePropertiesFormat: &network.ApplicationGatewaySslCertificatePropertiesFormat{
+						KeyVaultSecretID: to.StringPtr("https://my-vault.vault.azure.net/secrets/my-certificate"),
+					},
+				},
+			},
+			TrustedRootCertificates: &[]network.ApplicationGatewayTrustedRootCertificate{
+				{
+					Name: to.StringPtr("clusterCA"),
+					ApplicationGatewayTrustedRootCertificatePropertiesFormat: &network.ApplicationGatewayTrustedRootCertificatePropertiesFormat{
+						Data: to.StringPtr(base64.StdEncoding.EncodeToString([]byte("my-ca-cert"))),
+					},
+				},
+			},
+			BackendAddressPools: &[]network.ApplicationGatewayBackendAddressPool{
+				{
+					Name: to.StringPtr("my-cluster-appgw-backendPool"),
+				},
+			},
+			Probes: &[]network.ApplicationGatewayProbe{
+				{
+					Name: to.StringPtr("apiServerProbe"),
+					ApplicationGatewayProbePropertiesFormat: &network.ApplicationGatewayProbePropertiesFormat{
+						Protocol:                            network.HTTPS,
+						Path:                                to.StringPtr("/readyz"),
+						Interval:                            to.Int32Ptr(15),
+						Timeout:                             to.Int32Ptr(15),
+						UnhealthyThreshold:                  to.Int32Ptr(3),
+						PickHostNameFromBackendHTTPSettings: to.BoolPtr(true),
+					},
+				},
+			},
+			BackendHTTPSettingsCollection: &[]network.ApplicationGatewayBackendHTTPSettings{
+				{
+					Name: to.StringPtr("apiServerHTTPSettings"),
+					ApplicationGatewayBackendHTTPSettingsPropertiesFormat: &network.ApplicationGatewayBackendHTTPSettingsPropertiesFormat{
+						Port:                    to.Int32Ptr(6443),
+						Protocol:                network.HTTPS,
+						CookieBasedAffinity:     network.Disabled,
+						RequestTimeout:          to.Int32Ptr(30),
+						Probe:                   &network.SubResource{ID: to.StringPtr(appGatewayID + "/probes/apiServerProbe")},
+						TrustedRootCertificates: &[]network.SubResource{{ID: to.StringPtr(appGatewayID + "/trustedRootCertificates/clusterCA")}},
+						HostName:                to.StringPtr("my-cluster.westus2.cloudapp.azure.com"),
+					},
+				},
+			},
+			HTTPListeners: &[]network.ApplicationGatewayHTTPListener{
+				{
+					Name: to.StringPtr("httpsListener"),
+					ApplicationGatewayHTTPListenerPropertiesFormat: &network.ApplicationGatewayHTTPListenerPropertiesFormat{
+						FrontendIPConfiguration: &network.SubResource{ID: to.StringPtr(appGatewayID + "/frontendIPConfigurations/frontendIPConfig")},
+						FrontendPort:            &network.SubResource{ID: to.StringPtr(appGatewayID + "/frontendPorts/frontendPort")},
+						Protocol:                network.HTTPS,
+						SslCertificate:          &network.SubResource{ID: to.StringPtr(appGatewayID + "/sslCertificates/sslCertificate")},
+					},
+				},
+			},
+			RequestRoutingRules: &[]network.ApplicationGatewayRequestRoutingRule{
+				{
+					Name: to.StringPtr("apiServerRoutingRule"),
+					ApplicationGatewayRequestRoutingRulePropertiesFormat: &network.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+						RuleType:            network.Basic,
+						BackendAddressPool:  &network.SubResource{ID: to.StringPtr(appGatewayID + "/backendAddressPools/my-cluster-appgw-backendPool")},
+						BackendHTTPSettings: &network.SubResource{ID: to.StringPtr(appGatewayID + "/backendHttpSettingsCollection/apiServerHTTPSettings")},
+						HTTPListener:        &network.SubResource{ID: to.StringPtr(appGatewayID + "/httpListeners/httpsListener")},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestReconcileApplicationGateway(t *testing.T) {
+	provisioned := fakeApplicationGateway()
+	provisioned.ProvisioningState = network.Succeeded
+	scaledIn := fakeApplicationGateway()
+	scaledIn.ProvisioningState = network.Succeeded
+	scaledIn.Sku.Capacity = to.Int32Ptr(1)
+	failed := fakeApplicationGateway()
+	failed.ProvisioningState = network.Failed
+	withFirewallPolicy := fakeApplicationGateway()
+	withFirewallPolicy.Sku.Name = network.WAFV2
+	withFirewallPolicy.Sku.Tier = network.ApplicationGatewayTierWAFV2
+	withFirewallPolicy.FirewallPolicy = &network.SubResource{ID: to.StringPtr("my-policy-id")}
+
+	testcases := []struct {
+		name          string
+		spec          *azure.ApplicationGatewaySpec
+		expectedError string
+		expect        func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name: "no application gateway",
+			spec: nil,
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+			},
+		},
+		{
+			name: "create the application gateway",
+			spec: fakeApplicationGatewaySpec,
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(network.ApplicationGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-cluster-appgw", matchers.DiffEq(fakeApplicationGateway())).Return(azureautorest.Future{}, nil)
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-cluster-appgw", serviceName)
+			},
+		},
+		{
+			name: "create the application gateway with a web application firewall policy",
+			spec: &azure.ApplicationGatewaySpec{
+				Name:                   "my-cluster-appgw",
+				SubnetName:             "my-cluster-appgw-subnet",
+				SubnetCIDR:             "10.255.254.0/24",
+				VNetName:               "my-vnet",
+				PublicIPName:           "pip-my-cluster-appgw",
+				Capacity:               2,
+				FrontendPort:           443,
+				BackendPort:            6443,
+				HostName:               "my-cluster.westus2.cloudapp.azure.com",
+				SSLCertificateSecretID: "https://my-vault.vault.azure.net/secrets/my-certificate",
+				IdentityID:             "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+				FirewallPolicyID:       "my-policy-id",
+			},
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(network.ApplicationGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-cluster-appgw", matchers.DiffEq(withFirewallPolicy)).Return(azureautorest.Future{}, nil)
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-cluster-appgw", serviceName)
+			},
+		},
+		{
+			name: "application gateway is up to date",
+			spec: fakeApplicationGatewaySpec,
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(provisioned, nil)
+			},
+		},
+		{
+			name: "update the application gateway scaled in out of band",
+			spec: fakeApplicationGatewaySpec,
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(scaledIn, nil)
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-cluster-appgw", matchers.DiffEq(fakeApplicationGateway())).Return(azureautorest.Future{}, nil)
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-cluster-appgw", serviceName)
+			},
+		},
+		{
+			name: "update the application gateway which failed to provision",
+			spec: fakeApplicationGatewaySpec,
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(failed, nil)
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-cluster-appgw", matchers.DiffEq(fakeApplicationGateway())).Return(azureautorest.Future{}, nil)
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-cluster-appgw", serviceName)
+			},
+		},
+		{
+			name:          "application gateway not owned by the cluster",
+			spec:          fakeApplicationGatewaySpec,
+			expectedError: "application gateway my-cluster-appgw already exists in resource group my-rg and isn't owned by cluster my-cluster",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(network.ApplicationGateway{Name: to.StringPtr("my-cluster-appgw")}, nil)
+			},
+		},
+		{
+			name:          "application gateway creation fails",
+			spec:          fakeApplicationGatewaySpec,
+			expectedError: "failed to create application gateway my-cluster-appgw in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(network.ApplicationGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-cluster-appgw", gomock.AssignableToTypeOf(network.ApplicationGateway{})).Return(azureautorest.Future{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationgateways.NewMockApplicationGatewayScope(mockCtrl)
+			clientMock := mock_applicationgateways.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.ApplicationGatewaySpec().Return(tc.spec)
+			if tc.spec != nil {
+				s.GetLongRunningOperationState(tc.spec.Name, serviceName).Return(nil)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				s.ClusterCACertificate(context.TODO()).Return(fakeCACert, nil)
+				subnetsMock.EXPECT().Get(context.TODO(), "vnet-rg", "my-vnet", "my-cluster-appgw-subnet").Return(network201906.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+				publicIPsMock.EXPECT().Get(context.TODO(), "my-rg", "pip-my-cluster-appgw").Return(network201906.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+			}
+			tc.expect(s, clientMock.EXPECT(), subnetsMock.EXPECT(), publicIPsMock.EXPECT())
+
+			svc := &Service{
+				Scope:           scopeMock,
+				Client:          clientMock,
+				SubnetsClient:   subnetsMock,
+				PublicIPsClient: publicIPsMock,
+			}
+
+			err := svc.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileApplicationGatewayFailures(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name:          "subnet retrieval fails",
+			expectedError: "failed to get subnet my-cluster-appgw-subnet for application gateway my-cluster-appgw: #: Not found: StatusCode=404",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-cluster-appgw-subnet").Return(network201906.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "public IP retrieval fails",
+			expectedError: "failed to get public IP pip-my-cluster-appgw for application gateway my-cluster-appgw: #: Not found: StatusCode=404",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-cluster-appgw-subnet").Return(network201906.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-cluster-appgw").Return(network201906.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "CA certificate of the cluster isn't available",
+			expectedError: "failed to get the trusted root certificate of application gateway my-cluster-appgw: secret not found",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-cluster-appgw-subnet").Return(network201906.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-cluster-appgw").Return(network201906.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+				s.ClusterCACertificate(context.TODO()).Return(nil, errors.New("secret not found"))
+			},
+		},
+		{
+			name:          "CA certificate of the cluster isn't PEM encoded",
+			expectedError: "failed to decode the trusted root certificate of application gateway my-cluster-appgw",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-cluster-appgw-subnet").Return(network201906.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-cluster-appgw").Return(network201906.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+				s.ClusterCACertificate(context.TODO()).Return([]byte("my-ca-cert"), nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationgateways.NewMockApplicationGatewayScope(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.ApplicationGatewaySpec().Return(fakeApplicationGatewaySpec)
+			s.GetLongRunningOperationState("my-cluster-appgw", serviceName).Return(nil)
+			s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+			s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+			tc.expect(s, subnetsMock.EXPECT(), publicIPsMock.EXPECT())
+
+			svc := &Service{
+				Scope:           scopeMock,
+				Client:          mock_applicationgateways.NewMockClient(mockCtrl),
+				SubnetsClient:   subnetsMock,
+				PublicIPsClient: publicIPsMock,
+			}
+
+			g.Expect(svc.Reconcile(context.TODO())).To(MatchError(tc.expectedError))
+		})
+	}
+}
+
+func TestReconcileApplicationGatewayOperations(t *testing.T) {
+	g := NewWithT(t)
+
+	future, err := test.NewPutFuture("https://management.azure.com"+appGatewayID, "https://management.azure.com/operations/my-op")
+	g.Expect(err).NotTo(HaveOccurred())
+	data, err := future.MarshalJSON()
+	g.Expect(err).NotTo(HaveOccurred())
+	ongoing := &infrav1.Future{
+		Type:          infrav1.PutFuture,
+		ServiceName:   serviceName,
+		ResourceGroup: "my-rg",
+		Name:          "my-cluster-appgw",
+		Data:          string(data),
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder)
+	}{
+		{
+			name:          "creation is in progress",
+			expectedError: "operation type PUT on Azure resource my-rg/my-cluster-appgw is not done, state InProgress",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-cluster-appgw", serviceName).Return(nil)
+				s.ClusterCACertificate(context.TODO()).Return(fakeCACert, nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(network.ApplicationGateway{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-cluster-appgw", gomock.AssignableToTypeOf(network.ApplicationGateway{})).Return(future, nil)
+				m.IsDone(context.TODO(), future).Return(false, nil)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name:          "ongoing creation is still in progress",
+			expectedError: "operation type PUT on Azure resource my-rg/my-cluster-appgw is not done, state InProgress",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-cluster-appgw", serviceName).Return(ongoing.DeepCopy())
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(false, nil)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name: "ongoing creation is done",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-cluster-appgw", serviceName).Return(ongoing.DeepCopy())
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-cluster-appgw", serviceName)
+			},
+		},
+		{
+			name:          "ongoing creation failed",
+			expectedError: "failed PUT operation on applicationgateways my-cluster-appgw: #: Conflict: StatusCode=409",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-cluster-appgw", serviceName).Return(ongoing.DeepCopy())
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+				s.DeleteLongRunningOperationState("my-cluster-appgw", serviceName)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationgateways.NewMockApplicationGatewayScope(mockCtrl)
+			clientMock := mock_applicationgateways.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.ApplicationGatewaySpec().Return(fakeApplicationGatewaySpec)
+			s.SubscriptionID().AnyTimes().Return("123")
+			s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+			s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+			s.Location().AnyTimes().Return("westus2")
+			s.ClusterName().AnyTimes().Return("my-cluster")
+			s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			subnetsMock.EXPECT().Get(context.TODO(), "vnet-rg", "my-vnet", "my-cluster-appgw-subnet").AnyTimes().Return(network201906.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+			publicIPsMock.EXPECT().Get(context.TODO(), "my-rg", "pip-my-cluster-appgw").AnyTimes().Return(network201906.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+			tc.expect(s, clientMock.EXPECT())
+
+			svc := &Service{
+				Scope:           scopeMock,
+				Client:          clientMock,
+				SubnetsClient:   subnetsMock,
+				PublicIPsClient: publicIPsMock,
+			}
+
+			err := svc.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteApplicationGateway(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder)
+	}{
+		{
+			name:          "no application gateway",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.ApplicationGatewaySpec().Return(nil)
+			},
+		},
+		{
+			name:          "successfully delete the application gateway",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.ApplicationGatewaySpec().Return(fakeApplicationGatewaySpec)
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-appgw")
+			},
+		},
+		{
+			name:          "application gateway already deleted",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.ApplicationGatewaySpec().Return(fakeApplicationGatewaySpec)
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-appgw").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "skip application gateway not owned by the cluster in a pre-existing resource group",
+			expectedError: "",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.ApplicationGatewaySpec().Return(fakeApplicationGatewaySpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(false)
+				m.Get(context.TODO(), "my-rg", "my-cluster-appgw").Return(network.ApplicationGateway{}, nil)
+			},
+		},
+		{
+			name:          "application gateway deletion fails",
+			expectedError: "failed to delete application gateway my-cluster-appgw in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_applicationgateways.MockApplicationGatewayScopeMockRecorder, m *mock_applicationgateways.MockClientMockRecorder) {
+				s.ApplicationGatewaySpec().Return(fakeApplicationGatewaySpec)
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-appgw").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_applicationgateways.NewMockApplicationGatewayScope(mockCtrl)
+			clientMock := mock_applicationgateways.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			scopeMock.EXPECT().NetworkResourceGroup().AnyTimes().Return("my-rg")
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationgateways
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (network.ApplicationGateway, error)
+	CreateOrUpdateAsync(context.Context, string, string, network.ApplicationGateway) (azureautorest.Future, error)
+	IsDone(context.Context, azureautorest.Future) (bool, error)
+	Delete(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	applicationgateways network.ApplicationGatewaysClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new application gateways client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newApplicationGatewaysClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newApplicationGatewaysClient creates a new application gateways client from subscription ID.
+func newApplicationGatewaysClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.ApplicationGatewaysClient {
+	applicationGatewaysClient := network.NewApplicationGatewaysClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&applicationGatewaysClient.Client, authorizer)
+	return applicationGatewaysClient
+}
+
+// Get gets the specified application gateway.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, name string) (network.ApplicationGateway, error) {
+	return ac.applicationgateways.Get(ctx, resourceGroupName, name)
+}
+
+// CreateOrUpdateAsync starts creating or updating an application gateway, and returns the future of the operation
+// without waiting for it to complete.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, name string, appGateway network.ApplicationGateway) (azureautorest.Future, error) {
+	future, err := ac.applicationgateways.CreateOrUpdate(ctx, resourceGroupName, name, appGateway)
+	if err != nil {
+		return azureautorest.Future{}, err
+	}
+	return future.Future, nil
+}
+
+// IsDone polls a long-running application gateway operation once and returns whether it is done.
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.Future) (bool, error) {
+	return future.DoneWithContext(ctx, ac.applicationgateways)
+}
+
+// Delete deletes the specified application gateway.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, name string) error {
+	future, err := ac.applicationgateways.Delete(ctx, resourceGroupName, name)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.applicationgateways.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.applicationgateways)
+	return err
+}
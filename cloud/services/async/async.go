@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// FutureHandler polls the long-running operations started by a client.
+type FutureHandler interface {
+	// IsDone polls a long-running operation once and returns whether it is done.
+	IsDone(ctx context.Context, future azureautorest.Future) (bool, error)
+}
+
+// StartOperation saves the state of a long-running operation just started on a resource, so it is polled by the
+// following reconciles. It returns an OperationNotDoneError unless the operation is already done.
+func StartOperation(ctx context.Context, scope azure.FutureScope, client FutureHandler, future azureautorest.Future, futureType, service, resourceGroup, name string) error {
+	state := &infrav1.Future{
+		Type:          futureType,
+		ServiceName:   service,
+		ResourceGroup: resourceGroup,
+		Name:          name,
+	}
+	_, err := poll(ctx, scope, client, future, state)
+	return err
+}
+
+// ProcessOngoingOperation polls the long-running operation in progress on a resource, if there is one. It returns
+// true when the operation is done, and an OperationNotDoneError while it is in progress. The state of a done or
+// failed operation is deleted, so a failed operation is started again by the next reconcile.
+func ProcessOngoingOperation(ctx context.Context, scope azure.FutureScope, client FutureHandler, name, service string) (bool, error) {
+	state := scope.GetLongRunningOperationState(name, service)
+	if state == nil {
+		return false, nil
+	}
+	var future azureautorest.Future
+	if err := future.UnmarshalJSON([]byte(state.Data)); err != nil {
+		scope.DeleteLongRunningOperationState(name, service)
+		return false, errors.Wrapf(err, "failed to decode the %s operation on %s %s", state.Type, service, name)
+	}
+	return poll(ctx, scope, client, future, state)
+}
+
+func poll(ctx context.Context, scope azure.FutureScope, client FutureHandler, future azureautorest.Future, state *infrav1.Future) (bool, error) {
+	done, err := client.IsDone(ctx, future)
+	if done {
+		scope.DeleteLongRunningOperationState(state.Name, state.ServiceName)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed %s operation on %s %s", state.Type, state.ServiceName, state.Name)
+		}
+		return true, nil
+	}
+	if err != nil {
+		// the operation could not be polled, it is polled again by the next reconcile
+		return false, errors.Wrapf(err, "failed to poll the %s operation on %s %s", state.Type, state.ServiceName, state.Name)
+	}
+
+	data, err := future.MarshalJSON()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to encode the %s operation on %s %s", state.Type, state.ServiceName, state.Name)
+	}
+	state.Data = string(data)
+	state.State = future.Status()
+	state.PercentComplete = percentComplete(future.Response())
+	scope.SetLongRunningOperationState(state)
+	return false, azure.NewOperationNotDoneError(state)
+}
+
+// percentComplete returns the progress of an operation from its last polled status, when Azure reports it.
+func percentComplete(resp *http.Response) *int32 {
+	if resp == nil || resp.Body == nil {
+		return nil
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	status := struct {
+		PercentComplete *float64 `json:"percentComplete"`
+	}{}
+	if err := json.Unmarshal(b, &status); err != nil || status.PercentComplete == nil {
+		return nil
+	}
+	percent := int32(*status.PercentComplete)
+	return &percent
+}
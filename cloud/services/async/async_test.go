@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+)
+
+// futureScope keeps the states of the long-running operations in memory.
+type futureScope struct {
+	states []infrav1.Future
+}
+
+func (s *futureScope) GetLongRunningOperationState(name, service string) *infrav1.Future {
+	for i := range s.states {
+		if s.states[i].Name == name && s.states[i].ServiceName == service {
+			return &s.states[i]
+		}
+	}
+	return nil
+}
+
+func (s *futureScope) SetLongRunningOperationState(future *infrav1.Future) {
+	s.DeleteLongRunningOperationState(future.Name, future.ServiceName)
+	s.states = append(s.states, *future)
+}
+
+func (s *futureScope) DeleteLongRunningOperationState(name, service string) {
+	states := []infrav1.Future{}
+	for _, state := range s.states {
+		if state.Name != name || state.ServiceName != service {
+			states = append(states, state)
+		}
+	}
+	s.states = states
+}
+
+func newFuture(g *WithT) azureautorest.Future {
+	future, err := test.NewPutFuture("https://management.azure.com/my-lb", "https://management.azure.com/operations/my-op")
+	g.Expect(err).NotTo(HaveOccurred())
+	return future
+}
+
+func TestStartOperation(t *testing.T) {
+	testcases := []struct {
+		name          string
+		done          bool
+		pollErr       error
+		expectedError string
+		expectSaved   bool
+	}{
+		{
+			name: "operation already done",
+			done: true,
+		},
+		{
+			name:          "operation in progress",
+			expectedError: "operation type PUT on Azure resource my-rg/my-lb is not done, state InProgress",
+			expectSaved:   true,
+		},
+		{
+			name:          "operation failed",
+			done:          true,
+			pollErr:       errors.New("Conflict"),
+			expectedError: "failed PUT operation on loadbalancers my-lb: Conflict",
+		},
+		{
+			name:          "operation could not be polled",
+			pollErr:       errors.New("connection reset"),
+			expectedError: "failed to poll the PUT operation on loadbalancers my-lb: connection reset",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scope := &futureScope{}
+			client := mock_async.NewMockFutureHandler(mockCtrl)
+			client.EXPECT().IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(tc.done, tc.pollErr)
+
+			err := StartOperation(context.TODO(), scope, client, newFuture(g), infrav1.PutFuture, "loadbalancers", "my-rg", "my-lb")
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(azure.IsOperationNotDoneError(err)).To(Equal(tc.expectSaved))
+			g.Expect(scope.GetLongRunningOperationState("my-lb", "loadbalancers") != nil).To(Equal(tc.expectSaved))
+		})
+	}
+}
+
+func TestProcessOngoingOperation(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scope := &futureScope{}
+	client := mock_async.NewMockFutureHandler(mockCtrl)
+
+	done, err := ProcessOngoingOperation(context.TODO(), scope, client, "my-lb", "loadbalancers")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(done).To(BeFalse())
+
+	client.EXPECT().IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(false, nil)
+	err = StartOperation(context.TODO(), scope, client, newFuture(g), infrav1.PutFuture, "loadbalancers", "my-rg", "my-lb")
+	g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+
+	// the saved state resumes polling the operation
+	client.EXPECT().IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).
+		DoAndReturn(func(_ context.Context, future azureautorest.Future) (bool, error) {
+			g.Expect(future.PollingURL()).To(Equal("https://management.azure.com/operations/my-op"))
+			return false, nil
+		})
+	done, err = ProcessOngoingOperation(context.TODO(), scope, client, "my-lb", "loadbalancers")
+	g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+	g.Expect(done).To(BeFalse())
+
+	client.EXPECT().IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+	done, err = ProcessOngoingOperation(context.TODO(), scope, client, "my-lb", "loadbalancers")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(done).To(BeTrue())
+	g.Expect(scope.GetLongRunningOperationState("my-lb", "loadbalancers")).To(BeNil())
+}
+
+func TestProcessOngoingOperationInvalidState(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scope := &futureScope{states: []infrav1.Future{{Type: infrav1.PutFuture, ServiceName: "loadbalancers", ResourceGroup: "my-rg", Name: "my-lb", Data: "{}"}}}
+	client := mock_async.NewMockFutureHandler(mockCtrl)
+
+	_, err := ProcessOngoingOperation(context.TODO(), scope, client, "my-lb", "loadbalancers")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(scope.GetLongRunningOperationState("my-lb", "loadbalancers")).To(BeNil())
+}
+
+func TestPercentComplete(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(percentComplete(nil)).To(BeNil())
+	resp := &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{"status": "InProgress", "percentComplete": 42.5}`))}
+	g.Expect(percentComplete(resp)).To(Equal(to.Int32Ptr(42)))
+	// the body is kept for the next readers
+	body, err := ioutil.ReadAll(resp.Body)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(body)).To(ContainSubstring("percentComplete"))
+	g.Expect(percentComplete(&http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{"status": "InProgress"}`))})).To(BeNil())
+}
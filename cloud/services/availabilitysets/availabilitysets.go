@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package availabilitysets
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Reconcile creates or updates the availability sets of the machines of a cluster in a location without availability
+// zones. The availability sets are in the proximity placement group of the cluster, when there is one, as are their VMs.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, asSpec := range s.Scope.AvailabilitySetSpecs() {
+		_, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), asSpec.Name)
+		switch {
+		case err != nil && !azure.ResourceNotFound(err):
+			return errors.Wrapf(err, "failed to get availability set %s in resource group %s", asSpec.Name, s.Scope.ResourceGroup())
+		case err == nil:
+			// the fault and update domain counts of an availability set can't be changed once it is created
+			s.Scope.V(4).Info("Skipping reconcile of existing availability set", "availability set", asSpec.Name)
+			continue
+		}
+
+		as := compute.AvailabilitySet{
+			Location: to.StringPtr(s.Scope.Location()),
+			// the VMs of the cluster have managed disks
+			Sku: &compute.Sku{Name: to.StringPtr(string(compute.Aligned))},
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(asSpec.Name),
+				Role:        to.StringPtr(asSpec.Role),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
+			AvailabilitySetProperties: &compute.AvailabilitySetProperties{
+				PlatformFaultDomainCount:  to.Int32Ptr(azure.AvailabilitySetFaultDomainCount),
+				PlatformUpdateDomainCount: to.Int32Ptr(azure.AvailabilitySetUpdateDomainCount),
+			},
+		}
+		if ppg := s.Scope.ProximityPlacementGroupSpec(); ppg != nil {
+			as.ProximityPlacementGroup = &compute.SubResource{ID: to.StringPtr(ppg.ID)}
+		}
+
+		s.Scope.V(2).Info("creating availability set", "availability set", asSpec.Name)
+		if err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), asSpec.Name, as); err != nil {
+			return errors.Wrapf(err, "failed to create availability set %s in resource group %s", asSpec.Name, s.Scope.ResourceGroup())
+		}
+		s.Scope.V(2).Info("successfully created availability set", "availability set", asSpec.Name)
+	}
+	return nil
+}
+
+// Delete deletes the availability sets owned by the cluster. Their VMs are deleted with the machines, before the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, asSpec := range s.Scope.AvailabilitySetSpecs() {
+		existing, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), asSpec.Name)
+		if azure.ResourceNotFound(err) {
+			// already deleted
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get availability set %s in resource group %s", asSpec.Name, s.Scope.ResourceGroup())
+		}
+		if !converters.MapToTags(existing.Tags).HasOwned(s.Scope.ClusterName()) {
+			s.Scope.V(4).Info("Skipping deletion of availability set not owned by the cluster", "availability set", asSpec.Name)
+			continue
+		}
+
+		s.Scope.V(2).Info("deleting availability set", "availability set", asSpec.Name)
+		err = s.Client.Delete(ctx, s.Scope.ResourceGroup(), asSpec.Name)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete availability set %s in resource group %s", asSpec.Name, s.Scope.ResourceGroup())
+		}
+		s.Scope.V(2).Info("successfully deleted availability set", "availability set", asSpec.Name)
+	}
+	return nil
+}
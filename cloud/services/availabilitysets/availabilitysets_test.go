@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package availabilitysets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/availabilitysets/mock_availabilitysets"
+)
+
+var ownedTags = map[string]*string{
+	"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+}
+
+var availabilitySetSpecs = []azure.AvailabilitySetSpec{
+	{Name: "my-cluster-control-plane-as", Role: infrav1.ControlPlane},
+	{Name: "my-cluster-node-as", Role: infrav1.Node},
+}
+
+func TestReconcileAvailabilitySets(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder)
+	}{
+		{
+			name:          "no availability sets",
+			expectedError: "",
+			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
+				s.AvailabilitySetSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "availability sets are created",
+			expectedError: "",
+			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
+				s.AvailabilitySetSpecs().Return(availabilitySetSpecs)
+				s.ProximityPlacementGroupSpec().AnyTimes().Return(nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(compute.AvailabilitySet{}, notFound)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-control-plane-as", availabilitySetMatcher{role: infrav1.ControlPlane})
+				m.Get(context.TODO(), "my-rg", "my-cluster-node-as").Return(compute.AvailabilitySet{}, notFound)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-node-as", availabilitySetMatcher{role: infrav1.Node})
+			},
+		},
+		{
+			name:          "availability sets are created in the proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
+				s.AvailabilitySetSpecs().Return(availabilitySetSpecs[:1])
+				s.ProximityPlacementGroupSpec().AnyTimes().Return(&azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg", ID: "my-ppg-id"})
+				m.Get(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(compute.AvailabilitySet{}, notFound)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-control-plane-as", availabilitySetMatcher{role: infrav1.ControlPlane, ppgID: "my-ppg-id"})
+			},
+		},
+		{
+			name:          "existing availability sets are left as is",
+			expectedError: "",
+			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
+				s.AvailabilitySetSpecs().Return(availabilitySetSpecs)
+				m.Get(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(compute.AvailabilitySet{Tags: ownedTags}, nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-node-as").Return(compute.AvailabilitySet{Tags: ownedTags}, nil)
+			},
+		},
+		{
+			name:          "fail to create an availability set",
+			expectedError: "failed to create availability set my-cluster-control-plane-as in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_availabilitysets.MockAvailabilitySetScopeMockRecorder, m *mock_availabilitysets.MockClientMockRecorder) {
+				s.AvailabilitySetSpecs().Return(availabilitySetSpecs)
+				s.ProximityPlacementGroupSpec().AnyTimes().Return(nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(compute.AvailabilitySet{}, notFound)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-control-plane-as", availabilitySetMatcher{role: infrav1.ControlPlane}).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_availabilitysets.NewMockAvailabilitySetScope(mockCtrl)
+			clientMock := mock_availabilitysets.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().Location().AnyTimes().Return("westus")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestDeleteAvailabilitySets(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(m *mock_availabilitysets.MockClientMockRecorder)
+	}{
+		{
+			name:          "owned availability sets are deleted",
+			expectedError: "",
+			expect: func(m *mock_availabilitysets.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(compute.AvailabilitySet{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-control-plane-as")
+				m.Get(context.TODO(), "my-rg", "my-cluster-node-as").Return(compute.AvailabilitySet{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-node-as")
+			},
+		},
+		{
+			name:          "availability sets not owned by the cluster or already deleted are skipped",
+			expectedError: "",
+			expect: func(m *mock_availabilitysets.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(compute.AvailabilitySet{}, nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-node-as").Return(compute.AvailabilitySet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "fail to delete an availability set",
+			expectedError: "failed to delete availability set my-cluster-control-plane-as in resource group my-rg: #: Conflict: StatusCode=409",
+			expect: func(m *mock_availabilitysets.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(compute.AvailabilitySet{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-control-plane-as").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_availabilitysets.NewMockAvailabilitySetScope(mockCtrl)
+			clientMock := mock_availabilitysets.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			scopeMock.EXPECT().ResourceGroup().AnyTimes().Return("my-rg")
+			scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+			scopeMock.EXPECT().AvailabilitySetSpecs().Return(availabilitySetSpecs)
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+// availabilitySetMatcher matches an availability set of the machines of a role, with managed disks, 2 fault domains,
+// 5 update domains and, when set, in a proximity placement group.
+type availabilitySetMatcher struct {
+	role  string
+	ppgID string
+}
+
+func (m availabilitySetMatcher) Matches(x interface{}) bool {
+	as, ok := x.(compute.AvailabilitySet)
+	if !ok || as.Sku == nil || as.AvailabilitySetProperties == nil {
+		return false
+	}
+	var ppgID string
+	if as.ProximityPlacementGroup != nil {
+		ppgID = to.String(as.ProximityPlacementGroup.ID)
+	}
+	return to.String(as.Sku.Name) == string(compute.Aligned) &&
+		to.Int32(as.PlatformFaultDomainCount) == 2 &&
+		to.Int32(as.PlatformUpdateDomainCount) == 5 &&
+		to.String(as.Tags["sigs.k8s.io_cluster-api-provider-azure_role"]) == m.role &&
+		ppgID == m.ppgID
+}
+
+func (m availabilitySetMatcher) String() string {
+	return fmt.Sprintf("is an availability set of the %s machines in proximity placement group %q", m.role, m.ppgID)
+}
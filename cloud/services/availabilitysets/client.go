@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package availabilitysets
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (compute.AvailabilitySet, error)
+	CreateOrUpdate(context.Context, string, string, compute.AvailabilitySet) error
+	Delete(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	availabilitysets compute.AvailabilitySetsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new availability sets client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newAvailabilitySetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newAvailabilitySetsClient creates a new availability sets client from subscription ID.
+func newAvailabilitySetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.AvailabilitySetsClient {
+	availabilitySetsClient := compute.NewAvailabilitySetsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&availabilitySetsClient.Client, authorizer)
+	return availabilitySetsClient
+}
+
+// Get gets the specified availability set in a specified resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, asName string) (compute.AvailabilitySet, error) {
+	return ac.availabilitysets.Get(ctx, resourceGroupName, asName)
+}
+
+// CreateOrUpdate creates or updates an availability set.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, asName string, as compute.AvailabilitySet) error {
+	_, err := ac.availabilitysets.CreateOrUpdate(ctx, resourceGroupName, asName, as)
+	return err
+}
+
+// Delete deletes the specified availability set.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, asName string) error {
+	_, err := ac.availabilitysets.Delete(ctx, resourceGroupName, asName)
+	return err
+}
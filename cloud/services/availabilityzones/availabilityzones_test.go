@@ -149,6 +149,9 @@ func TestGetAvailabilityZones(t *testing.T) {
 							Subnets: []*infrav1.SubnetSpec{{
 								Name: "my-subnet",
 								Role: infrav1.SubnetNode,
+							}, {
+								Name: "my-subnet-cp",
+								Role: infrav1.SubnetControlPlane,
 							}},
 						},
 					},
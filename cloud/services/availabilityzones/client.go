@@ -45,8 +45,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // getResourceSkusClient creates a new availability zones client from subscription ID.
 func newResourceSkusClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.ResourceSkusClient {
 	skusClient := compute.NewResourceSkusClientWithBaseURI(baseURI, subscriptionID)
-	skusClient.Authorizer = authorizer
-	skusClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&skusClient.Client, authorizer)
 	return skusClient
 }
 
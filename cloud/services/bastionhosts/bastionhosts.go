@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bastionhosts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Reconcile gets/creates/updates the bastion host of the cluster, in its subnet and with its public IP.
+func (s *Service) Reconcile(ctx context.Context) error {
+	bastionSpec := s.Scope.BastionSpec()
+	if bastionSpec == nil {
+		return nil
+	}
+
+	s.Scope.V(2).Info("creating bastion host", "bastion", bastionSpec.Name)
+	subnet, err := s.SubnetsClient.Get(ctx, s.Scope.Vnet().ResourceGroup, bastionSpec.VNetName, bastionSpec.SubnetName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get subnet %s for bastion host %s", bastionSpec.SubnetName, bastionSpec.Name)
+	}
+	publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), bastionSpec.PublicIPName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get public IP %s for bastion host %s", bastionSpec.PublicIPName, bastionSpec.Name)
+	}
+
+	err = s.Client.CreateOrUpdate(
+		ctx,
+		s.Scope.NetworkResourceGroup(),
+		bastionSpec.Name,
+		network.BastionHost{
+			Name:     to.StringPtr(bastionSpec.Name),
+			Location: to.StringPtr(s.Scope.Location()),
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(bastionSpec.Name),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
+			BastionHostPropertiesFormat: &network.BastionHostPropertiesFormat{
+				IPConfigurations: &[]network.BastionHostIPConfiguration{
+					{
+						Name: to.StringPtr(bastionSpec.Name + "-ipconfig"),
+						BastionHostIPConfigurationPropertiesFormat: &network.BastionHostIPConfigurationPropertiesFormat{
+							Subnet:                    &network.SubResource{ID: subnet.ID},
+							PublicIPAddress:           &network.SubResource{ID: publicIP.ID},
+							PrivateIPAllocationMethod: network.Dynamic,
+						},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create bastion host %s in resource group %s", bastionSpec.Name, s.Scope.NetworkResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully created bastion host", "bastion", bastionSpec.Name)
+	return nil
+}
+
+// Delete deletes the bastion host of the cluster. It must be deleted before its subnet and public IP.
+func (s *Service) Delete(ctx context.Context) error {
+	bastionSpec := s.Scope.BastionSpec()
+	if bastionSpec == nil {
+		return nil
+	}
+
+	if !s.Scope.IsNetworkResourceGroupManaged() {
+		// only delete the bastion host owned by the cluster from a pre-existing resource group
+		bastionHost, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), bastionSpec.Name)
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get bastion host %s in resource group %s", bastionSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+		if !converters.MapToTags(bastionHost.Tags).HasOwned(s.Scope.ClusterName()) {
+			s.Scope.V(4).Info("Skipping deletion of bastion host not owned by the cluster", "bastion", bastionSpec.Name)
+			return nil
+		}
+	}
+
+	s.Scope.V(2).Info("deleting bastion host", "bastion", bastionSpec.Name)
+	err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), bastionSpec.Name)
+	if err != nil && azure.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete bastion host %s in resource group %s", bastionSpec.Name, s.Scope.NetworkResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully deleted bastion host", "bastion", bastionSpec.Name)
+	return nil
+}
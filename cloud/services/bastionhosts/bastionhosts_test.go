@@ -0,0 +1,240 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bastionhosts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/bastionhosts/mock_bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+)
+
+var fakeBastionSpec = &azure.BastionSpec{
+	Name:         "my-bastion",
+	SubnetName:   "AzureBastionSubnet",
+	SubnetCIDR:   "10.255.255.192/26",
+	PublicIPName: "pip-my-bastion",
+	VNetName:     "my-vnet",
+}
+
+func TestReconcileBastionHosts(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name:          "no bastion host",
+			expectedError: "",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.BastionSpec().Return(nil)
+			},
+		},
+		{
+			name:          "bastion host is created in its subnet with its public IP",
+			expectedError: "",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "AzureBastionSubnet").Return(network.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-bastion").Return(network.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-bastion", gomock.AssignableToTypeOf(network.BastionHost{})).Do(
+					func(_ context.Context, _, _ string, bastionHost network.BastionHost) {
+						ipConfig := (*bastionHost.IPConfigurations)[0]
+						if to.String(ipConfig.Subnet.ID) != "subnet-id" || to.String(ipConfig.PublicIPAddress.ID) != "pip-id" {
+							t.Errorf("unexpected IP configuration of bastion host: subnet %s, public IP %s",
+								to.String(ipConfig.Subnet.ID), to.String(ipConfig.PublicIPAddress.ID))
+						}
+					})
+			},
+		},
+		{
+			name:          "fail to get the bastion subnet",
+			expectedError: "failed to get subnet AzureBastionSubnet for bastion host my-bastion: #: Not found: StatusCode=404",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "AzureBastionSubnet").Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "fail to get the bastion public IP",
+			expectedError: "failed to get public IP pip-my-bastion for bastion host my-bastion: #: Not found: StatusCode=404",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "AzureBastionSubnet").Return(network.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-bastion").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "fail to create the bastion host",
+			expectedError: "failed to create bastion host my-bastion in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "AzureBastionSubnet").Return(network.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-bastion").Return(network.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-bastion", gomock.AssignableToTypeOf(network.BastionHost{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bastionhosts.NewMockBastionScope(mockCtrl)
+			clientMock := mock_bastionhosts.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), subnetsMock.EXPECT(), publicIPsMock.EXPECT())
+
+			s := &Service{
+				Scope:           scopeMock,
+				Client:          clientMock,
+				SubnetsClient:   subnetsMock,
+				PublicIPsClient: publicIPsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBastionHosts(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete the bastion host",
+			expectedError: "",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-bastion")
+			},
+		},
+		{
+			name:          "bastion host already deleted",
+			expectedError: "",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-bastion").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "skip bastion host not owned by the cluster in a pre-existing resource group",
+			expectedError: "",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(false)
+				m.Get(context.TODO(), "my-rg", "my-bastion").Return(network.BastionHost{}, nil)
+			},
+		},
+		{
+			name:          "bastion host deletion fails",
+			expectedError: "failed to delete bastion host my-bastion in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_bastionhosts.MockBastionScopeMockRecorder, m *mock_bastionhosts.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.BastionSpec().Return(fakeBastionSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-bastion").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bastionhosts.NewMockBastionScope(mockCtrl)
+			clientMock := mock_bastionhosts.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
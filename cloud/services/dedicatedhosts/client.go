@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedicatedhosts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	GetGroup(context.Context, string) (compute.DedicatedHostGroup, error)
+	GetHost(context.Context, string) (compute.DedicatedHost, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	baseURI    string
+	authorizer autorest.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new dedicated hosts client. The dedicated hosts can be in other resource groups and
+// subscriptions than the ones of the cluster.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		baseURI:    auth.BaseURI(),
+		authorizer: auth.Authorizer(),
+	}
+}
+
+// newDedicatedHostGroupsClient creates a new dedicated host groups client from subscription ID.
+func newDedicatedHostGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DedicatedHostGroupsClient {
+	c := compute.NewDedicatedHostGroupsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// newDedicatedHostsClient creates a new dedicated hosts client from subscription ID.
+func newDedicatedHostsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DedicatedHostsClient {
+	c := compute.NewDedicatedHostsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// GetGroup gets the dedicated host group with the given resource ID.
+func (ac *AzureClient) GetGroup(ctx context.Context, hostGroupID string) (compute.DedicatedHostGroup, error) {
+	resource, err := parseHostGroupID(hostGroupID)
+	if err != nil {
+		return compute.DedicatedHostGroup{}, err
+	}
+	return newDedicatedHostGroupsClient(resource.SubscriptionID, ac.baseURI, ac.authorizer).Get(ctx, resource.ResourceGroup, resource.ResourceName, "")
+}
+
+// GetHost gets the dedicated host with the given resource ID, with its instance view holding its available capacity.
+func (ac *AzureClient) GetHost(ctx context.Context, hostID string) (compute.DedicatedHost, error) {
+	i := strings.LastIndex(strings.ToLower(hostID), "/hosts/")
+	if i < 0 {
+		return compute.DedicatedHost{}, errors.Errorf("invalid dedicated host ID %s", hostID)
+	}
+	resource, err := parseHostGroupID(hostID[:i])
+	if err != nil {
+		return compute.DedicatedHost{}, errors.Wrapf(err, "invalid dedicated host ID %s", hostID)
+	}
+	hostName := hostID[i+len("/hosts/"):]
+	return newDedicatedHostsClient(resource.SubscriptionID, ac.baseURI, ac.authorizer).Get(ctx, resource.ResourceGroup, resource.ResourceName, hostName, compute.InstanceView)
+}
+
+func parseHostGroupID(hostGroupID string) (autorestazure.Resource, error) {
+	resource, err := autorestazure.ParseResourceID(hostGroupID)
+	if err != nil {
+		return autorestazure.Resource{}, errors.Wrapf(err, "invalid dedicated host group ID %s", hostGroupID)
+	}
+	if !strings.EqualFold(resource.Provider, "Microsoft.Compute") || !strings.EqualFold(resource.ResourceType, "hostGroups") {
+		return autorestazure.Resource{}, errors.Errorf("invalid dedicated host group ID %s: not a Microsoft.Compute/hostGroups resource", hostGroupID)
+	}
+	return resource, nil
+}
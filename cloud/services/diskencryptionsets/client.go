@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskencryptionsets
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string) (compute.DiskEncryptionSet, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	baseURI    string
+	authorizer autorest.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new disk encryption sets client. The disk encryption sets can be in other
+// resource groups than the one of the cluster.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		baseURI:    auth.BaseURI(),
+		authorizer: auth.Authorizer(),
+	}
+}
+
+// newDiskEncryptionSetsClient creates a new disk encryption sets client from subscription ID.
+func newDiskEncryptionSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DiskEncryptionSetsClient {
+	c := compute.NewDiskEncryptionSetsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// Get gets the disk encryption set with the given resource ID.
+func (ac *AzureClient) Get(ctx context.Context, resourceID string) (compute.DiskEncryptionSet, error) {
+	resource, err := autorestazure.ParseResourceID(resourceID)
+	if err != nil {
+		return compute.DiskEncryptionSet{}, errors.Wrapf(err, "invalid disk encryption set ID %s", resourceID)
+	}
+	if !strings.EqualFold(resource.Provider, "Microsoft.Compute") || !strings.EqualFold(resource.ResourceType, "diskEncryptionSets") {
+		return compute.DiskEncryptionSet{}, errors.Errorf("invalid disk encryption set ID %s: not a Microsoft.Compute/diskEncryptionSets resource", resourceID)
+	}
+	return newDiskEncryptionSetsClient(resource.SubscriptionID, ac.baseURI, ac.authorizer).Get(ctx, resource.ResourceGroup, resource.ResourceName)
+}
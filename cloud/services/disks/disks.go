@@ -29,14 +29,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
-// Delete deletes the disk associated with a VM.
+// Delete deletes the OS and data disks associated with a VM.
 func (s *Service) Delete(ctx context.Context) error {
 	for _, diskSpec := range s.Scope.DiskSpecs() {
 		s.Scope.V(2).Info("deleting disk", "disk", diskSpec.Name)
 		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), diskSpec.Name)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
-			return nil
+			continue
 		}
 		if err != nil {
 			return errors.Wrapf(err, "failed to delete disk %s in resource group %s", diskSpec.Name, s.Scope.ResourceGroup())
@@ -69,6 +69,24 @@ func TestDeleteDisk(t *testing.T) {
 				m.Delete(context.TODO(), "my-rg", "my-disk-1").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
 			},
 		},
+		{
+			name:          "first disk already deleted",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, m *mock_disks.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.DiskSpecs().Return([]azure.DiskSpec{
+					{
+						Name: "my-disk-1",
+					},
+					{
+						Name: "honk-disk",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-disk-1").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.Delete(context.TODO(), "my-rg", "honk-disk")
+			},
+		},
 		{
 			name:          "error while trying to delete the disk",
 			expectedError: "failed to delete disk my-disk-1 in resource group my-rg: #: Internal Server Error: StatusCode=500",
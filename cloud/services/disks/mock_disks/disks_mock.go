@@ -202,6 +202,48 @@ func (mr *MockDiskScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockDiskScope)(nil).ResourceGroup))
 }
 
+// IsResourceGroupManaged mocks base method.
+func (m *MockDiskScope) IsResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsResourceGroupManaged indicates an expected call of IsResourceGroupManaged.
+func (mr *MockDiskScopeMockRecorder) IsResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsResourceGroupManaged", reflect.TypeOf((*MockDiskScope)(nil).IsResourceGroupManaged))
+}
+
+// NetworkResourceGroup mocks base method.
+func (m *MockDiskScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockDiskScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockDiskScope)(nil).NetworkResourceGroup))
+}
+
+// IsNetworkResourceGroupManaged mocks base method.
+func (m *MockDiskScope) IsNetworkResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNetworkResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNetworkResourceGroupManaged indicates an expected call of IsNetworkResourceGroupManaged.
+func (mr *MockDiskScopeMockRecorder) IsNetworkResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNetworkResourceGroupManaged", reflect.TypeOf((*MockDiskScope)(nil).IsNetworkResourceGroupManaged))
+}
+
 // ClusterName mocks base method.
 func (m *MockDiskScope) ClusterName() string {
 	m.ctrl.T.Helper()
@@ -244,6 +286,20 @@ func (mr *MockDiskScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockDiskScope)(nil).AdditionalTags))
 }
 
+// LastAppliedTags mocks base method.
+func (m *MockDiskScope) LastAppliedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastAppliedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// LastAppliedTags indicates an expected call of LastAppliedTags.
+func (mr *MockDiskScopeMockRecorder) LastAppliedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAppliedTags", reflect.TypeOf((*MockDiskScope)(nil).LastAppliedTags))
+}
+
 // Vnet mocks base method.
 func (m *MockDiskScope) Vnet() *v1alpha3.VnetSpec {
 	m.ctrl.T.Helper()
@@ -258,6 +314,20 @@ func (mr *MockDiskScopeMockRecorder) Vnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockDiskScope)(nil).Vnet))
 }
 
+// Subnets mocks base method.
+func (m *MockDiskScope) Subnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// Subnets indicates an expected call of Subnets.
+func (mr *MockDiskScopeMockRecorder) Subnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subnets", reflect.TypeOf((*MockDiskScope)(nil).Subnets))
+}
+
 // NodeSubnet mocks base method.
 func (m *MockDiskScope) NodeSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -272,6 +342,20 @@ func (mr *MockDiskScopeMockRecorder) NodeSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnet", reflect.TypeOf((*MockDiskScope)(nil).NodeSubnet))
 }
 
+// NodeSubnets mocks base method.
+func (m *MockDiskScope) NodeSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// NodeSubnets indicates an expected call of NodeSubnets.
+func (mr *MockDiskScopeMockRecorder) NodeSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnets", reflect.TypeOf((*MockDiskScope)(nil).NodeSubnets))
+}
+
 // ControlPlaneSubnet mocks base method.
 func (m *MockDiskScope) ControlPlaneSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -286,6 +370,160 @@ func (mr *MockDiskScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockDiskScope)(nil).ControlPlaneSubnet))
 }
 
+// ControlPlaneSubnets mocks base method.
+func (m *MockDiskScope) ControlPlaneSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// ControlPlaneSubnets indicates an expected call of ControlPlaneSubnets.
+func (mr *MockDiskScopeMockRecorder) ControlPlaneSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnets", reflect.TypeOf((*MockDiskScope)(nil).ControlPlaneSubnets))
+}
+
+// ControlPlaneSubnetForZone mocks base method.
+func (m *MockDiskScope) ControlPlaneSubnetForZone(zone string) *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnetForZone", zone)
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnetForZone indicates an expected call of ControlPlaneSubnetForZone.
+func (mr *MockDiskScopeMockRecorder) ControlPlaneSubnetForZone(zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnetForZone", reflect.TypeOf((*MockDiskScope)(nil).ControlPlaneSubnetForZone), zone)
+}
+
+// IsAPIServerPrivate mocks base method.
+func (m *MockDiskScope) IsAPIServerPrivate() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAPIServerPrivate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAPIServerPrivate indicates an expected call of IsAPIServerPrivate.
+func (mr *MockDiskScopeMockRecorder) IsAPIServerPrivate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAPIServerPrivate", reflect.TypeOf((*MockDiskScope)(nil).IsAPIServerPrivate))
+}
+
+// InternalLBInboundNatRules mocks base method.
+func (m *MockDiskScope) InternalLBInboundNatRules() []v1alpha3.InboundNatRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InternalLBInboundNatRules")
+	ret0, _ := ret[0].([]v1alpha3.InboundNatRule)
+	return ret0
+}
+
+// InternalLBInboundNatRules indicates an expected call of InternalLBInboundNatRules.
+func (mr *MockDiskScopeMockRecorder) InternalLBInboundNatRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalLBInboundNatRules", reflect.TypeOf((*MockDiskScope)(nil).InternalLBInboundNatRules))
+}
+
+// ApplicationGatewayName mocks base method.
+func (m *MockDiskScope) ApplicationGatewayName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationGatewayName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ApplicationGatewayName indicates an expected call of ApplicationGatewayName.
+func (mr *MockDiskScopeMockRecorder) ApplicationGatewayName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationGatewayName", reflect.TypeOf((*MockDiskScope)(nil).ApplicationGatewayName))
+}
+
+// ControlPlaneOutboundLBName mocks base method.
+func (m *MockDiskScope) ControlPlaneOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ControlPlaneOutboundLBName indicates an expected call of ControlPlaneOutboundLBName.
+func (mr *MockDiskScopeMockRecorder) ControlPlaneOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneOutboundLBName", reflect.TypeOf((*MockDiskScope)(nil).ControlPlaneOutboundLBName))
+}
+
+// NodeOutboundLBName mocks base method.
+func (m *MockDiskScope) NodeOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeOutboundLBName indicates an expected call of NodeOutboundLBName.
+func (mr *MockDiskScopeMockRecorder) NodeOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLBName", reflect.TypeOf((*MockDiskScope)(nil).NodeOutboundLBName))
+}
+
+// AcceleratedNetworking mocks base method.
+func (m *MockDiskScope) AcceleratedNetworking() *bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceleratedNetworking")
+	ret0, _ := ret[0].(*bool)
+	return ret0
+}
+
+// AcceleratedNetworking indicates an expected call of AcceleratedNetworking.
+func (mr *MockDiskScopeMockRecorder) AcceleratedNetworking() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceleratedNetworking", reflect.TypeOf((*MockDiskScope)(nil).AcceleratedNetworking))
+}
+
+// DiskEncryptionSetID mocks base method.
+func (m *MockDiskScope) DiskEncryptionSetID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskEncryptionSetID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DiskEncryptionSetID indicates an expected call of DiskEncryptionSetID.
+func (mr *MockDiskScopeMockRecorder) DiskEncryptionSetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskEncryptionSetID", reflect.TypeOf((*MockDiskScope)(nil).DiskEncryptionSetID))
+}
+
+// DefaultImage mocks base method.
+func (m *MockDiskScope) DefaultImage() *v1alpha3.Image {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultImage")
+	ret0, _ := ret[0].(*v1alpha3.Image)
+	return ret0
+}
+
+// DefaultImage indicates an expected call of DefaultImage.
+func (mr *MockDiskScopeMockRecorder) DefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultImage", reflect.TypeOf((*MockDiskScope)(nil).DefaultImage))
+}
+
+// EnforcedTags mocks base method.
+func (m *MockDiskScope) EnforcedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnforcedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// EnforcedTags indicates an expected call of EnforcedTags.
+func (mr *MockDiskScopeMockRecorder) EnforcedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnforcedTags", reflect.TypeOf((*MockDiskScope)(nil).EnforcedTags))
+}
+
 // DiskSpecs mocks base method.
 func (m *MockDiskScope) DiskSpecs() []azure.DiskSpec {
 	m.ctrl.T.Helper()
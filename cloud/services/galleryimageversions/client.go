@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimageversions
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName, version string) (compute.GalleryImageVersion, error)
+	List(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName string) ([]compute.GalleryImageVersion, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	baseURI    string
+	authorizer autorest.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new shared image gallery image versions client. The galleries can be in other resource groups
+// and subscriptions than the ones of the cluster.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		baseURI:    auth.BaseURI(),
+		authorizer: auth.Authorizer(),
+	}
+}
+
+// newGalleryImageVersionsClient creates a new gallery image versions client from subscription ID.
+func newGalleryImageVersionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.GalleryImageVersionsClient {
+	c := compute.NewGalleryImageVersionsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// Get gets the given version of an image of a shared image gallery.
+func (ac *AzureClient) Get(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName, version string) (compute.GalleryImageVersion, error) {
+	return newGalleryImageVersionsClient(subscriptionID, ac.baseURI, ac.authorizer).Get(ctx, resourceGroupName, galleryName, imageName, version, "")
+}
+
+// List returns all the versions of an image of a shared image gallery.
+func (ac *AzureClient) List(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName string) ([]compute.GalleryImageVersion, error) {
+	iter, err := newGalleryImageVersionsClient(subscriptionID, ac.baseURI, ac.authorizer).ListByGalleryImageComplete(ctx, resourceGroupName, galleryName, imageName)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list gallery image versions")
+	}
+
+	var versions []compute.GalleryImageVersion
+	for iter.NotDone() {
+		versions = append(versions, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return versions, errors.Wrap(err, "could not iterate gallery image versions")
+		}
+	}
+
+	return versions, nil
+}
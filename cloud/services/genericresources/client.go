@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresources
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	List(context.Context, string, string) ([]resources.GenericResourceExpanded, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	resources resources.Client
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new resources client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		resources: newResourcesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newResourcesClient creates a new resources client from subscription ID.
+func newResourcesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.Client {
+	c := resources.NewClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// List returns the resources of a resource group matching the filter.
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName, filter string) ([]resources.GenericResourceExpanded, error) {
+	iter, err := ac.resources.ListByResourceGroupComplete(ctx, resourceGroupName, filter, "", nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list resources in resource group %s", resourceGroupName)
+	}
+
+	var list []resources.GenericResourceExpanded
+	for iter.NotDone() {
+		list = append(list, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return list, errors.Wrapf(err, "could not iterate resources in resource group %s", resourceGroupName)
+		}
+	}
+
+	return list, nil
+}
@@ -28,6 +28,7 @@ import (
 type Client interface {
 	Get(context.Context, string) (resources.Group, error)
 	CreateOrUpdate(context.Context, string, resources.Group) (resources.Group, error)
+	UpdateTags(context.Context, string, map[string]*string) error
 	Delete(context.Context, string) error
 }
 
@@ -47,8 +48,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newGroupsClient creates a new groups client from subscription ID.
 func newGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.GroupsClient {
 	groupsClient := resources.NewGroupsClientWithBaseURI(baseURI, subscriptionID)
-	groupsClient.Authorizer = authorizer
-	groupsClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&groupsClient.Client, authorizer)
 	return groupsClient
 }
 
@@ -62,6 +62,12 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, name string, group re
 	return ac.groups.CreateOrUpdate(ctx, name, group)
 }
 
+// UpdateTags replaces the tags of a resource group.
+func (ac *AzureClient) UpdateTags(ctx context.Context, name string, tags map[string]*string) error {
+	_, err := ac.groups.Update(ctx, name, resources.GroupPatchable{Tags: tags})
+	return err
+}
+
 // Delete deletes a resource group. When you delete a resource group, all of its resources are also deleted.
 func (ac *AzureClient) Delete(ctx context.Context, name string) error {
 	future, err := ac.groups.Delete(ctx, name)
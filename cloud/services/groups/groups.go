@@ -18,6 +18,7 @@ package groups
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -29,23 +30,43 @@ import (
 
 // Reconcile gets/creates/updates a resource group.
 func (s *Service) Reconcile(ctx context.Context) error {
-	if _, err := s.Client.Get(ctx, s.Scope.ResourceGroup()); err == nil {
+	if err := s.checkNetworkResourceGroup(ctx); err != nil {
+		return err
+	}
+
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(s.Scope.ResourceGroup()),
+		Role:        to.StringPtr(infrav1.CommonRole),
+		Additional:  s.Scope.AdditionalTags(),
+	})
+	existingGroup, err := s.Client.Get(ctx, s.Scope.ResourceGroup())
+	if err == nil {
 		// resource group already exists, skip creation
+		if !converters.MapToTags(existingGroup.Tags).HasOwned(s.Scope.ClusterName()) {
+			// the resource group was not created by the provider, so it must not be deleted with the cluster
+			s.Scope.SetResourceGroupID(to.String(existingGroup.ID))
+			return nil
+		}
+		if updatedTags, changed := converters.UpdateTags(existingGroup.Tags, tags, s.Scope.LastAppliedTags()); changed {
+			s.Scope.V(2).Info("updating resource group tags", "resource group", s.Scope.ResourceGroup())
+			if err := s.Client.UpdateTags(ctx, s.Scope.ResourceGroup(), updatedTags); err != nil {
+				return errors.Wrapf(err, "failed to update tags of resource group %s", s.Scope.ResourceGroup())
+			}
+		}
 		return nil
 	}
+	if !s.Scope.IsResourceGroupManaged() {
+		return errors.Wrapf(err, "failed to get pre-existing resource group %s", s.Scope.ResourceGroup())
+	}
 	s.Scope.V(2).Info("creating resource group", "resource group", s.Scope.ResourceGroup())
 	group := resources.Group{
 		Location: to.StringPtr(s.Scope.Location()),
-		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-			ClusterName: s.Scope.ClusterName(),
-			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Scope.ResourceGroup()),
-			Role:        to.StringPtr(infrav1.CommonRole),
-			Additional:  s.Scope.AdditionalTags(),
-		})),
+		Tags:     converters.TagsToMap(tags),
 	}
 
-	_, err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), group)
+	_, err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), group)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create resource group %s", s.Scope.ResourceGroup())
 	}
@@ -54,8 +75,29 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// checkNetworkResourceGroup checks that the network resource group exists when it isn't the cluster resource group.
+// It's never created nor deleted by the provider.
+func (s *Service) checkNetworkResourceGroup(ctx context.Context) error {
+	networkGroup := s.Scope.NetworkResourceGroup()
+	if strings.EqualFold(networkGroup, s.Scope.ResourceGroup()) {
+		return nil
+	}
+	if _, err := s.Client.Get(ctx, networkGroup); err != nil {
+		if azure.ResourceNotFound(err) {
+			return errors.Errorf("network resource group %s doesn't exist", networkGroup)
+		}
+		return errors.Wrapf(err, "failed to get network resource group %s", networkGroup)
+	}
+	return nil
+}
+
 // Delete deletes the resource group with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
+	if !s.Scope.IsResourceGroupManaged() {
+		s.Scope.V(4).Info("Skipping deletion of pre-existing resource group", "resource group", s.Scope.ResourceGroup())
+		return nil
+	}
+
 	managed, err := s.isGroupManaged(ctx)
 	if err != nil {
 		return errors.Wrap(err, "could not get resource group management state")
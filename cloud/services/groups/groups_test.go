@@ -31,6 +31,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 )
 
 func TestReconcileGroups(t *testing.T) {
@@ -44,8 +45,94 @@ func TestReconcileGroups(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ResourceGroup().Return("my-rg")
-				m.Get(context.TODO(), "my-rg").Return(resources.Group{}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg").Return(resources.Group{
+					ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg"),
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+						"Name": "my-rg",
+					}),
+				}, nil)
+			},
+		},
+		{
+			name:          "update the tags of an existing resource group",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"env": "prod"})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{"env": "dev", "team": "infra"})
+				m.Get(context.TODO(), "my-rg").Return(resources.Group{
+					ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg"),
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+						"Name":  "my-rg",
+						"env":   "dev",
+						"team":  "infra",
+						"owner": "ops",
+					}),
+				}, nil)
+				m.UpdateTags(context.TODO(), "my-rg", converters.TagsToMap(infrav1.Tags{
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+					"Name":  "my-rg",
+					"env":   "prod",
+					"owner": "ops",
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "failed to update the tags of an existing resource group",
+			expectedError: "failed to update tags of resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"env": "prod"})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg").Return(resources.Group{
+					ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg"),
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+					}),
+				}, nil)
+				m.UpdateTags(context.TODO(), "my-rg", gomock.Any()).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "pre-existing resource group not owned by the cluster",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg").Return(resources.Group{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg")}, nil)
+				s.SetResourceGroupID("/subscriptions/123/resourceGroups/my-rg")
+			},
+		},
+		{
+			name:          "pre-existing resource group does not exist",
+			expectedError: "failed to get pre-existing resource group my-rg: #: Not found: StatusCode=404",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.IsResourceGroupManaged().Return(false)
+				m.Get(context.TODO(), "my-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
 		},
 		{
@@ -54,9 +141,11 @@ func TestReconcileGroups(t *testing.T) {
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.IsResourceGroupManaged().Return(true)
 				m.Get(context.TODO(), "my-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(context.TODO(), "my-rg", gomock.AssignableToTypeOf(resources.Group{})).Return(resources.Group{}, nil)
 			},
@@ -67,13 +156,45 @@ func TestReconcileGroups(t *testing.T) {
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.IsResourceGroupManaged().Return(true)
 				m.Get(context.TODO(), "my-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(context.TODO(), "my-rg", gomock.AssignableToTypeOf(resources.Group{})).Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "network resource group does not exist",
+			expectedError: "network resource group my-network-rg doesn't exist",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-network-rg")
+				m.Get(context.TODO(), "my-network-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "network resource group exists",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-network-rg")
+				s.ClusterName().AnyTimes().Return("fake-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-network-rg").Return(resources.Group{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-network-rg")}, nil)
+				m.Get(context.TODO(), "my-rg").Return(resources.Group{
+					ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg"),
+					Tags: converters.TagsToMap(infrav1.Tags{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_fake-cluster": "owned",
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+						"Name": "my-rg",
+					}),
+				}, nil)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -116,15 +237,26 @@ func TestDeleteGroups(t *testing.T) {
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsResourceGroupManaged().Return(true)
 				m.Get(context.TODO(), "my-rg").Return(resources.Group{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "skip deletion of pre-existing resource group",
+			expectedError: "",
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsResourceGroupManaged().Return(false)
+			},
+		},
 		{
 			name:          "skip deletion in unmanaged mode",
 			expectedError: "",
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsResourceGroupManaged().Return(true)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				m.Get(context.TODO(), "my-rg").Return(resources.Group{}, nil)
 			},
@@ -135,6 +267,7 @@ func TestDeleteGroups(t *testing.T) {
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsResourceGroupManaged().Return(true)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				gomock.InOrder(
 					m.Get(context.TODO(), "my-rg").Return(resources.Group{
@@ -154,6 +287,7 @@ func TestDeleteGroups(t *testing.T) {
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsResourceGroupManaged().Return(true)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				gomock.InOrder(
 					m.Get(context.TODO(), "my-rg").Return(resources.Group{
@@ -173,6 +307,7 @@ func TestDeleteGroups(t *testing.T) {
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.IsResourceGroupManaged().Return(true)
 				s.ClusterName().AnyTimes().Return("fake-cluster")
 				gomock.InOrder(
 					m.Get(context.TODO(), "my-rg").Return(resources.Group{
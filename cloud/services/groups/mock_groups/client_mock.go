@@ -80,6 +80,20 @@ func (mr *MockClientMockRecorder) CreateOrUpdate(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockClient)(nil).CreateOrUpdate), arg0, arg1, arg2)
 }
 
+// UpdateTags mocks base method.
+func (m *MockClient) UpdateTags(arg0 context.Context, arg1 string, arg2 map[string]*string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTags indicates an expected call of UpdateTags.
+func (mr *MockClientMockRecorder) UpdateTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTags", reflect.TypeOf((*MockClient)(nil).UpdateTags), arg0, arg1, arg2)
+}
+
 // Delete mocks base method.
 func (m *MockClient) Delete(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
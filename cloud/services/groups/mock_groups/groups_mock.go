@@ -201,6 +201,48 @@ func (mr *MockGroupScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockGroupScope)(nil).ResourceGroup))
 }
 
+// IsResourceGroupManaged mocks base method.
+func (m *MockGroupScope) IsResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsResourceGroupManaged indicates an expected call of IsResourceGroupManaged.
+func (mr *MockGroupScopeMockRecorder) IsResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsResourceGroupManaged", reflect.TypeOf((*MockGroupScope)(nil).IsResourceGroupManaged))
+}
+
+// NetworkResourceGroup mocks base method.
+func (m *MockGroupScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockGroupScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockGroupScope)(nil).NetworkResourceGroup))
+}
+
+// IsNetworkResourceGroupManaged mocks base method.
+func (m *MockGroupScope) IsNetworkResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNetworkResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNetworkResourceGroupManaged indicates an expected call of IsNetworkResourceGroupManaged.
+func (mr *MockGroupScopeMockRecorder) IsNetworkResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNetworkResourceGroupManaged", reflect.TypeOf((*MockGroupScope)(nil).IsNetworkResourceGroupManaged))
+}
+
 // ClusterName mocks base method.
 func (m *MockGroupScope) ClusterName() string {
 	m.ctrl.T.Helper()
@@ -243,6 +285,20 @@ func (mr *MockGroupScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockGroupScope)(nil).AdditionalTags))
 }
 
+// LastAppliedTags mocks base method.
+func (m *MockGroupScope) LastAppliedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastAppliedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// LastAppliedTags indicates an expected call of LastAppliedTags.
+func (mr *MockGroupScopeMockRecorder) LastAppliedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAppliedTags", reflect.TypeOf((*MockGroupScope)(nil).LastAppliedTags))
+}
+
 // Vnet mocks base method.
 func (m *MockGroupScope) Vnet() *v1alpha3.VnetSpec {
 	m.ctrl.T.Helper()
@@ -257,6 +313,20 @@ func (mr *MockGroupScopeMockRecorder) Vnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockGroupScope)(nil).Vnet))
 }
 
+// Subnets mocks base method.
+func (m *MockGroupScope) Subnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// Subnets indicates an expected call of Subnets.
+func (mr *MockGroupScopeMockRecorder) Subnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subnets", reflect.TypeOf((*MockGroupScope)(nil).Subnets))
+}
+
 // NodeSubnet mocks base method.
 func (m *MockGroupScope) NodeSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -271,6 +341,20 @@ func (mr *MockGroupScopeMockRecorder) NodeSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnet", reflect.TypeOf((*MockGroupScope)(nil).NodeSubnet))
 }
 
+// NodeSubnets mocks base method.
+func (m *MockGroupScope) NodeSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// NodeSubnets indicates an expected call of NodeSubnets.
+func (mr *MockGroupScopeMockRecorder) NodeSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnets", reflect.TypeOf((*MockGroupScope)(nil).NodeSubnets))
+}
+
 // ControlPlaneSubnet mocks base method.
 func (m *MockGroupScope) ControlPlaneSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -284,3 +368,169 @@ func (mr *MockGroupScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockGroupScope)(nil).ControlPlaneSubnet))
 }
+
+// ControlPlaneSubnets mocks base method.
+func (m *MockGroupScope) ControlPlaneSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// ControlPlaneSubnets indicates an expected call of ControlPlaneSubnets.
+func (mr *MockGroupScopeMockRecorder) ControlPlaneSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnets", reflect.TypeOf((*MockGroupScope)(nil).ControlPlaneSubnets))
+}
+
+// ControlPlaneSubnetForZone mocks base method.
+func (m *MockGroupScope) ControlPlaneSubnetForZone(zone string) *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnetForZone", zone)
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnetForZone indicates an expected call of ControlPlaneSubnetForZone.
+func (mr *MockGroupScopeMockRecorder) ControlPlaneSubnetForZone(zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnetForZone", reflect.TypeOf((*MockGroupScope)(nil).ControlPlaneSubnetForZone), zone)
+}
+
+// IsAPIServerPrivate mocks base method.
+func (m *MockGroupScope) IsAPIServerPrivate() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAPIServerPrivate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAPIServerPrivate indicates an expected call of IsAPIServerPrivate.
+func (mr *MockGroupScopeMockRecorder) IsAPIServerPrivate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAPIServerPrivate", reflect.TypeOf((*MockGroupScope)(nil).IsAPIServerPrivate))
+}
+
+// InternalLBInboundNatRules mocks base method.
+func (m *MockGroupScope) InternalLBInboundNatRules() []v1alpha3.InboundNatRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InternalLBInboundNatRules")
+	ret0, _ := ret[0].([]v1alpha3.InboundNatRule)
+	return ret0
+}
+
+// InternalLBInboundNatRules indicates an expected call of InternalLBInboundNatRules.
+func (mr *MockGroupScopeMockRecorder) InternalLBInboundNatRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalLBInboundNatRules", reflect.TypeOf((*MockGroupScope)(nil).InternalLBInboundNatRules))
+}
+
+// ApplicationGatewayName mocks base method.
+func (m *MockGroupScope) ApplicationGatewayName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationGatewayName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ApplicationGatewayName indicates an expected call of ApplicationGatewayName.
+func (mr *MockGroupScopeMockRecorder) ApplicationGatewayName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationGatewayName", reflect.TypeOf((*MockGroupScope)(nil).ApplicationGatewayName))
+}
+
+// ControlPlaneOutboundLBName mocks base method.
+func (m *MockGroupScope) ControlPlaneOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ControlPlaneOutboundLBName indicates an expected call of ControlPlaneOutboundLBName.
+func (mr *MockGroupScopeMockRecorder) ControlPlaneOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneOutboundLBName", reflect.TypeOf((*MockGroupScope)(nil).ControlPlaneOutboundLBName))
+}
+
+// NodeOutboundLBName mocks base method.
+func (m *MockGroupScope) NodeOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeOutboundLBName indicates an expected call of NodeOutboundLBName.
+func (mr *MockGroupScopeMockRecorder) NodeOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLBName", reflect.TypeOf((*MockGroupScope)(nil).NodeOutboundLBName))
+}
+
+// AcceleratedNetworking mocks base method.
+func (m *MockGroupScope) AcceleratedNetworking() *bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceleratedNetworking")
+	ret0, _ := ret[0].(*bool)
+	return ret0
+}
+
+// AcceleratedNetworking indicates an expected call of AcceleratedNetworking.
+func (mr *MockGroupScopeMockRecorder) AcceleratedNetworking() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceleratedNetworking", reflect.TypeOf((*MockGroupScope)(nil).AcceleratedNetworking))
+}
+
+// DiskEncryptionSetID mocks base method.
+func (m *MockGroupScope) DiskEncryptionSetID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskEncryptionSetID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DiskEncryptionSetID indicates an expected call of DiskEncryptionSetID.
+func (mr *MockGroupScopeMockRecorder) DiskEncryptionSetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskEncryptionSetID", reflect.TypeOf((*MockGroupScope)(nil).DiskEncryptionSetID))
+}
+
+// DefaultImage mocks base method.
+func (m *MockGroupScope) DefaultImage() *v1alpha3.Image {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultImage")
+	ret0, _ := ret[0].(*v1alpha3.Image)
+	return ret0
+}
+
+// DefaultImage indicates an expected call of DefaultImage.
+func (mr *MockGroupScopeMockRecorder) DefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultImage", reflect.TypeOf((*MockGroupScope)(nil).DefaultImage))
+}
+
+// EnforcedTags mocks base method.
+func (m *MockGroupScope) EnforcedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnforcedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// EnforcedTags indicates an expected call of EnforcedTags.
+func (mr *MockGroupScopeMockRecorder) EnforcedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnforcedTags", reflect.TypeOf((*MockGroupScope)(nil).EnforcedTags))
+}
+
+// SetResourceGroupID mocks base method.
+func (m *MockGroupScope) SetResourceGroupID(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetResourceGroupID", arg0)
+}
+
+// SetResourceGroupID indicates an expected call of SetResourceGroupID.
+func (mr *MockGroupScopeMockRecorder) SetResourceGroupID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResourceGroupID", reflect.TypeOf((*MockGroupScope)(nil).SetResourceGroupID), arg0)
+}
@@ -31,6 +31,7 @@ type Service struct {
 type GroupScope interface {
 	logr.Logger
 	azure.ClusterDescriber
+	SetResourceGroupID(string)
 }
 
 // NewService creates a new service.
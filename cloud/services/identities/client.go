@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identities
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
+	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string) (msi.Identity, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	baseURI    string
+	authorizer autorest.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new user-assigned identities client. The identities can be in other
+// subscriptions than the one of the cluster.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		baseURI:    auth.BaseURI(),
+		authorizer: auth.Authorizer(),
+	}
+}
+
+// newUserAssignedIdentitiesClient creates a new user-assigned identities client from subscription ID.
+func newUserAssignedIdentitiesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) msi.UserAssignedIdentitiesClient {
+	c := msi.NewUserAssignedIdentitiesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// Get gets the user-assigned identity with the given resource ID.
+func (ac *AzureClient) Get(ctx context.Context, resourceID string) (msi.Identity, error) {
+	resource, err := autorestazure.ParseResourceID(resourceID)
+	if err != nil {
+		return msi.Identity{}, errors.Wrapf(err, "invalid user-assigned identity ID %s", resourceID)
+	}
+	if !strings.EqualFold(resource.Provider, "Microsoft.ManagedIdentity") || !strings.EqualFold(resource.ResourceType, "userAssignedIdentities") {
+		return msi.Identity{}, errors.Errorf("invalid user-assigned identity ID %s: not a Microsoft.ManagedIdentity/userAssignedIdentities resource", resourceID)
+	}
+	return newUserAssignedIdentitiesClient(resource.SubscriptionID, ac.baseURI, ac.authorizer).Get(ctx, resource.ResourceGroup, resource.ResourceName)
+}
@@ -47,8 +47,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newLoadbalancersClient creates a new inbound NAT rules client from subscription ID.
 func newInboundNatRulesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.InboundNatRulesClient {
 	inboundNatRulesClient := network.NewInboundNatRulesClientWithBaseURI(baseURI, subscriptionID)
-	inboundNatRulesClient.Authorizer = authorizer
-	inboundNatRulesClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&inboundNatRulesClient.Client, authorizer)
 	return inboundNatRulesClient
 }
 
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inboundnatrules
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Reconcile gets/creates the inbound NAT rules of a machine.
+// The SSH frontend port of a new rule is 22, or the first port from 2201 to 2219 not used by the other rules of the load balancer.
+// The frontend port of a new rule of the internal load balancer is the first port of its range not used by the other rules.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, natSpec := range s.Scope.InboundNatSpecs() {
+		s.Scope.V(2).Info("creating inbound NAT rule", "NAT rule", natSpec.Name)
+		lb, err := s.LoadBalancersClient.Get(ctx, s.Scope.NetworkResourceGroup(), natSpec.LoadBalancerName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get load balancer %s", natSpec.LoadBalancerName)
+		}
+		if lb.LoadBalancerPropertiesFormat == nil || lb.FrontendIPConfigurations == nil || lb.InboundNatRules == nil {
+			return errors.Errorf("could not get existing inbound NAT rules from load balancer %s properties", natSpec.LoadBalancerName)
+		}
+
+		ports := make(map[int32]struct{})
+		exists := false
+		for _, v := range *lb.InboundNatRules {
+			if to.String(v.Name) == natSpec.Name {
+				exists = true
+				break
+			}
+			if v.InboundNatRulePropertiesFormat != nil && v.FrontendPort != nil {
+				ports[*v.FrontendPort] = struct{}{}
+			}
+		}
+		if exists {
+			// Inbound NAT Rule already exists, nothing to do here.
+			s.Scope.V(2).Info("NAT rule already exists", "NAT rule", natSpec.Name)
+			continue
+		}
+
+		backendPort := int32(22)
+		var frontendPort int32
+		if natSpec.BackendPort != 0 {
+			backendPort = natSpec.BackendPort
+			frontendPort, err = getAvailableFrontendPort(ports, natSpec.FrontendPortRangeStart, natSpec.FrontendPortRangeEnd)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find available frontend port from %d to %d for NAT rule %s in load balancer %s",
+					natSpec.FrontendPortRangeStart, natSpec.FrontendPortRangeEnd, natSpec.Name, natSpec.LoadBalancerName)
+			}
+		} else {
+			frontendPort, err = getAvailableSSHFrontendPort(ports)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find available SSH frontend port for NAT rule %s in load balancer %s", natSpec.Name, natSpec.LoadBalancerName)
+			}
+		}
+		rule := network.InboundNatRule{
+			Name: to.StringPtr(natSpec.Name),
+			InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+				BackendPort:          to.Int32Ptr(backendPort),
+				EnableFloatingIP:     to.BoolPtr(false),
+				IdleTimeoutInMinutes: to.Int32Ptr(4),
+				FrontendIPConfiguration: &network.SubResource{
+					ID: (*lb.FrontendIPConfigurations)[0].ID,
+				},
+				Protocol:     network.TransportProtocolTCP,
+				FrontendPort: to.Int32Ptr(frontendPort),
+			},
+		}
+		s.Scope.V(3).Info("creating NAT rule", "NAT rule", natSpec.Name, "port", frontendPort)
+		if err := s.Client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), natSpec.LoadBalancerName, natSpec.Name, rule); err != nil {
+			return errors.Wrapf(err, "failed to create inbound NAT rule %s in load balancer %s", natSpec.Name, natSpec.LoadBalancerName)
+		}
+		s.Scope.V(2).Info("successfully created inbound NAT rule", "NAT rule", natSpec.Name)
+	}
+	return nil
+}
+
+// Delete deletes the inbound NAT rules of a machine.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, natSpec := range s.Scope.InboundNatSpecs() {
+		s.Scope.V(2).Info("deleting inbound NAT rule", "NAT rule", natSpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), natSpec.LoadBalancerName, natSpec.Name)
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete inbound NAT rule %s in load balancer %s", natSpec.Name, natSpec.LoadBalancerName)
+		}
+		s.Scope.V(2).Info("successfully deleted inbound NAT rule", "NAT rule", natSpec.Name)
+	}
+	return nil
+}
+
+// getAvailableSSHFrontendPort returns 22 when no other rule uses it, then the first free port from 2201 to 2219.
+func getAvailableSSHFrontendPort(ports map[int32]struct{}) (int32, error) {
+	if _, ok := ports[22]; !ok {
+		return 22, nil
+	}
+	return getAvailableFrontendPort(ports, 2201, 2219)
+}
+
+// getAvailableFrontendPort returns the first port from start to end which no other rule uses.
+func getAvailableFrontendPort(ports map[int32]struct{}, start, end int32) (int32, error) {
+	for i := start; i <= end; i++ {
+		if _, ok := ports[i]; !ok {
+			return i, nil
+		}
+	}
+	return 0, errors.New("all the ports are used by other rules")
+}
@@ -0,0 +1,422 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inboundnatrules
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules/mock_inboundnatrules"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/loadbalancers/mock_loadbalancers"
+)
+
+func TestReconcileInboundNATRule(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+			m *mock_inboundnatrules.MockClientMockRecorder,
+			mLoadBalancer *mock_loadbalancers.MockClientMockRecorder)
+	}{
+		{
+			name:          "NAT rule successfully created with the SSH port",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakePublicLoadBalancer(), nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "azure-test1", network.InboundNatRule{
+						Name: to.StringPtr("azure-test1"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort:         to.Int32Ptr(22),
+							BackendPort:          to.Int32Ptr(22),
+							EnableFloatingIP:     to.BoolPtr(false),
+							IdleTimeoutInMinutes: to.Int32Ptr(4),
+							FrontendIPConfiguration: &network.SubResource{
+								ID: to.StringPtr("frontend-ip-config-id"),
+							},
+							Protocol: network.TransportProtocolTCP,
+						},
+					}))
+			},
+		},
+		{
+			name:          "NAT rule successfully created with the next available port",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				lb := getFakePublicLoadBalancer()
+				lb.InboundNatRules = &[]network.InboundNatRule{
+					{
+						Name: to.StringPtr("other-machine-nat-rule"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort: to.Int32Ptr(22),
+						},
+					},
+					{
+						Name: to.StringPtr("other-machine-nat-rule-2"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort: to.Int32Ptr(2201),
+						},
+					},
+				}
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(lb, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "azure-test1", network.InboundNatRule{
+						Name: to.StringPtr("azure-test1"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort:         to.Int32Ptr(2202),
+							BackendPort:          to.Int32Ptr(22),
+							EnableFloatingIP:     to.BoolPtr(false),
+							IdleTimeoutInMinutes: to.Int32Ptr(4),
+							FrontendIPConfiguration: &network.SubResource{
+								ID: to.StringPtr("frontend-ip-config-id"),
+							},
+							Protocol: network.TransportProtocolTCP,
+						},
+					}))
+			},
+		},
+		{
+			name:          "NAT rule already exists",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				lb := getFakePublicLoadBalancer()
+				lb.InboundNatRules = &[]network.InboundNatRule{
+					{
+						Name: to.StringPtr("azure-test1"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort: to.Int32Ptr(22),
+						},
+					},
+				}
+				mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(lb, nil)
+			},
+		},
+		{
+			name:          "no SSH port available",
+			expectedError: "failed to find available SSH frontend port for NAT rule azure-test1 in load balancer my-public-lb: all the ports are used by other rules",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				lb := getFakePublicLoadBalancer()
+				rules := []network.InboundNatRule{
+					{
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort: to.Int32Ptr(22),
+						},
+					},
+				}
+				for port := int32(2201); port < 2220; port++ {
+					rules = append(rules, network.InboundNatRule{
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort: to.Int32Ptr(port),
+						},
+					})
+				}
+				lb.InboundNatRules = &rules
+				mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(lb, nil)
+			},
+		},
+		{
+			name:          "internal LB NAT rule created with the next available port of its range",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:                   "azure-test1-etcd",
+						LoadBalancerName:       "my-internal-lb",
+						FrontendPortRangeStart: 12379,
+						FrontendPortRangeEnd:   12381,
+						BackendPort:            2379,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				lb := getFakePublicLoadBalancer()
+				lb.InboundNatRules = &[]network.InboundNatRule{
+					{
+						Name: to.StringPtr("other-machine-etcd"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort: to.Int32Ptr(12379),
+						},
+					},
+				}
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-internal-lb").Return(lb, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-internal-lb", "azure-test1-etcd", network.InboundNatRule{
+						Name: to.StringPtr("azure-test1-etcd"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort:         to.Int32Ptr(12380),
+							BackendPort:          to.Int32Ptr(2379),
+							EnableFloatingIP:     to.BoolPtr(false),
+							IdleTimeoutInMinutes: to.Int32Ptr(4),
+							FrontendIPConfiguration: &network.SubResource{
+								ID: to.StringPtr("frontend-ip-config-id"),
+							},
+							Protocol: network.TransportProtocolTCP,
+						},
+					}))
+			},
+		},
+		{
+			name:          "no port of the range of an internal LB NAT rule available",
+			expectedError: "failed to find available frontend port from 12379 to 12379 for NAT rule azure-test1-etcd in load balancer my-internal-lb: all the ports are used by other rules",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:                   "azure-test1-etcd",
+						LoadBalancerName:       "my-internal-lb",
+						FrontendPortRangeStart: 12379,
+						FrontendPortRangeEnd:   12379,
+						BackendPort:            2379,
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				lb := getFakePublicLoadBalancer()
+				lb.InboundNatRules = &[]network.InboundNatRule{
+					{
+						Name: to.StringPtr("other-machine-etcd"),
+						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+							FrontendPort: to.Int32Ptr(12379),
+						},
+					},
+				}
+				mLoadBalancer.Get(context.TODO(), "my-rg", "my-internal-lb").Return(lb, nil)
+			},
+		},
+		{
+			name:          "fail to get load balancer",
+			expectedError: "failed to get load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").
+					Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "fail to create NAT rule",
+			expectedError: "failed to create inbound NAT rule azure-test1 in load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				gomock.InOrder(
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakePublicLoadBalancer(), nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "azure-test1", gomock.AssignableToTypeOf(network.InboundNatRule{})).
+						Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_inboundnatrules.NewMockInboundNatScope(mockCtrl)
+			clientMock := mock_inboundnatrules.NewMockClient(mockCtrl)
+			loadBalancerMock := mock_loadbalancers.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), loadBalancerMock.EXPECT())
+
+			s := &Service{
+				Scope:               scopeMock,
+				Client:              clientMock,
+				LoadBalancersClient: loadBalancerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteInboundNATRule(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+			m *mock_inboundnatrules.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete an existing NAT rule",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
+			},
+		},
+		{
+			name:          "NAT rule already deleted",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "NAT rule deletion fails",
+			expectedError: "failed to delete inbound NAT rule azure-test1 in load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockClientMockRecorder) {
+				s.InboundNatSpecs().Return([]azure.InboundNatSpec{
+					{
+						Name:             "azure-test1",
+						LoadBalancerName: "my-public-lb",
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_inboundnatrules.NewMockInboundNatScope(mockCtrl)
+			clientMock := mock_inboundnatrules.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func getFakePublicLoadBalancer() network.LoadBalancer {
+	return network.LoadBalancer{
+		Name: to.StringPtr("my-public-lb"),
+		ID:   to.StringPtr("my-public-lb-id"),
+		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+				{
+					ID: to.StringPtr("frontend-ip-config-id"),
+				},
+			},
+			InboundNatRules: &[]network.InboundNatRule{},
+		},
+	}
+}
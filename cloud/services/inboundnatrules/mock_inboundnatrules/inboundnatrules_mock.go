@@ -15,80 +15,525 @@ limitations under the License.
 */
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ../client.go
+// Source: ../service.go
 
 // Package mock_inboundnatrules is a generated GoMock package.
 package mock_inboundnatrules
 
 import (
-	context "context"
-	network "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	autorest "github.com/Azure/go-autorest/autorest"
+	logr "github.com/go-logr/logr"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	v1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
-// MockClient is a mock of Client interface.
-type MockClient struct {
+// MockInboundNatScope is a mock of InboundNatScope interface.
+type MockInboundNatScope struct {
 	ctrl     *gomock.Controller
-	recorder *MockClientMockRecorder
+	recorder *MockInboundNatScopeMockRecorder
 }
 
-// MockClientMockRecorder is the mock recorder for MockClient.
-type MockClientMockRecorder struct {
-	mock *MockClient
+// MockInboundNatScopeMockRecorder is the mock recorder for MockInboundNatScope.
+type MockInboundNatScopeMockRecorder struct {
+	mock *MockInboundNatScope
 }
 
-// NewMockClient creates a new mock instance.
-func NewMockClient(ctrl *gomock.Controller) *MockClient {
-	mock := &MockClient{ctrl: ctrl}
-	mock.recorder = &MockClientMockRecorder{mock}
+// NewMockInboundNatScope creates a new mock instance.
+func NewMockInboundNatScope(ctrl *gomock.Controller) *MockInboundNatScope {
+	mock := &MockInboundNatScope{ctrl: ctrl}
+	mock.recorder = &MockInboundNatScopeMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockClient) EXPECT() *MockClientMockRecorder {
+func (m *MockInboundNatScope) EXPECT() *MockInboundNatScopeMockRecorder {
 	return m.recorder
 }
 
-// Get mocks base method.
-func (m *MockClient) Get(arg0 context.Context, arg1, arg2, arg3 string) (network.InboundNatRule, error) {
+// Info mocks base method.
+func (m *MockInboundNatScope) Info(msg string, keysAndValues ...interface{}) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(network.InboundNatRule)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	varargs := []interface{}{msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockClientMockRecorder) Get(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// Info indicates an expected call of Info.
+func (mr *MockInboundNatScopeMockRecorder) Info(msg interface{}, keysAndValues ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0, arg1, arg2, arg3)
+	varargs := append([]interface{}{msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockInboundNatScope)(nil).Info), varargs...)
 }
 
-// CreateOrUpdate mocks base method.
-func (m *MockClient) CreateOrUpdate(arg0 context.Context, arg1, arg2, arg3 string, arg4 network.InboundNatRule) error {
+// Enabled mocks base method.
+func (m *MockInboundNatScope) Enabled() bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdate", arg0, arg1, arg2, arg3, arg4)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
-// CreateOrUpdate indicates an expected call of CreateOrUpdate.
-func (mr *MockClientMockRecorder) CreateOrUpdate(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+// Enabled indicates an expected call of Enabled.
+func (mr *MockInboundNatScopeMockRecorder) Enabled() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockClient)(nil).CreateOrUpdate), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockInboundNatScope)(nil).Enabled))
 }
 
-// Delete mocks base method.
-func (m *MockClient) Delete(arg0 context.Context, arg1, arg2, arg3 string) error {
+// Error mocks base method.
+func (m *MockInboundNatScope) Error(err error, msg string, keysAndValues ...interface{}) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(error)
+	varargs := []interface{}{err, msg}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockInboundNatScopeMockRecorder) Error(err, msg interface{}, keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{err, msg}, keysAndValues...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockInboundNatScope)(nil).Error), varargs...)
+}
+
+// V mocks base method.
+func (m *MockInboundNatScope) V(level int) logr.InfoLogger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V", level)
+	ret0, _ := ret[0].(logr.InfoLogger)
+	return ret0
+}
+
+// V indicates an expected call of V.
+func (mr *MockInboundNatScopeMockRecorder) V(level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V", reflect.TypeOf((*MockInboundNatScope)(nil).V), level)
+}
+
+// WithValues mocks base method.
+func (m *MockInboundNatScope) WithValues(keysAndValues ...interface{}) logr.Logger {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithValues", varargs...)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithValues indicates an expected call of WithValues.
+func (mr *MockInboundNatScopeMockRecorder) WithValues(keysAndValues ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithValues", reflect.TypeOf((*MockInboundNatScope)(nil).WithValues), keysAndValues...)
+}
+
+// WithName mocks base method.
+func (m *MockInboundNatScope) WithName(name string) logr.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithName", name)
+	ret0, _ := ret[0].(logr.Logger)
+	return ret0
+}
+
+// WithName indicates an expected call of WithName.
+func (mr *MockInboundNatScopeMockRecorder) WithName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithName", reflect.TypeOf((*MockInboundNatScope)(nil).WithName), name)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockInboundNatScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockInboundNatScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockInboundNatScope)(nil).SubscriptionID))
+}
+
+// BaseURI mocks base method.
+func (m *MockInboundNatScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockInboundNatScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockInboundNatScope)(nil).BaseURI))
+}
+
+// Authorizer mocks base method.
+func (m *MockInboundNatScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockInboundNatScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockInboundNatScope)(nil).Authorizer))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockInboundNatScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockInboundNatScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockInboundNatScope)(nil).ResourceGroup))
+}
+
+// IsResourceGroupManaged mocks base method.
+func (m *MockInboundNatScope) IsResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsResourceGroupManaged indicates an expected call of IsResourceGroupManaged.
+func (mr *MockInboundNatScopeMockRecorder) IsResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsResourceGroupManaged", reflect.TypeOf((*MockInboundNatScope)(nil).IsResourceGroupManaged))
+}
+
+// NetworkResourceGroup mocks base method.
+func (m *MockInboundNatScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockInboundNatScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockInboundNatScope)(nil).NetworkResourceGroup))
+}
+
+// IsNetworkResourceGroupManaged mocks base method.
+func (m *MockInboundNatScope) IsNetworkResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNetworkResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNetworkResourceGroupManaged indicates an expected call of IsNetworkResourceGroupManaged.
+func (mr *MockInboundNatScopeMockRecorder) IsNetworkResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNetworkResourceGroupManaged", reflect.TypeOf((*MockInboundNatScope)(nil).IsNetworkResourceGroupManaged))
+}
+
+// ClusterName mocks base method.
+func (m *MockInboundNatScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockInboundNatScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockInboundNatScope)(nil).ClusterName))
+}
+
+// Location mocks base method.
+func (m *MockInboundNatScope) Location() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockInboundNatScopeMockRecorder) Location() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockInboundNatScope)(nil).Location))
+}
+
+// AdditionalTags mocks base method.
+func (m *MockInboundNatScope) AdditionalTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdditionalTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// AdditionalTags indicates an expected call of AdditionalTags.
+func (mr *MockInboundNatScopeMockRecorder) AdditionalTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockInboundNatScope)(nil).AdditionalTags))
+}
+
+// LastAppliedTags mocks base method.
+func (m *MockInboundNatScope) LastAppliedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastAppliedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// LastAppliedTags indicates an expected call of LastAppliedTags.
+func (mr *MockInboundNatScopeMockRecorder) LastAppliedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAppliedTags", reflect.TypeOf((*MockInboundNatScope)(nil).LastAppliedTags))
+}
+
+// Vnet mocks base method.
+func (m *MockInboundNatScope) Vnet() *v1alpha3.VnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vnet")
+	ret0, _ := ret[0].(*v1alpha3.VnetSpec)
+	return ret0
+}
+
+// Vnet indicates an expected call of Vnet.
+func (mr *MockInboundNatScopeMockRecorder) Vnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockInboundNatScope)(nil).Vnet))
+}
+
+// Subnets mocks base method.
+func (m *MockInboundNatScope) Subnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// Subnets indicates an expected call of Subnets.
+func (mr *MockInboundNatScopeMockRecorder) Subnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subnets", reflect.TypeOf((*MockInboundNatScope)(nil).Subnets))
+}
+
+// NodeSubnet mocks base method.
+func (m *MockInboundNatScope) NodeSubnet() *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnet")
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// NodeSubnet indicates an expected call of NodeSubnet.
+func (mr *MockInboundNatScopeMockRecorder) NodeSubnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnet", reflect.TypeOf((*MockInboundNatScope)(nil).NodeSubnet))
+}
+
+// NodeSubnets mocks base method.
+func (m *MockInboundNatScope) NodeSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// NodeSubnets indicates an expected call of NodeSubnets.
+func (mr *MockInboundNatScopeMockRecorder) NodeSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnets", reflect.TypeOf((*MockInboundNatScope)(nil).NodeSubnets))
+}
+
+// ControlPlaneSubnet mocks base method.
+func (m *MockInboundNatScope) ControlPlaneSubnet() *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnet")
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnet indicates an expected call of ControlPlaneSubnet.
+func (mr *MockInboundNatScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockInboundNatScope)(nil).ControlPlaneSubnet))
+}
+
+// ControlPlaneSubnets mocks base method.
+func (m *MockInboundNatScope) ControlPlaneSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// ControlPlaneSubnets indicates an expected call of ControlPlaneSubnets.
+func (mr *MockInboundNatScopeMockRecorder) ControlPlaneSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnets", reflect.TypeOf((*MockInboundNatScope)(nil).ControlPlaneSubnets))
+}
+
+// ControlPlaneSubnetForZone mocks base method.
+func (m *MockInboundNatScope) ControlPlaneSubnetForZone(zone string) *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnetForZone", zone)
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnetForZone indicates an expected call of ControlPlaneSubnetForZone.
+func (mr *MockInboundNatScopeMockRecorder) ControlPlaneSubnetForZone(zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnetForZone", reflect.TypeOf((*MockInboundNatScope)(nil).ControlPlaneSubnetForZone), zone)
+}
+
+// IsAPIServerPrivate mocks base method.
+func (m *MockInboundNatScope) IsAPIServerPrivate() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAPIServerPrivate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAPIServerPrivate indicates an expected call of IsAPIServerPrivate.
+func (mr *MockInboundNatScopeMockRecorder) IsAPIServerPrivate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAPIServerPrivate", reflect.TypeOf((*MockInboundNatScope)(nil).IsAPIServerPrivate))
+}
+
+// InternalLBInboundNatRules mocks base method.
+func (m *MockInboundNatScope) InternalLBInboundNatRules() []v1alpha3.InboundNatRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InternalLBInboundNatRules")
+	ret0, _ := ret[0].([]v1alpha3.InboundNatRule)
+	return ret0
+}
+
+// InternalLBInboundNatRules indicates an expected call of InternalLBInboundNatRules.
+func (mr *MockInboundNatScopeMockRecorder) InternalLBInboundNatRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalLBInboundNatRules", reflect.TypeOf((*MockInboundNatScope)(nil).InternalLBInboundNatRules))
+}
+
+// ApplicationGatewayName mocks base method.
+func (m *MockInboundNatScope) ApplicationGatewayName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationGatewayName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ApplicationGatewayName indicates an expected call of ApplicationGatewayName.
+func (mr *MockInboundNatScopeMockRecorder) ApplicationGatewayName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationGatewayName", reflect.TypeOf((*MockInboundNatScope)(nil).ApplicationGatewayName))
+}
+
+// ControlPlaneOutboundLBName mocks base method.
+func (m *MockInboundNatScope) ControlPlaneOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ControlPlaneOutboundLBName indicates an expected call of ControlPlaneOutboundLBName.
+func (mr *MockInboundNatScopeMockRecorder) ControlPlaneOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneOutboundLBName", reflect.TypeOf((*MockInboundNatScope)(nil).ControlPlaneOutboundLBName))
+}
+
+// NodeOutboundLBName mocks base method.
+func (m *MockInboundNatScope) NodeOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeOutboundLBName indicates an expected call of NodeOutboundLBName.
+func (mr *MockInboundNatScopeMockRecorder) NodeOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLBName", reflect.TypeOf((*MockInboundNatScope)(nil).NodeOutboundLBName))
+}
+
+// AcceleratedNetworking mocks base method.
+func (m *MockInboundNatScope) AcceleratedNetworking() *bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceleratedNetworking")
+	ret0, _ := ret[0].(*bool)
+	return ret0
+}
+
+// AcceleratedNetworking indicates an expected call of AcceleratedNetworking.
+func (mr *MockInboundNatScopeMockRecorder) AcceleratedNetworking() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceleratedNetworking", reflect.TypeOf((*MockInboundNatScope)(nil).AcceleratedNetworking))
+}
+
+// DiskEncryptionSetID mocks base method.
+func (m *MockInboundNatScope) DiskEncryptionSetID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskEncryptionSetID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DiskEncryptionSetID indicates an expected call of DiskEncryptionSetID.
+func (mr *MockInboundNatScopeMockRecorder) DiskEncryptionSetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskEncryptionSetID", reflect.TypeOf((*MockInboundNatScope)(nil).DiskEncryptionSetID))
+}
+
+// DefaultImage mocks base method.
+func (m *MockInboundNatScope) DefaultImage() *v1alpha3.Image {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultImage")
+	ret0, _ := ret[0].(*v1alpha3.Image)
+	return ret0
+}
+
+// DefaultImage indicates an expected call of DefaultImage.
+func (mr *MockInboundNatScopeMockRecorder) DefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultImage", reflect.TypeOf((*MockInboundNatScope)(nil).DefaultImage))
+}
+
+// EnforcedTags mocks base method.
+func (m *MockInboundNatScope) EnforcedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnforcedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// EnforcedTags indicates an expected call of EnforcedTags.
+func (mr *MockInboundNatScopeMockRecorder) EnforcedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnforcedTags", reflect.TypeOf((*MockInboundNatScope)(nil).EnforcedTags))
+}
+
+// InboundNatSpecs mocks base method.
+func (m *MockInboundNatScope) InboundNatSpecs() []azure.InboundNatSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InboundNatSpecs")
+	ret0, _ := ret[0].([]azure.InboundNatSpec)
 	return ret0
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockClientMockRecorder) Delete(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// InboundNatSpecs indicates an expected call of InboundNatSpecs.
+func (mr *MockInboundNatScopeMockRecorder) InboundNatSpecs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InboundNatSpecs", reflect.TypeOf((*MockInboundNatScope)(nil).InboundNatSpecs))
 }
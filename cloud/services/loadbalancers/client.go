@@ -21,13 +21,15 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
 // Client wraps go-sdk
 type Client interface {
 	Get(context.Context, string, string) (network.LoadBalancer, error)
-	CreateOrUpdate(context.Context, string, string, network.LoadBalancer) error
+	CreateOrUpdateAsync(context.Context, string, string, network.LoadBalancer) (azureautorest.Future, error)
+	IsDone(context.Context, azureautorest.Future) (bool, error)
 	Delete(context.Context, string, string) error
 }
 
@@ -47,8 +49,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newLoadbalancersClient creates a new load balancer client from subscription ID.
 func newLoadBalancersClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.LoadBalancersClient {
 	loadBalancersClient := network.NewLoadBalancersClientWithBaseURI(baseURI, subscriptionID)
-	loadBalancersClient.Authorizer = authorizer
-	loadBalancersClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&loadBalancersClient.Client, authorizer)
 	return loadBalancersClient
 }
 
@@ -57,18 +58,19 @@ func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, lbName string
 	return ac.loadbalancers.Get(ctx, resourceGroupName, lbName, "")
 }
 
-// CreateOrUpdate creates or updates a load balancer.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, lbName string, lb network.LoadBalancer) error {
+// CreateOrUpdateAsync starts creating or updating a load balancer, and returns the future of the operation
+// without waiting for it to complete.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName string, lbName string, lb network.LoadBalancer) (azureautorest.Future, error) {
 	future, err := ac.loadbalancers.CreateOrUpdate(ctx, resourceGroupName, lbName, lb)
 	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.loadbalancers.Client)
-	if err != nil {
-		return err
+		return azureautorest.Future{}, err
 	}
-	_, err = future.Result(ac.loadbalancers)
-	return err
+	return future.Future, nil
+}
+
+// IsDone polls a long-running load balancer operation once and returns whether it is done.
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.Future) (bool, error) {
+	return future.DoneWithContext(ctx, ac.loadbalancers)
 }
 
 // Delete deletes the specified load balancer.
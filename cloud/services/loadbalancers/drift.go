@@ -0,0 +1,313 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+// DriftCorrectedReason is the reason of the event recorded when the configuration of a load balancer edited
+// out-of-band is corrected.
+const DriftCorrectedReason = "LoadBalancerDriftCorrected"
+
+// properties are the properties of a sub-resource of a load balancer the provider manages, by name. The unset ones
+// are left out.
+type properties map[string]string
+
+func (p properties) set(name string, value interface{}) {
+	switch v := value.(type) {
+	case *string:
+		if v != nil {
+			p[name] = *v
+		}
+	case *int32:
+		if v != nil {
+			p[name] = fmt.Sprint(*v)
+		}
+	case *bool:
+		if v != nil {
+			p[name] = fmt.Sprint(*v)
+		}
+	case *[]string:
+		if v != nil && len(*v) > 0 {
+			p[name] = strings.Join(*v, ",")
+		}
+	case *network.SubResource:
+		if v != nil && v.ID != nil {
+			p[name] = path.Base(*v.ID)
+		}
+	default:
+		if s := fmt.Sprint(v); s != "" {
+			p[name] = s
+		}
+	}
+}
+
+// matches returns whether these properties have the values of all the desired ones.
+func (p properties) matches(desired properties) bool {
+	for name, value := range desired {
+		if !strings.EqualFold(p[name], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// the kinds of sub-resources of a load balancer, in the order of loadBalancerSubResources.
+const (
+	frontendKind = iota
+	backendPoolKind
+	probeKind
+	loadBalancingRuleKind
+	inboundNatRuleKind
+	outboundRuleKind
+)
+
+// subResources are the sub-resources of a load balancer of one kind, in their order on the load balancer.
+type subResources struct {
+	kind       string
+	names      []string
+	properties map[string]properties
+}
+
+func (r *subResources) add(name *string) properties {
+	p := properties{}
+	r.names = append(r.names, to.String(name))
+	r.properties[to.String(name)] = p
+	return p
+}
+
+// loadBalancerSubResources returns the frontend IP configurations, backend pools, probes and rules of a load balancer.
+func loadBalancerSubResources(lb *network.LoadBalancer) []subResources {
+	frontends := subResources{kind: "frontend IP configuration", properties: map[string]properties{}}
+	pools := subResources{kind: "backend pool", properties: map[string]properties{}}
+	probes := subResources{kind: "probe", properties: map[string]properties{}}
+	lbRules := subResources{kind: "load balancing rule", properties: map[string]properties{}}
+	natRules := subResources{kind: "inbound NAT rule", properties: map[string]properties{}}
+	outboundRules := subResources{kind: "outbound rule", properties: map[string]properties{}}
+
+	props := lb.LoadBalancerPropertiesFormat
+	if props == nil {
+		props = &network.LoadBalancerPropertiesFormat{}
+	}
+	if props.FrontendIPConfigurations != nil {
+		for _, f := range *props.FrontendIPConfigurations {
+			p := frontends.add(f.Name)
+			p.set("zones", f.Zones)
+			if f.FrontendIPConfigurationPropertiesFormat != nil {
+				if f.PublicIPAddress != nil {
+					p.set("publicIP", f.PublicIPAddress.ID)
+				}
+				if f.Subnet != nil {
+					p.set("subnet", f.Subnet.ID)
+				}
+			}
+		}
+	}
+	if props.BackendAddressPools != nil {
+		for _, b := range *props.BackendAddressPools {
+			pools.add(b.Name)
+		}
+	}
+	if props.Probes != nil {
+		for _, r := range *props.Probes {
+			p := probes.add(r.Name)
+			if r.ProbePropertiesFormat != nil {
+				p.set("protocol", r.Protocol)
+				p.set("port", r.Port)
+				p.set("requestPath", r.RequestPath)
+				p.set("interval", r.IntervalInSeconds)
+				p.set("numberOfProbes", r.NumberOfProbes)
+			}
+		}
+	}
+	if props.LoadBalancingRules != nil {
+		for _, r := range *props.LoadBalancingRules {
+			p := lbRules.add(r.Name)
+			if r.LoadBalancingRulePropertiesFormat != nil {
+				p.set("protocol", r.Protocol)
+				p.set("frontend", r.FrontendIPConfiguration)
+				p.set("frontendPort", r.FrontendPort)
+				p.set("backendPool", r.BackendAddressPool)
+				p.set("backendPort", r.BackendPort)
+				p.set("probe", r.Probe)
+				p.set("idleTimeout", r.IdleTimeoutInMinutes)
+				p.set("floatingIP", r.EnableFloatingIP)
+				p.set("disableOutboundSnat", r.DisableOutboundSnat)
+				p.set("loadDistribution", r.LoadDistribution)
+			}
+		}
+	}
+	if props.InboundNatRules != nil {
+		for _, r := range *props.InboundNatRules {
+			p := natRules.add(r.Name)
+			if r.InboundNatRulePropertiesFormat != nil {
+				p.set("protocol", r.Protocol)
+				p.set("frontend", r.FrontendIPConfiguration)
+				p.set("frontendPort", r.FrontendPort)
+				p.set("backendPort", r.BackendPort)
+			}
+		}
+	}
+	if props.OutboundRules != nil {
+		for _, r := range *props.OutboundRules {
+			p := outboundRules.add(r.Name)
+			if r.OutboundRulePropertiesFormat != nil {
+				p.set("protocol", r.Protocol)
+				if r.FrontendIPConfigurations != nil {
+					for i := range *r.FrontendIPConfigurations {
+						p.set(fmt.Sprintf("frontend%d", i), &(*r.FrontendIPConfigurations)[i])
+					}
+				}
+				p.set("backendPool", r.BackendAddressPool)
+				p.set("allocatedOutboundPorts", r.AllocatedOutboundPorts)
+				p.set("idleTimeout", r.IdleTimeoutInMinutes)
+			}
+		}
+	}
+	return []subResources{frontends, pools, probes, lbRules, natRules, outboundRules}
+}
+
+// loadBalancerDrift returns the differences between a load balancer in Azure and the desired one: the sub-resources
+// missing from the existing load balancer, those configured differently, and those it has which are neither desired
+// nor user-managed.
+func loadBalancerDrift(existing, desired *network.LoadBalancer, userManaged map[string]bool) []string {
+	var drift []string
+	existingResources := loadBalancerSubResources(existing)
+	for i, want := range loadBalancerSubResources(desired) {
+		have := existingResources[i]
+		for _, name := range want.names {
+			existing, ok := have.properties[name]
+			switch {
+			case !ok:
+				drift = append(drift, fmt.Sprintf("missing %s %s", want.kind, name))
+			case !existing.matches(want.properties[name]):
+				drift = append(drift, fmt.Sprintf("changed %s %s", want.kind, name))
+			}
+		}
+		for _, name := range have.names {
+			if _, ok := want.properties[name]; !ok && !userManaged[name] {
+				drift = append(drift, fmt.Sprintf("unexpected %s %s", have.kind, name))
+			}
+		}
+	}
+	return drift
+}
+
+// userManagedNames returns the names of the sub-resources listed in the user-managed tag of a load balancer, which
+// were added out-of-band and are kept as they are.
+func userManagedNames(tags map[string]*string) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(to.String(tags[infrav1.UserManagedTagKey]), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// keepUserManaged adds the user-managed sub-resources of the existing load balancer to the desired one, unless the
+// desired load balancer has a sub-resource of the same name.
+func keepUserManaged(desired, existing *network.LoadBalancer, userManaged map[string]bool) {
+	if len(userManaged) == 0 || existing.LoadBalancerPropertiesFormat == nil {
+		return
+	}
+	props := desired.LoadBalancerPropertiesFormat
+	desiredResources := loadBalancerSubResources(desired)
+	keep := func(kind int, name *string) bool {
+		_, desired := desiredResources[kind].properties[to.String(name)]
+		return userManaged[to.String(name)] && !desired
+	}
+	if existing.FrontendIPConfigurations != nil {
+		for _, f := range *existing.FrontendIPConfigurations {
+			if keep(frontendKind, f.Name) {
+				var frontends []network.FrontendIPConfiguration
+				if props.FrontendIPConfigurations != nil {
+					frontends = *props.FrontendIPConfigurations
+				}
+				frontends = append(frontends, f)
+				props.FrontendIPConfigurations = &frontends
+			}
+		}
+	}
+	if existing.BackendAddressPools != nil {
+		for _, p := range *existing.BackendAddressPools {
+			if keep(backendPoolKind, p.Name) {
+				var pools []network.BackendAddressPool
+				if props.BackendAddressPools != nil {
+					pools = *props.BackendAddressPools
+				}
+				pools = append(pools, p)
+				props.BackendAddressPools = &pools
+			}
+		}
+	}
+	if existing.Probes != nil {
+		for _, p := range *existing.Probes {
+			if keep(probeKind, p.Name) {
+				var probes []network.Probe
+				if props.Probes != nil {
+					probes = *props.Probes
+				}
+				probes = append(probes, p)
+				props.Probes = &probes
+			}
+		}
+	}
+	if existing.LoadBalancingRules != nil {
+		for _, r := range *existing.LoadBalancingRules {
+			if keep(loadBalancingRuleKind, r.Name) {
+				var rules []network.LoadBalancingRule
+				if props.LoadBalancingRules != nil {
+					rules = *props.LoadBalancingRules
+				}
+				rules = append(rules, r)
+				props.LoadBalancingRules = &rules
+			}
+		}
+	}
+	if existing.InboundNatRules != nil {
+		for _, r := range *existing.InboundNatRules {
+			if keep(inboundNatRuleKind, r.Name) {
+				var rules []network.InboundNatRule
+				if props.InboundNatRules != nil {
+					rules = *props.InboundNatRules
+				}
+				rules = append(rules, r)
+				props.InboundNatRules = &rules
+			}
+		}
+	}
+	if existing.OutboundRules != nil {
+		for _, r := range *existing.OutboundRules {
+			if keep(outboundRuleKind, r.Name) {
+				var rules []network.OutboundRule
+				if props.OutboundRules != nil {
+					rules = *props.OutboundRules
+				}
+				rules = append(rules, r)
+				props.OutboundRules = &rules
+			}
+		}
+	}
+}
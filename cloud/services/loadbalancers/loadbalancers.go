@@ -28,6 +28,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/async"
 )
 
 // Reconcile gets/creates/updates a load balancer.
@@ -35,31 +36,59 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	for _, lbSpec := range s.Scope.LBSpecs() {
 		frontEndIPConfigName := fmt.Sprintf("%s-%s", lbSpec.Name, "frontEnd")
 		backEndAddressPoolName := fmt.Sprintf("%s-%s", lbSpec.Name, "backendPool")
-		if lbSpec.Role == infrav1.NodeOutboundRole {
+		if lbSpec.Role == infrav1.NodeOutboundRole || lbSpec.Role == infrav1.ControlPlaneOutboundRole {
 			backEndAddressPoolName = fmt.Sprintf("%s-%s", lbSpec.Name, "outboundBackendPool")
 		}
-		idPrefix := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers", s.Scope.SubscriptionID(), s.Scope.ResourceGroup())
+		idPrefix := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers", s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup())
+
+		// a load balancer created by an operation done since the last reconcile is read back but not updated again
+		done, err := async.ProcessOngoingOperation(ctx, s.Scope, s.Client, lbSpec.Name, serviceName)
+		if err != nil {
+			return err
+		}
 
 		s.Scope.V(2).Info("creating load balancer", "load balancer", lbSpec.Name)
 
+		var existingLB *network.LoadBalancer
+		existing, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name)
+		if err == nil {
+			existingLB = &existing
+		} else if !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to look for existing load balancer %s", lbSpec.Name)
+		}
+
 		var frontIPConfig network.FrontendIPConfigurationPropertiesFormat
+		var ipv6FrontIPConfig *network.FrontendIPConfigurationPropertiesFormat
 		if lbSpec.Role == infrav1.InternalRole {
+			if len(lbSpec.Zones) > 0 && !azure.SupportsAvailabilityZones(s.Scope.Location()) {
+				return errors.Errorf("cannot create load balancer %s with a frontend in zones %v: availability zones are not supported in location %s", lbSpec.Name, lbSpec.Zones, s.Scope.Location())
+			}
 			var privateIP string
-			internalLB, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), lbSpec.Name)
-			if err == nil {
-				ipConfigs := internalLB.LoadBalancerPropertiesFormat.FrontendIPConfigurations
+			allocationMethod := network.Static
+			if existingLB != nil {
+				ipConfigs := existingLB.LoadBalancerPropertiesFormat.FrontendIPConfigurations
 				if ipConfigs != nil && len(*ipConfigs) > 0 {
-					privateIP = to.String((*ipConfigs)[0].FrontendIPConfigurationPropertiesFormat.PrivateIPAddress)
+					properties := (*ipConfigs)[0].FrontendIPConfigurationPropertiesFormat
+					privateIP = to.String(properties.PrivateIPAddress)
+					if properties.PrivateIPAllocationMethod != "" {
+						allocationMethod = properties.PrivateIPAllocationMethod
+					}
 				}
-			} else if azure.ResourceNotFound(err) {
-				s.Scope.V(2).Info("internalLB not found in RG", "internal lb", lbSpec.Name, "resource group", s.Scope.ResourceGroup())
-				privateIP, err = s.getAvailablePrivateIP(ctx, s.Scope.Vnet().ResourceGroup, s.Scope.Vnet().Name, lbSpec.SubnetCidr, lbSpec.PrivateIPAddress)
+			} else if lbSpec.PrivateIPAddress == "" {
+				// without a static IP, Azure allocates one from the subnet and it is read back once the LB is created
+				s.Scope.V(2).Info("internalLB not found in RG, allocating a dynamic private IP", "internal lb", lbSpec.Name, "resource group", s.Scope.NetworkResourceGroup())
+				allocationMethod = network.Dynamic
+			} else {
+				s.Scope.V(2).Info("internalLB not found in RG", "internal lb", lbSpec.Name, "resource group", s.Scope.NetworkResourceGroup())
+				privateIP, err = s.getAvailablePrivateIP(ctx, s.Scope.Vnet().ResourceGroup, s.Scope.Vnet().Name, lbSpec.PrivateIPAddress)
 				if err != nil {
 					return err
 				}
 				s.Scope.V(2).Info("setting internal load balancer IP", "private ip", privateIP)
-			} else {
-				return errors.Wrap(err, "failed to look for existing internal LB")
+			}
+			if privateIP != "" && lbSpec.PrivateIPAddress != privateIP {
+				// record the selected private IP so the control plane endpoint can point at it
+				s.Scope.ControlPlaneSubnet().InternalLBIPAddress = privateIP
 			}
 			s.Scope.V(2).Info("getting subnet", "subnet", lbSpec.SubnetName)
 			subnet, err := s.SubnetsClient.Get(ctx, s.Scope.Vnet().ResourceGroup, s.Scope.Vnet().Name, lbSpec.SubnetName)
@@ -68,15 +97,21 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			}
 			s.Scope.V(2).Info("successfully got subnet", "subnet", lbSpec.SubnetName)
 			frontIPConfig = network.FrontendIPConfigurationPropertiesFormat{
-				PrivateIPAllocationMethod: network.Static,
+				PrivateIPAllocationMethod: allocationMethod,
 				Subnet:                    &subnet,
-				PrivateIPAddress:          to.StringPtr(privateIP),
+			}
+			if privateIP != "" {
+				frontIPConfig.PrivateIPAddress = to.StringPtr(privateIP)
 			}
 		} else {
+			publicIPResourceGroup := lbSpec.PublicIPResourceGroup
+			if publicIPResourceGroup == "" {
+				publicIPResourceGroup = s.Scope.NetworkResourceGroup()
+			}
 			s.Scope.V(2).Info("getting public ip", "public ip", lbSpec.PublicIPName)
-			publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.ResourceGroup(), lbSpec.PublicIPName)
+			publicIP, err := s.PublicIPsClient.Get(ctx, publicIPResourceGroup, lbSpec.PublicIPName)
 			if err != nil && azure.ResourceNotFound(err) {
-				return errors.Wrap(err, fmt.Sprintf("public ip %s not found in RG %s", lbSpec.PublicIPName, s.Scope.ResourceGroup()))
+				return errors.Wrap(err, fmt.Sprintf("public ip %s not found in RG %s", lbSpec.PublicIPName, publicIPResourceGroup))
 			} else if err != nil {
 				return errors.Wrap(err, "failed to look for existing public IP")
 			}
@@ -85,22 +120,50 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				PrivateIPAllocationMethod: network.Dynamic,
 				PublicIPAddress:           &publicIP,
 			}
+			if lbSpec.IPv6PublicIPName != "" {
+				ipv6PublicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), lbSpec.IPv6PublicIPName)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get IPv6 public IP %s", lbSpec.IPv6PublicIPName)
+				}
+				ipv6FrontIPConfig = &network.FrontendIPConfigurationPropertiesFormat{
+					PrivateIPAllocationMethod: network.Dynamic,
+					PublicIPAddress:           &ipv6PublicIP,
+				}
+			}
+		}
+
+		sku := network.LoadBalancerSkuNameStandard
+		if lbSpec.SKU == infrav1.SKUBasic {
+			sku = network.LoadBalancerSkuNameBasic
+		}
+
+		tags := converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Role:        to.StringPtr(lbSpec.Role),
+			Additional:  s.Scope.AdditionalTags(),
+		}))
+		var tagsChanged bool
+		if existingLB != nil {
+			// keep the tags set out-of-band on the existing load balancer
+			tags, tagsChanged = converters.UpdateTags(existingLB.Tags, converters.MapToTags(tags), s.Scope.LastAppliedTags())
+		}
+
+		var frontendZones *[]string
+		if lbSpec.Role == infrav1.InternalRole && len(lbSpec.Zones) > 0 {
+			frontendZones = &lbSpec.Zones
 		}
 
 		lb := network.LoadBalancer{
-			Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+			Sku:      &network.LoadBalancerSku{Name: sku},
 			Location: to.StringPtr(s.Scope.Location()),
-			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-				ClusterName: s.Scope.ClusterName(),
-				Lifecycle:   infrav1.ResourceLifecycleOwned,
-				Role:        to.StringPtr(lbSpec.Role),
-				Additional:  s.Scope.AdditionalTags(),
-			})),
+			Tags:     tags,
 			LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
 				FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
 					{
 						Name:                                    &frontEndIPConfigName,
 						FrontendIPConfigurationPropertiesFormat: &frontIPConfig,
+						Zones:                                   frontendZones,
 					},
 				},
 				BackendAddressPools: &[]network.BackendAddressPool{
@@ -129,19 +192,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 
 		if lbSpec.Role == infrav1.APIServerRole || lbSpec.Role == infrav1.InternalRole {
-			probeName := "HTTPSProbe"
-			lb.LoadBalancerPropertiesFormat.Probes = &[]network.Probe{
-				{
-					Name: to.StringPtr(probeName),
-					ProbePropertiesFormat: &network.ProbePropertiesFormat{
-						Protocol:          network.ProbeProtocolHTTPS,
-						RequestPath:       to.StringPtr("/healthz"),
-						Port:              to.Int32Ptr(lbSpec.APIServerPort),
-						IntervalInSeconds: to.Int32Ptr(15),
-						NumberOfProbes:    to.Int32Ptr(4),
-					},
-				},
-			}
+			probe := apiServerProbe(lbSpec)
+			probeName := to.String(probe.Name)
+			lb.LoadBalancerPropertiesFormat.Probes = &[]network.Probe{probe}
 			lbRule := network.LoadBalancingRule{
 				Name: to.StringPtr("LBRuleHTTPS"),
 				LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
@@ -150,7 +203,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 					BackendPort:          to.Int32Ptr(lbSpec.APIServerPort),
 					IdleTimeoutInMinutes: to.Int32Ptr(4),
 					EnableFloatingIP:     to.BoolPtr(false),
-					LoadDistribution:     network.LoadDistributionDefault,
+					LoadDistribution:     loadDistribution(lbSpec),
 					FrontendIPConfiguration: &network.SubResource{
 						ID: to.StringPtr(fmt.Sprintf("/%s/%s/frontendIPConfigurations/%s", idPrefix, lbSpec.Name, frontEndIPConfigName)),
 					},
@@ -163,7 +216,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				},
 			}
 
-			if lbSpec.Role == infrav1.APIServerRole {
+			if lbSpec.Role == infrav1.APIServerRole && sku == network.LoadBalancerSkuNameStandard {
 				// We disable outbound SNAT explicitly in the HTTPS LB rule and enable TCP and UDP outbound NAT with an outbound rule.
 				// For more information on Standard LB outbound connections see https://docs.microsoft.com/en-us/azure/load-balancer/load-balancer-outbound-connections.
 				lbRule.LoadBalancingRulePropertiesFormat.DisableOutboundSnat = to.BoolPtr(true)
@@ -173,10 +226,75 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			lb.LoadBalancerPropertiesFormat.LoadBalancingRules = &[]network.LoadBalancingRule{lbRule}
 		}
 
-		err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), lbSpec.Name, lb)
+		if lbSpec.Role == infrav1.NodeOutboundRole {
+			if err := s.addOutboundFrontends(ctx, &lb, lbSpec, idPrefix); err != nil {
+				return err
+			}
+			if err := configureOutboundRule(&lb, existingLB, lbSpec, backEndAddressPoolName); err != nil {
+				return err
+			}
+		}
 
-		if err != nil {
-			return errors.Wrapf(err, "failed to create load balancer %s", lbSpec.Name)
+		if lbSpec.Role == infrav1.ControlPlaneOutboundRole {
+			if err := configureOutboundRule(&lb, existingLB, lbSpec, backEndAddressPoolName); err != nil {
+				return err
+			}
+		}
+
+		if ipv6FrontIPConfig != nil {
+			addIPv6Configuration(&lb, lbSpec, ipv6FrontIPConfig, idPrefix)
+		}
+
+		if lbSpec.Role == infrav1.APIServerRole {
+			if err := s.addAdditionalFrontends(ctx, &lb, lbSpec, idPrefix); err != nil {
+				return err
+			}
+			if existingLB != nil && existingLB.LoadBalancerPropertiesFormat != nil {
+				// the SSH inbound NAT rules are reconciled with the control plane machines, keep them on update
+				lb.LoadBalancerPropertiesFormat.InboundNatRules = existingLB.InboundNatRules
+			}
+		}
+
+		if lbSpec.Role == infrav1.InternalRole && existingLB != nil && existingLB.LoadBalancerPropertiesFormat != nil {
+			// the inbound NAT rules of the internal LB are reconciled with the control plane machines, keep the
+			// rules of the configured ones on update and remove the others
+			lb.LoadBalancerPropertiesFormat.InboundNatRules = internalInboundNatRules(existingLB, lbSpec.InboundNatRules)
+		}
+
+		if sku == network.LoadBalancerSkuNameBasic {
+			// outbound rules are only supported by Standard load balancers, Basic load balancers provide implicit outbound NAT
+			lb.LoadBalancerPropertiesFormat.OutboundRules = nil
+		}
+
+		var drift []string
+		if existingLB != nil {
+			// the sub-resources listed in the user-managed tag were added out-of-band, keep them on update
+			userManaged := userManagedNames(existingLB.Tags)
+			keepUserManaged(&lb, existingLB, userManaged)
+			drift = loadBalancerDrift(existingLB, &lb, userManaged)
+		}
+
+		if existingLB != nil && len(drift) == 0 && !tagsChanged {
+			// the load balancer is up to date, an update would only start a long running operation to wait for
+			s.Scope.V(2).Info("load balancer is up to date", "load balancer", lbSpec.Name)
+		} else if !done {
+			future, err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name, lb)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create load balancer %s", lbSpec.Name)
+			}
+			if len(drift) > 0 {
+				s.Scope.V(2).Info("corrected the drift of load balancer", "load balancer", lbSpec.Name, "drift", drift)
+				s.Scope.Event(DriftCorrectedReason, fmt.Sprintf("corrected the drift of load balancer %s: %s", lbSpec.Name, strings.Join(drift, ", ")))
+			}
+			if err := async.StartOperation(ctx, s.Scope, s.Client, future, infrav1.PutFuture, serviceName, s.Scope.NetworkResourceGroup(), lbSpec.Name); err != nil {
+				return err
+			}
+		}
+
+		if lbSpec.Role == infrav1.InternalRole && frontIPConfig.PrivateIPAddress == nil {
+			if err := s.recordAllocatedPrivateIP(ctx, lbSpec.Name); err != nil {
+				return err
+			}
 		}
 
 		s.Scope.V(2).Info("successfully created load balancer", "load balancer", lbSpec.Name)
@@ -187,14 +305,28 @@ func (s *Service) Reconcile(ctx context.Context) error {
 // Delete deletes the public load balancer with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	for _, lbSpec := range s.Scope.LBSpecs() {
+		if !s.Scope.IsNetworkResourceGroupManaged() {
+			// only delete the load balancers owned by the cluster from a pre-existing resource group
+			lb, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name)
+			if azure.ResourceNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to get load balancer %s in resource group %s", lbSpec.Name, s.Scope.NetworkResourceGroup())
+			}
+			if !converters.MapToTags(lb.Tags).HasOwned(s.Scope.ClusterName()) {
+				klog.V(4).Infof("Skipping deletion of load balancer %s not owned by the cluster", lbSpec.Name)
+				continue
+			}
+		}
 		klog.V(2).Infof("deleting load balancer %s", lbSpec.Name)
-		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), lbSpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), lbSpec.Name)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
 			return nil
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete load balancer %s in resource group %s", lbSpec.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		klog.V(2).Infof("deleted public load balancer %s", lbSpec.Name)
@@ -202,18 +334,182 @@ func (s *Service) Delete(ctx context.Context) error {
 	return nil
 }
 
-// getAvailablePrivateIP checks if the desired private IP address is available in a virtual network.
-// If the IP address is taken or empty, it will make an attempt to find an available IP in the same subnet
-func (s *Service) getAvailablePrivateIP(ctx context.Context, resourceGroup, vnetName, subnetCIDR, PreferredIPAddress string) (string, error) {
-	ip := PreferredIPAddress
-	if ip == "" {
-		ip = azure.DefaultInternalLBIPAddress
-		if subnetCIDR != infrav1.DefaultControlPlaneSubnetCIDR {
-			// If the user provided a custom subnet CIDR without providing a private IP, try finding an available IP in the subnet space
-			index := strings.LastIndex(subnetCIDR, ".")
-			ip = subnetCIDR[0:(index+1)] + "0"
+// configureOutboundRule applies the SNAT port allocation and idle timeout of an outbound LB to its outbound rule.
+// Each frontend IP provides a fixed number of SNAT ports, so the allocated ports must leave enough ports
+// for every instance already in the backend pool.
+func configureOutboundRule(lb *network.LoadBalancer, existingLB *network.LoadBalancer, lbSpec azure.LBSpec, backEndAddressPoolName string) error {
+	rule := (*lb.OutboundRules)[0].OutboundRulePropertiesFormat
+	if lbSpec.IdleTimeoutInMinutes != 0 {
+		rule.IdleTimeoutInMinutes = to.Int32Ptr(lbSpec.IdleTimeoutInMinutes)
+	}
+	if lbSpec.AllocatedOutboundPorts == 0 {
+		return nil
+	}
+	rule.AllocatedOutboundPorts = to.Int32Ptr(lbSpec.AllocatedOutboundPorts)
+
+	if existingLB == nil {
+		return nil
+	}
+	instances := 0
+	if existingLB.LoadBalancerPropertiesFormat != nil && existingLB.BackendAddressPools != nil {
+		for _, pool := range *existingLB.BackendAddressPools {
+			if to.String(pool.Name) == backEndAddressPoolName && pool.BackendAddressPoolPropertiesFormat != nil && pool.BackendIPConfigurations != nil {
+				instances = len(*pool.BackendIPConfigurations)
+			}
+		}
+	}
+	frontendIPs := len(*rule.FrontendIPConfigurations)
+	if maxInstances := frontendIPs * infrav1.MaxOutboundPortsPerIP / int(lbSpec.AllocatedOutboundPorts); instances > maxInstances {
+		return errors.Errorf("cannot allocate %d outbound ports per instance on load balancer %s: its %d frontend IPs provide ports for at most %d instances, but the backend pool has %d",
+			lbSpec.AllocatedOutboundPorts, lbSpec.Name, frontendIPs, maxInstances, instances)
+	}
+	return nil
+}
+
+// addOutboundFrontends adds a frontend for each additional public IP of the node outbound load balancer,
+// and uses all of them in its outbound rule so the SNAT ports of every IP are available to the nodes.
+func (s *Service) addOutboundFrontends(ctx context.Context, lb *network.LoadBalancer, lbSpec azure.LBSpec, idPrefix string) error {
+	if len(lbSpec.AdditionalPublicIPNames) == 0 {
+		return nil
+	}
+	props := lb.LoadBalancerPropertiesFormat
+	frontendIPConfigs := *props.FrontendIPConfigurations
+	rule := (*props.OutboundRules)[0].OutboundRulePropertiesFormat
+	ruleFrontends := *rule.FrontendIPConfigurations
+	for _, ipName := range lbSpec.AdditionalPublicIPNames {
+		publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), ipName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get outbound public IP %s of load balancer %s", ipName, lbSpec.Name)
 		}
+		frontEndIPConfigName := azure.GenerateAdditionalFrontendIPConfigName(lbSpec.Name, ipName)
+		frontendIPConfigs = append(frontendIPConfigs, network.FrontendIPConfiguration{
+			Name: to.StringPtr(frontEndIPConfigName),
+			FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+				PrivateIPAllocationMethod: network.Dynamic,
+				PublicIPAddress:           &publicIP,
+			},
+		})
+		ruleFrontends = append(ruleFrontends, network.SubResource{
+			ID: to.StringPtr(fmt.Sprintf("/%s/%s/frontendIPConfigurations/%s", idPrefix, lbSpec.Name, frontEndIPConfigName)),
+		})
 	}
+	props.FrontendIPConfigurations = &frontendIPConfigs
+	rule.FrontendIPConfigurations = &ruleFrontends
+	return nil
+}
+
+// addIPv6Configuration adds the IPv6 frontend, backend pool and rules of a dual-stack load balancer,
+// mirroring the IPv4 ones.
+func addIPv6Configuration(lb *network.LoadBalancer, lbSpec azure.LBSpec, frontIPConfig *network.FrontendIPConfigurationPropertiesFormat, idPrefix string) {
+	frontEndIPConfigName := azure.GenerateIPv6FrontendIPConfigName(lbSpec.Name)
+	backEndAddressPoolName := azure.GenerateIPv6BackendPoolName(lbSpec.Name)
+	frontEndIPConfigID := to.StringPtr(fmt.Sprintf("/%s/%s/frontendIPConfigurations/%s", idPrefix, lbSpec.Name, frontEndIPConfigName))
+	backEndAddressPoolID := to.StringPtr(fmt.Sprintf("/%s/%s/backendAddressPools/%s", idPrefix, lbSpec.Name, backEndAddressPoolName))
+
+	props := lb.LoadBalancerPropertiesFormat
+	frontendIPConfigs := append(*props.FrontendIPConfigurations, network.FrontendIPConfiguration{
+		Name:                                    to.StringPtr(frontEndIPConfigName),
+		FrontendIPConfigurationPropertiesFormat: frontIPConfig,
+	})
+	props.FrontendIPConfigurations = &frontendIPConfigs
+	backendPools := append(*props.BackendAddressPools, network.BackendAddressPool{
+		Name: to.StringPtr(backEndAddressPoolName),
+	})
+	props.BackendAddressPools = &backendPools
+
+	if props.OutboundRules != nil {
+		outboundRules := *props.OutboundRules
+		for _, rule := range *props.OutboundRules {
+			ipv6Rule := rule
+			ipv6Rule.Name = to.StringPtr(to.String(rule.Name) + "-ipv6")
+			ruleProps := *rule.OutboundRulePropertiesFormat
+			ruleProps.FrontendIPConfigurations = &[]network.SubResource{{ID: frontEndIPConfigID}}
+			ruleProps.BackendAddressPool = &network.SubResource{ID: backEndAddressPoolID}
+			ipv6Rule.OutboundRulePropertiesFormat = &ruleProps
+			outboundRules = append(outboundRules, ipv6Rule)
+		}
+		props.OutboundRules = &outboundRules
+	}
+
+	if props.LoadBalancingRules != nil {
+		lbRules := *props.LoadBalancingRules
+		for _, rule := range *props.LoadBalancingRules {
+			ipv6Rule := rule
+			ipv6Rule.Name = to.StringPtr(to.String(rule.Name) + "-ipv6")
+			ruleProps := *rule.LoadBalancingRulePropertiesFormat
+			ruleProps.FrontendIPConfiguration = &network.SubResource{ID: frontEndIPConfigID}
+			ruleProps.BackendAddressPool = &network.SubResource{ID: backEndAddressPoolID}
+			ipv6Rule.LoadBalancingRulePropertiesFormat = &ruleProps
+			lbRules = append(lbRules, ipv6Rule)
+		}
+		props.LoadBalancingRules = &lbRules
+	}
+}
+
+// addAdditionalFrontends adds a frontend and a load balancing rule for each additional public IP of the API server load balancer.
+// The rules mirror the HTTPS rule of the default frontend, outbound traffic keeps using the default frontend only.
+// Azure only accepts several rules with the same backend port and pool when they use a floating IP, so the additional
+// rules do.
+func (s *Service) addAdditionalFrontends(ctx context.Context, lb *network.LoadBalancer, lbSpec azure.LBSpec, idPrefix string) error {
+	if len(lbSpec.AdditionalPublicIPNames) == 0 {
+		return nil
+	}
+	props := lb.LoadBalancerPropertiesFormat
+	frontendIPConfigs := *props.FrontendIPConfigurations
+	lbRules := *props.LoadBalancingRules
+	apiServerRule := lbRules[0]
+	for _, ipName := range lbSpec.AdditionalPublicIPNames {
+		publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), ipName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get additional public IP %s of load balancer %s", ipName, lbSpec.Name)
+		}
+		frontEndIPConfigName := azure.GenerateAdditionalFrontendIPConfigName(lbSpec.Name, ipName)
+		frontendIPConfigs = append(frontendIPConfigs, network.FrontendIPConfiguration{
+			Name: to.StringPtr(frontEndIPConfigName),
+			FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+				PrivateIPAllocationMethod: network.Dynamic,
+				PublicIPAddress:           &publicIP,
+			},
+		})
+		rule := apiServerRule
+		rule.Name = to.StringPtr(fmt.Sprintf("%s-%s", to.String(apiServerRule.Name), ipName))
+		ruleProps := *apiServerRule.LoadBalancingRulePropertiesFormat
+		ruleProps.FrontendIPConfiguration = &network.SubResource{
+			ID: to.StringPtr(fmt.Sprintf("/%s/%s/frontendIPConfigurations/%s", idPrefix, lbSpec.Name, frontEndIPConfigName)),
+		}
+		ruleProps.EnableFloatingIP = to.BoolPtr(true)
+		rule.LoadBalancingRulePropertiesFormat = &ruleProps
+		lbRules = append(lbRules, rule)
+	}
+	props.FrontendIPConfigurations = &frontendIPConfigs
+	props.LoadBalancingRules = &lbRules
+	return nil
+}
+
+// recordAllocatedPrivateIP reads the private IP Azure dynamically allocated to the frontend of the internal
+// load balancer and records it in the control plane subnet, so the control plane endpoint can point at it.
+func (s *Service) recordAllocatedPrivateIP(ctx context.Context, lbName string) error {
+	lb, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), lbName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get load balancer %s", lbName)
+	}
+	if lb.LoadBalancerPropertiesFormat == nil || lb.FrontendIPConfigurations == nil || len(*lb.FrontendIPConfigurations) == 0 ||
+		(*lb.FrontendIPConfigurations)[0].FrontendIPConfigurationPropertiesFormat == nil {
+		return errors.Errorf("load balancer %s has no frontend IP configuration", lbName)
+	}
+	privateIP := to.String((*lb.FrontendIPConfigurations)[0].PrivateIPAddress)
+	if privateIP == "" {
+		return errors.Errorf("no private IP was allocated to load balancer %s", lbName)
+	}
+	s.Scope.V(2).Info("setting internal load balancer IP", "private ip", privateIP)
+	s.Scope.ControlPlaneSubnet().InternalLBIPAddress = privateIP
+	return nil
+}
+
+// getAvailablePrivateIP checks if the desired private IP address is available in a virtual network.
+// If the IP address is taken, it will make an attempt to find an available IP in the same subnet
+func (s *Service) getAvailablePrivateIP(ctx context.Context, resourceGroup, vnetName, PreferredIPAddress string) (string, error) {
+	ip := PreferredIPAddress
 	result, err := s.VirtualNetworksClient.CheckIPAddressAvailability(ctx, resourceGroup, vnetName, ip)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to check IP availability")
@@ -226,3 +522,59 @@ func (s *Service) getAvailablePrivateIP(ctx context.Context, resourceGroup, vnet
 	}
 	return ip, nil
 }
+
+// internalInboundNatRules returns the inbound NAT rules of the internal load balancer which belong to one of the
+// configured rules, named <machine name>-<rule name>.
+func internalInboundNatRules(existingLB *network.LoadBalancer, configured []infrav1.InboundNatRule) *[]network.InboundNatRule {
+	if len(configured) == 0 || existingLB.InboundNatRules == nil {
+		return nil
+	}
+	rules := []network.InboundNatRule{}
+	for _, rule := range *existingLB.InboundNatRules {
+		for _, c := range configured {
+			if strings.HasSuffix(to.String(rule.Name), "-"+c.Name) {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+	return &rules
+}
+
+// loadDistribution returns the load distribution of the API server load balancing rules, Default unless another one is set.
+func loadDistribution(lbSpec azure.LBSpec) network.LoadDistribution {
+	if lbSpec.LoadDistribution == "" {
+		return network.LoadDistributionDefault
+	}
+	return network.LoadDistribution(lbSpec.LoadDistribution)
+}
+
+// apiServerProbe returns the health probe of an API server load balancer.
+// By default the API server is probed with an HTTPS request to /healthz every 15 seconds,
+// and an instance is marked unhealthy after 4 failed probes.
+func apiServerProbe(lbSpec azure.LBSpec) network.Probe {
+	probe := network.Probe{
+		Name: to.StringPtr("HTTPSProbe"),
+		ProbePropertiesFormat: &network.ProbePropertiesFormat{
+			Protocol:          network.ProbeProtocolHTTPS,
+			RequestPath:       to.StringPtr("/healthz"),
+			Port:              to.Int32Ptr(lbSpec.APIServerPort),
+			IntervalInSeconds: to.Int32Ptr(15),
+			NumberOfProbes:    to.Int32Ptr(4),
+		},
+	}
+	if lbSpec.Probe.Protocol == infrav1.ProbeProtocolTCP {
+		probe.Name = to.StringPtr("TCPProbe")
+		probe.Protocol = network.ProbeProtocolTCP
+		probe.RequestPath = nil
+	} else if lbSpec.Probe.RequestPath != "" {
+		probe.RequestPath = to.StringPtr(lbSpec.Probe.RequestPath)
+	}
+	if lbSpec.Probe.IntervalInSeconds != 0 {
+		probe.IntervalInSeconds = to.Int32Ptr(lbSpec.Probe.IntervalInSeconds)
+	}
+	if lbSpec.Probe.UnhealthyThreshold != 0 {
+		probe.NumberOfProbes = to.Int32Ptr(lbSpec.Probe.UnhealthyThreshold)
+	}
+	return probe
+}
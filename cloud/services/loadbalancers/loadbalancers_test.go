@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks/mock_virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
 	"testing"
 
@@ -29,6 +30,7 @@ import (
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/loadbalancers/mock_loadbalancers"
@@ -59,7 +61,9 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
 		},
@@ -77,10 +81,33 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "pre-existing public IP in another resource group does not exist",
+			expectedError: "public ip my-static-ip not found in RG my-ips: #: Not found: StatusCode=404",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                  "my-publiclb",
+						PublicIPName:          "my-static-ip",
+						PublicIPResourceGroup: "my-ips",
+						Role:                  infrav1.APIServerRole,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				mPublicIP.Get(context.TODO(), "my-ips", "my-static-ip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
 		{
 			name:          "fail to create a public LB",
 			expectedError: "failed to create load balancer my-publiclb: #: Internal Server Error: StatusCode=500",
@@ -95,12 +122,14 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().AnyTimes().Return("testlocation")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
-				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(azureautorest.Future{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
 		{
@@ -118,13 +147,15 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().AnyTimes().Return("testlocation")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				gomock.InOrder(
 					mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("my-publicip")}, nil),
-					m.CreateOrUpdate(context.TODO(), "my-rg", "my-publiclb", matchers.DiffEq(network.LoadBalancer{
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", matchers.DiffEq(network.LoadBalancer{
 						Tags: map[string]*string{
 							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
 							"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.APIServerRole),
@@ -197,49 +228,120 @@ func TestReconcileLoadBalancer(t *testing.T) {
 								},
 							},
 						},
-					})).Return(nil))
+					})).Return(azureautorest.Future{}, nil))
 			},
 		},
 		{
-			name:          "create node outbound LB",
+			name:          "create dual-stack apiserver LB",
 			expectedError: "",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.LBSpecs().Return([]azure.LBSpec{
 					{
-						Name:         "cluster-name",
-						PublicIPName: "outbound-publicip",
-						Role:         infrav1.NodeOutboundRole,
+						Name:             "my-publiclb",
+						PublicIPName:     "my-publicip",
+						IPv6PublicIPName: "my-publicip-v6",
+						Role:             infrav1.APIServerRole,
+						APIServerPort:    6443,
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Location().AnyTimes().Return("testlocation")
-				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				gomock.InOrder(
-					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil),
-					m.CreateOrUpdate(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+					mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("my-publicip")}, nil),
+					mPublicIP.Get(context.TODO(), "my-rg", "my-publicip-v6").Return(network.PublicIPAddress{Name: to.StringPtr("my-publicip-v6")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", matchers.DiffEq(network.LoadBalancer{
 						Tags: map[string]*string{
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
-							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.APIServerRole),
 						},
 						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
 						Location: to.StringPtr("testlocation"),
 						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
 							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
 								{
-									Name: to.StringPtr("cluster-name-frontEnd"),
+									Name: to.StringPtr("my-publiclb-frontEnd"),
 									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
 										PrivateIPAllocationMethod: network.Dynamic,
-										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")},
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("my-publicip")},
+									},
+								},
+								{
+									Name: to.StringPtr("my-publiclb-frontEnd-ipv6"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("my-publicip-v6")},
 									},
 								},
 							},
 							BackendAddressPools: &[]network.BackendAddressPool{
 								{
-									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+									Name: to.StringPtr("my-publiclb-backendPool"),
+								},
+								{
+									Name: to.StringPtr("my-publiclb-backendPool-ipv6"),
+								},
+							},
+							LoadBalancingRules: &[]network.LoadBalancingRule{
+								{
+									Name: to.StringPtr("LBRuleHTTPS"),
+									LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+										DisableOutboundSnat:  to.BoolPtr(true),
+										Protocol:             network.TransportProtocolTCP,
+										FrontendPort:         to.Int32Ptr(6443),
+										BackendPort:          to.Int32Ptr(6443),
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+										EnableFloatingIP:     to.BoolPtr(false),
+										LoadDistribution:     network.LoadDistributionDefault,
+										FrontendIPConfiguration: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"),
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+										},
+										Probe: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/HTTPSProbe"),
+										},
+									},
+								},
+								{
+									Name: to.StringPtr("LBRuleHTTPS-ipv6"),
+									LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+										DisableOutboundSnat:  to.BoolPtr(true),
+										Protocol:             network.TransportProtocolTCP,
+										FrontendPort:         to.Int32Ptr(6443),
+										BackendPort:          to.Int32Ptr(6443),
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+										EnableFloatingIP:     to.BoolPtr(false),
+										LoadDistribution:     network.LoadDistributionDefault,
+										FrontendIPConfiguration: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd-ipv6"),
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool-ipv6"),
+										},
+										Probe: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/HTTPSProbe"),
+										},
+									},
+								},
+							},
+							Probes: &[]network.Probe{
+								{
+									Name: to.StringPtr("HTTPSProbe"),
+									ProbePropertiesFormat: &network.ProbePropertiesFormat{
+										Protocol:          network.ProbeProtocolHTTPS,
+										Port:              to.Int32Ptr(6443),
+										RequestPath:       to.StringPtr("/healthz"),
+										IntervalInSeconds: to.Int32Ptr(15),
+										NumberOfProbes:    to.Int32Ptr(4),
+									},
 								},
 							},
 							OutboundRules: &[]network.OutboundRule{
@@ -247,10 +349,152 @@ func TestReconcileLoadBalancer(t *testing.T) {
 									Name: to.StringPtr("OutboundNATAllProtocols"),
 									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
 										FrontendIPConfigurations: &[]network.SubResource{
-											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd")},
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")},
 										},
 										BackendAddressPool: &network.SubResource{
-											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/backendAddressPools/cluster-name-outboundBackendPool"),
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+										},
+										Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+									},
+								},
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols-ipv6"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd-ipv6")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool-ipv6"),
+										},
+										Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+									},
+								},
+							},
+						},
+					})).Return(azureautorest.Future{}, nil))
+			},
+		},
+		{
+			name:          "create apiserver LB with an additional public IP",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                    "my-publiclb",
+						PublicIPName:            "my-publicip",
+						AdditionalPublicIPNames: []string{"my-other-publicip"},
+						Role:                    infrav1.APIServerRole,
+						APIServerPort:           6443,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				gomock.InOrder(
+					mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("my-publicip")}, nil),
+					mPublicIP.Get(context.TODO(), "my-rg", "my-other-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("my-other-publicip")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.APIServerRole),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("my-publiclb-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("my-publicip")},
+									},
+								},
+								{
+									Name: to.StringPtr("my-publiclb-frontEnd-my-other-publicip"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("my-other-publicip")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("my-publiclb-backendPool"),
+								},
+							},
+							LoadBalancingRules: &[]network.LoadBalancingRule{
+								{
+									Name: to.StringPtr("LBRuleHTTPS"),
+									LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+										DisableOutboundSnat:  to.BoolPtr(true),
+										Protocol:             network.TransportProtocolTCP,
+										FrontendPort:         to.Int32Ptr(6443),
+										BackendPort:          to.Int32Ptr(6443),
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+										EnableFloatingIP:     to.BoolPtr(false),
+										LoadDistribution:     network.LoadDistributionDefault,
+										FrontendIPConfiguration: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"),
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+										},
+										Probe: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/HTTPSProbe"),
+										},
+									},
+								},
+								{
+									Name: to.StringPtr("LBRuleHTTPS-my-other-publicip"),
+									LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+										DisableOutboundSnat:  to.BoolPtr(true),
+										Protocol:             network.TransportProtocolTCP,
+										FrontendPort:         to.Int32Ptr(6443),
+										BackendPort:          to.Int32Ptr(6443),
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+										EnableFloatingIP:     to.BoolPtr(true),
+										LoadDistribution:     network.LoadDistributionDefault,
+										FrontendIPConfiguration: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd-my-other-publicip"),
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+										},
+										Probe: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/HTTPSProbe"),
+										},
+									},
+								},
+							},
+							Probes: &[]network.Probe{
+								{
+									Name: to.StringPtr("HTTPSProbe"),
+									ProbePropertiesFormat: &network.ProbePropertiesFormat{
+										Protocol:          network.ProbeProtocolHTTPS,
+										Port:              to.Int32Ptr(6443),
+										RequestPath:       to.StringPtr("/healthz"),
+										IntervalInSeconds: to.Int32Ptr(15),
+										NumberOfProbes:    to.Int32Ptr(4),
+									},
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
 										},
 										Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
 										IdleTimeoutInMinutes: to.Int32Ptr(4),
@@ -258,7 +502,483 @@ func TestReconcileLoadBalancer(t *testing.T) {
 								},
 							},
 						},
-					})).Return(nil))
+					})).Return(azureautorest.Future{}, nil))
+			},
+		},
+		{
+			name:          "additional public IP of the apiserver LB does not exist",
+			expectedError: "failed to get additional public IP my-other-publicip of load balancer my-publiclb: #: Not found: StatusCode=404",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                    "my-publiclb",
+						PublicIPName:            "my-publicip",
+						AdditionalPublicIPNames: []string{"my-other-publicip"},
+						Role:                    infrav1.APIServerRole,
+						APIServerPort:           6443,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "my-other-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "create node outbound LB",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:         "cluster-name",
+						PublicIPName: "outbound-publicip",
+						Role:         infrav1.NodeOutboundRole,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "cluster-name").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				gomock.InOrder(
+					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("cluster-name-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/backendAddressPools/cluster-name-outboundBackendPool"),
+										},
+										Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+									},
+								},
+							},
+						},
+					})).Return(azureautorest.Future{}, nil))
+			},
+		},
+		{
+			name:          "create control plane outbound LB",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                 "cluster-name-outbound-lb",
+						PublicIPName:         "pip-cluster-name-controlplane-outbound",
+						Role:                 infrav1.ControlPlaneOutboundRole,
+						IdleTimeoutInMinutes: 15,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "cluster-name-outbound-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				gomock.InOrder(
+					mPublicIP.Get(context.TODO(), "my-rg", "pip-cluster-name-controlplane-outbound").Return(network.PublicIPAddress{Name: to.StringPtr("pip-cluster-name-controlplane-outbound")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "cluster-name-outbound-lb", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.ControlPlaneOutboundRole),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("cluster-name-outbound-lb-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("pip-cluster-name-controlplane-outbound")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outbound-lb-outboundBackendPool"),
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name-outbound-lb/frontendIPConfigurations/cluster-name-outbound-lb-frontEnd")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name-outbound-lb/backendAddressPools/cluster-name-outbound-lb-outboundBackendPool"),
+										},
+										Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes: to.Int32Ptr(15),
+									},
+								},
+							},
+						},
+					})).Return(azureautorest.Future{}, nil))
+			},
+		},
+		{
+			name:          "update the tags of an existing node outbound LB",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:         "cluster-name",
+						PublicIPName: "outbound-publicip",
+						Role:         infrav1.NodeOutboundRole,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"hello": "world"})
+				s.Event(DriftCorrectedReason, gomock.Any())
+				gomock.InOrder(
+					m.Get(context.TODO(), "my-rg", "cluster-name").Return(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+							"foo":      to.StringPtr("bar"),
+							"external": to.StringPtr("value"),
+						},
+					}, nil),
+					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+							"hello":    to.StringPtr("world"),
+							"external": to.StringPtr("value"),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("cluster-name-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/backendAddressPools/cluster-name-outboundBackendPool"),
+										},
+										Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes: to.Int32Ptr(4),
+									},
+								},
+							},
+						},
+					})).Return(azureautorest.Future{}, nil))
+			},
+		},
+		{
+			name:          "create node outbound LB with allocated outbound ports",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                   "cluster-name",
+						PublicIPName:           "outbound-publicip",
+						Role:                   infrav1.NodeOutboundRole,
+						AllocatedOutboundPorts: 1024,
+						IdleTimeoutInMinutes:   30,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Event(DriftCorrectedReason, gomock.Any())
+				gomock.InOrder(
+					m.Get(context.TODO(), "my-rg", "cluster-name").Return(network.LoadBalancer{
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+									BackendAddressPoolPropertiesFormat: &network.BackendAddressPoolPropertiesFormat{
+										BackendIPConfigurations: &[]network.InterfaceIPConfiguration{{}, {}},
+									},
+								},
+							},
+						},
+					}, nil),
+					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("cluster-name-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/backendAddressPools/cluster-name-outboundBackendPool"),
+										},
+										Protocol:               network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes:   to.Int32Ptr(30),
+										AllocatedOutboundPorts: to.Int32Ptr(1024),
+									},
+								},
+							},
+						},
+					})).Return(azureautorest.Future{}, nil))
+			},
+		},
+		{
+			name:          "create node outbound LB with several outbound IPs",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                    "cluster-name",
+						PublicIPName:            "outbound-publicip",
+						AdditionalPublicIPNames: []string{"outbound-publicip-2"},
+						Role:                    infrav1.NodeOutboundRole,
+						AllocatedOutboundPorts:  32000,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Event(DriftCorrectedReason, gomock.Any())
+				gomock.InOrder(
+					m.Get(context.TODO(), "my-rg", "cluster-name").Return(network.LoadBalancer{
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+									BackendAddressPoolPropertiesFormat: &network.BackendAddressPoolPropertiesFormat{
+										BackendIPConfigurations: &[]network.InterfaceIPConfiguration{{}, {}, {}},
+									},
+								},
+							},
+						},
+					}, nil),
+					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil),
+					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip-2").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip-2")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
+						Location: to.StringPtr("testlocation"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("cluster-name-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")},
+									},
+								},
+								{
+									Name: to.StringPtr("cluster-name-frontEnd-outbound-publicip-2"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip-2")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+								},
+							},
+							OutboundRules: &[]network.OutboundRule{
+								{
+									Name: to.StringPtr("OutboundNATAllProtocols"),
+									OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
+										FrontendIPConfigurations: &[]network.SubResource{
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd")},
+											{ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/frontendIPConfigurations/cluster-name-frontEnd-outbound-publicip-2")},
+										},
+										BackendAddressPool: &network.SubResource{
+											ID: to.StringPtr("//subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/cluster-name/backendAddressPools/cluster-name-outboundBackendPool"),
+										},
+										Protocol:               network.LoadBalancerOutboundRuleProtocolAll,
+										IdleTimeoutInMinutes:   to.Int32Ptr(4),
+										AllocatedOutboundPorts: to.Int32Ptr(32000),
+									},
+								},
+							},
+						},
+					})).Return(azureautorest.Future{}, nil))
+			},
+		},
+		{
+			name:          "allocated outbound ports exceed the backend pool size",
+			expectedError: "cannot allocate 32000 outbound ports per instance on load balancer cluster-name: its 1 frontend IPs provide ports for at most 2 instances, but the backend pool has 3",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:                   "cluster-name",
+						PublicIPName:           "outbound-publicip",
+						Role:                   infrav1.NodeOutboundRole,
+						AllocatedOutboundPorts: 32000,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				gomock.InOrder(
+					m.Get(context.TODO(), "my-rg", "cluster-name").Return(network.LoadBalancer{
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+									BackendAddressPoolPropertiesFormat: &network.BackendAddressPoolPropertiesFormat{
+										BackendIPConfigurations: &[]network.InterfaceIPConfiguration{{}, {}, {}},
+									},
+								},
+							},
+						},
+					}, nil),
+					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil))
+			},
+		},
+		{
+			name:          "create basic node outbound LB",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:         "cluster-name",
+						PublicIPName: "outbound-publicip",
+						Role:         infrav1.NodeOutboundRole,
+						SKU:          infrav1.SKUBasic,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "cluster-name").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				gomock.InOrder(
+					mPublicIP.Get(context.TODO(), "my-rg", "outbound-publicip").Return(network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "cluster-name", matchers.DiffEq(network.LoadBalancer{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster-name": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr(infrav1.NodeOutboundRole),
+						},
+						Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameBasic},
+						Location: to.StringPtr("testlocation"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									Name: to.StringPtr("cluster-name-frontEnd"),
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PublicIPAddress:           &network.PublicIPAddress{Name: to.StringPtr("outbound-publicip")},
+									},
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									Name: to.StringPtr("cluster-name-outboundBackendPool"),
+								},
+							},
+						},
+					})).Return(azureautorest.Future{}, nil))
 			},
 		},
 		{
@@ -277,7 +997,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
 					ResourceGroup: "my-rg",
 					Name:          "my-vnet",
@@ -288,12 +1009,56 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				m.Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				mVnet.CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "10.0.0.10").Return(network.IPAddressAvailabilityResult{Available: to.BoolPtr(true)}, nil)
 				mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
-				m.CreateOrUpdate(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+			},
+		},
+		{
+			name:          "internal load balancer does not exist and a dynamic private IP is allocated",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.LBSpecs().Return([]azure.LBSpec{
+					{
+						Name:       "my-lb",
+						SubnetCidr: "10.0.0.0/16",
+						SubnetName: "my-subnet",
+						Role:       infrav1.InternalRole,
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "my-vnet",
+				})
+				s.ControlPlaneSubnet().Return(&infrav1.SubnetSpec{Name: "my-subnet"})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("cluster-name")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				gomock.InOrder(
+					m.Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
+					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
+					m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{})),
+					m.Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+										PrivateIPAllocationMethod: network.Dynamic,
+										PrivateIPAddress:          to.StringPtr("10.0.0.4"),
+									},
+								},
+							},
+						},
+					}, nil),
+				)
 			},
 		},
 		{
 			name:          "internal load balancer retrieval fails",
-			expectedError: "failed to look for existing internal LB: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to look for existing load balancer my-lb: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder, mSubnet *mock_subnets.MockClientMockRecorder) {
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
@@ -307,7 +1072,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
 					ResourceGroup: "my-rg",
 					Name:          "my-vnet",
@@ -335,7 +1101,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
 					ResourceGroup: "my-rg",
 					Name:          "my-vnet",
@@ -343,6 +1110,7 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				s.Location().AnyTimes().Return("testlocation")
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Event(DriftCorrectedReason, gomock.Any())
 				m.Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{
 					LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
 						FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
@@ -354,7 +1122,7 @@ func TestReconcileLoadBalancer(t *testing.T) {
 							},
 						}}}, nil)
 				mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
-				m.CreateOrUpdate(context.TODO(), "my-rg", "my-lb", matchers.DiffEq(network.LoadBalancer{
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb", matchers.DiffEq(network.LoadBalancer{
 					Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
 					Location: to.StringPtr("testlocation"),
 					Tags: map[string]*string{
@@ -431,7 +1199,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
 					ResourceGroup: "my-rg",
 					Name:          "my-vnet",
@@ -459,7 +1228,8 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
 					ResourceGroup: "my-rg",
 					Name:          "my-vnet",
@@ -500,7 +1270,10 @@ func TestReconcileLoadBalancer(t *testing.T) {
 					},
 				})
 				s.SubscriptionID().AnyTimes().Return("123")
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-lb-2").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Get(context.TODO(), "my-rg", "my-lb-3").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{
 					ResourceGroup: "my-rg",
 					Name:          "my-vnet",
@@ -513,14 +1286,15 @@ func TestReconcileLoadBalancer(t *testing.T) {
 				mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
 				mPublicIP.Get(context.TODO(), "my-rg", "my-apiserver-ip").Return(network.PublicIPAddress{Name: to.StringPtr("my-apiserver-ip")}, nil)
 				mPublicIP.Get(context.TODO(), "my-rg", "my-node-ip").Return(network.PublicIPAddress{Name: to.StringPtr("my-node-ip")}, nil)
-				m.CreateOrUpdate(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{}))
-				m.CreateOrUpdate(context.TODO(), "my-rg", "my-lb-2", gomock.AssignableToTypeOf(network.LoadBalancer{}))
-				m.CreateOrUpdate(context.TODO(), "my-rg", "my-lb-3", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb-2", gomock.AssignableToTypeOf(network.LoadBalancer{}))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb-3", gomock.AssignableToTypeOf(network.LoadBalancer{}))
 			},
 		},
 	}
 
 	for _, tc := range testcases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
 			t.Parallel()
@@ -535,6 +1309,7 @@ func TestReconcileLoadBalancer(t *testing.T) {
 			subnetMock := mock_subnets.NewMockClient(mockCtrl)
 
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), publicIPsMock.EXPECT(), vnetMock.EXPECT(), subnetMock.EXPECT())
+			expectNoOngoingOperation(scopeMock.EXPECT(), clientMock.EXPECT())
 
 			s := &Service{
 				Scope:                 scopeMock,
@@ -555,6 +1330,563 @@ func TestReconcileLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestReconcileLoadBalancerKeepsInboundNatRules(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+	clientMock := mock_loadbalancers.NewMockClient(mockCtrl)
+	publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+	natRules := &[]network.InboundNatRule{
+		{
+			Name: to.StringPtr("azure-test1"),
+			InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+				FrontendPort: to.Int32Ptr(22),
+				BackendPort:  to.Int32Ptr(22),
+			},
+		},
+	}
+	s := scopeMock.EXPECT()
+	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	s.LBSpecs().Return([]azure.LBSpec{
+		{
+			Name:          "my-publiclb",
+			PublicIPName:  "my-publicip",
+			Role:          infrav1.APIServerRole,
+			APIServerPort: 6443,
+		},
+	})
+	s.SubscriptionID().AnyTimes().Return("123")
+	s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+	s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+	s.Location().AnyTimes().Return("testlocation")
+	s.ClusterName().AnyTimes().Return("my-cluster")
+	s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+	s.Event(DriftCorrectedReason, gomock.Any())
+	clientMock.EXPECT().Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{
+		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{InboundNatRules: natRules},
+	}, nil)
+	publicIPsMock.EXPECT().Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
+	var updated network.LoadBalancer
+	clientMock.EXPECT().CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).
+		Do(func(_ context.Context, _, _ string, lb network.LoadBalancer) { updated = lb })
+	expectNoOngoingOperation(s, clientMock.EXPECT())
+
+	svc := &Service{
+		Scope:           scopeMock,
+		Client:          clientMock,
+		PublicIPsClient: publicIPsMock,
+	}
+
+	g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+	g.Expect(updated.InboundNatRules).To(Equal(natRules))
+}
+
+func TestReconcileInternalLoadBalancerKeepsInboundNatRules(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+	clientMock := mock_loadbalancers.NewMockClient(mockCtrl)
+	subnetMock := mock_subnets.NewMockClient(mockCtrl)
+
+	etcdRule := network.InboundNatRule{
+		Name: to.StringPtr("azure-test1-etcd"),
+		InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+			FrontendPort: to.Int32Ptr(12379),
+			BackendPort:  to.Int32Ptr(2379),
+		},
+	}
+	removedRule := network.InboundNatRule{
+		Name: to.StringPtr("azure-test1-removed"),
+		InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
+			FrontendPort: to.Int32Ptr(10000),
+			BackendPort:  to.Int32Ptr(10000),
+		},
+	}
+	s := scopeMock.EXPECT()
+	s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+	s.LBSpecs().Return([]azure.LBSpec{
+		{
+			Name:             "my-lb",
+			SubnetName:       "my-subnet",
+			PrivateIPAddress: "10.0.0.10",
+			Role:             infrav1.InternalRole,
+			APIServerPort:    6443,
+			InboundNatRules: []infrav1.InboundNatRule{
+				{Name: "etcd", FrontendPortRangeStart: 12379, FrontendPortRangeEnd: 12381, BackendPort: 2379},
+			},
+		},
+	})
+	s.SubscriptionID().AnyTimes().Return("123")
+	s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+	s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+	s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+	s.Location().AnyTimes().Return("testlocation")
+	s.ClusterName().AnyTimes().Return("my-cluster")
+	s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+	s.Event(DriftCorrectedReason, gomock.Any())
+	clientMock.EXPECT().Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{
+		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+				{
+					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
+						PrivateIPAddress:          to.StringPtr("10.0.0.10"),
+						PrivateIPAllocationMethod: network.Static,
+					},
+				},
+			},
+			InboundNatRules: &[]network.InboundNatRule{etcdRule, removedRule},
+		},
+	}, nil)
+	subnetMock.EXPECT().Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
+	var updated network.LoadBalancer
+	clientMock.EXPECT().CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{})).
+		Do(func(_ context.Context, _, _ string, lb network.LoadBalancer) { updated = lb })
+	expectNoOngoingOperation(s, clientMock.EXPECT())
+
+	svc := &Service{
+		Scope:         scopeMock,
+		Client:        clientMock,
+		SubnetsClient: subnetMock,
+	}
+
+	g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+	g.Expect(updated.InboundNatRules).To(Equal(&[]network.InboundNatRule{etcdRule}))
+}
+
+func TestReconcileLoadBalancerDrift(t *testing.T) {
+	testcases := []struct {
+		name          string
+		drift         func(lb *network.LoadBalancer)
+		expectedEvent string
+		expect        func(g *WithT, updated network.LoadBalancer)
+	}{
+		{
+			name: "no drift",
+		},
+		{
+			name: "missing load balancing rule",
+			drift: func(lb *network.LoadBalancer) {
+				lb.LoadBalancingRules = nil
+			},
+			expectedEvent: "corrected the drift of load balancer my-publiclb: missing load balancing rule LBRuleHTTPS",
+			expect: func(g *WithT, updated network.LoadBalancer) {
+				g.Expect(*updated.LoadBalancingRules).To(HaveLen(1))
+			},
+		},
+		{
+			name: "changed probe",
+			drift: func(lb *network.LoadBalancer) {
+				(*lb.Probes)[0].Port = to.Int32Ptr(80)
+			},
+			expectedEvent: "corrected the drift of load balancer my-publiclb: changed probe HTTPSProbe",
+			expect: func(g *WithT, updated network.LoadBalancer) {
+				g.Expect((*updated.Probes)[0].Port).To(Equal(to.Int32Ptr(6443)))
+			},
+		},
+		{
+			name: "unexpected frontend and rule are removed",
+			drift: func(lb *network.LoadBalancer) {
+				frontends := append(*lb.FrontendIPConfigurations, network.FrontendIPConfiguration{Name: to.StringPtr("extra-frontend")})
+				lb.FrontendIPConfigurations = &frontends
+				rules := append(*lb.LoadBalancingRules, network.LoadBalancingRule{Name: to.StringPtr("extra-rule")})
+				lb.LoadBalancingRules = &rules
+			},
+			expectedEvent: "corrected the drift of load balancer my-publiclb: unexpected frontend IP configuration extra-frontend, unexpected load balancing rule extra-rule",
+			expect: func(g *WithT, updated network.LoadBalancer) {
+				g.Expect(*updated.FrontendIPConfigurations).To(HaveLen(1))
+				g.Expect(*updated.LoadBalancingRules).To(HaveLen(1))
+			},
+		},
+		{
+			name: "user-managed frontend and rule are not a drift",
+			drift: func(lb *network.LoadBalancer) {
+				frontends := append(*lb.FrontendIPConfigurations, network.FrontendIPConfiguration{Name: to.StringPtr("user-frontend")})
+				lb.FrontendIPConfigurations = &frontends
+				rules := append(*lb.LoadBalancingRules, network.LoadBalancingRule{Name: to.StringPtr("user-rule")})
+				lb.LoadBalancingRules = &rules
+				lb.Tags[infrav1.UserManagedTagKey] = to.StringPtr("user-frontend, user-rule")
+			},
+		},
+		{
+			name: "user-managed frontend and rule are kept",
+			drift: func(lb *network.LoadBalancer) {
+				frontends := append(*lb.FrontendIPConfigurations, network.FrontendIPConfiguration{Name: to.StringPtr("user-frontend")})
+				lb.FrontendIPConfigurations = &frontends
+				rules := append(*lb.LoadBalancingRules, network.LoadBalancingRule{Name: to.StringPtr("user-rule")})
+				lb.LoadBalancingRules = &rules
+				lb.Tags[infrav1.UserManagedTagKey] = to.StringPtr("user-frontend, user-rule")
+				(*lb.Probes)[0].Port = to.Int32Ptr(80)
+			},
+			expectedEvent: "corrected the drift of load balancer my-publiclb: changed probe HTTPSProbe",
+			expect: func(g *WithT, updated network.LoadBalancer) {
+				g.Expect(*updated.FrontendIPConfigurations).To(HaveLen(2))
+				g.Expect(to.String((*updated.FrontendIPConfigurations)[1].Name)).To(Equal("user-frontend"))
+				g.Expect(*updated.LoadBalancingRules).To(HaveLen(2))
+				g.Expect(to.String((*updated.LoadBalancingRules)[1].Name)).To(Equal("user-rule"))
+				g.Expect(updated.Tags).To(HaveKeyWithValue(infrav1.UserManagedTagKey, to.StringPtr("user-frontend, user-rule")))
+			},
+		},
+		{
+			name: "changed tags",
+			drift: func(lb *network.LoadBalancer) {
+				delete(lb.Tags, infrav1.NameAzureClusterAPIRole)
+			},
+			expect: func(g *WithT, updated network.LoadBalancer) {
+				g.Expect(updated.Tags).To(HaveKeyWithValue(infrav1.NameAzureClusterAPIRole, to.StringPtr(infrav1.APIServerRole)))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+			clientMock := mock_loadbalancers.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.LBSpecs().Times(2).Return([]azure.LBSpec{
+				{
+					Name:          "my-publiclb",
+					PublicIPName:  "my-publicip",
+					Role:          infrav1.APIServerRole,
+					APIServerPort: 6443,
+				},
+			})
+			s.SubscriptionID().AnyTimes().Return("123")
+			s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+			s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+			s.Location().AnyTimes().Return("testlocation")
+			s.ClusterName().AnyTimes().Return("my-cluster")
+			s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			expectNoOngoingOperation(s, clientMock.EXPECT())
+			publicIPsMock.EXPECT().Get(context.TODO(), "my-rg", "my-publicip").Times(2).Return(network.PublicIPAddress{
+				ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip"),
+			}, nil)
+
+			svc := &Service{
+				Scope:           scopeMock,
+				Client:          clientMock,
+				PublicIPsClient: publicIPsMock,
+			}
+
+			// the load balancer created by a first reconcile drifts before the second one
+			var existing, updated network.LoadBalancer
+			gomock.InOrder(
+				clientMock.EXPECT().Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")),
+				clientMock.EXPECT().CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).
+					Do(func(_ context.Context, _, _ string, lb network.LoadBalancer) { existing = lb }),
+			)
+			g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+
+			if tc.drift != nil {
+				tc.drift(&existing)
+			}
+			if tc.expectedEvent != "" {
+				s.Event(DriftCorrectedReason, tc.expectedEvent)
+			}
+			get := clientMock.EXPECT().Get(context.TODO(), "my-rg", "my-publiclb").Return(existing, nil)
+			if tc.expect == nil {
+				// a load balancer without drift is not updated
+				g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+				return
+			}
+			clientMock.EXPECT().CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).
+				After(get).
+				Do(func(_ context.Context, _, _ string, lb network.LoadBalancer) { updated = lb })
+			g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+			tc.expect(g, updated)
+		})
+	}
+}
+
+func TestReconcileZoneRedundantInternalLoadBalancer(t *testing.T) {
+	testcases := []struct {
+		name          string
+		location      string
+		zones         []string
+		expectedZones *[]string
+		expectedError string
+	}{
+		{
+			name:          "zone-redundant frontend",
+			location:      "westus2",
+			zones:         []string{"1", "2", "3"},
+			expectedZones: &[]string{"1", "2", "3"},
+		},
+		{
+			name:     "non-zonal frontend",
+			location: "westus2",
+		},
+		{
+			name:          "zones in a location without availability zones",
+			location:      "westcentralus",
+			zones:         []string{"1", "2", "3"},
+			expectedError: "cannot create load balancer my-lb with a frontend in zones [1 2 3]: availability zones are not supported in location westcentralus",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+			clientMock := mock_loadbalancers.NewMockClient(mockCtrl)
+			vnetMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+			subnetMock := mock_subnets.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.LBSpecs().Return([]azure.LBSpec{
+				{
+					Name:             "my-lb",
+					SubnetName:       "my-subnet",
+					PrivateIPAddress: "10.0.0.10",
+					Role:             infrav1.InternalRole,
+					Zones:            tc.zones,
+				},
+			})
+			s.SubscriptionID().AnyTimes().Return("123")
+			s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+			s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+			s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+			s.Location().AnyTimes().Return(tc.location)
+			s.ClusterName().AnyTimes().Return("my-cluster")
+			s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			expectNoOngoingOperation(s, clientMock.EXPECT())
+			clientMock.EXPECT().Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			var created network.LoadBalancer
+			if tc.expectedError == "" {
+				vnetMock.EXPECT().CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "10.0.0.10").Return(network.IPAddressAvailabilityResult{Available: to.BoolPtr(true)}, nil)
+				subnetMock.EXPECT().Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
+				clientMock.EXPECT().CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{})).
+					Do(func(_ context.Context, _, _ string, lb network.LoadBalancer) { created = lb })
+			}
+
+			svc := &Service{
+				Scope:                 scopeMock,
+				Client:                clientMock,
+				VirtualNetworksClient: vnetMock,
+				SubnetsClient:         subnetMock,
+			}
+
+			err := svc.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect((*created.FrontendIPConfigurations)[0].Zones).To(Equal(tc.expectedZones))
+		})
+	}
+}
+
+func TestReconcileLoadBalancerDistribution(t *testing.T) {
+	testcases := []struct {
+		name         string
+		distribution infrav1.LoadDistribution
+		expected     network.LoadDistribution
+	}{
+		{
+			name:     "default load distribution",
+			expected: network.LoadDistributionDefault,
+		},
+		{
+			name:         "source IP affinity",
+			distribution: infrav1.LoadDistributionSourceIP,
+			expected:     network.LoadDistributionSourceIP,
+		},
+		{
+			name:         "source IP and protocol affinity",
+			distribution: infrav1.LoadDistributionSourceIPProtocol,
+			expected:     network.LoadDistributionSourceIPProtocol,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+			clientMock := mock_loadbalancers.NewMockClient(mockCtrl)
+			vnetMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+			subnetMock := mock_subnets.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.LBSpecs().Return([]azure.LBSpec{
+				{
+					Name:             "my-lb",
+					SubnetName:       "my-subnet",
+					PrivateIPAddress: "10.0.0.10",
+					Role:             infrav1.InternalRole,
+					APIServerPort:    6443,
+					LoadDistribution: tc.distribution,
+				},
+			})
+			s.SubscriptionID().AnyTimes().Return("123")
+			s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+			s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+			s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "my-vnet"})
+			s.Location().AnyTimes().Return("westus2")
+			s.ClusterName().AnyTimes().Return("my-cluster")
+			s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			expectNoOngoingOperation(s, clientMock.EXPECT())
+			clientMock.EXPECT().Get(context.TODO(), "my-rg", "my-lb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			vnetMock.EXPECT().CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "10.0.0.10").Return(network.IPAddressAvailabilityResult{Available: to.BoolPtr(true)}, nil)
+			subnetMock.EXPECT().Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil)
+			var created network.LoadBalancer
+			clientMock.EXPECT().CreateOrUpdateAsync(context.TODO(), "my-rg", "my-lb", gomock.AssignableToTypeOf(network.LoadBalancer{})).
+				Do(func(_ context.Context, _, _ string, lb network.LoadBalancer) { created = lb })
+
+			svc := &Service{
+				Scope:                 scopeMock,
+				Client:                clientMock,
+				VirtualNetworksClient: vnetMock,
+				SubnetsClient:         subnetMock,
+			}
+
+			g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+			g.Expect(*created.LoadBalancingRules).To(HaveLen(1))
+			g.Expect((*created.LoadBalancingRules)[0].LoadDistribution).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestReconcileLoadBalancerOperations(t *testing.T) {
+	g := NewWithT(t)
+
+	future, err := test.NewPutFuture("https://management.azure.com/my-publiclb", "https://management.azure.com/operations/my-op")
+	g.Expect(err).NotTo(HaveOccurred())
+	data, err := future.MarshalJSON()
+	g.Expect(err).NotTo(HaveOccurred())
+	ongoing := &infrav1.Future{
+		Type:          infrav1.PutFuture,
+		ServiceName:   serviceName,
+		ResourceGroup: "my-rg",
+		Name:          "my-publiclb",
+		Data:          string(data),
+	}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name:          "creation is in progress",
+			expectedError: "operation type PUT on Azure resource my-rg/my-publiclb is not done, state InProgress",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-publiclb", serviceName).Return(nil)
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "my-publiclb", gomock.AssignableToTypeOf(network.LoadBalancer{})).Return(future, nil)
+				m.IsDone(context.TODO(), future).Return(false, nil)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name:          "ongoing creation is still in progress",
+			expectedError: "operation type PUT on Azure resource my-rg/my-publiclb is not done, state InProgress",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-publiclb", serviceName).Return(ongoing.DeepCopy())
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(false, nil)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name: "ongoing creation is done",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-publiclb", serviceName).Return(ongoing.DeepCopy())
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+				s.DeleteLongRunningOperationState("my-publiclb", serviceName)
+				m.Get(context.TODO(), "my-rg", "my-publiclb").Return(network.LoadBalancer{LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{}}, nil)
+				mPublicIP.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, nil)
+			},
+		},
+		{
+			name:          "ongoing creation failed",
+			expectedError: "failed PUT operation on loadbalancers my-publiclb: #: Conflict: StatusCode=409",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("my-publiclb", serviceName).Return(ongoing.DeepCopy())
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+				s.DeleteLongRunningOperationState("my-publiclb", serviceName)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+			clientMock := mock_loadbalancers.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			s := scopeMock.EXPECT()
+			s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+			s.LBSpecs().Return([]azure.LBSpec{
+				{
+					Name:          "my-publiclb",
+					PublicIPName:  "my-publicip",
+					Role:          infrav1.APIServerRole,
+					APIServerPort: 6443,
+				},
+			})
+			s.SubscriptionID().AnyTimes().Return("123")
+			s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+			s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+			s.Location().AnyTimes().Return("testlocation")
+			s.ClusterName().AnyTimes().Return("my-cluster")
+			s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+			tc.expect(s, clientMock.EXPECT(), publicIPsMock.EXPECT())
+
+			svc := &Service{
+				Scope:           scopeMock,
+				Client:          clientMock,
+				PublicIPsClient: publicIPsMock,
+			}
+
+			err := svc.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+// expectNoOngoingOperation expects load balancers without a long-running operation in progress, whose creation
+// or update is done immediately.
+func expectNoOngoingOperation(s *mock_loadbalancers.MockLBScopeMockRecorder, m *mock_loadbalancers.MockClientMockRecorder) {
+	s.GetLongRunningOperationState(gomock.Any(), serviceName).AnyTimes().Return(nil)
+	s.DeleteLongRunningOperationState(gomock.Any(), serviceName).AnyTimes()
+	m.IsDone(gomock.Any(), gomock.Any()).AnyTimes().Return(true, nil)
+}
+
 func TestDeleteLoadBalancer(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -574,7 +1906,8 @@ func TestDeleteLoadBalancer(t *testing.T) {
 						Name: "my-publiclb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
 				m.Delete(context.TODO(), "my-rg", "my-internallb")
 				m.Delete(context.TODO(), "my-rg", "my-publiclb")
 			},
@@ -589,7 +1922,8 @@ func TestDeleteLoadBalancer(t *testing.T) {
 						Name: "my-publiclb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
 				m.Delete(context.TODO(), "my-rg", "my-publiclb").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
@@ -604,7 +1938,8 @@ func TestDeleteLoadBalancer(t *testing.T) {
 						Name: "my-publiclb",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
 				m.Delete(context.TODO(), "my-rg", "my-publiclb").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
@@ -640,29 +1975,100 @@ func TestDeleteLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestAPIServerProbe(t *testing.T) {
+	testcases := []struct {
+		name     string
+		probe    azure.ProbeSpec
+		expected network.Probe
+	}{
+		{
+			name:  "default probe",
+			probe: azure.ProbeSpec{},
+			expected: network.Probe{
+				Name: to.StringPtr("HTTPSProbe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolHTTPS,
+					RequestPath:       to.StringPtr("/healthz"),
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(15),
+					NumberOfProbes:    to.Int32Ptr(4),
+				},
+			},
+		},
+		{
+			name: "HTTPS probe with a custom path, interval and threshold",
+			probe: azure.ProbeSpec{
+				Protocol:           infrav1.ProbeProtocolHTTPS,
+				RequestPath:        "/readyz",
+				IntervalInSeconds:  30,
+				UnhealthyThreshold: 8,
+			},
+			expected: network.Probe{
+				Name: to.StringPtr("HTTPSProbe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolHTTPS,
+					RequestPath:       to.StringPtr("/readyz"),
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(30),
+					NumberOfProbes:    to.Int32Ptr(8),
+				},
+			},
+		},
+		{
+			name:  "TCP probe",
+			probe: azure.ProbeSpec{Protocol: infrav1.ProbeProtocolTCP},
+			expected: network.Probe{
+				Name: to.StringPtr("TCPProbe"),
+				ProbePropertiesFormat: &network.ProbePropertiesFormat{
+					Protocol:          network.ProbeProtocolTCP,
+					Port:              to.Int32Ptr(6443),
+					IntervalInSeconds: to.Int32Ptr(15),
+					NumberOfProbes:    to.Int32Ptr(4),
+				},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			probe := apiServerProbe(azure.LBSpec{
+				Role:          infrav1.APIServerRole,
+				APIServerPort: 6443,
+				Probe:         tc.probe,
+			})
+			g.Expect(probe).To(Equal(tc.expected))
+		})
+	}
+}
+
 func TestGetAvailablePrivateIP(t *testing.T) {
 	g := NewWithT(t)
 
 	testcases := []struct {
-		name       string
-		subnetCidr string
-		expectedIP string
-		expect     func(s *mock_loadbalancers.MockLBScopeMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder)
+		name        string
+		preferredIP string
+		expectedIP  string
+		expect      func(s *mock_loadbalancers.MockLBScopeMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder)
 	}{
 		{
-			name:       "internal load balancer with a valid subnet cidr",
-			subnetCidr: "10.0.8.0/16",
-			expectedIP: "10.0.8.0",
+			name:        "preferred IP is available",
+			preferredIP: "10.0.8.10",
+			expectedIP:  "10.0.8.10",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder) {
-				mVnet.CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "10.0.8.0").Return(network.IPAddressAvailabilityResult{Available: to.BoolPtr(true)}, nil)
+				mVnet.CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "10.0.8.10").Return(network.IPAddressAvailabilityResult{Available: to.BoolPtr(true)}, nil)
 			},
 		},
 		{
-			name:       "internal load balancer subnet cidr not 8 characters in length",
-			subnetCidr: "10.64.8.0",
-			expectedIP: "10.64.8.0",
+			name:        "preferred IP is taken",
+			preferredIP: "10.64.8.10",
+			expectedIP:  "10.64.8.11",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, mVnet *mock_virtualnetworks.MockClientMockRecorder) {
-				mVnet.CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "10.64.8.0").Return(network.IPAddressAvailabilityResult{Available: to.BoolPtr(true)}, nil)
+				mVnet.CheckIPAddressAvailability(context.TODO(), "my-rg", "my-vnet", "10.64.8.10").Return(network.IPAddressAvailabilityResult{
+					Available:            to.BoolPtr(false),
+					AvailableIPAddresses: &[]string{"10.64.8.11", "10.64.8.12"},
+				}, nil)
 			},
 		},
 	}
@@ -679,7 +2085,7 @@ func TestGetAvailablePrivateIP(t *testing.T) {
 				VirtualNetworksClient: vnetMock,
 			}
 
-			resultIP, err := s.getAvailablePrivateIP(context.TODO(), "my-rg", "my-vnet", tc.subnetCidr, "")
+			resultIP, err := s.getAvailablePrivateIP(context.TODO(), "my-rg", "my-vnet", tc.preferredIP)
 			g.Expect(err).NotTo(HaveOccurred())
 			g.Expect(resultIP).To(Equal(tc.expectedIP))
 		})
@@ -108,6 +108,48 @@ func (mr *MockLBScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockLBScope)(nil).ResourceGroup))
 }
 
+// IsResourceGroupManaged mocks base method.
+func (m *MockLBScope) IsResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsResourceGroupManaged indicates an expected call of IsResourceGroupManaged.
+func (mr *MockLBScopeMockRecorder) IsResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsResourceGroupManaged", reflect.TypeOf((*MockLBScope)(nil).IsResourceGroupManaged))
+}
+
+// NetworkResourceGroup mocks base method.
+func (m *MockLBScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockLBScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockLBScope)(nil).NetworkResourceGroup))
+}
+
+// IsNetworkResourceGroupManaged mocks base method.
+func (m *MockLBScope) IsNetworkResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNetworkResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNetworkResourceGroupManaged indicates an expected call of IsNetworkResourceGroupManaged.
+func (mr *MockLBScopeMockRecorder) IsNetworkResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNetworkResourceGroupManaged", reflect.TypeOf((*MockLBScope)(nil).IsNetworkResourceGroupManaged))
+}
+
 // ClusterName mocks base method.
 func (m *MockLBScope) ClusterName() string {
 	m.ctrl.T.Helper()
@@ -150,6 +192,20 @@ func (mr *MockLBScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockLBScope)(nil).AdditionalTags))
 }
 
+// LastAppliedTags mocks base method.
+func (m *MockLBScope) LastAppliedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastAppliedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// LastAppliedTags indicates an expected call of LastAppliedTags.
+func (mr *MockLBScopeMockRecorder) LastAppliedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAppliedTags", reflect.TypeOf((*MockLBScope)(nil).LastAppliedTags))
+}
+
 // Vnet mocks base method.
 func (m *MockLBScope) Vnet() *v1alpha3.VnetSpec {
 	m.ctrl.T.Helper()
@@ -164,6 +220,20 @@ func (mr *MockLBScopeMockRecorder) Vnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockLBScope)(nil).Vnet))
 }
 
+// Subnets mocks base method.
+func (m *MockLBScope) Subnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// Subnets indicates an expected call of Subnets.
+func (mr *MockLBScopeMockRecorder) Subnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subnets", reflect.TypeOf((*MockLBScope)(nil).Subnets))
+}
+
 // NodeSubnet mocks base method.
 func (m *MockLBScope) NodeSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -178,6 +248,20 @@ func (mr *MockLBScopeMockRecorder) NodeSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnet", reflect.TypeOf((*MockLBScope)(nil).NodeSubnet))
 }
 
+// NodeSubnets mocks base method.
+func (m *MockLBScope) NodeSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// NodeSubnets indicates an expected call of NodeSubnets.
+func (mr *MockLBScopeMockRecorder) NodeSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnets", reflect.TypeOf((*MockLBScope)(nil).NodeSubnets))
+}
+
 // ControlPlaneSubnet mocks base method.
 func (m *MockLBScope) ControlPlaneSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -192,6 +276,160 @@ func (mr *MockLBScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockLBScope)(nil).ControlPlaneSubnet))
 }
 
+// ControlPlaneSubnets mocks base method.
+func (m *MockLBScope) ControlPlaneSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// ControlPlaneSubnets indicates an expected call of ControlPlaneSubnets.
+func (mr *MockLBScopeMockRecorder) ControlPlaneSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnets", reflect.TypeOf((*MockLBScope)(nil).ControlPlaneSubnets))
+}
+
+// ControlPlaneSubnetForZone mocks base method.
+func (m *MockLBScope) ControlPlaneSubnetForZone(zone string) *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnetForZone", zone)
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnetForZone indicates an expected call of ControlPlaneSubnetForZone.
+func (mr *MockLBScopeMockRecorder) ControlPlaneSubnetForZone(zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnetForZone", reflect.TypeOf((*MockLBScope)(nil).ControlPlaneSubnetForZone), zone)
+}
+
+// IsAPIServerPrivate mocks base method.
+func (m *MockLBScope) IsAPIServerPrivate() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAPIServerPrivate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAPIServerPrivate indicates an expected call of IsAPIServerPrivate.
+func (mr *MockLBScopeMockRecorder) IsAPIServerPrivate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAPIServerPrivate", reflect.TypeOf((*MockLBScope)(nil).IsAPIServerPrivate))
+}
+
+// InternalLBInboundNatRules mocks base method.
+func (m *MockLBScope) InternalLBInboundNatRules() []v1alpha3.InboundNatRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InternalLBInboundNatRules")
+	ret0, _ := ret[0].([]v1alpha3.InboundNatRule)
+	return ret0
+}
+
+// InternalLBInboundNatRules indicates an expected call of InternalLBInboundNatRules.
+func (mr *MockLBScopeMockRecorder) InternalLBInboundNatRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalLBInboundNatRules", reflect.TypeOf((*MockLBScope)(nil).InternalLBInboundNatRules))
+}
+
+// ApplicationGatewayName mocks base method.
+func (m *MockLBScope) ApplicationGatewayName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationGatewayName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ApplicationGatewayName indicates an expected call of ApplicationGatewayName.
+func (mr *MockLBScopeMockRecorder) ApplicationGatewayName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationGatewayName", reflect.TypeOf((*MockLBScope)(nil).ApplicationGatewayName))
+}
+
+// ControlPlaneOutboundLBName mocks base method.
+func (m *MockLBScope) ControlPlaneOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ControlPlaneOutboundLBName indicates an expected call of ControlPlaneOutboundLBName.
+func (mr *MockLBScopeMockRecorder) ControlPlaneOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneOutboundLBName", reflect.TypeOf((*MockLBScope)(nil).ControlPlaneOutboundLBName))
+}
+
+// NodeOutboundLBName mocks base method.
+func (m *MockLBScope) NodeOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeOutboundLBName indicates an expected call of NodeOutboundLBName.
+func (mr *MockLBScopeMockRecorder) NodeOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLBName", reflect.TypeOf((*MockLBScope)(nil).NodeOutboundLBName))
+}
+
+// AcceleratedNetworking mocks base method.
+func (m *MockLBScope) AcceleratedNetworking() *bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceleratedNetworking")
+	ret0, _ := ret[0].(*bool)
+	return ret0
+}
+
+// AcceleratedNetworking indicates an expected call of AcceleratedNetworking.
+func (mr *MockLBScopeMockRecorder) AcceleratedNetworking() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceleratedNetworking", reflect.TypeOf((*MockLBScope)(nil).AcceleratedNetworking))
+}
+
+// DiskEncryptionSetID mocks base method.
+func (m *MockLBScope) DiskEncryptionSetID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskEncryptionSetID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DiskEncryptionSetID indicates an expected call of DiskEncryptionSetID.
+func (mr *MockLBScopeMockRecorder) DiskEncryptionSetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskEncryptionSetID", reflect.TypeOf((*MockLBScope)(nil).DiskEncryptionSetID))
+}
+
+// DefaultImage mocks base method.
+func (m *MockLBScope) DefaultImage() *v1alpha3.Image {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultImage")
+	ret0, _ := ret[0].(*v1alpha3.Image)
+	return ret0
+}
+
+// DefaultImage indicates an expected call of DefaultImage.
+func (mr *MockLBScopeMockRecorder) DefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultImage", reflect.TypeOf((*MockLBScope)(nil).DefaultImage))
+}
+
+// EnforcedTags mocks base method.
+func (m *MockLBScope) EnforcedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnforcedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// EnforcedTags indicates an expected call of EnforcedTags.
+func (mr *MockLBScopeMockRecorder) EnforcedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnforcedTags", reflect.TypeOf((*MockLBScope)(nil).EnforcedTags))
+}
+
 // Info mocks base method.
 func (m *MockLBScope) Info(msg string, keysAndValues ...interface{}) {
 	m.ctrl.T.Helper()
@@ -286,6 +524,44 @@ func (mr *MockLBScopeMockRecorder) WithName(name interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithName", reflect.TypeOf((*MockLBScope)(nil).WithName), name)
 }
 
+// GetLongRunningOperationState mocks base method.
+func (m *MockLBScope) GetLongRunningOperationState(arg0, arg1 string) *v1alpha3.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", arg0, arg1)
+	ret0, _ := ret[0].(*v1alpha3.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockLBScopeMockRecorder) GetLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockLBScope)(nil).GetLongRunningOperationState), arg0, arg1)
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockLBScope) SetLongRunningOperationState(arg0 *v1alpha3.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockLBScopeMockRecorder) SetLongRunningOperationState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockLBScope)(nil).SetLongRunningOperationState), arg0)
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockLBScope) DeleteLongRunningOperationState(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", arg0, arg1)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockLBScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockLBScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
+}
+
 // LBSpecs mocks base method.
 func (m *MockLBScope) LBSpecs() []azure.LBSpec {
 	m.ctrl.T.Helper()
@@ -299,3 +575,15 @@ func (mr *MockLBScopeMockRecorder) LBSpecs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LBSpecs", reflect.TypeOf((*MockLBScope)(nil).LBSpecs))
 }
+
+// Event mocks base method.
+func (m *MockLBScope) Event(reason, message string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Event", reason, message)
+}
+
+// Event indicates an expected call of Event.
+func (mr *MockLBScopeMockRecorder) Event(reason, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Event", reflect.TypeOf((*MockLBScope)(nil).Event), reason, message)
+}
@@ -27,10 +27,14 @@ import (
 // LBScope defines the scope interface for a load balancer service.
 type LBScope interface {
 	azure.ClusterDescriber
+	azure.FutureScope
 	logr.Logger
 	LBSpecs() []azure.LBSpec
+	Event(reason, message string)
 }
 
+const serviceName = "loadbalancers"
+
 // Service provides operations on azure resources
 type Service struct {
 	Scope LBScope
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-06-01/subscriptions"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	List(context.Context) ([]subscriptions.Location, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	subscriptionID string
+	subscriptions  subscriptions.Client
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new locations client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		subscriptionID: auth.SubscriptionID(),
+		subscriptions:  newSubscriptionsClient(auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newSubscriptionsClient creates a new subscriptions client.
+func newSubscriptionsClient(baseURI string, authorizer autorest.Authorizer) subscriptions.Client {
+	c := subscriptions.NewClientWithBaseURI(baseURI)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// List returns the locations available to the subscription.
+func (ac *AzureClient) List(ctx context.Context) ([]subscriptions.Location, error) {
+	res, err := ac.subscriptions.ListLocations(ctx, ac.subscriptionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list locations")
+	}
+	if res.Value == nil {
+		return nil, nil
+	}
+	return *res.Value, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natgateways
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Reconcile gets/creates/updates a NAT gateway and associates it with its subnet.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, natGatewaySpec := range s.Scope.NatGatewaySpecs() {
+		s.Scope.V(2).Info("creating NAT gateway", "NAT gateway", natGatewaySpec.Name)
+		publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.PublicIPName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get public IP %s for NAT gateway %s", natGatewaySpec.PublicIPName, natGatewaySpec.Name)
+		}
+
+		err = s.Client.CreateOrUpdate(
+			ctx,
+			s.Scope.NetworkResourceGroup(),
+			natGatewaySpec.Name,
+			network.NatGateway{
+				Location: to.StringPtr(s.Scope.Location()),
+				Sku:      &network.NatGatewaySku{Name: network.Standard},
+				Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+					ClusterName: s.Scope.ClusterName(),
+					Lifecycle:   infrav1.ResourceLifecycleOwned,
+					Name:        to.StringPtr(natGatewaySpec.Name),
+					Additional:  s.Scope.AdditionalTags(),
+				})),
+				NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
+					PublicIPAddresses: &[]network.SubResource{
+						{ID: publicIP.ID},
+					},
+				},
+			},
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create NAT gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
+		}
+
+		natGateway, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.Name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get NAT gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
+		}
+		s.Scope.V(2).Info("successfully created NAT gateway", "NAT gateway", natGatewaySpec.Name)
+
+		if err := s.associateSubnet(ctx, natGatewaySpec.SubnetName, natGateway); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// associateSubnet attaches the NAT gateway to the subnet with the provided name and records its ID in the subnet spec.
+func (s *Service) associateSubnet(ctx context.Context, subnetName string, natGateway network.NatGateway) error {
+	subnet, err := s.SubnetsClient.Get(ctx, s.Scope.Vnet().ResourceGroup, s.Scope.Vnet().Name, subnetName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get subnet %s in vnet %s", subnetName, s.Scope.Vnet().Name)
+	}
+
+	if subnet.SubnetPropertiesFormat == nil || subnet.NatGateway == nil || to.String(subnet.NatGateway.ID) != to.String(natGateway.ID) {
+		s.Scope.V(2).Info("associating NAT gateway with subnet", "NAT gateway", to.String(natGateway.Name), "subnet", subnetName)
+		if subnet.SubnetPropertiesFormat == nil {
+			subnet.SubnetPropertiesFormat = &network.SubnetPropertiesFormat{}
+		}
+		subnet.NatGateway = &network.SubResource{ID: natGateway.ID}
+		if err := s.SubnetsClient.CreateOrUpdate(ctx, s.Scope.Vnet().ResourceGroup, s.Scope.Vnet().Name, subnetName, subnet); err != nil {
+			return errors.Wrapf(err, "failed to associate NAT gateway %s with subnet %s", to.String(natGateway.Name), subnetName)
+		}
+	}
+
+	for _, nodeSubnet := range s.Scope.NodeSubnets() {
+		if nodeSubnet.Name == subnetName {
+			nodeSubnet.NatGateway.ID = to.String(natGateway.ID)
+		}
+	}
+	return nil
+}
+
+// Delete deletes the NAT gateways in the provided scope.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, natGatewaySpec := range s.Scope.NatGatewaySpecs() {
+		if !s.Scope.IsNetworkResourceGroupManaged() {
+			// only delete the NAT gateways owned by the cluster from a pre-existing resource group
+			natGateway, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.Name)
+			if azure.ResourceNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to get NAT gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
+			}
+			if !converters.MapToTags(natGateway.Tags).HasOwned(s.Scope.ClusterName()) {
+				s.Scope.V(4).Info("Skipping deletion of NAT gateway not owned by the cluster", "NAT gateway", natGatewaySpec.Name)
+				continue
+			}
+		}
+		s.Scope.V(2).Info("deleting NAT gateway", "NAT gateway", natGatewaySpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), natGatewaySpec.Name)
+		if err != nil && azure.ResourceNotFound(err) {
+			// already deleted
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete NAT gateway %s in resource group %s", natGatewaySpec.Name, s.Scope.NetworkResourceGroup())
+		}
+
+		s.Scope.V(2).Info("successfully deleted NAT gateway", "NAT gateway", natGatewaySpec.Name)
+	}
+	return nil
+}
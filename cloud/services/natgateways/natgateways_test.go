@@ -0,0 +1,254 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natgateways
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/natgateways/mock_natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+)
+
+func TestReconcileNatGateways(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
+	}{
+		{
+			name:          "NAT gateway is created and associated with its subnet",
+			expectedError: "",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{
+						Name:         "my-natgw",
+						SubnetName:   "node-subnet",
+						PublicIPName: "pip-my-natgw",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				s.NodeSubnets().AnyTimes().Return(infrav1.Subnets{{Name: "node-subnet", Role: infrav1.SubnetNode}})
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-natgw").Return(network.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-natgw", gomock.AssignableToTypeOf(network.NatGateway{}))
+				m.Get(context.TODO(), "my-rg", "my-natgw").Return(network.NatGateway{ID: to.StringPtr("natgw-id"), Name: to.StringPtr("my-natgw")}, nil)
+				mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "node-subnet").Return(network.Subnet{
+					ID:                     to.StringPtr("subnet-id"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{},
+				}, nil)
+				mSubnet.CreateOrUpdate(context.TODO(), "my-rg", "my-vnet", "node-subnet", gomock.AssignableToTypeOf(network.Subnet{}))
+			},
+		},
+		{
+			name:          "NAT gateway already associated with its subnet",
+			expectedError: "",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{
+						Name:         "my-natgw",
+						SubnetName:   "node-subnet",
+						PublicIPName: "pip-my-natgw",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"})
+				s.NodeSubnets().AnyTimes().Return(infrav1.Subnets{{Name: "node-subnet", Role: infrav1.SubnetNode}})
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-natgw").Return(network.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-natgw", gomock.AssignableToTypeOf(network.NatGateway{}))
+				m.Get(context.TODO(), "my-rg", "my-natgw").Return(network.NatGateway{ID: to.StringPtr("natgw-id"), Name: to.StringPtr("my-natgw")}, nil)
+				mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "node-subnet").Return(network.Subnet{
+					ID: to.StringPtr("subnet-id"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						NatGateway: &network.SubResource{ID: to.StringPtr("natgw-id")},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "fail to get the NAT gateway public IP",
+			expectedError: "failed to get public IP pip-my-natgw for NAT gateway my-natgw: #: Not found: StatusCode=404",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{
+						Name:         "my-natgw",
+						SubnetName:   "node-subnet",
+						PublicIPName: "pip-my-natgw",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-natgw").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "fail to create the NAT gateway",
+			expectedError: "failed to create NAT gateway my-natgw in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{
+						Name:         "my-natgw",
+						SubnetName:   "node-subnet",
+						PublicIPName: "pip-my-natgw",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				mPublicIP.Get(context.TODO(), "my-rg", "pip-my-natgw").Return(network.PublicIPAddress{ID: to.StringPtr("pip-id")}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-natgw", gomock.AssignableToTypeOf(network.NatGateway{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_natgateways.NewMockNatGatewayScope(mockCtrl)
+			clientMock := mock_natgateways.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), subnetsMock.EXPECT(), publicIPsMock.EXPECT())
+
+			s := &Service{
+				Scope:           scopeMock,
+				Client:          clientMock,
+				SubnetsClient:   subnetsMock,
+				PublicIPsClient: publicIPsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteNatGateways(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete existing NAT gateways",
+			expectedError: "",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{Name: "my-natgw"},
+					{Name: "my-natgw-2"},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-natgw")
+				m.Delete(context.TODO(), "my-rg", "my-natgw-2")
+			},
+		},
+		{
+			name:          "NAT gateway already deleted",
+			expectedError: "",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{Name: "my-natgw"},
+					{Name: "my-natgw-2"},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-natgw").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Delete(context.TODO(), "my-rg", "my-natgw-2")
+			},
+		},
+		{
+			name:          "NAT gateway deletion fails",
+			expectedError: "failed to delete NAT gateway my-natgw in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, m *mock_natgateways.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.NatGatewaySpecs().Return([]azure.NatGatewaySpec{
+					{Name: "my-natgw"},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-natgw").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_natgateways.NewMockNatGatewayScope(mockCtrl)
+			clientMock := mock_natgateways.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
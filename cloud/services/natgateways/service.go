@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natgateways
+
+import (
+	"github.com/go-logr/logr"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets"
+)
+
+// NatGatewayScope defines the scope interface for a NAT gateway service.
+type NatGatewayScope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	NatGatewaySpecs() []azure.NatGatewaySpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope NatGatewayScope
+	Client
+	SubnetsClient   subnets.Client
+	PublicIPsClient publicips.Client
+}
+
+// NewService creates a new service.
+func NewService(scope NatGatewayScope) *Service {
+	return &Service{
+		Scope:           scope,
+		Client:          NewClient(scope),
+		SubnetsClient:   subnets.NewClient(scope),
+		PublicIPsClient: publicips.NewClient(scope),
+	}
+}
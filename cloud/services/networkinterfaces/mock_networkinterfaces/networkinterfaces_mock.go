@@ -108,6 +108,48 @@ func (mr *MockNICScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockNICScope)(nil).ResourceGroup))
 }
 
+// IsResourceGroupManaged mocks base method.
+func (m *MockNICScope) IsResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsResourceGroupManaged indicates an expected call of IsResourceGroupManaged.
+func (mr *MockNICScopeMockRecorder) IsResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsResourceGroupManaged", reflect.TypeOf((*MockNICScope)(nil).IsResourceGroupManaged))
+}
+
+// NetworkResourceGroup mocks base method.
+func (m *MockNICScope) NetworkResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NetworkResourceGroup indicates an expected call of NetworkResourceGroup.
+func (mr *MockNICScopeMockRecorder) NetworkResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkResourceGroup", reflect.TypeOf((*MockNICScope)(nil).NetworkResourceGroup))
+}
+
+// IsNetworkResourceGroupManaged mocks base method.
+func (m *MockNICScope) IsNetworkResourceGroupManaged() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNetworkResourceGroupManaged")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNetworkResourceGroupManaged indicates an expected call of IsNetworkResourceGroupManaged.
+func (mr *MockNICScopeMockRecorder) IsNetworkResourceGroupManaged() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNetworkResourceGroupManaged", reflect.TypeOf((*MockNICScope)(nil).IsNetworkResourceGroupManaged))
+}
+
 // ClusterName mocks base method.
 func (m *MockNICScope) ClusterName() string {
 	m.ctrl.T.Helper()
@@ -150,6 +192,20 @@ func (mr *MockNICScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockNICScope)(nil).AdditionalTags))
 }
 
+// LastAppliedTags mocks base method.
+func (m *MockNICScope) LastAppliedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastAppliedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// LastAppliedTags indicates an expected call of LastAppliedTags.
+func (mr *MockNICScopeMockRecorder) LastAppliedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAppliedTags", reflect.TypeOf((*MockNICScope)(nil).LastAppliedTags))
+}
+
 // Vnet mocks base method.
 func (m *MockNICScope) Vnet() *v1alpha3.VnetSpec {
 	m.ctrl.T.Helper()
@@ -164,6 +220,20 @@ func (mr *MockNICScopeMockRecorder) Vnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockNICScope)(nil).Vnet))
 }
 
+// Subnets mocks base method.
+func (m *MockNICScope) Subnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// Subnets indicates an expected call of Subnets.
+func (mr *MockNICScopeMockRecorder) Subnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subnets", reflect.TypeOf((*MockNICScope)(nil).Subnets))
+}
+
 // NodeSubnet mocks base method.
 func (m *MockNICScope) NodeSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -178,6 +248,20 @@ func (mr *MockNICScopeMockRecorder) NodeSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnet", reflect.TypeOf((*MockNICScope)(nil).NodeSubnet))
 }
 
+// NodeSubnets mocks base method.
+func (m *MockNICScope) NodeSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// NodeSubnets indicates an expected call of NodeSubnets.
+func (mr *MockNICScopeMockRecorder) NodeSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeSubnets", reflect.TypeOf((*MockNICScope)(nil).NodeSubnets))
+}
+
 // ControlPlaneSubnet mocks base method.
 func (m *MockNICScope) ControlPlaneSubnet() *v1alpha3.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -192,6 +276,160 @@ func (mr *MockNICScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockNICScope)(nil).ControlPlaneSubnet))
 }
 
+// ControlPlaneSubnets mocks base method.
+func (m *MockNICScope) ControlPlaneSubnets() v1alpha3.Subnets {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnets")
+	ret0, _ := ret[0].(v1alpha3.Subnets)
+	return ret0
+}
+
+// ControlPlaneSubnets indicates an expected call of ControlPlaneSubnets.
+func (mr *MockNICScopeMockRecorder) ControlPlaneSubnets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnets", reflect.TypeOf((*MockNICScope)(nil).ControlPlaneSubnets))
+}
+
+// ControlPlaneSubnetForZone mocks base method.
+func (m *MockNICScope) ControlPlaneSubnetForZone(zone string) *v1alpha3.SubnetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneSubnetForZone", zone)
+	ret0, _ := ret[0].(*v1alpha3.SubnetSpec)
+	return ret0
+}
+
+// ControlPlaneSubnetForZone indicates an expected call of ControlPlaneSubnetForZone.
+func (mr *MockNICScopeMockRecorder) ControlPlaneSubnetForZone(zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnetForZone", reflect.TypeOf((*MockNICScope)(nil).ControlPlaneSubnetForZone), zone)
+}
+
+// IsAPIServerPrivate mocks base method.
+func (m *MockNICScope) IsAPIServerPrivate() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAPIServerPrivate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAPIServerPrivate indicates an expected call of IsAPIServerPrivate.
+func (mr *MockNICScopeMockRecorder) IsAPIServerPrivate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAPIServerPrivate", reflect.TypeOf((*MockNICScope)(nil).IsAPIServerPrivate))
+}
+
+// InternalLBInboundNatRules mocks base method.
+func (m *MockNICScope) InternalLBInboundNatRules() []v1alpha3.InboundNatRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InternalLBInboundNatRules")
+	ret0, _ := ret[0].([]v1alpha3.InboundNatRule)
+	return ret0
+}
+
+// InternalLBInboundNatRules indicates an expected call of InternalLBInboundNatRules.
+func (mr *MockNICScopeMockRecorder) InternalLBInboundNatRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalLBInboundNatRules", reflect.TypeOf((*MockNICScope)(nil).InternalLBInboundNatRules))
+}
+
+// ApplicationGatewayName mocks base method.
+func (m *MockNICScope) ApplicationGatewayName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationGatewayName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ApplicationGatewayName indicates an expected call of ApplicationGatewayName.
+func (mr *MockNICScopeMockRecorder) ApplicationGatewayName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationGatewayName", reflect.TypeOf((*MockNICScope)(nil).ApplicationGatewayName))
+}
+
+// ControlPlaneOutboundLBName mocks base method.
+func (m *MockNICScope) ControlPlaneOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ControlPlaneOutboundLBName indicates an expected call of ControlPlaneOutboundLBName.
+func (mr *MockNICScopeMockRecorder) ControlPlaneOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneOutboundLBName", reflect.TypeOf((*MockNICScope)(nil).ControlPlaneOutboundLBName))
+}
+
+// NodeOutboundLBName mocks base method.
+func (m *MockNICScope) NodeOutboundLBName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeOutboundLBName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeOutboundLBName indicates an expected call of NodeOutboundLBName.
+func (mr *MockNICScopeMockRecorder) NodeOutboundLBName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeOutboundLBName", reflect.TypeOf((*MockNICScope)(nil).NodeOutboundLBName))
+}
+
+// AcceleratedNetworking mocks base method.
+func (m *MockNICScope) AcceleratedNetworking() *bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceleratedNetworking")
+	ret0, _ := ret[0].(*bool)
+	return ret0
+}
+
+// AcceleratedNetworking indicates an expected call of AcceleratedNetworking.
+func (mr *MockNICScopeMockRecorder) AcceleratedNetworking() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceleratedNetworking", reflect.TypeOf((*MockNICScope)(nil).AcceleratedNetworking))
+}
+
+// DiskEncryptionSetID mocks base method.
+func (m *MockNICScope) DiskEncryptionSetID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskEncryptionSetID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DiskEncryptionSetID indicates an expected call of DiskEncryptionSetID.
+func (mr *MockNICScopeMockRecorder) DiskEncryptionSetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskEncryptionSetID", reflect.TypeOf((*MockNICScope)(nil).DiskEncryptionSetID))
+}
+
+// DefaultImage mocks base method.
+func (m *MockNICScope) DefaultImage() *v1alpha3.Image {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultImage")
+	ret0, _ := ret[0].(*v1alpha3.Image)
+	return ret0
+}
+
+// DefaultImage indicates an expected call of DefaultImage.
+func (mr *MockNICScopeMockRecorder) DefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultImage", reflect.TypeOf((*MockNICScope)(nil).DefaultImage))
+}
+
+// EnforcedTags mocks base method.
+func (m *MockNICScope) EnforcedTags() v1alpha3.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnforcedTags")
+	ret0, _ := ret[0].(v1alpha3.Tags)
+	return ret0
+}
+
+// EnforcedTags indicates an expected call of EnforcedTags.
+func (mr *MockNICScopeMockRecorder) EnforcedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnforcedTags", reflect.TypeOf((*MockNICScope)(nil).EnforcedTags))
+}
+
 // Info mocks base method.
 func (m *MockNICScope) Info(msg string, keysAndValues ...interface{}) {
 	m.ctrl.T.Helper()
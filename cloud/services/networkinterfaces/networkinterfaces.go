@@ -45,8 +45,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 
 		backendAddressPools := []network.BackendAddressPool{}
+		ipv6BackendAddressPools := []network.BackendAddressPool{}
+		inboundNatRules := []network.InboundNatRule{}
 		if nicSpec.PublicLoadBalancerName != "" {
-			lb, lberr := s.LoadBalancersClient.Get(ctx, s.Scope.ResourceGroup(), nicSpec.PublicLoadBalancerName)
+			lb, lberr := s.LoadBalancersClient.Get(ctx, s.Scope.NetworkResourceGroup(), nicSpec.PublicLoadBalancerName)
 			if lberr != nil {
 				return errors.Wrap(lberr, "failed to get public LB")
 			}
@@ -54,24 +56,25 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				network.BackendAddressPool{
 					ID: (*lb.BackendAddressPools)[0].ID,
 				})
-
-			if nicSpec.MachineRole == infrav1.ControlPlane {
-				ruleName := nicSpec.MachineName
-				naterr := s.createInboundNatRule(ctx, lb, ruleName)
-				if naterr != nil {
-					return errors.Wrap(naterr, "failed to create NAT rule")
+			if nicSpec.IPv6Enabled {
+				for _, pool := range *lb.BackendAddressPools {
+					if to.String(pool.Name) == azure.GenerateIPv6BackendPoolName(nicSpec.PublicLoadBalancerName) {
+						ipv6BackendAddressPools = append(ipv6BackendAddressPools, network.BackendAddressPool{ID: pool.ID})
+					}
 				}
+			}
 
-				nicConfig.LoadBalancerInboundNatRules = &[]network.InboundNatRule{
-					{
-						ID: to.StringPtr(fmt.Sprintf("%s/inboundNatRules/%s", to.String(lb.ID), ruleName)),
-					},
-				}
+			if nicSpec.MachineRole == infrav1.ControlPlane && nicSpec.PublicLoadBalancerName == azure.GeneratePublicLBName(s.Scope.ClusterName()) {
+				// the SSH inbound NAT rule of the machine is reconciled before its network interface
+				ruleName := nicSpec.MachineName
+				inboundNatRules = append(inboundNatRules, network.InboundNatRule{
+					ID: to.StringPtr(fmt.Sprintf("%s/inboundNatRules/%s", to.String(lb.ID), ruleName)),
+				})
 			}
 		}
 		if nicSpec.InternalLoadBalancerName != "" {
 			// only control planes have an attached internal LB
-			internalLB, ilberr := s.LoadBalancersClient.Get(ctx, s.Scope.ResourceGroup(), nicSpec.InternalLoadBalancerName)
+			internalLB, ilberr := s.LoadBalancersClient.Get(ctx, s.Scope.NetworkResourceGroup(), nicSpec.InternalLoadBalancerName)
 			if ilberr != nil {
 				return errors.Wrap(ilberr, "failed to get internalLB")
 			}
@@ -80,39 +83,77 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				network.BackendAddressPool{
 					ID: (*internalLB.BackendAddressPools)[0].ID,
 				})
+			// the inbound NAT rules of the machine on the internal LB are reconciled before its network interface
+			for _, ruleName := range nicSpec.InternalInboundNatRuleNames {
+				inboundNatRules = append(inboundNatRules, network.InboundNatRule{
+					ID: to.StringPtr(fmt.Sprintf("%s/inboundNatRules/%s", to.String(internalLB.ID), ruleName)),
+				})
+			}
 		}
 		nicConfig.LoadBalancerBackendAddressPools = &backendAddressPools
+		if len(inboundNatRules) > 0 {
+			nicConfig.LoadBalancerInboundNatRules = &inboundNatRules
+		}
+		if nicSpec.ApplicationGatewayName != "" {
+			// the application gateway is reconciled with the cluster, before the network interfaces of the machines
+			nicConfig.ApplicationGatewayBackendAddressPools = &[]network.ApplicationGatewayBackendAddressPool{
+				{
+					ID: to.StringPtr(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationGateways/%s/backendAddressPools/%s",
+						s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), nicSpec.ApplicationGatewayName,
+						azure.GenerateApplicationGatewayBackendPoolName(nicSpec.ApplicationGatewayName))),
+				},
+			}
+		}
 
 		if nicSpec.PublicIPName != "" {
-			publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.ResourceGroup(), nicSpec.PublicIPName)
+			publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), nicSpec.PublicIPName)
 			if err != nil {
 				return errors.Wrap(err, "failed to get publicIP")
 			}
 			nicConfig.PublicIPAddress = &publicIP
 		}
 
-		if nicSpec.AcceleratedNetworking == nil {
-			// set accelerated networking to the capability of the VMSize
+		if nicSpec.AcceleratedNetworking == nil || *nicSpec.AcceleratedNetworking {
+			// set accelerated networking to the capability of the VMSize, or check it when explicitly enabled
 			sku := nicSpec.VMSize
 			accelNet, err := s.ResourceSkusClient.HasAcceleratedNetworking(ctx, sku)
 			if err != nil {
 				return errors.Wrap(err, "failed to get accelerated networking capability")
 			}
+			if nicSpec.AcceleratedNetworking != nil && !accelNet {
+				return errors.Errorf("cannot enable accelerated networking on network interface %s: VM size %s does not support it", nicSpec.Name, sku)
+			}
 			nicSpec.AcceleratedNetworking = to.BoolPtr(accelNet)
 		}
 
+		ipConfigs := []network.InterfaceIPConfiguration{
+			{
+				Name:                                     to.StringPtr("pipConfig"),
+				InterfaceIPConfigurationPropertiesFormat: nicConfig,
+			},
+		}
+		if nicSpec.IPv6Enabled {
+			// dual-stack NICs get a secondary IPv6 configuration, the IPv4 one must remain primary
+			nicConfig.Primary = to.BoolPtr(true)
+			ipConfigs = append(ipConfigs, network.InterfaceIPConfiguration{
+				Name: to.StringPtr("ipConfigv6"),
+				InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+					Subnet:                          &network.Subnet{ID: subnet.ID},
+					Primary:                         to.BoolPtr(false),
+					PrivateIPAllocationMethod:       network.Dynamic,
+					PrivateIPAddressVersion:         network.IPv6,
+					LoadBalancerBackendAddressPools: &ipv6BackendAddressPools,
+				},
+			})
+		}
+
 		err = s.Client.CreateOrUpdate(ctx,
 			s.Scope.ResourceGroup(),
 			nicSpec.Name,
 			network.Interface{
 				Location: to.StringPtr(s.Scope.Location()),
 				InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
-					IPConfigurations: &[]network.InterfaceIPConfiguration{
-						{
-							Name:                                     to.StringPtr("pipConfig"),
-							InterfaceIPConfigurationPropertiesFormat: nicConfig,
-						},
-					},
+					IPConfigurations:            &ipConfigs,
 					EnableAcceleratedNetworking: nicSpec.AcceleratedNetworking,
 				},
 			})
@@ -133,57 +174,7 @@ func (s *Service) Delete(ctx context.Context) error {
 		if err != nil && !azure.ResourceNotFound(err) {
 			return errors.Wrapf(err, "failed to delete network interface %s in resource group %s", nicSpec.Name, s.Scope.ResourceGroup())
 		}
-		NATRuleName := nicSpec.MachineName
-		err = s.InboundNATRulesClient.Delete(ctx, s.Scope.ResourceGroup(), nicSpec.PublicLoadBalancerName, NATRuleName)
-		if err != nil && !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete inbound NAT rule %s in load balancer %s", NATRuleName, nicSpec.PublicLoadBalancerName)
-		}
-		s.Scope.V(2).Info("successfully deleted NIC and NAT rule", "network interface", nicSpec.Name, "NAT rule", NATRuleName)
+		s.Scope.V(2).Info("successfully deleted NIC", "network interface", nicSpec.Name)
 	}
 	return nil
 }
-
-func (s *Service) createInboundNatRule(ctx context.Context, lb network.LoadBalancer, ruleName string) error {
-	var sshFrontendPort int32 = 22
-	ports := make(map[int32]struct{})
-	if lb.LoadBalancerPropertiesFormat == nil || lb.InboundNatRules == nil {
-		return errors.Errorf("Could not get existing inbound NAT rules from load balancer %s properties", to.String(lb.Name))
-	}
-	for _, v := range *lb.InboundNatRules {
-		if to.String(v.Name) == ruleName {
-			// Inbound NAT Rule already exists, nothing to do here.
-			s.Scope.V(2).Info("NAT rule already exists", "NAT rule", ruleName)
-			return nil
-		}
-		ports[*v.InboundNatRulePropertiesFormat.FrontendPort] = struct{}{}
-	}
-	if _, ok := ports[22]; ok {
-		var i int32
-		found := false
-		for i = 2201; i < 2220; i++ {
-			if _, ok := ports[i]; !ok {
-				sshFrontendPort = i
-				found = true
-				break
-			}
-		}
-		if !found {
-			return errors.Errorf("Failed to find available SSH Frontend port for NAT Rule in load balancer %s for AzureMachine %s", to.String(lb.Name), ruleName)
-		}
-	}
-	rule := network.InboundNatRule{
-		Name: to.StringPtr(ruleName),
-		InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-			BackendPort:          to.Int32Ptr(22),
-			EnableFloatingIP:     to.BoolPtr(false),
-			IdleTimeoutInMinutes: to.Int32Ptr(4),
-			FrontendIPConfiguration: &network.SubResource{
-				ID: (*lb.FrontendIPConfigurations)[0].ID,
-			},
-			Protocol:     network.TransportProtocolTCP,
-			FrontendPort: &sshFrontendPort,
-		},
-	}
-	s.Scope.V(3).Info("Creating rule %s using port %d", "NAT rule", ruleName, "port", sshFrontendPort)
-	return s.InboundNATRulesClient.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), to.String(lb.Name), ruleName, rule)
-}
@@ -30,7 +30,6 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
-	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules/mock_inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/loadbalancers/mock_loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces/mock_networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
@@ -49,7 +48,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 			m *mock_networkinterfaces.MockClientMockRecorder,
 			mSubnet *mock_subnets.MockClientMockRecorder,
 			mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-			mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 			mPublicIP *mock_publicips.MockClientMockRecorder,
 			mResourceSku *mock_resourceskus.MockClientMockRecorder)
 	}{
@@ -60,7 +58,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
 					{
@@ -71,6 +69,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").
 					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
@@ -83,7 +82,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -100,6 +98,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").
@@ -117,7 +116,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -135,6 +133,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -164,7 +163,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -181,6 +179,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -209,7 +208,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -227,6 +225,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").
@@ -247,19 +246,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 							},
 							InboundNatRules: &[]network.InboundNatRule{},
 						}}, nil),
-					mInboundNATRules.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "azure-test1", network.InboundNatRule{
-						Name: pointer.StringPtr("azure-test1"),
-						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-							FrontendPort:         to.Int32Ptr(22),
-							BackendPort:          to.Int32Ptr(22),
-							EnableFloatingIP:     to.BoolPtr(false),
-							IdleTimeoutInMinutes: to.Int32Ptr(4),
-							FrontendIPConfiguration: &network.SubResource{
-								ID: to.StringPtr("frontend-ip-config-id"),
-							},
-							Protocol: network.TransportProtocolTCP,
-						},
-					}),
 					mLoadBalancer.Get(context.TODO(), "my-rg", "my-internal-lb").
 						Return(network.LoadBalancer{
 							ID: pointer.StringPtr("my-internal-lb-id"),
@@ -291,13 +277,91 @@ func TestReconcileNetworkInterface(t *testing.T) {
 			},
 		},
 		{
-			name:          "control plane network interface fail to get public LB",
-			expectedError: "failed to get public LB: #: Internal Server Error: StatusCode=500",
+			name:          "control plane network interface with inbound NAT rules on the internal LB successfully created",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
+				m *mock_networkinterfaces.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder,
+				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{
+					{
+						Name:                        "my-net-interface",
+						MachineName:                 "azure-test1",
+						MachineRole:                 infrav1.ControlPlane,
+						SubnetName:                  "my-subnet",
+						VNetName:                    "my-vnet",
+						VNetResourceGroup:           "my-rg",
+						PublicLoadBalancerName:      "my-public-lb",
+						InternalLoadBalancerName:    "my-internal-lb",
+						InternalInboundNatRuleNames: []string{"azure-test1-etcd"},
+						VMSize:                      "Standard_D2v2",
+						AcceleratedNetworking:       nil,
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("fake-location")
+				gomock.InOrder(
+					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").
+						Return(network.Subnet{ID: to.StringPtr("my-subnet-id")}, nil),
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(network.LoadBalancer{
+						Name: to.StringPtr("my-public-lb"),
+						ID:   pointer.StringPtr("my-public-lb-id"),
+						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+								{
+									ID: to.StringPtr("frontend-ip-config-id"),
+								},
+							},
+							BackendAddressPools: &[]network.BackendAddressPool{
+								{
+									ID: pointer.StringPtr("my-backend-pool-id"),
+								},
+							},
+							InboundNatRules: &[]network.InboundNatRule{},
+						}}, nil),
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-internal-lb").
+						Return(network.LoadBalancer{
+							ID: pointer.StringPtr("my-internal-lb-id"),
+							LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+								BackendAddressPools: &[]network.BackendAddressPool{
+									{
+										ID: pointer.StringPtr("my-internal-backend-pool-id"),
+									},
+								},
+							}}, nil),
+					mResourceSku.HasAcceleratedNetworking(gomock.Any(), gomock.Any()),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-net-interface", matchers.DiffEq(network.Interface{
+						Location: to.StringPtr("test-location"),
+						InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+							EnableAcceleratedNetworking: to.BoolPtr(false),
+							IPConfigurations: &[]network.InterfaceIPConfiguration{
+								{
+									Name: to.StringPtr("pipConfig"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										Subnet:                    &network.Subnet{ID: to.StringPtr("my-subnet-id")},
+										PrivateIPAllocationMethod: network.Dynamic,
+										LoadBalancerInboundNatRules: &[]network.InboundNatRule{
+											{ID: to.StringPtr("my-public-lb-id/inboundNatRules/azure-test1")},
+											{ID: to.StringPtr("my-internal-lb-id/inboundNatRules/azure-test1-etcd")},
+										},
+										LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{{ID: to.StringPtr("my-backend-pool-id")}, {ID: to.StringPtr("my-internal-backend-pool-id")}},
+									},
+								},
+							},
+						},
+					})))
+			},
+		},
+		{
+			name:          "control plane network interface in the backend pool of the application gateway successfully created",
+			expectedError: "",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -308,28 +372,57 @@ func TestReconcileNetworkInterface(t *testing.T) {
 						SubnetName:               "my-subnet",
 						VNetName:                 "my-vnet",
 						VNetResourceGroup:        "my-rg",
-						PublicLoadBalancerName:   "my-public-lb",
 						InternalLoadBalancerName: "my-internal-lb",
+						ApplicationGatewayName:   "my-cluster-appgw",
 						VMSize:                   "Standard_D2v2",
-						AcceleratedNetworking:    nil,
+						AcceleratedNetworking:    to.BoolPtr(false),
 					},
 				})
+				s.SubscriptionID().AnyTimes().Return("123")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
-					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").
-						Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")))
+					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").
+						Return(network.Subnet{ID: to.StringPtr("my-subnet-id")}, nil),
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-internal-lb").
+						Return(network.LoadBalancer{
+							ID: pointer.StringPtr("my-internal-lb-id"),
+							LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+								BackendAddressPools: &[]network.BackendAddressPool{
+									{
+										ID: pointer.StringPtr("my-internal-backend-pool-id"),
+									},
+								},
+							}}, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-net-interface", matchers.DiffEq(network.Interface{
+						Location: to.StringPtr("test-location"),
+						InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+							EnableAcceleratedNetworking: to.BoolPtr(false),
+							IPConfigurations: &[]network.InterfaceIPConfiguration{
+								{
+									Name: to.StringPtr("pipConfig"),
+									InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+										Subnet:                          &network.Subnet{ID: to.StringPtr("my-subnet-id")},
+										PrivateIPAllocationMethod:       network.Dynamic,
+										LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{{ID: to.StringPtr("my-internal-backend-pool-id")}},
+										ApplicationGatewayBackendAddressPools: &[]network.ApplicationGatewayBackendAddressPool{
+											{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/applicationGateways/my-cluster-appgw/backendAddressPools/my-cluster-appgw-backendPool")},
+										},
+									},
+								},
+							},
+						},
+					})))
 			},
 		},
 		{
-			name:          "control plane network interface fail to create NAT rule",
-			expectedError: "failed to create NAT rule: #: Internal Server Error: StatusCode=500",
+			name:          "control plane network interface fail to get public LB",
+			expectedError: "failed to get public LB: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -347,54 +440,12 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
-					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(network.LoadBalancer{
-						Name: to.StringPtr("my-public-lb"),
-						ID:   pointer.StringPtr("my-public-lb-id"),
-						LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
-							FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
-								{
-									ID: to.StringPtr("frontend-ip-config-id"),
-								},
-							},
-							BackendAddressPools: &[]network.BackendAddressPool{
-								{
-									ID: pointer.StringPtr("my-backend-pool-id"),
-								},
-							},
-							InboundNatRules: &[]network.InboundNatRule{
-								{
-									Name: pointer.StringPtr("other-machine-nat-rule"),
-									ID:   pointer.StringPtr("some-natrules-id"),
-									InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-										FrontendPort: to.Int32Ptr(22),
-									},
-								},
-								{
-									Name: pointer.StringPtr("other-machine-nat-rule-2"),
-									ID:   pointer.StringPtr("some-natrules-id-2"),
-									InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-										FrontendPort: to.Int32Ptr(2201),
-									},
-								},
-							},
-						}}, nil),
-					mInboundNATRules.CreateOrUpdate(context.TODO(), "my-rg", "my-public-lb", "azure-test1", network.InboundNatRule{
-						Name: pointer.StringPtr("azure-test1"),
-						InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-							FrontendPort:         to.Int32Ptr(2202),
-							BackendPort:          to.Int32Ptr(22),
-							EnableFloatingIP:     to.BoolPtr(false),
-							IdleTimeoutInMinutes: to.Int32Ptr(4),
-							FrontendIPConfiguration: &network.SubResource{
-								ID: to.StringPtr("frontend-ip-config-id"),
-							},
-							Protocol: network.TransportProtocolTCP,
-						},
-					}).
-						Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")))
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").
+						Return(network.LoadBalancer{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")))
 			},
 		},
 		{
@@ -404,7 +455,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -422,6 +472,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -436,7 +487,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -465,6 +515,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -482,7 +533,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -501,6 +551,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -515,7 +566,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -532,6 +582,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -562,7 +613,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -579,6 +629,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -609,7 +660,6 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				m *mock_networkinterfaces.MockClientMockRecorder,
 				mSubnet *mock_subnets.MockClientMockRecorder,
 				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
-				mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder,
 				mPublicIP *mock_publicips.MockClientMockRecorder,
 				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
@@ -626,6 +676,7 @@ func TestReconcileNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("fake-location")
 				gomock.InOrder(
 					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
@@ -635,6 +686,72 @@ func TestReconcileNetworkInterface(t *testing.T) {
 				)
 			},
 		},
+		{
+			name:          "fail to enable accelerated networking on a VM size that does not support it",
+			expectedError: "cannot enable accelerated networking on network interface my-net-interface: VM size Standard_B2s does not support it",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
+				m *mock_networkinterfaces.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder,
+				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{
+					{
+						Name:                   "my-net-interface",
+						MachineName:            "azure-test1",
+						MachineRole:            infrav1.Node,
+						SubnetName:             "my-subnet",
+						VNetName:               "my-vnet",
+						VNetResourceGroup:      "my-rg",
+						PublicLoadBalancerName: "my-public-lb",
+						VMSize:                 "Standard_B2s",
+						AcceleratedNetworking:  to.BoolPtr(true),
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("fake-location")
+				gomock.InOrder(
+					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakeNodeOutboundLoadBalancer(), nil),
+					mResourceSku.HasAcceleratedNetworking(context.TODO(), "Standard_B2s").Return(false, nil),
+				)
+			},
+		},
+		{
+			name:          "network interface with accelerated networking explicitly enabled successfully created",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
+				m *mock_networkinterfaces.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder,
+				mLoadBalancer *mock_loadbalancers.MockClientMockRecorder,
+				mPublicIP *mock_publicips.MockClientMockRecorder,
+				mResourceSku *mock_resourceskus.MockClientMockRecorder) {
+				s.NICSpecs().Return([]azure.NICSpec{
+					{
+						Name:                   "my-net-interface",
+						MachineName:            "azure-test1",
+						MachineRole:            infrav1.Node,
+						SubnetName:             "my-subnet",
+						VNetName:               "my-vnet",
+						VNetResourceGroup:      "my-rg",
+						PublicLoadBalancerName: "my-public-lb",
+						VMSize:                 "Standard_D2v2",
+						AcceleratedNetworking:  to.BoolPtr(true),
+					},
+				})
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("fake-location")
+				gomock.InOrder(
+					mSubnet.Get(context.TODO(), "my-rg", "my-vnet", "my-subnet").Return(network.Subnet{}, nil),
+					mLoadBalancer.Get(context.TODO(), "my-rg", "my-public-lb").Return(getFakeNodeOutboundLoadBalancer(), nil),
+					mResourceSku.HasAcceleratedNetworking(context.TODO(), "Standard_D2v2").Return(true, nil),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-net-interface", gomock.AssignableToTypeOf(network.Interface{})),
+				)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -647,22 +764,20 @@ func TestReconcileNetworkInterface(t *testing.T) {
 			clientMock := mock_networkinterfaces.NewMockClient(mockCtrl)
 			subnetMock := mock_subnets.NewMockClient(mockCtrl)
 			loadBalancerMock := mock_loadbalancers.NewMockClient(mockCtrl)
-			inboundNatRulesMock := mock_inboundnatrules.NewMockClient(mockCtrl)
 			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
 			resourceSkusMock := mock_resourceskus.NewMockClient(mockCtrl)
 
 			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), subnetMock.EXPECT(),
-				loadBalancerMock.EXPECT(), inboundNatRulesMock.EXPECT(), publicIPsMock.EXPECT(),
+				loadBalancerMock.EXPECT(), publicIPsMock.EXPECT(),
 				resourceSkusMock.EXPECT())
 
 			s := &Service{
-				Scope:                 scopeMock,
-				Client:                clientMock,
-				SubnetsClient:         subnetMock,
-				LoadBalancersClient:   loadBalancerMock,
-				InboundNATRulesClient: inboundNatRulesMock,
-				PublicIPsClient:       publicIPsMock,
-				ResourceSkusClient:    resourceSkusMock,
+				Scope:               scopeMock,
+				Client:              clientMock,
+				SubnetsClient:       subnetMock,
+				LoadBalancersClient: loadBalancerMock,
+				PublicIPsClient:     publicIPsMock,
+				ResourceSkusClient:  resourceSkusMock,
 			}
 
 			err := s.Reconcile(context.TODO())
@@ -681,13 +796,13 @@ func TestDeleteNetworkInterface(t *testing.T) {
 		name          string
 		expectedError string
 		expect        func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-			m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
+			m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder)
 	}{
 		{
 			name:          "successfully delete an existing network interface",
 			expectedError: "",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
 					{
 						Name:                   "my-net-interface",
@@ -697,15 +812,15 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(context.TODO(), "my-rg", "my-net-interface")
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
 			},
 		},
 		{
 			name:          "network interface already deleted",
 			expectedError: "",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
 					{
 						Name:                   "my-net-interface",
@@ -715,16 +830,16 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				})
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				m.Delete(context.TODO(), "my-rg", "my-net-interface").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
 			},
 		},
 		{
 			name:          "network interface deletion fails",
 			expectedError: "failed to delete network interface my-net-interface in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
+				m *mock_networkinterfaces.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
 				s.NICSpecs().Return([]azure.NICSpec{
 					{
 						Name:                   "my-net-interface",
@@ -733,49 +848,12 @@ func TestDeleteNetworkInterface(t *testing.T) {
 					},
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
 				m.Delete(context.TODO(), "my-rg", "my-net-interface").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
-		{
-			name:          "NAT rule already deleted",
-			expectedError: "",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.NICSpecs().Return([]azure.NICSpec{
-					{
-						Name:                   "my-net-interface",
-						PublicLoadBalancerName: "my-public-lb",
-						MachineName:            "azure-test1",
-					},
-				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				m.Delete(context.TODO(), "my-rg", "my-net-interface").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1")
-			},
-		},
-		{
-			name:          "NAT rule deletion fails",
-			expectedError: "failed to delete inbound NAT rule azure-test1 in load balancer my-public-lb: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder,
-				m *mock_networkinterfaces.MockClientMockRecorder, mInboundNATRules *mock_inboundnatrules.MockClientMockRecorder, mPublicIP *mock_publicips.MockClientMockRecorder) {
-				s.NICSpecs().Return([]azure.NICSpec{
-					{
-						Name:                   "my-net-interface",
-						PublicLoadBalancerName: "my-public-lb",
-						MachineName:            "azure-test1",
-					},
-				})
-				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
-				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Delete(context.TODO(), "my-rg", "my-net-interface")
-				mInboundNATRules.Delete(context.TODO(), "my-rg", "my-public-lb", "azure-test1").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
-			},
-		},
 	}
 
 	for _, tc := range testcases {
@@ -786,16 +864,14 @@ func TestDeleteNetworkInterface(t *testing.T) {
 			defer mockCtrl.Finish()
 			scopeMock := mock_networkinterfaces.NewMockNICScope(mockCtrl)
 			clientMock := mock_networkinterfaces.NewMockClient(mockCtrl)
-			inboundNatRulesMock := mock_inboundnatrules.NewMockClient(mockCtrl)
 			publicIPMock := mock_publicips.NewMockClient(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), inboundNatRulesMock.EXPECT(), publicIPMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), publicIPMock.EXPECT())
 
 			s := &Service{
-				Scope:                 scopeMock,
-				Client:                clientMock,
-				InboundNATRulesClient: inboundNatRulesMock,
-				PublicIPsClient:       publicIPMock,
+				Scope:           scopeMock,
+				Client:          clientMock,
+				PublicIPsClient: publicIPMock,
 			}
 
 			err := s.Delete(context.TODO())
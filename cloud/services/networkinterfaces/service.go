@@ -19,7 +19,6 @@ package networkinterfaces
 import (
 	"github.com/go-logr/logr"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
-	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/resourceskus"
@@ -37,22 +36,20 @@ type NICScope interface {
 type Service struct {
 	Scope NICScope
 	Client
-	SubnetsClient         subnets.Client
-	LoadBalancersClient   loadbalancers.Client
-	PublicIPsClient       publicips.Client
-	InboundNATRulesClient inboundnatrules.Client
-	ResourceSkusClient    resourceskus.Client
+	SubnetsClient       subnets.Client
+	LoadBalancersClient loadbalancers.Client
+	PublicIPsClient     publicips.Client
+	ResourceSkusClient  resourceskus.Client
 }
 
 // NewService creates a new service.
 func NewService(scope NICScope) *Service {
 	return &Service{
-		Scope:                 scope,
-		Client:                NewClient(scope),
-		SubnetsClient:         subnets.NewClient(scope),
-		LoadBalancersClient:   loadbalancers.NewClient(scope),
-		PublicIPsClient:       publicips.NewClient(scope),
-		InboundNATRulesClient: inboundnatrules.NewClient(scope),
-		ResourceSkusClient:    resourceskus.NewClient(scope),
+		Scope:               scope,
+		Client:              NewClient(scope),
+		SubnetsClient:       subnets.NewClient(scope),
+		LoadBalancersClient: loadbalancers.NewClient(scope),
+		PublicIPsClient:     publicips.NewClient(scope),
+		ResourceSkusClient:  resourceskus.NewClient(scope),
 	}
 }
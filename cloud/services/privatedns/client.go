@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatedns
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	GetZone(context.Context, string, string) (privatedns.PrivateZone, error)
+	CreateOrUpdateZone(context.Context, string, string, privatedns.PrivateZone) error
+	DeleteZone(context.Context, string, string) error
+	CreateOrUpdateLink(context.Context, string, string, string, privatedns.VirtualNetworkLink) error
+	DeleteLink(context.Context, string, string, string) error
+	CreateOrUpdateRecordSet(context.Context, string, string, privatedns.RecordType, string, privatedns.RecordSet) error
+	DeleteRecordSet(context.Context, string, string, privatedns.RecordType, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	privatezones privatedns.PrivateZonesClient
+	vnetlinks    privatedns.VirtualNetworkLinksClient
+	recordsets   privatedns.RecordSetsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new private DNS client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		privatezones: newPrivateZonesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		vnetlinks:    newVirtualNetworkLinksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		recordsets:   newRecordSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newPrivateZonesClient creates a new private zones client from subscription ID.
+func newPrivateZonesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.PrivateZonesClient {
+	zonesClient := privatedns.NewPrivateZonesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&zonesClient.Client, authorizer)
+	return zonesClient
+}
+
+// newVirtualNetworkLinksClient creates a new virtual network links client from subscription ID.
+func newVirtualNetworkLinksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.VirtualNetworkLinksClient {
+	linksClient := privatedns.NewVirtualNetworkLinksClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&linksClient.Client, authorizer)
+	return linksClient
+}
+
+// newRecordSetsClient creates a new record sets client from subscription ID.
+func newRecordSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) privatedns.RecordSetsClient {
+	recordsClient := privatedns.NewRecordSetsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&recordsClient.Client, authorizer)
+	return recordsClient
+}
+
+// GetZone gets the specified private DNS zone in a specified resource group.
+func (ac *AzureClient) GetZone(ctx context.Context, resourceGroupName, zoneName string) (privatedns.PrivateZone, error) {
+	return ac.privatezones.Get(ctx, resourceGroupName, zoneName)
+}
+
+// CreateOrUpdateZone creates or updates a private DNS zone.
+func (ac *AzureClient) CreateOrUpdateZone(ctx context.Context, resourceGroupName string, zoneName string, zone privatedns.PrivateZone) error {
+	future, err := ac.privatezones.CreateOrUpdate(ctx, resourceGroupName, zoneName, zone, "", "")
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privatezones.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privatezones)
+	return err
+}
+
+// DeleteZone deletes the specified private DNS zone.
+func (ac *AzureClient) DeleteZone(ctx context.Context, resourceGroupName, zoneName string) error {
+	future, err := ac.privatezones.Delete(ctx, resourceGroupName, zoneName, "")
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privatezones.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privatezones)
+	return err
+}
+
+// CreateOrUpdateLink creates or updates a virtual network link to the specified private DNS zone.
+func (ac *AzureClient) CreateOrUpdateLink(ctx context.Context, resourceGroupName, zoneName, linkName string, link privatedns.VirtualNetworkLink) error {
+	future, err := ac.vnetlinks.CreateOrUpdate(ctx, resourceGroupName, zoneName, linkName, link, "", "")
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.vnetlinks.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.vnetlinks)
+	return err
+}
+
+// DeleteLink deletes a virtual network link to the specified private DNS zone.
+func (ac *AzureClient) DeleteLink(ctx context.Context, resourceGroupName, zoneName, linkName string) error {
+	future, err := ac.vnetlinks.Delete(ctx, resourceGroupName, zoneName, linkName, "")
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.vnetlinks.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.vnetlinks)
+	return err
+}
+
+// CreateOrUpdateRecordSet creates or updates a record set within the specified private DNS zone.
+func (ac *AzureClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName, zoneName string, recordType privatedns.RecordType, name string, set privatedns.RecordSet) error {
+	_, err := ac.recordsets.CreateOrUpdate(ctx, resourceGroupName, zoneName, recordType, name, set, "", "")
+	return err
+}
+
+// DeleteRecordSet deletes a record set from the specified private DNS zone.
+func (ac *AzureClient) DeleteRecordSet(ctx context.Context, resourceGroupName, zoneName string, recordType privatedns.RecordType, name string) error {
+	_, err := ac.recordsets.Delete(ctx, resourceGroupName, zoneName, recordType, name, "")
+	return err
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatedns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+const (
+	// privateDNSLocation is the location of private DNS zones and their links, which are global resources.
+	privateDNSLocation = "global"
+	// recordTTL is the time to live of the API server record, in seconds.
+	recordTTL = 300
+)
+
+// Reconcile creates or updates the private DNS zone, its link to the cluster vnet and the API server record.
+func (s *Service) Reconcile(ctx context.Context) error {
+	spec := s.Scope.PrivateDNSSpec()
+	if spec == nil {
+		return nil
+	}
+	if spec.RecordIP == "" {
+		return errors.Errorf("cannot create record %s in private DNS zone %s: the internal load balancer IP is not known yet", spec.RecordName, spec.ZoneName)
+	}
+
+	_, err := s.Client.GetZone(ctx, s.Scope.ResourceGroup(), spec.ZoneName)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get private DNS zone %s in resource group %s", spec.ZoneName, s.Scope.ResourceGroup())
+	case err != nil:
+		s.Scope.V(2).Info("creating private DNS zone", "private dns zone", spec.ZoneName)
+		err = s.Client.CreateOrUpdateZone(ctx, s.Scope.ResourceGroup(), spec.ZoneName, privatedns.PrivateZone{
+			Location: to.StringPtr(privateDNSLocation),
+			Tags:     s.ownedTags(spec.ZoneName),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create private DNS zone %s", spec.ZoneName)
+		}
+		s.Scope.V(2).Info("successfully created private DNS zone", "private dns zone", spec.ZoneName)
+	default:
+		// the zone already exists, it may have been provided by the user so it is left as is
+		s.Scope.V(2).Info("private DNS zone already exists", "private dns zone", spec.ZoneName)
+	}
+
+	s.Scope.V(2).Info("creating virtual network link", "virtual network link", spec.LinkName, "private dns zone", spec.ZoneName)
+	err = s.Client.CreateOrUpdateLink(ctx, s.Scope.ResourceGroup(), spec.ZoneName, spec.LinkName, privatedns.VirtualNetworkLink{
+		Location: to.StringPtr(privateDNSLocation),
+		Tags:     s.ownedTags(spec.LinkName),
+		VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+			VirtualNetwork: &privatedns.SubResource{
+				ID: to.StringPtr(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s",
+					s.Scope.SubscriptionID(), spec.VNetResourceGroup, spec.VNetName)),
+			},
+			RegistrationEnabled: to.BoolPtr(false),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create virtual network link %s in private DNS zone %s", spec.LinkName, spec.ZoneName)
+	}
+	s.Scope.V(2).Info("successfully created virtual network link", "virtual network link", spec.LinkName, "private dns zone", spec.ZoneName)
+
+	s.Scope.V(2).Info("creating record", "record", spec.RecordName, "private dns zone", spec.ZoneName)
+	err = s.Client.CreateOrUpdateRecordSet(ctx, s.Scope.ResourceGroup(), spec.ZoneName, privatedns.A, spec.RecordName, privatedns.RecordSet{
+		RecordSetProperties: &privatedns.RecordSetProperties{
+			TTL: to.Int64Ptr(recordTTL),
+			ARecords: &[]privatedns.ARecord{
+				{Ipv4Address: to.StringPtr(spec.RecordIP)},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create record %s in private DNS zone %s", spec.RecordName, spec.ZoneName)
+	}
+	s.Scope.V(2).Info("successfully created record", "record", spec.RecordName, "private dns zone", spec.ZoneName)
+
+	return nil
+}
+
+// Delete deletes the API server record and the virtual network link, and the private DNS zone if it is owned by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	spec := s.Scope.PrivateDNSSpec()
+	if spec == nil {
+		return nil
+	}
+
+	zone, err := s.Client.GetZone(ctx, s.Scope.ResourceGroup(), spec.ZoneName)
+	if azure.ResourceNotFound(err) {
+		// the zone and everything in it are already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get private DNS zone %s in resource group %s", spec.ZoneName, s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("deleting record", "record", spec.RecordName, "private dns zone", spec.ZoneName)
+	err = s.Client.DeleteRecordSet(ctx, s.Scope.ResourceGroup(), spec.ZoneName, privatedns.A, spec.RecordName)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete record %s in private DNS zone %s", spec.RecordName, spec.ZoneName)
+	}
+
+	s.Scope.V(2).Info("deleting virtual network link", "virtual network link", spec.LinkName, "private dns zone", spec.ZoneName)
+	err = s.Client.DeleteLink(ctx, s.Scope.ResourceGroup(), spec.ZoneName, spec.LinkName)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete virtual network link %s in private DNS zone %s", spec.LinkName, spec.ZoneName)
+	}
+
+	if !converters.MapToTags(zone.Tags).HasOwned(s.Scope.ClusterName()) {
+		s.Scope.V(4).Info("Skipping deletion of private DNS zone not owned by the cluster", "private dns zone", spec.ZoneName)
+		return nil
+	}
+
+	s.Scope.V(2).Info("deleting private DNS zone", "private dns zone", spec.ZoneName)
+	err = s.Client.DeleteZone(ctx, s.Scope.ResourceGroup(), spec.ZoneName)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete private DNS zone %s in resource group %s", spec.ZoneName, s.Scope.ResourceGroup())
+	}
+	s.Scope.V(2).Info("successfully deleted private DNS zone", "private dns zone", spec.ZoneName)
+
+	return nil
+}
+
+func (s *Service) ownedTags(name string) map[string]*string {
+	return converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(name),
+		Additional:  s.Scope.AdditionalTags(),
+	}))
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatedns
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatedns/mock_privatedns"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
+)
+
+var privateDNSSpec = &azure.PrivateDNSSpec{
+	ZoneName:          "my-cluster.capz.io",
+	LinkName:          "my-vnet-link",
+	VNetName:          "my-vnet",
+	VNetResourceGroup: "vnet-rg",
+	RecordName:        "apiserver",
+	RecordIP:          "10.0.0.100",
+}
+
+var notFound = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+
+func TestReconcilePrivateDNS(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder)
+	}{
+		{
+			name:          "no private DNS zone for a public cluster",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateDNSSpec().Return(nil)
+			},
+		},
+		{
+			name:          "zone, link and record are created",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{}, notFound)
+				m.CreateOrUpdateZone(context.TODO(), "my-rg", "my-cluster.capz.io", matchers.DiffEq(privatedns.PrivateZone{
+					Location: to.StringPtr("global"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-cluster.capz.io"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+				}))
+				m.CreateOrUpdateLink(context.TODO(), "my-rg", "my-cluster.capz.io", "my-vnet-link", matchers.DiffEq(privatedns.VirtualNetworkLink{
+					Location: to.StringPtr("global"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-vnet-link"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+						VirtualNetwork: &privatedns.SubResource{
+							ID: to.StringPtr("/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+						},
+						RegistrationEnabled: to.BoolPtr(false),
+					},
+				}))
+				m.CreateOrUpdateRecordSet(context.TODO(), "my-rg", "my-cluster.capz.io", privatedns.A, "apiserver", matchers.DiffEq(privatedns.RecordSet{
+					RecordSetProperties: &privatedns.RecordSetProperties{
+						TTL:      to.Int64Ptr(300),
+						ARecords: &[]privatedns.ARecord{{Ipv4Address: to.StringPtr("10.0.0.100")}},
+					},
+				}))
+			},
+		},
+		{
+			name:          "existing zone is reused",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{Name: to.StringPtr("my-cluster.capz.io")}, nil)
+				m.CreateOrUpdateLink(context.TODO(), "my-rg", "my-cluster.capz.io", "my-vnet-link", gomock.AssignableToTypeOf(privatedns.VirtualNetworkLink{}))
+				m.CreateOrUpdateRecordSet(context.TODO(), "my-rg", "my-cluster.capz.io", privatedns.A, "apiserver", gomock.AssignableToTypeOf(privatedns.RecordSet{}))
+			},
+		},
+		{
+			name:          "internal load balancer IP is not known yet",
+			expectedError: "cannot create record apiserver in private DNS zone my-cluster.capz.io: the internal load balancer IP is not known yet",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateDNSSpec().Return(&azure.PrivateDNSSpec{
+					ZoneName:   "my-cluster.capz.io",
+					RecordName: "apiserver",
+				})
+			},
+		},
+		{
+			name:          "fail to create the zone",
+			expectedError: "failed to create private DNS zone my-cluster.capz.io: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{}, notFound)
+				m.CreateOrUpdateZone(context.TODO(), "my-rg", "my-cluster.capz.io", gomock.AssignableToTypeOf(privatedns.PrivateZone{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name:          "fail to create the record",
+			expectedError: "failed to create record apiserver in private DNS zone my-cluster.capz.io: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{}, nil)
+				m.CreateOrUpdateLink(context.TODO(), "my-rg", "my-cluster.capz.io", "my-vnet-link", gomock.AssignableToTypeOf(privatedns.VirtualNetworkLink{}))
+				m.CreateOrUpdateRecordSet(context.TODO(), "my-rg", "my-cluster.capz.io", privatedns.A, "apiserver", gomock.AssignableToTypeOf(privatedns.RecordSet{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privatedns.NewMockPrivateDNSScope(mockCtrl)
+			clientMock := mock_privatedns.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePrivateDNS(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder)
+	}{
+		{
+			name:          "owned zone is deleted with its link and record",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				m.DeleteRecordSet(context.TODO(), "my-rg", "my-cluster.capz.io", privatedns.A, "apiserver")
+				m.DeleteLink(context.TODO(), "my-rg", "my-cluster.capz.io", "my-vnet-link")
+				m.DeleteZone(context.TODO(), "my-rg", "my-cluster.capz.io")
+			},
+		},
+		{
+			name:          "user-provided zone is not deleted",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{}, nil)
+				m.DeleteRecordSet(context.TODO(), "my-rg", "my-cluster.capz.io", privatedns.A, "apiserver")
+				m.DeleteLink(context.TODO(), "my-rg", "my-cluster.capz.io", "my-vnet-link").Return(notFound)
+			},
+		},
+		{
+			name:          "zone is already deleted",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{}, notFound)
+			},
+		},
+		{
+			name:          "fail to delete the link",
+			expectedError: "failed to delete virtual network link my-vnet-link in private DNS zone my-cluster.capz.io: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privatedns.MockPrivateDNSScopeMockRecorder, m *mock_privatedns.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateDNSSpec().Return(privateDNSSpec)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.GetZone(context.TODO(), "my-rg", "my-cluster.capz.io").Return(privatedns.PrivateZone{}, nil)
+				m.DeleteRecordSet(context.TODO(), "my-rg", "my-cluster.capz.io", privatedns.A, "apiserver")
+				m.DeleteLink(context.TODO(), "my-rg", "my-cluster.capz.io", "my-vnet-link").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privatedns.NewMockPrivateDNSScope(mockCtrl)
+			clientMock := mock_privatedns.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
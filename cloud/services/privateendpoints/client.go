@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (network.PrivateEndpoint, error)
+	CreateOrUpdate(context.Context, string, string, network.PrivateEndpoint) error
+	Delete(context.Context, string, string) error
+	CreateOrUpdateDNSZoneGroup(context.Context, string, string, string, network.PrivateDNSZoneGroup) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	privateendpoints network.PrivateEndpointsClient
+	dnszonegroups    network.PrivateDNSZoneGroupsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new private endpoints client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		privateendpoints: newPrivateEndpointsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		dnszonegroups:    newPrivateDNSZoneGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newPrivateEndpointsClient creates a new private endpoints client from subscription ID.
+func newPrivateEndpointsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PrivateEndpointsClient {
+	privateEndpointsClient := network.NewPrivateEndpointsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&privateEndpointsClient.Client, authorizer)
+	return privateEndpointsClient
+}
+
+// newPrivateDNSZoneGroupsClient creates a new private DNS zone groups client from subscription ID.
+func newPrivateDNSZoneGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PrivateDNSZoneGroupsClient {
+	dnsZoneGroupsClient := network.NewPrivateDNSZoneGroupsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&dnsZoneGroupsClient.Client, authorizer)
+	return dnsZoneGroupsClient
+}
+
+// Get gets the specified private endpoint in a specified resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, privateEndpointName string) (network.PrivateEndpoint, error) {
+	return ac.privateendpoints.Get(ctx, resourceGroupName, privateEndpointName, "")
+}
+
+// CreateOrUpdate creates or updates a private endpoint.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, privateEndpointName string, privateEndpoint network.PrivateEndpoint) error {
+	future, err := ac.privateendpoints.CreateOrUpdate(ctx, resourceGroupName, privateEndpointName, privateEndpoint)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privateendpoints.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privateendpoints)
+	return err
+}
+
+// Delete deletes the specified private endpoint, along with its private DNS zone groups.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, privateEndpointName string) error {
+	future, err := ac.privateendpoints.Delete(ctx, resourceGroupName, privateEndpointName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privateendpoints.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privateendpoints)
+	return err
+}
+
+// CreateOrUpdateDNSZoneGroup creates or updates a private DNS zone group of a private endpoint.
+func (ac *AzureClient) CreateOrUpdateDNSZoneGroup(ctx context.Context, resourceGroupName, privateEndpointName, groupName string, group network.PrivateDNSZoneGroup) error {
+	future, err := ac.dnszonegroups.CreateOrUpdate(ctx, resourceGroupName, privateEndpointName, groupName, group)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.dnszonegroups.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.dnszonegroups)
+	return err
+}
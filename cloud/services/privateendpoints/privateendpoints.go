@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+	"strings"
+
+	network201906 "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// privateDNSZoneGroupName is the name of the private DNS zone group of each private endpoint.
+const privateDNSZoneGroupName = "default"
+
+// Reconcile creates or updates the private endpoints of the cluster in their subnets, and the private DNS zone
+// groups registering their private IPs in the private DNS zones.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, endpointSpec := range s.Scope.PrivateEndpointSpecs() {
+		s.Scope.V(2).Info("creating private endpoint", "private endpoint", endpointSpec.Name)
+		subnet, err := s.SubnetsClient.Get(ctx, s.Scope.Vnet().ResourceGroup, endpointSpec.VnetName, endpointSpec.SubnetName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get subnet %s for private endpoint %s", endpointSpec.SubnetName, endpointSpec.Name)
+		}
+		if err := s.prepareSubnet(ctx, endpointSpec, subnet); err != nil {
+			return err
+		}
+
+		err = s.Client.CreateOrUpdate(
+			ctx,
+			s.Scope.NetworkResourceGroup(),
+			endpointSpec.Name,
+			network.PrivateEndpoint{
+				Name:     to.StringPtr(endpointSpec.Name),
+				Location: to.StringPtr(s.Scope.Location()),
+				Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+					ClusterName: s.Scope.ClusterName(),
+					Lifecycle:   infrav1.ResourceLifecycleOwned,
+					Name:        to.StringPtr(endpointSpec.Name),
+					Additional:  s.Scope.AdditionalTags(),
+				})),
+				PrivateEndpointProperties: &network.PrivateEndpointProperties{
+					Subnet: &network.Subnet{ID: subnet.ID},
+					PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+						{
+							Name: to.StringPtr(endpointSpec.Name),
+							PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+								PrivateLinkServiceID: to.StringPtr(endpointSpec.PrivateLinkServiceID),
+								GroupIds:             to.StringSlicePtr(endpointSpec.GroupIDs),
+							},
+						},
+					},
+				},
+			},
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create private endpoint %s in resource group %s", endpointSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+
+		if len(endpointSpec.PrivateDNSZoneIDs) > 0 {
+			err = s.Client.CreateOrUpdateDNSZoneGroup(ctx, s.Scope.NetworkResourceGroup(), endpointSpec.Name, privateDNSZoneGroupName,
+				network.PrivateDNSZoneGroup{
+					Name: to.StringPtr(privateDNSZoneGroupName),
+					PrivateDNSZoneGroupPropertiesFormat: &network.PrivateDNSZoneGroupPropertiesFormat{
+						PrivateDNSZoneConfigs: privateDNSZoneConfigs(endpointSpec.PrivateDNSZoneIDs),
+					},
+				})
+			if err != nil {
+				return errors.Wrapf(err, "failed to create private DNS zone group of private endpoint %s in resource group %s", endpointSpec.Name, s.Scope.NetworkResourceGroup())
+			}
+		}
+		s.Scope.V(2).Info("successfully created private endpoint", "private endpoint", endpointSpec.Name)
+	}
+	return nil
+}
+
+// prepareSubnet checks that private endpoints can be created in the subnet: it can't be delegated to a service, and
+// its private endpoint network policies must be disabled. They are disabled on the subnets of a vnet created by the
+// provider, while a pre-existing vnet is expected to have them disabled already.
+func (s *Service) prepareSubnet(ctx context.Context, endpointSpec azure.PrivateEndpointSpec, subnet network201906.Subnet) error {
+	if subnet.SubnetPropertiesFormat == nil {
+		subnet.SubnetPropertiesFormat = &network201906.SubnetPropertiesFormat{}
+	}
+	if subnet.Delegations != nil && len(*subnet.Delegations) > 0 {
+		var services []string
+		for _, delegation := range *subnet.Delegations {
+			if delegation.ServiceDelegationPropertiesFormat != nil {
+				services = append(services, to.String(delegation.ServiceName))
+			}
+		}
+		return errors.Errorf("subnet %s of private endpoint %s is delegated to %s, private endpoints can't be created in a delegated subnet",
+			endpointSpec.SubnetName, endpointSpec.Name, strings.Join(services, ", "))
+	}
+	if strings.EqualFold(to.String(subnet.PrivateEndpointNetworkPolicies), "Disabled") {
+		return nil
+	}
+	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
+		return errors.Errorf("subnet %s of private endpoint %s must have its private endpoint network policies disabled",
+			endpointSpec.SubnetName, endpointSpec.Name)
+	}
+
+	s.Scope.V(2).Info("disabling private endpoint network policies of subnet", "subnet", endpointSpec.SubnetName)
+	subnet.PrivateEndpointNetworkPolicies = to.StringPtr("Disabled")
+	if err := s.SubnetsClient.CreateOrUpdate(ctx, s.Scope.Vnet().ResourceGroup, endpointSpec.VnetName, endpointSpec.SubnetName, subnet); err != nil {
+		return errors.Wrapf(err, "failed to disable private endpoint network policies of subnet %s in resource group %s", endpointSpec.SubnetName, s.Scope.Vnet().ResourceGroup)
+	}
+	return nil
+}
+
+// privateDNSZoneConfigs returns the configurations of the private DNS zones of a private DNS zone group, named after
+// the zones.
+func privateDNSZoneConfigs(zoneIDs []string) *[]network.PrivateDNSZoneConfig {
+	configs := make([]network.PrivateDNSZoneConfig, 0, len(zoneIDs))
+	for _, zoneID := range zoneIDs {
+		name := zoneID
+		if zone, err := autorestazure.ParseResourceID(zoneID); err == nil {
+			name = zone.ResourceName
+		}
+		configs = append(configs, network.PrivateDNSZoneConfig{
+			Name: to.StringPtr(strings.ReplaceAll(name, ".", "-")),
+			PrivateDNSZonePropertiesFormat: &network.PrivateDNSZonePropertiesFormat{
+				PrivateDNSZoneID: to.StringPtr(zoneID),
+			},
+		})
+	}
+	return &configs
+}
+
+// Delete deletes the private endpoints of the cluster, along with their private DNS zone groups. They must be
+// deleted before their subnets.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, endpointSpec := range s.Scope.PrivateEndpointSpecs() {
+		if !s.Scope.IsNetworkResourceGroupManaged() {
+			// only delete the private endpoints owned by the cluster from a pre-existing resource group
+			privateEndpoint, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), endpointSpec.Name)
+			if azure.ResourceNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to get private endpoint %s in resource group %s", endpointSpec.Name, s.Scope.NetworkResourceGroup())
+			}
+			if !converters.MapToTags(privateEndpoint.Tags).HasOwned(s.Scope.ClusterName()) {
+				s.Scope.V(4).Info("Skipping deletion of private endpoint not owned by the cluster", "private endpoint", endpointSpec.Name)
+				continue
+			}
+		}
+
+		s.Scope.V(2).Info("deleting private endpoint", "private endpoint", endpointSpec.Name)
+		err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), endpointSpec.Name)
+		if err != nil && azure.ResourceNotFound(err) {
+			// already deleted
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete private endpoint %s in resource group %s", endpointSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+		s.Scope.V(2).Info("successfully deleted private endpoint", "private endpoint", endpointSpec.Name)
+	}
+	return nil
+}
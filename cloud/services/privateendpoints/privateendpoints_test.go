@@ -0,0 +1,316 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoints
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	network201906 "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privateendpoints/mock_privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
+)
+
+var fakePrivateEndpointSpecs = []azure.PrivateEndpointSpec{
+	{
+		Name:                 "my-vault-pe",
+		SubnetName:           "my-subnet-node",
+		VnetName:             "my-vnet",
+		PrivateLinkServiceID: "/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.KeyVault/vaults/my-vault",
+		GroupIDs:             []string{"vault"},
+		PrivateDNSZoneIDs:    []string{"/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.Network/privateDnsZones/privatelink.vaultcore.azure.net"},
+	},
+}
+
+func TestReconcilePrivateEndpoints(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder)
+	}{
+		{
+			name:          "no private endpoints",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.PrivateEndpointSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "private endpoint is created in its subnet with its private DNS zone group",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-node").Return(fakeSubnet("Disabled"), nil)
+				gomock.InOrder(
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-vault-pe", matchers.DiffEq(network.PrivateEndpoint{
+						Name:     to.StringPtr("my-vault-pe"),
+						Location: to.StringPtr("testlocation"),
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"Name": to.StringPtr("my-vault-pe"),
+						},
+						PrivateEndpointProperties: &network.PrivateEndpointProperties{
+							Subnet: &network.Subnet{ID: to.StringPtr("subnet-id")},
+							PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{
+								{
+									Name: to.StringPtr("my-vault-pe"),
+									PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+										PrivateLinkServiceID: to.StringPtr("/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.KeyVault/vaults/my-vault"),
+										GroupIds:             &[]string{"vault"},
+									},
+								},
+							},
+						},
+					})),
+					m.CreateOrUpdateDNSZoneGroup(context.TODO(), "my-rg", "my-vault-pe", "default", matchers.DiffEq(network.PrivateDNSZoneGroup{
+						Name: to.StringPtr("default"),
+						PrivateDNSZoneGroupPropertiesFormat: &network.PrivateDNSZoneGroupPropertiesFormat{
+							PrivateDNSZoneConfigs: &[]network.PrivateDNSZoneConfig{
+								{
+									Name: to.StringPtr("privatelink-vaultcore-azure-net"),
+									PrivateDNSZonePropertiesFormat: &network.PrivateDNSZonePropertiesFormat{
+										PrivateDNSZoneID: to.StringPtr("/subscriptions/123/resourceGroups/shared-rg/providers/Microsoft.Network/privateDnsZones/privatelink.vaultcore.azure.net"),
+									},
+								},
+							},
+						},
+					})),
+				)
+			},
+		},
+		{
+			name:          "private endpoint network policies are disabled on the subnet of a managed vnet",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-node").Return(fakeSubnet("Enabled"), nil)
+				gomock.InOrder(
+					mSubnet.CreateOrUpdate(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-node", matchers.DiffEq(fakeSubnet("Disabled"))),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-vault-pe", gomock.AssignableToTypeOf(network.PrivateEndpoint{})),
+					m.CreateOrUpdateDNSZoneGroup(context.TODO(), "my-rg", "my-vault-pe", "default", gomock.AssignableToTypeOf(network.PrivateDNSZoneGroup{})),
+				)
+			},
+		},
+		{
+			name:          "private endpoint network policies are enabled on the subnet of a pre-existing vnet",
+			expectedError: "subnet my-subnet-node of private endpoint my-vault-pe must have its private endpoint network policies disabled",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{ID: "my-vnet-id", Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-node").Return(network201906.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+			},
+		},
+		{
+			name:          "subnet of the private endpoint is delegated",
+			expectedError: "subnet my-subnet-node of private endpoint my-vault-pe is delegated to Microsoft.ContainerInstance/containerGroups, private endpoints can't be created in a delegated subnet",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				subnet := fakeSubnet("Disabled")
+				subnet.Delegations = &[]network201906.Delegation{
+					{
+						Name: to.StringPtr("aci"),
+						ServiceDelegationPropertiesFormat: &network201906.ServiceDelegationPropertiesFormat{
+							ServiceName: to.StringPtr("Microsoft.ContainerInstance/containerGroups"),
+						},
+					},
+				}
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-node").Return(subnet, nil)
+			},
+		},
+		{
+			name:          "fail to get the subnet of the private endpoint",
+			expectedError: "failed to get subnet my-subnet-node for private endpoint my-vault-pe: #: Not found: StatusCode=404",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-node").Return(network201906.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "fail to create the private endpoint",
+			expectedError: "failed to create private endpoint my-vault-pe in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-node").Return(fakeSubnet("Disabled"), nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vault-pe", gomock.AssignableToTypeOf(network.PrivateEndpoint{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privateendpoints.NewMockPrivateEndpointScope(mockCtrl)
+			clientMock := mock_privateendpoints.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), subnetsMock.EXPECT())
+
+			s := &Service{
+				Scope:         scopeMock,
+				Client:        clientMock,
+				SubnetsClient: subnetsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePrivateEndpoints(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete the private endpoint",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-vault-pe")
+			},
+		},
+		{
+			name:          "private endpoint already deleted",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-vault-pe").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "skip private endpoint not owned by the cluster in a pre-existing resource group",
+			expectedError: "",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(false)
+				m.Get(context.TODO(), "my-rg", "my-vault-pe").Return(network.PrivateEndpoint{}, nil)
+			},
+		},
+		{
+			name:          "private endpoint deletion fails",
+			expectedError: "failed to delete private endpoint my-vault-pe in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privateendpoints.MockPrivateEndpointScopeMockRecorder, m *mock_privateendpoints.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateEndpointSpecs().Return(fakePrivateEndpointSpecs)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-vault-pe").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privateendpoints.NewMockPrivateEndpointScope(mockCtrl)
+			clientMock := mock_privateendpoints.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func fakeSubnet(privateEndpointNetworkPolicies string) network201906.Subnet {
+	return network201906.Subnet{
+		ID: to.StringPtr("subnet-id"),
+		SubnetPropertiesFormat: &network201906.SubnetPropertiesFormat{
+			AddressPrefix:                  to.StringPtr("10.1.0.0/16"),
+			PrivateEndpointNetworkPolicies: to.StringPtr(privateEndpointNetworkPolicies),
+		},
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatelinkservices
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (network.PrivateLinkService, error)
+	CreateOrUpdate(context.Context, string, string, network.PrivateLinkService) (network.PrivateLinkService, error)
+	Delete(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	privatelinkservices network.PrivateLinkServicesClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new private link services client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		privatelinkservices: newPrivateLinkServicesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newPrivateLinkServicesClient creates a new private link services client from subscription ID.
+func newPrivateLinkServicesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PrivateLinkServicesClient {
+	privateLinkServicesClient := network.NewPrivateLinkServicesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&privateLinkServicesClient.Client, authorizer)
+	return privateLinkServicesClient
+}
+
+// Get gets the specified private link service in a specified resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, serviceName string) (network.PrivateLinkService, error) {
+	return ac.privatelinkservices.Get(ctx, resourceGroupName, serviceName, "")
+}
+
+// CreateOrUpdate creates or updates a private link service, and returns it with its alias.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, serviceName string, service network.PrivateLinkService) (network.PrivateLinkService, error) {
+	future, err := ac.privatelinkservices.CreateOrUpdate(ctx, resourceGroupName, serviceName, service)
+	if err != nil {
+		return network.PrivateLinkService{}, err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privatelinkservices.Client)
+	if err != nil {
+		return network.PrivateLinkService{}, err
+	}
+	return future.Result(ac.privatelinkservices)
+}
+
+// Delete deletes the specified private link service, along with the connections of its private endpoints.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, serviceName string) error {
+	future, err := ac.privatelinkservices.Delete(ctx, resourceGroupName, serviceName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.privatelinkservices.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.privatelinkservices)
+	return err
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatelinkservices
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	network201906 "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Reconcile gets/creates/updates the private link service on the frontend of the internal API server load balancer,
+// and records its alias. A private link service removed from the spec is deleted.
+func (s *Service) Reconcile(ctx context.Context) error {
+	plsSpec := s.Scope.PrivateLinkServiceSpec()
+	if plsSpec == nil {
+		if s.Scope.Network().PrivateLinkServiceAlias == "" {
+			return nil
+		}
+		if err := s.Delete(ctx); err != nil {
+			return err
+		}
+		s.Scope.SetPrivateLinkServiceAlias("")
+		return nil
+	}
+
+	s.Scope.V(2).Info("creating private link service", "private link service", plsSpec.Name)
+	subnet, err := s.SubnetsClient.Get(ctx, s.Scope.Vnet().ResourceGroup, plsSpec.VnetName, plsSpec.SubnetName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get subnet %s for private link service %s", plsSpec.SubnetName, plsSpec.Name)
+	}
+	if err := s.prepareSubnet(ctx, *plsSpec, subnet); err != nil {
+		return err
+	}
+
+	tags := converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(plsSpec.Name),
+		Additional:  s.Scope.AdditionalTags(),
+	}))
+	existing, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), plsSpec.Name)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get private link service %s in resource group %s", plsSpec.Name, s.Scope.NetworkResourceGroup())
+	case err == nil:
+		if !converters.MapToTags(existing.Tags).HasOwned(s.Scope.ClusterName()) {
+			return errors.Errorf("private link service %s already exists in resource group %s and isn't owned by cluster %s",
+				plsSpec.Name, s.Scope.NetworkResourceGroup(), s.Scope.ClusterName())
+		}
+		// keep the tags set out-of-band on the existing private link service
+		tags, _ = converters.UpdateTags(existing.Tags, converters.MapToTags(tags), s.Scope.LastAppliedTags())
+	}
+
+	frontendID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s/frontendIPConfigurations/%s-frontEnd",
+		s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), plsSpec.LoadBalancerName, plsSpec.LoadBalancerName)
+	pls, err := s.Client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), plsSpec.Name, network.PrivateLinkService{
+		Name:     to.StringPtr(plsSpec.Name),
+		Location: to.StringPtr(s.Scope.Location()),
+		Tags:     tags,
+		PrivateLinkServiceProperties: &network.PrivateLinkServiceProperties{
+			LoadBalancerFrontendIPConfigurations: &[]network.FrontendIPConfiguration{{ID: to.StringPtr(frontendID)}},
+			IPConfigurations: &[]network.PrivateLinkServiceIPConfiguration{
+				{
+					Name: to.StringPtr(plsSpec.Name + "-ipconfig"),
+					PrivateLinkServiceIPConfigurationProperties: &network.PrivateLinkServiceIPConfigurationProperties{
+						Subnet:                    &network.Subnet{ID: subnet.ID},
+						PrivateIPAllocationMethod: network.Dynamic,
+						Primary:                   to.BoolPtr(true),
+					},
+				},
+			},
+			Visibility: &network.PrivateLinkServicePropertiesVisibility{
+				Subscriptions: to.StringSlicePtr(plsSpec.VisibilitySubscriptions),
+			},
+			AutoApproval: &network.PrivateLinkServicePropertiesAutoApproval{
+				Subscriptions: to.StringSlicePtr(plsSpec.AutoApprovalSubscriptions),
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create private link service %s in resource group %s", plsSpec.Name, s.Scope.NetworkResourceGroup())
+	}
+	if pls.PrivateLinkServiceProperties != nil {
+		s.Scope.SetPrivateLinkServiceAlias(to.String(pls.Alias))
+	}
+
+	s.Scope.V(2).Info("successfully created private link service", "private link service", plsSpec.Name)
+	return nil
+}
+
+// prepareSubnet checks that the private link service can allocate its NAT IPs from the subnet: Azure requires the
+// private link service network policies of the subnet to be disabled. They are disabled on the subnets of a vnet
+// created by the provider, while a pre-existing vnet is expected to have them disabled already.
+func (s *Service) prepareSubnet(ctx context.Context, plsSpec azure.PrivateLinkServiceSpec, subnet network201906.Subnet) error {
+	if subnet.SubnetPropertiesFormat == nil {
+		subnet.SubnetPropertiesFormat = &network201906.SubnetPropertiesFormat{}
+	}
+	if strings.EqualFold(to.String(subnet.PrivateLinkServiceNetworkPolicies), "Disabled") {
+		return nil
+	}
+	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
+		return errors.Errorf("subnet %s of private link service %s must have its private link service network policies disabled",
+			plsSpec.SubnetName, plsSpec.Name)
+	}
+	s.Scope.V(2).Info("disabling private link service network policies of subnet", "subnet", plsSpec.SubnetName)
+	subnet.PrivateLinkServiceNetworkPolicies = to.StringPtr("Disabled")
+	if err := s.SubnetsClient.CreateOrUpdate(ctx, s.Scope.Vnet().ResourceGroup, plsSpec.VnetName, plsSpec.SubnetName, subnet); err != nil {
+		return errors.Wrapf(err, "failed to disable private link service network policies of subnet %s in resource group %s", plsSpec.SubnetName, s.Scope.Vnet().ResourceGroup)
+	}
+	return nil
+}
+
+// Delete deletes the private link service of the internal API server load balancer, whether or not it is still in the
+// spec. It must be deleted before the load balancer and its subnet.
+func (s *Service) Delete(ctx context.Context) error {
+	name := s.Scope.PrivateLinkServiceName()
+	if !s.Scope.IsNetworkResourceGroupManaged() {
+		// only delete the private link service owned by the cluster from a pre-existing resource group
+		pls, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), name)
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get private link service %s in resource group %s", name, s.Scope.NetworkResourceGroup())
+		}
+		if !converters.MapToTags(pls.Tags).HasOwned(s.Scope.ClusterName()) {
+			s.Scope.V(4).Info("Skipping deletion of private link service not owned by the cluster", "private link service", name)
+			return nil
+		}
+	}
+
+	s.Scope.V(2).Info("deleting private link service", "private link service", name)
+	err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), name)
+	if err != nil && azure.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete private link service %s in resource group %s", name, s.Scope.NetworkResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully deleted private link service", "private link service", name)
+	return nil
+}
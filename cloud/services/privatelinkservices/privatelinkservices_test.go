@@ -0,0 +1,350 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatelinkservices
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	network201906 "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatelinkservices/mock_privatelinkservices"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
+)
+
+var fakePrivateLinkServiceSpec = azure.PrivateLinkServiceSpec{
+	Name:                      "my-cluster-apiserver-pls",
+	SubnetName:                "my-subnet-cp",
+	VnetName:                  "my-vnet",
+	LoadBalancerName:          "my-cluster-internal-lb",
+	VisibilitySubscriptions:   []string{"*"},
+	AutoApprovalSubscriptions: []string{"00000000-1111-2222-3333-444444444444"},
+}
+
+func TestReconcilePrivateLinkService(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+			mSubnet *mock_subnets.MockClientMockRecorder)
+	}{
+		{
+			name:          "no private link service",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.PrivateLinkServiceSpec().Return(nil)
+				s.Network().Return(&infrav1.Network{})
+			},
+		},
+		{
+			name:          "private link service is created on the frontend of the internal load balancer and its alias is recorded",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceSpec().Return(&fakePrivateLinkServiceSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-cp").Return(fakeSubnet("Disabled"), nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(network.PrivateLinkService{}, notFound)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-apiserver-pls", matchers.DiffEq(network.PrivateLinkService{
+					Name:     to.StringPtr("my-cluster-apiserver-pls"),
+					Location: to.StringPtr("testlocation"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"Name": to.StringPtr("my-cluster-apiserver-pls"),
+					},
+					PrivateLinkServiceProperties: &network.PrivateLinkServiceProperties{
+						LoadBalancerFrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+							{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster-internal-lb/frontendIPConfigurations/my-cluster-internal-lb-frontEnd")},
+						},
+						IPConfigurations: &[]network.PrivateLinkServiceIPConfiguration{
+							{
+								Name: to.StringPtr("my-cluster-apiserver-pls-ipconfig"),
+								PrivateLinkServiceIPConfigurationProperties: &network.PrivateLinkServiceIPConfigurationProperties{
+									Subnet:                    &network.Subnet{ID: to.StringPtr("subnet-id")},
+									PrivateIPAllocationMethod: network.Dynamic,
+									Primary:                   to.BoolPtr(true),
+								},
+							},
+						},
+						Visibility: &network.PrivateLinkServicePropertiesVisibility{
+							Subscriptions: &[]string{"*"},
+						},
+						AutoApproval: &network.PrivateLinkServicePropertiesAutoApproval{
+							Subscriptions: &[]string{"00000000-1111-2222-3333-444444444444"},
+						},
+					},
+				})).Return(network.PrivateLinkService{
+					PrivateLinkServiceProperties: &network.PrivateLinkServiceProperties{
+						Alias: to.StringPtr("my-cluster-apiserver-pls.1234.testlocation.azure.privatelinkservice"),
+					},
+				}, nil)
+				s.SetPrivateLinkServiceAlias("my-cluster-apiserver-pls.1234.testlocation.azure.privatelinkservice")
+			},
+		},
+		{
+			name:          "private link service network policies are disabled on the subnet of a managed vnet",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceSpec().Return(&fakePrivateLinkServiceSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-cp").Return(fakeSubnet("Enabled"), nil)
+				gomock.InOrder(
+					mSubnet.CreateOrUpdate(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-cp", matchers.DiffEq(fakeSubnet("Disabled"))),
+					m.Get(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(network.PrivateLinkService{}, notFound),
+					m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-apiserver-pls", gomock.AssignableToTypeOf(network.PrivateLinkService{})),
+				)
+			},
+		},
+		{
+			name:          "private link service network policies are enabled on the subnet of a pre-existing vnet",
+			expectedError: "subnet my-subnet-cp of private link service my-cluster-apiserver-pls must have its private link service network policies disabled",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceSpec().Return(&fakePrivateLinkServiceSpec)
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{ID: "my-vnet-id", Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-cp").Return(network201906.Subnet{ID: to.StringPtr("subnet-id")}, nil)
+			},
+		},
+		{
+			name:          "existing private link service keeps its out-of-band tags",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceSpec().Return(&fakePrivateLinkServiceSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-cp").Return(fakeSubnet("Disabled"), nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(network.PrivateLinkService{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"Name":     to.StringPtr("my-cluster-apiserver-pls"),
+						"external": to.StringPtr("value"),
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-apiserver-pls", gomock.AssignableToTypeOf(network.PrivateLinkService{})).
+					Do(func(_ context.Context, _, _ string, pls network.PrivateLinkService) {
+						if to.String(pls.Tags["external"]) != "value" {
+							t.Errorf("expected the out-of-band tag to be kept, got %v", pls.Tags)
+						}
+					})
+			},
+		},
+		{
+			name:          "private link service of another owner",
+			expectedError: "private link service my-cluster-apiserver-pls already exists in resource group my-rg and isn't owned by cluster my-cluster",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceSpec().Return(&fakePrivateLinkServiceSpec)
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-cp").Return(fakeSubnet("Disabled"), nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(network.PrivateLinkService{}, nil)
+			},
+		},
+		{
+			name:          "private link service removed from the spec is deleted",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceSpec().Return(nil)
+				s.Network().Return(&infrav1.Network{PrivateLinkServiceAlias: "my-cluster-apiserver-pls.1234.testlocation.azure.privatelinkservice"})
+				s.PrivateLinkServiceName().Return("my-cluster-apiserver-pls")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-apiserver-pls")
+				s.SetPrivateLinkServiceAlias("")
+			},
+		},
+		{
+			name:          "fail to create the private link service",
+			expectedError: "failed to create private link service my-cluster-apiserver-pls in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder,
+				mSubnet *mock_subnets.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceSpec().Return(&fakePrivateLinkServiceSpec)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Vnet().AnyTimes().Return(&infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "vnet-rg"})
+				mSubnet.Get(context.TODO(), "vnet-rg", "my-vnet", "my-subnet-cp").Return(fakeSubnet("Disabled"), nil)
+				m.Get(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(network.PrivateLinkService{}, notFound)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-apiserver-pls", gomock.AssignableToTypeOf(network.PrivateLinkService{})).
+					Return(network.PrivateLinkService{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privatelinkservices.NewMockPrivateLinkServiceScope(mockCtrl)
+			clientMock := mock_privatelinkservices.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), subnetsMock.EXPECT())
+
+			s := &Service{
+				Scope:         scopeMock,
+				Client:        clientMock,
+				SubnetsClient: subnetsMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePrivateLinkService(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder)
+	}{
+		{
+			name:          "successfully delete the private link service",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceName().Return("my-cluster-apiserver-pls")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-apiserver-pls")
+			},
+		},
+		{
+			name:          "private link service already deleted",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceName().Return("my-cluster-apiserver-pls")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "skip private link service not owned by the cluster in a pre-existing resource group",
+			expectedError: "",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceName().Return("my-cluster-apiserver-pls")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(false)
+				m.Get(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(network.PrivateLinkService{}, nil)
+			},
+		},
+		{
+			name:          "private link service deletion fails",
+			expectedError: "failed to delete private link service my-cluster-apiserver-pls in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_privatelinkservices.MockPrivateLinkServiceScopeMockRecorder, m *mock_privatelinkservices.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PrivateLinkServiceName().Return("my-cluster-apiserver-pls")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-apiserver-pls").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_privatelinkservices.NewMockPrivateLinkServiceScope(mockCtrl)
+			clientMock := mock_privatelinkservices.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func fakeSubnet(privateLinkServiceNetworkPolicies string) network201906.Subnet {
+	return network201906.Subnet{
+		ID: to.StringPtr("subnet-id"),
+		SubnetPropertiesFormat: &network201906.SubnetPropertiesFormat{
+			AddressPrefix:                     to.StringPtr("10.0.0.0/16"),
+			PrivateLinkServiceNetworkPolicies: to.StringPtr(privateLinkServiceNetworkPolicies),
+		},
+	}
+}
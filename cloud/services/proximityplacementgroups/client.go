@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proximityplacementgroups
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (compute.ProximityPlacementGroup, error)
+	CreateOrUpdate(context.Context, string, string, compute.ProximityPlacementGroup) error
+	Delete(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	proximityplacementgroups compute.ProximityPlacementGroupsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new proximity placement groups client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newProximityPlacementGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newProximityPlacementGroupsClient creates a new proximity placement groups client from subscription ID.
+func newProximityPlacementGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.ProximityPlacementGroupsClient {
+	proximityPlacementGroupsClient := compute.NewProximityPlacementGroupsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&proximityPlacementGroupsClient.Client, authorizer)
+	return proximityPlacementGroupsClient
+}
+
+// Get gets the specified proximity placement group in a specified resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, ppgName string) (compute.ProximityPlacementGroup, error) {
+	return ac.proximityplacementgroups.Get(ctx, resourceGroupName, ppgName, "")
+}
+
+// CreateOrUpdate creates or updates a proximity placement group.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, ppgName string, ppg compute.ProximityPlacementGroup) error {
+	_, err := ac.proximityplacementgroups.CreateOrUpdate(ctx, resourceGroupName, ppgName, ppg)
+	return err
+}
+
+// Delete deletes the specified proximity placement group.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, ppgName string) error {
+	_, err := ac.proximityplacementgroups.Delete(ctx, resourceGroupName, ppgName)
+	return err
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proximityplacementgroups
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Reconcile gets/creates/updates the proximity placement group of the cluster.
+// A pre-existing proximity placement group not owned by the cluster is used as is.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ppgSpec := s.Scope.ProximityPlacementGroupSpec()
+	if ppgSpec == nil {
+		return nil
+	}
+
+	existing, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), ppgSpec.Name)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get proximity placement group %s in resource group %s", ppgSpec.Name, s.Scope.ResourceGroup())
+	case err == nil && !converters.MapToTags(existing.Tags).HasOwned(s.Scope.ClusterName()):
+		s.Scope.V(4).Info("Skipping reconcile of proximity placement group not owned by the cluster", "proximity placement group", ppgSpec.Name)
+		return nil
+	}
+
+	s.Scope.V(2).Info("creating proximity placement group", "proximity placement group", ppgSpec.Name)
+	err = s.Client.CreateOrUpdate(
+		ctx,
+		s.Scope.ResourceGroup(),
+		ppgSpec.Name,
+		compute.ProximityPlacementGroup{
+			Location: to.StringPtr(s.Scope.Location()),
+			Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.Scope.ClusterName(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        to.StringPtr(ppgSpec.Name),
+				Additional:  s.Scope.AdditionalTags(),
+			})),
+			ProximityPlacementGroupProperties: &compute.ProximityPlacementGroupProperties{
+				ProximityPlacementGroupType: compute.Standard,
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create proximity placement group %s in resource group %s", ppgSpec.Name, s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully created proximity placement group", "proximity placement group", ppgSpec.Name)
+	return nil
+}
+
+// Delete deletes the proximity placement group of the cluster if it is owned by the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ppgSpec := s.Scope.ProximityPlacementGroupSpec()
+	if ppgSpec == nil {
+		return nil
+	}
+
+	existing, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), ppgSpec.Name)
+	if azure.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get proximity placement group %s in resource group %s", ppgSpec.Name, s.Scope.ResourceGroup())
+	}
+	if !converters.MapToTags(existing.Tags).HasOwned(s.Scope.ClusterName()) {
+		s.Scope.V(4).Info("Skipping deletion of proximity placement group not owned by the cluster", "proximity placement group", ppgSpec.Name)
+		return nil
+	}
+
+	s.Scope.V(2).Info("deleting proximity placement group", "proximity placement group", ppgSpec.Name)
+	err = s.Client.Delete(ctx, s.Scope.ResourceGroup(), ppgSpec.Name)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to delete proximity placement group %s in resource group %s", ppgSpec.Name, s.Scope.ResourceGroup())
+	}
+
+	s.Scope.V(2).Info("successfully deleted proximity placement group", "proximity placement group", ppgSpec.Name)
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proximityplacementgroups
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/proximityplacementgroups/mock_proximityplacementgroups"
+)
+
+var ownedTags = map[string]*string{
+	"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+}
+
+func TestReconcileProximityPlacementGroup(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder)
+	}{
+		{
+			name:          "no proximity placement group",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.ProximityPlacementGroupSpec().Return(nil)
+			},
+		},
+		{
+			name:          "proximity placement group is created",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-ppg", gomock.AssignableToTypeOf(compute.ProximityPlacementGroup{}))
+			},
+		},
+		{
+			name:          "owned proximity placement group is updated",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{Tags: ownedTags}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-ppg", gomock.AssignableToTypeOf(compute.ProximityPlacementGroup{}))
+			},
+		},
+		{
+			name:          "pre-existing proximity placement group is left as is",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "shared-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(context.TODO(), "my-rg", "shared-ppg").Return(compute.ProximityPlacementGroup{Name: to.StringPtr("shared-ppg")}, nil)
+			},
+		},
+		{
+			name:          "fail to create the proximity placement group",
+			expectedError: "failed to create proximity placement group my-cluster-ppg in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-ppg", gomock.AssignableToTypeOf(compute.ProximityPlacementGroup{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_proximityplacementgroups.NewMockProximityPlacementGroupScope(mockCtrl)
+			clientMock := mock_proximityplacementgroups.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteProximityPlacementGroup(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder)
+	}{
+		{
+			name:          "owned proximity placement group is deleted",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(context.TODO(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-ppg")
+			},
+		},
+		{
+			name:          "pre-existing proximity placement group is not deleted",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "shared-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(context.TODO(), "my-rg", "shared-ppg").Return(compute.ProximityPlacementGroup{}, nil)
+			},
+		},
+		{
+			name:          "proximity placement group already deleted",
+			expectedError: "",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(context.TODO(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "fail to delete the proximity placement group",
+			expectedError: "failed to delete proximity placement group my-cluster-ppg in resource group my-rg: #: Conflict: StatusCode=409",
+			expect: func(s *mock_proximityplacementgroups.MockProximityPlacementGroupScopeMockRecorder, m *mock_proximityplacementgroups.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.ProximityPlacementGroupSpec().Return(&azure.ProximityPlacementGroupSpec{Name: "my-cluster-ppg"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				m.Get(context.TODO(), "my-rg", "my-cluster-ppg").Return(compute.ProximityPlacementGroup{Tags: ownedTags}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-cluster-ppg").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_proximityplacementgroups.NewMockProximityPlacementGroupScope(mockCtrl)
+			clientMock := mock_proximityplacementgroups.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
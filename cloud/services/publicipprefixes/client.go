@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicipprefixes
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(context.Context, string, string) (network.PublicIPPrefix, error)
+	CreateOrUpdate(context.Context, string, string, network.PublicIPPrefix) error
+	Delete(context.Context, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	publicipprefixes network.PublicIPPrefixesClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new public IP prefix client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newPublicIPPrefixesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newPublicIPPrefixesClient creates a new public IP prefix client from subscription ID.
+func newPublicIPPrefixesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PublicIPPrefixesClient {
+	publicIPPrefixesClient := network.NewPublicIPPrefixesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&publicIPPrefixesClient.Client, authorizer)
+	return publicIPPrefixesClient
+}
+
+// Get gets the specified public IP prefix in a specified resource group.
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, prefixName string) (network.PublicIPPrefix, error) {
+	return ac.publicipprefixes.Get(ctx, resourceGroupName, prefixName, "")
+}
+
+// CreateOrUpdate creates or updates a public IP prefix.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, prefixName string, prefix network.PublicIPPrefix) error {
+	future, err := ac.publicipprefixes.CreateOrUpdate(ctx, resourceGroupName, prefixName, prefix)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.publicipprefixes.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.publicipprefixes)
+	return err
+}
+
+// Delete deletes the specified public IP prefix.
+func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, prefixName string) error {
+	future, err := ac.publicipprefixes.Delete(ctx, resourceGroupName, prefixName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.publicipprefixes.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.publicipprefixes)
+	return err
+}
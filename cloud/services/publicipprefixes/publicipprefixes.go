@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicipprefixes
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+)
+
+// Reconcile gets/creates/updates a public IP prefix.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, prefixSpec := range s.Scope.PublicIPPrefixSpecs() {
+		s.Scope.V(2).Info("creating public IP prefix", "public ip prefix", prefixSpec.Name)
+		var zones *[]string
+		if len(prefixSpec.Zones) > 0 {
+			if !azure.SupportsAvailabilityZones(s.Scope.Location()) {
+				return errors.Errorf("cannot create public IP prefix %s in zones %v: availability zones are not supported in location %s", prefixSpec.Name, prefixSpec.Zones, s.Scope.Location())
+			}
+			zones = &prefixSpec.Zones
+		}
+		err := s.Client.CreateOrUpdate(
+			ctx,
+			s.Scope.NetworkResourceGroup(),
+			prefixSpec.Name,
+			network.PublicIPPrefix{
+				Sku:      &network.PublicIPPrefixSku{Name: network.PublicIPPrefixSkuNameStandard},
+				Name:     to.StringPtr(prefixSpec.Name),
+				Location: to.StringPtr(s.Scope.Location()),
+				Zones:    zones,
+				Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+					ClusterName: s.Scope.ClusterName(),
+					Lifecycle:   infrav1.ResourceLifecycleOwned,
+					Name:        to.StringPtr(prefixSpec.Name),
+					Additional:  s.Scope.AdditionalTags(),
+				})),
+				PublicIPPrefixPropertiesFormat: &network.PublicIPPrefixPropertiesFormat{
+					PublicIPAddressVersion: network.IPv4,
+					PrefixLength:           to.Int32Ptr(prefixSpec.PrefixLength),
+				},
+			},
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create public IP prefix %s in resource group %s", prefixSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+
+		s.Scope.V(2).Info("successfully created public IP prefix", "public ip prefix", prefixSpec.Name)
+	}
+	return nil
+}
+
+// Delete deletes the public IP prefixes in the provided scope.
+// A prefix cannot be deleted while public IPs are allocated from it, so the public IPs must be deleted first.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, prefixSpec := range s.Scope.PublicIPPrefixSpecs() {
+		prefix, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), prefixSpec.Name)
+		if azure.ResourceNotFound(err) {
+			// already deleted
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get public IP prefix %s in resource group %s", prefixSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+		if !s.Scope.IsNetworkResourceGroupManaged() && !converters.MapToTags(prefix.Tags).HasOwned(s.Scope.ClusterName()) {
+			// only delete the public IP prefixes owned by the cluster from a pre-existing resource group
+			s.Scope.V(4).Info("Skipping deletion of public IP prefix not owned by the cluster", "public ip prefix", prefixSpec.Name)
+			continue
+		}
+		if prefix.PublicIPPrefixPropertiesFormat != nil && prefix.PublicIPAddresses != nil && len(*prefix.PublicIPAddresses) > 0 {
+			return errors.Errorf("public IP prefix %s still has %d public IPs allocated from it", prefixSpec.Name, len(*prefix.PublicIPAddresses))
+		}
+
+		s.Scope.V(2).Info("deleting public IP prefix", "public ip prefix", prefixSpec.Name)
+		err = s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), prefixSpec.Name)
+		if err != nil && azure.ResourceNotFound(err) {
+			// already deleted
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete public IP prefix %s in resource group %s", prefixSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+
+		s.Scope.V(2).Info("successfully deleted public IP prefix", "public ip prefix", prefixSpec.Name)
+	}
+	return nil
+}
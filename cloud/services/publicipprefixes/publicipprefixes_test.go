@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publicipprefixes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicipprefixes/mock_publicipprefixes"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
+)
+
+func TestReconcilePublicIPPrefixes(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder)
+	}{
+		{
+			name:          "public IP prefix is created",
+			expectedError: "",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPPrefixSpecs().Return([]azure.PublicIPPrefixSpec{
+					{
+						Name:         "my-prefix",
+						PrefixLength: 30,
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-prefix", matchers.DiffEq(network.PublicIPPrefix{
+					Sku:      &network.PublicIPPrefixSku{Name: network.PublicIPPrefixSkuNameStandard},
+					Name:     to.StringPtr("my-prefix"),
+					Location: to.StringPtr("testlocation"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-prefix"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPPrefixPropertiesFormat: &network.PublicIPPrefixPropertiesFormat{
+						PublicIPAddressVersion: network.IPv4,
+						PrefixLength:           to.Int32Ptr(30),
+					},
+				}))
+			},
+		},
+		{
+			name:          "no public IP prefix",
+			expectedError: "",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.PublicIPPrefixSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "fail to create the public IP prefix",
+			expectedError: "failed to create public IP prefix my-prefix in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPPrefixSpecs().Return([]azure.PublicIPPrefixSpec{
+					{
+						Name:         "my-prefix",
+						PrefixLength: 30,
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-prefix", gomock.AssignableToTypeOf(network.PublicIPPrefix{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_publicipprefixes.NewMockPublicIPPrefixScope(mockCtrl)
+			clientMock := mock_publicipprefixes.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePublicIPPrefixes(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder)
+	}{
+		{
+			name:          "public IP prefix is deleted",
+			expectedError: "",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPPrefixSpecs().Return([]azure.PublicIPPrefixSpec{{Name: "my-prefix", PrefixLength: 30}})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Get(context.TODO(), "my-rg", "my-prefix").Return(network.PublicIPPrefix{
+					PublicIPPrefixPropertiesFormat: &network.PublicIPPrefixPropertiesFormat{},
+				}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-prefix")
+			},
+		},
+		{
+			name:          "public IP prefix already deleted",
+			expectedError: "",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPPrefixSpecs().Return([]azure.PublicIPPrefixSpec{{Name: "my-prefix", PrefixLength: 30}})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(context.TODO(), "my-rg", "my-prefix").Return(network.PublicIPPrefix{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name:          "public IP prefix still has public IPs",
+			expectedError: "public IP prefix my-prefix still has 1 public IPs allocated from it",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPPrefixSpecs().Return([]azure.PublicIPPrefixSpec{{Name: "my-prefix", PrefixLength: 30}})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Get(context.TODO(), "my-rg", "my-prefix").Return(network.PublicIPPrefix{
+					PublicIPPrefixPropertiesFormat: &network.PublicIPPrefixPropertiesFormat{
+						PublicIPAddresses: &[]network.ReferencedPublicIPAddress{{ID: to.StringPtr("pip-id")}},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "public IP prefix not owned by the cluster in a pre-existing resource group",
+			expectedError: "",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPPrefixSpecs().Return([]azure.PublicIPPrefixSpec{{Name: "my-prefix", PrefixLength: 30}})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(false)
+				m.Get(context.TODO(), "my-rg", "my-prefix").Return(network.PublicIPPrefix{}, nil)
+			},
+		},
+		{
+			name:          "public IP prefix deletion fails",
+			expectedError: "failed to delete public IP prefix my-prefix in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicipprefixes.MockPublicIPPrefixScopeMockRecorder, m *mock_publicipprefixes.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPPrefixSpecs().Return([]azure.PublicIPPrefixSpec{{Name: "my-prefix", PrefixLength: 30}})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
+				m.Get(context.TODO(), "my-rg", "my-prefix").Return(network.PublicIPPrefix{}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-prefix").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_publicipprefixes.NewMockPublicIPPrefixScope(mockCtrl)
+			clientMock := mock_publicipprefixes.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ type Client interface {
 	Get(context.Context, string, string) (network.PublicIPAddress, error)
 	CreateOrUpdate(context.Context, string, string, network.PublicIPAddress) error
 	Delete(context.Context, string, string) error
+	CheckDNSNameAvailability(context.Context, string, string) (network.DNSNameAvailabilityResult, error)
 }
 
 // AzureClient contains the Azure go-sdk Client
@@ -47,8 +48,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newPublicIPAddressesClient creates a new public IP client from subscription ID.
 func newPublicIPAddressesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PublicIPAddressesClient {
 	publicIPsClient := network.NewPublicIPAddressesClientWithBaseURI(baseURI, subscriptionID)
-	publicIPsClient.Authorizer = authorizer
-	publicIPsClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&publicIPsClient.Client, authorizer)
 	return publicIPsClient
 }
 
@@ -84,3 +84,8 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, ipName str
 	_, err = future.Result(ac.publicips)
 	return err
 }
+
+// CheckDNSNameAvailability checks whether a DNS label is available in the cloudapp.azure.com zone of a location.
+func (ac *AzureClient) CheckDNSNameAvailability(ctx context.Context, location, domainNameLabel string) (network.DNSNameAvailabilityResult, error) {
+	return ac.publicips.CheckDNSNameAvailability(ctx, location, domainNameLabel)
+}
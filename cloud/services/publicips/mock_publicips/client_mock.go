@@ -92,3 +92,18 @@ func (mr *MockClientMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1, arg2)
 }
+
+// CheckDNSNameAvailability mocks base method.
+func (m *MockClient) CheckDNSNameAvailability(arg0 context.Context, arg1, arg2 string) (network.DNSNameAvailabilityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckDNSNameAvailability", arg0, arg1, arg2)
+	ret0, _ := ret[0].(network.DNSNameAvailabilityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckDNSNameAvailability indicates an expected call of CheckDNSNameAvailability.
+func (mr *MockClientMockRecorder) CheckDNSNameAvailability(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDNSNameAvailability", reflect.TypeOf((*MockClient)(nil).CheckDNSNameAvailability), arg0, arg1, arg2)
+}
@@ -18,58 +18,189 @@ package publicips
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
 )
 
-// Reconcile gets/creates/updates a public ip.
+// Reconcile gets/creates/updates the public ips. The public ips don't depend on each other, so they are reconciled
+// concurrently.
 func (s *Service) Reconcile(ctx context.Context) error {
-	for _, ip := range s.Scope.PublicIPSpecs() {
-		s.Scope.V(2).Info("creating public IP", "public ip", ip.Name)
-		err := s.Client.CreateOrUpdate(
-			ctx,
-			s.Scope.ResourceGroup(),
-			ip.Name,
-			network.PublicIPAddress{
-				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
-				Name:     to.StringPtr(ip.Name),
-				Location: to.StringPtr(s.Scope.Location()),
-				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-					PublicIPAddressVersion:   network.IPv4,
-					PublicIPAllocationMethod: network.Static,
-					DNSSettings: &network.PublicIPAddressDNSSettings{
-						DomainNameLabel: to.StringPtr(strings.ToLower(ip.Name)),
-						Fqdn:            to.StringPtr(ip.DNSName),
-					},
-				},
-			},
-		)
+	ips := s.Scope.PublicIPSpecs()
+	return azure.ForEachParallel(len(ips), azure.DefaultMaxConcurrentOperations, func(i int) error {
+		return s.reconcilePublicIP(ctx, ips[i])
+	})
+}
 
+// reconcilePublicIP gets/creates/updates a public ip.
+func (s *Service) reconcilePublicIP(ctx context.Context, ip azure.PublicIPSpec) error {
+	s.Scope.V(2).Info("creating public IP", "public ip", ip.Name)
+	sku := network.PublicIPAddressSkuNameStandard
+	if ip.SKU == infrav1.SKUBasic {
+		sku = network.PublicIPAddressSkuNameBasic
+	}
+	version := network.IPv4
+	if ip.IsIPv6 {
+		version = network.IPv6
+	}
+	var zones *[]string
+	if len(ip.Zones) > 0 {
+		if !azure.SupportsAvailabilityZones(s.Scope.Location()) {
+			return errors.Errorf("cannot create public IP %s in zones %v: availability zones are not supported in location %s", ip.Name, ip.Zones, s.Scope.Location())
+		}
+		zones = &ip.Zones
+	}
+	var prefix *network.SubResource
+	if ip.PublicIPPrefixName != "" {
+		prefix = &network.SubResource{
+			ID: to.StringPtr(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/publicIPPrefixes/%s",
+				s.Scope.SubscriptionID(), s.Scope.NetworkResourceGroup(), ip.PublicIPPrefixName)),
+		}
+	}
+	tags := converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(ip.Name),
+		Additional:  s.additionalTags(ip),
+	}))
+	label := ip.DNSLabel
+	if label == "" {
+		label = strings.ToLower(ip.Name)
+	}
+	publicIP := network.PublicIPAddress{
+		Sku:      &network.PublicIPAddressSku{Name: sku},
+		Name:     to.StringPtr(ip.Name),
+		Location: to.StringPtr(s.Scope.Location()),
+		Zones:    zones,
+		Tags:     tags,
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+			PublicIPAddressVersion:   version,
+			PublicIPAllocationMethod: network.Static,
+			PublicIPPrefix:           prefix,
+			DNSSettings: &network.PublicIPAddressDNSSettings{
+				DomainNameLabel: to.StringPtr(label),
+				Fqdn:            to.StringPtr(ip.DNSName),
+			},
+		},
+	}
+	existingIP, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), ip.Name)
+	switch {
+	case err != nil && !azure.ResourceNotFound(err):
+		return errors.Wrapf(err, "failed to get public IP %s in resource group %s", ip.Name, s.Scope.NetworkResourceGroup())
+	case err != nil && ip.DNSLabel != "":
+		if err := s.checkDNSLabelAvailability(ctx, ip.Name, label); err != nil {
+			return err
+		}
+	case err == nil:
+		if ip.DNSLabel != "" && !hasDNSLabel(existingIP, label) {
+			if err := s.checkDNSLabelAvailability(ctx, ip.Name, label); err != nil {
+				return err
+			}
+		}
+		var changed bool
+		publicIP, changed, err = s.adopt(existingIP, publicIP)
 		if err != nil {
-			return errors.Wrap(err, "cannot create public IP")
+			return err
 		}
+		if !changed {
+			s.Scope.V(2).Info("public IP is up to date", "public ip", ip.Name)
+			return nil
+		}
+	}
+
+	if err := s.Client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), ip.Name, publicIP); err != nil {
+		return errors.Wrap(err, "cannot create public IP")
+	}
 
-		s.Scope.V(2).Info("successfully created public IP", "public ip", ip.Name)
+	s.Scope.V(2).Info("successfully created public IP", "public ip", ip.Name)
+	return nil
+}
+
+// additionalTags returns the additional tags of the cluster with the tags of the public IP merged on top of them.
+// The enforced tags of the cluster still take precedence over the tags of the public IP.
+func (s *Service) additionalTags(ip azure.PublicIPSpec) infrav1.Tags {
+	tags := make(infrav1.Tags)
+	tags.Merge(s.Scope.AdditionalTags())
+	if len(ip.AdditionalTags) > 0 {
+		tags.Merge(ip.AdditionalTags)
+		tags.Merge(s.Scope.EnforcedTags())
 	}
+	return tags
+}
 
+// checkDNSLabelAvailability checks that the custom DNS label of a public IP isn't used by another public IP of the
+// location, as Azure only reports the conflict once the public IP fails to provision.
+func (s *Service) checkDNSLabelAvailability(ctx context.Context, name, label string) error {
+	result, err := s.Client.CheckDNSNameAvailability(ctx, s.Scope.Location(), label)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check the availability of DNS label %s of public IP %s", label, name)
+	}
+	if !to.Bool(result.Available) {
+		return errors.Errorf("DNS label %s of public IP %s is already used in location %s", label, name, s.Scope.Location())
+	}
 	return nil
 }
 
+// hasDNSLabel returns true if a public IP has the given DNS label.
+func hasDNSLabel(ip network.PublicIPAddress, label string) bool {
+	return ip.PublicIPAddressPropertiesFormat != nil && ip.DNSSettings != nil && to.String(ip.DNSSettings.DomainNameLabel) == label
+}
+
+// adopt returns an existing public IP of the cluster with the fields that drifted from the desired public IP updated,
+// and whether any of them did. Only the tags and the DNS label are updated, the other fields of the existing public IP
+// are kept. An existing public IP without the ownership tag of the cluster isn't adopted, as it may
+// be a resource of the user with the same name.
+func (s *Service) adopt(existing, desired network.PublicIPAddress) (network.PublicIPAddress, bool, error) {
+	name := to.String(desired.Name)
+	if !converters.MapToTags(existing.Tags).HasOwned(s.Scope.ClusterName()) {
+		return existing, false, errors.Errorf("public IP %s already exists in resource group %s and isn't owned by cluster %s", name, s.Scope.NetworkResourceGroup(), s.Scope.ClusterName())
+	}
+
+	// keep the tags set out-of-band on the existing public IP
+	tags, changed := converters.UpdateTags(existing.Tags, converters.MapToTags(desired.Tags), s.Scope.LastAppliedTags())
+	existing.Tags = tags
+
+	if existing.PublicIPAddressPropertiesFormat == nil {
+		existing.PublicIPAddressPropertiesFormat = &network.PublicIPAddressPropertiesFormat{}
+	}
+	if existing.DNSSettings == nil || to.String(existing.DNSSettings.DomainNameLabel) != to.String(desired.DNSSettings.DomainNameLabel) {
+		existing.DNSSettings = desired.DNSSettings
+		changed = true
+	}
+	return existing, changed, nil
+}
+
 // Delete deletes the public IP with the provided scope.
 func (s *Service) Delete(ctx context.Context) error {
 	for _, ip := range s.Scope.PublicIPSpecs() {
+		if !s.Scope.IsNetworkResourceGroupManaged() {
+			// only delete the public IPs owned by the cluster from a pre-existing resource group
+			publicIP, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), ip.Name)
+			if azure.ResourceNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to get public IP %s in resource group %s", ip.Name, s.Scope.NetworkResourceGroup())
+			}
+			if !converters.MapToTags(publicIP.Tags).HasOwned(s.Scope.ClusterName()) {
+				s.Scope.V(4).Info("Skipping deletion of public IP not owned by the cluster", "public ip", ip.Name)
+				continue
+			}
+		}
 		s.Scope.V(2).Info("deleting public IP", "public ip", ip.Name)
-		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), ip.Name)
+		err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), ip.Name)
 		if err != nil && azure.ResourceNotFound(err) {
 			// already deleted
-			return nil
+			continue
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", ip.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", ip.Name, s.Scope.NetworkResourceGroup())
 		}
 
 		s.Scope.V(2).Info("deleted public IP", "public ip", ip.Name)
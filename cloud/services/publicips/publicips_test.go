@@ -21,12 +21,15 @@ import (
 	"net/http"
 	"testing"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 
 	network "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
@@ -63,13 +66,354 @@ func TestReconcilePublicIP(t *testing.T) {
 						Name: "my-publicip-3",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{}))
+				m.Get(context.TODO(), "my-rg", "my-publicip-2").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip-2", gomock.AssignableToTypeOf(network.PublicIPAddress{}))
+				m.Get(context.TODO(), "my-rg", "my-publicip-3").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip-3", gomock.AssignableToTypeOf(network.PublicIPAddress{}))
 			},
 		},
+		{
+			name:          "can create a zone-redundant public IP from a prefix",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:               "my-publicip",
+						PublicIPPrefixName: "my-prefix",
+						Zones:              []string{"1", "2", "3"},
+					},
+				})
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", matchers.DiffEq(network.PublicIPAddress{
+					Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+					Name:     to.StringPtr("my-publicip"),
+					Location: to.StringPtr("westus2"),
+					Zones:    &[]string{"1", "2", "3"},
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   network.IPv4,
+						PublicIPAllocationMethod: network.Static,
+						PublicIPPrefix: &network.SubResource{
+							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPPrefixes/my-prefix"),
+						},
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-publicip"),
+							Fqdn:            to.StringPtr(""),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "can create a public IP with a custom DNS label",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:     "my-publicip",
+						DNSName:  "my-cluster.westus2.cloudapp.azure.com",
+						DNSLabel: "my-cluster",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CheckDNSNameAvailability(context.TODO(), "westus2", "my-cluster").Return(network.DNSNameAvailabilityResult{Available: to.BoolPtr(true)}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", matchers.DiffEq(network.PublicIPAddress{
+					Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+					Name:     to.StringPtr("my-publicip"),
+					Location: to.StringPtr("westus2"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   network.IPv4,
+						PublicIPAllocationMethod: network.Static,
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-cluster"),
+							Fqdn:            to.StringPtr("my-cluster.westus2.cloudapp.azure.com"),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "fail to create a public IP with a custom DNS label used in the location",
+			expectedError: "DNS label my-cluster of public IP my-publicip is already used in location westus2",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:     "my-publicip",
+						DNSLabel: "my-cluster",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CheckDNSNameAvailability(context.TODO(), "westus2", "my-cluster").Return(network.DNSNameAvailabilityResult{Available: to.BoolPtr(false)}, nil)
+			},
+		},
+		{
+			name:          "do not check the availability of the DNS label of an existing public IP",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:     "my-publicip",
+						DNSLabel: "my-cluster",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("westus2")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-publicip"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-cluster"),
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "fail to create a zonal public IP in a location without availability zones",
+			expectedError: "cannot create public IP my-publicip in zones [1]: availability zones are not supported in location testlocation",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:  "my-publicip",
+						Zones: []string{"1"},
+					},
+				})
+				s.Location().AnyTimes().Return("testlocation")
+			},
+		},
+		{
+			name:          "update the tags of an existing public IP",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"hello": "world"})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{"foo": "bar"})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"foo":      to.StringPtr("bar"),
+						"external": to.StringPtr("value"),
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", matchers.DiffEq(network.PublicIPAddress{
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"hello":    to.StringPtr("world"),
+						"external": to.StringPtr("value"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-publicip"),
+							Fqdn:            to.StringPtr(""),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "can create a public IP with tags merged on top of the cluster tags",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:           "my-publicip",
+						AdditionalTags: infrav1.Tags{"firewall": "allow-egress", "team": "network", "costCenter": "5678"},
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"team": "platform", "costCenter": "1234"})
+				s.EnforcedTags().AnyTimes().Return(infrav1.Tags{"costCenter": "1234"})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", matchers.DiffEq(network.PublicIPAddress{
+					Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
+					Name:     to.StringPtr("my-publicip"),
+					Location: to.StringPtr("testlocation"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-publicip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"firewall":   to.StringPtr("allow-egress"),
+						"team":       to.StringPtr("network"),
+						"costCenter": to.StringPtr("1234"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   network.IPv4,
+						PublicIPAllocationMethod: network.Static,
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-publicip"),
+							Fqdn:            to.StringPtr(""),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "adopt an existing public IP of the cluster without drift",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-cluster-outbound-ip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-cluster-outbound-ip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-cluster-outbound-ip"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-cluster-outbound-ip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						IPAddress: to.StringPtr("20.0.0.1"),
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-cluster-outbound-ip"),
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "adopt an existing public IP of the cluster with a drifted DNS label",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-cluster-outbound-ip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-cluster-outbound-ip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-cluster-outbound-ip"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-cluster-outbound-ip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						IPAddress: to.StringPtr("20.0.0.1"),
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("other-label"),
+						},
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-cluster-outbound-ip", matchers.DiffEq(network.PublicIPAddress{
+					Name: to.StringPtr("my-cluster-outbound-ip"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-cluster-outbound-ip"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+					PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+						IPAddress: to.StringPtr("20.0.0.1"),
+						DNSSettings: &network.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.StringPtr("my-cluster-outbound-ip"),
+							Fqdn:            to.StringPtr(""),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:          "refuse to adopt an existing public IP not owned by the cluster",
+			expectedError: "public IP my-cluster-outbound-ip already exists in resource group my-rg and isn't owned by cluster my-cluster",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-cluster-outbound-ip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-cluster-outbound-ip").Return(network.PublicIPAddress{
+					Name: to.StringPtr("my-cluster-outbound-ip"),
+					Tags: map[string]*string{
+						"team": to.StringPtr("networking"),
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "public IP retrieval fails",
+			expectedError: "failed to get public IP my-publicip in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
 		{
 			name:          "fail to create a public IP",
 			expectedError: "cannot create public IP: #: Internal Server Error: StatusCode=500",
@@ -81,14 +425,42 @@ func TestReconcilePublicIP(t *testing.T) {
 						DNSName: "fakedns",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
 		},
+		{
+			name:          "create the other public IPs when one fails to be created",
+			expectedError: "cannot create public IP: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+					{
+						Name: "my-publicip-2",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("testlocation")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.LastAppliedTags().AnyTimes().Return(infrav1.Tags{})
+				m.Get(context.TODO(), "my-rg", gomock.Any()).Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")).Times(2)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-publicip-2", gomock.AssignableToTypeOf(network.PublicIPAddress{}))
+			},
+		},
 	}
 
 	for _, tc := range testcases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
 
@@ -135,11 +507,37 @@ func TestDeletePublicIP(t *testing.T) {
 						Name: "my-publicip-2",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
 				m.Delete(context.TODO(), "my-rg", "my-publicip")
 				m.Delete(context.TODO(), "my-rg", "my-publicip-2")
 			},
 		},
+		{
+			name:          "only delete the owned public IPs of a pre-existing resource group",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name: "my-publicip",
+					},
+					{
+						Name: "shared-publicip",
+					},
+				})
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(false)
+				m.Get(context.TODO(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				m.Delete(context.TODO(), "my-rg", "my-publicip")
+				m.Get(context.TODO(), "my-rg", "shared-publicip").Return(network.PublicIPAddress{}, nil)
+			},
+		},
 		{
 			name:          "public ip already deleted",
 			expectedError: "",
@@ -150,7 +548,8 @@ func TestDeletePublicIP(t *testing.T) {
 						Name: "my-publicip",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
 				m.Delete(context.TODO(), "my-rg", "my-publicip").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
@@ -165,7 +564,8 @@ func TestDeletePublicIP(t *testing.T) {
 						Name: "my-publicip",
 					},
 				})
-				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.NetworkResourceGroup().AnyTimes().Return("my-rg")
+				s.IsNetworkResourceGroupManaged().AnyTimes().Return(true)
 				m.Delete(context.TODO(), "my-rg", "my-publicip").
 					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
 			},
@@ -45,8 +45,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newRoleAssignmentClient creates a role assignments client from subscription ID.
 func newRoleAssignmentClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) authorization.RoleAssignmentsClient {
 	roleClient := authorization.NewRoleAssignmentsClientWithBaseURI(baseURI, subscriptionID)
-	roleClient.Authorizer = authorizer
-	roleClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&roleClient.Client, authorizer)
 	return roleClient
 }
 
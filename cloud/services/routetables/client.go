@@ -28,6 +28,7 @@ import (
 type Client interface {
 	Get(context.Context, string, string) (network.RouteTable, error)
 	CreateOrUpdate(context.Context, string, string, network.RouteTable) error
+	UpdateTags(context.Context, string, string, map[string]*string) error
 	Delete(context.Context, string, string) error
 }
 
@@ -47,8 +48,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newRouteTablesClient creates a new route tables client from subscription ID.
 func newRouteTablesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.RouteTablesClient {
 	routeTablesClient := network.NewRouteTablesClientWithBaseURI(baseURI, subscriptionID)
-	routeTablesClient.Authorizer = authorizer
-	routeTablesClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&routeTablesClient.Client, authorizer)
 	return routeTablesClient
 }
 
@@ -71,6 +71,20 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName str
 	return err
 }
 
+// UpdateTags replaces the tags of a route table.
+func (ac *AzureClient) UpdateTags(ctx context.Context, resourceGroupName, rtName string, tags map[string]*string) error {
+	future, err := ac.routetables.UpdateTags(ctx, resourceGroupName, rtName, network.TagsObject{Tags: tags})
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.routetables.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.routetables)
+	return err
+}
+
 // Delete deletes the specified route table.
 func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, rtName string) error {
 	future, err := ac.routetables.Delete(ctx, resourceGroupName, rtName)
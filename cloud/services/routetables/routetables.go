@@ -18,16 +18,22 @@ package routetables
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
 )
 
 // Spec specification for route table.
 type Spec struct {
 	Name string
+	// ResourceGroup is the resource group of the route table, defaults to the cluster resource group.
+	ResourceGroup string
+	Routes        []infrav1.Route
 }
 
 // Reconcile gets/creates/updates a route table.
@@ -40,43 +46,122 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	if !ok {
 		return errors.New("invalid Route Table Specification")
 	}
+	resourceGroup := routeTableSpec.ResourceGroup
+	if resourceGroup == "" {
+		resourceGroup = s.Scope.NetworkResourceGroup()
+	}
 
-	existingRouteTable, err := s.Get(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name)
+	routes := toRoutes(routeTableSpec.Routes)
+	tags := converters.TagsToMap(s.tags(routeTableSpec))
+	existingRouteTable, err := s.Get(ctx, resourceGroup, routeTableSpec.Name)
 	if !azure.ResourceNotFound(err) {
 		if err != nil {
-			return errors.Wrapf(err, "failed to get route table %s in %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+			return errors.Wrapf(err, "failed to get route table %s in %s", routeTableSpec.Name, resourceGroup)
 		}
 
 		// route table already exists
-		// currently don't support:
-		//  1. creating separate control plane and node (#718) so update both
-		//  2. specifying your own routes via spec
-		s.Scope.NodeSubnet().RouteTable.Name = to.String(existingRouteTable.Name)
-		s.Scope.NodeSubnet().RouteTable.ID = to.String(existingRouteTable.ID)
-		s.Scope.ControlPlaneSubnet().RouteTable.Name = to.String(existingRouteTable.Name)
-		s.Scope.ControlPlaneSubnet().RouteTable.ID = to.String(existingRouteTable.ID)
+		// currently don't support creating separate control plane and node (#718) so update both
+		for _, subnet := range append(s.Scope.ControlPlaneSubnets(), s.Scope.NodeSubnets()...) {
+			if subnet.RouteTable.Name == "" || subnet.RouteTable.Name == routeTableSpec.Name {
+				subnet.RouteTable.Name = to.String(existingRouteTable.Name)
+				subnet.RouteTable.ID = to.String(existingRouteTable.ID)
+			}
+		}
 
-		return nil
+		if !converters.MapToTags(existingRouteTable.Tags).HasOwned(s.Scope.ClusterName()) {
+			// the route table was provided by the user, it is associated with the subnets as is
+			s.Scope.V(4).Info("Skipping routes reconcile of route table not owned by the cluster", "route table", routeTableSpec.Name)
+			return nil
+		}
+		var tagsChanged bool
+		tags, tagsChanged = converters.UpdateTags(existingRouteTable.Tags, s.tags(routeTableSpec), s.Scope.LastAppliedTags())
+		if len(routes) == 0 {
+			if !tagsChanged {
+				return nil
+			}
+			// only the tags changed, patch them to leave the routes and the subnet associations untouched
+			s.Scope.V(2).Info("updating route table tags", "route table", routeTableSpec.Name)
+			if err := s.Client.UpdateTags(ctx, resourceGroup, routeTableSpec.Name, tags); err != nil {
+				return errors.Wrapf(err, "failed to update tags of route table %s in resource group %s", routeTableSpec.Name, resourceGroup)
+			}
+			return nil
+		}
+		routes = mergeRoutes(existingRouteTable.RouteTablePropertiesFormat, routes)
+	} else if !strings.EqualFold(resourceGroup, s.Scope.NetworkResourceGroup()) {
+		return errors.Errorf("route table %s does not exist in resource group %s", routeTableSpec.Name, resourceGroup)
 	}
 
 	s.Scope.V(2).Info("creating route table", "route table", routeTableSpec.Name)
+	properties := &network.RouteTablePropertiesFormat{}
+	if len(routes) > 0 {
+		properties.Routes = &routes
+	}
 	err = s.Client.CreateOrUpdate(
 		ctx,
-		s.Scope.ResourceGroup(),
+		resourceGroup,
 		routeTableSpec.Name,
 		network.RouteTable{
 			Location:                   to.StringPtr(s.Scope.Location()),
-			RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+			Tags:                       tags,
+			RouteTablePropertiesFormat: properties,
 		},
 	)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create route table %s in resource group %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+		return errors.Wrapf(err, "failed to create route table %s in resource group %s", routeTableSpec.Name, resourceGroup)
 	}
 
 	s.Scope.V(2).Info("successfully created route table", "route table", routeTableSpec.Name)
 	return nil
 }
 
+// tags returns the tags of a route table owned by the cluster.
+func (s *Service) tags(routeTableSpec *Spec) infrav1.Tags {
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(routeTableSpec.Name),
+		Additional:  s.Scope.AdditionalTags(),
+	})
+}
+
+// toRoutes converts the user-defined routes of a route table spec.
+func toRoutes(specs []infrav1.Route) []network.Route {
+	var routes []network.Route
+	for _, route := range specs {
+		properties := &network.RoutePropertiesFormat{
+			AddressPrefix: to.StringPtr(route.AddressPrefix),
+			NextHopType:   network.RouteNextHopType(route.NextHopType),
+		}
+		if route.NextHopIPAddress != "" {
+			properties.NextHopIPAddress = to.StringPtr(route.NextHopIPAddress)
+		}
+		routes = append(routes, network.Route{
+			Name:                  to.StringPtr(route.Name),
+			RoutePropertiesFormat: properties,
+		})
+	}
+	return routes
+}
+
+// mergeRoutes replaces the existing routes with the user-defined routes of the same name,
+// and keeps the other ones, e.g. the pod routes added by the cloud provider.
+func mergeRoutes(existing *network.RouteTablePropertiesFormat, routes []network.Route) []network.Route {
+	if existing == nil || existing.Routes == nil {
+		return routes
+	}
+	names := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		names[to.String(route.Name)] = true
+	}
+	merged := routes
+	for _, route := range *existing.Routes {
+		if !names[to.String(route.Name)] {
+			merged = append(merged, route)
+		}
+	}
+	return merged
+}
+
 // Delete deletes the route table with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
@@ -87,14 +172,33 @@ func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	if !ok {
 		return errors.New("invalid Route Table Specification")
 	}
+	resourceGroup := routeTableSpec.ResourceGroup
+	if resourceGroup == "" {
+		resourceGroup = s.Scope.NetworkResourceGroup()
+	}
+	if !s.Scope.IsNetworkResourceGroupManaged() || !strings.EqualFold(resourceGroup, s.Scope.NetworkResourceGroup()) {
+		// only delete the route tables owned by the cluster from a pre-existing resource group,
+		// route tables provided by the user are only disassociated by the deletion of the subnets
+		routeTable, err := s.Client.Get(ctx, resourceGroup, routeTableSpec.Name)
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get route table %s in resource group %s", routeTableSpec.Name, resourceGroup)
+		}
+		if !converters.MapToTags(routeTable.Tags).HasOwned(s.Scope.ClusterName()) {
+			s.Scope.V(4).Info("Skipping deletion of route table not owned by the cluster", "route table", routeTableSpec.Name)
+			return nil
+		}
+	}
 	s.Scope.V(2).Info("deleting route table", "route table", routeTableSpec.Name)
-	err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), routeTableSpec.Name)
+	err := s.Client.Delete(ctx, resourceGroup, routeTableSpec.Name)
 	if err != nil && azure.ResourceNotFound(err) {
 		// already deleted
 		return nil
 	}
 	if err != nil {
-		return errors.Wrapf(err, "failed to delete route table %s in resource group %s", routeTableSpec.Name, s.Scope.ResourceGroup())
+		return errors.Wrapf(err, "failed to delete route table %s in resource group %s", routeTableSpec.Name, resourceGroup)
 	}
 
 	s.Scope.V(2).Info("successfully deleted route table", "route table", routeTableSpec.Name)
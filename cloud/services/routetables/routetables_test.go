@@ -34,6 +34,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -69,11 +70,15 @@ func TestInvalidRouteTableSpec(t *testing.T) {
 		Cluster: cluster,
 		AzureCluster: &infrav1.AzureCluster{
 			Spec: infrav1.AzureClusterSpec{
-				Location: "test-location",
+				Location:       "test-location",
 				ResourceGroup:  "my-rg",
 				SubscriptionID: subscriptionID,
 				NetworkSpec: infrav1.NetworkSpec{
 					Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+					Subnets: infrav1.Subnets{
+						{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+						{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+					},
 				},
 			},
 		},
@@ -102,6 +107,7 @@ func TestReconcileRouteTables(t *testing.T) {
 		name           string
 		routetableSpec Spec
 		tags           infrav1.Tags
+		additionalTags infrav1.Tags
 		expectedError  string
 		expect         func(m *mock_routetables.MockClientMockRecorder)
 	}{
@@ -156,6 +162,215 @@ func TestReconcileRouteTables(t *testing.T) {
 				m.CreateOrUpdate(context.TODO(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.RouteTable{})).Times(0)
 			},
 		},
+		{
+			name: "create route table with routes",
+			routetableSpec: Spec{
+				Name: "my-routetable",
+				Routes: []infrav1.Route{
+					{Name: "to-firewall", AddressPrefix: "0.0.0.0/0", NextHopType: infrav1.RouteNextHopTypeVirtualAppliance, NextHopIPAddress: "10.100.0.4"},
+				},
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-routetable", matchers.DiffEq(network.RouteTable{
+					Location: to.StringPtr("\btest-location"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-routetable"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{
+							{
+								Name: to.StringPtr("to-firewall"),
+								RoutePropertiesFormat: &network.RoutePropertiesFormat{
+									AddressPrefix:    to.StringPtr("0.0.0.0/0"),
+									NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+									NextHopIPAddress: to.StringPtr("10.100.0.4"),
+								},
+							},
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "update the routes of an owned route table and keep the other routes",
+			routetableSpec: Spec{
+				Name: "my-routetable",
+				Routes: []infrav1.Route{
+					{Name: "to-firewall", AddressPrefix: "0.0.0.0/0", NextHopType: infrav1.RouteNextHopTypeVirtualAppliance, NextHopIPAddress: "10.100.0.5"},
+				},
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-routetable"),
+					ID:   to.StringPtr("1"),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{
+							{Name: to.StringPtr("to-firewall")},
+							{Name: to.StringPtr("pod-route")},
+						},
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-routetable", matchers.DiffEq(network.RouteTable{
+					Location: to.StringPtr("\btest-location"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-routetable"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{
+							{
+								Name: to.StringPtr("to-firewall"),
+								RoutePropertiesFormat: &network.RoutePropertiesFormat{
+									AddressPrefix:    to.StringPtr("0.0.0.0/0"),
+									NextHopType:      network.RouteNextHopTypeVirtualAppliance,
+									NextHopIPAddress: to.StringPtr("10.100.0.5"),
+								},
+							},
+							{Name: to.StringPtr("pod-route")},
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "update the tags of an owned route table without updating its routes",
+			routetableSpec: Spec{
+				Name: "my-routetable",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			additionalTags: infrav1.Tags{"env": "prod"},
+			expectedError:  "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-routetable"),
+					ID:   to.StringPtr("1"),
+					Tags: map[string]*string{
+						"Name":  to.StringPtr("my-routetable"),
+						"owner": to.StringPtr("ops"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+					RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{
+						Routes: &[]network.Route{{Name: to.StringPtr("pod-route")}},
+					},
+				}, nil)
+				m.UpdateTags(context.TODO(), "my-rg", "my-routetable", map[string]*string{
+					"Name":  to.StringPtr("my-routetable"),
+					"env":   to.StringPtr("prod"),
+					"owner": to.StringPtr("ops"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+				})
+				m.CreateOrUpdate(context.TODO(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.RouteTable{})).Times(0)
+			},
+		},
+		{
+			name: "do not update an owned route table with up to date tags",
+			routetableSpec: Spec{
+				Name: "my-routetable",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			additionalTags: infrav1.Tags{"env": "prod"},
+			expectedError:  "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-routetable"),
+					ID:   to.StringPtr("1"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-routetable"),
+						"env":  to.StringPtr("prod"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				m.UpdateTags(context.TODO(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				m.CreateOrUpdate(context.TODO(), gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(network.RouteTable{})).Times(0)
+			},
+		},
+		{
+			name: "fail to update the tags of an owned route table",
+			routetableSpec: Spec{
+				Name: "my-routetable",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			additionalTags: infrav1.Tags{"env": "prod"},
+			expectedError:  "failed to update tags of route table my-routetable in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("my-routetable"),
+					ID:   to.StringPtr("1"),
+					Tags: map[string]*string{
+						"Name": to.StringPtr("my-routetable"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					},
+				}, nil)
+				m.UpdateTags(context.TODO(), "my-rg", "my-routetable", gomock.Any()).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+		{
+			name: "do not manage the routes of a route table provided by the user",
+			routetableSpec: Spec{
+				Name:          "hub-routetable",
+				ResourceGroup: "hub-rg",
+				Routes: []infrav1.Route{
+					{Name: "to-firewall", AddressPrefix: "0.0.0.0/0", NextHopType: infrav1.RouteNextHopTypeVirtualAppliance, NextHopIPAddress: "10.100.0.4"},
+				},
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "hub-rg", "hub-routetable").Return(network.RouteTable{
+					Name: to.StringPtr("hub-routetable"),
+					ID:   to.StringPtr("/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/routeTables/hub-routetable"),
+				}, nil)
+			},
+		},
+		{
+			name: "fail when the route table provided by the user does not exist",
+			routetableSpec: Spec{
+				Name:          "hub-routetable",
+				ResourceGroup: "hub-rg",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "route table hub-routetable does not exist in resource group hub-rg",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "hub-rg", "hub-routetable").Return(network.RouteTable{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
 		{
 			name: "fail when getting existing route table",
 			routetableSpec: Spec{
@@ -215,7 +430,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						ResourceGroup:  "my-rg",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
@@ -235,6 +450,7 @@ func TestReconcileRouteTables(t *testing.T) {
 								},
 							},
 						},
+						AdditionalTags: tc.additionalTags,
 					},
 				},
 			})
@@ -293,6 +509,22 @@ func TestDeleteRouteTable(t *testing.T) {
 				m.Delete(context.TODO(), "my-rg", "my-routetable")
 			},
 		},
+		{
+			name: "route table provided by the user is not deleted",
+			routetableSpec: Spec{
+				Name:          "hub-routetable",
+				ResourceGroup: "hub-rg",
+			},
+			tags: infrav1.Tags{
+				"Name": "my-vnet",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			},
+			expectedError: "",
+			expect: func(m *mock_routetables.MockClientMockRecorder) {
+				m.Get(context.TODO(), "hub-rg", "hub-routetable").Return(network.RouteTable{Name: to.StringPtr("hub-routetable")}, nil)
+			},
+		},
 		{
 			name: "route table already deleted",
 			routetableSpec: Spec{
@@ -350,7 +582,7 @@ func TestDeleteRouteTable(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						ResourceGroup:  "my-rg",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
@@ -360,6 +592,10 @@ func TestDeleteRouteTable(t *testing.T) {
 								ResourceGroup: "my-rg",
 								Tags:          tc.tags,
 							},
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
 						},
 					},
 				},
@@ -36,25 +36,39 @@ import (
 // Spec input specification for Get/CreateOrUpdate/Delete calls
 type (
 	Spec struct {
-		Name                   string
-		ResourceGroup          string
-		Location               string
-		ClusterName            string
-		MachinePoolName        string
-		Sku                    string
-		Capacity               int64
-		SSHKeyData             string
-		Image                  *infrav1.Image
-		OSDisk                 infrav1.OSDisk
-		DataDisks              []infrav1.DataDisk
-		CustomData             string
-		SubnetID               string
-		PublicLoadBalancerName string
-		AdditionalTags         infrav1.Tags
-		AcceleratedNetworking  *bool
+		Name                      string
+		ResourceGroup             string
+		Location                  string
+		ClusterName               string
+		MachinePoolName           string
+		Sku                       string
+		Capacity                  int64
+		SSHKeyData                string
+		AdminUsername             string
+		Image                     *infrav1.Image
+		OSDisk                    infrav1.OSDisk
+		DataDisks                 []infrav1.DataDisk
+		CustomData                string
+		SubnetID                  string
+		PublicLoadBalancerName    string
+		NetworkResourceGroup      string
+		AdditionalTags            infrav1.Tags
+		AcceleratedNetworking     *bool
+		Zones                     []string
+		SpotVMOptions             *infrav1.SpotVMOptions
+		ProximityPlacementGroupID string
+		DiskEncryptionSetID       string
+		UpgradePolicy             *infrav1exp.UpgradePolicy
+		TerminateNotification     *infrav1exp.TerminateNotification
 	}
 )
 
+const (
+	// kubeletHealthzPort is the port of the health endpoint of the kubelet, which the application health extension
+	// probes on the instances of a scale set.
+	kubeletHealthzPort = 10248
+)
+
 func (s *Service) Get(ctx context.Context, vmssSpec *Spec) (*infrav1exp.VMSS, error) {
 	vmss, err := s.Client.Get(ctx, vmssSpec.ResourceGroup, vmssSpec.Name)
 	if err != nil {
@@ -87,25 +101,38 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	}
 	vmssSpec.AdditionalTags[infrav1.ClusterAzureCloudProviderTagKey(vmssSpec.MachinePoolName)] = string(infrav1.ResourceLifecycleOwned)
 
-	if vmssSpec.AcceleratedNetworking == nil {
-		// set accelerated networking to the capability of the VMSize
+	if vmssSpec.AcceleratedNetworking == nil || *vmssSpec.AcceleratedNetworking {
+		// set accelerated networking to the capability of the VMSize, or check it when explicitly enabled
 		accelNet, err := s.ResourceSkusClient.HasAcceleratedNetworking(ctx, vmssSpec.Sku)
 		if err != nil {
 			return errors.Wrap(err, "failed to get accelerated networking capability")
 		}
+		if vmssSpec.AcceleratedNetworking != nil && !accelNet {
+			return errors.Errorf("cannot enable accelerated networking on scale set %s: VM size %s does not support it", vmssSpec.Name, vmssSpec.Sku)
+		}
 		vmssSpec.AcceleratedNetworking = to.BoolPtr(accelNet)
 	}
 
-	// Get the node outbound LB backend pool ID
-	lb, lberr := s.LoadBalancersClient.Get(ctx, vmssSpec.ResourceGroup, vmssSpec.PublicLoadBalancerName)
-	if lberr != nil {
-		return errors.Wrap(lberr, "failed to get cloud provider LB")
+	priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(vmssSpec.SpotVMOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get Spot VM options")
 	}
 
-	backendAddressPools := []compute.SubResource{
-		{
+	adminUsername := vmssSpec.AdminUsername
+	if adminUsername == "" {
+		adminUsername = azure.DefaultUserName
+	}
+
+	backendAddressPools := []compute.SubResource{}
+	if vmssSpec.PublicLoadBalancerName != "" {
+		// Get the node outbound LB backend pool ID
+		lb, lberr := s.LoadBalancersClient.Get(ctx, vmssSpec.NetworkResourceGroup, vmssSpec.PublicLoadBalancerName)
+		if lberr != nil {
+			return errors.Wrap(lberr, "failed to get cloud provider LB")
+		}
+		backendAddressPools = append(backendAddressPools, compute.SubResource{
 			ID: (*lb.BackendAddressPools)[0].ID,
-		},
+		})
 	}
 
 	vmss := compute.VirtualMachineScaleSet{
@@ -117,25 +144,29 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 			Role:        to.StringPtr(infrav1.Node),
 			Additional:  vmssSpec.AdditionalTags,
 		})),
+		Plan: converters.ImageToPlan(vmssSpec.Image),
 		Sku: &compute.Sku{
 			Name:     to.StringPtr(vmssSpec.Sku),
 			Tier:     to.StringPtr("Standard"),
 			Capacity: to.Int64Ptr(vmssSpec.Capacity),
 		},
 		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
-			UpgradePolicy: &compute.UpgradePolicy{
-				Mode: compute.UpgradeModeManual,
-			},
+			UpgradePolicy: generateUpgradePolicy(vmssSpec.UpgradePolicy),
 			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
+				Priority:               priority,
+				EvictionPolicy:         evictionPolicy,
+				BillingProfile:         billingProfile,
+				ExtensionProfile:       generateExtensionProfile(*vmssSpec),
+				ScheduledEventsProfile: generateScheduledEventsProfile(vmssSpec.TerminateNotification),
 				OsProfile: &compute.VirtualMachineScaleSetOSProfile{
 					ComputerNamePrefix: to.StringPtr(vmssSpec.Name),
-					AdminUsername:      to.StringPtr(azure.DefaultUserName),
+					AdminUsername:      to.StringPtr(adminUsername),
 					CustomData:         to.StringPtr(vmssSpec.CustomData),
 					LinuxConfiguration: &compute.LinuxConfiguration{
 						SSH: &compute.SSHConfiguration{
 							PublicKeys: &[]compute.SSHPublicKey{
 								{
-									Path:    to.StringPtr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
+									Path:    to.StringPtr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername)),
 									KeyData: to.StringPtr(vmssSpec.SSHKeyData),
 								},
 							},
@@ -173,6 +204,16 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		},
 	}
 
+	if len(vmssSpec.Zones) > 0 {
+		vmss.Zones = &vmssSpec.Zones
+	}
+
+	if vmssSpec.ProximityPlacementGroupID != "" {
+		vmss.VirtualMachineScaleSetProperties.ProximityPlacementGroup = &compute.SubResource{
+			ID: to.StringPtr(vmssSpec.ProximityPlacementGroupID),
+		}
+	}
+
 	_, err = s.Client.Get(ctx, vmssSpec.ResourceGroup, vmssSpec.Name)
 	if !azure.ResourceNotFound(err) {
 		if err != nil {
@@ -226,21 +267,52 @@ func generateStorageProfile(vmssSpec Spec) (*compute.VirtualMachineScaleSetStora
 		OsDisk: &compute.VirtualMachineScaleSetOSDisk{
 			OsType:       compute.OperatingSystemTypes(vmssSpec.OSDisk.OSType),
 			CreateOption: compute.DiskCreateOptionTypesFromImage,
-			DiskSizeGB:   to.Int32Ptr(vmssSpec.OSDisk.DiskSizeGB),
 			ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
 				StorageAccountType: compute.StorageAccountTypes(vmssSpec.OSDisk.ManagedDisk.StorageAccountType),
 			},
 		},
 	}
 
+	// an empty disk size is the size of the OS disk of the image
+	if vmssSpec.OSDisk.DiskSizeGB != 0 {
+		storageProfile.OsDisk.DiskSizeGB = to.Int32Ptr(vmssSpec.OSDisk.DiskSizeGB)
+	}
+
+	// an ephemeral OS disk is stored on the VM host and needs read-only caching
+	if vmssSpec.OSDisk.DiffDiskSettings != nil {
+		storageProfile.OsDisk.Caching = compute.CachingTypesReadOnly
+		storageProfile.OsDisk.DiffDiskSettings = &compute.DiffDiskSettings{
+			Option:    compute.DiffDiskOptions(vmssSpec.OSDisk.DiffDiskSettings.Option),
+			Placement: compute.DiffDiskPlacement(vmssSpec.OSDisk.DiffDiskSettings.Placement),
+		}
+	}
+
+	var diskEncryptionSet *compute.DiskEncryptionSetParameters
+	if vmssSpec.DiskEncryptionSetID != "" {
+		diskEncryptionSet = &compute.DiskEncryptionSetParameters{
+			ID: to.StringPtr(vmssSpec.DiskEncryptionSetID),
+		}
+		storageProfile.OsDisk.ManagedDisk.DiskEncryptionSet = diskEncryptionSet
+	}
+
 	dataDisks := []compute.VirtualMachineScaleSetDataDisk{}
 	for _, disk := range vmssSpec.DataDisks {
-		dataDisks = append(dataDisks, compute.VirtualMachineScaleSetDataDisk{
+		dataDisk := compute.VirtualMachineScaleSetDataDisk{
 			CreateOption: compute.DiskCreateOptionTypesEmpty,
 			DiskSizeGB:   to.Int32Ptr(disk.DiskSizeGB),
 			Lun:          disk.Lun,
 			Name:         to.StringPtr(azure.GenerateDataDiskName(vmssSpec.Name, disk.NameSuffix)),
-		})
+			Caching:      compute.CachingTypes(disk.CachingType),
+		}
+		if disk.ManagedDisk != nil || diskEncryptionSet != nil {
+			dataDisk.ManagedDisk = &compute.VirtualMachineScaleSetManagedDiskParameters{
+				DiskEncryptionSet: diskEncryptionSet,
+			}
+		}
+		if disk.ManagedDisk != nil {
+			dataDisk.ManagedDisk.StorageAccountType = compute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType)
+		}
+		dataDisks = append(dataDisks, dataDisk)
 	}
 	storageProfile.DataDisks = &dataDisks
 
@@ -254,6 +326,79 @@ func generateStorageProfile(vmssSpec Spec) (*compute.VirtualMachineScaleSetStora
 	return storageProfile, nil
 }
 
+// generateUpgradePolicy generates the upgrade policy of the scale set, in the Manual mode unless another one is set.
+func generateUpgradePolicy(policy *infrav1exp.UpgradePolicy) *compute.UpgradePolicy {
+	upgradePolicy := &compute.UpgradePolicy{
+		Mode: compute.UpgradeModeManual,
+	}
+	if policy == nil {
+		return upgradePolicy
+	}
+	if policy.Mode != "" {
+		upgradePolicy.Mode = compute.UpgradeMode(policy.Mode)
+	}
+	if rolling := policy.RollingUpgradePolicy; rolling != nil && upgradePolicy.Mode == compute.UpgradeModeRolling {
+		upgradePolicy.RollingUpgradePolicy = &compute.RollingUpgradePolicy{
+			MaxBatchInstancePercent:             rolling.MaxBatchInstancePercent,
+			MaxUnhealthyInstancePercent:         rolling.MaxUnhealthyInstancePercent,
+			MaxUnhealthyUpgradedInstancePercent: rolling.MaxUnhealthyUpgradedInstancePercent,
+		}
+		if rolling.PauseTimeBetweenBatches != nil {
+			// Azure expects an ISO 8601 duration
+			pause := fmt.Sprintf("PT%dS", int64(rolling.PauseTimeBetweenBatches.Seconds()))
+			upgradePolicy.RollingUpgradePolicy.PauseTimeBetweenBatches = to.StringPtr(pause)
+		}
+	}
+	return upgradePolicy
+}
+
+// generateExtensionProfile generates the extensions of the scale set instances. A rolling upgrade needs the health of
+// the instances, which the application health extension reports by probing the health endpoint of the kubelet.
+func generateExtensionProfile(vmssSpec Spec) *compute.VirtualMachineScaleSetExtensionProfile {
+	if vmssSpec.UpgradePolicy == nil || vmssSpec.UpgradePolicy.Mode != infrav1exp.UpgradeModeRolling {
+		return nil
+	}
+	extensionType := "ApplicationHealthLinux"
+	if vmssSpec.OSDisk.OSType == infrav1.WindowsOS {
+		extensionType = "ApplicationHealthWindows"
+	}
+	return &compute.VirtualMachineScaleSetExtensionProfile{
+		Extensions: &[]compute.VirtualMachineScaleSetExtension{
+			{
+				Name: to.StringPtr("HealthExtension"),
+				VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+					Publisher:               to.StringPtr("Microsoft.ManagedServices"),
+					Type:                    to.StringPtr(extensionType),
+					TypeHandlerVersion:      to.StringPtr("1.0"),
+					AutoUpgradeMinorVersion: to.BoolPtr(true),
+					Settings: map[string]interface{}{
+						"protocol":    "http",
+						"port":        kubeletHealthzPort,
+						"requestPath": "/healthz",
+					},
+				},
+			},
+		},
+	}
+}
+
+// generateScheduledEventsProfile generates the scheduled events of the scale set instances, with the Terminate
+// scheduled event when a terminate notification is set.
+func generateScheduledEventsProfile(notification *infrav1exp.TerminateNotification) *compute.ScheduledEventsProfile {
+	if notification == nil {
+		return nil
+	}
+	profile := &compute.TerminateNotificationProfile{
+		Enable: to.BoolPtr(notification.Enabled),
+	}
+	if notification.NotBeforeTimeout != nil {
+		// Azure expects an ISO 8601 duration
+		timeout := fmt.Sprintf("PT%dM", int64(notification.NotBeforeTimeout.Minutes()))
+		profile.NotBeforeTimeout = to.StringPtr(timeout)
+	}
+	return &compute.ScheduledEventsProfile{TerminateNotificationProfile: profile}
+}
+
 func getVMSSUpdateFromVMSS(vmss compute.VirtualMachineScaleSet) (compute.VirtualMachineScaleSetUpdate, error) {
 	json, err := vmss.MarshalJSON()
 	if err != nil {
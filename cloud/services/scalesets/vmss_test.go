@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
@@ -67,6 +68,10 @@ func TestNewService(t *testing.T) {
 				SubscriptionID: "123",
 				NetworkSpec: infrav1.NetworkSpec{
 					Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+					Subnets: infrav1.Subnets{
+						{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+						{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+					},
 				},
 			},
 		},
@@ -99,6 +104,7 @@ func TestService_Get(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -129,6 +135,7 @@ func TestService_Get(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -160,6 +167,7 @@ func TestService_Get(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -255,6 +263,7 @@ func TestService_Reconcile(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -376,6 +385,7 @@ func TestService_Reconcile(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -490,6 +500,7 @@ func TestService_Reconcile(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -699,6 +710,7 @@ func TestService_Delete(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -727,6 +739,7 @@ func TestService_Delete(t *testing.T) {
 				return &Spec{
 					Name:                   mpScope.Name(),
 					ResourceGroup:          scope.AzureCluster.Spec.ResourceGroup,
+					NetworkResourceGroup:   scope.AzureCluster.Spec.ResourceGroup,
 					Location:               scope.AzureCluster.Spec.Location,
 					ClusterName:            scope.Cluster.Name,
 					SubnetID:               scope.AzureCluster.Spec.NetworkSpec.Subnets[0].ID,
@@ -839,6 +852,117 @@ func TestGetVMSSUpdateFromVMSS(t *testing.T) {
 	g.Expect(result).To(gomega.Equal(expectedUpdate))
 }
 
+func TestGenerateUpgradePolicy(t *testing.T) {
+	tests := []struct {
+		name                     string
+		spec                     Spec
+		expectedUpgradePolicy    *compute.UpgradePolicy
+		expectedExtensionProfile *compute.VirtualMachineScaleSetExtensionProfile
+	}{
+		{
+			name:                  "manual by default",
+			expectedUpgradePolicy: &compute.UpgradePolicy{Mode: compute.UpgradeModeManual},
+		},
+		{
+			name:                  "automatic",
+			spec:                  Spec{UpgradePolicy: &infrav1exp.UpgradePolicy{Mode: infrav1exp.UpgradeModeAutomatic}},
+			expectedUpgradePolicy: &compute.UpgradePolicy{Mode: compute.UpgradeModeAutomatic},
+		},
+		{
+			name: "rolling",
+			spec: Spec{
+				OSDisk: infrav1.OSDisk{OSType: "Linux"},
+				UpgradePolicy: &infrav1exp.UpgradePolicy{
+					Mode: infrav1exp.UpgradeModeRolling,
+					RollingUpgradePolicy: &infrav1exp.RollingUpgradePolicy{
+						MaxBatchInstancePercent: to.Int32Ptr(10),
+						PauseTimeBetweenBatches: &metav1.Duration{Duration: 90 * time.Second},
+					},
+				},
+			},
+			expectedUpgradePolicy: &compute.UpgradePolicy{
+				Mode: compute.UpgradeModeRolling,
+				RollingUpgradePolicy: &compute.RollingUpgradePolicy{
+					MaxBatchInstancePercent: to.Int32Ptr(10),
+					PauseTimeBetweenBatches: to.StringPtr("PT90S"),
+				},
+			},
+			expectedExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{
+				Extensions: &[]compute.VirtualMachineScaleSetExtension{
+					{
+						Name: to.StringPtr("HealthExtension"),
+						VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+							Publisher:               to.StringPtr("Microsoft.ManagedServices"),
+							Type:                    to.StringPtr("ApplicationHealthLinux"),
+							TypeHandlerVersion:      to.StringPtr("1.0"),
+							AutoUpgradeMinorVersion: to.BoolPtr(true),
+							Settings: map[string]interface{}{
+								"protocol":    "http",
+								"port":        kubeletHealthzPort,
+								"requestPath": "/healthz",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(generateUpgradePolicy(tc.spec.UpgradePolicy)).To(gomega.Equal(tc.expectedUpgradePolicy))
+			g.Expect(generateExtensionProfile(tc.spec)).To(gomega.Equal(tc.expectedExtensionProfile))
+		})
+	}
+}
+
+func TestGenerateScheduledEventsProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification *infrav1exp.TerminateNotification
+		expected     *compute.ScheduledEventsProfile
+	}{
+		{
+			name: "no terminate notification",
+		},
+		{
+			name:         "terminate notification with the default timeout",
+			notification: &infrav1exp.TerminateNotification{Enabled: true},
+			expected: &compute.ScheduledEventsProfile{
+				TerminateNotificationProfile: &compute.TerminateNotificationProfile{Enable: to.BoolPtr(true)},
+			},
+		},
+		{
+			name: "terminate notification with a timeout",
+			notification: &infrav1exp.TerminateNotification{
+				Enabled:          true,
+				NotBeforeTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+			expected: &compute.ScheduledEventsProfile{
+				TerminateNotificationProfile: &compute.TerminateNotificationProfile{
+					Enable:           to.BoolPtr(true),
+					NotBeforeTimeout: to.StringPtr("PT10M"),
+				},
+			},
+		},
+		{
+			name:         "disabled terminate notification",
+			notification: &infrav1exp.TerminateNotification{},
+			expected: &compute.ScheduledEventsProfile{
+				TerminateNotificationProfile: &compute.TerminateNotificationProfile{Enable: to.BoolPtr(false)},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(generateScheduledEventsProfile(tc.notification)).To(gomega.Equal(tc.expected))
+		})
+	}
+}
+
 func getScopes(g *gomega.GomegaWithT) (*scope.ClusterScope, *scope.MachinePoolScope) {
 	cluster := &clusterv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
@@ -859,7 +983,11 @@ func getScopes(g *gomega.GomegaWithT) (*scope.ClusterScope, *scope.MachinePoolSc
 					Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
 					Subnets: infrav1.Subnets{
 						{
-							ID: "subnet0.id",
+							ID:   "subnet0.id",
+							Role: infrav1.SubnetNode,
+						},
+						{
+							Role: infrav1.SubnetControlPlane,
 						},
 					},
 				},
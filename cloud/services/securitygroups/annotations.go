@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securitygroups
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SecurityRulesLastAppliedAnnotation is the key for the AzureCluster annotation
+	// which tracks the additional security rules applied to each security group
+	// of the cluster, so rules removed from the spec can be deleted from Azure.
+	SecurityRulesLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-security-rules"
+)
+
+// lastAppliedSecurityRules returns the names of the additional rules applied to the security group during the last reconcile.
+func (s *Service) lastAppliedSecurityRules(nsgName string) (map[string]bool, error) {
+	applied, err := s.securityRulesAnnotation()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, name := range applied[nsgName] {
+		names[name] = true
+	}
+	return names, nil
+}
+
+// setLastAppliedSecurityRules records the names of the additional rules applied to the security group.
+func (s *Service) setLastAppliedSecurityRules(nsgName string, rules map[string]network.SecurityRule) error {
+	applied, err := s.securityRulesAnnotation()
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		if _, ok := applied[nsgName]; !ok {
+			return nil
+		}
+		delete(applied, nsgName)
+	} else {
+		names := make([]string, 0, len(rules))
+		for name := range rules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		applied[nsgName] = names
+	}
+
+	annotations := s.Scope.AzureCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if len(applied) == 0 {
+		delete(annotations, SecurityRulesLastAppliedAnnotation)
+	} else {
+		b, err := json.Marshal(applied)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal last applied security rules")
+		}
+		annotations[SecurityRulesLastAppliedAnnotation] = string(b)
+	}
+	s.Scope.AzureCluster.SetAnnotations(annotations)
+	return nil
+}
+
+// securityRulesAnnotation returns the additional rule names by security group name stored in the AzureCluster annotation.
+func (s *Service) securityRulesAnnotation() (map[string][]string, error) {
+	applied := make(map[string][]string)
+	value := s.Scope.AzureCluster.GetAnnotations()[SecurityRulesLastAppliedAnnotation]
+	if value == "" {
+		return applied, nil
+	}
+	if err := json.Unmarshal([]byte(value), &applied); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s annotation", SecurityRulesLastAppliedAnnotation)
+	}
+	return applied, nil
+}
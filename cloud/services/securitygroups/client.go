@@ -47,8 +47,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newSecurityGroupsClient creates a new security groups client from subscription ID.
 func newSecurityGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.SecurityGroupsClient {
 	securityGroupsClient := network.NewSecurityGroupsClientWithBaseURI(baseURI, subscriptionID)
-	securityGroupsClient.Authorizer = authorizer
-	securityGroupsClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&securityGroupsClient.Client, authorizer)
 	return securityGroupsClient
 }
 
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securitygroups
+
+import (
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+const (
+	sshRuleName           = "allow_ssh"
+	apiServerRuleName     = "allow_apiserver"
+	apiServerIPv6RuleName = "allow_apiserver_ipv6"
+)
+
+// defaultRuleNames are the names of the default rules of the control plane security group. A default rule which is
+// no longer desired is deleted, even if it was created before the applied rules were recorded in the annotation.
+var defaultRuleNames = map[string]bool{
+	sshRuleName:           true,
+	apiServerRuleName:     true,
+	apiServerIPv6RuleName: true,
+}
+
+// controlPlaneDefaultRules returns the default rules of the control plane security group: SSH from any source unless
+// it is disabled, and the API server port from the allowed CIDRs, or from any source when none are set. The default
+// rules of Azure already allow the traffic from the vnet and the health probes of the load balancers, so restricted
+// sources only have to include the public IPs of the cluster, through which its machines reach a public API server.
+// Azure rules can't mix IPv4 and IPv6 sources, IPv6 sources get a rule of their own.
+func (s *Service) controlPlaneDefaultRules() map[string]network.SecurityRule {
+	networkSpec := s.Scope.AzureCluster.Spec.NetworkSpec
+	rules := make(map[string]network.SecurityRule)
+	if !networkSpec.SSHDisabled {
+		rules[sshRuleName] = newDefaultRule(sshRuleName, "Allow SSH", 100, "22", []string{"*"})
+	}
+
+	apiServerPort := strconv.Itoa(int(s.Scope.APIServerPort()))
+	if len(networkSpec.AllowedAPIServerCIDRs) == 0 {
+		rules[apiServerRuleName] = newDefaultRule(apiServerRuleName, "Allow K8s API Server", 101, apiServerPort, []string{"*"})
+		return rules
+	}
+
+	var ipv4Sources, ipv6Sources []string
+	seen := make(map[string]bool)
+	sources := append(append([]string{}, networkSpec.AllowedAPIServerCIDRs...), s.clusterPublicIPs()...)
+	for _, source := range sources {
+		if source == "" || seen[source] {
+			continue
+		}
+		seen[source] = true
+		ip, _, err := net.ParseCIDR(source)
+		if err != nil {
+			ip = net.ParseIP(source)
+		}
+		if ip != nil && ip.To4() == nil {
+			ipv6Sources = append(ipv6Sources, source)
+		} else {
+			ipv4Sources = append(ipv4Sources, source)
+		}
+	}
+	sort.Strings(ipv4Sources)
+	sort.Strings(ipv6Sources)
+	if len(ipv4Sources) > 0 {
+		rules[apiServerRuleName] = newDefaultRule(apiServerRuleName, "Allow K8s API Server", 101, apiServerPort, ipv4Sources)
+	}
+	if len(ipv6Sources) > 0 {
+		rules[apiServerIPv6RuleName] = newDefaultRule(apiServerIPv6RuleName, "Allow K8s API Server over IPv6", 102, apiServerPort, ipv6Sources)
+	}
+	return rules
+}
+
+// lbHealthProbeRule returns the rule of the control plane security group allowing the health probes of the load
+// balancers, which come from the AzureLoadBalancer service tag, to the API server port. Azure allows them by default,
+// but with the lowest priority, so a rule denying the inbound traffic would otherwise block them and take the API
+// server out of the load balancers. The rule has a reserved priority, ahead of the rules set in the spec.
+func (s *Service) lbHealthProbeRule() network.SecurityRule {
+	apiServerPort := strconv.Itoa(int(s.Scope.APIServerPort()))
+	return newDefaultRule(infrav1.LBHealthProbeSecurityRuleName, "Allow Azure load balancer health probes",
+		infrav1.LBHealthProbeSecurityRulePriority, apiServerPort, []string{"AzureLoadBalancer"})
+}
+
+// clusterPublicIPs returns the public IP addresses of the load balancers of the cluster known from its network status.
+// The control plane machines egress through the public API server load balancer, and the nodes through the node
+// outbound load balancer.
+func (s *Service) clusterPublicIPs() []string {
+	status := s.Scope.Network()
+	ips := []string{status.APIServerIP.IPAddress, status.APIServerIPv6.IPAddress}
+	return append(ips, status.NodeOutboundIPs...)
+}
+
+func newDefaultRule(name, description string, priority int32, destinationPort string, sources []string) network.SecurityRule {
+	rule := network.SecurityRule{
+		Name: to.StringPtr(name),
+		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+			Description:              to.StringPtr(description),
+			Protocol:                 network.SecurityRuleProtocolTCP,
+			SourcePortRange:          to.StringPtr("*"),
+			DestinationAddressPrefix: to.StringPtr("*"),
+			DestinationPortRange:     to.StringPtr(destinationPort),
+			Access:                   network.SecurityRuleAccessAllow,
+			Direction:                network.SecurityRuleDirectionInbound,
+			Priority:                 to.Int32Ptr(priority),
+		},
+	}
+	if len(sources) == 1 {
+		rule.SourceAddressPrefix = to.StringPtr(sources[0])
+	} else {
+		rule.SourceAddressPrefixes = &sources
+	}
+	return rule
+}
+
+// sourceAddressPrefixes returns the sorted source address prefixes of a rule, whether it has one or several.
+func sourceAddressPrefixes(rule network.SecurityRule) []string {
+	var prefixes []string
+	if to.String(rule.SourceAddressPrefix) != "" {
+		prefixes = append(prefixes, to.String(rule.SourceAddressPrefix))
+	}
+	if rule.SourceAddressPrefixes != nil {
+		prefixes = append(prefixes, *rule.SourceAddressPrefixes...)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
@@ -18,6 +18,7 @@ package securitygroups
 
 import (
 	"context"
+	"reflect"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
@@ -25,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
 )
 
 // Spec specification for network security groups
@@ -44,9 +46,9 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return errors.New("invalid security groups specification")
 	}
 
-	securityGroup, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), nsgSpec.Name)
+	securityGroup, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name)
 	if err != nil && !azure.ResourceNotFound(err) {
-		return errors.Wrapf(err, "failed to get NSG %s in %s", nsgSpec.Name, s.Scope.ResourceGroup())
+		return errors.Wrapf(err, "failed to get NSG %s in %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
 	}
 
 	nsgExists := false
@@ -57,48 +59,118 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	}
 
 	ingressRules := make(map[string]network.SecurityRule, 0)
+	additionalRules := make(map[string]network.SecurityRule, 0)
+	defaultRulesManaged := false
 
 	if nsgSpec.IsControlPlane {
-		// Add any specified ingress rules from controlplane security group spec
-		cpSubnet := s.Scope.ControlPlaneSubnet()
-		if cpSubnet != nil && len(cpSubnet.SecurityGroup.IngressRules) > 0 {
+		// Add any specified ingress rules from the first control plane subnet using this security group
+		var cpSubnet *infrav1.SubnetSpec
+		for _, subnet := range s.Scope.ControlPlaneSubnets() {
+			if subnet.SecurityGroup.Name == nsgSpec.Name {
+				cpSubnet = subnet
+				break
+			}
+		}
+		if cpSubnet != nil {
 			for _, ingressRule := range cpSubnet.SecurityGroup.IngressRules {
 				ingressRules[ingressRule.Name] = newIngressSecurityRule(*ingressRule)
 			}
+			for _, rule := range cpSubnet.SecurityGroup.SecurityRules {
+				additionalRules[rule.Name] = newSecurityRule(*rule)
+			}
+			// The default rules are only added when no ingress rules are specified, and are then kept up to date like the additional rules.
+			if cpSubnet.SecurityGroup.IngressRules == nil {
+				defaultRulesManaged = true
+				for name, rule := range s.controlPlaneDefaultRules() {
+					if _, ok := additionalRules[name]; !ok {
+						additionalRules[name] = rule
+					}
+				}
+			}
+			// The health probe rule is always added, and replaces a custom rule of the same name.
+			delete(ingressRules, infrav1.LBHealthProbeSecurityRuleName)
+			additionalRules[infrav1.LBHealthProbeSecurityRuleName] = s.lbHealthProbeRule()
 		}
 	} else {
-		// Add any specified ingress rules from node security group spec
-		nodeSubnet := s.Scope.NodeSubnet()
-		if nodeSubnet != nil && len(nodeSubnet.SecurityGroup.IngressRules) > 0 {
+		// Add any specified ingress rules from the node subnets using this security group
+		for _, nodeSubnet := range s.Scope.NodeSubnets() {
+			if nodeSubnet.SecurityGroup.Name != nsgSpec.Name {
+				continue
+			}
 			for _, ingressRule := range nodeSubnet.SecurityGroup.IngressRules {
 				ingressRules[ingressRule.Name] = newIngressSecurityRule(*ingressRule)
 			}
+			for _, rule := range nodeSubnet.SecurityGroup.SecurityRules {
+				additionalRules[rule.Name] = newSecurityRule(*rule)
+			}
 		}
 	}
 
-	if nsgExists {
-		// Check if the expected rules are present
-		update := false
-		for _, rule := range ingressRules {
-			if !ruleExists(securityRules, rule) {
-				update = true
-				securityRules = append(securityRules, rule)
-			}
+	lastApplied, err := s.lastAppliedSecurityRules(nsgSpec.Name)
+	if err != nil {
+		return err
+	}
+
+	// Drop the additional and default rules that were removed from the spec or changed since the last reconcile,
+	// rules created by other tooling such as the cloud provider are left untouched.
+	update := false
+	currentRules := make([]network.SecurityRule, 0, len(securityRules))
+	for _, rule := range securityRules {
+		name := to.String(rule.Name)
+		desired, ok := additionalRules[name]
+		managed := lastApplied[name] || (defaultRulesManaged && defaultRuleNames[name])
+		if (!ok && managed) || (ok && !securityRuleEqual(rule, desired)) {
+			update = true
+			continue
 		}
-		if !update {
-			// Skip update for control-plane NSG as the required default rules are present
-			s.Scope.V(2).Info("security group exists and no default rules are missing, skipping update", "security group", nsgSpec.Name)
-			return nil
+		currentRules = append(currentRules, rule)
+	}
+	securityRules = currentRules
+
+	// Check if the expected rules are present
+	for _, rule := range ingressRules {
+		if !ruleExists(securityRules, rule) {
+			update = true
+			securityRules = append(securityRules, rule)
 		}
-	} else {
-		s.Scope.V(2).Info("applying missing default rules for control plane security group", "security group", nsgSpec.Name)
-		for _, rule := range ingressRules {
+	}
+	for name, rule := range additionalRules {
+		if !ruleNameExists(securityRules, name) {
+			update = true
 			securityRules = append(securityRules, rule)
 		}
 	}
 
+	if err := checkPriorityCollisions(securityRules); err != nil {
+		return errors.Wrapf(err, "invalid security rules for security group %s", nsgSpec.Name)
+	}
+
+	desiredTags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(nsgSpec.Name),
+		Additional:  s.Scope.AdditionalTags(),
+	})
+	tags := converters.TagsToMap(desiredTags)
+	if nsgExists {
+		// Keep the tags of the existing NSG, an NSG which was not created by the provider must not become owned by the cluster.
+		tags = securityGroup.Tags
+		if converters.MapToTags(securityGroup.Tags).HasOwned(s.Scope.ClusterName()) {
+			var tagsChanged bool
+			tags, tagsChanged = converters.UpdateTags(securityGroup.Tags, desiredTags, s.Scope.LastAppliedTags())
+			update = update || tagsChanged
+		}
+	}
+
+	if nsgExists && !update {
+		// Skip update as the required rules and tags are present
+		s.Scope.V(2).Info("security group exists and no rules are missing, skipping update", "security group", nsgSpec.Name)
+		return s.setLastAppliedSecurityRules(nsgSpec.Name, additionalRules)
+	}
+
 	sg := network.SecurityGroup{
 		Location: to.StringPtr(s.Scope.Location()),
+		Tags:     tags,
 		SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
 			SecurityRules: &securityRules,
 		},
@@ -108,13 +180,56 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		sg.Etag = securityGroup.Etag
 	}
 	s.Scope.V(2).Info("creating security group", "security group", nsgSpec.Name)
-	err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), nsgSpec.Name, sg)
+	err = s.Client.CreateOrUpdate(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name, sg)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create security group %s in resource group %s", nsgSpec.Name, s.Scope.ResourceGroup())
+		return errors.Wrapf(err, "failed to create security group %s in resource group %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
 	}
 
 	s.Scope.V(2).Info("created security group", "security group", nsgSpec.Name)
-	return err
+	return s.setLastAppliedSecurityRules(nsgSpec.Name, additionalRules)
+}
+
+func ruleNameExists(rules []network.SecurityRule, name string) bool {
+	for _, existingRule := range rules {
+		if strings.EqualFold(to.String(existingRule.Name), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// securityRuleEqual returns true if the existing rule matches the desired one.
+func securityRuleEqual(existing network.SecurityRule, desired network.SecurityRule) bool {
+	if existing.SecurityRulePropertiesFormat == nil || desired.SecurityRulePropertiesFormat == nil {
+		return existing.SecurityRulePropertiesFormat == desired.SecurityRulePropertiesFormat
+	}
+	return to.String(existing.Description) == to.String(desired.Description) &&
+		existing.Protocol == desired.Protocol &&
+		existing.Direction == desired.Direction &&
+		existing.Access == desired.Access &&
+		to.Int32(existing.Priority) == to.Int32(desired.Priority) &&
+		reflect.DeepEqual(sourceAddressPrefixes(existing), sourceAddressPrefixes(desired)) &&
+		to.String(existing.SourcePortRange) == to.String(desired.SourcePortRange) &&
+		to.String(existing.DestinationAddressPrefix) == to.String(desired.DestinationAddressPrefix) &&
+		to.String(existing.DestinationPortRange) == to.String(desired.DestinationPortRange)
+}
+
+// checkPriorityCollisions returns an error if two rules of the same direction share a priority.
+func checkPriorityCollisions(rules []network.SecurityRule) error {
+	priorities := make(map[network.SecurityRuleDirection]map[int32]string)
+	for _, rule := range rules {
+		if rule.SecurityRulePropertiesFormat == nil || rule.Priority == nil {
+			continue
+		}
+		if priorities[rule.Direction] == nil {
+			priorities[rule.Direction] = make(map[int32]string)
+		}
+		if other, ok := priorities[rule.Direction][*rule.Priority]; ok {
+			return errors.Errorf("%s security rules %s and %s both have priority %d", strings.ToLower(string(rule.Direction)), other, to.String(rule.Name), *rule.Priority)
+		}
+		priorities[rule.Direction][*rule.Priority] = to.String(rule.Name)
+	}
+	return nil
 }
 
 func ruleExists(rules []network.SecurityRule, rule network.SecurityRule) bool {
@@ -167,20 +282,65 @@ func newIngressSecurityRule(ingress infrav1.IngressRule) network.SecurityRule {
 	return secRule
 }
 
+func newSecurityRule(rule infrav1.SecurityRule) network.SecurityRule {
+	secRule := network.SecurityRule{
+		Name: to.StringPtr(rule.Name),
+		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+			Description:              to.StringPtr(rule.Description),
+			SourceAddressPrefix:      rule.Source,
+			SourcePortRange:          rule.SourcePorts,
+			DestinationAddressPrefix: rule.Destination,
+			DestinationPortRange:     rule.DestinationPorts,
+			Access:                   network.SecurityRuleAccessAllow,
+			Direction:                network.SecurityRuleDirectionInbound,
+			Priority:                 to.Int32Ptr(rule.Priority),
+		},
+	}
+
+	if rule.Direction == infrav1.SecurityRuleDirectionOutbound {
+		secRule.SecurityRulePropertiesFormat.Direction = network.SecurityRuleDirectionOutbound
+	}
+
+	switch rule.Protocol {
+	case infrav1.SecurityGroupProtocolAll:
+		secRule.SecurityRulePropertiesFormat.Protocol = network.SecurityRuleProtocolAsterisk
+	case infrav1.SecurityGroupProtocolTCP:
+		secRule.SecurityRulePropertiesFormat.Protocol = network.SecurityRuleProtocolTCP
+	case infrav1.SecurityGroupProtocolUDP:
+		secRule.SecurityRulePropertiesFormat.Protocol = network.SecurityRuleProtocolUDP
+	}
+
+	return secRule
+}
+
 // Delete deletes the network security group with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	nsgSpec, ok := spec.(*Spec)
 	if !ok {
 		return errors.New("invalid security groups specification")
 	}
+	if !s.Scope.IsNetworkResourceGroupManaged() {
+		// only delete the security groups owned by the cluster from a pre-existing resource group
+		securityGroup, err := s.Client.Get(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name)
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get security group %s in resource group %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
+		}
+		if !converters.MapToTags(securityGroup.Tags).HasOwned(s.Scope.ClusterName()) {
+			s.Scope.V(4).Info("Skipping deletion of security group not owned by the cluster", "security group", nsgSpec.Name)
+			return nil
+		}
+	}
 	s.Scope.V(2).Info("deleting security group", "security group", nsgSpec.Name)
-	err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), nsgSpec.Name)
+	err := s.Client.Delete(ctx, s.Scope.NetworkResourceGroup(), nsgSpec.Name)
 	if err != nil && azure.ResourceNotFound(err) {
 		// already deleted
 		return nil
 	}
 	if err != nil {
-		return errors.Wrapf(err, "failed to delete security group %s in resource group %s", nsgSpec.Name, s.Scope.ResourceGroup())
+		return errors.Wrapf(err, "failed to delete security group %s in resource group %s", nsgSpec.Name, s.Scope.NetworkResourceGroup())
 	}
 
 	s.Scope.V(2).Info("successfully deleted security group", "security group", nsgSpec.Name)
@@ -18,19 +18,23 @@ package securitygroups
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups/mock_securitygroups"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -110,6 +114,10 @@ func TestReconcileSecurityGroups(t *testing.T) {
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet: *tc.vnetSpec,
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
 						},
 					},
 				},
@@ -130,6 +138,461 @@ func TestReconcileSecurityGroups(t *testing.T) {
 	}
 }
 
+func TestReconcileSecurityRules(t *testing.T) {
+	customRule := &infrav1.SecurityRule{
+		Name:             "allow_port_50000",
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionInbound,
+		Priority:         200,
+		SourcePorts:      to.StringPtr("*"),
+		DestinationPorts: to.StringPtr("50000"),
+		Source:           to.StringPtr("10.0.0.0/16"),
+		Destination:      to.StringPtr("*"),
+	}
+	outboundRule := &infrav1.SecurityRule{
+		Name:             "deny_smtp",
+		Protocol:         infrav1.SecurityGroupProtocolTCP,
+		Direction:        infrav1.SecurityRuleDirectionOutbound,
+		Priority:         200,
+		DestinationPorts: to.StringPtr("25"),
+	}
+	cloudProviderRule := network.SecurityRule{
+		Name: to.StringPtr("a1b2c3-TCP-80-Internet"),
+		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+			Direction: network.SecurityRuleDirectionInbound,
+			Priority:  to.Int32Ptr(500),
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		securityRules infrav1.SecurityRules
+		annotations   map[string]string
+		existingRules *[]network.SecurityRule
+		expectUpdate  bool
+		expectedRules []string
+		expectedError string
+	}{
+		{
+			name:          "security group is created with inbound and outbound rules",
+			securityRules: infrav1.SecurityRules{customRule, outboundRule},
+			expectUpdate:  true,
+			expectedRules: []string{"allow_port_50000", "deny_smtp"},
+		},
+		{
+			name:          "rules that already exist are not updated",
+			securityRules: infrav1.SecurityRules{customRule},
+			existingRules: &[]network.SecurityRule{cloudProviderRule, newSecurityRule(*customRule)},
+			expectUpdate:  false,
+		},
+		{
+			name:          "rule removed from the spec is deleted, other rules are preserved",
+			securityRules: infrav1.SecurityRules{outboundRule},
+			annotations:   map[string]string{SecurityRulesLastAppliedAnnotation: `{"my-sg":["allow_port_50000","deny_smtp"]}`},
+			existingRules: &[]network.SecurityRule{cloudProviderRule, newSecurityRule(*customRule), newSecurityRule(*outboundRule)},
+			expectUpdate:  true,
+			expectedRules: []string{"a1b2c3-TCP-80-Internet", "deny_smtp"},
+		},
+		{
+			name: "changed rule is replaced",
+			securityRules: infrav1.SecurityRules{
+				{
+					Name:             "allow_port_50000",
+					Protocol:         infrav1.SecurityGroupProtocolTCP,
+					Direction:        infrav1.SecurityRuleDirectionInbound,
+					Priority:         200,
+					DestinationPorts: to.StringPtr("50001"),
+				},
+			},
+			annotations:   map[string]string{SecurityRulesLastAppliedAnnotation: `{"my-sg":["allow_port_50000"]}`},
+			existingRules: &[]network.SecurityRule{newSecurityRule(*customRule)},
+			expectUpdate:  true,
+			expectedRules: []string{"allow_port_50000"},
+		},
+		{
+			name: "priority collision with an existing rule",
+			securityRules: infrav1.SecurityRules{
+				{
+					Name:      "allow_http",
+					Protocol:  infrav1.SecurityGroupProtocolTCP,
+					Direction: infrav1.SecurityRuleDirectionInbound,
+					Priority:  500,
+				},
+			},
+			existingRules: &[]network.SecurityRule{cloudProviderRule},
+			expectedError: "invalid security rules for security group my-sg: inbound security rules a1b2c3-TCP-80-Internet and allow_http both have priority 500",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			sgMock := mock_securitygroups.NewMockClient(mockCtrl)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, cluster)
+
+			existing := network.SecurityGroup{}
+			if tc.existingRules != nil {
+				existing = network.SecurityGroup{
+					Name: to.StringPtr("my-sg"),
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: tc.existingRules,
+					},
+				}
+			}
+			sgMock.EXPECT().Get(context.TODO(), "my-rg", "my-sg").Return(existing, nil)
+			var updated network.SecurityGroup
+			if tc.expectUpdate {
+				sgMock.EXPECT().CreateOrUpdate(context.TODO(), "my-rg", "my-sg", gomock.AssignableToTypeOf(network.SecurityGroup{})).
+					Do(func(_ context.Context, _ string, _ string, sg network.SecurityGroup) { updated = sg })
+			}
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  client,
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+					Spec: infrav1.AzureClusterSpec{
+						Location:       "test-location",
+						ResourceGroup:  "my-rg",
+						SubscriptionID: subscriptionID,
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+								{
+									Name: "node-subnet",
+									Role: infrav1.SubnetNode,
+									SecurityGroup: infrav1.SecurityGroup{
+										Name:          "my-sg",
+										SecurityRules: tc.securityRules,
+									},
+								},
+							},
+						},
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &Service{
+				Scope:  clusterScope,
+				Client: sgMock,
+			}
+
+			err = s.Reconcile(context.TODO(), &Spec{Name: "my-sg"})
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectUpdate {
+				var names []string
+				for _, rule := range *updated.SecurityRules {
+					names = append(names, to.String(rule.Name))
+				}
+				g.Expect(names).To(ConsistOf(tc.expectedRules))
+			}
+			var names []string
+			for _, rule := range tc.securityRules {
+				names = append(names, rule.Name)
+			}
+			g.Expect(clusterScope.AzureCluster.Annotations[SecurityRulesLastAppliedAnnotation]).To(MatchJSON(fmt.Sprintf(`{"my-sg":["%s"]}`, strings.Join(names, `","`))))
+		})
+	}
+}
+
+func TestReconcileDefaultSecurityRules(t *testing.T) {
+	sshRule := newDefaultRule("allow_ssh", "Allow SSH", 100, "22", []string{"*"})
+	apiServerRule := newDefaultRule("allow_apiserver", "Allow K8s API Server", 101, "6443", []string{"*"})
+	healthProbeRule := newDefaultRule("allow_lb_health_probes", "Allow Azure load balancer health probes", 103, "6443", []string{"AzureLoadBalancer"})
+
+	testcases := []struct {
+		name            string
+		networkSpec     infrav1.NetworkSpec
+		status          infrav1.Network
+		ingressRules    infrav1.IngressRules
+		existingRules   *[]network.SecurityRule
+		expectUpdate    bool
+		expectedSources map[string][]string
+	}{
+		{
+			name:         "default rules allow SSH and the API server from any source",
+			expectUpdate: true,
+			expectedSources: map[string][]string{
+				"allow_ssh":              {"*"},
+				"allow_apiserver":        {"*"},
+				"allow_lb_health_probes": {"AzureLoadBalancer"},
+			},
+		},
+		{
+			name:          "default rules that already exist are not updated",
+			existingRules: &[]network.SecurityRule{sshRule, apiServerRule, healthProbeRule},
+			expectUpdate:  false,
+		},
+		{
+			name:          "disabled SSH rule is deleted",
+			networkSpec:   infrav1.NetworkSpec{SSHDisabled: true},
+			existingRules: &[]network.SecurityRule{sshRule, apiServerRule, healthProbeRule},
+			expectUpdate:  true,
+			expectedSources: map[string][]string{
+				"allow_apiserver":        {"*"},
+				"allow_lb_health_probes": {"AzureLoadBalancer"},
+			},
+		},
+		{
+			name:        "API server rule is restricted to the allowed CIDRs and the public IPs of the cluster",
+			networkSpec: infrav1.NetworkSpec{SSHDisabled: true, AllowedAPIServerCIDRs: []string{"203.0.113.0/24", "2001:db8::/32"}},
+			status: infrav1.Network{
+				APIServerIP:     infrav1.PublicIP{IPAddress: "20.1.2.3"},
+				APIServerIPv6:   infrav1.PublicIP{IPAddress: "2603:1030::1"},
+				NodeOutboundIPs: []string{"20.4.5.6"},
+			},
+			existingRules: &[]network.SecurityRule{sshRule, apiServerRule, healthProbeRule},
+			expectUpdate:  true,
+			expectedSources: map[string][]string{
+				"allow_apiserver":        {"20.1.2.3", "20.4.5.6", "203.0.113.0/24"},
+				"allow_apiserver_ipv6":   {"2001:db8::/32", "2603:1030::1"},
+				"allow_lb_health_probes": {"AzureLoadBalancer"},
+			},
+		},
+		{
+			name: "default rules are not managed with custom ingress rules",
+			ingressRules: infrav1.IngressRules{
+				{
+					Name:             "allow_ssh",
+					Description:      "Allow SSH",
+					Priority:         100,
+					Protocol:         infrav1.SecurityGroupProtocolTCP,
+					Source:           to.StringPtr("*"),
+					SourcePorts:      to.StringPtr("*"),
+					Destination:      to.StringPtr("*"),
+					DestinationPorts: to.StringPtr("22"),
+				},
+			},
+			networkSpec:   infrav1.NetworkSpec{SSHDisabled: true},
+			existingRules: &[]network.SecurityRule{sshRule, apiServerRule, healthProbeRule},
+			expectUpdate:  false,
+		},
+		{
+			name: "health probe rule is added with custom ingress rules",
+			ingressRules: infrav1.IngressRules{
+				{
+					Name:             "allow_apiserver",
+					Description:      "Allow K8s API Server",
+					Priority:         101,
+					Protocol:         infrav1.SecurityGroupProtocolTCP,
+					Source:           to.StringPtr("203.0.113.0/24"),
+					SourcePorts:      to.StringPtr("*"),
+					Destination:      to.StringPtr("*"),
+					DestinationPorts: to.StringPtr("6443"),
+				},
+			},
+			existingRules: &[]network.SecurityRule{},
+			expectUpdate:  true,
+			expectedSources: map[string][]string{
+				"allow_apiserver":        {"203.0.113.0/24"},
+				"allow_lb_health_probes": {"AzureLoadBalancer"},
+			},
+		},
+		{
+			name:          "changed health probe rule is restored",
+			networkSpec:   infrav1.NetworkSpec{SSHDisabled: true},
+			existingRules: &[]network.SecurityRule{apiServerRule, newDefaultRule("allow_lb_health_probes", "Allow Azure load balancer health probes", 103, "6443", []string{"10.0.0.0/16"})},
+			expectUpdate:  true,
+			expectedSources: map[string][]string{
+				"allow_apiserver":        {"*"},
+				"allow_lb_health_probes": {"AzureLoadBalancer"},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			sgMock := mock_securitygroups.NewMockClient(mockCtrl)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, cluster)
+
+			existing := network.SecurityGroup{}
+			if tc.existingRules != nil {
+				existing = network.SecurityGroup{
+					Name: to.StringPtr("my-cp-sg"),
+					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+						SecurityRules: tc.existingRules,
+					},
+				}
+			}
+			sgMock.EXPECT().Get(context.TODO(), "my-rg", "my-cp-sg").Return(existing, nil)
+			var updated network.SecurityGroup
+			if tc.expectUpdate {
+				sgMock.EXPECT().CreateOrUpdate(context.TODO(), "my-rg", "my-cp-sg", gomock.AssignableToTypeOf(network.SecurityGroup{})).
+					Do(func(_ context.Context, _ string, _ string, sg network.SecurityGroup) { updated = sg })
+			}
+
+			networkSpec := tc.networkSpec
+			networkSpec.Subnets = infrav1.Subnets{
+				{
+					Name: "cp-subnet",
+					Role: infrav1.SubnetControlPlane,
+					SecurityGroup: infrav1.SecurityGroup{
+						Name:         "my-cp-sg",
+						IngressRules: tc.ingressRules,
+					},
+				},
+				{Name: "node-subnet", Role: infrav1.SubnetNode},
+			}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  client,
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						Location:       "test-location",
+						ResourceGroup:  "my-rg",
+						SubscriptionID: subscriptionID,
+						NetworkSpec:    networkSpec,
+					},
+					Status: infrav1.AzureClusterStatus{
+						Network: tc.status,
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &Service{
+				Scope:  clusterScope,
+				Client: sgMock,
+			}
+
+			g.Expect(s.Reconcile(context.TODO(), &Spec{Name: "my-cp-sg", IsControlPlane: true})).To(Succeed())
+			if tc.expectUpdate {
+				sources := make(map[string][]string)
+				for _, rule := range *updated.SecurityRules {
+					sources[to.String(rule.Name)] = sourceAddressPrefixes(rule)
+				}
+				g.Expect(sources).To(Equal(tc.expectedSources))
+			}
+		})
+	}
+}
+
+func TestReconcileSecurityGroupTags(t *testing.T) {
+	testcases := []struct {
+		name         string
+		existingTags map[string]*string
+		expectedTags map[string]*string
+	}{
+		{
+			name: "tags of a security group owned by the cluster are updated",
+			existingTags: map[string]*string{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+				"Name":  to.StringPtr("my-sg"),
+				"team":  to.StringPtr("infra"),
+				"owner": to.StringPtr("ops"),
+			},
+			expectedTags: map[string]*string{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+				"Name":  to.StringPtr("my-sg"),
+				"env":   to.StringPtr("prod"),
+				"owner": to.StringPtr("ops"),
+			},
+		},
+		{
+			name:         "tags of a security group not owned by the cluster are not updated",
+			existingTags: map[string]*string{"team": to.StringPtr("infra")},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			sgMock := mock_securitygroups.NewMockClient(mockCtrl)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, cluster)
+
+			sgMock.EXPECT().Get(context.TODO(), "my-rg", "my-sg").Return(network.SecurityGroup{
+				Name: to.StringPtr("my-sg"),
+				Tags: tc.existingTags,
+				SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+					SecurityRules: &[]network.SecurityRule{},
+				},
+			}, nil)
+			if tc.expectedTags != nil {
+				sgMock.EXPECT().CreateOrUpdate(context.TODO(), "my-rg", "my-sg", gomock.AssignableToTypeOf(network.SecurityGroup{})).
+					Do(func(_ context.Context, _ string, _ string, sg network.SecurityGroup) {
+						g.Expect(sg.Tags).To(Equal(tc.expectedTags))
+					})
+			}
+
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  client,
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+						azure.TagsLastAppliedAnnotation: `{"team":"infra"}`,
+					}},
+					Spec: infrav1.AzureClusterSpec{
+						Location:       "test-location",
+						ResourceGroup:  "my-rg",
+						SubscriptionID: subscriptionID,
+						AdditionalTags: infrav1.Tags{"env": "prod"},
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{Name: "cp-subnet", Role: infrav1.SubnetControlPlane},
+								{
+									Name:          "node-subnet",
+									Role:          infrav1.SubnetNode,
+									SecurityGroup: infrav1.SecurityGroup{Name: "my-sg"},
+								},
+							},
+						},
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &Service{
+				Scope:  clusterScope,
+				Client: sgMock,
+			}
+
+			g.Expect(s.Reconcile(context.TODO(), &Spec{Name: "my-sg"})).To(Succeed())
+		})
+	}
+}
+
 func TestDeleteSecurityGroups(t *testing.T) {
 	testcases := []struct {
 		name   string
@@ -180,6 +643,12 @@ func TestDeleteSecurityGroups(t *testing.T) {
 						Location: "test-location",
 						ResourceGroup:  "my-rg",
 						SubscriptionID: subscriptionID,
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
+						},
 					},
 				},
 			})
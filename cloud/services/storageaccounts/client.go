@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageaccounts
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	List(context.Context) ([]storage.Account, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	accounts storage.AccountsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new storage accounts client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		accounts: newAccountsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newAccountsClient creates a new storage accounts client from subscription ID.
+func newAccountsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) storage.AccountsClient {
+	c := storage.NewAccountsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// List returns all the storage accounts of the subscription.
+func (ac *AzureClient) List(ctx context.Context) ([]storage.Account, error) {
+	iter, err := ac.accounts.ListComplete(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list storage accounts")
+	}
+
+	var accounts []storage.Account
+	for iter.NotDone() {
+		accounts = append(accounts, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return accounts, errors.Wrap(err, "could not iterate storage accounts")
+		}
+	}
+	return accounts, nil
+}
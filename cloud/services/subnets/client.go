@@ -21,6 +21,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
@@ -29,29 +30,49 @@ type Client interface {
 	Get(context.Context, string, string, string) (network.Subnet, error)
 	CreateOrUpdate(context.Context, string, string, string, network.Subnet) error
 	Delete(context.Context, string, string, string) error
+	ListAvailableEndpointServices(context.Context, string) ([]network.EndpointServiceResult, error)
+	ListAvailableDelegations(context.Context, string) ([]network.AvailableDelegation, error)
 }
 
 // AzureClient contains the Azure go-sdk Client
 type AzureClient struct {
-	subnets network.SubnetsClient
+	subnets          network.SubnetsClient
+	endpointServices network.AvailableEndpointServicesClient
+	delegations      network.AvailableDelegationsClient
 }
 
 var _ Client = &AzureClient{}
 
 // NewClient creates a new subnets client from subscription ID.
 func NewClient(auth azure.Authorizer) *AzureClient {
-	c := newSubnetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
+	return &AzureClient{
+		subnets:          newSubnetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		endpointServices: newAvailableEndpointServicesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		delegations:      newAvailableDelegationsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
 }
 
 // newSubnetsClient creates a new subnets client from subscription ID.
 func newSubnetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.SubnetsClient {
 	subnetsClient := network.NewSubnetsClientWithBaseURI(baseURI, subscriptionID)
-	subnetsClient.Authorizer = authorizer
-	subnetsClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&subnetsClient.Client, authorizer)
 	return subnetsClient
 }
 
+// newAvailableEndpointServicesClient creates a new available endpoint services client from subscription ID.
+func newAvailableEndpointServicesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.AvailableEndpointServicesClient {
+	c := network.NewAvailableEndpointServicesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// newAvailableDelegationsClient creates a new available delegations client from subscription ID.
+func newAvailableDelegationsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.AvailableDelegationsClient {
+	c := network.NewAvailableDelegationsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
 // Get gets the specified subnet by virtual network and resource group.
 func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName, snName string) (network.Subnet, error) {
 	return ac.subnets.Get(ctx, resourceGroupName, vnetName, snName, "")
@@ -84,3 +105,37 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vnetName,
 	_, err = future.Result(ac.subnets)
 	return err
 }
+
+// ListAvailableEndpointServices lists the services which can be the service endpoints of the subnets of a location.
+func (ac *AzureClient) ListAvailableEndpointServices(ctx context.Context, location string) ([]network.EndpointServiceResult, error) {
+	iter, err := ac.endpointServices.ListComplete(ctx, location)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list available endpoint services")
+	}
+
+	var services []network.EndpointServiceResult
+	for iter.NotDone() {
+		services = append(services, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return services, errors.Wrap(err, "could not iterate available endpoint services")
+		}
+	}
+	return services, nil
+}
+
+// ListAvailableDelegations lists the services the subnets of a location can be delegated to.
+func (ac *AzureClient) ListAvailableDelegations(ctx context.Context, location string) ([]network.AvailableDelegation, error) {
+	iter, err := ac.delegations.ListComplete(ctx, location)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list available delegations")
+	}
+
+	var delegations []network.AvailableDelegation
+	for iter.NotDone() {
+		delegations = append(delegations, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return delegations, errors.Wrap(err, "could not iterate available delegations")
+		}
+	}
+	return delegations, nil
+}
@@ -92,3 +92,33 @@ func (mr *MockClientMockRecorder) Delete(arg0, arg1, arg2, arg3 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1, arg2, arg3)
 }
+
+// ListAvailableDelegations mocks base method.
+func (m *MockClient) ListAvailableDelegations(arg0 context.Context, arg1 string) ([]network.AvailableDelegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailableDelegations", arg0, arg1)
+	ret0, _ := ret[0].([]network.AvailableDelegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailableDelegations indicates an expected call of ListAvailableDelegations.
+func (mr *MockClientMockRecorder) ListAvailableDelegations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailableDelegations", reflect.TypeOf((*MockClient)(nil).ListAvailableDelegations), arg0, arg1)
+}
+
+// ListAvailableEndpointServices mocks base method.
+func (m *MockClient) ListAvailableEndpointServices(arg0 context.Context, arg1 string) ([]network.EndpointServiceResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailableEndpointServices", arg0, arg1)
+	ret0, _ := ret[0].([]network.EndpointServiceResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailableEndpointServices indicates an expected call of ListAvailableEndpointServices.
+func (mr *MockClientMockRecorder) ListAvailableEndpointServices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailableEndpointServices", reflect.TypeOf((*MockClient)(nil).ListAvailableEndpointServices), arg0, arg1)
+}
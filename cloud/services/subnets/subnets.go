@@ -19,6 +19,8 @@ package subnets
 import (
 	"context"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -30,20 +32,25 @@ import (
 
 // Spec input specification for Get/CreateOrUpdate/Delete calls
 type Spec struct {
-	Name                string
-	CIDR                string
-	VnetName            string
-	RouteTableName      string
-	SecurityGroupName   string
-	Role                infrav1.SubnetRole
-	InternalLBIPAddress string
+	ID                      string
+	Name                    string
+	CIDR                    string
+	IPv6CIDR                string
+	VnetName                string
+	RouteTableName          string
+	RouteTableResourceGroup string
+	SecurityGroupName       string
+	Role                    infrav1.SubnetRole
+	InternalLBIPAddress     string
+	ServiceEndpoints        []string
+	Delegations             []string
 }
 
 // getExisting provides information about an existing subnet.
-func (s *Service) getExisting(ctx context.Context, rgName string, spec *Spec) (*infrav1.SubnetSpec, error) {
+func (s *Service) getExisting(ctx context.Context, rgName string, spec *Spec) (*infrav1.SubnetSpec, network.Subnet, error) {
 	subnet, err := s.Client.Get(ctx, rgName, spec.VnetName, spec.Name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to fetch subnet named %q in vnet %q", spec.VnetName, spec.Name)
+		return nil, network.Subnet{}, errors.Wrapf(err, "failed to fetch subnet named %q in vnet %q", spec.Name, spec.VnetName)
 	}
 
 	subnetSpec := &infrav1.SubnetSpec{
@@ -53,8 +60,28 @@ func (s *Service) getExisting(ctx context.Context, rgName string, spec *Spec) (*
 		ID:                  to.String(subnet.ID),
 		CidrBlock:           to.String(subnet.SubnetPropertiesFormat.AddressPrefix),
 	}
+	// dual-stack subnets report their address prefixes as a list
+	for _, prefix := range to.StringSlice(subnet.SubnetPropertiesFormat.AddressPrefixes) {
+		if ip, _, err := net.ParseCIDR(prefix); err == nil && ip.To4() == nil {
+			subnetSpec.IPv6CidrBlock = prefix
+		} else if subnetSpec.CidrBlock == "" {
+			subnetSpec.CidrBlock = prefix
+		}
+	}
+	if subnet.ServiceEndpoints != nil {
+		for _, endpoint := range *subnet.ServiceEndpoints {
+			subnetSpec.ServiceEndpoints = append(subnetSpec.ServiceEndpoints, to.String(endpoint.Service))
+		}
+	}
+	if subnet.Delegations != nil {
+		for _, delegation := range *subnet.Delegations {
+			if delegation.ServiceDelegationPropertiesFormat != nil {
+				subnetSpec.Delegations = append(subnetSpec.Delegations, to.String(delegation.ServiceName))
+			}
+		}
+	}
 
-	return subnetSpec, nil
+	return subnetSpec, subnet, nil
 }
 
 // Reconcile gets/creates/updates a subnet.
@@ -63,21 +90,34 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	if !ok {
 		return errors.New("Invalid Subnet Specification")
 	}
-	existingSubnet, err := s.getExisting(ctx, s.Scope.Vnet().ResourceGroup, subnetSpec)
+	existingSubnet, subnet, err := s.getExisting(ctx, s.Scope.Vnet().ResourceGroup, subnetSpec)
 	if err == nil {
+		if subnetSpec.ID != "" && !strings.EqualFold(existingSubnet.ID, subnetSpec.ID) {
+			return errors.Errorf("subnet %s found in vnet %s has ID %s, which does not match the provided ID %s",
+				subnetSpec.Name, subnetSpec.VnetName, existingSubnet.ID, subnetSpec.ID)
+		}
+		// the service endpoints and delegations of the subnets of a pre-existing vnet aren't managed
+		if s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
+			if err := s.update(ctx, subnetSpec, existingSubnet, subnet); err != nil {
+				return err
+			}
+		}
 		// subnet already exists, update the spec and skip creation
 		var subnet *infrav1.SubnetSpec
-		if subnetSpec.Role == infrav1.SubnetControlPlane {
-			subnet = s.Scope.ControlPlaneSubnet()
-		} else if subnetSpec.Role == infrav1.SubnetNode {
-			subnet = s.Scope.NodeSubnet()
-		} else {
+		for _, sn := range s.Scope.Subnets() {
+			if sn.Name == subnetSpec.Name && sn.Role == subnetSpec.Role {
+				subnet = sn
+				break
+			}
+		}
+		if subnet == nil {
 			return nil
 		}
 
 		subnet.Role = subnetSpec.Role
 		subnet.Name = existingSubnet.Name
 		subnet.CidrBlock = existingSubnet.CidrBlock
+		subnet.IPv6CidrBlock = existingSubnet.IPv6CidrBlock
 		subnet.ID = existingSubnet.ID
 
 		return nil
@@ -89,15 +129,27 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	}
 	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
 		// if the vnet is unmanaged, we expect all subnets to be created as well
+		if subnetSpec.ID != "" {
+			return errors.Errorf("subnet %s with ID %s was provided but could not be found in vnet %s", subnetSpec.Name, subnetSpec.ID, subnetSpec.VnetName)
+		}
 		return fmt.Errorf("vnet was provided but subnet %s is missing", subnetSpec.Name)
 	}
 
 	subnetProperties := network.SubnetPropertiesFormat{
 		AddressPrefix: to.StringPtr(subnetSpec.CIDR),
 	}
+	if subnetSpec.IPv6CIDR != "" {
+		// dual-stack subnets use the list of address prefixes instead
+		subnetProperties.AddressPrefix = nil
+		subnetProperties.AddressPrefixes = &[]string{subnetSpec.CIDR, subnetSpec.IPv6CIDR}
+	}
 	if subnetSpec.RouteTableName != "" {
+		routeTableResourceGroup := subnetSpec.RouteTableResourceGroup
+		if routeTableResourceGroup == "" {
+			routeTableResourceGroup = s.Scope.NetworkResourceGroup()
+		}
 		s.Scope.V(2).Info("getting route table", "route table", subnetSpec.RouteTableName)
-		rt, err := s.RouteTablesClient.Get(ctx, s.Scope.ResourceGroup(), subnetSpec.RouteTableName)
+		rt, err := s.RouteTablesClient.Get(ctx, routeTableResourceGroup, subnetSpec.RouteTableName)
 		if err != nil {
 			return err
 		}
@@ -105,13 +157,30 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		subnetProperties.RouteTable = &rt
 	}
 
-	s.Scope.V(2).Info("getting security group", "security group", subnetSpec.SecurityGroupName)
-	nsg, err := s.SecurityGroupsClient.Get(ctx, s.Scope.ResourceGroup(), subnetSpec.SecurityGroupName)
-	if err != nil {
-		return err
+	if len(subnetSpec.ServiceEndpoints) > 0 {
+		if err := s.validateServiceEndpoints(ctx, subnetSpec); err != nil {
+			return err
+		}
+		subnetProperties.ServiceEndpoints = serviceEndpoints(subnetSpec.ServiceEndpoints, nil)
+	}
+
+	if len(subnetSpec.Delegations) > 0 {
+		if err := s.validateDelegations(ctx, subnetSpec); err != nil {
+			return err
+		}
+		subnetProperties.Delegations = delegations(subnetSpec.Delegations, nil)
+	}
+
+	// the subnet of a bastion host has no security group
+	if subnetSpec.SecurityGroupName != "" {
+		s.Scope.V(2).Info("getting security group", "security group", subnetSpec.SecurityGroupName)
+		nsg, err := s.SecurityGroupsClient.Get(ctx, s.Scope.NetworkResourceGroup(), subnetSpec.SecurityGroupName)
+		if err != nil {
+			return err
+		}
+		s.Scope.V(2).Info("successfully got security group", "security group", subnetSpec.SecurityGroupName)
+		subnetProperties.NetworkSecurityGroup = &nsg
 	}
-	s.Scope.V(2).Info("successfully got security group", "security group", subnetSpec.SecurityGroupName)
-	subnetProperties.NetworkSecurityGroup = &nsg
 
 	s.Scope.V(2).Info("creating subnet in vnet", "subnet", subnetSpec.Name, "vnet", subnetSpec.VnetName)
 	err = s.Client.CreateOrUpdate(
@@ -132,6 +201,142 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	return nil
 }
 
+// update updates the service endpoints and the delegations of an existing subnet, adding the missing services and
+// removing the ones no longer in its spec. The subnet is updated as a whole, keeping its security group and route table.
+func (s *Service) update(ctx context.Context, subnetSpec *Spec, existingSubnet *infrav1.SubnetSpec, subnet network.Subnet) error {
+	updateServiceEndpoints := !sameServices(existingSubnet.ServiceEndpoints, subnetSpec.ServiceEndpoints)
+	updateDelegations := !sameServices(existingSubnet.Delegations, subnetSpec.Delegations)
+	if !updateServiceEndpoints && !updateDelegations {
+		return nil
+	}
+
+	if updateServiceEndpoints {
+		if err := s.validateServiceEndpoints(ctx, subnetSpec); err != nil {
+			return err
+		}
+		s.Scope.V(2).Info("updating service endpoints of subnet", "subnet", subnetSpec.Name, "service endpoints", subnetSpec.ServiceEndpoints)
+		subnet.ServiceEndpoints = serviceEndpoints(subnetSpec.ServiceEndpoints, subnet.ServiceEndpoints)
+	}
+	if updateDelegations {
+		if err := s.validateDelegations(ctx, subnetSpec); err != nil {
+			return err
+		}
+		s.Scope.V(2).Info("updating delegations of subnet", "subnet", subnetSpec.Name, "delegations", subnetSpec.Delegations)
+		subnet.Delegations = delegations(subnetSpec.Delegations, subnet.Delegations)
+	}
+	if err := s.Client.CreateOrUpdate(ctx, s.Scope.Vnet().ResourceGroup, subnetSpec.VnetName, subnetSpec.Name, subnet); err != nil {
+		return errors.Wrapf(err, "failed to update subnet %s in resource group %s", subnetSpec.Name, s.Scope.Vnet().ResourceGroup)
+	}
+	return nil
+}
+
+// validateServiceEndpoints checks that the service endpoints of the subnet are available in the location of the cluster.
+func (s *Service) validateServiceEndpoints(ctx context.Context, subnetSpec *Spec) error {
+	if len(subnetSpec.ServiceEndpoints) == 0 {
+		return nil
+	}
+
+	available, err := s.Client.ListAvailableEndpointServices(ctx, s.Scope.Location())
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the endpoint services available in location %s", s.Scope.Location())
+	}
+	names := make([]string, 0, len(available))
+	for _, service := range available {
+		names = append(names, to.String(service.Name))
+	}
+	for _, service := range subnetSpec.ServiceEndpoints {
+		if !containsService(names, service) {
+			return errors.Errorf("service endpoint %s of subnet %s isn't available in location %s", service, subnetSpec.Name, s.Scope.Location())
+		}
+	}
+	return nil
+}
+
+// validateDelegations checks that the subnet can be delegated to its services in the location of the cluster.
+func (s *Service) validateDelegations(ctx context.Context, subnetSpec *Spec) error {
+	if len(subnetSpec.Delegations) == 0 {
+		return nil
+	}
+
+	available, err := s.Client.ListAvailableDelegations(ctx, s.Scope.Location())
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the delegations available in location %s", s.Scope.Location())
+	}
+	names := make([]string, 0, len(available))
+	for _, delegation := range available {
+		names = append(names, to.String(delegation.ServiceName))
+	}
+	for _, service := range subnetSpec.Delegations {
+		if !containsService(names, service) {
+			return errors.Errorf("delegation of subnet %s to %s isn't available in location %s", subnetSpec.Name, service, s.Scope.Location())
+		}
+	}
+	return nil
+}
+
+// delegations returns the delegations of the subnet to the services, keeping the names and actions of the existing
+// delegations. A new delegation is named after its service.
+func delegations(services []string, existing *[]network.Delegation) *[]network.Delegation {
+	result := make([]network.Delegation, 0, len(services))
+	for _, service := range services {
+		delegation := network.Delegation{
+			Name: to.StringPtr(strings.ReplaceAll(service, "/", ".")),
+			ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+				ServiceName: to.StringPtr(service),
+			},
+		}
+		if existing != nil {
+			for _, d := range *existing {
+				if d.ServiceDelegationPropertiesFormat != nil && strings.EqualFold(to.String(d.ServiceName), service) {
+					delegation.Name = d.Name
+					delegation.Actions = d.Actions
+				}
+			}
+		}
+		result = append(result, delegation)
+	}
+	return &result
+}
+
+// serviceEndpoints returns the service endpoints of the services, keeping the locations of the existing endpoints.
+func serviceEndpoints(services []string, existing *[]network.ServiceEndpointPropertiesFormat) *[]network.ServiceEndpointPropertiesFormat {
+	endpoints := make([]network.ServiceEndpointPropertiesFormat, 0, len(services))
+	for _, service := range services {
+		endpoint := network.ServiceEndpointPropertiesFormat{Service: to.StringPtr(service)}
+		if existing != nil {
+			for _, e := range *existing {
+				if strings.EqualFold(to.String(e.Service), service) {
+					endpoint.Locations = e.Locations
+				}
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return &endpoints
+}
+
+func containsService(services []string, service string) bool {
+	for _, s := range services {
+		if strings.EqualFold(s, service) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameServices returns whether two lists of services hold the same services, in any order.
+func sameServices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, service := range a {
+		if !containsService(b, service) {
+			return false
+		}
+	}
+	return true
+}
+
 // Delete deletes the subnet with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
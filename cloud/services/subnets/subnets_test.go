@@ -83,6 +83,23 @@ func TestReconcileSubnets(t *testing.T) {
 				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", gomock.AssignableToTypeOf(network.Subnet{}))
 			},
 		},
+		{
+			name: "subnet without security group does not exist",
+			subnetSpec: Spec{
+				Name:     "AzureBastionSubnet",
+				CIDR:     "10.255.255.192/26",
+				VnetName: "my-vnet",
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "AzureBastionSubnet").
+					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "AzureBastionSubnet", gomock.AssignableToTypeOf(network.Subnet{}))
+			},
+		},
 		{
 			name: "vnet was provided but subnet is missing",
 			subnetSpec: Spec{
@@ -138,6 +155,341 @@ func TestReconcileSubnets(t *testing.T) {
 					}, nil)
 			},
 		},
+		{
+			name: "pre-existing subnet referenced by ID is missing",
+			subnetSpec: Spec{
+				ID:       "/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet",
+				Name:     "my-subnet",
+				VnetName: "custom-vnet",
+				Role:     infrav1.SubnetControlPlane,
+			},
+			vnetSpec:      &infrav1.VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet", ID: "id1"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "subnet my-subnet with ID /subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet was provided but could not be found in vnet custom-vnet",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "custom-vnet-rg", "custom-vnet", "my-subnet").
+					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+		},
+		{
+			name: "pre-existing subnet referenced by ID exists",
+			subnetSpec: Spec{
+				ID:       "/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet",
+				Name:     "my-subnet",
+				VnetName: "custom-vnet",
+				Role:     infrav1.SubnetControlPlane,
+			},
+			vnetSpec: &infrav1.VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet", ID: "id1"},
+			subnets: []*infrav1.SubnetSpec{{
+				ID:   "/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet",
+				Name: "my-subnet",
+				Role: infrav1.SubnetControlPlane,
+			}},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "custom-vnet-rg", "custom-vnet", "my-subnet").
+					Return(network.Subnet{
+						ID:   to.StringPtr("/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet"),
+						Name: to.StringPtr("my-subnet"),
+						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+							AddressPrefix: to.StringPtr("10.0.1.0/24"),
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "pre-existing subnet does not match the provided ID",
+			subnetSpec: Spec{
+				ID:       "/subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet",
+				Name:     "my-subnet",
+				VnetName: "custom-vnet",
+				Role:     infrav1.SubnetControlPlane,
+			},
+			vnetSpec:      &infrav1.VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet", ID: "id1"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "subnet my-subnet found in vnet custom-vnet has ID /subscriptions/456/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet, which does not match the provided ID /subscriptions/123/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "custom-vnet-rg", "custom-vnet", "my-subnet").
+					Return(network.Subnet{
+						ID:   to.StringPtr("/subscriptions/456/resourceGroups/custom-vnet-rg/providers/Microsoft.Network/virtualNetworks/custom-vnet/subnets/my-subnet"),
+						Name: to.StringPtr("my-subnet"),
+						SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+							AddressPrefix: to.StringPtr("10.0.1.0/24"),
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "subnet with service endpoints does not exist",
+			subnetSpec: Spec{
+				Name:             "my-subnet",
+				CIDR:             "10.0.0.0/16",
+				VnetName:         "my-vnet",
+				Role:             infrav1.SubnetNode,
+				ServiceEndpoints: []string{"Microsoft.Storage"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").
+					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.ListAvailableEndpointServices(context.TODO(), "test-location").
+					Return(availableEndpointServices("Microsoft.Storage", "Microsoft.Sql"), nil)
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", network.Subnet{
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix: to.StringPtr("10.0.0.0/16"),
+						ServiceEndpoints: &[]network.ServiceEndpointPropertiesFormat{
+							{Service: to.StringPtr("Microsoft.Storage")},
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "service endpoints of an existing subnet are added and removed",
+			subnetSpec: Spec{
+				Name:             "my-subnet",
+				CIDR:             "10.0.0.0/16",
+				VnetName:         "my-vnet",
+				Role:             infrav1.SubnetNode,
+				ServiceEndpoints: []string{"Microsoft.Sql", "Microsoft.Storage"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").
+					Return(subnetWithServiceEndpoints(
+						network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.Sql"), Locations: &[]string{"test-location"}},
+						network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.KeyVault"), Locations: &[]string{"*"}},
+					), nil)
+				m.ListAvailableEndpointServices(context.TODO(), "test-location").
+					Return(availableEndpointServices("Microsoft.KeyVault", "Microsoft.Storage", "Microsoft.Sql"), nil)
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", subnetWithServiceEndpoints(
+					network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.Sql"), Locations: &[]string{"test-location"}},
+					network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.Storage")},
+				))
+			},
+		},
+		{
+			name: "security group and route table of an existing subnet are kept when updating it",
+			subnetSpec: Spec{
+				Name:             "my-subnet",
+				CIDR:             "10.0.0.0/16",
+				VnetName:         "my-vnet",
+				Role:             infrav1.SubnetNode,
+				ServiceEndpoints: []string{"Microsoft.Storage"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				subnet := subnetWithServiceEndpoints()
+				subnet.NetworkSecurityGroup = &network.SecurityGroup{ID: to.StringPtr("my-nsg-id")}
+				subnet.RouteTable = &network.RouteTable{ID: to.StringPtr("my-routetable-id")}
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").Return(subnet, nil)
+				m.ListAvailableEndpointServices(context.TODO(), "test-location").
+					Return(availableEndpointServices("Microsoft.Storage"), nil)
+
+				updated := subnetWithServiceEndpoints(network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.Storage")})
+				updated.NetworkSecurityGroup = &network.SecurityGroup{ID: to.StringPtr("my-nsg-id")}
+				updated.RouteTable = &network.RouteTable{ID: to.StringPtr("my-routetable-id")}
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", updated)
+				m1.Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				m2.Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "service endpoints of an existing subnet are up to date",
+			subnetSpec: Spec{
+				Name:             "my-subnet",
+				CIDR:             "10.0.0.0/16",
+				VnetName:         "my-vnet",
+				Role:             infrav1.SubnetNode,
+				ServiceEndpoints: []string{"Microsoft.Storage", "Microsoft.Sql"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").
+					Return(subnetWithServiceEndpoints(
+						network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.Sql")},
+						network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.Storage")},
+					), nil)
+			},
+		},
+		{
+			name: "service endpoint is not available in the location",
+			subnetSpec: Spec{
+				Name:             "my-subnet",
+				CIDR:             "10.0.0.0/16",
+				VnetName:         "my-vnet",
+				Role:             infrav1.SubnetNode,
+				ServiceEndpoints: []string{"Microsoft.Storage"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "service endpoint Microsoft.Storage of subnet my-subnet isn't available in location test-location",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").
+					Return(subnetWithServiceEndpoints(), nil)
+				m.ListAvailableEndpointServices(context.TODO(), "test-location").
+					Return(availableEndpointServices("Microsoft.Sql"), nil)
+			},
+		},
+		{
+			name: "subnet with delegations does not exist",
+			subnetSpec: Spec{
+				Name:        "my-subnet",
+				CIDR:        "10.0.0.0/16",
+				VnetName:    "my-vnet",
+				Role:        infrav1.SubnetNode,
+				Delegations: []string{"Microsoft.Netapp/volumes"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").
+					Return(network.Subnet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.ListAvailableDelegations(context.TODO(), "test-location").
+					Return(availableDelegations("Microsoft.Netapp/volumes", "Microsoft.ContainerInstance/containerGroups"), nil)
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", network.Subnet{
+					Name: to.StringPtr("my-subnet"),
+					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+						AddressPrefix: to.StringPtr("10.0.0.0/16"),
+						Delegations: &[]network.Delegation{
+							{
+								Name:                              to.StringPtr("Microsoft.Netapp.volumes"),
+								ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{ServiceName: to.StringPtr("Microsoft.Netapp/volumes")},
+							},
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "delegations of an existing subnet are added and removed with its security group and route table kept",
+			subnetSpec: Spec{
+				Name:        "my-subnet",
+				CIDR:        "10.0.0.0/16",
+				VnetName:    "my-vnet",
+				Role:        infrav1.SubnetNode,
+				Delegations: []string{"Microsoft.Netapp/volumes", "Microsoft.ContainerInstance/containerGroups"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				subnet := subnetWithServiceEndpoints()
+				subnet.NetworkSecurityGroup = &network.SecurityGroup{ID: to.StringPtr("my-nsg-id")}
+				subnet.RouteTable = &network.RouteTable{ID: to.StringPtr("my-routetable-id")}
+				subnet.Delegations = &[]network.Delegation{
+					{
+						Name: to.StringPtr("netapp"),
+						ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+							ServiceName: to.StringPtr("Microsoft.Netapp/volumes"),
+							Actions:     &[]string{"Microsoft.Network/networkinterfaces/*"},
+						},
+					},
+					{
+						Name:                              to.StringPtr("web"),
+						ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{ServiceName: to.StringPtr("Microsoft.Web/serverFarms")},
+					},
+				}
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").Return(subnet, nil)
+				m.ListAvailableDelegations(context.TODO(), "test-location").
+					Return(availableDelegations("Microsoft.Netapp/volumes", "Microsoft.ContainerInstance/containerGroups", "Microsoft.Web/serverFarms"), nil)
+
+				updated := subnetWithServiceEndpoints()
+				updated.NetworkSecurityGroup = &network.SecurityGroup{ID: to.StringPtr("my-nsg-id")}
+				updated.RouteTable = &network.RouteTable{ID: to.StringPtr("my-routetable-id")}
+				updated.Delegations = &[]network.Delegation{
+					{
+						Name: to.StringPtr("netapp"),
+						ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+							ServiceName: to.StringPtr("Microsoft.Netapp/volumes"),
+							Actions:     &[]string{"Microsoft.Network/networkinterfaces/*"},
+						},
+					},
+					{
+						Name:                              to.StringPtr("Microsoft.ContainerInstance.containerGroups"),
+						ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{ServiceName: to.StringPtr("Microsoft.ContainerInstance/containerGroups")},
+					},
+				}
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", updated)
+				m1.Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				m2.Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "service endpoints and delegations of an existing subnet are updated together",
+			subnetSpec: Spec{
+				Name:             "my-subnet",
+				CIDR:             "10.0.0.0/16",
+				VnetName:         "my-vnet",
+				Role:             infrav1.SubnetNode,
+				ServiceEndpoints: []string{"Microsoft.Storage"},
+				Delegations:      []string{"Microsoft.Netapp/volumes"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").Return(subnetWithServiceEndpoints(), nil)
+				m.ListAvailableEndpointServices(context.TODO(), "test-location").
+					Return(availableEndpointServices("Microsoft.Storage"), nil)
+				m.ListAvailableDelegations(context.TODO(), "test-location").
+					Return(availableDelegations("Microsoft.Netapp/volumes"), nil)
+
+				updated := subnetWithServiceEndpoints(network.ServiceEndpointPropertiesFormat{Service: to.StringPtr("Microsoft.Storage")})
+				updated.Delegations = &[]network.Delegation{
+					{
+						Name:                              to.StringPtr("Microsoft.Netapp.volumes"),
+						ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{ServiceName: to.StringPtr("Microsoft.Netapp/volumes")},
+					},
+				}
+				m.CreateOrUpdate(context.TODO(), "", "my-vnet", "my-subnet", updated)
+			},
+		},
+		{
+			name: "delegation is not available in the location",
+			subnetSpec: Spec{
+				Name:        "my-subnet",
+				CIDR:        "10.0.0.0/16",
+				VnetName:    "my-vnet",
+				Role:        infrav1.SubnetNode,
+				Delegations: []string{"Microsoft.Netapp/volumes"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{Name: "my-vnet"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "delegation of subnet my-subnet to Microsoft.Netapp/volumes isn't available in location test-location",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "", "my-vnet", "my-subnet").
+					Return(subnetWithServiceEndpoints(), nil)
+				m.ListAvailableDelegations(context.TODO(), "test-location").
+					Return(availableDelegations("Microsoft.ContainerInstance/containerGroups"), nil)
+			},
+		},
+		{
+			name: "service endpoints of a subnet of a pre-existing vnet are not updated",
+			subnetSpec: Spec{
+				Name:             "my-subnet",
+				VnetName:         "custom-vnet",
+				Role:             infrav1.SubnetNode,
+				ServiceEndpoints: []string{"Microsoft.Storage"},
+			},
+			vnetSpec:      &infrav1.VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet", ID: "id1"},
+			subnets:       []*infrav1.SubnetSpec{},
+			expectedError: "",
+			expect: func(m *mock_subnets.MockClientMockRecorder, m1 *mock_routetables.MockClientMockRecorder, m2 *mock_securitygroups.MockClientMockRecorder) {
+				m.Get(context.TODO(), "custom-vnet-rg", "custom-vnet", "my-subnet").
+					Return(subnetWithServiceEndpoints(), nil)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -159,6 +511,12 @@ func TestReconcileSubnets(t *testing.T) {
 
 			tc.expect(subnetMock.EXPECT(), rtMock.EXPECT(), sgMock.EXPECT())
 
+			// the scope requires a control plane and a node subnet, the ones of the test case come first
+			subnets := append(tc.subnets,
+				&infrav1.SubnetSpec{Name: "other-cp-subnet", Role: infrav1.SubnetControlPlane},
+				&infrav1.SubnetSpec{Name: "other-node-subnet", Role: infrav1.SubnetNode},
+			)
+
 			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
 				AzureClients: scope.AzureClients{
 					Authorizer: autorest.NullAuthorizer{},
@@ -167,12 +525,12 @@ func TestReconcileSubnets(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						ResourceGroup:  "my-rg",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet:    *tc.vnetSpec,
-							Subnets: tc.subnets,
+							Subnets: subnets,
 						},
 					},
 				},
@@ -197,6 +555,36 @@ func TestReconcileSubnets(t *testing.T) {
 	}
 }
 
+func subnetWithServiceEndpoints(endpoints ...network.ServiceEndpointPropertiesFormat) network.Subnet {
+	subnet := network.Subnet{
+		ID:   to.StringPtr("subnet-id"),
+		Name: to.StringPtr("my-subnet"),
+		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			AddressPrefix: to.StringPtr("10.0.0.0/16"),
+		},
+	}
+	if len(endpoints) > 0 {
+		subnet.ServiceEndpoints = &endpoints
+	}
+	return subnet
+}
+
+func availableEndpointServices(names ...string) []network.EndpointServiceResult {
+	services := make([]network.EndpointServiceResult, 0, len(names))
+	for _, name := range names {
+		services = append(services, network.EndpointServiceResult{Name: to.StringPtr(name)})
+	}
+	return services
+}
+
+func availableDelegations(serviceNames ...string) []network.AvailableDelegation {
+	delegations := make([]network.AvailableDelegation, 0, len(serviceNames))
+	for _, serviceName := range serviceNames {
+		delegations = append(delegations, network.AvailableDelegation{ServiceName: to.StringPtr(serviceName)})
+	}
+	return delegations
+}
+
 func TestDeleteSubnets(t *testing.T) {
 	testcases := []struct {
 		name       string
@@ -277,11 +665,15 @@ func TestDeleteSubnets(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						ResourceGroup:  "my-rg",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet: *tc.vnetSpec,
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
 						},
 					},
 				},
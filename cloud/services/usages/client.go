@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usages
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	List(context.Context, string) ([]compute.Usage, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	usages compute.UsageClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new compute usages client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		usages: newUsageClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newUsageClient creates a new compute usages client from subscription ID.
+func newUsageClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.UsageClient {
+	c := compute.NewUsageClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// List returns the compute resource usages and limits of the subscription in a location.
+func (ac *AzureClient) List(ctx context.Context, location string) ([]compute.Usage, error) {
+	iter, err := ac.usages.ListComplete(ctx, location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list compute usages in location %s", location)
+	}
+
+	var usages []compute.Usage
+	for iter.NotDone() {
+		usages = append(usages, iter.Value())
+		if err := iter.NextWithContext(ctx); err != nil {
+			return usages, errors.Wrapf(err, "could not iterate compute usages in location %s", location)
+		}
+	}
+
+	return usages, nil
+}
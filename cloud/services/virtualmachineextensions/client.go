@@ -47,8 +47,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newVirtualMachineExtensionsClient creates a new VM extension client from subscription ID.
 func newVirtualMachineExtensionsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineExtensionsClient {
 	vmExtClient := compute.NewVirtualMachineExtensionsClientWithBaseURI(baseURI, subscriptionID)
-	vmExtClient.Authorizer = authorizer
-	vmExtClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&vmExtClient.Client, authorizer)
 	return vmExtClient
 }
 
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachineextensions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+const (
+	// ProvisioningStateSucceeded is the provisioning state of an extension installed on a VM.
+	ProvisioningStateSucceeded = "Succeeded"
+	// ProvisioningStateFailed is the provisioning state of an extension which failed to install on a VM.
+	ProvisioningStateFailed = "Failed"
+)
+
+// Spec input specification for the extensions of a VM.
+type Spec struct {
+	VMName     string
+	Extensions []infrav1.VMExtension
+	// Removed are the names of the extensions to uninstall from the VM.
+	Removed []string
+}
+
+// ReconcileVMExtensions installs the extensions of the spec missing from the VM, updates the ones which differ from the
+// spec or failed to provision, and uninstalls the removed ones. An extension which fails to provision doesn't prevent
+// the others from being reconciled. It returns the provisioning state of each extension of the spec.
+func (s *Service) ReconcileVMExtensions(ctx context.Context, spec *Spec) ([]infrav1.VMExtensionStatus, error) {
+	var errs []error
+	statuses := make([]infrav1.VMExtensionStatus, 0, len(spec.Extensions))
+	for _, extension := range spec.Extensions {
+		state, err := s.reconcileVMExtension(ctx, spec.VMName, extension)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		statuses = append(statuses, infrav1.VMExtensionStatus{Name: extension.Name, ProvisioningState: state})
+	}
+
+	for _, name := range spec.Removed {
+		s.Scope.V(2).Info("deleting VM extension", "vm", spec.VMName, "extension", name)
+		err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), spec.VMName, name)
+		if err != nil && !azure.ResourceNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete extension %s of VM %s", name, spec.VMName))
+			continue
+		}
+		s.Scope.V(2).Info("successfully deleted VM extension", "vm", spec.VMName, "extension", name)
+	}
+
+	return statuses, kerrors.NewAggregate(errs)
+}
+
+// reconcileVMExtension creates or updates an extension of a VM unless it is already provisioned as specified, and
+// returns its provisioning state.
+func (s *Service) reconcileVMExtension(ctx context.Context, vmName string, extension infrav1.VMExtension) (string, error) {
+	existing, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), vmName, extension.Name)
+	if err != nil && !azure.ResourceNotFound(err) {
+		return "", errors.Wrapf(err, "failed to get extension %s of VM %s", extension.Name, vmName)
+	}
+	if err == nil && existing.VirtualMachineExtensionProperties != nil {
+		state := to.String(existing.ProvisioningState)
+		if state != ProvisioningStateFailed && matchesSpec(existing.VirtualMachineExtensionProperties, extension) {
+			return state, nil
+		}
+		s.Scope.V(2).Info("updating VM extension", "vm", vmName, "extension", extension.Name, "provisioningState", state)
+	} else {
+		s.Scope.V(2).Info("creating VM extension", "vm", vmName, "extension", extension.Name)
+	}
+
+	properties := &compute.VirtualMachineExtensionProperties{
+		Publisher:               to.StringPtr(extension.Publisher),
+		Type:                    to.StringPtr(extension.Type),
+		TypeHandlerVersion:      to.StringPtr(extension.Version),
+		AutoUpgradeMinorVersion: to.BoolPtr(true),
+	}
+	if len(extension.Settings) > 0 {
+		properties.Settings = extension.Settings
+	}
+	err = s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), vmName, extension.Name, compute.VirtualMachineExtension{
+		Location:                          to.StringPtr(s.Scope.Location()),
+		VirtualMachineExtensionProperties: properties,
+	})
+	if err != nil {
+		return ProvisioningStateFailed, errors.Wrapf(err, "failed to provision extension %s of VM %s", extension.Name, vmName)
+	}
+
+	s.Scope.V(2).Info("successfully provisioned VM extension", "vm", vmName, "extension", extension.Name)
+	return ProvisioningStateSucceeded, nil
+}
+
+// matchesSpec returns true if the publisher, type, version and settings of an extension of a VM are the ones of the spec.
+func matchesSpec(existing *compute.VirtualMachineExtensionProperties, extension infrav1.VMExtension) bool {
+	return to.String(existing.Publisher) == extension.Publisher &&
+		to.String(existing.Type) == extension.Type &&
+		to.String(existing.TypeHandlerVersion) == extension.Version &&
+		reflect.DeepEqual(settingsOf(existing.Settings), settingsOf(extension.Settings))
+}
+
+// settingsOf returns the settings of an extension as a map of strings, as Azure returns them decoded from JSON.
+// Empty settings are returned as nil.
+func settingsOf(settings interface{}) map[string]string {
+	result := map[string]string{}
+	switch s := settings.(type) {
+	case map[string]string:
+		for k, v := range s {
+			result[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range s {
+			result[k] = fmt.Sprint(v)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
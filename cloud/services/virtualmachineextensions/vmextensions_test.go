@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachineextensions
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachineextensions/mock_virtualmachineextensions"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	clusterv1.AddToScheme(scheme.Scheme)
+}
+
+var customScript = infrav1.VMExtension{
+	Name:      "custom-script",
+	Publisher: "Microsoft.Azure.Extensions",
+	Type:      "CustomScript",
+	Version:   "2.1",
+	Settings:  map[string]string{"commandToExecute": "echo hello"},
+}
+
+func TestReconcileVMExtensions(t *testing.T) {
+	testcases := []struct {
+		name             string
+		spec             Spec
+		expectedStatuses []infrav1.VMExtensionStatus
+		expectedError    string
+		expect           func(m *mock_virtualmachineextensions.MockClientMockRecorder)
+	}{
+		{
+			name:             "installs a missing extension",
+			spec:             Spec{VMName: "my-vm", Extensions: []infrav1.VMExtension{customScript}},
+			expectedStatuses: []infrav1.VMExtensionStatus{{Name: "custom-script", ProvisioningState: "Succeeded"}},
+			expect: func(m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vm", "custom-script").
+					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "custom-script", compute.VirtualMachineExtension{
+					Location: to.StringPtr("test-location"),
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:               to.StringPtr("Microsoft.Azure.Extensions"),
+						Type:                    to.StringPtr("CustomScript"),
+						TypeHandlerVersion:      to.StringPtr("2.1"),
+						AutoUpgradeMinorVersion: to.BoolPtr(true),
+						Settings:                map[string]string{"commandToExecute": "echo hello"},
+					},
+				})
+			},
+		},
+		{
+			name:             "leaves an extension provisioned as specified",
+			spec:             Spec{VMName: "my-vm", Extensions: []infrav1.VMExtension{customScript}},
+			expectedStatuses: []infrav1.VMExtensionStatus{{Name: "custom-script", ProvisioningState: "Succeeded"}},
+			expect: func(m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vm", "custom-script").Return(compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:          to.StringPtr("Microsoft.Azure.Extensions"),
+						Type:               to.StringPtr("CustomScript"),
+						TypeHandlerVersion: to.StringPtr("2.1"),
+						Settings:           map[string]interface{}{"commandToExecute": "echo hello"},
+						ProvisioningState:  to.StringPtr("Succeeded"),
+					},
+				}, nil)
+			},
+		},
+		{
+			name:             "updates an extension with different settings",
+			spec:             Spec{VMName: "my-vm", Extensions: []infrav1.VMExtension{customScript}},
+			expectedStatuses: []infrav1.VMExtensionStatus{{Name: "custom-script", ProvisioningState: "Succeeded"}},
+			expect: func(m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vm", "custom-script").Return(compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:          to.StringPtr("Microsoft.Azure.Extensions"),
+						Type:               to.StringPtr("CustomScript"),
+						TypeHandlerVersion: to.StringPtr("2.1"),
+						Settings:           map[string]interface{}{"commandToExecute": "echo bye"},
+						ProvisioningState:  to.StringPtr("Succeeded"),
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "custom-script", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+			},
+		},
+		{
+			name:             "retries a failed extension",
+			spec:             Spec{VMName: "my-vm", Extensions: []infrav1.VMExtension{customScript}},
+			expectedStatuses: []infrav1.VMExtensionStatus{{Name: "custom-script", ProvisioningState: "Succeeded"}},
+			expect: func(m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vm", "custom-script").Return(compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:          to.StringPtr("Microsoft.Azure.Extensions"),
+						Type:               to.StringPtr("CustomScript"),
+						TypeHandlerVersion: to.StringPtr("2.1"),
+						Settings:           map[string]interface{}{"commandToExecute": "echo hello"},
+						ProvisioningState:  to.StringPtr("Failed"),
+					},
+				}, nil)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "custom-script", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+			},
+		},
+		{
+			name: "reconciles the other extensions when one fails to provision",
+			spec: Spec{VMName: "my-vm", Extensions: []infrav1.VMExtension{
+				{Name: "broken", Publisher: "Contoso", Type: "Broken", Version: "1.0"},
+				customScript,
+			}},
+			expectedStatuses: []infrav1.VMExtensionStatus{
+				{Name: "broken", ProvisioningState: "Failed"},
+				{Name: "custom-script", ProvisioningState: "Succeeded"},
+			},
+			expectedError: "failed to provision extension broken of VM my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "my-vm", gomock.Any()).
+					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")).Times(2)
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "broken", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{})).
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdate(context.TODO(), "my-rg", "my-vm", "custom-script", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+			},
+		},
+		{
+			name:             "uninstalls removed extensions",
+			spec:             Spec{VMName: "my-vm", Removed: []string{"custom-script", "already-gone"}},
+			expectedStatuses: []infrav1.VMExtensionStatus{},
+			expect: func(m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				m.Delete(context.TODO(), "my-rg", "my-vm", "custom-script")
+				m.Delete(context.TODO(), "my-rg", "my-vm", "already-gone").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+			},
+		},
+		{
+			name:             "error while uninstalling an extension",
+			spec:             Spec{VMName: "my-vm", Removed: []string{"custom-script"}},
+			expectedStatuses: []infrav1.VMExtensionStatus{},
+			expectedError:    "failed to delete extension custom-script of VM my-vm: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_virtualmachineextensions.MockClientMockRecorder) {
+				m.Delete(context.TODO(), "my-rg", "my-vm", "custom-script").
+					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_virtualmachineextensions.NewMockClient(mockCtrl)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				AzureClients: scope.AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+				Cluster: cluster,
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						Location:       "test-location",
+						ResourceGroup:  "my-rg",
+						SubscriptionID: "123",
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
+						},
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  clusterScope,
+				Client: clientMock,
+			}
+
+			statuses, err := s.ReconcileVMExtensions(context.TODO(), &tc.spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(statuses).To(Equal(tc.expectedStatuses))
+		})
+	}
+}
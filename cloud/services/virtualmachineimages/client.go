@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachineimages
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	Get(ctx context.Context, location, publisher, offer, sku, version string) (compute.VirtualMachineImage, error)
+	List(ctx context.Context, location, publisher, offer, sku string) ([]compute.VirtualMachineImageResource, error)
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	images compute.VirtualMachineImagesClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new marketplace VM images client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		images: newVirtualMachineImagesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+	}
+}
+
+// newVirtualMachineImagesClient creates a new marketplace VM images client from subscription ID.
+func newVirtualMachineImagesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineImagesClient {
+	c := compute.NewVirtualMachineImagesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
+// Get gets the given version of a marketplace VM image in a location.
+func (ac *AzureClient) Get(ctx context.Context, location, publisher, offer, sku, version string) (compute.VirtualMachineImage, error) {
+	return ac.images.Get(ctx, location, publisher, offer, sku, version)
+}
+
+// List returns the versions of a marketplace VM image in a location.
+func (ac *AzureClient) List(ctx context.Context, location, publisher, offer, sku string) ([]compute.VirtualMachineImageResource, error) {
+	images, err := ac.images.List(ctx, location, publisher, offer, sku, "", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if images.Value == nil {
+		return nil, nil
+	}
+	return *images.Value, nil
+}
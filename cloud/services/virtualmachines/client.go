@@ -28,6 +28,7 @@ import (
 type Client interface {
 	Get(context.Context, string, string) (compute.VirtualMachine, error)
 	CreateOrUpdate(context.Context, string, string, compute.VirtualMachine) error
+	Update(context.Context, string, string, compute.VirtualMachineUpdate) error
 	Delete(context.Context, string, string) error
 }
 
@@ -47,14 +48,14 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newVirtualMachinesClient creates a new VM client from subscription ID.
 func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
 	vmClient := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
-	vmClient.Authorizer = authorizer
-	vmClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&vmClient.Client, authorizer)
 	return vmClient
 }
 
 // Get retrieves information about the model view or the instance view of a virtual machine.
 func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmName string) (compute.VirtualMachine, error) {
-	return ac.virtualmachines.Get(ctx, resourceGroupName, vmName, "")
+	// the instance view reports the power state of the VM, which tells whether a Spot VM was evicted
+	return ac.virtualmachines.Get(ctx, resourceGroupName, vmName, compute.InstanceView)
 }
 
 // CreateOrUpdate the operation to create or update a virtual machine.
@@ -71,6 +72,20 @@ func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vm
 	return err
 }
 
+// Update the operation to update a virtual machine.
+func (ac *AzureClient) Update(ctx context.Context, resourceGroupName, vmName string, vm compute.VirtualMachineUpdate) error {
+	future, err := ac.virtualmachines.Update(ctx, resourceGroupName, vmName, vm)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, ac.virtualmachines.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(ac.virtualmachines)
+	return err
+}
+
 // Delete the operation to delete a virtual machine.
 func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, vmName string) error {
 	future, err := ac.virtualmachines.Delete(ctx, resourceGroupName, vmName)
@@ -18,9 +18,12 @@ package virtualmachines
 
 import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/dedicatedhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/identities"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts"
 )
 
 // Service provides operations on azure resources
@@ -31,6 +34,9 @@ type Service struct {
 	InterfacesClient      networkinterfaces.Client
 	PublicIPsClient       publicips.Client
 	RoleAssignmentsClient roleassignments.Client
+	IdentitiesClient      identities.Client
+	DedicatedHostsClient  dedicatedhosts.Client
+	StorageAccountsClient storageaccounts.Client
 }
 
 // NewService creates a new service.
@@ -42,5 +48,8 @@ func NewService(scope *scope.ClusterScope, machineScope *scope.MachineScope) *Se
 		InterfacesClient:      networkinterfaces.NewClient(scope),
 		PublicIPsClient:       publicips.NewClient(scope),
 		RoleAssignmentsClient: roleassignments.NewClient(scope),
+		IdentitiesClient:      identities.NewClient(scope),
+		DedicatedHostsClient:  dedicatedhosts.NewClient(scope),
+		StorageAccountsClient: storageaccounts.NewClient(scope),
 	}
 }
@@ -21,15 +21,17 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
@@ -40,18 +42,25 @@ const azureBuiltInContributorID = "b24988ac-6180-42a0-ab88-20f7382dd24c"
 
 // Spec input specification for Get/CreateOrUpdate/Delete calls
 type Spec struct {
-	Name                   string
-	NICNames               []string
-	SSHKeyData             string
-	Size                   string
-	Zone                   string
-	Image                  *infrav1.Image
-	Identity               infrav1.VMIdentity
-	OSDisk                 infrav1.OSDisk
-	DataDisks              []infrav1.DataDisk
-	CustomData             string
-	UserAssignedIdentities []infrav1.UserAssignedIdentity
-	SpotVMOptions          *infrav1.SpotVMOptions
+	Name                      string
+	NICNames                  []string
+	SSHKeyData                string
+	AdminUsername             string
+	Size                      string
+	Zone                      string
+	Image                     *infrav1.Image
+	Identity                  infrav1.VMIdentity
+	OSDisk                    infrav1.OSDisk
+	DataDisks                 []infrav1.DataDisk
+	CustomData                string
+	UserAssignedIdentities    []string
+	SpotVMOptions             *infrav1.SpotVMOptions
+	ProximityPlacementGroupID string
+	AvailabilitySetID         string
+	DedicatedHostGroupID      string
+	DedicatedHostID           string
+	DiskEncryptionSetID       string
+	BootDiagnostics           *infrav1.BootDiagnostics
 }
 
 // Get provides information about a virtual machine.
@@ -85,11 +94,24 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return errors.New("invalid VM specification")
 	}
 
+	if err := s.ValidateDedicatedHost(ctx, vmSpec); err != nil {
+		return errors.Wrap(err, "cannot create VM")
+	}
+
+	if err := s.ValidateBootDiagnostics(ctx, vmSpec); err != nil {
+		return errors.Wrap(err, "cannot create VM")
+	}
+
 	storageProfile, err := generateStorageProfile(*vmSpec)
 	if err != nil {
 		return err
 	}
 
+	osProfile, err := generateOSProfile(*vmSpec)
+	if err != nil {
+		return err
+	}
+
 	nicRefs := make([]compute.NetworkInterfaceReference, len(vmSpec.NICNames))
 	for i, nicName := range vmSpec.NICNames {
 		primary := i == 0
@@ -114,7 +136,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	// Set the cloud provider tag
 	additionalTags[infrav1.ClusterAzureCloudProviderTagKey(s.MachineScope.Name())] = string(infrav1.ResourceLifecycleOwned)
 
-	priority, evictionPolicy, billingProfile, err := getSpotVMOptions(vmSpec.SpotVMOptions)
+	priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(vmSpec.SpotVMOptions)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get Spot VM options")
 	}
@@ -128,27 +150,13 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 			Role:        to.StringPtr(s.MachineScope.Role()),
 			Additional:  additionalTags,
 		})),
+		Plan: converters.ImageToPlan(vmSpec.Image),
 		VirtualMachineProperties: &compute.VirtualMachineProperties{
 			HardwareProfile: &compute.HardwareProfile{
 				VMSize: compute.VirtualMachineSizeTypes(vmSpec.Size),
 			},
 			StorageProfile: storageProfile,
-			OsProfile: &compute.OSProfile{
-				ComputerName:  to.StringPtr(vmSpec.Name),
-				AdminUsername: to.StringPtr(azure.DefaultUserName),
-				CustomData:    to.StringPtr(vmSpec.CustomData),
-				LinuxConfiguration: &compute.LinuxConfiguration{
-					DisablePasswordAuthentication: to.BoolPtr(true),
-					SSH: &compute.SSHConfiguration{
-						PublicKeys: &[]compute.SSHPublicKey{
-							{
-								Path:    to.StringPtr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
-								KeyData: to.StringPtr(vmSpec.SSHKeyData),
-							},
-						},
-					},
-				},
-			},
+			OsProfile:      osProfile,
 			NetworkProfile: &compute.NetworkProfile{
 				NetworkInterfaces: &nicRefs,
 			},
@@ -165,6 +173,39 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		virtualMachine.Zones = &zones
 	}
 
+	if vmSpec.DedicatedHostID != "" {
+		virtualMachine.VirtualMachineProperties.Host = &compute.SubResource{
+			ID: to.StringPtr(vmSpec.DedicatedHostID),
+		}
+	} else if vmSpec.DedicatedHostGroupID != "" {
+		virtualMachine.VirtualMachineProperties.HostGroup = &compute.SubResource{
+			ID: to.StringPtr(vmSpec.DedicatedHostGroupID),
+		}
+	}
+
+	if vmSpec.BootDiagnostics != nil && vmSpec.BootDiagnostics.Enabled {
+		// without a storage URI, the boot diagnostics are stored in a managed storage account
+		bootDiagnostics := &compute.BootDiagnostics{Enabled: to.BoolPtr(true)}
+		if vmSpec.BootDiagnostics.StorageAccountURI != "" {
+			bootDiagnostics.StorageURI = to.StringPtr(vmSpec.BootDiagnostics.StorageAccountURI)
+		}
+		virtualMachine.VirtualMachineProperties.DiagnosticsProfile = &compute.DiagnosticsProfile{
+			BootDiagnostics: bootDiagnostics,
+		}
+	}
+
+	if vmSpec.ProximityPlacementGroupID != "" {
+		virtualMachine.VirtualMachineProperties.ProximityPlacementGroup = &compute.SubResource{
+			ID: to.StringPtr(vmSpec.ProximityPlacementGroupID),
+		}
+	}
+
+	if vmSpec.AvailabilitySetID != "" {
+		virtualMachine.VirtualMachineProperties.AvailabilitySet = &compute.SubResource{
+			ID: to.StringPtr(vmSpec.AvailabilitySetID),
+		}
+	}
+
 	if vmSpec.Identity == infrav1.VMIdentitySystemAssigned {
 		virtualMachine.Identity = &compute.VirtualMachineIdentity{
 			Type: compute.ResourceIdentityTypeSystemAssigned,
@@ -173,16 +214,15 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		if len(vmSpec.UserAssignedIdentities) == 0 {
 			return errors.Wrapf(err, "cannot create VM: The user-assigned identity provider ids must not be null or empty for 'UserAssigned' identity type.")
 		}
+		if err := s.ValidateUserAssignedIdentities(ctx, vmSpec.UserAssignedIdentities); err != nil {
+			return errors.Wrap(err, "cannot create VM")
+		}
 		// UserAssignedIdentities - The list of user identities associated with the Virtual Machine.
 		// The user identity dictionary key references will be ARM resource ids in the form:
 		// '/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.ManagedIdentity/userAssignedIdentities/{identityName}'.
 		userIdentitiesMap := make(map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue, len(vmSpec.UserAssignedIdentities))
 		for _, id := range vmSpec.UserAssignedIdentities {
-			key := id.ProviderID
-			if strings.HasPrefix(id.ProviderID, "azure:///") {
-				key = strings.TrimPrefix(key, "azure:///")
-			}
-			userIdentitiesMap[key] = &compute.VirtualMachineIdentityUserAssignedIdentitiesValue{}
+			userIdentitiesMap[id] = &compute.VirtualMachineIdentityUserAssignedIdentitiesValue{}
 		}
 		virtualMachine.Identity = &compute.VirtualMachineIdentity{
 			Type:                   compute.ResourceIdentityTypeUserAssigned,
@@ -210,6 +250,156 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	return nil
 }
 
+// ValidateUserAssignedIdentities returns an error when one of the user-assigned identities doesn't exist, or isn't
+// in a resource group the cluster identity can read.
+func (s *Service) ValidateUserAssignedIdentities(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if _, err := s.IdentitiesClient.Get(ctx, id); err != nil {
+			if azure.ResourceNotFound(err) {
+				return errors.Errorf("user-assigned identity %s does not exist", id)
+			}
+			return errors.Wrapf(err, "failed to get user-assigned identity %s", id)
+		}
+	}
+	return nil
+}
+
+// ReconcileDataDisks attaches the data disks of the spec missing from the existing VM, identified by their LUN.
+// Azure attaches data disks to a running VM, but doesn't resize or detach them without stopping it, so the data
+// disks already attached are left as they are.
+func (s *Service) ReconcileDataDisks(ctx context.Context, vmSpec *Spec) error {
+	vm, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), vmSpec.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get VM %s", vmSpec.Name)
+	}
+	if vm.VirtualMachineProperties == nil || vm.StorageProfile == nil {
+		return errors.Errorf("VM %s has no storage profile", vmSpec.Name)
+	}
+
+	var dataDisks []compute.DataDisk
+	attachedLuns := make(map[int32]struct{})
+	if vm.StorageProfile.DataDisks != nil {
+		dataDisks = append(dataDisks, *vm.StorageProfile.DataDisks...)
+		for _, disk := range *vm.StorageProfile.DataDisks {
+			if disk.Lun != nil {
+				attachedLuns[*disk.Lun] = struct{}{}
+			}
+		}
+	}
+	attached := len(dataDisks)
+	for _, disk := range vmSpec.DataDisks {
+		if disk.Lun == nil {
+			continue
+		}
+		if _, ok := attachedLuns[*disk.Lun]; !ok {
+			dataDisks = append(dataDisks, generateDataDisk(*vmSpec, disk))
+		}
+	}
+	if len(dataDisks) == attached {
+		return nil
+	}
+
+	s.Scope.V(2).Info("attaching data disks to VM", "vm", vmSpec.Name, "count", len(dataDisks)-attached)
+	update := compute.VirtualMachineUpdate{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			StorageProfile: &compute.StorageProfile{
+				DataDisks: &dataDisks,
+			},
+		},
+	}
+	if err := s.Client.Update(ctx, s.Scope.ResourceGroup(), vmSpec.Name, update); err != nil {
+		return errors.Wrapf(err, "failed to attach data disks to VM %s", vmSpec.Name)
+	}
+	return nil
+}
+
+// ValidateDedicatedHost checks that the VM can be placed on its dedicated host. The host group must be in the
+// location and availability zone of the VM. Without a host, the host group must support automatic placement, otherwise
+// the host must have capacity left for the VM size.
+func (s *Service) ValidateDedicatedHost(ctx context.Context, vmSpec *Spec) error {
+	if vmSpec.DedicatedHostGroupID == "" {
+		return nil
+	}
+
+	group, err := s.DedicatedHostsClient.GetGroup(ctx, vmSpec.DedicatedHostGroupID)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return errors.Errorf("dedicated host group %s does not exist", vmSpec.DedicatedHostGroupID)
+		}
+		return errors.Wrapf(err, "failed to get dedicated host group %s", vmSpec.DedicatedHostGroupID)
+	}
+	if !strings.EqualFold(to.String(group.Location), s.Scope.Location()) {
+		return errors.Errorf("dedicated host group %s in location %s can't host VM %s in location %s", vmSpec.DedicatedHostGroupID, to.String(group.Location), vmSpec.Name, s.Scope.Location())
+	}
+	var groupZone string
+	if group.Zones != nil && len(*group.Zones) > 0 {
+		groupZone = (*group.Zones)[0]
+	}
+	if groupZone != vmSpec.Zone {
+		return errors.Errorf("dedicated host group %s in %s can't host VM %s in %s", vmSpec.DedicatedHostGroupID, describeZone(groupZone), vmSpec.Name, describeZone(vmSpec.Zone))
+	}
+
+	if vmSpec.DedicatedHostID == "" {
+		if group.DedicatedHostGroupProperties == nil || !to.Bool(group.SupportAutomaticPlacement) {
+			return errors.Errorf("dedicated host group %s doesn't support automatic placement, the dedicated host of VM %s must be set", vmSpec.DedicatedHostGroupID, vmSpec.Name)
+		}
+		return nil
+	}
+
+	host, err := s.DedicatedHostsClient.GetHost(ctx, vmSpec.DedicatedHostID)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return errors.Errorf("dedicated host %s does not exist", vmSpec.DedicatedHostID)
+		}
+		return errors.Wrapf(err, "failed to get dedicated host %s", vmSpec.DedicatedHostID)
+	}
+	if host.DedicatedHostProperties == nil || host.InstanceView == nil || host.InstanceView.AvailableCapacity == nil || host.InstanceView.AvailableCapacity.AllocatableVMs == nil {
+		// the capacity of the host is unknown, let Azure place the VM
+		return nil
+	}
+	for _, allocatable := range *host.InstanceView.AvailableCapacity.AllocatableVMs {
+		if strings.EqualFold(to.String(allocatable.VMSize), vmSpec.Size) {
+			if allocatable.Count == nil || *allocatable.Count < 1 {
+				return errors.Errorf("dedicated host %s has insufficient capacity for VM %s of size %s", vmSpec.DedicatedHostID, vmSpec.Name, vmSpec.Size)
+			}
+			return nil
+		}
+	}
+	return errors.Errorf("dedicated host %s can't host VM %s of size %s", vmSpec.DedicatedHostID, vmSpec.Name, vmSpec.Size)
+}
+
+// ValidateBootDiagnostics checks that the storage account of the boot diagnostics of the VM is a Standard storage
+// account of the subscription of the cluster. Boot diagnostics stored in a managed storage account aren't checked.
+func (s *Service) ValidateBootDiagnostics(ctx context.Context, vmSpec *Spec) error {
+	if vmSpec.BootDiagnostics == nil || !vmSpec.BootDiagnostics.Enabled || vmSpec.BootDiagnostics.StorageAccountURI == "" {
+		return nil
+	}
+
+	uri := vmSpec.BootDiagnostics.StorageAccountURI
+	accounts, err := s.StorageAccountsClient.List(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to look for the storage account %s of the boot diagnostics of VM %s", uri, vmSpec.Name)
+	}
+	for _, account := range accounts {
+		if account.AccountProperties == nil || account.PrimaryEndpoints == nil ||
+			!strings.EqualFold(strings.TrimSuffix(to.String(account.PrimaryEndpoints.Blob), "/"), strings.TrimSuffix(uri, "/")) {
+			continue
+		}
+		if account.Sku != nil && account.Sku.Tier == storage.Premium {
+			return errors.Errorf("storage account %s of the boot diagnostics of VM %s is a Premium storage account, boot diagnostics require a Standard storage account", uri, vmSpec.Name)
+		}
+		return nil
+	}
+	return errors.Errorf("storage account %s of the boot diagnostics of VM %s doesn't exist in subscription %s", uri, vmSpec.Name, s.Scope.SubscriptionID())
+}
+
+func describeZone(zone string) string {
+	if zone == "" {
+		return "no availability zone"
+	}
+	return fmt.Sprintf("availability zone %s", zone)
+}
+
 func (s *Service) createRoleAssignmentForIdentity(ctx context.Context, vmName string) error {
 	resultVM, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), vmName)
 	if err != nil {
@@ -310,7 +500,7 @@ func (s *Service) getAddresses(ctx context.Context, vm compute.VirtualMachine) (
 // getPublicIPAddress will fetch a public ip address resource by name and return a nodeaddresss representation
 func (s *Service) getPublicIPAddress(ctx context.Context, publicIPAddressName string) (corev1.NodeAddress, error) {
 	retAddress := corev1.NodeAddress{}
-	publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.ResourceGroup(), publicIPAddressName)
+	publicIP, err := s.PublicIPsClient.Get(ctx, s.Scope.NetworkResourceGroup(), publicIPAddressName)
 	if err != nil {
 		return retAddress, err
 	}
@@ -336,21 +526,35 @@ func generateStorageProfile(vmSpec Spec) (*compute.StorageProfile, error) {
 			Name:         to.StringPtr(azure.GenerateOSDiskName(vmSpec.Name)),
 			OsType:       compute.OperatingSystemTypes(vmSpec.OSDisk.OSType),
 			CreateOption: compute.DiskCreateOptionTypesFromImage,
-			DiskSizeGB:   to.Int32Ptr(vmSpec.OSDisk.DiskSizeGB),
 			ManagedDisk: &compute.ManagedDiskParameters{
 				StorageAccountType: compute.StorageAccountTypes(vmSpec.OSDisk.ManagedDisk.StorageAccountType),
 			},
 		},
 	}
 
+	// an empty disk size is the size of the OS disk of the image
+	if vmSpec.OSDisk.DiskSizeGB != 0 {
+		storageProfile.OsDisk.DiskSizeGB = to.Int32Ptr(vmSpec.OSDisk.DiskSizeGB)
+	}
+
+	// an ephemeral OS disk is stored on the VM host and needs read-only caching
+	if vmSpec.OSDisk.DiffDiskSettings != nil {
+		storageProfile.OsDisk.Caching = compute.CachingTypesReadOnly
+		storageProfile.OsDisk.DiffDiskSettings = &compute.DiffDiskSettings{
+			Option:    compute.DiffDiskOptions(vmSpec.OSDisk.DiffDiskSettings.Option),
+			Placement: compute.DiffDiskPlacement(vmSpec.OSDisk.DiffDiskSettings.Placement),
+		}
+	}
+
+	if vmSpec.DiskEncryptionSetID != "" {
+		storageProfile.OsDisk.ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{
+			ID: to.StringPtr(vmSpec.DiskEncryptionSetID),
+		}
+	}
+
 	dataDisks := []compute.DataDisk{}
 	for _, disk := range vmSpec.DataDisks {
-		dataDisks = append(dataDisks, compute.DataDisk{
-			CreateOption: compute.DiskCreateOptionTypesEmpty,
-			DiskSizeGB:   to.Int32Ptr(disk.DiskSizeGB),
-			Lun:          disk.Lun,
-			Name:         to.StringPtr(azure.GenerateDataDiskName(vmSpec.Name, disk.NameSuffix)),
-		})
+		dataDisks = append(dataDisks, generateDataDisk(vmSpec, disk))
 	}
 	storageProfile.DataDisks = &dataDisks
 
@@ -364,22 +568,93 @@ func generateStorageProfile(vmSpec Spec) (*compute.StorageProfile, error) {
 	return storageProfile, nil
 }
 
-func getSpotVMOptions(spotVMOptions *infrav1.SpotVMOptions) (compute.VirtualMachinePriorityTypes, compute.VirtualMachineEvictionPolicyTypes, *compute.BillingProfile, error) {
-	// Spot VM not requested, return zero values to apply defaults
-	if spotVMOptions == nil {
-		return compute.VirtualMachinePriorityTypes(""), compute.VirtualMachineEvictionPolicyTypes(""), nil, nil
+const (
+	// windowsAdminPasswordBytes is the number of random bytes of the admin password of Windows VMs, which is 88
+	// characters long once base64 encoded. Azure accepts passwords of 12 to 123 characters.
+	windowsAdminPasswordBytes = 64
+	// windowsAdminPasswordAttempts is the number of passwords generated to find one meeting the complexity
+	// requirements of Windows.
+	windowsAdminPasswordAttempts = 10
+)
+
+// generateOSProfile generates the OS profile of the VM. The admin user defaults to capi. The SSH public key is added
+// to the authorized_keys of the admin user of Linux VMs. Windows VMs can't be configured with an SSH key by Azure and get a random admin password,
+// which isn't kept: the bootstrap data of Windows VMs sets up remote access.
+func generateOSProfile(vmSpec Spec) (*compute.OSProfile, error) {
+	adminUsername := vmSpec.AdminUsername
+	if adminUsername == "" {
+		adminUsername = azure.DefaultUserName
 	}
-	var billingProfile *compute.BillingProfile
-	if spotVMOptions.MaxPrice != nil {
-		maxPrice, err := strconv.ParseFloat(*spotVMOptions.MaxPrice, 64)
+	osProfile := &compute.OSProfile{
+		ComputerName:  to.StringPtr(vmSpec.Name),
+		AdminUsername: to.StringPtr(adminUsername),
+		CustomData:    to.StringPtr(vmSpec.CustomData),
+	}
+
+	if vmSpec.OSDisk.OSType == infrav1.WindowsOS {
+		if errs := infrav1.ValidateComputerName(vmSpec.Name, vmSpec.OSDisk.OSType, field.NewPath("name")); len(errs) > 0 {
+			return nil, errors.Wrapf(errs.ToAggregate(), "invalid Windows VM %s", vmSpec.Name)
+		}
+		password, err := generateWindowsAdminPassword()
 		if err != nil {
-			return compute.VirtualMachinePriorityTypes(""), compute.VirtualMachineEvictionPolicyTypes(""), nil, err
+			return nil, errors.Wrap(err, "failed to generate the admin password of the Windows VM")
 		}
-		billingProfile = &compute.BillingProfile{
-			MaxPrice: &maxPrice,
+		osProfile.AdminPassword = to.StringPtr(password)
+		osProfile.WindowsConfiguration = &compute.WindowsConfiguration{
+			// updates restarting nodes are left to the cluster operator, as for Linux VMs
+			EnableAutomaticUpdates: to.BoolPtr(false),
 		}
+		return osProfile, nil
+	}
+
+	osProfile.LinuxConfiguration = &compute.LinuxConfiguration{
+		DisablePasswordAuthentication: to.BoolPtr(true),
+		SSH: &compute.SSHConfiguration{
+			PublicKeys: &[]compute.SSHPublicKey{
+				{
+					Path:    to.StringPtr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername)),
+					KeyData: to.StringPtr(vmSpec.SSHKeyData),
+				},
+			},
+		},
 	}
-	return compute.Spot, compute.Deallocate, billingProfile, nil
+	return osProfile, nil
+}
+
+// generateWindowsAdminPassword generates a random admin password meeting the complexity requirements of Windows.
+func generateWindowsAdminPassword() (string, error) {
+	for i := 0; i < windowsAdminPasswordAttempts; i++ {
+		password, err := GenerateRandomString(windowsAdminPasswordBytes)
+		if err != nil {
+			return "", err
+		}
+		if isComplexWindowsPassword(password) {
+			return password, nil
+		}
+	}
+	return "", errors.Errorf("no password meeting the complexity requirements of Windows was generated in %d attempts", windowsAdminPasswordAttempts)
+}
+
+// isComplexWindowsPassword returns true if a password meets the complexity requirements of Windows VMs: between 12
+// and 123 characters, with at least 3 of a lowercase letter, an uppercase letter, a digit and a special character.
+func isComplexWindowsPassword(password string) bool {
+	if len(password) < 12 || len(password) > 123 {
+		return false
+	}
+	var lower, upper, digit, special int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			special = 1
+		}
+	}
+	return lower+upper+digit+special >= 3
 }
 
 // GenerateRandomString returns a URL-safe, base64 encoded
@@ -396,3 +671,26 @@ func GenerateRandomString(n int) (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(b), err
 }
+
+// generateDataDisk generates the SDK data disk created empty for a data disk of the VM.
+func generateDataDisk(vmSpec Spec, disk infrav1.DataDisk) compute.DataDisk {
+	dataDisk := compute.DataDisk{
+		CreateOption: compute.DiskCreateOptionTypesEmpty,
+		DiskSizeGB:   to.Int32Ptr(disk.DiskSizeGB),
+		Lun:          disk.Lun,
+		Name:         to.StringPtr(azure.GenerateDataDiskName(vmSpec.Name, disk.NameSuffix)),
+		Caching:      compute.CachingTypes(disk.CachingType),
+	}
+	if disk.ManagedDisk != nil || vmSpec.DiskEncryptionSetID != "" {
+		dataDisk.ManagedDisk = &compute.ManagedDiskParameters{}
+	}
+	if disk.ManagedDisk != nil {
+		dataDisk.ManagedDisk.StorageAccountType = compute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType)
+	}
+	if vmSpec.DiskEncryptionSetID != "" {
+		dataDisk.ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{
+			ID: to.StringPtr(vmSpec.DiskEncryptionSetID),
+		}
+	}
+	return dataDisk
+}
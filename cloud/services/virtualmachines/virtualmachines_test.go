@@ -19,12 +19,16 @@ package virtualmachines
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/dedicatedhosts/mock_dedicatedhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/identities/mock_identities"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces/mock_networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/roleassignments/mock_roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/storageaccounts/mock_storageaccounts"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines/mock_virtualmachines"
 
 	"github.com/Azure/go-autorest/autorest"
@@ -32,6 +36,8 @@ import (
 	"github.com/golang/mock/gomock"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	network "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -78,6 +84,10 @@ func TestInvalidVM(t *testing.T) {
 				SubscriptionID: subscriptionID,
 				NetworkSpec: infrav1.NetworkSpec{
 					Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+					Subnets: infrav1.Subnets{
+						{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+						{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+					},
 				},
 			},
 		},
@@ -315,8 +325,11 @@ func TestGetVM(t *testing.T) {
 							Subnets: infrav1.Subnets{
 								&infrav1.SubnetSpec{
 									Name: "subnet-1",
+									Role: infrav1.SubnetNode,
+								},
+								&infrav1.SubnetSpec{
+									Role: infrav1.SubnetControlPlane,
 								},
-								&infrav1.SubnetSpec{},
 							},
 						},
 					},
@@ -394,8 +407,11 @@ func TestReconcileVM(t *testing.T) {
 						Subnets: infrav1.Subnets{
 							&infrav1.SubnetSpec{
 								Name: "subnet-1",
+								Role: infrav1.SubnetNode,
+							},
+							&infrav1.SubnetSpec{
+								Role: infrav1.SubnetControlPlane,
 							},
-							&infrav1.SubnetSpec{},
 						},
 					},
 				},
@@ -413,6 +429,57 @@ func TestReconcileVM(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with a custom admin username",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						Data: to.StringPtr("bootstrap-data"),
+					},
+					Version: to.StringPtr("1.15.7"),
+				},
+			},
+			machineConfig: &infrav1.AzureMachineSpec{
+				VMSize:        "Standard_B2ms",
+				Location:      "eastus",
+				Image:         image,
+				AdminUsername: "k8sadmin",
+			},
+			azureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: subscriptionID,
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								Name: "subnet-1",
+								Role: infrav1.SubnetNode,
+							},
+							&infrav1.SubnetSpec{
+								Role: infrav1.SubnetControlPlane,
+							},
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					Network: infrav1.Network{
+						APIServerIP: infrav1.PublicIP{
+							DNSName: "azure-test-dns",
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mra *mock_roleassignments.MockClientMockRecorder) {
+				mnic.Get(gomock.Any(), gomock.Any(), gomock.Any())
+				m.CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+					g.Expect(vm.OsProfile.AdminUsername).To(Equal(to.StringPtr("k8sadmin")))
+					g.Expect((*vm.OsProfile.LinuxConfiguration.SSH.PublicKeys)[0].Path).To(Equal(to.StringPtr("/home/k8sadmin/.ssh/authorized_keys")))
+				})
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with system assigned identity",
 			machine: clusterv1.Machine{
@@ -439,8 +506,11 @@ func TestReconcileVM(t *testing.T) {
 						Subnets: infrav1.Subnets{
 							&infrav1.SubnetSpec{
 								Name: "subnet-1",
+								Role: infrav1.SubnetNode,
+							},
+							&infrav1.SubnetSpec{
+								Role: infrav1.SubnetControlPlane,
 							},
-							&infrav1.SubnetSpec{},
 						},
 					},
 				},
@@ -485,8 +555,11 @@ func TestReconcileVM(t *testing.T) {
 						Subnets: infrav1.Subnets{
 							&infrav1.SubnetSpec{
 								Name: "subnet-1",
+								Role: infrav1.SubnetNode,
+							},
+							&infrav1.SubnetSpec{
+								Role: infrav1.SubnetControlPlane,
 							},
-							&infrav1.SubnetSpec{},
 						},
 					},
 				},
@@ -530,8 +603,11 @@ func TestReconcileVM(t *testing.T) {
 						Subnets: infrav1.Subnets{
 							&infrav1.SubnetSpec{
 								Name: "subnet-1",
+								Role: infrav1.SubnetNode,
+							},
+							&infrav1.SubnetSpec{
+								Role: infrav1.SubnetControlPlane,
 							},
-							&infrav1.SubnetSpec{},
 						},
 					},
 				},
@@ -553,6 +629,59 @@ func TestReconcileVM(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a Windows vm",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						Data: to.StringPtr("bootstrap-data"),
+					},
+					Version: to.StringPtr("1.19.1"),
+				},
+			},
+			machineConfig: &infrav1.AzureMachineSpec{
+				VMSize:   "Standard_D2s_v3",
+				Location: "eastus",
+				Image:    image,
+				OSDisk:   infrav1.OSDisk{OSType: infrav1.WindowsOS},
+			},
+			azureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					SubscriptionID: subscriptionID,
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							&infrav1.SubnetSpec{
+								Name: "subnet-1",
+								Role: infrav1.SubnetNode,
+							},
+							&infrav1.SubnetSpec{
+								Role: infrav1.SubnetControlPlane,
+							},
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					Network: infrav1.Network{
+						APIServerIP: infrav1.PublicIP{
+							DNSName: "azure-test-dns",
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder, mnic *mock_networkinterfaces.MockClientMockRecorder, mpip *mock_publicips.MockClientMockRecorder, mra *mock_roleassignments.MockClientMockRecorder) {
+				mnic.Get(gomock.Any(), gomock.Any(), gomock.Any())
+				m.CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_, _, _ interface{}, vm compute.VirtualMachine) {
+					g.Expect(vm.OsProfile.LinuxConfiguration).To(BeNil())
+					g.Expect(vm.OsProfile.WindowsConfiguration).To(Equal(&compute.WindowsConfiguration{EnableAutomaticUpdates: to.BoolPtr(false)}))
+					g.Expect(isComplexWindowsPassword(to.String(vm.OsProfile.AdminPassword))).To(BeTrue())
+					g.Expect(vm.StorageProfile.OsDisk.OsType).To(Equal(compute.Windows))
+				})
+			},
+			expectedError: "",
+		},
 		{
 			name: "vm creation fails",
 			machine: clusterv1.Machine{
@@ -578,8 +707,11 @@ func TestReconcileVM(t *testing.T) {
 						Subnets: infrav1.Subnets{
 							&infrav1.SubnetSpec{
 								Name: "subnet-1",
+								Role: infrav1.SubnetNode,
+							},
+							&infrav1.SubnetSpec{
+								Role: infrav1.SubnetControlPlane,
 							},
-							&infrav1.SubnetSpec{},
 						},
 					},
 				},
@@ -677,6 +809,7 @@ func TestReconcileVM(t *testing.T) {
 				Name:          machineScope.Name(),
 				NICNames:      []string{"test-nic"},
 				SSHKeyData:    "fake-key",
+				AdminUsername: machineScope.AdminUsername(),
 				Size:          machineScope.AzureMachine.Spec.VMSize,
 				OSDisk:        machineScope.AzureMachine.Spec.OSDisk,
 				Image:         machineScope.AzureMachine.Spec.Image,
@@ -765,6 +898,10 @@ func TestDeleteVM(t *testing.T) {
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg"},
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
 						},
 					},
 				},
@@ -786,3 +923,437 @@ func TestDeleteVM(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateUserAssignedIdentities(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ids           []string
+		expect        func(mid *mock_identities.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "identities exist",
+			ids:  []string{"/subscriptions/123/resourceGroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1"},
+			expect: func(mid *mock_identities.MockClientMockRecorder) {
+				mid.Get(gomock.Any(), "/subscriptions/123/resourceGroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1").Return(msi.Identity{}, nil)
+			},
+			expectedError: "",
+		},
+		{
+			name: "identity does not exist",
+			ids:  []string{"/subscriptions/123/resourceGroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1"},
+			expect: func(mid *mock_identities.MockClientMockRecorder) {
+				mid.Get(gomock.Any(), gomock.Any()).Return(msi.Identity{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "user-assigned identity /subscriptions/123/resourceGroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1 does not exist",
+		},
+		{
+			name: "identity resource group is not accessible",
+			ids:  []string{"/subscriptions/123/resourceGroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1"},
+			expect: func(mid *mock_identities.MockClientMockRecorder) {
+				mid.Get(gomock.Any(), gomock.Any()).Return(msi.Identity{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+			expectedError: "failed to get user-assigned identity /subscriptions/123/resourceGroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1: #: Forbidden: StatusCode=403",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			identitiesMock := mock_identities.NewMockClient(mockCtrl)
+			tc.expect(identitiesMock.EXPECT())
+
+			s := &Service{
+				IdentitiesClient: identitiesMock,
+			}
+
+			err := s.ValidateUserAssignedIdentities(context.TODO(), tc.ids)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidateDedicatedHost(t *testing.T) {
+	hostGroupID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/hostGroups/my-host-group"
+	hostID := hostGroupID + "/hosts/my-host"
+	zonalGroup := compute.DedicatedHostGroup{
+		Location: to.StringPtr("westus2"),
+		Zones:    &[]string{"1"},
+		DedicatedHostGroupProperties: &compute.DedicatedHostGroupProperties{
+			SupportAutomaticPlacement: to.BoolPtr(true),
+		},
+	}
+	hostWithCapacity := func(count float64) compute.DedicatedHost {
+		return compute.DedicatedHost{
+			DedicatedHostProperties: &compute.DedicatedHostProperties{
+				InstanceView: &compute.DedicatedHostInstanceView{
+					AvailableCapacity: &compute.DedicatedHostAvailableCapacity{
+						AllocatableVMs: &[]compute.DedicatedHostAllocatableVM{
+							{VMSize: to.StringPtr("Standard_D2s_v3"), Count: to.Float64Ptr(count)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		vmSpec        Spec
+		expect        func(mdh *mock_dedicatedhosts.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:   "VM without dedicated host",
+			vmSpec: Spec{Name: "my-vm"},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {},
+		},
+		{
+			name:   "host group with automatic placement",
+			vmSpec: Spec{Name: "my-vm", Zone: "1", DedicatedHostGroupID: hostGroupID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(zonalGroup, nil)
+			},
+		},
+		{
+			name:   "host with capacity",
+			vmSpec: Spec{Name: "my-vm", Size: "Standard_D2s_v3", Zone: "1", DedicatedHostGroupID: hostGroupID, DedicatedHostID: hostID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(zonalGroup, nil)
+				mdh.GetHost(gomock.Any(), hostID).Return(hostWithCapacity(2), nil)
+			},
+		},
+		{
+			name:   "host group does not exist",
+			vmSpec: Spec{Name: "my-vm", Zone: "1", DedicatedHostGroupID: hostGroupID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(compute.DedicatedHostGroup{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "dedicated host group " + hostGroupID + " does not exist",
+		},
+		{
+			name:   "host group in another location",
+			vmSpec: Spec{Name: "my-vm", Zone: "1", DedicatedHostGroupID: hostGroupID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(compute.DedicatedHostGroup{Location: to.StringPtr("eastus"), Zones: &[]string{"1"}}, nil)
+			},
+			expectedError: "dedicated host group " + hostGroupID + " in location eastus can't host VM my-vm in location westus2",
+		},
+		{
+			name:   "host group in another availability zone",
+			vmSpec: Spec{Name: "my-vm", DedicatedHostGroupID: hostGroupID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(zonalGroup, nil)
+			},
+			expectedError: "dedicated host group " + hostGroupID + " in availability zone 1 can't host VM my-vm in no availability zone",
+		},
+		{
+			name:   "host group without automatic placement",
+			vmSpec: Spec{Name: "my-vm", DedicatedHostGroupID: hostGroupID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(compute.DedicatedHostGroup{Location: to.StringPtr("westus2")}, nil)
+			},
+			expectedError: "dedicated host group " + hostGroupID + " doesn't support automatic placement, the dedicated host of VM my-vm must be set",
+		},
+		{
+			name:   "host with insufficient capacity",
+			vmSpec: Spec{Name: "my-vm", Size: "Standard_D2s_v3", Zone: "1", DedicatedHostGroupID: hostGroupID, DedicatedHostID: hostID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(zonalGroup, nil)
+				mdh.GetHost(gomock.Any(), hostID).Return(hostWithCapacity(0), nil)
+			},
+			expectedError: "dedicated host " + hostID + " has insufficient capacity for VM my-vm of size Standard_D2s_v3",
+		},
+		{
+			name:   "host without the VM size",
+			vmSpec: Spec{Name: "my-vm", Size: "Standard_E2s_v3", Zone: "1", DedicatedHostGroupID: hostGroupID, DedicatedHostID: hostID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(zonalGroup, nil)
+				mdh.GetHost(gomock.Any(), hostID).Return(hostWithCapacity(2), nil)
+			},
+			expectedError: "dedicated host " + hostID + " can't host VM my-vm of size Standard_E2s_v3",
+		},
+		{
+			name:   "host is not accessible",
+			vmSpec: Spec{Name: "my-vm", Size: "Standard_D2s_v3", Zone: "1", DedicatedHostGroupID: hostGroupID, DedicatedHostID: hostID},
+			expect: func(mdh *mock_dedicatedhosts.MockClientMockRecorder) {
+				mdh.GetGroup(gomock.Any(), hostGroupID).Return(zonalGroup, nil)
+				mdh.GetHost(gomock.Any(), hostID).Return(compute.DedicatedHost{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+			expectedError: "failed to get dedicated host " + hostID + ": #: Forbidden: StatusCode=403",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			dedicatedHostsMock := mock_dedicatedhosts.NewMockClient(mockCtrl)
+			tc.expect(dedicatedHostsMock.EXPECT())
+
+			s := &Service{
+				Scope:                newTestClusterScope(g),
+				DedicatedHostsClient: dedicatedHostsMock,
+			}
+
+			err := s.ValidateDedicatedHost(context.TODO(), &tc.vmSpec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidateBootDiagnostics(t *testing.T) {
+	uri := "https://mystorageaccount.blob.core.windows.net/"
+	account := func(blob string, tier storage.SkuTier) storage.Account {
+		return storage.Account{
+			Sku: &storage.Sku{Tier: tier},
+			AccountProperties: &storage.AccountProperties{
+				PrimaryEndpoints: &storage.Endpoints{Blob: to.StringPtr(blob)},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		vmSpec        Spec
+		expect        func(msa *mock_storageaccounts.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:   "VM without boot diagnostics",
+			vmSpec: Spec{Name: "my-vm"},
+			expect: func(msa *mock_storageaccounts.MockClientMockRecorder) {},
+		},
+		{
+			name:   "managed storage account",
+			vmSpec: Spec{Name: "my-vm", BootDiagnostics: &infrav1.BootDiagnostics{Enabled: true}},
+			expect: func(msa *mock_storageaccounts.MockClientMockRecorder) {},
+		},
+		{
+			name:   "storage account of the subscription",
+			vmSpec: Spec{Name: "my-vm", BootDiagnostics: &infrav1.BootDiagnostics{Enabled: true, StorageAccountURI: "https://MyStorageAccount.blob.core.windows.net"}},
+			expect: func(msa *mock_storageaccounts.MockClientMockRecorder) {
+				msa.List(gomock.Any()).Return([]storage.Account{
+					account("https://otheraccount.blob.core.windows.net/", storage.Standard),
+					account(uri, storage.Standard),
+				}, nil)
+			},
+		},
+		{
+			name:   "storage account does not exist",
+			vmSpec: Spec{Name: "my-vm", BootDiagnostics: &infrav1.BootDiagnostics{Enabled: true, StorageAccountURI: uri}},
+			expect: func(msa *mock_storageaccounts.MockClientMockRecorder) {
+				msa.List(gomock.Any()).Return([]storage.Account{account("https://otheraccount.blob.core.windows.net/", storage.Standard)}, nil)
+			},
+			expectedError: "storage account " + uri + " of the boot diagnostics of VM my-vm doesn't exist in subscription 123",
+		},
+		{
+			name:   "premium storage account",
+			vmSpec: Spec{Name: "my-vm", BootDiagnostics: &infrav1.BootDiagnostics{Enabled: true, StorageAccountURI: uri}},
+			expect: func(msa *mock_storageaccounts.MockClientMockRecorder) {
+				msa.List(gomock.Any()).Return([]storage.Account{account(uri, storage.Premium)}, nil)
+			},
+			expectedError: "storage account " + uri + " of the boot diagnostics of VM my-vm is a Premium storage account, boot diagnostics require a Standard storage account",
+		},
+		{
+			name:   "storage accounts can't be listed",
+			vmSpec: Spec{Name: "my-vm", BootDiagnostics: &infrav1.BootDiagnostics{Enabled: true, StorageAccountURI: uri}},
+			expect: func(msa *mock_storageaccounts.MockClientMockRecorder) {
+				msa.List(gomock.Any()).Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 403}, "Forbidden"))
+			},
+			expectedError: "failed to look for the storage account " + uri + " of the boot diagnostics of VM my-vm: #: Forbidden: StatusCode=403",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			storageAccountsMock := mock_storageaccounts.NewMockClient(mockCtrl)
+			tc.expect(storageAccountsMock.EXPECT())
+
+			s := &Service{
+				Scope:                 newTestClusterScope(g),
+				StorageAccountsClient: storageAccountsMock,
+			}
+
+			err := s.ValidateBootDiagnostics(context.TODO(), &tc.vmSpec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileDataDisks(t *testing.T) {
+	attachedDisk := compute.DataDisk{
+		Lun:          to.Int32Ptr(0),
+		Name:         to.StringPtr("my-vm_etcddisk"),
+		CreateOption: compute.DiskCreateOptionTypesEmpty,
+		ManagedDisk:  &compute.ManagedDiskParameters{ID: to.StringPtr("my-vm_etcddisk-id")},
+	}
+	vmWithDisks := func(disks ...compute.DataDisk) compute.VirtualMachine {
+		return compute.VirtualMachine{
+			VirtualMachineProperties: &compute.VirtualMachineProperties{
+				StorageProfile: &compute.StorageProfile{
+					DataDisks: &disks,
+				},
+			},
+		}
+	}
+	dataDisks := []infrav1.DataDisk{
+		{NameSuffix: "etcddisk", DiskSizeGB: 256, Lun: to.Int32Ptr(0)},
+		{NameSuffix: "mydisk", DiskSizeGB: 128, Lun: to.Int32Ptr(1), ManagedDisk: &infrav1.ManagedDisk{StorageAccountType: "Premium_LRS"}, CachingType: "ReadOnly"},
+	}
+
+	testcases := []struct {
+		name          string
+		dataDisks     []infrav1.DataDisk
+		expect        func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:      "all data disks attached",
+			dataDisks: dataDisks[:1],
+			expect: func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-rg", "my-vm").Return(vmWithDisks(attachedDisk), nil)
+			},
+		},
+		{
+			name:      "attach a data disk added to the spec",
+			dataDisks: dataDisks,
+			expect: func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-rg", "my-vm").Return(vmWithDisks(attachedDisk), nil)
+				m.Update(gomock.Any(), "my-rg", "my-vm", gomock.Any()).Do(func(_, _, _ interface{}, update compute.VirtualMachineUpdate) {
+					g.Expect(*update.StorageProfile.DataDisks).To(Equal([]compute.DataDisk{
+						attachedDisk,
+						{
+							Lun:          to.Int32Ptr(1),
+							Name:         to.StringPtr("my-vm_mydisk"),
+							CreateOption: compute.DiskCreateOptionTypesEmpty,
+							DiskSizeGB:   to.Int32Ptr(128),
+							Caching:      compute.CachingTypesReadOnly,
+							ManagedDisk:  &compute.ManagedDiskParameters{StorageAccountType: compute.StorageAccountTypesPremiumLRS},
+						},
+					}))
+				})
+			},
+		},
+		{
+			name:      "data disks can't be attached",
+			dataDisks: dataDisks,
+			expect: func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-rg", "my-vm").Return(vmWithDisks(attachedDisk), nil)
+				m.Update(gomock.Any(), "my-rg", "my-vm", gomock.Any()).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+			},
+			expectedError: "failed to attach data disks to VM my-vm: #: Conflict: StatusCode=409",
+		},
+		{
+			name:      "VM retrieval fails",
+			dataDisks: dataDisks,
+			expect: func(g *WithT, m *mock_virtualmachines.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-rg", "my-vm").Return(compute.VirtualMachine{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+			expectedError: "failed to get VM my-vm: #: Internal Server Error: StatusCode=500",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			vmMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			tc.expect(g, vmMock.EXPECT())
+
+			s := &Service{
+				Scope:  newTestClusterScope(g),
+				Client: vmMock,
+			}
+
+			err := s.ReconcileDataDisks(context.TODO(), &Spec{Name: "my-vm", DataDisks: tc.dataDisks})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func newTestClusterScope(g *WithT) *scope.ClusterScope {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+	}
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		AzureClients: scope.AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Cluster: cluster,
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				Location:       "westus2",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: subscriptionID,
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						&infrav1.SubnetSpec{Role: infrav1.SubnetControlPlane},
+						&infrav1.SubnetSpec{Role: infrav1.SubnetNode},
+					},
+				},
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	return clusterScope
+}
+
+func TestIsComplexWindowsPassword(t *testing.T) {
+	testcases := []struct {
+		password string
+		expected bool
+	}{
+		{password: "Abcdefghijk1", expected: true},
+		{password: "abcdefghij1!", expected: true},
+		{password: "ABCDEFGHIJ_-", expected: false},
+		{password: "Abc1!", expected: false},
+		{password: strings.Repeat("Ab1!", 31), expected: false},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.password, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isComplexWindowsPassword(tc.password)).To(Equal(tc.expected))
+		})
+	}
+}
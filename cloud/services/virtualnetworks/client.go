@@ -21,13 +21,16 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 )
 
 // Client wraps go-sdk
 type Client interface {
 	Get(context.Context, string, string) (network.VirtualNetwork, error)
-	CreateOrUpdate(context.Context, string, string, network.VirtualNetwork) error
+	CreateOrUpdateAsync(context.Context, string, string, network.VirtualNetwork) (azureautorest.Future, error)
+	IsDone(context.Context, azureautorest.Future) (bool, error)
+	UpdateTags(context.Context, string, string, map[string]*string) error
 	Delete(context.Context, string, string) error
 	CheckIPAddressAvailability(context.Context, string, string, string) (network.IPAddressAvailabilityResult, error)
 }
@@ -48,8 +51,7 @@ func NewClient(auth azure.Authorizer) *AzureClient {
 // newVirtualNetworksClient creates a new vnet client from subscription ID.
 func newVirtualNetworksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.VirtualNetworksClient {
 	vnetsClient := network.NewVirtualNetworksClientWithBaseURI(baseURI, subscriptionID)
-	vnetsClient.Authorizer = authorizer
-	vnetsClient.AddToUserAgent(azure.UserAgent())
+	azure.SetAutoRestClientDefaults(&vnetsClient.Client, authorizer)
 	return vnetsClient
 }
 
@@ -58,9 +60,24 @@ func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vnetName stri
 	return ac.virtualnetworks.Get(ctx, resourceGroupName, vnetName, "")
 }
 
-// CreateOrUpdate creates or updates a virtual network in the specified resource group.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, vnetName string, vn network.VirtualNetwork) error {
+// CreateOrUpdateAsync starts creating or updating a virtual network in the specified resource group, and returns
+// the future of the operation without waiting for it to complete.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vnetName string, vn network.VirtualNetwork) (azureautorest.Future, error) {
 	future, err := ac.virtualnetworks.CreateOrUpdate(ctx, resourceGroupName, vnetName, vn)
+	if err != nil {
+		return azureautorest.Future{}, err
+	}
+	return future.Future, nil
+}
+
+// IsDone polls a long-running virtual network operation once and returns whether it is done.
+func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.Future) (bool, error) {
+	return future.DoneWithContext(ctx, ac.virtualnetworks)
+}
+
+// UpdateTags replaces the tags of a virtual network.
+func (ac *AzureClient) UpdateTags(ctx context.Context, resourceGroupName, vnetName string, tags map[string]*string) error {
+	future, err := ac.virtualnetworks.UpdateTags(ctx, resourceGroupName, vnetName, network.TagsObject{Tags: tags})
 	if err != nil {
 		return err
 	}
@@ -20,6 +20,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 )
 
+const serviceName = "virtualnetworks"
+
 // Service provides operations on azure resources
 type Service struct {
 	Scope *scope.ClusterScope
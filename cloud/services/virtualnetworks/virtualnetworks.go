@@ -18,6 +18,7 @@ package virtualnetworks
 
 import (
 	"context"
+	"net"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -25,6 +26,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/async"
 )
 
 // Spec input specification for Get/CreateOrUpdate/Delete calls
@@ -32,6 +34,8 @@ type Spec struct {
 	ResourceGroup string
 	Name          string
 	CIDR          string
+	IPv6CIDR      string
+	DNSServers    []string
 }
 
 // getExisting provides information about an existing virtual network.
@@ -43,18 +47,29 @@ func (s *Service) getExisting(ctx context.Context, spec *Spec) (*infrav1.VnetSpe
 		}
 		return nil, errors.Wrapf(err, "failed to get VNet %s", spec.Name)
 	}
-	cidr := ""
+	cidr, ipv6CIDR := "", ""
 	if vnet.VirtualNetworkPropertiesFormat != nil && vnet.VirtualNetworkPropertiesFormat.AddressSpace != nil {
-		prefixes := to.StringSlice(vnet.VirtualNetworkPropertiesFormat.AddressSpace.AddressPrefixes)
-		if prefixes != nil && len(prefixes) > 0 {
-			cidr = prefixes[0]
+		for _, prefix := range to.StringSlice(vnet.VirtualNetworkPropertiesFormat.AddressSpace.AddressPrefixes) {
+			if ip, _, err := net.ParseCIDR(prefix); err == nil && ip.To4() == nil {
+				if ipv6CIDR == "" {
+					ipv6CIDR = prefix
+				}
+			} else if cidr == "" {
+				cidr = prefix
+			}
 		}
 	}
+	var dnsServers []string
+	if vnet.VirtualNetworkPropertiesFormat != nil && vnet.VirtualNetworkPropertiesFormat.DhcpOptions != nil {
+		dnsServers = append(dnsServers, to.StringSlice(vnet.VirtualNetworkPropertiesFormat.DhcpOptions.DNSServers)...)
+	}
 	return &infrav1.VnetSpec{
 		ResourceGroup: spec.ResourceGroup,
 		ID:            to.String(vnet.ID),
 		Name:          to.String(vnet.Name),
 		CidrBlock:     cidr,
+		IPv6CidrBlock: ipv6CIDR,
+		DNSServers:    dnsServers,
 		Tags:          converters.MapToTags(vnet.Tags),
 	}, nil
 }
@@ -74,6 +89,10 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return errors.New("Invalid VNET Specification")
 	}
 
+	if _, err := async.ProcessOngoingOperation(ctx, s.Scope, s.Client, vnetSpec.Name, serviceName); err != nil {
+		return err
+	}
+
 	existingVnet, err := s.getExisting(ctx, vnetSpec)
 	if !azure.ResourceNotFound(err) {
 		if err != nil {
@@ -82,36 +101,121 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 
 		if !existingVnet.IsManaged(s.Scope.ClusterName()) {
 			s.Scope.V(2).Info("Working on custom VNet", "vnet-id", existingVnet.ID)
+		} else {
+			if err := s.updateTags(ctx, vnetSpec, existingVnet); err != nil {
+				return err
+			}
+			if err := s.updateDNSServers(ctx, vnetSpec, existingVnet); err != nil {
+				return err
+			}
 		}
-		// vnet already exists, cannot update since it's immutable
+		// the address space of an existing vnet is immutable
 		existingVnet.DeepCopyInto(s.Scope.Vnet())
 		return nil
 	}
+	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
+		// a pre-existing vnet was referenced by ID, never create it on behalf of the user
+		return errors.Errorf("vnet %s with ID %s was provided but could not be found in resource group %s", vnetSpec.Name, s.Scope.Vnet().ID, vnetSpec.ResourceGroup)
+	}
 	s.Scope.V(2).Info("creating VNet", "VNet", vnetSpec.Name)
+	addressPrefixes := []string{vnetSpec.CIDR}
+	if vnetSpec.IPv6CIDR != "" {
+		addressPrefixes = append(addressPrefixes, vnetSpec.IPv6CIDR)
+	}
 	vnetProperties := network.VirtualNetwork{
-		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-			ClusterName: s.Scope.ClusterName(),
-			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(vnetSpec.Name),
-			Role:        to.StringPtr(infrav1.CommonRole),
-			Additional:  s.Scope.AdditionalTags(),
-		})),
+		Tags:     converters.TagsToMap(s.tags(vnetSpec)),
 		Location: to.StringPtr(s.Scope.Location()),
 		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
 			AddressSpace: &network.AddressSpace{
-				AddressPrefixes: &[]string{vnetSpec.CIDR},
+				AddressPrefixes: &addressPrefixes,
 			},
+			DhcpOptions: dhcpOptions(vnetSpec),
 		},
 	}
-	err = s.Client.CreateOrUpdate(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnetProperties)
+	future, err := s.Client.CreateOrUpdateAsync(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnetProperties)
 	if err != nil {
 		return err
 	}
+	if err := async.StartOperation(ctx, s.Scope, s.Client, future, infrav1.PutFuture, serviceName, vnetSpec.ResourceGroup, vnetSpec.Name); err != nil {
+		return err
+	}
 
 	s.Scope.V(2).Info("successfully created VNet", "VNet", vnetSpec.Name)
 	return nil
 }
 
+// updateTags applies the tags of the cluster to a virtual network owned by the cluster.
+func (s *Service) updateTags(ctx context.Context, vnetSpec *Spec, existingVnet *infrav1.VnetSpec) error {
+	tags, changed := converters.UpdateTags(converters.TagsToMap(existingVnet.Tags), s.tags(vnetSpec), s.Scope.LastAppliedTags())
+	if !changed {
+		return nil
+	}
+	s.Scope.V(2).Info("updating VNet tags", "VNet", vnetSpec.Name)
+	if err := s.Client.UpdateTags(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, tags); err != nil {
+		return errors.Wrapf(err, "failed to update tags of VNet %s", vnetSpec.Name)
+	}
+	existingVnet.Tags = converters.MapToTags(tags)
+	return nil
+}
+
+// updateDNSServers applies the DNS servers of the spec to a virtual network owned by the cluster, an empty list
+// resetting it to the Azure-provided DNS. The whole virtual network is updated, with its current subnets.
+func (s *Service) updateDNSServers(ctx context.Context, vnetSpec *Spec, existingVnet *infrav1.VnetSpec) error {
+	if equalDNSServers(existingVnet.DNSServers, vnetSpec.DNSServers) {
+		return nil
+	}
+	s.Scope.V(2).Info("updating VNet DNS servers", "VNet", vnetSpec.Name, "DNS servers", vnetSpec.DNSServers)
+	vnet, err := s.Client.Get(ctx, vnetSpec.ResourceGroup, vnetSpec.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get VNet %s", vnetSpec.Name)
+	}
+	if vnet.VirtualNetworkPropertiesFormat == nil {
+		vnet.VirtualNetworkPropertiesFormat = &network.VirtualNetworkPropertiesFormat{}
+	}
+	vnet.VirtualNetworkPropertiesFormat.DhcpOptions = dhcpOptions(vnetSpec)
+	future, err := s.Client.CreateOrUpdateAsync(ctx, vnetSpec.ResourceGroup, vnetSpec.Name, vnet)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update DNS servers of VNet %s", vnetSpec.Name)
+	}
+	if err := async.StartOperation(ctx, s.Scope, s.Client, future, infrav1.PutFuture, serviceName, vnetSpec.ResourceGroup, vnetSpec.Name); err != nil {
+		return err
+	}
+	existingVnet.DNSServers = vnetSpec.DNSServers
+	return nil
+}
+
+// dhcpOptions returns the DHCP options of a virtual network with the DNS servers of the spec, none meaning the
+// Azure-provided DNS.
+func dhcpOptions(vnetSpec *Spec) *network.DhcpOptions {
+	dnsServers := append([]string{}, vnetSpec.DNSServers...)
+	return &network.DhcpOptions{DNSServers: &dnsServers}
+}
+
+// equalDNSServers returns true if two lists hold the same DNS servers in the same order, which is their order of
+// preference.
+func equalDNSServers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// tags returns the tags of a virtual network owned by the cluster.
+func (s *Service) tags(vnetSpec *Spec) infrav1.Tags {
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Scope.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.StringPtr(vnetSpec.Name),
+		Role:        to.StringPtr(infrav1.CommonRole),
+		Additional:  s.Scope.AdditionalTags(),
+	})
+}
+
 // Delete deletes the virtual network with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	if !s.Scope.Vnet().IsManaged(s.Scope.ClusterName()) {
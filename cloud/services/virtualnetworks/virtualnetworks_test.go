@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks/mock_virtualnetworks"
 
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 
@@ -35,6 +36,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -49,10 +51,12 @@ func init() {
 
 func TestReconcileVnet(t *testing.T) {
 	testcases := []struct {
-		name   string
-		input  *infrav1.VnetSpec
-		output *infrav1.VnetSpec
-		expect func(m *mock_virtualnetworks.MockClientMockRecorder)
+		name           string
+		input          *infrav1.VnetSpec
+		additionalTags infrav1.Tags
+		output         *infrav1.VnetSpec
+		expectedError  string
+		expect         func(m *mock_virtualnetworks.MockClientMockRecorder)
 	}{
 		{
 			name:  "managed vnet exists",
@@ -80,6 +84,43 @@ func TestReconcileVnet(t *testing.T) {
 					}, nil)
 			},
 		},
+		{
+			name:           "managed vnet exists with outdated tags",
+			input:          &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-exists"},
+			additionalTags: infrav1.Tags{"env": "prod"},
+			output: &infrav1.VnetSpec{ResourceGroup: "my-rg", ID: "azure/fake/id", Name: "vnet-exists", CidrBlock: "10.0.0.0/8", Tags: infrav1.Tags{
+				"Name":  "vnet-exists",
+				"env":   "prod",
+				"owner": "ops",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			}},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "vnet-exists").
+					Return(network.VirtualNetwork{
+						ID:   to.StringPtr("azure/fake/id"),
+						Name: to.StringPtr("vnet-exists"),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+							},
+						},
+						Tags: map[string]*string{
+							"Name":  to.StringPtr("vnet-exists"),
+							"owner": to.StringPtr("ops"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+						},
+					}, nil)
+				m.UpdateTags(context.TODO(), "my-rg", "vnet-exists", map[string]*string{
+					"Name":  to.StringPtr("vnet-exists"),
+					"env":   to.StringPtr("prod"),
+					"owner": to.StringPtr("ops"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+					"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+				})
+			},
+		},
 		{
 			name:   "managed vnet does not exist",
 			input:  &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-new", CidrBlock: "10.0.0.0/8"},
@@ -88,7 +129,144 @@ func TestReconcileVnet(t *testing.T) {
 				m.Get(context.TODO(), "my-rg", "vnet-new").
 					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 
-				m.CreateOrUpdate(context.TODO(), "my-rg", "vnet-new", gomock.AssignableToTypeOf(network.VirtualNetwork{}))
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "vnet-new", gomock.AssignableToTypeOf(network.VirtualNetwork{}))
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+			},
+		},
+		{
+			name:   "managed vnet does not exist with DNS servers",
+			input:  &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-new", CidrBlock: "10.0.0.0/8", DNSServers: []string{"10.0.0.4", "10.0.0.5"}},
+			output: &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-new", CidrBlock: "10.0.0.0/8", DNSServers: []string{"10.0.0.4", "10.0.0.5"}},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "vnet-new").
+					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "vnet-new", network.VirtualNetwork{
+					Tags: map[string]*string{
+						"Name": to.StringPtr("vnet-new"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+					},
+					Location: to.StringPtr("test-location"),
+					VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+						AddressSpace: &network.AddressSpace{
+							AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+						},
+						DhcpOptions: &network.DhcpOptions{DNSServers: to.StringSlicePtr([]string{"10.0.0.4", "10.0.0.5"})},
+					},
+				})
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+			},
+		},
+		{
+			name:  "managed vnet exists with the DNS servers",
+			input: &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-exists", DNSServers: []string{"10.0.0.4"}},
+			output: &infrav1.VnetSpec{ResourceGroup: "my-rg", ID: "azure/fake/id", Name: "vnet-exists", CidrBlock: "10.0.0.0/8", DNSServers: []string{"10.0.0.4"}, Tags: infrav1.Tags{
+				"Name": "vnet-exists",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			}},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "vnet-exists").
+					Return(network.VirtualNetwork{
+						ID:   to.StringPtr("azure/fake/id"),
+						Name: to.StringPtr("vnet-exists"),
+						VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+							AddressSpace: &network.AddressSpace{
+								AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+							},
+							DhcpOptions: &network.DhcpOptions{DNSServers: to.StringSlicePtr([]string{"10.0.0.4"})},
+						},
+						Tags: map[string]*string{
+							"Name": to.StringPtr("vnet-exists"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+						},
+					}, nil)
+			},
+		},
+		{
+			name:  "managed vnet exists with other DNS servers",
+			input: &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-exists", DNSServers: []string{"10.0.0.5", "10.0.0.4"}},
+			output: &infrav1.VnetSpec{ResourceGroup: "my-rg", ID: "azure/fake/id", Name: "vnet-exists", CidrBlock: "10.0.0.0/8", DNSServers: []string{"10.0.0.5", "10.0.0.4"}, Tags: infrav1.Tags{
+				"Name": "vnet-exists",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			}},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				existing := network.VirtualNetwork{
+					ID:   to.StringPtr("azure/fake/id"),
+					Name: to.StringPtr("vnet-exists"),
+					VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+						AddressSpace: &network.AddressSpace{
+							AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+						},
+						DhcpOptions: &network.DhcpOptions{DNSServers: to.StringSlicePtr([]string{"10.0.0.4", "10.0.0.5"})},
+						Subnets:     &[]network.Subnet{{Name: to.StringPtr("my-subnet-node")}},
+					},
+					Tags: map[string]*string{
+						"Name": to.StringPtr("vnet-exists"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+					},
+				}
+				m.Get(context.TODO(), "my-rg", "vnet-exists").Return(existing, nil).Times(2)
+				updated := existing
+				updated.VirtualNetworkPropertiesFormat = &network.VirtualNetworkPropertiesFormat{
+					AddressSpace: existing.AddressSpace,
+					DhcpOptions:  &network.DhcpOptions{DNSServers: to.StringSlicePtr([]string{"10.0.0.5", "10.0.0.4"})},
+					Subnets:      existing.Subnets,
+				}
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "vnet-exists", updated)
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+			},
+		},
+		{
+			name:  "managed vnet resets to the Azure-provided DNS",
+			input: &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-exists"},
+			output: &infrav1.VnetSpec{ResourceGroup: "my-rg", ID: "azure/fake/id", Name: "vnet-exists", CidrBlock: "10.0.0.0/8", Tags: infrav1.Tags{
+				"Name": "vnet-exists",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
+			}},
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				existing := network.VirtualNetwork{
+					ID:   to.StringPtr("azure/fake/id"),
+					Name: to.StringPtr("vnet-exists"),
+					VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+						AddressSpace: &network.AddressSpace{
+							AddressPrefixes: to.StringSlicePtr([]string{"10.0.0.0/8"}),
+						},
+						DhcpOptions: &network.DhcpOptions{DNSServers: to.StringSlicePtr([]string{"10.0.0.4"})},
+					},
+					Tags: map[string]*string{
+						"Name": to.StringPtr("vnet-exists"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_role":                 to.StringPtr("common"),
+					},
+				}
+				m.Get(context.TODO(), "my-rg", "vnet-exists").Return(existing, nil).Times(2)
+				updated := existing
+				updated.VirtualNetworkPropertiesFormat = &network.VirtualNetworkPropertiesFormat{
+					AddressSpace: existing.AddressSpace,
+					DhcpOptions:  &network.DhcpOptions{DNSServers: &[]string{}},
+				}
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "vnet-exists", updated)
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+			},
+		},
+		{
+			name:          "managed vnet creation is in progress",
+			input:         &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-new", CidrBlock: "10.0.0.0/8"},
+			output:        &infrav1.VnetSpec{ResourceGroup: "my-rg", Name: "vnet-new", CidrBlock: "10.0.0.0/8"},
+			expectedError: "operation type PUT on Azure resource my-rg/vnet-new is not done, state InProgress",
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "my-rg", "vnet-new").
+					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+
+				future, _ := test.NewPutFuture("https://management.azure.com/vnet-new", "https://management.azure.com/operations/my-op")
+				m.CreateOrUpdateAsync(context.TODO(), "my-rg", "vnet-new", gomock.AssignableToTypeOf(network.VirtualNetwork{})).Return(future, nil)
+				m.IsDone(context.TODO(), future).Return(false, nil)
 			},
 		},
 		{
@@ -119,7 +297,18 @@ func TestReconcileVnet(t *testing.T) {
 				m.Get(context.TODO(), "custom-vnet-rg", "custom-vnet").
 					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 
-				m.CreateOrUpdate(context.TODO(), "custom-vnet-rg", "custom-vnet", gomock.AssignableToTypeOf(network.VirtualNetwork{}))
+				m.CreateOrUpdateAsync(context.TODO(), "custom-vnet-rg", "custom-vnet", gomock.AssignableToTypeOf(network.VirtualNetwork{}))
+				m.IsDone(context.TODO(), gomock.AssignableToTypeOf(azureautorest.Future{})).Return(true, nil)
+			},
+		},
+		{
+			name:          "unmanaged vnet with ID not found",
+			input:         &infrav1.VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet", ID: "azure/custom-vnet/id"},
+			output:        &infrav1.VnetSpec{ResourceGroup: "custom-vnet-rg", Name: "custom-vnet", ID: "azure/custom-vnet/id"},
+			expectedError: "vnet custom-vnet with ID azure/custom-vnet/id was provided but could not be found in resource group custom-vnet-rg",
+			expect: func(m *mock_virtualnetworks.MockClientMockRecorder) {
+				m.Get(context.TODO(), "custom-vnet-rg", "custom-vnet").
+					Return(network.VirtualNetwork{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 			},
 		},
 	}
@@ -150,11 +339,16 @@ func TestReconcileVnet(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet: *tc.input,
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
 						},
+						AdditionalTags: tc.additionalTags,
 					},
 				},
 			})
@@ -169,10 +363,16 @@ func TestReconcileVnet(t *testing.T) {
 				Name:          clusterScope.Vnet().Name,
 				ResourceGroup: clusterScope.Vnet().ResourceGroup,
 				CIDR:          clusterScope.Vnet().CidrBlock,
+				DNSServers:    clusterScope.Vnet().DNSServers,
 			}
 
 			err = s.Reconcile(context.TODO(), vnetSpec)
-			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
 			g.Expect(clusterScope.Vnet()).To(Equal(tc.output))
 
 			if !reflect.DeepEqual(clusterScope.Vnet(), tc.output) {
@@ -244,10 +444,14 @@ func TestDeleteVnet(t *testing.T) {
 				Cluster: cluster,
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						Location: "test-location",
+						Location:       "test-location",
 						SubscriptionID: subscriptionID,
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet: *tc.input,
+							Subnets: infrav1.Subnets{
+								{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+								{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+							},
 						},
 					},
 				},
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vnetpeerings
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Client wraps go-sdk
+type Client interface {
+	CreateOrUpdate(context.Context, string, string, string, string, network.VirtualNetworkPeering) error
+	Delete(context.Context, string, string, string, string) error
+}
+
+// AzureClient contains the Azure go-sdk Client
+type AzureClient struct {
+	auth azure.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new virtual network peerings client.
+// Peerings are created on both ends, so the go-sdk client is created for the subscription of each call.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		auth: auth,
+	}
+}
+
+// newVirtualNetworkPeeringsClient creates a new virtual network peerings client from subscription ID.
+func newVirtualNetworkPeeringsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.VirtualNetworkPeeringsClient {
+	peeringsClient := network.NewVirtualNetworkPeeringsClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&peeringsClient.Client, authorizer)
+	return peeringsClient
+}
+
+// CreateOrUpdate creates or updates a peering of the specified virtual network.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, subscriptionID, resourceGroupName, vnetName, peeringName string, peering network.VirtualNetworkPeering) error {
+	peerings := newVirtualNetworkPeeringsClient(subscriptionID, ac.auth.BaseURI(), ac.auth.Authorizer())
+	future, err := peerings.CreateOrUpdate(ctx, resourceGroupName, vnetName, peeringName, peering)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, peerings.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(peerings)
+	return err
+}
+
+// Delete deletes a peering of the specified virtual network.
+func (ac *AzureClient) Delete(ctx context.Context, subscriptionID, resourceGroupName, vnetName, peeringName string) error {
+	peerings := newVirtualNetworkPeeringsClient(subscriptionID, ac.auth.BaseURI(), ac.auth.Authorizer())
+	future, err := peerings.Delete(ctx, resourceGroupName, vnetName, peeringName)
+	if err != nil {
+		return err
+	}
+	err = future.WaitForCompletionRef(ctx, peerings.Client)
+	if err != nil {
+		return err
+	}
+	_, err = future.Result(peerings)
+	return err
+}
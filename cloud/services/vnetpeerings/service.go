@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vnetpeerings
+
+import (
+	"github.com/go-logr/logr"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// VnetPeeringScope defines the scope interface for a virtual network peering service.
+type VnetPeeringScope interface {
+	logr.Logger
+	azure.ClusterDescriber
+	VnetPeeringSpecs() []azure.VnetPeeringSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope VnetPeeringScope
+	Client
+}
+
+// NewService creates a new service.
+func NewService(scope VnetPeeringScope) *Service {
+	return &Service{
+		Scope:  scope,
+		Client: NewClient(scope),
+	}
+}
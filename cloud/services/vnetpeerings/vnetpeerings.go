@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vnetpeerings
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+)
+
+// Reconcile creates or updates both directions of the virtual network peerings.
+func (s *Service) Reconcile(ctx context.Context) error {
+	for _, peering := range s.Scope.VnetPeeringSpecs() {
+		s.Scope.V(2).Info("creating vnet peering", "vnet peering", peering.Name, "vnet", peering.VnetName)
+		err := s.Client.CreateOrUpdate(ctx, peering.SubscriptionID, peering.ResourceGroup, peering.VnetName, peering.Name,
+			network.VirtualNetworkPeering{
+				VirtualNetworkPeeringPropertiesFormat: &network.VirtualNetworkPeeringPropertiesFormat{
+					AllowVirtualNetworkAccess: to.BoolPtr(true),
+					AllowForwardedTraffic:     to.BoolPtr(peering.AllowForwardedTraffic),
+					AllowGatewayTransit:       to.BoolPtr(peering.AllowGatewayTransit),
+					UseRemoteGateways:         to.BoolPtr(peering.UseRemoteGateways),
+					RemoteVirtualNetwork: &network.SubResource{
+						ID: to.StringPtr(peering.RemoteVnetID),
+					},
+				},
+			})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create vnet peering %s of vnet %s in resource group %s", peering.Name, peering.VnetName, peering.ResourceGroup)
+		}
+		s.Scope.V(2).Info("successfully created vnet peering", "vnet peering", peering.Name, "vnet", peering.VnetName)
+	}
+	return nil
+}
+
+// Delete deletes both directions of the virtual network peerings.
+func (s *Service) Delete(ctx context.Context) error {
+	for _, peering := range s.Scope.VnetPeeringSpecs() {
+		s.Scope.V(2).Info("deleting vnet peering", "vnet peering", peering.Name, "vnet", peering.VnetName)
+		err := s.Client.Delete(ctx, peering.SubscriptionID, peering.ResourceGroup, peering.VnetName, peering.Name)
+		if err != nil && azure.ResourceNotFound(err) {
+			// already deleted
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete vnet peering %s of vnet %s in resource group %s", peering.Name, peering.VnetName, peering.ResourceGroup)
+		}
+		s.Scope.V(2).Info("successfully deleted vnet peering", "vnet peering", peering.Name, "vnet", peering.VnetName)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vnetpeerings
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/klogr"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/vnetpeerings/mock_vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers"
+)
+
+var peeringSpecs = []azure.VnetPeeringSpec{
+	{
+		Name:                  "hub-vnet-to-my-vnet",
+		SubscriptionID:        "456",
+		ResourceGroup:         "hub-rg",
+		VnetName:              "hub-vnet",
+		RemoteVnetID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+		AllowForwardedTraffic: true,
+		AllowGatewayTransit:   true,
+	},
+	{
+		Name:                  "my-vnet-to-hub-vnet",
+		SubscriptionID:        "123",
+		ResourceGroup:         "my-rg",
+		VnetName:              "my-vnet",
+		RemoteVnetID:          "/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworks/hub-vnet",
+		AllowForwardedTraffic: true,
+		UseRemoteGateways:     true,
+	},
+}
+
+func TestReconcileVnetPeerings(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder)
+	}{
+		{
+			name:          "both directions of the peering are created",
+			expectedError: "",
+			expect: func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VnetPeeringSpecs().Return(peeringSpecs)
+				gomock.InOrder(
+					m.CreateOrUpdate(context.TODO(), "456", "hub-rg", "hub-vnet", "hub-vnet-to-my-vnet", matchers.DiffEq(network.VirtualNetworkPeering{
+						VirtualNetworkPeeringPropertiesFormat: &network.VirtualNetworkPeeringPropertiesFormat{
+							AllowVirtualNetworkAccess: to.BoolPtr(true),
+							AllowForwardedTraffic:     to.BoolPtr(true),
+							AllowGatewayTransit:       to.BoolPtr(true),
+							UseRemoteGateways:         to.BoolPtr(false),
+							RemoteVirtualNetwork: &network.SubResource{
+								ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+							},
+						},
+					})),
+					m.CreateOrUpdate(context.TODO(), "123", "my-rg", "my-vnet", "my-vnet-to-hub-vnet", matchers.DiffEq(network.VirtualNetworkPeering{
+						VirtualNetworkPeeringPropertiesFormat: &network.VirtualNetworkPeeringPropertiesFormat{
+							AllowVirtualNetworkAccess: to.BoolPtr(true),
+							AllowForwardedTraffic:     to.BoolPtr(true),
+							AllowGatewayTransit:       to.BoolPtr(false),
+							UseRemoteGateways:         to.BoolPtr(true),
+							RemoteVirtualNetwork: &network.SubResource{
+								ID: to.StringPtr("/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/virtualNetworks/hub-vnet"),
+							},
+						},
+					})),
+				)
+			},
+		},
+		{
+			name:          "no peerings",
+			expectedError: "",
+			expect: func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder) {
+				s.VnetPeeringSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "fail to create a peering",
+			expectedError: "failed to create vnet peering hub-vnet-to-my-vnet of vnet hub-vnet in resource group hub-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VnetPeeringSpecs().Return(peeringSpecs)
+				m.CreateOrUpdate(context.TODO(), "456", "hub-rg", "hub-vnet", "hub-vnet-to-my-vnet", gomock.AssignableToTypeOf(network.VirtualNetworkPeering{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_vnetpeerings.NewMockVnetPeeringScope(mockCtrl)
+			clientMock := mock_vnetpeerings.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteVnetPeerings(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder)
+	}{
+		{
+			name:          "both directions of the peering are deleted",
+			expectedError: "",
+			expect: func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VnetPeeringSpecs().Return(peeringSpecs)
+				m.Delete(context.TODO(), "456", "hub-rg", "hub-vnet", "hub-vnet-to-my-vnet")
+				m.Delete(context.TODO(), "123", "my-rg", "my-vnet", "my-vnet-to-hub-vnet")
+			},
+		},
+		{
+			name:          "peering already deleted",
+			expectedError: "",
+			expect: func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VnetPeeringSpecs().Return(peeringSpecs)
+				m.Delete(context.TODO(), "456", "hub-rg", "hub-vnet", "hub-vnet-to-my-vnet").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Delete(context.TODO(), "123", "my-rg", "my-vnet", "my-vnet-to-hub-vnet")
+			},
+		},
+		{
+			name:          "fail to delete a peering",
+			expectedError: "failed to delete vnet peering my-vnet-to-hub-vnet of vnet my-vnet in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_vnetpeerings.MockVnetPeeringScopeMockRecorder, m *mock_vnetpeerings.MockClientMockRecorder) {
+				s.V(gomock.AssignableToTypeOf(2)).AnyTimes().Return(klogr.New())
+				s.VnetPeeringSpecs().Return(peeringSpecs)
+				m.Delete(context.TODO(), "456", "hub-rg", "hub-vnet", "hub-vnet-to-my-vnet")
+				m.Delete(context.TODO(), "123", "my-rg", "my-vnet", "my-vnet-to-hub-vnet").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_vnetpeerings.NewMockVnetPeeringScope(mockCtrl)
+			clientMock := mock_vnetpeerings.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -16,10 +16,30 @@ limitations under the License.
 
 package azure
 
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
 // PublicIPSpec defines the specification for a public IP.
 type PublicIPSpec struct {
 	Name    string
 	DNSName string
+	// DNSLabel is the DNS label of the public IP, which defaults to its name in lowercase.
+	DNSLabel string
+	SKU      infrav1.SKU
+	IsIPv6   bool
+	// PublicIPPrefixName is the name of the public IP prefix to allocate the IP from, if any.
+	PublicIPPrefixName string
+	Zones              []string
+	// AdditionalTags are tags of the public IP merged on top of the additional tags of the cluster.
+	AdditionalTags infrav1.Tags
+}
+
+// PublicIPPrefixSpec defines the specification for a public IP prefix.
+type PublicIPPrefixSpec struct {
+	Name         string
+	PrefixLength int32
+	Zones        []string
 }
 
 // NICSpec defines the specification for a network interface.
@@ -33,9 +53,25 @@ type NICSpec struct {
 	StaticIPAddress          string
 	PublicLoadBalancerName   string
 	InternalLoadBalancerName string
-	PublicIPName             string
-	VMSize                   string
-	AcceleratedNetworking    *bool
+	// InternalInboundNatRuleNames are the names of the inbound NAT rules of the machine on the internal LB.
+	InternalInboundNatRuleNames []string
+	PublicIPName                string
+	VMSize                      string
+	AcceleratedNetworking       *bool
+	IPv6Enabled                 bool
+	// ApplicationGatewayName is the name of the application gateway with the machine in its backend pool.
+	ApplicationGatewayName string
+}
+
+// InboundNatSpec defines the specification for an inbound NAT rule giving SSH access to a machine, or of an inbound
+// NAT rule of the internal API server LB when it has a BackendPort.
+type InboundNatSpec struct {
+	Name             string
+	LoadBalancerName string
+	// FrontendPortRangeStart and FrontendPortRangeEnd are the frontend ports the rule gets the first free port of.
+	FrontendPortRangeStart int32
+	FrontendPortRangeEnd   int32
+	BackendPort            int32
 }
 
 // DiskSpec defines the specification for a Disk.
@@ -43,13 +79,141 @@ type DiskSpec struct {
 	Name string
 }
 
+// NatGatewaySpec defines the specification for a NAT gateway.
+type NatGatewaySpec struct {
+	Name         string
+	SubnetName   string
+	PublicIPName string
+}
+
+// PrivateDNSSpec defines the specification for a private DNS zone and the API server record in it.
+type PrivateDNSSpec struct {
+	ZoneName          string
+	LinkName          string
+	VNetName          string
+	VNetResourceGroup string
+	RecordName        string
+	RecordIP          string
+}
+
+// VnetPeeringSpec defines the specification for one direction of a virtual network peering.
+type VnetPeeringSpec struct {
+	Name                  string
+	SubscriptionID        string
+	ResourceGroup         string
+	VnetName              string
+	RemoteVnetID          string
+	AllowForwardedTraffic bool
+	AllowGatewayTransit   bool
+	UseRemoteGateways     bool
+}
+
+// PrivateEndpointSpec defines the specification for a private endpoint and its private DNS zone group.
+type PrivateEndpointSpec struct {
+	Name                 string
+	SubnetName           string
+	VnetName             string
+	PrivateLinkServiceID string
+	GroupIDs             []string
+	PrivateDNSZoneIDs    []string
+}
+
+// PrivateLinkServiceSpec defines the specification for a private link service on the frontend of a load balancer.
+type PrivateLinkServiceSpec struct {
+	Name                      string
+	SubnetName                string
+	VnetName                  string
+	LoadBalancerName          string
+	VisibilitySubscriptions   []string
+	AutoApprovalSubscriptions []string
+}
+
+// BastionSpec defines the specification for an Azure Bastion host.
+type BastionSpec struct {
+	Name         string
+	SubnetName   string
+	SubnetCIDR   string
+	PublicIPName string
+	VNetName     string
+}
+
+// ApplicationGatewaySpec defines the specification for an application gateway fronting the API server.
+type ApplicationGatewaySpec struct {
+	Name         string
+	SubnetName   string
+	SubnetCIDR   string
+	VNetName     string
+	PublicIPName string
+	Capacity     int32
+	FrontendPort int32
+	// BackendPort and HostName are the port and host name of the API server, the host name matching its certificate.
+	BackendPort            int32
+	HostName               string
+	SSLCertificateSecretID string
+	IdentityID             string
+	FirewallPolicyID       string
+}
+
+// ProximityPlacementGroupSpec defines the specification for a proximity placement group.
+type ProximityPlacementGroupSpec struct {
+	Name          string
+	ID            string
+	FailureDomain string
+}
+
+// AvailabilitySetSpec defines the specification for an availability set.
+type AvailabilitySetSpec struct {
+	Name string
+	ID   string
+	Role string
+}
+
+// ScaleSetSpec defines the specification for a virtual machine scale set.
+type ScaleSetSpec struct {
+	Name                   string
+	Size                   string
+	Capacity               int64
+	Zones                  []string
+	SubnetID               string
+	PublicLoadBalancerName string
+	AcceleratedNetworking  *bool
+	SpotVMOptions          *infrav1.SpotVMOptions
+}
+
 // LBSpec defines the specification for a load balancer.
 type LBSpec struct {
-	Name             string
-	PublicIPName     string
-	Role             string
-	SubnetName       string
-	SubnetCidr       string
-	PrivateIPAddress string
-	APIServerPort    int32
+	Name         string
+	PublicIPName string
+	// PublicIPResourceGroup is the resource group of the public IP, the network resource group of the cluster when empty.
+	PublicIPResourceGroup string
+	IPv6PublicIPName      string
+	Role                  string
+	SubnetName            string
+	SubnetCidr            string
+	PrivateIPAddress      string
+	APIServerPort         int32
+	SKU                   infrav1.SKU
+	Probe                 ProbeSpec
+	// Zones are the availability zones of the frontend of the internal API server LB, which is not zonal when empty.
+	Zones []string
+	// AdditionalPublicIPNames are the public IPs of the additional frontends of the API server LB,
+	// or of the node outbound LB.
+	AdditionalPublicIPNames []string
+	// AllocatedOutboundPorts and IdleTimeoutInMinutes configure the outbound rule of the node outbound LB,
+	// zero values keep the Azure defaults.
+	AllocatedOutboundPorts int32
+	IdleTimeoutInMinutes   int32
+	// LoadDistribution is the load distribution of the API server load balancing rules, Default when empty.
+	LoadDistribution infrav1.LoadDistribution
+	// InboundNatRules are the inbound NAT rules of the internal API server LB, created with the control plane machines.
+	InboundNatRules []infrav1.InboundNatRule
+}
+
+// ProbeSpec defines the specification for the health probe of an API server load balancer.
+// Unset fields take the default value of the probe.
+type ProbeSpec struct {
+	Protocol           infrav1.ProbeProtocol
+	RequestPath        string
+	IntervalInSeconds  int32
+	UnhealthyThreshold int32
 }
@@ -18,11 +18,14 @@ package controllers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/util"
@@ -38,7 +41,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 )
 
@@ -56,6 +61,7 @@ func (r *AzureClusterReconciler) SetupWithManager(mgr ctrl.Manager, options cont
 		WithOptions(options).
 		For(azCluster).
 		WithEventFilter(predicates.ResourceNotPaused(r.Log)). // don't queue reconcile if resource is paused
+		WithEventFilter(skipPeriodicResync()).                // requeued at their resync period instead
 		Build(r)
 	if err != nil {
 		return errors.Wrapf(err, "error creating controller")
@@ -78,7 +84,10 @@ func (r *AzureClusterReconciler) SetupWithManager(mgr ctrl.Manager, options cont
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusteridentities,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates;azuremachinetemplates/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,verbs=get;list;watch
 
 func (r *AzureClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
 	ctx, cancel := context.WithTimeout(context.Background(), reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
@@ -130,6 +139,11 @@ func (r *AzureClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, ret
 		conditions.SetSummary(azureCluster,
 			conditions.WithConditions(
 				infrav1.NetworkInfrastructureReadyCondition,
+				infrav1.VNetReadyCondition,
+				infrav1.SubnetsReadyCondition,
+				infrav1.SecurityGroupsReadyCondition,
+				infrav1.PublicIPsReadyCondition,
+				infrav1.LoadBalancersReadyCondition,
 			),
 			conditions.WithStepCounterIfOnly(
 				infrav1.NetworkInfrastructureReadyCondition,
@@ -161,11 +175,31 @@ func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterSco
 		return reconcile.Result{}, err
 	}
 
+	if feature.Gates.Enabled(feature.VCPUQuotaCheck) {
+		r.checkVCPUQuota(ctx, clusterScope)
+	}
+
+	if azureCluster.Annotations[infrav1.DryRunAnnotation] == "true" {
+		return r.reconcileDryRun(ctx, clusterScope)
+	}
+	conditions.Delete(azureCluster, infrav1.DryRunCondition)
+
 	err := newAzureClusterReconciler(clusterScope).Reconcile(ctx)
 	if err != nil {
+		if azure.IsOperationNotDoneError(err) {
+			// the progress of the operation is polled again after the requeue, without failing the reconcile
+			clusterScope.V(2).Info("Waiting for a long-running operation to complete", "reason", err.Error())
+			r.Recorder.Event(azureCluster, corev1.EventTypeNormal, infrav1.OperationInProgressReason, err.Error())
+			conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.OperationInProgressReason, clusterv1.ConditionSeverityInfo, err.Error())
+			return reconcile.Result{RequeueAfter: 15 * time.Second}, nil
+		}
 		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile cluster services")
 	}
 
+	if err := r.reconcileCloudProviderConfig(ctx, clusterScope); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile cloud provider configuration")
+	}
+
 	if azureCluster.Status.Network.APIServerIP.DNSName == "" {
 		clusterScope.Info("Waiting for Load Balancer to exist")
 		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.LoadBalancerProvisioningReason, clusterv1.ConditionSeverityWarning, err.Error())
@@ -173,16 +207,96 @@ func (r *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterSco
 	}
 
 	// Set APIEndpoints so the Cluster API Cluster Controller can pull them
-	azureCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
-		Host: azureCluster.Status.Network.APIServerIP.DNSName,
-		Port: clusterScope.APIServerPort(),
-	}
+	azureCluster.Spec.ControlPlaneEndpoint = clusterScope.ControlPlaneEndpoint()
 
 	// No errors, so mark us ready so the Cluster API Cluster Controller can pull it
 	azureCluster.Status.Ready = true
 	conditions.MarkTrue(azureCluster, infrav1.NetworkInfrastructureReadyCondition)
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: resyncPeriod(azureCluster)}, nil
+}
+
+// reconcileCloudProviderConfig renders the azure.json configuration of the nodes into the cloud provider config secret
+// of the cluster, when the AzureCluster asks for it. The secret is owned by the AzureCluster, and deleted with it.
+func (r *AzureClusterReconciler) reconcileCloudProviderConfig(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	if clusterScope.AzureCluster.Spec.CloudProviderConfig == nil {
+		return nil
+	}
+	config, err := clusterScope.CloudProviderConfig(ctx)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterScope.CloudProviderConfigSecretName(),
+			Namespace: clusterScope.Namespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.OwnerReferences = util.EnsureOwnerRef(secret.OwnerReferences, metav1.OwnerReference{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "AzureCluster",
+			Name:       clusterScope.AzureCluster.Name,
+			UID:        clusterScope.AzureCluster.UID,
+		})
+		secret.Data = map[string][]byte{
+			scope.CloudProviderConfigKey: []byte(config),
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create or update secret %s", secret.Name)
+	}
+	return nil
+}
+
+// reconcileDryRun plans the changes the reconcile of the cluster would make to its Azure resources, and reports them
+// as events and in the DryRun condition of the AzureCluster, without making them.
+func (r *AzureClusterReconciler) reconcileDryRun(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	clusterScope.Info("Planning the changes of the dry run of the AzureCluster")
+	azureCluster := clusterScope.AzureCluster
+
+	changes, err := newAzureClusterPlanner(clusterScope).Plan(ctx)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to plan the changes of the dry run")
+	}
+
+	if len(changes) == 0 {
+		conditions.MarkTrue(azureCluster, infrav1.DryRunCondition)
+		return reconcile.Result{RequeueAfter: resyncPeriod(azureCluster)}, nil
+	}
+	messages := make([]string, 0, len(changes))
+	for _, change := range changes {
+		clusterScope.Info("Planned change", "change", change.String())
+		r.Recorder.Event(azureCluster, corev1.EventTypeNormal, infrav1.ChangesPlannedReason, change.String())
+		messages = append(messages, change.String())
+	}
+	conditions.MarkFalse(azureCluster, infrav1.DryRunCondition, infrav1.ChangesPlannedReason, clusterv1.ConditionSeverityInfo, strings.Join(messages, "; "))
+	return reconcile.Result{RequeueAfter: resyncPeriod(azureCluster)}, nil
+}
+
+// checkVCPUQuota sets the VCPUQuotaAvailable condition of the AzureCluster from the vCPUs needed by its
+// AzureMachines which are not provisioned yet. It doesn't fail the reconcile, as the check is best effort.
+func (r *AzureClusterReconciler) checkVCPUQuota(ctx context.Context, clusterScope *scope.ClusterScope) {
+	azureMachines := &infrav1.AzureMachineList{}
+	if err := r.Client.List(ctx, azureMachines, client.InNamespace(clusterScope.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterLabelName: clusterScope.ClusterName()}); err != nil {
+		clusterScope.Info("Skipping vCPU quota check, failed to list AzureMachines", "error", err.Error())
+		return
+	}
+
+	vmSizes := map[string]int32{}
+	for _, azureMachine := range azureMachines.Items {
+		if azureMachine.Spec.ProviderID == nil && azureMachine.DeletionTimestamp.IsZero() {
+			vmSizes[azureMachine.Spec.VMSize]++
+		}
+	}
+
+	if err := clusterScope.CheckVCPUQuota(ctx, vmSizes); err != nil {
+		clusterScope.SetConditionFalse(infrav1.VCPUQuotaAvailableCondition, infrav1.InsufficientVCPUQuotaReason, err)
+		r.Recorder.Event(clusterScope.AzureCluster, corev1.EventTypeWarning, infrav1.InsufficientVCPUQuotaReason, err.Error())
+		return
+	}
+	clusterScope.SetConditionTrue(infrav1.VCPUQuotaAvailableCondition)
 }
 
 func (r *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/pkg/errors"
+)
+
+// clusterResource is a kind of Azure resource of a cluster.
+type clusterResource string
+
+const (
+	resourceGroupResource           clusterResource = "resource group"
+	proximityPlacementGroupResource clusterResource = "proximity placement group"
+	availabilitySetsResource        clusterResource = "availability sets"
+	virtualNetworkResource          clusterResource = "virtual network"
+	vnetPeeringsResource            clusterResource = "virtual network peerings"
+	securityGroupsResource          clusterResource = "network security groups"
+	routeTablesResource             clusterResource = "route tables"
+	subnetsResource                 clusterResource = "subnets"
+	publicIPPrefixesResource        clusterResource = "public IP prefixes"
+	publicIPsResource               clusterResource = "public IPs"
+	natGatewaysResource             clusterResource = "NAT gateways"
+	bastionHostResource             clusterResource = "bastion host"
+	applicationGatewayResource      clusterResource = "application gateway"
+	privateEndpointsResource        clusterResource = "private endpoints"
+	privateLinkServicesResource     clusterResource = "private link services"
+	loadBalancersResource           clusterResource = "load balancers"
+	privateDNSResource              clusterResource = "private DNS zone"
+)
+
+// clusterResources lists the kinds of resources of a cluster. Among the kinds which can be deleted at the same point,
+// the first listed is deleted first.
+var clusterResources = []clusterResource{
+	bastionHostResource,
+	applicationGatewayResource,
+	privateEndpointsResource,
+	privateLinkServicesResource,
+	privateDNSResource,
+	loadBalancersResource,
+	subnetsResource,
+	natGatewaysResource,
+	publicIPsResource,
+	publicIPPrefixesResource,
+	routeTablesResource,
+	securityGroupsResource,
+	vnetPeeringsResource,
+	virtualNetworkResource,
+	availabilitySetsResource,
+	proximityPlacementGroupResource,
+	resourceGroupResource,
+}
+
+// clusterResourceDependencies maps each kind of resource of a cluster to the kinds of resources it references. The
+// reconcile of a cluster creates the referenced resources first, except for the NAT gateways, which are associated with
+// the subnets by updating them once created. A resource can only be deleted once it isn't referenced anymore, so the
+// kinds of resources are deleted in the reverse order of their references.
+var clusterResourceDependencies = map[clusterResource][]clusterResource{
+	proximityPlacementGroupResource: {resourceGroupResource},
+	availabilitySetsResource:        {proximityPlacementGroupResource},
+	virtualNetworkResource:          {resourceGroupResource},
+	vnetPeeringsResource:            {virtualNetworkResource},
+	securityGroupsResource:          {resourceGroupResource},
+	routeTablesResource:             {resourceGroupResource},
+	subnetsResource:                 {virtualNetworkResource, securityGroupsResource, routeTablesResource, natGatewaysResource},
+	publicIPPrefixesResource:        {resourceGroupResource},
+	publicIPsResource:               {publicIPPrefixesResource},
+	natGatewaysResource:             {publicIPsResource},
+	bastionHostResource:             {subnetsResource, publicIPsResource},
+	applicationGatewayResource:      {subnetsResource, publicIPsResource},
+	privateEndpointsResource:        {subnetsResource},
+	privateLinkServicesResource:     {loadBalancersResource, subnetsResource},
+	loadBalancersResource:           {subnetsResource, publicIPsResource},
+	privateDNSResource:              {virtualNetworkResource},
+}
+
+// deletionOrder returns the kinds of resources in the order they must be deleted: each kind comes before all the kinds
+// it references. It fails if the references between the kinds of resources form a cycle.
+func deletionOrder(resources []clusterResource, dependencies map[clusterResource][]clusterResource) ([]clusterResource, error) {
+	referencedBy := map[clusterResource][]clusterResource{}
+	for _, resource := range resources {
+		for _, dependency := range dependencies[resource] {
+			referencedBy[dependency] = append(referencedBy[dependency], resource)
+		}
+	}
+
+	order := make([]clusterResource, 0, len(resources))
+	deleted := map[clusterResource]bool{}
+	for len(order) < len(resources) {
+		next := clusterResource("")
+		for _, resource := range resources {
+			if deleted[resource] {
+				continue
+			}
+			referenced := false
+			for _, referrer := range referencedBy[resource] {
+				if !deleted[referrer] {
+					referenced = true
+					break
+				}
+			}
+			if !referenced {
+				next = resource
+				break
+			}
+		}
+		if next == "" {
+			return nil, errors.New("the references between the resources of the cluster form a cycle")
+		}
+		deleted[next] = true
+		order = append(order, next)
+	}
+	return order, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDeletionOrder(t *testing.T) {
+	g := NewWithT(t)
+
+	order, err := deletionOrder(clusterResources, clusterResourceDependencies)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(order).To(Equal([]clusterResource{
+		bastionHostResource,
+		applicationGatewayResource,
+		privateEndpointsResource,
+		privateLinkServicesResource,
+		privateDNSResource,
+		loadBalancersResource,
+		subnetsResource,
+		natGatewaysResource,
+		publicIPsResource,
+		publicIPPrefixesResource,
+		routeTablesResource,
+		securityGroupsResource,
+		vnetPeeringsResource,
+		virtualNetworkResource,
+		availabilitySetsResource,
+		proximityPlacementGroupResource,
+		resourceGroupResource,
+	}))
+
+	position := map[clusterResource]int{}
+	for i, resource := range order {
+		position[resource] = i
+	}
+	for resource, dependencies := range clusterResourceDependencies {
+		for _, dependency := range dependencies {
+			g.Expect(position[resource]).To(BeNumerically("<", position[dependency]), "%s must be deleted before %s", resource, dependency)
+		}
+	}
+
+	steps := newAzureClusterReconciler(newPlannerTestClusterScope(t)).deletionSteps()
+	for _, resource := range clusterResources {
+		g.Expect(steps).To(HaveKey(resource))
+	}
+}
+
+func TestDeletionOrderWithCycle(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := deletionOrder([]clusterResource{subnetsResource, virtualNetworkResource}, map[clusterResource][]clusterResource{
+		subnetsResource:        {virtualNetworkResource},
+		virtualNetworkResource: {subnetsResource},
+	})
+	g.Expect(err).To(MatchError("the references between the resources of the cluster form a cycle"))
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/applicationgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks"
+)
+
+const (
+	// plannedCreate is the action of a resource the reconcile would create.
+	plannedCreate = "create"
+	// plannedUpdate is the action of a resource the reconcile would update.
+	plannedUpdate = "update"
+	// plannedKeep is the action of a resource which differs from the spec, but which the reconcile would leave as is.
+	plannedKeep = "keep"
+)
+
+// plannedChange is a change the reconcile of a cluster would make to one of its Azure resources.
+type plannedChange struct {
+	action   string
+	resource string
+	name     string
+	detail   string
+}
+
+func (c plannedChange) String() string {
+	s := fmt.Sprintf("%s %s %s", c.action, c.resource, c.name)
+	if c.detail != "" {
+		s += ": " + c.detail
+	}
+	return s
+}
+
+// azureClusterPlanner plans the changes the reconcile of a cluster would make to its Azure resources, from read
+// calls only, for the dry run of the cluster.
+type azureClusterPlanner struct {
+	scope                *scope.ClusterScope
+	reconciler           *azureClusterReconciler
+	groupsClient         groups.Client
+	vnetsClient          virtualnetworks.Client
+	subnetsClient        subnets.Client
+	securityGroupsClient securitygroups.Client
+	routeTablesClient    routetables.Client
+	publicIPsClient      publicips.Client
+	natGatewaysClient    natgateways.Client
+	loadBalancersClient  loadbalancers.Client
+	bastionHostsClient   bastionhosts.Client
+	appGatewaysClient    applicationgateways.Client
+}
+
+// newAzureClusterPlanner populates all the clients based on input scope
+func newAzureClusterPlanner(scope *scope.ClusterScope) *azureClusterPlanner {
+	return &azureClusterPlanner{
+		scope:                scope,
+		reconciler:           newAzureClusterReconciler(scope),
+		groupsClient:         groups.NewClient(scope),
+		vnetsClient:          virtualnetworks.NewClient(scope),
+		subnetsClient:        subnets.NewClient(scope),
+		securityGroupsClient: securitygroups.NewClient(scope),
+		routeTablesClient:    routetables.NewClient(scope),
+		publicIPsClient:      publicips.NewClient(scope),
+		natGatewaysClient:    natgateways.NewClient(scope),
+		loadBalancersClient:  loadbalancers.NewClient(scope),
+		bastionHostsClient:   bastionhosts.NewClient(scope),
+		appGatewaysClient:    applicationgateways.NewClient(scope),
+	}
+}
+
+// Plan returns the changes the reconcile of the cluster would make to its Azure resources, in the order it would
+// make them. Nothing is created, updated or deleted, and the spec of the cluster is left as is.
+func (p *azureClusterPlanner) Plan(ctx context.Context) ([]plannedChange, error) {
+	var changes []plannedChange
+
+	_, err := p.groupsClient.Get(ctx, p.scope.ResourceGroup())
+	if missing, err := isMissing(err, "resource group", p.scope.ResourceGroup()); err != nil {
+		return nil, err
+	} else if missing {
+		changes = append(changes, plannedChange{action: plannedCreate, resource: "resource group", name: p.scope.ResourceGroup()})
+	}
+
+	vnetChanges, err := p.planVnet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, vnetChanges...)
+
+	sgNames := append(p.reconciler.controlPlaneSecurityGroupNames(), p.reconciler.nodeSecurityGroupNames()...)
+	for _, name := range sgNames {
+		_, err := p.securityGroupsClient.Get(ctx, p.scope.NetworkResourceGroup(), name)
+		if missing, err := isMissing(err, "network security group", name); err != nil {
+			return nil, err
+		} else if missing {
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "network security group", name: name})
+		}
+	}
+
+	for _, rtSpec := range p.reconciler.routeTableSpecs() {
+		if rtSpec.ResourceGroup != "" {
+			// a pre-existing route table is used as is
+			continue
+		}
+		_, err := p.routeTablesClient.Get(ctx, p.scope.NetworkResourceGroup(), rtSpec.Name)
+		if missing, err := isMissing(err, "route table", rtSpec.Name); err != nil {
+			return nil, err
+		} else if missing {
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "route table", name: rtSpec.Name})
+		}
+	}
+
+	subnetChanges, err := p.planSubnets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, subnetChanges...)
+
+	for _, ip := range p.scope.PublicIPSpecs() {
+		_, err := p.publicIPsClient.Get(ctx, p.scope.NetworkResourceGroup(), ip.Name)
+		if missing, err := isMissing(err, "public IP", ip.Name); err != nil {
+			return nil, err
+		} else if missing {
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "public IP", name: ip.Name})
+		}
+	}
+
+	for _, natGatewaySpec := range p.scope.NatGatewaySpecs() {
+		_, err := p.natGatewaysClient.Get(ctx, p.scope.NetworkResourceGroup(), natGatewaySpec.Name)
+		if missing, err := isMissing(err, "NAT gateway", natGatewaySpec.Name); err != nil {
+			return nil, err
+		} else if missing {
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "NAT gateway", name: natGatewaySpec.Name,
+				detail: fmt.Sprintf("for subnet %s", natGatewaySpec.SubnetName)})
+		}
+	}
+
+	if bastionSpec := p.scope.BastionSpec(); bastionSpec != nil {
+		_, err := p.bastionHostsClient.Get(ctx, p.scope.NetworkResourceGroup(), bastionSpec.Name)
+		if missing, err := isMissing(err, "bastion host", bastionSpec.Name); err != nil {
+			return nil, err
+		} else if missing {
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "bastion host", name: bastionSpec.Name})
+		}
+	}
+
+	for _, lbSpec := range p.scope.LBSpecs() {
+		_, err := p.loadBalancersClient.Get(ctx, p.scope.NetworkResourceGroup(), lbSpec.Name)
+		if missing, err := isMissing(err, "load balancer", lbSpec.Name); err != nil {
+			return nil, err
+		} else if missing {
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "load balancer", name: lbSpec.Name,
+				detail: fmt.Sprintf("with role %s", lbSpec.Role)})
+		}
+	}
+
+	if appGatewaySpec := p.scope.ApplicationGatewaySpec(); appGatewaySpec != nil {
+		_, err := p.appGatewaysClient.Get(ctx, p.scope.NetworkResourceGroup(), appGatewaySpec.Name)
+		if missing, err := isMissing(err, "application gateway", appGatewaySpec.Name); err != nil {
+			return nil, err
+		} else if missing {
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "application gateway", name: appGatewaySpec.Name})
+		}
+	}
+
+	return changes, nil
+}
+
+// planVnet plans the creation of a managed vnet. The address space of an existing vnet is never updated.
+func (p *azureClusterPlanner) planVnet(ctx context.Context) ([]plannedChange, error) {
+	vnetSpec := p.scope.Vnet()
+	vnet, err := p.vnetsClient.Get(ctx, vnetSpec.ResourceGroup, vnetSpec.Name)
+	missing, err := isMissing(err, "virtual network", vnetSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	addressSpace := []string{vnetSpec.CidrBlock}
+	if vnetSpec.IPv6CidrBlock != "" {
+		addressSpace = append(addressSpace, vnetSpec.IPv6CidrBlock)
+	}
+	if missing {
+		if !vnetSpec.IsManaged(p.scope.ClusterName()) {
+			return nil, errors.Errorf("vnet %s with ID %s was provided but could not be found in resource group %s", vnetSpec.Name, vnetSpec.ID, vnetSpec.ResourceGroup)
+		}
+		return []plannedChange{{action: plannedCreate, resource: "virtual network", name: vnetSpec.Name,
+			detail: fmt.Sprintf("with address space %s", strings.Join(addressSpace, ", "))}}, nil
+	}
+
+	var existing []string
+	if vnet.VirtualNetworkPropertiesFormat != nil && vnet.AddressSpace != nil {
+		existing = to.StringSlice(vnet.AddressSpace.AddressPrefixes)
+	}
+	if !sameStrings(existing, addressSpace) {
+		return []plannedChange{{action: plannedKeep, resource: "virtual network", name: vnetSpec.Name,
+			detail: fmt.Sprintf("its address space %s differs from %s in the spec, the address space of an existing vnet isn't updated",
+				strings.Join(existing, ", "), strings.Join(addressSpace, ", "))}}, nil
+	}
+	return nil, nil
+}
+
+// planSubnets plans the creation of the subnets of a managed vnet, and the update of their service endpoints and
+// delegations.
+// The CIDR blocks of existing subnets are never updated.
+func (p *azureClusterPlanner) planSubnets(ctx context.Context) ([]plannedChange, error) {
+	type subnetPlan struct {
+		name             string
+		cidrBlocks       []string
+		serviceEndpoints []string
+		delegations      []string
+	}
+	var plans []subnetPlan
+	for _, subnet := range append(p.scope.ControlPlaneSubnets(), p.scope.NodeSubnets()...) {
+		cidrBlocks := []string{subnet.CidrBlock}
+		if subnet.IPv6CidrBlock != "" {
+			cidrBlocks = append(cidrBlocks, subnet.IPv6CidrBlock)
+		}
+		plans = append(plans, subnetPlan{name: subnet.Name, cidrBlocks: cidrBlocks, serviceEndpoints: subnet.ServiceEndpoints,
+			delegations: subnet.Delegations})
+	}
+	if bastionSpec := p.scope.BastionSpec(); bastionSpec != nil {
+		plans = append(plans, subnetPlan{name: bastionSpec.SubnetName, cidrBlocks: []string{bastionSpec.SubnetCIDR}})
+	}
+	if appGatewaySpec := p.scope.ApplicationGatewaySpec(); appGatewaySpec != nil {
+		plans = append(plans, subnetPlan{name: appGatewaySpec.SubnetName, cidrBlocks: []string{appGatewaySpec.SubnetCIDR}})
+	}
+
+	var changes []plannedChange
+	managed := p.scope.Vnet().IsManaged(p.scope.ClusterName())
+	for _, plan := range plans {
+		subnet, err := p.subnetsClient.Get(ctx, p.scope.Vnet().ResourceGroup, p.scope.Vnet().Name, plan.name)
+		missing, err := isMissing(err, "subnet", plan.name)
+		if err != nil {
+			return nil, err
+		}
+		if missing {
+			if !managed {
+				return nil, errors.Errorf("vnet was provided but subnet %s is missing", plan.name)
+			}
+			changes = append(changes, plannedChange{action: plannedCreate, resource: "subnet", name: plan.name,
+				detail: fmt.Sprintf("with CIDR block %s", strings.Join(plan.cidrBlocks, ", "))})
+			continue
+		}
+
+		existing := subnetCIDRBlocks(subnet)
+		if !sameStrings(existing, plan.cidrBlocks) {
+			changes = append(changes, plannedChange{action: plannedKeep, resource: "subnet", name: plan.name,
+				detail: fmt.Sprintf("its CIDR block %s differs from %s in the spec, the CIDR block of an existing subnet isn't updated",
+					strings.Join(existing, ", "), strings.Join(plan.cidrBlocks, ", "))})
+		}
+		var existingServices []string
+		if subnet.SubnetPropertiesFormat != nil && subnet.ServiceEndpoints != nil {
+			for _, endpoint := range *subnet.ServiceEndpoints {
+				existingServices = append(existingServices, to.String(endpoint.Service))
+			}
+		}
+		if managed && !sameStrings(existingServices, plan.serviceEndpoints) {
+			changes = append(changes, plannedChange{action: plannedUpdate, resource: "subnet", name: plan.name,
+				detail: fmt.Sprintf("service endpoints from [%s] to [%s]", strings.Join(existingServices, ", "), strings.Join(plan.serviceEndpoints, ", "))})
+		}
+		var existingDelegations []string
+		if subnet.SubnetPropertiesFormat != nil && subnet.Delegations != nil {
+			for _, delegation := range *subnet.Delegations {
+				if delegation.ServiceDelegationPropertiesFormat != nil {
+					existingDelegations = append(existingDelegations, to.String(delegation.ServiceName))
+				}
+			}
+		}
+		if managed && !sameStrings(existingDelegations, plan.delegations) {
+			changes = append(changes, plannedChange{action: plannedUpdate, resource: "subnet", name: plan.name,
+				detail: fmt.Sprintf("delegations from [%s] to [%s]", strings.Join(existingDelegations, ", "), strings.Join(plan.delegations, ", "))})
+		}
+	}
+	return changes, nil
+}
+
+// isMissing returns true if a resource wasn't found by a read call, or the error of the call.
+func isMissing(err error, resource, name string) (bool, error) {
+	if azure.ResourceNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get %s %s", resource, name)
+	}
+	return false, nil
+}
+
+// subnetCIDRBlocks returns the address prefixes of a subnet, which has a list of them when it is dual-stack.
+func subnetCIDRBlocks(subnet network.Subnet) []string {
+	if subnet.SubnetPropertiesFormat == nil {
+		return nil
+	}
+	if subnet.AddressPrefixes != nil && len(*subnet.AddressPrefixes) > 0 {
+		return *subnet.AddressPrefixes
+	}
+	if subnet.AddressPrefix != nil {
+		return []string{*subnet.AddressPrefix}
+	}
+	return nil
+}
+
+// sameStrings returns true if both lists have the same strings, in any order and regardless of case.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, s := range a {
+		seen[strings.ToLower(s)]++
+	}
+	for _, s := range b {
+		if seen[strings.ToLower(s)] == 0 {
+			return false
+		}
+		seen[strings.ToLower(s)]--
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/applicationgateways/mock_applicationgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/bastionhosts/mock_bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/groups/mock_groups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/loadbalancers/mock_loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/natgateways/mock_natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables/mock_routetables"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups/mock_securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks/mock_virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+)
+
+func TestAzureClusterPlannerPlan(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	existingSubnet := func(cidr string, services ...string) network.Subnet {
+		var endpoints []network.ServiceEndpointPropertiesFormat
+		for _, service := range services {
+			endpoints = append(endpoints, network.ServiceEndpointPropertiesFormat{Service: to.StringPtr(service)})
+		}
+		return network.Subnet{SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			AddressPrefix:    to.StringPtr(cidr),
+			ServiceEndpoints: &endpoints,
+		}}
+	}
+
+	tests := []struct {
+		name            string
+		expect          func(v *mock_virtualnetworks.MockClientMockRecorder, s *mock_subnets.MockClientMockRecorder)
+		resourcesExist  bool
+		expectedChanges []string
+	}{
+		{
+			name:           "no changes to an existing cluster",
+			resourcesExist: true,
+			expect: func(v *mock_virtualnetworks.MockClientMockRecorder, s *mock_subnets.MockClientMockRecorder) {
+				v.Get(gomock.Any(), "my-rg", "my-vnet").Return(network.VirtualNetwork{VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+					AddressSpace: &network.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/8"}},
+				}}, nil)
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "cp-subnet").Return(existingSubnet("10.0.0.0/16"), nil)
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "node-subnet").Return(existingSubnet("10.1.0.0/16", "Microsoft.Storage"), nil)
+			},
+		},
+		{
+			name: "creates all the resources of a new cluster",
+			expect: func(v *mock_virtualnetworks.MockClientMockRecorder, s *mock_subnets.MockClientMockRecorder) {
+				v.Get(gomock.Any(), "my-rg", "my-vnet").Return(network.VirtualNetwork{}, notFound)
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "cp-subnet").Return(network.Subnet{}, notFound)
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "node-subnet").Return(network.Subnet{}, notFound)
+			},
+			expectedChanges: []string{
+				"create resource group my-rg",
+				"create virtual network my-vnet: with address space 10.0.0.0/8",
+				"create network security group cp-nsg",
+				"create network security group node-nsg",
+				"create subnet cp-subnet: with CIDR block 10.0.0.0/16",
+				"create subnet node-subnet: with CIDR block 10.1.0.0/16",
+				"create public IP pip-my-cluster-node-outbound",
+				"create public IP my-cluster-api",
+				"create load balancer my-cluster-internal-lb: with role internal",
+				"create load balancer my-cluster-public-lb: with role apiserver",
+				"create load balancer my-cluster: with role nodeOutbound",
+			},
+		},
+		{
+			name:           "keeps the address spaces of an existing vnet and its subnets",
+			resourcesExist: true,
+			expect: func(v *mock_virtualnetworks.MockClientMockRecorder, s *mock_subnets.MockClientMockRecorder) {
+				v.Get(gomock.Any(), "my-rg", "my-vnet").Return(network.VirtualNetwork{VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+					AddressSpace: &network.AddressSpace{AddressPrefixes: &[]string{"192.168.0.0/16"}},
+				}}, nil)
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "cp-subnet").Return(existingSubnet("192.168.0.0/24"), nil)
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "node-subnet").Return(existingSubnet("10.1.0.0/16"), nil)
+			},
+			expectedChanges: []string{
+				"keep virtual network my-vnet: its address space 192.168.0.0/16 differs from 10.0.0.0/8 in the spec, the address space of an existing vnet isn't updated",
+				"keep subnet cp-subnet: its CIDR block 192.168.0.0/24 differs from 10.0.0.0/16 in the spec, the CIDR block of an existing subnet isn't updated",
+				"update subnet node-subnet: service endpoints from [] to [Microsoft.Storage]",
+			},
+		},
+		{
+			name:           "removes the delegations of an existing subnet missing from the spec",
+			resourcesExist: true,
+			expect: func(v *mock_virtualnetworks.MockClientMockRecorder, s *mock_subnets.MockClientMockRecorder) {
+				v.Get(gomock.Any(), "my-rg", "my-vnet").Return(network.VirtualNetwork{VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+					AddressSpace: &network.AddressSpace{AddressPrefixes: &[]string{"10.0.0.0/8"}},
+				}}, nil)
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "cp-subnet").Return(existingSubnet("10.0.0.0/16"), nil)
+				nodeSubnet := existingSubnet("10.1.0.0/16", "Microsoft.Storage")
+				nodeSubnet.Delegations = &[]network.Delegation{
+					{ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{ServiceName: to.StringPtr("Microsoft.Netapp/volumes")}},
+				}
+				s.Get(gomock.Any(), "my-rg", "my-vnet", "node-subnet").Return(nodeSubnet, nil)
+			},
+			expectedChanges: []string{
+				"update subnet node-subnet: delegations from [Microsoft.Netapp/volumes] to []",
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			groupsMock := mock_groups.NewMockClient(mockCtrl)
+			vnetsMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+			subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+			securityGroupsMock := mock_securitygroups.NewMockClient(mockCtrl)
+			routeTablesMock := mock_routetables.NewMockClient(mockCtrl)
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+			natGatewaysMock := mock_natgateways.NewMockClient(mockCtrl)
+			loadBalancersMock := mock_loadbalancers.NewMockClient(mockCtrl)
+			bastionHostsMock := mock_bastionhosts.NewMockClient(mockCtrl)
+			appGatewaysMock := mock_applicationgateways.NewMockClient(mockCtrl)
+
+			tc.expect(vnetsMock.EXPECT(), subnetsMock.EXPECT())
+			if tc.resourcesExist {
+				groupsMock.EXPECT().Get(gomock.Any(), "my-rg").Return(resources.Group{}, nil)
+				securityGroupsMock.EXPECT().Get(gomock.Any(), "my-rg", gomock.Any()).Return(network.SecurityGroup{}, nil).Times(2)
+				publicIPsMock.EXPECT().Get(gomock.Any(), "my-rg", gomock.Any()).Return(network.PublicIPAddress{}, nil).Times(2)
+				loadBalancersMock.EXPECT().Get(gomock.Any(), "my-rg", gomock.Any()).Return(network.LoadBalancer{}, nil).Times(3)
+			} else {
+				groupsMock.EXPECT().Get(gomock.Any(), "my-rg").Return(resources.Group{}, notFound)
+				securityGroupsMock.EXPECT().Get(gomock.Any(), "my-rg", gomock.Any()).Return(network.SecurityGroup{}, notFound).Times(2)
+				publicIPsMock.EXPECT().Get(gomock.Any(), "my-rg", gomock.Any()).Return(network.PublicIPAddress{}, notFound).Times(2)
+				loadBalancersMock.EXPECT().Get(gomock.Any(), "my-rg", gomock.Any()).Return(network.LoadBalancer{}, notFound).Times(3)
+			}
+
+			clusterScope := newPlannerTestClusterScope(t)
+			p := &azureClusterPlanner{
+				scope:                clusterScope,
+				reconciler:           newAzureClusterReconciler(clusterScope),
+				groupsClient:         groupsMock,
+				vnetsClient:          vnetsMock,
+				subnetsClient:        subnetsMock,
+				securityGroupsClient: securityGroupsMock,
+				routeTablesClient:    routeTablesMock,
+				publicIPsClient:      publicIPsMock,
+				natGatewaysClient:    natGatewaysMock,
+				loadBalancersClient:  loadBalancersMock,
+				bastionHostsClient:   bastionHostsMock,
+				appGatewaysClient:    appGatewaysMock,
+			}
+
+			changes, err := p.Plan(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			g.Expect(got).To(Equal(tc.expectedChanges))
+		})
+	}
+}
+
+func newPlannerTestClusterScope(t *testing.T) *scope.ClusterScope {
+	g := NewWithT(t)
+
+	// setCredentials resolves the cloud environment from the process environment when the scope is created
+	defer test.SetEnv("AZURE_ENVIRONMENT", "AzurePublicCloud")()
+
+	cluster := newCluster("my-cluster")
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		AzureClients: scope.AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Client:  fake.NewFakeClientWithScheme(setupScheme(g), cluster),
+		Cluster: cluster,
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			Spec: infrav1.AzureClusterSpec{
+				Location:       "westus2",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				NetworkSpec: infrav1.NetworkSpec{
+					Vnet: infrav1.VnetSpec{Name: "my-vnet", ResourceGroup: "my-rg", CidrBlock: "10.0.0.0/8"},
+					Subnets: infrav1.Subnets{
+						{
+							Name:          "cp-subnet",
+							Role:          infrav1.SubnetControlPlane,
+							CidrBlock:     "10.0.0.0/16",
+							SecurityGroup: infrav1.SecurityGroup{Name: "cp-nsg"},
+						},
+						{
+							Name:             "node-subnet",
+							Role:             infrav1.SubnetNode,
+							CidrBlock:        "10.1.0.0/16",
+							SecurityGroup:    infrav1.SecurityGroup{Name: "node-nsg"},
+							ServiceEndpoints: []string{"Microsoft.Storage"},
+						},
+					},
+				},
+			},
+			Status: infrav1.AzureClusterStatus{
+				Network: infrav1.Network{
+					APIServerIP: infrav1.PublicIP{Name: "my-cluster-api"},
+				},
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	return clusterScope
+}
@@ -20,8 +20,12 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"reflect"
 	"strconv"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	"k8s.io/klog"
@@ -30,48 +34,93 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/applicationgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/availabilitysets"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/availabilityzones"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/bastionhosts"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatedns"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/privatelinkservices"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/proximityplacementgroups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicipprefixes"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/vnetpeerings"
 )
 
 // azureClusterReconciler is the reconciler called by the AzureCluster controller
 type azureClusterReconciler struct {
-	scope                *scope.ClusterScope
-	groupsSvc            azure.Service
-	vnetSvc              azure.OldService
-	securityGroupSvc     azure.OldService
-	routeTableSvc        azure.OldService
-	subnetsSvc           azure.OldService
-	publicIPSvc          azure.Service
-	loadBalancerSvc      azure.Service
-	availabilityZonesSvc azure.GetterService
+	scope                 *scope.ClusterScope
+	groupsSvc             azure.Service
+	vnetSvc               azure.OldService
+	vnetsClient           virtualnetworks.Client
+	vnetPeeringSvc        azure.Service
+	securityGroupSvc      azure.OldService
+	securityGroupsClient  securitygroups.Client
+	routeTableSvc         azure.OldService
+	subnetsSvc            azure.OldService
+	subnetsClient         subnets.Client
+	publicIPPrefixSvc     azure.Service
+	publicIPSvc           azure.Service
+	publicIPsClient       publicips.Client
+	natGatewaySvc         azure.Service
+	loadBalancerSvc       azure.Service
+	loadBalancersClient   loadbalancers.Client
+	privateDNSSvc         azure.Service
+	bastionSvc            azure.Service
+	privateEndpointSvc    azure.Service
+	privateLinkServiceSvc azure.Service
+	appGatewaySvc         azure.Service
+	ppgSvc                azure.Service
+	availabilitySetsSvc   azure.Service
+	availabilityZonesSvc  azure.GetterService
 }
 
 // newAzureClusterReconciler populates all the services based on input scope
 func newAzureClusterReconciler(scope *scope.ClusterScope) *azureClusterReconciler {
 	return &azureClusterReconciler{
-		scope:                scope,
-		groupsSvc:            groups.NewService(scope),
-		vnetSvc:              virtualnetworks.NewService(scope),
-		securityGroupSvc:     securitygroups.NewService(scope),
-		routeTableSvc:        routetables.NewService(scope),
-		subnetsSvc:           subnets.NewService(scope),
-		publicIPSvc:          publicips.NewService(scope),
-		loadBalancerSvc:      loadbalancers.NewService(scope),
-		availabilityZonesSvc: availabilityzones.NewService(scope),
+		scope:                 scope,
+		groupsSvc:             groups.NewService(scope),
+		vnetSvc:               virtualnetworks.NewService(scope),
+		vnetsClient:           virtualnetworks.NewClient(scope),
+		vnetPeeringSvc:        vnetpeerings.NewService(scope),
+		securityGroupSvc:      securitygroups.NewService(scope),
+		securityGroupsClient:  securitygroups.NewClient(scope),
+		routeTableSvc:         routetables.NewService(scope),
+		subnetsSvc:            subnets.NewService(scope),
+		subnetsClient:         subnets.NewClient(scope),
+		publicIPPrefixSvc:     publicipprefixes.NewService(scope),
+		publicIPSvc:           publicips.NewService(scope),
+		publicIPsClient:       publicips.NewClient(scope),
+		natGatewaySvc:         natgateways.NewService(scope),
+		loadBalancerSvc:       loadbalancers.NewService(scope),
+		loadBalancersClient:   loadbalancers.NewClient(scope),
+		privateDNSSvc:         privatedns.NewService(scope),
+		bastionSvc:            bastionhosts.NewService(scope),
+		privateEndpointSvc:    privateendpoints.NewService(scope),
+		privateLinkServiceSvc: privatelinkservices.NewService(scope),
+		appGatewaySvc:         applicationgateways.NewService(scope),
+		ppgSvc:                proximityplacementgroups.NewService(scope),
+		availabilitySetsSvc:   availabilitysets.NewService(scope),
+		availabilityZonesSvc:  availabilityzones.NewService(scope),
 	}
 }
 
 // Reconcile reconciles all the services in pre determined order
 func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 	klog.V(2).Infof("reconciling cluster %s", r.scope.ClusterName())
+	if err := r.scope.ValidateLocation(ctx); err != nil {
+		return errors.Wrapf(err, "invalid location for cluster %s", r.scope.ClusterName())
+	}
+
 	if err := r.createOrUpdateNetworkAPIServerIP(); err != nil {
 		return errors.Wrapf(err, "failed to create or update network API server IP for cluster %s in location %s", r.scope.ClusterName(), r.scope.Location())
 	}
@@ -80,127 +129,324 @@ func (r *azureClusterReconciler) Reconcile(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to get availability zones for cluster %s in location %s", r.scope.ClusterName(), r.scope.Location())
 	}
 
+	if _, err := r.scope.ResolveDefaultImage(ctx); err != nil {
+		return errors.Wrapf(err, "invalid default image for cluster %s", r.scope.ClusterName())
+	}
+
 	if err := r.groupsSvc.Reconcile(ctx); err != nil {
 		return errors.Wrapf(err, "failed to reconcile resource group for cluster %s", r.scope.ClusterName())
 	}
 
+	if err := r.ppgSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile proximity placement group for cluster %s", r.scope.ClusterName())
+	}
+
+	if err := r.availabilitySetsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile availability sets for cluster %s", r.scope.ClusterName())
+	}
+
 	vnetSpec := &virtualnetworks.Spec{
 		ResourceGroup: r.scope.Vnet().ResourceGroup,
 		Name:          r.scope.Vnet().Name,
 		CIDR:          r.scope.Vnet().CidrBlock,
+		IPv6CIDR:      r.scope.Vnet().IPv6CidrBlock,
+		DNSServers:    r.scope.Vnet().DNSServers,
 	}
 	if err := r.vnetSvc.Reconcile(ctx, vnetSpec); err != nil {
+		r.setConditionFalse(infrav1.VNetReadyCondition, infrav1.VNetReconcileFailedReason, err)
 		return errors.Wrapf(err, "failed to reconcile virtual network for cluster %s", r.scope.ClusterName())
 	}
+	r.scope.SetConditionTrue(infrav1.VNetReadyCondition)
+
+	if err := r.setVnetResourceID(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get virtual network resource ID for cluster %s", r.scope.ClusterName())
+	}
+
+	if err := r.vnetPeeringSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile virtual network peerings for cluster %s", r.scope.ClusterName())
+	}
+
+	for _, cpSubnet := range r.scope.ControlPlaneSubnets() {
+		if reflect.DeepEqual(cpSubnet.SecurityGroup.IngressRules, r.legacyControlPlaneIngressRules()) {
+			// the default rules used to be written to the spec, which would keep them from following the network spec
+			cpSubnet.SecurityGroup.IngressRules = nil
+		}
+	}
+
+	if err := r.reconcileSecurityGroups(ctx); err != nil {
+		return err
+	}
 
-	cpSubnet := r.scope.ControlPlaneSubnet()
-	if cpSubnet.SecurityGroup.IngressRules == nil {
-		cpSubnet.SecurityGroup.IngressRules = r.generateControlPlaneIngressRules()
+	if err := r.setSecurityGroupResourceIDs(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get network security group resource IDs for cluster %s", r.scope.ClusterName())
 	}
 
-	sgSpec := &securitygroups.Spec{
-		Name:           r.scope.ControlPlaneSubnet().SecurityGroup.Name,
-		IsControlPlane: true,
+	if err := r.scope.ValidateBastion(); err != nil {
+		return errors.Wrapf(err, "invalid bastion host for cluster %s", r.scope.ClusterName())
 	}
-	if err := r.securityGroupSvc.Reconcile(ctx, sgSpec); err != nil {
-		return errors.Wrapf(err, "failed to reconcile control plane network security group for cluster %s", r.scope.ClusterName())
+
+	if err := r.scope.ValidateApplicationGateway(); err != nil {
+		return errors.Wrapf(err, "invalid application gateway for cluster %s", r.scope.ClusterName())
 	}
 
-	sgSpec = &securitygroups.Spec{
-		Name:           r.scope.NodeSubnet().SecurityGroup.Name,
-		IsControlPlane: false,
+	if err := r.scope.ValidatePrivateEndpoints(); err != nil {
+		return errors.Wrapf(err, "invalid private endpoints for cluster %s", r.scope.ClusterName())
 	}
-	if err := r.securityGroupSvc.Reconcile(ctx, sgSpec); err != nil {
-		return errors.Wrapf(err, "failed to reconcile node network security group for cluster %s", r.scope.ClusterName())
+
+	// the route tables must exist before the subnets they are associated with
+	if err := r.reconcileRouteTables(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile route tables for cluster %s", r.scope.ClusterName())
 	}
 
-	rtSpec := &routetables.Spec{
-		Name: r.scope.NodeSubnet().RouteTable.Name,
+	var subnetSpec *subnets.Spec
+	for _, cpSubnet := range r.scope.ControlPlaneSubnets() {
+		subnetSpec = &subnets.Spec{
+			ID:                      cpSubnet.ID,
+			Name:                    cpSubnet.Name,
+			CIDR:                    cpSubnet.CidrBlock,
+			IPv6CIDR:                cpSubnet.IPv6CidrBlock,
+			VnetName:                r.scope.Vnet().Name,
+			SecurityGroupName:       cpSubnet.SecurityGroup.Name,
+			Role:                    cpSubnet.Role,
+			RouteTableName:          cpSubnet.RouteTable.Name,
+			RouteTableResourceGroup: routeTableResourceGroup(cpSubnet.RouteTable),
+			InternalLBIPAddress:     cpSubnet.InternalLBIPAddress,
+			ServiceEndpoints:        cpSubnet.ServiceEndpoints,
+			Delegations:             cpSubnet.Delegations,
+		}
+		if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
+			r.scope.SetConditionFalse(infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, err)
+			return errors.Wrapf(err, "failed to reconcile control plane subnet %s for cluster %s", cpSubnet.Name, r.scope.ClusterName())
+		}
 	}
-	if err := r.routeTableSvc.Reconcile(ctx, rtSpec); err != nil {
-		return errors.Wrapf(err, "failed to reconcile route table %s for cluster %s", r.scope.NodeSubnet().RouteTable.Name, r.scope.ClusterName())
+
+	for _, nodeSubnet := range r.scope.NodeSubnets() {
+		subnetSpec = &subnets.Spec{
+			ID:                      nodeSubnet.ID,
+			Name:                    nodeSubnet.Name,
+			CIDR:                    nodeSubnet.CidrBlock,
+			IPv6CIDR:                nodeSubnet.IPv6CidrBlock,
+			VnetName:                r.scope.Vnet().Name,
+			SecurityGroupName:       nodeSubnet.SecurityGroup.Name,
+			RouteTableName:          nodeSubnet.RouteTable.Name,
+			RouteTableResourceGroup: routeTableResourceGroup(nodeSubnet.RouteTable),
+			Role:                    nodeSubnet.Role,
+			ServiceEndpoints:        nodeSubnet.ServiceEndpoints,
+			Delegations:             nodeSubnet.Delegations,
+		}
+		if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
+			r.scope.SetConditionFalse(infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, err)
+			return errors.Wrapf(err, "failed to reconcile node subnet %s for cluster %s", nodeSubnet.Name, r.scope.ClusterName())
+		}
 	}
 
-	subnetSpec := &subnets.Spec{
-		Name:                r.scope.ControlPlaneSubnet().Name,
-		CIDR:                r.scope.ControlPlaneSubnet().CidrBlock,
-		VnetName:            r.scope.Vnet().Name,
-		SecurityGroupName:   r.scope.ControlPlaneSubnet().SecurityGroup.Name,
-		Role:                r.scope.ControlPlaneSubnet().Role,
-		RouteTableName:      r.scope.ControlPlaneSubnet().RouteTable.Name,
-		InternalLBIPAddress: r.scope.ControlPlaneSubnet().InternalLBIPAddress,
+	if bastionSpec := r.scope.BastionSpec(); bastionSpec != nil {
+		subnetSpec = &subnets.Spec{
+			Name:     bastionSpec.SubnetName,
+			CIDR:     bastionSpec.SubnetCIDR,
+			VnetName: bastionSpec.VNetName,
+		}
+		if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
+			r.scope.SetConditionFalse(infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, err)
+			return errors.Wrapf(err, "failed to reconcile bastion subnet %s for cluster %s", bastionSpec.SubnetName, r.scope.ClusterName())
+		}
 	}
-	if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
-		return errors.Wrapf(err, "failed to reconcile control plane subnet for cluster %s", r.scope.ClusterName())
+
+	if appGatewaySpec := r.scope.ApplicationGatewaySpec(); appGatewaySpec != nil {
+		subnetSpec = &subnets.Spec{
+			Name:     appGatewaySpec.SubnetName,
+			CIDR:     appGatewaySpec.SubnetCIDR,
+			VnetName: appGatewaySpec.VNetName,
+		}
+		if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
+			r.scope.SetConditionFalse(infrav1.SubnetsReadyCondition, infrav1.SubnetsReconcileFailedReason, err)
+			return errors.Wrapf(err, "failed to reconcile application gateway subnet %s for cluster %s", appGatewaySpec.SubnetName, r.scope.ClusterName())
+		}
 	}
+	r.scope.SetConditionTrue(infrav1.SubnetsReadyCondition)
 
-	subnetSpec = &subnets.Spec{
-		Name:              r.scope.NodeSubnet().Name,
-		CIDR:              r.scope.NodeSubnet().CidrBlock,
-		VnetName:          r.scope.Vnet().Name,
-		SecurityGroupName: r.scope.NodeSubnet().SecurityGroup.Name,
-		RouteTableName:    r.scope.NodeSubnet().RouteTable.Name,
-		Role:              r.scope.NodeSubnet().Role,
+	if err := r.setSubnetResourceIDs(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get subnet resource IDs for cluster %s", r.scope.ClusterName())
 	}
-	if err := r.subnetsSvc.Reconcile(ctx, subnetSpec); err != nil {
-		return errors.Wrapf(err, "failed to reconcile node subnet for cluster %s", r.scope.ClusterName())
+
+	if err := r.publicIPPrefixSvc.Reconcile(ctx); err != nil {
+		r.scope.SetConditionFalse(infrav1.PublicIPsReadyCondition, infrav1.PublicIPsReconcileFailedReason, err)
+		return errors.Wrapf(err, "failed to reconcile public IP prefixes for cluster %s", r.scope.ClusterName())
 	}
 
 	if err := r.publicIPSvc.Reconcile(ctx); err != nil {
+		r.scope.SetConditionFalse(infrav1.PublicIPsReadyCondition, infrav1.PublicIPsReconcileFailedReason, err)
 		return errors.Wrapf(err, "failed to reconcile public IPs for cluster %s", r.scope.ClusterName())
 	}
 
+	if err := r.validateAPIServerPublicIP(ctx); err != nil {
+		r.scope.SetConditionFalse(infrav1.PublicIPsReadyCondition, infrav1.PublicIPsReconcileFailedReason, err)
+		return errors.Wrapf(err, "invalid API server public IP for cluster %s", r.scope.ClusterName())
+	}
+	r.scope.SetConditionTrue(infrav1.PublicIPsReadyCondition)
+
+	if err := r.setPublicIPResourceIDs(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get public IP resource IDs for cluster %s", r.scope.ClusterName())
+	}
+
+	if err := r.natGatewaySvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile NAT gateways for cluster %s", r.scope.ClusterName())
+	}
+
+	if err := r.bastionSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile bastion host for cluster %s", r.scope.ClusterName())
+	}
+
+	if err := r.privateEndpointSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile private endpoints for cluster %s", r.scope.ClusterName())
+	}
+
+	if err := r.scope.ValidateAPIServerPort(); err != nil {
+		return errors.Wrap(err, "invalid API server port")
+	}
+
+	if err := r.scope.ValidateInternalLBIPAddress(); err != nil {
+		return errors.Wrapf(err, "invalid internal load balancer IP for cluster %s", r.scope.ClusterName())
+	}
+
 	if err := r.loadBalancerSvc.Reconcile(ctx); err != nil {
+		r.setConditionFalse(infrav1.LoadBalancersReadyCondition, infrav1.LoadBalancersReconcileFailedReason, err)
 		return errors.Wrapf(err, "failed to reconcile load balancers for cluster %s", r.scope.ClusterName())
 	}
+	r.scope.SetConditionTrue(infrav1.LoadBalancersReadyCondition)
 
-	return nil
-}
+	// the additional API server public IPs removed from the spec aren't used by the API server load balancer anymore
+	if err := r.deleteRemovedAPIServerIPs(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete the removed API server public IPs of cluster %s", r.scope.ClusterName())
+	}
 
-// Delete reconciles all the services in pre determined order
-func (r *azureClusterReconciler) Delete(ctx context.Context) error {
-	if err := r.loadBalancerSvc.Delete(ctx); err != nil {
-		if !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete load balancers for cluster %s", r.scope.ClusterName())
-		}
+	if err := r.setLoadBalancerResourceIDs(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get load balancer resource IDs for cluster %s", r.scope.ClusterName())
 	}
 
-	if err := r.deleteSubnets(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete subnets")
+	if err := r.setLoadBalancerFrontendIPs(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get load balancer frontend IP addresses for cluster %s", r.scope.ClusterName())
 	}
 
-	rtSpec := &routetables.Spec{
-		Name: r.scope.NodeSubnet().RouteTable.Name,
+	if err := r.privateLinkServiceSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile private link service for cluster %s", r.scope.ClusterName())
 	}
-	if err := r.routeTableSvc.Delete(ctx, rtSpec); err != nil {
-		if !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete route table %s for cluster %s", r.scope.NodeSubnet().RouteTable.Name, r.scope.ClusterName())
-		}
+
+	if err := r.privateDNSSvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile private DNS zone for cluster %s", r.scope.ClusterName())
 	}
 
-	if err := r.deleteNSG(ctx); err != nil {
-		return errors.Wrap(err, "failed to delete network security group")
+	if err := r.setAPIServerIPAddresses(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get API server IP addresses for cluster %s", r.scope.ClusterName())
 	}
 
-	vnetSpec := &virtualnetworks.Spec{
-		ResourceGroup: r.scope.Vnet().ResourceGroup,
-		Name:          r.scope.Vnet().Name,
+	// the backend of the application gateway is reached at the control plane endpoint, known once the load balancers
+	// are reconciled
+	if err := r.appGatewaySvc.Reconcile(ctx); err != nil {
+		return errors.Wrapf(err, "failed to reconcile application gateway for cluster %s", r.scope.ClusterName())
 	}
-	if err := r.vnetSvc.Delete(ctx, vnetSpec); err != nil {
-		if !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete virtual network %s for cluster %s", r.scope.Vnet().Name, r.scope.ClusterName())
-		}
+
+	// the additional tags are now applied to every resource, so the next reconcile can tell which ones were removed
+	if err := r.scope.UpdateLastAppliedTags(); err != nil {
+		return errors.Wrapf(err, "failed to record the applied tags of cluster %s", r.scope.ClusterName())
 	}
 
-	if err := r.groupsSvc.Delete(ctx); err != nil {
-		if !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete resource group for cluster %s", r.scope.ClusterName())
-		}
+	return nil
+}
+
+// setConditionFalse sets a condition of the cluster to false with the reason of the failure, or as in progress while
+// a long-running operation of the service is polled.
+func (r *azureClusterReconciler) setConditionFalse(conditionType clusterv1.ConditionType, reason string, err error) {
+	if azure.IsOperationNotDoneError(err) {
+		r.scope.SetConditionInProgress(conditionType, err)
+		return
+	}
+	r.scope.SetConditionFalse(conditionType, reason, err)
+}
+
+// Delete deletes the resources of the cluster, each kind of resource only once the resources referencing it are deleted.
+// A deletion conflicting with a resource still referencing the deleted one is retried a few times, as the referencing
+// resource may still be being released.
+func (r *azureClusterReconciler) Delete(ctx context.Context) error {
+	order, err := deletionOrder(clusterResources, clusterResourceDependencies)
+	if err != nil {
+		return errors.Wrapf(err, "failed to order the deletion of the resources of cluster %s", r.scope.ClusterName())
 	}
 
+	steps := r.deletionSteps()
+	for _, resource := range order {
+		if err := azure.RetryOnConflict(ctx, azure.DefaultConflictMaxRetries, azure.DefaultConflictRetryDelay, func() error {
+			return steps[resource](ctx)
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete %s for cluster %s", resource, r.scope.ClusterName())
+		}
+	}
 	return nil
 }
 
+// deletionSteps returns the function deleting each kind of resource of the cluster. A resource already deleted isn't
+// an error.
+func (r *azureClusterReconciler) deletionSteps() map[clusterResource]func(context.Context) error {
+	ignoreNotFound := func(err error) error {
+		if azure.ResourceNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return map[clusterResource]func(context.Context) error{
+		bastionHostResource:         r.bastionSvc.Delete,
+		applicationGatewayResource:  r.appGatewaySvc.Delete,
+		privateEndpointsResource:    r.privateEndpointSvc.Delete,
+		privateLinkServicesResource: r.privateLinkServiceSvc.Delete,
+		privateDNSResource:          r.privateDNSSvc.Delete,
+		loadBalancersResource: func(ctx context.Context) error {
+			if err := ignoreNotFound(r.loadBalancerSvc.Delete(ctx)); err != nil {
+				return err
+			}
+			r.scope.Network().InternalLBIPAddress = ""
+			r.scope.Network().InternalLBZones = nil
+			r.scope.Network().NodeOutboundIPs = nil
+			return nil
+		},
+		subnetsResource: r.deleteSubnets,
+		natGatewaysResource: func(ctx context.Context) error {
+			return ignoreNotFound(r.natGatewaySvc.Delete(ctx))
+		},
+		publicIPsResource: func(ctx context.Context) error {
+			if err := r.deleteRemovedAPIServerIPs(ctx); err != nil {
+				return err
+			}
+			return ignoreNotFound(r.publicIPSvc.Delete(ctx))
+		},
+		publicIPPrefixesResource: func(ctx context.Context) error {
+			return ignoreNotFound(r.publicIPPrefixSvc.Delete(ctx))
+		},
+		routeTablesResource: func(ctx context.Context) error {
+			for _, rtSpec := range r.routeTableSpecs() {
+				if err := ignoreNotFound(r.routeTableSvc.Delete(ctx, rtSpec)); err != nil {
+					return errors.Wrapf(err, "failed to delete route table %s", rtSpec.Name)
+				}
+			}
+			return nil
+		},
+		securityGroupsResource: r.deleteNSG,
+		vnetPeeringsResource:   r.vnetPeeringSvc.Delete,
+		virtualNetworkResource: func(ctx context.Context) error {
+			vnetSpec := &virtualnetworks.Spec{
+				ResourceGroup: r.scope.Vnet().ResourceGroup,
+				Name:          r.scope.Vnet().Name,
+			}
+			return errors.Wrapf(ignoreNotFound(r.vnetSvc.Delete(ctx, vnetSpec)), "failed to delete virtual network %s", r.scope.Vnet().Name)
+		},
+		// the VMs of the cluster are deleted with its machines, before the cluster
+		availabilitySetsResource:        r.availabilitySetsSvc.Delete,
+		proximityPlacementGroupResource: r.ppgSvc.Delete,
+		resourceGroupResource: func(ctx context.Context) error {
+			return ignoreNotFound(r.groupsSvc.Delete(ctx))
+		},
+	}
+}
+
 func (r *azureClusterReconciler) deleteSubnets(ctx context.Context) error {
 	for _, s := range r.scope.Subnets() {
 		subnetSpec := &subnets.Spec{
@@ -209,7 +455,29 @@ func (r *azureClusterReconciler) deleteSubnets(ctx context.Context) error {
 		}
 		if err := r.subnetsSvc.Delete(ctx, subnetSpec); err != nil {
 			if !azure.ResourceNotFound(err) {
-				return errors.Wrapf(err, "failed to delete %s subnet for cluster %s", s.Name, r.scope.ClusterName())
+				return errors.Wrapf(err, "failed to delete subnet %s", s.Name)
+			}
+		}
+	}
+	if bastionSpec := r.scope.BastionSpec(); bastionSpec != nil {
+		subnetSpec := &subnets.Spec{
+			Name:     bastionSpec.SubnetName,
+			VnetName: bastionSpec.VNetName,
+		}
+		if err := r.subnetsSvc.Delete(ctx, subnetSpec); err != nil {
+			if !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to delete subnet %s", bastionSpec.SubnetName)
+			}
+		}
+	}
+	if appGatewaySpec := r.scope.ApplicationGatewaySpec(); appGatewaySpec != nil {
+		subnetSpec := &subnets.Spec{
+			Name:     appGatewaySpec.SubnetName,
+			VnetName: appGatewaySpec.VNetName,
+		}
+		if err := r.subnetsSvc.Delete(ctx, subnetSpec); err != nil {
+			if !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to delete subnet %s", appGatewaySpec.SubnetName)
 			}
 		}
 	}
@@ -217,29 +485,173 @@ func (r *azureClusterReconciler) deleteSubnets(ctx context.Context) error {
 }
 
 func (r *azureClusterReconciler) deleteNSG(ctx context.Context) error {
-	sgSpec := &securitygroups.Spec{
-		Name: r.scope.NodeSubnet().SecurityGroup.Name,
+	for _, name := range r.nodeSecurityGroupNames() {
+		sgSpec := &securitygroups.Spec{
+			Name: name,
+		}
+		if err := r.securityGroupSvc.Delete(ctx, sgSpec); err != nil {
+			if !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to delete security group %s", name)
+			}
+		}
 	}
-	if err := r.securityGroupSvc.Delete(ctx, sgSpec); err != nil {
-		if !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete security group %s for cluster %s", r.scope.NodeSubnet().SecurityGroup.Name, r.scope.ClusterName())
+	for _, name := range r.controlPlaneSecurityGroupNames() {
+		sgSpec := &securitygroups.Spec{
+			Name: name,
+		}
+		if err := r.securityGroupSvc.Delete(ctx, sgSpec); err != nil {
+			if !azure.ResourceNotFound(err) {
+				return errors.Wrapf(err, "failed to delete security group %s", name)
+			}
 		}
 	}
-	sgSpec = &securitygroups.Spec{
-		Name: r.scope.ControlPlaneSubnet().SecurityGroup.Name,
+
+	return nil
+}
+
+// reconcileSecurityGroups reconciles the control plane and node security groups. They are reconciled one at a time,
+// as each of them records its rules in the last applied security rules annotation of the AzureCluster.
+func (r *azureClusterReconciler) reconcileSecurityGroups(ctx context.Context) error {
+	for _, name := range r.controlPlaneSecurityGroupNames() {
+		sgSpec := &securitygroups.Spec{
+			Name:           name,
+			IsControlPlane: true,
+		}
+		if err := r.securityGroupSvc.Reconcile(ctx, sgSpec); err != nil {
+			r.scope.SetConditionFalse(infrav1.SecurityGroupsReadyCondition, infrav1.SecurityGroupsReconcileFailedReason, err)
+			return errors.Wrapf(err, "failed to reconcile control plane network security group %s for cluster %s", name, r.scope.ClusterName())
+		}
 	}
-	if err := r.securityGroupSvc.Delete(ctx, sgSpec); err != nil {
-		if !azure.ResourceNotFound(err) {
-			return errors.Wrapf(err, "failed to delete security group %s for cluster %s", r.scope.ControlPlaneSubnet().SecurityGroup.Name, r.scope.ClusterName())
+
+	for _, name := range r.nodeSecurityGroupNames() {
+		sgSpec := &securitygroups.Spec{
+			Name:           name,
+			IsControlPlane: false,
+		}
+		if err := r.securityGroupSvc.Reconcile(ctx, sgSpec); err != nil {
+			r.scope.SetConditionFalse(infrav1.SecurityGroupsReadyCondition, infrav1.SecurityGroupsReconcileFailedReason, err)
+			return errors.Wrapf(err, "failed to reconcile node network security group %s for cluster %s", name, r.scope.ClusterName())
 		}
 	}
+	r.scope.SetConditionTrue(infrav1.SecurityGroupsReadyCondition)
+	return nil
+}
 
+// reconcileRouteTables reconciles the route tables of the subnets. They are reconciled one at a time, as each of them
+// records its name and ID in the subnets of the AzureCluster associated with it.
+func (r *azureClusterReconciler) reconcileRouteTables(ctx context.Context) error {
+	for _, rtSpec := range r.routeTableSpecs() {
+		if err := r.routeTableSvc.Reconcile(ctx, rtSpec); err != nil {
+			return errors.Wrapf(err, "failed to reconcile route table %s", rtSpec.Name)
+		}
+	}
 	return nil
 }
 
-// CreateOrUpdateNetworkAPIServerIP creates or updates public ip name and dns name
+// deleteRemovedAPIServerIPs deletes the additional public IPs of the API server load balancer which were removed from
+// the spec, then records the current ones. Only the public IPs owned by the cluster are deleted.
+func (r *azureClusterReconciler) deleteRemovedAPIServerIPs(ctx context.Context) error {
+	for _, name := range r.scope.RemovedAdditionalAPIServerIPNames() {
+		ip, err := r.publicIPsClient.Get(ctx, r.scope.NetworkResourceGroup(), name)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get public IP %s in resource group %s", name, r.scope.NetworkResourceGroup())
+		}
+		if !converters.MapToTags(ip.Tags).HasOwned(r.scope.ClusterName()) {
+			r.scope.V(4).Info("Skipping deletion of public IP not owned by the cluster", "public ip", name)
+			continue
+		}
+		r.scope.V(2).Info("deleting removed API server public IP", "public ip", name)
+		if err := r.publicIPsClient.Delete(ctx, r.scope.NetworkResourceGroup(), name); err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", name, r.scope.NetworkResourceGroup())
+		}
+	}
+	return r.scope.UpdateLastAppliedAdditionalAPIServerIPNames()
+}
+
+// controlPlaneSecurityGroupNames returns the distinct security group names used by the control plane subnets,
+// leaving out the security groups of pre-existing subnets, which keep the security group they were provisioned with.
+func (r *azureClusterReconciler) controlPlaneSecurityGroupNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, subnet := range r.scope.ControlPlaneSubnets() {
+		if subnet.IsPreExisting(r.scope.Vnet(), r.scope.ClusterName()) {
+			r.scope.V(2).Info("Skipping network security group of pre-existing control plane subnet", "subnet-id", subnet.ID)
+			continue
+		}
+		if !seen[subnet.SecurityGroup.Name] {
+			seen[subnet.SecurityGroup.Name] = true
+			names = append(names, subnet.SecurityGroup.Name)
+		}
+	}
+	return names
+}
+
+// nodeSecurityGroupNames returns the distinct security group names used by the node subnets.
+func (r *azureClusterReconciler) nodeSecurityGroupNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, subnet := range r.scope.NodeSubnets() {
+		if !seen[subnet.SecurityGroup.Name] {
+			seen[subnet.SecurityGroup.Name] = true
+			names = append(names, subnet.SecurityGroup.Name)
+		}
+	}
+	return names
+}
+
+// routeTableSpecs returns the specs of the distinct route tables used by the subnets.
+// The routes of a route table shared by several subnets are taken from the first subnet defining them.
+func (r *azureClusterReconciler) routeTableSpecs() []*routetables.Spec {
+	var specs []*routetables.Spec
+	seen := make(map[string]*routetables.Spec)
+	subnets := append(r.scope.ControlPlaneSubnets(), r.scope.NodeSubnets()...)
+	for _, subnet := range subnets {
+		if subnet.RouteTable.Name == "" {
+			continue
+		}
+		spec, ok := seen[subnet.RouteTable.Name]
+		if !ok {
+			spec = &routetables.Spec{
+				Name:          subnet.RouteTable.Name,
+				ResourceGroup: routeTableResourceGroup(subnet.RouteTable),
+			}
+			seen[spec.Name] = spec
+			specs = append(specs, spec)
+		}
+		if len(spec.Routes) == 0 {
+			spec.Routes = subnet.RouteTable.Routes
+		}
+	}
+	return specs
+}
+
+// routeTableResourceGroup returns the resource group of a route table with an ID,
+// or an empty string for the cluster resource group.
+func routeTableResourceGroup(routeTable infrav1.RouteTable) string {
+	if routeTable.ID == "" {
+		return ""
+	}
+	resource, err := autorestazure.ParseResourceID(routeTable.ID)
+	if err != nil {
+		return ""
+	}
+	return resource.ResourceGroup
+}
+
+// CreateOrUpdateNetworkAPIServerIP creates or updates public ip name and dns name. The name of a pre-existing public IP
+// is its name in its resource ID, and its DNS name is read from Azure.
 func (r *azureClusterReconciler) createOrUpdateNetworkAPIServerIP() error {
-	if r.scope.Network().APIServerIP.Name == "" {
+	if r.scope.IsAPIServerIPPreExisting() {
+		id := r.scope.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPID
+		resource, err := autorestazure.ParseResourceID(id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse API server public IP ID %s", id)
+		}
+		r.scope.Network().APIServerIP.Name = resource.ResourceName
+	} else if r.scope.Network().APIServerIP.Name == "" {
 		h := fnv.New32a()
 		if _, err := h.Write([]byte(fmt.Sprintf("%s/%s/%s", r.scope.SubscriptionID(), r.scope.ResourceGroup(), r.scope.ClusterName()))); err != nil {
 			return errors.Wrapf(err, "failed to write hash sum for api server ip")
@@ -247,11 +659,263 @@ func (r *azureClusterReconciler) createOrUpdateNetworkAPIServerIP() error {
 		r.scope.Network().APIServerIP.Name = azure.GeneratePublicIPName(r.scope.ClusterName(), fmt.Sprintf("%x", h.Sum32()))
 	}
 
-	r.scope.Network().APIServerIP.DNSName = r.scope.GenerateFQDN()
+	// the name of a private API server is only advertised once its private DNS zone is reconciled
+	if !r.scope.IsAPIServerIPPreExisting() && !r.scope.IsAPIServerPrivate() {
+		r.scope.Network().APIServerIP.DNSName = r.scope.GenerateFQDN()
+	}
+
+	if r.scope.IsIPv6Enabled() {
+		if r.scope.Network().APIServerIPv6.Name == "" {
+			r.scope.Network().APIServerIPv6.Name = azure.GenerateIPv6PublicIPName(r.scope.Network().APIServerIP.Name)
+		}
+		r.scope.Network().APIServerIPv6.DNSName = r.scope.GenerateIPv6FQDN()
+	}
+	return nil
+}
+
+// setAPIServerIPAddresses reports the IPv4 and, for dual-stack clusters, IPv6 addresses of the API server public IPs in the network status.
+// Private API servers have no public IP, their name in the private DNS zone of the cluster is reported instead, as the
+// zone now exists.
+func (r *azureClusterReconciler) setAPIServerIPAddresses(ctx context.Context) error {
+	if r.scope.IsAPIServerPrivate() {
+		r.scope.Network().APIServerIP.DNSName = r.scope.GenerateFQDN()
+		return nil
+	}
+	ip, err := r.publicIPsClient.Get(ctx, r.scope.APIServerIPResourceGroup(), r.scope.Network().APIServerIP.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get public IP %s", r.scope.Network().APIServerIP.Name)
+	}
+	r.scope.Network().APIServerIP.ID = to.String(ip.ID)
+	r.scope.Network().APIServerIP.IPAddress = to.String(ip.IPAddress)
+
+	if r.scope.IsIPv6Enabled() {
+		ipv6, err := r.publicIPsClient.Get(ctx, r.scope.NetworkResourceGroup(), r.scope.Network().APIServerIPv6.Name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get public IP %s", r.scope.Network().APIServerIPv6.Name)
+		}
+		r.scope.Network().APIServerIPv6.ID = to.String(ipv6.ID)
+		r.scope.Network().APIServerIPv6.IPAddress = to.String(ipv6.IPAddress)
+	}
 	return nil
 }
 
+// validateAPIServerPublicIP checks that a pre-existing API server public IP exists, is of the Standard SKU and is in the
+// location of the cluster, and records its DNS name, which is the DNS name of the API server.
+func (r *azureClusterReconciler) validateAPIServerPublicIP(ctx context.Context) error {
+	if !r.scope.IsAPIServerIPPreExisting() {
+		return nil
+	}
+	name, resourceGroup := r.scope.Network().APIServerIP.Name, r.scope.APIServerIPResourceGroup()
+	ip, err := r.publicIPsClient.Get(ctx, resourceGroup, name)
+	if azure.ResourceNotFound(err) {
+		return errors.Errorf("API server public IP %s was not found in resource group %s", name, resourceGroup)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get public IP %s in resource group %s", name, resourceGroup)
+	}
+	if ip.Sku == nil || ip.Sku.Name != network.PublicIPAddressSkuNameStandard {
+		return errors.Errorf("API server public IP %s must be of the %s SKU", name, network.PublicIPAddressSkuNameStandard)
+	}
+	if location := to.String(ip.Location); !sameLocation(location, r.scope.Location()) {
+		return errors.Errorf("API server public IP %s is in location %s, not in the location %s of the cluster", name, location, r.scope.Location())
+	}
+	r.scope.Network().APIServerIP.ID = to.String(ip.ID)
+	r.scope.Network().APIServerIP.DNSName = publicIPDNSName(ip)
+	return nil
+}
+
+// publicIPDNSName returns the FQDN of a public IP, or its IP address when it has no DNS label.
+func publicIPDNSName(ip network.PublicIPAddress) string {
+	if ip.PublicIPAddressPropertiesFormat == nil {
+		return ""
+	}
+	if ip.DNSSettings != nil && to.String(ip.DNSSettings.Fqdn) != "" {
+		return to.String(ip.DNSSettings.Fqdn)
+	}
+	return to.String(ip.IPAddress)
+}
+
+// sameLocation returns true if both names are the same Azure location, which Azure reports in lowercase without spaces,
+// e.g. westus2 for West US 2.
+func sameLocation(a, b string) bool {
+	normalize := func(location string) string {
+		return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+	}
+	return normalize(a) == normalize(b)
+}
+
+// setLoadBalancerFrontendIPs records the IP addresses currently assigned to the frontends of the internal
+// and node outbound load balancers. They are read from Azure on every reconcile, so a recreated load
+// balancer reports its new addresses.
+func (r *azureClusterReconciler) setLoadBalancerFrontendIPs(ctx context.Context) error {
+	var internalLBIPAddress string
+	var internalLBZones []string
+	var nodeOutboundIPs []string
+	for _, lbSpec := range r.scope.LBSpecs() {
+		if lbSpec.Role != infrav1.InternalRole && lbSpec.Role != infrav1.NodeOutboundRole {
+			continue
+		}
+		lb, err := r.loadBalancersClient.Get(ctx, r.scope.NetworkResourceGroup(), lbSpec.Name)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get load balancer %s", lbSpec.Name)
+		}
+		if lb.LoadBalancerPropertiesFormat == nil || lb.FrontendIPConfigurations == nil {
+			continue
+		}
+		for _, frontend := range *lb.FrontendIPConfigurations {
+			properties := frontend.FrontendIPConfigurationPropertiesFormat
+			if properties == nil {
+				continue
+			}
+			if lbSpec.Role == infrav1.InternalRole {
+				if internalLBIPAddress == "" && properties.PrivateIPAddressVersion != network.IPv6 {
+					internalLBIPAddress = to.String(properties.PrivateIPAddress)
+					if frontend.Zones != nil {
+						internalLBZones = *frontend.Zones
+					}
+				}
+				continue
+			}
+			if properties.PublicIPAddress == nil || properties.PublicIPAddress.ID == nil {
+				continue
+			}
+			id := to.String(properties.PublicIPAddress.ID)
+			name := id[strings.LastIndex(id, "/")+1:]
+			ip, err := r.publicIPsClient.Get(ctx, r.scope.NetworkResourceGroup(), name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get public IP %s of load balancer %s", name, lbSpec.Name)
+			}
+			if to.String(ip.IPAddress) != "" {
+				nodeOutboundIPs = append(nodeOutboundIPs, to.String(ip.IPAddress))
+			}
+		}
+	}
+	r.scope.Network().InternalLBIPAddress = internalLBIPAddress
+	r.scope.Network().InternalLBZones = internalLBZones
+	r.scope.Network().NodeOutboundIPs = nodeOutboundIPs
+	return nil
+}
+
+// setVnetResourceID records the resource ID Azure reports for the virtual network of the cluster. The resource IDs
+// of the network status are read from Azure on every reconcile, so a recreated resource reports its new ID, and a
+// resource which is not found has none.
+func (r *azureClusterReconciler) setVnetResourceID(ctx context.Context) error {
+	vnet, err := r.vnetsClient.Get(ctx, r.scope.Vnet().ResourceGroup, r.scope.Vnet().Name)
+	if azure.ResourceNotFound(err) {
+		r.scope.Network().ResourceIDs.Vnet = ""
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get virtual network %s", r.scope.Vnet().Name)
+	}
+	r.scope.Network().ResourceIDs.Vnet = to.String(vnet.ID)
+	return nil
+}
+
+// setSubnetResourceIDs records the resource IDs Azure reports for the subnets of the cluster, including the subnets
+// of its bastion host and application gateway.
+func (r *azureClusterReconciler) setSubnetResourceIDs(ctx context.Context) error {
+	var names []string
+	for _, subnet := range r.scope.Subnets() {
+		names = append(names, subnet.Name)
+	}
+	if bastionSpec := r.scope.BastionSpec(); bastionSpec != nil {
+		names = append(names, bastionSpec.SubnetName)
+	}
+	if appGatewaySpec := r.scope.ApplicationGatewaySpec(); appGatewaySpec != nil {
+		names = append(names, appGatewaySpec.SubnetName)
+	}
+	ids, err := getResourceIDs(names, func(name string) (*string, error) {
+		subnet, err := r.subnetsClient.Get(ctx, r.scope.Vnet().ResourceGroup, r.scope.Vnet().Name, name)
+		return subnet.ID, errors.Wrapf(err, "failed to get subnet %s", name)
+	})
+	if err != nil {
+		return err
+	}
+	r.scope.Network().ResourceIDs.Subnets = ids
+	return nil
+}
+
+// setSecurityGroupResourceIDs records the resource IDs Azure reports for the network security groups of the cluster.
+func (r *azureClusterReconciler) setSecurityGroupResourceIDs(ctx context.Context) error {
+	names := append(r.controlPlaneSecurityGroupNames(), r.nodeSecurityGroupNames()...)
+	ids, err := getResourceIDs(names, func(name string) (*string, error) {
+		sg, err := r.securityGroupsClient.Get(ctx, r.scope.NetworkResourceGroup(), name)
+		return sg.ID, errors.Wrapf(err, "failed to get network security group %s", name)
+	})
+	if err != nil {
+		return err
+	}
+	r.scope.Network().ResourceIDs.SecurityGroups = ids
+	return nil
+}
+
+// setPublicIPResourceIDs records the resource IDs Azure reports for the public IPs created for the cluster.
+func (r *azureClusterReconciler) setPublicIPResourceIDs(ctx context.Context) error {
+	var names []string
+	for _, ipSpec := range r.scope.PublicIPSpecs() {
+		names = append(names, ipSpec.Name)
+	}
+	ids, err := getResourceIDs(names, func(name string) (*string, error) {
+		ip, err := r.publicIPsClient.Get(ctx, r.scope.NetworkResourceGroup(), name)
+		return ip.ID, errors.Wrapf(err, "failed to get public IP %s", name)
+	})
+	if err != nil {
+		return err
+	}
+	r.scope.Network().ResourceIDs.PublicIPs = ids
+	return nil
+}
+
+// setLoadBalancerResourceIDs records the resource IDs Azure reports for the load balancers of the cluster.
+func (r *azureClusterReconciler) setLoadBalancerResourceIDs(ctx context.Context) error {
+	var names []string
+	for _, lbSpec := range r.scope.LBSpecs() {
+		names = append(names, lbSpec.Name)
+	}
+	ids, err := getResourceIDs(names, func(name string) (*string, error) {
+		lb, err := r.loadBalancersClient.Get(ctx, r.scope.NetworkResourceGroup(), name)
+		return lb.ID, errors.Wrapf(err, "failed to get load balancer %s", name)
+	})
+	if err != nil {
+		return err
+	}
+	r.scope.Network().ResourceIDs.LoadBalancers = ids
+	return nil
+}
+
+// getResourceIDs gets the named resources and returns their IDs keyed by name, leaving out the resources not found.
+func getResourceIDs(names []string, get func(name string) (*string, error)) (map[string]string, error) {
+	var ids map[string]string
+	for _, name := range names {
+		id, err := get(name)
+		if azure.ResourceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if to.String(id) == "" {
+			continue
+		}
+		if ids == nil {
+			ids = make(map[string]string, len(names))
+		}
+		ids[name] = to.String(id)
+	}
+	return ids, nil
+}
+
 func (r *azureClusterReconciler) setFailureDomainsForLocation(ctx context.Context) error {
+	if r.scope.LoadBalancerSKU() == infrav1.SKUBasic {
+		// Basic load balancers cannot be zone-redundant, so no zones are exposed as failure domains
+		r.scope.V(2).Info("skipping failure domains for Basic load balancer SKU")
+		return nil
+	}
+
 	spec := &availabilityzones.Spec{}
 	zonesInterface, err := r.availabilityZonesSvc.Get(ctx, spec)
 	if err != nil {
@@ -259,25 +923,106 @@ func (r *azureClusterReconciler) setFailureDomainsForLocation(ctx context.Contex
 	}
 
 	zones := zonesInterface.([]string)
+	pinnedZones, err := r.scope.ControlPlaneZones(zones)
+	if err != nil {
+		return err
+	}
+	controlPlaneZones := make(map[string]bool, len(zones))
 	for _, zone := range zones {
-		r.scope.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
+		controlPlaneZones[zone] = true
+	}
+	excludedZones := make(map[string]bool)
+	if overrides := r.scope.AzureCluster.Spec.FailureDomains; overrides != nil {
+		if overrides.ControlPlaneVMSize != "" {
+			// only the zones where the control plane VM size can be deployed are eligible for control plane machines
+			vmSizeZonesInterface, err := r.availabilityZonesSvc.Get(ctx, &availabilityzones.Spec{VMSize: to.StringPtr(overrides.ControlPlaneVMSize)})
+			if err != nil {
+				return errors.Wrapf(err, "failed to get availability zones of control plane VM size %s", overrides.ControlPlaneVMSize)
+			}
+			controlPlaneZones = make(map[string]bool, len(zones))
+			for _, zone := range vmSizeZonesInterface.([]string) {
+				controlPlaneZones[zone] = true
+			}
+		}
+		for _, zone := range overrides.Include {
+			controlPlaneZones[zone] = true
+		}
+		for _, zone := range overrides.Exclude {
+			excludedZones[zone] = true
+		}
+	}
+	pinnedOrder := make(map[string]int, len(pinnedZones))
+	for i, zone := range pinnedZones {
+		if !controlPlaneZones[zone] {
+			return errors.Errorf("control plane VM size %s can't be deployed in control plane zone %s", r.scope.AzureCluster.Spec.FailureDomains.ControlPlaneVMSize, zone)
+		}
+		pinnedOrder[zone] = i + 1
+	}
+	if len(pinnedZones) > 0 {
+		// only the pinned zones are eligible for control plane machines
+		controlPlaneZones = make(map[string]bool, len(pinnedZones))
+		for _, zone := range pinnedZones {
+			controlPlaneZones[zone] = true
+		}
+	}
+
+	// the failure domains are rebuilt so the zones excluded since the last reconcile are removed
+	r.scope.AzureCluster.Status.FailureDomains = nil
+
+	if ppg := r.scope.ProximityPlacementGroupSpec(); len(zones) == 0 && (ppg == nil || ppg.FailureDomain == "") {
+		// without availability zones, the machines of each role are spread across the fault domains of an availability set
+		r.scope.V(2).Info("using availability sets as failure domains in a location without availability zones", "location", r.scope.Location())
+		r.scope.SetAvailabilitySetFailureDomains()
+		return nil
+	}
+
+	if ppg := r.scope.ProximityPlacementGroupSpec(); ppg != nil {
+		// the VMs of a proximity placement group are in a single data center, so they can't be spread across zones
+		if ppg.FailureDomain == "" {
+			r.scope.V(2).Info("skipping failure domains for proximity placement group without failure domain", "proximity-placement-group", ppg.Name)
+			return nil
+		}
+		available := false
+		for _, zone := range zones {
+			available = available || zone == ppg.FailureDomain
+		}
+		if !available {
+			return errors.Errorf("failure domain %s of proximity placement group %s is not an availability zone of location %s", ppg.FailureDomain, ppg.Name, r.scope.Location())
+		}
+		if !controlPlaneZones[ppg.FailureDomain] {
+			return errors.Errorf("failure domain %s of proximity placement group %s is not eligible for control plane machines", ppg.FailureDomain, ppg.Name)
+		}
+		if order, ok := pinnedOrder[ppg.FailureDomain]; ok {
+			r.scope.SetControlPlaneFailureDomain(ppg.FailureDomain, order)
+			return nil
+		}
+		r.scope.SetFailureDomain(ppg.FailureDomain, clusterv1.FailureDomainSpec{
 			ControlPlane: true,
 		})
+		return nil
+	}
+
+	for _, zone := range zones {
+		if excludedZones[zone] {
+			r.scope.V(2).Info("excluding availability zone from the failure domains", "zone", zone)
+			continue
+		}
+		if order, ok := pinnedOrder[zone]; ok {
+			r.scope.SetControlPlaneFailureDomain(zone, order)
+			continue
+		}
+		r.scope.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
+			ControlPlane: controlPlaneZones[zone],
+		})
 	}
 
 	return nil
 }
 
-func (r *azureClusterReconciler) generateControlPlaneIngressRules() infrav1.IngressRules {
-	apiPort := "6443"
-	if r.scope.Cluster.Spec.ClusterNetwork.APIServerPort != nil {
-		apiPort = strconv.Itoa(
-			int(
-				to.Int32(r.scope.Cluster.Spec.ClusterNetwork.APIServerPort),
-			),
-		)
-	}
-
+// legacyControlPlaneIngressRules returns the default ingress rules written to the control plane subnet spec by
+// earlier releases. The security group service now generates the default rules itself.
+func (r *azureClusterReconciler) legacyControlPlaneIngressRules() infrav1.IngressRules {
+	apiPort := strconv.Itoa(int(r.scope.APIServerPort()))
 	return infrav1.IngressRules{
 		&infrav1.IngressRule{
 			Name:             "allow_ssh",
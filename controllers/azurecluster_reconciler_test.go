@@ -0,0 +1,363 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	azure "sigs.k8s.io/cluster-api-provider-azure/cloud"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/mocks"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/routetables/mock_routetables"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/securitygroups/mock_securitygroups"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/subnets/mock_subnets"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualnetworks/mock_virtualnetworks"
+)
+
+func TestValidateAPIServerPublicIP(t *testing.T) {
+	const publicIPID = "/subscriptions/123/resourceGroups/my-ips/providers/Microsoft.Network/publicIPAddresses/my-static-ip"
+	existingIP := func(sku network.PublicIPAddressSkuName, location, fqdn string) network.PublicIPAddress {
+		ip := network.PublicIPAddress{
+			ID:       to.StringPtr(publicIPID),
+			Sku:      &network.PublicIPAddressSku{Name: sku},
+			Location: to.StringPtr(location),
+			PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+				IPAddress: to.StringPtr("20.1.2.3"),
+			},
+		}
+		if fqdn != "" {
+			ip.DNSSettings = &network.PublicIPAddressDNSSettings{Fqdn: to.StringPtr(fqdn)}
+		}
+		return ip
+	}
+
+	tests := []struct {
+		name            string
+		publicIPID      string
+		expect          func(m *mock_publicips.MockClientMockRecorder)
+		expectedError   string
+		expectedDNSName string
+	}{
+		{
+			name:            "public IP created for the cluster",
+			expect:          func(m *mock_publicips.MockClientMockRecorder) {},
+			expectedDNSName: "",
+		},
+		{
+			name:       "pre-existing public IP with a DNS label",
+			publicIPID: publicIPID,
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-ips", "my-static-ip").Return(existingIP(network.PublicIPAddressSkuNameStandard, "westus2", "api.westus2.cloudapp.azure.com"), nil)
+			},
+			expectedDNSName: "api.westus2.cloudapp.azure.com",
+		},
+		{
+			name:       "pre-existing public IP without a DNS label",
+			publicIPID: publicIPID,
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-ips", "my-static-ip").Return(existingIP(network.PublicIPAddressSkuNameStandard, "West US 2", ""), nil)
+			},
+			expectedDNSName: "20.1.2.3",
+		},
+		{
+			name:       "pre-existing public IP of the Basic SKU",
+			publicIPID: publicIPID,
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-ips", "my-static-ip").Return(existingIP(network.PublicIPAddressSkuNameBasic, "westus2", ""), nil)
+			},
+			expectedError: "API server public IP my-static-ip must be of the Standard SKU",
+		},
+		{
+			name:       "pre-existing public IP in another location",
+			publicIPID: publicIPID,
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-ips", "my-static-ip").Return(existingIP(network.PublicIPAddressSkuNameStandard, "eastus", ""), nil)
+			},
+			expectedError: "API server public IP my-static-ip is in location eastus, not in the location westus2 of the cluster",
+		},
+		{
+			name:       "missing pre-existing public IP",
+			publicIPID: publicIPID,
+			expect: func(m *mock_publicips.MockClientMockRecorder) {
+				m.Get(gomock.Any(), "my-ips", "my-static-ip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+			},
+			expectedError: "API server public IP my-static-ip was not found in resource group my-ips",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+			tc.expect(publicIPsMock.EXPECT())
+
+			clusterScope := newPlannerTestClusterScope(t)
+			clusterScope.AzureCluster.Spec.NetworkSpec.APIServerLB.PublicIPID = tc.publicIPID
+			r := newAzureClusterReconciler(clusterScope)
+			r.publicIPsClient = publicIPsMock
+			g.Expect(r.createOrUpdateNetworkAPIServerIP()).To(Succeed())
+			if tc.publicIPID == "" {
+				// the DNS name of a public IP created for the cluster is generated
+				tc.expectedDNSName = clusterScope.GenerateFQDN()
+			}
+
+			err := r.validateAPIServerPublicIP(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(clusterScope.Network().APIServerIP.DNSName).To(Equal(tc.expectedDNSName))
+			if tc.publicIPID != "" {
+				g.Expect(clusterScope.Network().APIServerIP.Name).To(Equal("my-static-ip"))
+				for _, ip := range clusterScope.PublicIPSpecs() {
+					g.Expect(ip.Name).NotTo(Equal("my-static-ip"))
+				}
+				for _, lbSpec := range clusterScope.LBSpecs() {
+					if lbSpec.Role == infrav1.APIServerRole {
+						g.Expect(lbSpec.PublicIPResourceGroup).To(Equal("my-ips"))
+					}
+				}
+				g.Expect(clusterScope.ControlPlaneEndpoint().Host).To(Equal(tc.expectedDNSName))
+			}
+		})
+	}
+}
+
+func TestPrivateAPIServerDNSName(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope := newPlannerTestClusterScope(t)
+	clusterScope.AzureCluster.Spec.NetworkSpec.APIServerLB.Type = infrav1.Internal
+	r := newAzureClusterReconciler(clusterScope)
+
+	// the name of a private API server isn't advertised before its private DNS zone is reconciled
+	g.Expect(r.createOrUpdateNetworkAPIServerIP()).To(Succeed())
+	g.Expect(clusterScope.Network().APIServerIP.DNSName).To(BeEmpty())
+
+	g.Expect(r.setAPIServerIPAddresses(context.TODO())).To(Succeed())
+	g.Expect(clusterScope.Network().APIServerIP.DNSName).To(Equal("apiserver.my-cluster.capz.io"))
+}
+
+func TestSetNetworkResourceIDs(t *testing.T) {
+	const idPrefix = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network"
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	vnetsMock := mock_virtualnetworks.NewMockClient(mockCtrl)
+	subnetsMock := mock_subnets.NewMockClient(mockCtrl)
+	securityGroupsMock := mock_securitygroups.NewMockClient(mockCtrl)
+	vnetsMock.EXPECT().Get(gomock.Any(), "my-rg", "my-vnet").Return(network.VirtualNetwork{ID: to.StringPtr(idPrefix + "/virtualNetworks/my-vnet")}, nil)
+	subnetsMock.EXPECT().Get(gomock.Any(), "my-rg", "my-vnet", "cp-subnet").Return(network.Subnet{ID: to.StringPtr(idPrefix + "/virtualNetworks/my-vnet/subnets/cp-subnet")}, nil)
+	subnetsMock.EXPECT().Get(gomock.Any(), "my-rg", "my-vnet", "node-subnet").Return(network.Subnet{}, notFound)
+	securityGroupsMock.EXPECT().Get(gomock.Any(), "my-rg", "cp-nsg").Return(network.SecurityGroup{ID: to.StringPtr(idPrefix + "/networkSecurityGroups/cp-nsg")}, nil)
+	securityGroupsMock.EXPECT().Get(gomock.Any(), "my-rg", "node-nsg").Return(network.SecurityGroup{ID: to.StringPtr(idPrefix + "/networkSecurityGroups/node-nsg-2")}, nil)
+
+	clusterScope := newPlannerTestClusterScope(t)
+	clusterScope.Network().ResourceIDs = infrav1.NetworkResourceIDs{
+		Vnet:           idPrefix + "/virtualNetworks/my-vnet",
+		Subnets:        map[string]string{"node-subnet": idPrefix + "/virtualNetworks/my-vnet/subnets/node-subnet"},
+		SecurityGroups: map[string]string{"node-nsg": idPrefix + "/networkSecurityGroups/node-nsg"},
+	}
+	r := newAzureClusterReconciler(clusterScope)
+	r.vnetsClient = vnetsMock
+	r.subnetsClient = subnetsMock
+	r.securityGroupsClient = securityGroupsMock
+
+	g.Expect(r.setVnetResourceID(context.TODO())).To(Succeed())
+	g.Expect(r.setSubnetResourceIDs(context.TODO())).To(Succeed())
+	g.Expect(r.setSecurityGroupResourceIDs(context.TODO())).To(Succeed())
+
+	// the subnet not found is removed, and the recreated security group reports its new ID
+	g.Expect(clusterScope.Network().ResourceIDs).To(Equal(infrav1.NetworkResourceIDs{
+		Vnet:    idPrefix + "/virtualNetworks/my-vnet",
+		Subnets: map[string]string{"cp-subnet": idPrefix + "/virtualNetworks/my-vnet/subnets/cp-subnet"},
+		SecurityGroups: map[string]string{
+			"cp-nsg":   idPrefix + "/networkSecurityGroups/cp-nsg",
+			"node-nsg": idPrefix + "/networkSecurityGroups/node-nsg-2",
+		},
+	}))
+}
+
+func TestReconcileSecurityGroupsRecordsTheRulesOfEachSecurityGroup(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	rule := func(name string) *infrav1.SecurityRule {
+		return &infrav1.SecurityRule{
+			Name:             name,
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Priority:         200,
+			DestinationPorts: to.StringPtr("50000"),
+		}
+	}
+
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	securityGroupsMock := mock_securitygroups.NewMockClient(mockCtrl)
+	securityGroupsMock.EXPECT().Get(gomock.Any(), "my-rg", gomock.Any()).Return(network.SecurityGroup{}, notFound).Times(3)
+	securityGroupsMock.EXPECT().CreateOrUpdate(gomock.Any(), "my-rg", gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	clusterScope := newPlannerTestClusterScope(t)
+	nodeSubnet := clusterScope.NodeSubnet()
+	nodeSubnet.SecurityGroup.SecurityRules = infrav1.SecurityRules{rule("allow_node_port")}
+	clusterScope.AzureCluster.Spec.NetworkSpec.Subnets = append(clusterScope.AzureCluster.Spec.NetworkSpec.Subnets, &infrav1.SubnetSpec{
+		Name:          "node-subnet-2",
+		Role:          infrav1.SubnetNode,
+		CidrBlock:     "10.2.0.0/16",
+		SecurityGroup: infrav1.SecurityGroup{Name: "node-nsg-2", SecurityRules: infrav1.SecurityRules{rule("allow_node_2_port")}},
+	})
+	r := newAzureClusterReconciler(clusterScope)
+	r.securityGroupSvc = &securitygroups.Service{Scope: clusterScope, Client: securityGroupsMock}
+
+	g.Expect(r.reconcileSecurityGroups(context.TODO())).To(Succeed())
+
+	// the rules of every security group are kept in the annotation, none of them overwrites the others
+	applied := map[string][]string{}
+	g.Expect(json.Unmarshal([]byte(clusterScope.AzureCluster.Annotations[securitygroups.SecurityRulesLastAppliedAnnotation]), &applied)).To(Succeed())
+	g.Expect(applied).To(HaveKey("cp-nsg"))
+	g.Expect(applied).To(HaveKeyWithValue("node-nsg", []string{"allow_node_port"}))
+	g.Expect(applied).To(HaveKeyWithValue("node-nsg-2", []string{"allow_node_2_port"}))
+}
+
+func TestReconcileRouteTablesAssociatesEachSubnetWithItsRouteTable(t *testing.T) {
+	routeTable := func(name string) network.RouteTable {
+		return network.RouteTable{
+			Name: to.StringPtr(name),
+			ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/" + name),
+		}
+	}
+
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	routeTablesMock := mock_routetables.NewMockClient(mockCtrl)
+	routeTablesMock.EXPECT().Get(gomock.Any(), "my-rg", "cp-rt").Return(routeTable("cp-rt"), nil)
+	routeTablesMock.EXPECT().Get(gomock.Any(), "my-rg", "node-rt").Return(routeTable("node-rt"), nil)
+
+	clusterScope := newPlannerTestClusterScope(t)
+	clusterScope.ControlPlaneSubnet().RouteTable.Name = "cp-rt"
+	clusterScope.NodeSubnet().RouteTable.Name = "node-rt"
+	r := newAzureClusterReconciler(clusterScope)
+	r.routeTableSvc = &routetables.Service{Scope: clusterScope, Client: routeTablesMock}
+
+	g.Expect(r.reconcileRouteTables(context.TODO())).To(Succeed())
+
+	// every subnet keeps the route table it was associated with
+	g.Expect(clusterScope.ControlPlaneSubnet().RouteTable.ID).To(Equal(to.String(routeTable("cp-rt").ID)))
+	g.Expect(clusterScope.NodeSubnet().RouteTable.ID).To(Equal(to.String(routeTable("node-rt").ID)))
+}
+
+func TestDeleteRemovedAPIServerIPs(t *testing.T) {
+	notFound := autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")
+	owned := map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")}
+
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+	publicIPsMock.EXPECT().Get(gomock.Any(), "my-rg", "my-cluster-api-2").Return(network.PublicIPAddress{Tags: owned}, nil)
+	publicIPsMock.EXPECT().Delete(gomock.Any(), "my-rg", "my-cluster-api-2").Return(nil)
+	publicIPsMock.EXPECT().Get(gomock.Any(), "my-rg", "shared-ip").Return(network.PublicIPAddress{}, nil)
+	publicIPsMock.EXPECT().Get(gomock.Any(), "my-rg", "deleted-ip").Return(network.PublicIPAddress{}, notFound)
+
+	clusterScope := newPlannerTestClusterScope(t)
+	clusterScope.AzureCluster.Annotations = map[string]string{
+		azure.AdditionalAPIServerIPsLastAppliedAnnotation: `["my-cluster-api-2","my-cluster-api-3","shared-ip","deleted-ip"]`,
+	}
+	clusterScope.AzureCluster.Spec.NetworkSpec.APIServerLB.AdditionalPublicIPNames = []string{"my-cluster-api-3"}
+	r := newAzureClusterReconciler(clusterScope)
+	r.publicIPsClient = publicIPsMock
+
+	// only the removed public IP owned by the cluster is deleted, and the current public IPs are recorded
+	g.Expect(r.deleteRemovedAPIServerIPs(context.TODO())).To(Succeed())
+	g.Expect(clusterScope.AzureCluster.Annotations).To(HaveKeyWithValue(azure.AdditionalAPIServerIPsLastAppliedAnnotation, `["my-cluster-api-3"]`))
+
+	clusterScope.AzureCluster.Spec.NetworkSpec.APIServerLB.AdditionalPublicIPNames = nil
+	publicIPsMock.EXPECT().Get(gomock.Any(), "my-rg", "my-cluster-api-3").Return(network.PublicIPAddress{Tags: owned}, nil)
+	publicIPsMock.EXPECT().Delete(gomock.Any(), "my-rg", "my-cluster-api-3").Return(nil)
+	g.Expect(r.deleteRemovedAPIServerIPs(context.TODO())).To(Succeed())
+	g.Expect(clusterScope.AzureCluster.Annotations).NotTo(HaveKey(azure.AdditionalAPIServerIPsLastAppliedAnnotation))
+}
+
+func TestDeletePublicIPsFromPreExistingResourceGroup(t *testing.T) {
+	owned := map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned")}
+
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clusterScope := newPlannerTestClusterScope(t)
+	clusterScope.SetResourceGroupID("/subscriptions/123/resourceGroups/my-rg")
+	r := newAzureClusterReconciler(clusterScope)
+
+	// the public IPs owned by the cluster are deleted with it, even from a resource group CAPZ doesn't delete
+	publicIPsMock := mock_publicips.NewMockClient(mockCtrl)
+	for _, ip := range clusterScope.PublicIPSpecs() {
+		publicIPsMock.EXPECT().Get(gomock.Any(), "my-rg", ip.Name).Return(network.PublicIPAddress{Tags: owned}, nil)
+		publicIPsMock.EXPECT().Delete(gomock.Any(), "my-rg", ip.Name).Return(nil)
+	}
+	r.publicIPsClient = publicIPsMock
+	r.publicIPSvc = &publicips.Service{Scope: clusterScope, Client: publicIPsMock}
+
+	g.Expect(clusterScope.PublicIPSpecs()).NotTo(BeEmpty())
+	g.Expect(r.deletionSteps()[publicIPsResource](context.TODO())).To(Succeed())
+}
+
+func TestSetFailureDomainsWithoutAvailabilityZones(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	zonesMock := mocks.NewMockGetterService(mockCtrl)
+	zonesMock.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]string{}, nil)
+
+	clusterScope := newPlannerTestClusterScope(t)
+	clusterScope.SetFailureDomain("1", clusterv1.FailureDomainSpec{ControlPlane: true})
+	r := newAzureClusterReconciler(clusterScope)
+	r.availabilityZonesSvc = zonesMock
+
+	g.Expect(r.setFailureDomainsForLocation(context.TODO())).To(Succeed())
+	g.Expect(clusterScope.AzureCluster.Status.FailureDomains).To(HaveLen(2))
+	g.Expect(clusterScope.AzureCluster.Status.FailureDomains).To(HaveKeyWithValue("my-cluster-control-plane-as", clusterv1.FailureDomainSpec{
+		ControlPlane: true,
+		Attributes:   map[string]string{infrav1.AvailabilitySetAttribute: "true"},
+	}))
+	g.Expect(clusterScope.AvailabilitySetSpec(infrav1.Node).Name).To(Equal("my-cluster-node-as"))
+}
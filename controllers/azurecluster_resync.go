@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+// resyncPeriod returns the resync period set by the annotation of an AzureCluster, bounded to MinResyncPeriod and
+// MaxResyncPeriod, or 0 when the annotation is unset or invalid, and the cluster is resynced at the sync period of the
+// controller.
+func resyncPeriod(azureCluster *infrav1.AzureCluster) time.Duration {
+	value, ok := azureCluster.Annotations[infrav1.ResyncPeriodAnnotation]
+	if !ok {
+		return 0
+	}
+	period, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	if period < infrav1.MinResyncPeriod {
+		return infrav1.MinResyncPeriod
+	}
+	if period > infrav1.MaxResyncPeriod {
+		return infrav1.MaxResyncPeriod
+	}
+	return period
+}
+
+// skipPeriodicResync filters out the periodic resyncs of the AzureClusters with a resync period, which are requeued at
+// their own period instead. A resync is an update event of an unchanged resource version.
+func skipPeriodicResync() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.MetaOld == nil || e.MetaNew == nil || e.MetaOld.GetResourceVersion() != e.MetaNew.GetResourceVersion() {
+				return true
+			}
+			azureCluster, ok := e.ObjectNew.(*infrav1.AzureCluster)
+			return !ok || resyncPeriod(azureCluster) == 0
+		},
+	}
+}
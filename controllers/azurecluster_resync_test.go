@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+func TestResyncPeriod(t *testing.T) {
+	testcases := []struct {
+		name        string
+		annotations map[string]string
+		expected    time.Duration
+	}{
+		{
+			name:     "no resync period",
+			expected: 0,
+		},
+		{
+			name:        "resync period",
+			annotations: map[string]string{infrav1.ResyncPeriodAnnotation: "5m"},
+			expected:    5 * time.Minute,
+		},
+		{
+			name:        "invalid resync period",
+			annotations: map[string]string{infrav1.ResyncPeriodAnnotation: "soon"},
+			expected:    0,
+		},
+		{
+			name:        "resync period below the minimum",
+			annotations: map[string]string{infrav1.ResyncPeriodAnnotation: "1s"},
+			expected:    infrav1.MinResyncPeriod,
+		},
+		{
+			name:        "resync period above the maximum",
+			annotations: map[string]string{infrav1.ResyncPeriodAnnotation: "720h"},
+			expected:    infrav1.MaxResyncPeriod,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			azureCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			g.Expect(resyncPeriod(azureCluster)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestSkipPeriodicResync(t *testing.T) {
+	g := NewWithT(t)
+	updateEvent := func(oldVersion, newVersion string, annotations map[string]string) event.UpdateEvent {
+		oldCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{ResourceVersion: oldVersion, Annotations: annotations}}
+		newCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{ResourceVersion: newVersion, Annotations: annotations}}
+		return event.UpdateEvent{MetaOld: oldCluster, ObjectOld: oldCluster, MetaNew: newCluster, ObjectNew: newCluster}
+	}
+	withPeriod := map[string]string{infrav1.ResyncPeriodAnnotation: "1h"}
+
+	predicate := skipPeriodicResync()
+	g.Expect(predicate.Update(updateEvent("1", "1", nil))).To(BeTrue())
+	g.Expect(predicate.Update(updateEvent("1", "1", withPeriod))).To(BeFalse())
+	g.Expect(predicate.Update(updateEvent("1", "2", withPeriod))).To(BeTrue())
+}
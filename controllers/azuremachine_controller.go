@@ -274,6 +274,17 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 		return reconcile.Result{}, err
 	}
 
+	if vm.Evicted {
+		// the failure reason lets Cluster API remediate the machine
+		machineScope.SetNotReady()
+		machineScope.Info("Spot VM was evicted", "name", vm.Name)
+		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "SpotVMEvicted", "Azure Spot VM was evicted")
+		machineScope.SetFailureReason(capierrors.UpdateMachineError)
+		machineScope.SetFailureMessage(errors.Errorf("Azure Spot VM %s was evicted", vm.Name))
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMEvictedReason, clusterv1.ConditionSeverityWarning, "")
+		return reconcile.Result{}, nil
+	}
+
 	// Make sure Spec.ProviderID is always set.
 	machineScope.SetProviderID(fmt.Sprintf("azure:///%s", vm.ID))
 
@@ -281,6 +292,8 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 
 	machineScope.SetAddresses(vm.Addresses)
 
+	machineScope.SetOSDisk(vm.OSDisk)
+
 	// Proceed to reconcile the AzureMachine state.
 	machineScope.SetVMState(vm.State)
 
@@ -325,6 +338,22 @@ func (r *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineSco
 		return reconcile.Result{}, errors.Errorf("failed to ensure tags: %+v", err)
 	}
 
+	// Ensure that the user-assigned identities are correct.
+	err = r.reconcileUserAssignedIdentities(ctx, machineScope, clusterScope)
+	if err != nil {
+		r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "UserAssignedIdentitiesIncorrect", errors.Wrap(err, "failed to ensure user-assigned identities").Error())
+		return reconcile.Result{}, errors.Wrap(err, "failed to ensure user-assigned identities")
+	}
+
+	// Ensure that the extensions are installed, once the VM is running.
+	if vm.State == infrav1.VMStateSucceeded {
+		err = r.reconcileVMExtensions(ctx, machineScope, clusterScope)
+		if err != nil {
+			r.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "VMExtensionsFailed", errors.Wrap(err, "failed to ensure VM extensions").Error())
+			return reconcile.Result{}, errors.Wrap(err, "failed to ensure VM extensions")
+		}
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -336,6 +365,12 @@ func (r *AzureMachineReconciler) getOrCreate(ctx context.Context, scope *scope.M
 		return nil, err
 	}
 
+	if vm == nil && scope.GetVMID() != nil && scope.SpotVMOptions() != nil {
+		// Azure deletes a Spot VM with the Delete eviction policy when it is evicted. It isn't recreated,
+		// so the machine is reported as evicted and replaced by Cluster API.
+		return &infrav1.VM{Name: scope.Name(), Evicted: true}, nil
+	}
+
 	if vm == nil {
 		// Create a new VM if we couldn't find a running VM.
 		vm, err = ams.Reconcile(ctx)
@@ -344,6 +379,12 @@ func (r *AzureMachineReconciler) getOrCreate(ctx context.Context, scope *scope.M
 			conditions.MarkFalse(scope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
 			return nil, errors.Wrapf(err, "failed to reconcile AzureMachine")
 		}
+	} else if vm.State == infrav1.VMStateSucceeded {
+		// data disks added to the spec are attached to the running VM without recreating it
+		if err := ams.ReconcileDataDisks(ctx); err != nil {
+			r.Recorder.Eventf(scope.AzureMachine, corev1.EventTypeWarning, "Error attaching data disks", errors.Wrapf(err, "failed to reconcile data disks").Error())
+			return nil, errors.Wrapf(err, "failed to reconcile data disks")
+		}
 	}
 
 	return vm, nil
@@ -277,6 +277,12 @@ func TestConditions(t *testing.T) {
 			azureCluster := &infrav1.AzureCluster{
 				Spec: infrav1.AzureClusterSpec{
 					SubscriptionID: "123",
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{Name: "my-subnet-cp", Role: infrav1.SubnetControlPlane},
+							{Name: "my-subnet-node", Role: infrav1.SubnetNode},
+						},
+					},
 				},
 			}
 			initObjects := []runtime.Object{
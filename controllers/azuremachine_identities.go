@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
+)
+
+const (
+	// UserAssignedIdentitiesLastAppliedAnnotation is the key for the machine object annotation
+	// which tracks the user-assigned identities that the machine actuator assigned to the VM.
+	// Only these identities are removed from the VM when they are removed from the AzureMachine,
+	// the identities assigned to the VM by other means are left untouched.
+	UserAssignedIdentitiesLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-user-assigned-identities"
+)
+
+// Ensure that the user-assigned identities of an existing machine are correct
+func (r *AzureMachineReconciler) reconcileUserAssignedIdentities(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) error {
+	annotation, err := r.machineAnnotationJSON(machineScope.AzureMachine, UserAssignedIdentitiesLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	changed, added, removed, newAnnotation := UserAssignedIdentitiesChanged(annotation, machineScope.UserAssignedIdentities())
+	if !changed {
+		return nil
+	}
+
+	svc := virtualmachines.NewService(clusterScope, machineScope)
+	if err := svc.ValidateUserAssignedIdentities(ctx, added); err != nil {
+		return err
+	}
+	vm, err := svc.Client.Get(ctx, clusterScope.ResourceGroup(), machineScope.Name())
+	if err != nil {
+		return errors.Wrapf(err, "failed to query AzureMachine VM")
+	}
+
+	systemAssigned := false
+	identities := map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue{}
+	if vm.Identity != nil {
+		systemAssigned = strings.Contains(string(vm.Identity.Type), string(compute.ResourceIdentityTypeSystemAssigned))
+		for id, value := range vm.Identity.UserAssignedIdentities {
+			identities[id] = value
+		}
+	}
+	updated := false
+	for _, id := range added {
+		if identityKey(identities, id) == "" {
+			identities[id] = &compute.VirtualMachineIdentityUserAssignedIdentitiesValue{}
+			updated = true
+		}
+	}
+	removedKeys := []string{}
+	for _, id := range removed {
+		if key := identityKey(identities, id); key != "" {
+			delete(identities, key)
+			removedKeys = append(removedKeys, key)
+			updated = true
+		}
+	}
+
+	if updated {
+		machineScope.Info("Updating user-assigned identities on AzureMachine")
+		// Only the identity of the VM is patched, the other properties of the VM are left untouched.
+		identity := vmIdentity(systemAssigned, identities)
+		if identity.UserAssignedIdentities != nil {
+			// A PATCH merges the user-assigned identities, the removed ones must be set to null.
+			for _, key := range removedKeys {
+				identity.UserAssignedIdentities[key] = nil
+			}
+		}
+		update := compute.VirtualMachineUpdate{Identity: identity}
+		if err := svc.Client.Update(ctx, clusterScope.ResourceGroup(), machineScope.Name(), update); err != nil {
+			return errors.Wrapf(err, "cannot update VM user-assigned identities")
+		}
+	}
+
+	// We also need to update the annotation if anything changed.
+	return r.updateMachineAnnotationJSON(machineScope.AzureMachine, UserAssignedIdentitiesLastAppliedAnnotation, newAnnotation)
+}
+
+// UserAssignedIdentitiesChanged determines which user-assigned identities to add and which to remove.
+func UserAssignedIdentitiesChanged(annotation map[string]interface{}, src []string) (bool, []string, []string, map[string]interface{}) {
+	added := []string{}
+	removed := []string{}
+	newAnnotation := map[string]interface{}{}
+
+	for _, id := range src {
+		newAnnotation[id] = true
+		if _, ok := annotation[id]; !ok {
+			added = append(added, id)
+		}
+	}
+	for id := range annotation {
+		if _, ok := newAnnotation[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+
+	return len(added) > 0 || len(removed) > 0, added, removed, newAnnotation
+}
+
+// identityKey returns the key of the user-assigned identity in the identities of a VM, which Azure
+// returns with a different casing of the resource ID, or an empty string if the VM doesn't have it.
+func identityKey(identities map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue, id string) string {
+	for key := range identities {
+		if strings.EqualFold(key, id) {
+			return key
+		}
+	}
+	return ""
+}
+
+// vmIdentity returns the identity of a VM with the given user-assigned identities.
+func vmIdentity(systemAssigned bool, identities map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue) *compute.VirtualMachineIdentity {
+	switch {
+	case systemAssigned && len(identities) > 0:
+		return &compute.VirtualMachineIdentity{Type: compute.ResourceIdentityTypeSystemAssignedUserAssigned, UserAssignedIdentities: identities}
+	case systemAssigned:
+		return &compute.VirtualMachineIdentity{Type: compute.ResourceIdentityTypeSystemAssigned}
+	case len(identities) > 0:
+		return &compute.VirtualMachineIdentity{Type: compute.ResourceIdentityTypeUserAssigned, UserAssignedIdentities: identities}
+	default:
+		return &compute.VirtualMachineIdentity{Type: compute.ResourceIdentityTypeNone}
+	}
+}
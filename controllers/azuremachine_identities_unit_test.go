@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	. "github.com/onsi/gomega"
+)
+
+func TestUserAssignedIdentitiesChanged(t *testing.T) {
+	g := NewWithT(t)
+
+	var tests = map[string]struct {
+		annotation             map[string]interface{}
+		src                    []string
+		expectedResult         bool
+		expectedAdded          []string
+		expectedRemoved        []string
+		expectedNewAnnotations map[string]interface{}
+	}{
+		"identities are the same": {
+			annotation:             map[string]interface{}{"id1": true},
+			src:                    []string{"id1"},
+			expectedResult:         false,
+			expectedAdded:          []string{},
+			expectedRemoved:        []string{},
+			expectedNewAnnotations: map[string]interface{}{"id1": true},
+		}, "identity added": {
+			annotation:             map[string]interface{}{"id1": true},
+			src:                    []string{"id1", "id2"},
+			expectedResult:         true,
+			expectedAdded:          []string{"id2"},
+			expectedRemoved:        []string{},
+			expectedNewAnnotations: map[string]interface{}{"id1": true, "id2": true},
+		}, "identities removed": {
+			annotation:             map[string]interface{}{"id1": true, "id3": true, "id2": true},
+			src:                    []string{"id1"},
+			expectedResult:         true,
+			expectedAdded:          []string{},
+			expectedRemoved:        []string{"id2", "id3"},
+			expectedNewAnnotations: map[string]interface{}{"id1": true},
+		}, "nothing applied yet": {
+			annotation:             nil,
+			src:                    []string{"id1"},
+			expectedResult:         true,
+			expectedAdded:          []string{"id1"},
+			expectedRemoved:        []string{},
+			expectedNewAnnotations: map[string]interface{}{"id1": true},
+		},
+	}
+
+	for name, test := range tests {
+		changed, added, removed, newAnnotation := UserAssignedIdentitiesChanged(test.annotation, test.src)
+		g.Expect(changed).To(Equal(test.expectedResult), name)
+		g.Expect(added).To(Equal(test.expectedAdded), name)
+		g.Expect(removed).To(Equal(test.expectedRemoved), name)
+		g.Expect(newAnnotation).To(Equal(test.expectedNewAnnotations), name)
+	}
+}
+
+func TestVMIdentity(t *testing.T) {
+	g := NewWithT(t)
+
+	identities := map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue{"id1": {}}
+
+	g.Expect(vmIdentity(true, identities).Type).To(Equal(compute.ResourceIdentityTypeSystemAssignedUserAssigned))
+	g.Expect(vmIdentity(true, nil).Type).To(Equal(compute.ResourceIdentityTypeSystemAssigned))
+	g.Expect(vmIdentity(false, identities).Type).To(Equal(compute.ResourceIdentityTypeUserAssigned))
+	g.Expect(vmIdentity(false, nil).Type).To(Equal(compute.ResourceIdentityTypeNone))
+	g.Expect(vmIdentity(false, nil).UserAssignedIdentities).To(BeNil())
+}
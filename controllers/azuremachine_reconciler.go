@@ -18,7 +18,6 @@ package controllers
 
 import (
 	"context"
-	"encoding/base64"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
@@ -28,6 +27,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/availabilityzones"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/disks"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachines"
@@ -40,6 +40,7 @@ type azureMachineService struct {
 	machineScope         *scope.MachineScope
 	clusterScope         *scope.ClusterScope
 	availabilityZonesSvc azure.GetterService
+	inboundNatRulesSvc   azure.Service
 	networkInterfacesSvc azure.Service
 	virtualMachinesSvc   *virtualmachines.Service
 	disksSvc             azure.Service
@@ -52,6 +53,7 @@ func newAzureMachineService(machineScope *scope.MachineScope, clusterScope *scop
 		machineScope:         machineScope,
 		clusterScope:         clusterScope,
 		availabilityZonesSvc: availabilityzones.NewService(clusterScope),
+		inboundNatRulesSvc:   inboundnatrules.NewService(machineScope),
 		networkInterfacesSvc: networkinterfaces.NewService(machineScope),
 		virtualMachinesSvc:   virtualmachines.NewService(clusterScope, machineScope),
 		disksSvc:             disks.NewService(machineScope),
@@ -61,17 +63,51 @@ func newAzureMachineService(machineScope *scope.MachineScope, clusterScope *scop
 
 // Reconcile reconciles all the services in pre determined order
 func (s *azureMachineService) Reconcile(ctx context.Context) (*infrav1.VM, error) {
-	err := s.publicIPsSvc.Reconcile(ctx)
+	if err := s.machineScope.ValidateSpotVMOptions(); err != nil {
+		return nil, errors.Wrap(err, "invalid Spot VM options")
+	}
+
+	if err := s.machineScope.ValidateSubnet(); err != nil {
+		return nil, errors.Wrap(err, "invalid subnet")
+	}
+
+	if err := s.machineScope.ValidateProximityPlacementGroup(s.clusterScope.ProximityPlacementGroupSpec()); err != nil {
+		return nil, errors.Wrap(err, "invalid proximity placement group")
+	}
+
+	if err := s.machineScope.ValidateAvailabilitySet(s.clusterScope.AvailabilitySetSpec(s.machineScope.Role())); err != nil {
+		return nil, errors.Wrap(err, "invalid availability set")
+	}
+
+	osDisk, err := s.machineScope.OSDisk(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid OS disk")
+	}
+
+	if err := s.machineScope.ValidateDataDisks(ctx); err != nil {
+		return nil, errors.Wrap(err, "invalid data disks")
+	}
+
+	if err := s.machineScope.ValidateDiskEncryptionSet(ctx); err != nil {
+		return nil, errors.Wrap(err, "invalid disk encryption set")
+	}
+
+	err = s.publicIPsSvc.Reconcile(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create public IPs")
 	}
 
+	err = s.inboundNatRulesSvc.Reconcile(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create inbound NAT rule")
+	}
+
 	err = s.networkInterfacesSvc.Reconcile(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create VM network interface")
 	}
 
-	vm, vmErr := s.reconcileVirtualMachine(ctx, azure.GenerateNICName(s.machineScope.Name()))
+	vm, vmErr := s.reconcileVirtualMachine(ctx, azure.GenerateNICName(s.machineScope.Name()), osDisk)
 	if vmErr != nil {
 		return nil, errors.Wrapf(vmErr, "failed to create VM %s ", s.machineScope.Name())
 	}
@@ -95,6 +131,11 @@ func (s *azureMachineService) Delete(ctx context.Context) error {
 		return errors.Wrapf(err, "Unable to delete network interface")
 	}
 
+	err = s.inboundNatRulesSvc.Delete(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete inbound NAT rule")
+	}
+
 	err = s.publicIPsSvc.Delete(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete public IPs")
@@ -108,6 +149,19 @@ func (s *azureMachineService) Delete(ctx context.Context) error {
 	return nil
 }
 
+// ReconcileDataDisks attaches the data disks added to the spec of the machine to its existing VM.
+func (s *azureMachineService) ReconcileDataDisks(ctx context.Context) error {
+	if len(s.machineScope.AzureMachine.Spec.DataDisks) == 0 {
+		return nil
+	}
+	vmSpec := &virtualmachines.Spec{
+		Name:                s.machineScope.Name(),
+		DataDisks:           s.machineScope.AzureMachine.Spec.DataDisks,
+		DiskEncryptionSetID: s.machineScope.DiskEncryptionSetID(),
+	}
+	return s.virtualMachinesSvc.ReconcileDataDisks(ctx, vmSpec)
+}
+
 func (s *azureMachineService) VMIfExists(ctx context.Context, id *string) (*infrav1.VM, error) {
 	if id == nil {
 		s.clusterScope.Info("VM does not have an ID")
@@ -185,14 +239,20 @@ func (s *azureMachineService) getVirtualMachineZone(ctx context.Context) (string
 	return selectedZone, nil
 }
 
-func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicName string) (*infrav1.VM, error) {
-	decoded, err := base64.StdEncoding.DecodeString(s.machineScope.AzureMachine.Spec.SSHPublicKey)
+func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicName string, osDisk infrav1.OSDisk) (*infrav1.VM, error) {
+	sshKeyData, err := s.machineScope.SSHPublicKey()
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to decode ssh public key")
+		return nil, errors.Wrap(err, "invalid SSH public key")
 	}
 
 	var vmZone string
 	azSupported := s.isAvailabilityZoneSupported()
+	if azSupported && s.clusterScope.LoadBalancerSKU() == infrav1.SKUBasic {
+		if s.machineScope.AvailabilityZone() != "" || s.machineScope.AzureMachine.Spec.AvailabilityZone.ID != nil {
+			return nil, errors.Errorf("availability zones require the %s load balancer SKU, cluster %s uses %s", infrav1.SKUStandard, s.clusterScope.ClusterName(), infrav1.SKUBasic)
+		}
+		azSupported = false
+	}
 	if azSupported {
 		useAZ := true
 
@@ -214,11 +274,16 @@ func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicNa
 		nicNames = append(nicNames, azure.GeneratePublicNICName(s.machineScope.Name()))
 	}
 
-	image, err := getVMImage(s.machineScope)
+	image, err := getVMImage(ctx, s.machineScope)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get VM image")
 	}
 
+	if err := s.machineScope.ValidateOSDiskSize(ctx, osDisk, image); err != nil {
+		return nil, errors.Wrap(err, "invalid OS disk")
+	}
+	s.machineScope.SetImage(image)
+
 	bootstrapData, err := s.machineScope.GetBootstrapData(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrieve bootstrap data")
@@ -227,16 +292,27 @@ func (s *azureMachineService) reconcileVirtualMachine(ctx context.Context, nicNa
 	vmSpec := &virtualmachines.Spec{
 		Name:                   s.machineScope.Name(),
 		NICNames:               nicNames,
-		SSHKeyData:             string(decoded),
+		SSHKeyData:             sshKeyData,
+		AdminUsername:          s.machineScope.AdminUsername(),
 		Size:                   s.machineScope.AzureMachine.Spec.VMSize,
-		OSDisk:                 s.machineScope.AzureMachine.Spec.OSDisk,
+		OSDisk:                 osDisk,
 		DataDisks:              s.machineScope.AzureMachine.Spec.DataDisks,
 		Image:                  image,
 		CustomData:             bootstrapData,
 		Zone:                   vmZone,
 		Identity:               s.machineScope.AzureMachine.Spec.Identity,
-		UserAssignedIdentities: s.machineScope.AzureMachine.Spec.UserAssignedIdentities,
-		SpotVMOptions:          s.machineScope.AzureMachine.Spec.SpotVMOptions,
+		UserAssignedIdentities: s.machineScope.UserAssignedIdentities(),
+		SpotVMOptions:          s.machineScope.SpotVMOptions(),
+		DedicatedHostGroupID:   s.machineScope.DedicatedHostGroupID(),
+		DedicatedHostID:        s.machineScope.DedicatedHostID(),
+		DiskEncryptionSetID:    s.machineScope.DiskEncryptionSetID(),
+		BootDiagnostics:        s.machineScope.AzureMachine.Spec.BootDiagnostics,
+	}
+	if ppg := s.clusterScope.ProximityPlacementGroupSpec(); ppg != nil {
+		vmSpec.ProximityPlacementGroupID = ppg.ID
+	}
+	if as := s.clusterScope.AvailabilitySetSpec(s.machineScope.Role()); as != nil {
+		vmSpec.AvailabilitySetID = as.ID
 	}
 
 	err = s.virtualMachinesSvc.Reconcile(ctx, vmSpec)
@@ -283,26 +359,24 @@ func GetControlPlaneMachines(machineList *clusterv1.MachineList) []*clusterv1.Ma
 // isAvailabilityZoneSupported determines if Availability Zones are supported in a selected location
 // based on SupportedAvailabilityZoneLocations. Returns true if supported.
 func (s *azureMachineService) isAvailabilityZoneSupported() bool {
-	azSupported := false
-
-	for _, supportedLocation := range azure.SupportedAvailabilityZoneLocations {
-		if s.machineScope.Location() == supportedLocation {
-			azSupported = true
-
-			return azSupported
-		}
+	if azure.SupportsAvailabilityZones(s.machineScope.Location()) {
+		return true
 	}
 
 	s.machineScope.V(2).Info("Availability Zones are not supported in the selected location", "location", s.machineScope.Location())
-	return azSupported
+	return false
 }
 
-// Pick image from the machine configuration, or use a default one.
-func getVMImage(scope *scope.MachineScope) (*infrav1.Image, error) {
+// Pick image from the machine configuration, then from the default image of the cluster, or use a default one. A
+// shared image gallery image is resolved to an existing version.
+func getVMImage(ctx context.Context, scope *scope.MachineScope) (*infrav1.Image, error) {
 	// Use custom Marketplace image, Image ID or a Shared Image Gallery image if provided
-	if scope.AzureMachine.Spec.Image != nil {
-		return scope.AzureMachine.Spec.Image, nil
+	if image := scope.Image(); image != nil {
+		return scope.ResolveImage(ctx, image)
 	}
 	scope.Info("No image specified for machine, using default", "machine", scope.AzureMachine.GetName())
+	if scope.AzureMachine.Spec.OSDisk.OSType == infrav1.WindowsOS {
+		return azure.GetDefaultWindowsImage(to.String(scope.Machine.Spec.Version))
+	}
 	return azure.GetDefaultUbuntuImage(to.String(scope.Machine.Spec.Version))
 }
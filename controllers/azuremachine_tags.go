@@ -42,8 +42,9 @@ func (r *AzureMachineReconciler) reconcileTags(ctx context.Context, machineScope
 		return err
 	}
 	changed, created, deleted, newAnnotation := TagsChanged(annotation, additionalTags)
-	if changed {
-		machineScope.Info("Updating tags on AzureMachine")
+	// The enforced tags are checked on every reconcile, as they may have been removed from the VM out-of-band.
+	enforcedTags := clusterScope.EnforcedTags()
+	if changed || len(enforcedTags) > 0 {
 		vmSpec := &virtualmachines.Spec{
 			Name: machineScope.Name(),
 		}
@@ -53,6 +54,9 @@ func (r *AzureMachineReconciler) reconcileTags(ctx context.Context, machineScope
 			return errors.Wrapf(err, "failed to query AzureMachine VM")
 		}
 		tags := vm.Tags
+		if tags == nil {
+			tags = make(map[string]*string)
+		}
 		for k, v := range created {
 			tags[k] = to.StringPtr(v)
 		}
@@ -61,20 +65,45 @@ func (r *AzureMachineReconciler) reconcileTags(ctx context.Context, machineScope
 			delete(tags, k)
 		}
 
+		enforced := EnforceTags(tags, enforcedTags)
+		if !changed && !enforced {
+			return nil
+		}
+		if enforced {
+			machineScope.Info("Setting enforced tags removed or changed out-of-band on AzureMachine")
+		} else {
+			machineScope.Info("Updating tags on AzureMachine")
+		}
+
 		vm.Tags = tags
 		if err := svc.Client.CreateOrUpdate(ctx, clusterScope.ResourceGroup(), vmSpec.Name, vm); err != nil {
 			return errors.Wrapf(err, "cannot update VM tags")
 		}
 
 		// We also need to update the annotation if anything changed.
-		if err = r.updateMachineAnnotationJSON(machineScope.AzureMachine, TagsLastAppliedAnnotation, newAnnotation); err != nil {
-			return err
+		if changed {
+			if err = r.updateMachineAnnotationJSON(machineScope.AzureMachine, TagsLastAppliedAnnotation, newAnnotation); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// EnforceTags sets the enforced tags which are missing from the tags of a resource, or have another value, and
+// reports whether any was set.
+func EnforceTags(tags map[string]*string, enforced map[string]string) bool {
+	changed := false
+	for k, v := range enforced {
+		if current, ok := tags[k]; !ok || to.String(current) != v {
+			tags[k] = to.StringPtr(v)
+			changed = true
+		}
+	}
+	return changed
+}
+
 // TagsChanged determines which tags to delete and which to add.
 func TagsChanged(annotation map[string]interface{}, src map[string]string) (bool, map[string]string, map[string]string, map[string]interface{}) {
 	// Bool tracking if we found any changed state.
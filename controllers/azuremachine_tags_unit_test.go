@@ -19,6 +19,7 @@ package controllers
 import (
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
 )
 
@@ -118,3 +119,46 @@ func TestTagsChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestEnforceTags(t *testing.T) {
+	g := NewWithT(t)
+
+	var tests = map[string]struct {
+		tags           map[string]*string
+		enforced       map[string]string
+		expectedResult bool
+		expectedTags   map[string]*string
+	}{
+		"enforced tags are present": {
+			tags:           map[string]*string{"costCenter": to.StringPtr("1234"), "foo": to.StringPtr("bar")},
+			enforced:       map[string]string{"costCenter": "1234"},
+			expectedResult: false,
+			expectedTags:   map[string]*string{"costCenter": to.StringPtr("1234"), "foo": to.StringPtr("bar")},
+		},
+		"enforced tag removed out-of-band": {
+			tags:           map[string]*string{"foo": to.StringPtr("bar")},
+			enforced:       map[string]string{"costCenter": "1234", "env": "prod"},
+			expectedResult: true,
+			expectedTags:   map[string]*string{"costCenter": to.StringPtr("1234"), "env": to.StringPtr("prod"), "foo": to.StringPtr("bar")},
+		},
+		"enforced tag changed out-of-band": {
+			tags:           map[string]*string{"costCenter": to.StringPtr("5678")},
+			enforced:       map[string]string{"costCenter": "1234"},
+			expectedResult: true,
+			expectedTags:   map[string]*string{"costCenter": to.StringPtr("1234")},
+		},
+		"no enforced tags": {
+			tags:           map[string]*string{"foo": to.StringPtr("bar")},
+			expectedResult: false,
+			expectedTags:   map[string]*string{"foo": to.StringPtr("bar")},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			g.Expect(EnforceTags(test.tags, test.enforced)).To(Equal(test.expectedResult))
+			g.Expect(test.tags).To(Equal(test.expectedTags))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/services/virtualmachineextensions"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// VMExtensionsLastAppliedAnnotation is the key for the machine object annotation
+	// which tracks the extensions that the machine actuator installed on the VM.
+	// Only these extensions are uninstalled from the VM when they are removed from the AzureMachine,
+	// the extensions installed on the VM by other means are left untouched.
+	VMExtensionsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-vm-extensions"
+)
+
+// Ensure that the extensions of an existing machine are installed and provisioned
+func (r *AzureMachineReconciler) reconcileVMExtensions(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) error {
+	annotation, err := r.machineAnnotationJSON(machineScope.AzureMachine, VMExtensionsLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	extensions := machineScope.AzureMachine.Spec.VMExtensions
+	removed, newAnnotation := VMExtensionsRemoved(annotation, extensions)
+	if len(extensions) == 0 && len(removed) == 0 {
+		machineScope.AzureMachine.Status.VMExtensions = nil
+		conditions.Delete(machineScope.AzureMachine, infrav1.VMExtensionsReadyCondition)
+		return nil
+	}
+
+	svc := virtualmachineextensions.NewService(clusterScope)
+	statuses, err := svc.ReconcileVMExtensions(ctx, &virtualmachineextensions.Spec{
+		VMName:     machineScope.Name(),
+		Extensions: extensions,
+		Removed:    removed,
+	})
+	machineScope.AzureMachine.Status.VMExtensions = statuses
+	if err != nil {
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMExtensionsReadyCondition, infrav1.VMExtensionProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		// Keep tracking the removed extensions, their deletion is retried on the next reconcile.
+		for _, name := range removed {
+			newAnnotation[name] = true
+		}
+	} else {
+		conditions.MarkTrue(machineScope.AzureMachine, infrav1.VMExtensionsReadyCondition)
+	}
+
+	if annotationErr := r.updateMachineAnnotationJSON(machineScope.AzureMachine, VMExtensionsLastAppliedAnnotation, newAnnotation); annotationErr != nil {
+		return annotationErr
+	}
+	return err
+}
+
+// VMExtensionsRemoved determines which extensions installed by the last reconcile were removed from the spec, and the
+// new value of the annotation tracking the installed extensions.
+func VMExtensionsRemoved(annotation map[string]interface{}, extensions []infrav1.VMExtension) ([]string, map[string]interface{}) {
+	removed := []string{}
+	newAnnotation := map[string]interface{}{}
+
+	for _, extension := range extensions {
+		newAnnotation[extension.Name] = true
+	}
+	for name := range annotation {
+		if _, ok := newAnnotation[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	return removed, newAnnotation
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+)
+
+func TestVMExtensionsRemoved(t *testing.T) {
+	g := NewWithT(t)
+
+	var tests = map[string]struct {
+		annotation             map[string]interface{}
+		extensions             []infrav1.VMExtension
+		expectedRemoved        []string
+		expectedNewAnnotations map[string]interface{}
+	}{
+		"extensions are the same": {
+			annotation:             map[string]interface{}{"ext1": true},
+			extensions:             []infrav1.VMExtension{{Name: "ext1"}},
+			expectedRemoved:        []string{},
+			expectedNewAnnotations: map[string]interface{}{"ext1": true},
+		}, "extension added": {
+			annotation:             map[string]interface{}{"ext1": true},
+			extensions:             []infrav1.VMExtension{{Name: "ext1"}, {Name: "ext2"}},
+			expectedRemoved:        []string{},
+			expectedNewAnnotations: map[string]interface{}{"ext1": true, "ext2": true},
+		}, "extensions removed": {
+			annotation:             map[string]interface{}{"ext1": true, "ext3": true, "ext2": true},
+			extensions:             []infrav1.VMExtension{{Name: "ext1"}},
+			expectedRemoved:        []string{"ext2", "ext3"},
+			expectedNewAnnotations: map[string]interface{}{"ext1": true},
+		}, "nothing applied yet": {
+			annotation:             nil,
+			extensions:             []infrav1.VMExtension{{Name: "ext1"}},
+			expectedRemoved:        []string{},
+			expectedNewAnnotations: map[string]interface{}{"ext1": true},
+		},
+	}
+
+	for name, test := range tests {
+		removed, newAnnotation := VMExtensionsRemoved(test.annotation, test.extensions)
+		g.Expect(removed).To(Equal(test.expectedRemoved), name)
+		g.Expect(newAnnotation).To(Equal(test.expectedNewAnnotations), name)
+	}
+}
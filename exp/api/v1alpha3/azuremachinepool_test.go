@@ -18,8 +18,11 @@ package v1alpha3_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
@@ -79,6 +82,136 @@ func TestAzureMachinePool_Validate(t *testing.T) {
 				g.Expect(actual.Error()).To(gomega.ContainSubstring("You must supply a ID, Marketplace or SharedGallery image details"))
 			},
 		},
+		{
+			Name: "HasValidRollingUpgradePolicy",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						UpgradePolicy: &exp.UpgradePolicy{
+							Mode: exp.UpgradeModeRolling,
+							RollingUpgradePolicy: &exp.RollingUpgradePolicy{
+								MaxBatchInstancePercent:             to.Int32Ptr(50),
+								MaxUnhealthyInstancePercent:         to.Int32Ptr(50),
+								MaxUnhealthyUpgradedInstancePercent: to.Int32Ptr(0),
+								PauseTimeBetweenBatches:             &metav1.Duration{Duration: 30 * time.Second},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).ToNot(gomega.HaveOccurred())
+			},
+		},
+		{
+			Name: "HasRollingUpgradePolicyWithoutRollingMode",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						UpgradePolicy: &exp.UpgradePolicy{
+							Mode:                 exp.UpgradeModeAutomatic,
+							RollingUpgradePolicy: &exp.RollingUpgradePolicy{MaxBatchInstancePercent: to.Int32Ptr(50)},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("a rolling upgrade policy can only be set in the Rolling mode"))
+			},
+		},
+		{
+			Name: "HasInvalidBatchPercent",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						UpgradePolicy: &exp.UpgradePolicy{
+							Mode:                 exp.UpgradeModeRolling,
+							RollingUpgradePolicy: &exp.RollingUpgradePolicy{MaxBatchInstancePercent: to.Int32Ptr(2)},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("upgradePolicy.rollingUpgradePolicy.maxBatchInstancePercent: Invalid value: 2: must be between 5 and 100"))
+			},
+		},
+		{
+			Name: "HasInvalidPause",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						UpgradePolicy: &exp.UpgradePolicy{
+							Mode: exp.UpgradeModeRolling,
+							RollingUpgradePolicy: &exp.RollingUpgradePolicy{
+								PauseTimeBetweenBatches: &metav1.Duration{Duration: 1500 * time.Millisecond},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("must be a whole number of seconds"))
+			},
+		},
+		{
+			Name: "HasValidTerminateNotification",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Template: exp.AzureMachineTemplate{
+							TerminateNotification: &exp.TerminateNotification{
+								Enabled:          true,
+								NotBeforeTimeout: &metav1.Duration{Duration: 15 * time.Minute},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).ToNot(gomega.HaveOccurred())
+			},
+		},
+		{
+			Name: "HasTerminateNotificationTimeoutOutOfRange",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Template: exp.AzureMachineTemplate{
+							TerminateNotification: &exp.TerminateNotification{
+								Enabled:          true,
+								NotBeforeTimeout: &metav1.Duration{Duration: 20 * time.Minute},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("terminateNotification.notBeforeTimeout: Invalid value: \"20m0s\": must be between 5m0s and 15m0s"))
+			},
+		},
+		{
+			Name: "HasTerminateNotificationTimeoutInSeconds",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Template: exp.AzureMachineTemplate{
+							TerminateNotification: &exp.TerminateNotification{
+								Enabled:          true,
+								NotBeforeTimeout: &metav1.Duration{Duration: 450 * time.Second},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("must be a whole number of minutes"))
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -17,13 +17,88 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 )
 
+const (
+	// UpgradeModeManual upgrades the instances of a scale set to its latest model only when they are manually
+	// upgraded or reimaged.
+	UpgradeModeManual UpgradeMode = "Manual"
+	// UpgradeModeAutomatic upgrades all the instances of a scale set to its latest model at the same time.
+	UpgradeModeAutomatic UpgradeMode = "Automatic"
+	// UpgradeModeRolling upgrades the instances of a scale set to its latest model in batches.
+	UpgradeModeRolling UpgradeMode = "Rolling"
+
+	// MinTerminateNotificationTimeout is the shortest timeout of the Terminate scheduled event Azure allows.
+	MinTerminateNotificationTimeout = 5 * time.Minute
+	// MaxTerminateNotificationTimeout is the longest timeout of the Terminate scheduled event Azure allows.
+	MaxTerminateNotificationTimeout = 15 * time.Minute
+)
+
 type (
+	// UpgradeMode is the mode of the upgrades of the instances of a scale set to its latest model.
+	UpgradeMode string
+
+	// UpgradePolicy configures how the instances of a scale set are upgraded to its latest model, e.g. after a change
+	// of its image.
+	UpgradePolicy struct {
+		// Mode is the upgrade mode of the instances. Defaults to Manual.
+		// +kubebuilder:validation:Enum=Manual;Automatic;Rolling
+		// +optional
+		Mode UpgradeMode `json:"mode,omitempty"`
+
+		// RollingUpgradePolicy configures the batches of the upgrades in the Rolling mode.
+		// +optional
+		RollingUpgradePolicy *RollingUpgradePolicy `json:"rollingUpgradePolicy,omitempty"`
+	}
+
+	// RollingUpgradePolicy configures the batches of a rolling upgrade. Unset fields keep the Azure defaults.
+	RollingUpgradePolicy struct {
+		// MaxBatchInstancePercent is the maximum percentage of the instances upgraded at the same time in a batch,
+		// from 5 to 100. Defaults to 20.
+		// +kubebuilder:validation:Minimum=5
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		MaxBatchInstancePercent *int32 `json:"maxBatchInstancePercent,omitempty"`
+
+		// MaxUnhealthyInstancePercent is the maximum percentage of the instances which can be unhealthy at the same
+		// time, being upgraded or not, before the upgrade is aborted, from 5 to 100. Defaults to 20.
+		// +kubebuilder:validation:Minimum=5
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		MaxUnhealthyInstancePercent *int32 `json:"maxUnhealthyInstancePercent,omitempty"`
+
+		// MaxUnhealthyUpgradedInstancePercent is the maximum percentage of the upgraded instances which can be
+		// unhealthy after a batch before the upgrade is aborted, from 0 to 100. Defaults to 20.
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		MaxUnhealthyUpgradedInstancePercent *int32 `json:"maxUnhealthyUpgradedInstancePercent,omitempty"`
+
+		// PauseTimeBetweenBatches is the time waited between the end of a batch and the start of the next one,
+		// in whole seconds. Defaults to 0.
+		// +optional
+		PauseTimeBetweenBatches *metav1.Duration `json:"pauseTimeBetweenBatches,omitempty"`
+	}
+
+	// TerminateNotification configures the Terminate scheduled event of the instances of a scale set, which notifies
+	// them before they are deleted, e.g. when the scale set is scaled in or the instance is evicted.
+	TerminateNotification struct {
+		// Enabled enables the Terminate scheduled event.
+		Enabled bool `json:"enabled"`
+
+		// NotBeforeTimeout is how long an instance has to approve the Terminate scheduled event, e.g. once its node
+		// is drained, before the event is approved automatically and the instance deleted, in whole minutes from 5
+		// to 15. Defaults to 5 minutes.
+		// +optional
+		NotBeforeTimeout *metav1.Duration `json:"notBeforeTimeout,omitempty"`
+	}
+
 	AzureMachineTemplate struct {
 		// VMSize is the size of the Virtual Machine to build.
 		// See https://docs.microsoft.com/en-us/rest/api/compute/virtualmachines/createorupdate#virtualmachinesizetypes
@@ -46,11 +121,31 @@ type (
 		// SSHPublicKey is the SSH public key string base64 encoded to add to a Virtual Machine
 		SSHPublicKey string `json:"sshPublicKey"`
 
-		// AcceleratedNetworking enables or disables Azure accelerated networking. If omitted, it will be set based on
-		// whether the requested VMSize supports accelerated networking.
-		// If AcceleratedNetworking is set to true with a VMSize that does not support it, Azure will return an error.
+		// AdminUsername is the username of the admin user of the scale set instances, whose authorized_keys holds
+		// the SSH public key. It can't be one of the usernames Azure disallows, such as admin or root, and can be up
+		// to 64 characters long. Defaults to capi.
+		// +optional
+		AdminUsername string `json:"adminUsername,omitempty"`
+
+		// AcceleratedNetworking enables or disables Azure accelerated networking. If omitted, the default of the cluster
+		// is used, or it will be set based on whether the requested VMSize supports accelerated networking.
+		// If AcceleratedNetworking is enabled with a VMSize that does not support it, the scale set fails to be created.
 		// +optional
 		AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+
+		// SpotVMOptions allows the ability to specify the scale set instances should be Spot VMs
+		// +optional
+		SpotVMOptions *infrav1.SpotVMOptions `json:"spotVMOptions,omitempty"`
+
+		// DiskEncryptionSetID is the resource ID of the disk encryption set encrypting the OS and data disks of the
+		// scale set instances with a customer-managed key. Defaults to the disk encryption set of the cluster.
+		// +optional
+		DiskEncryptionSetID string `json:"diskEncryptionSetID,omitempty"`
+
+		// TerminateNotification configures the Terminate scheduled event of the scale set instances, which lets a
+		// termination handler on the instances cordon and drain their node before they are deleted.
+		// +optional
+		TerminateNotification *TerminateNotification `json:"terminateNotification,omitempty"`
 	}
 
 	// AzureMachinePoolSpec defines the desired state of AzureMachinePool
@@ -74,6 +169,11 @@ type (
 		// This field must match the provider IDs as seen on the node objects corresponding to a machine pool's machine instances.
 		// +optional
 		ProviderIDList []string `json:"providerIDList,omitempty"`
+
+		// UpgradePolicy configures how the instances of the scale set are upgraded to its latest model, e.g. when its
+		// image changes. Defaults to the Manual mode.
+		// +optional
+		UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
 	}
 
 	// AzureMachinePoolStatus defines the observed state of AzureMachinePool
@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"fmt"
+	"time"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -71,6 +74,11 @@ func (amp *AzureMachinePool) ValidateDelete() error {
 func (amp *AzureMachinePool) Validate() error {
 	validators := []func() error{
 		amp.ValidateImage,
+		amp.ValidateSpotVMOptions,
+		amp.ValidateDiskEncryptionSetID,
+		amp.ValidateAdminUsername,
+		amp.ValidateUpgradePolicy,
+		amp.ValidateTerminateNotification,
 	}
 
 	var errs []error
@@ -99,3 +107,112 @@ func (amp *AzureMachinePool) ValidateImage() error {
 	}
 	return nil
 }
+
+// ValidateSpotVMOptions of an AzureMachinePool
+func (amp *AzureMachinePool) ValidateSpotVMOptions() error {
+	if errs := infrav1.ValidateSpotVMOptions(amp.Spec.Template.SpotVMOptions, field.NewPath("spotVMOptions")); len(errs) > 0 {
+		agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
+		azuremachinepoollog.Info("Invalid Spot VM options: %s", agg.Error())
+		return agg
+	}
+	return nil
+}
+
+// ValidateDiskEncryptionSetID of an AzureMachinePool
+func (amp *AzureMachinePool) ValidateDiskEncryptionSetID() error {
+	if errs := infrav1.ValidateDiskEncryptionSetID(amp.Spec.Template.DiskEncryptionSetID, field.NewPath("diskEncryptionSetID")); len(errs) > 0 {
+		agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
+		azuremachinepoollog.Info("Invalid disk encryption set ID: %s", agg.Error())
+		return agg
+	}
+	return nil
+}
+
+// ValidateAdminUsername of an AzureMachinePool
+func (amp *AzureMachinePool) ValidateAdminUsername() error {
+	if errs := infrav1.ValidateAdminUsername(amp.Spec.Template.AdminUsername, amp.Spec.Template.OSDisk.OSType, field.NewPath("adminUsername")); len(errs) > 0 {
+		agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
+		azuremachinepoollog.Info("Invalid admin username: %s", agg.Error())
+		return agg
+	}
+	return nil
+}
+
+// ValidateUpgradePolicy of an AzureMachinePool
+func (amp *AzureMachinePool) ValidateUpgradePolicy() error {
+	if errs := validateUpgradePolicy(amp.Spec.UpgradePolicy, field.NewPath("upgradePolicy")); len(errs) > 0 {
+		agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
+		azuremachinepoollog.Info("Invalid upgrade policy: %s", agg.Error())
+		return agg
+	}
+	return nil
+}
+
+// validateUpgradePolicy validates the mode of an upgrade policy, and the batches of a rolling upgrade policy.
+func validateUpgradePolicy(policy *UpgradePolicy, fldPath *field.Path) field.ErrorList {
+	if policy == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	switch policy.Mode {
+	case "", UpgradeModeManual, UpgradeModeAutomatic, UpgradeModeRolling:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mode"), policy.Mode,
+			[]string{string(UpgradeModeManual), string(UpgradeModeAutomatic), string(UpgradeModeRolling)}))
+	}
+	rolling := policy.RollingUpgradePolicy
+	if rolling == nil {
+		return allErrs
+	}
+	rollingPath := fldPath.Child("rollingUpgradePolicy")
+	if policy.Mode != UpgradeModeRolling {
+		allErrs = append(allErrs, field.Forbidden(rollingPath,
+			fmt.Sprintf("a rolling upgrade policy can only be set in the %s mode", UpgradeModeRolling)))
+	}
+	allErrs = append(allErrs, validatePercent(rolling.MaxBatchInstancePercent, 5, rollingPath.Child("maxBatchInstancePercent"))...)
+	allErrs = append(allErrs, validatePercent(rolling.MaxUnhealthyInstancePercent, 5, rollingPath.Child("maxUnhealthyInstancePercent"))...)
+	allErrs = append(allErrs, validatePercent(rolling.MaxUnhealthyUpgradedInstancePercent, 0, rollingPath.Child("maxUnhealthyUpgradedInstancePercent"))...)
+	if pause := rolling.PauseTimeBetweenBatches; pause != nil {
+		if pause.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(rollingPath.Child("pauseTimeBetweenBatches"), pause.Duration.String(), "must not be negative"))
+		} else if pause.Duration%time.Second != 0 {
+			allErrs = append(allErrs, field.Invalid(rollingPath.Child("pauseTimeBetweenBatches"), pause.Duration.String(), "must be a whole number of seconds"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateTerminateNotification of an AzureMachinePool
+func (amp *AzureMachinePool) ValidateTerminateNotification() error {
+	if errs := validateTerminateNotification(amp.Spec.Template.TerminateNotification, field.NewPath("terminateNotification")); len(errs) > 0 {
+		agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
+		azuremachinepoollog.Info("Invalid terminate notification: %s", agg.Error())
+		return agg
+	}
+	return nil
+}
+
+// validateTerminateNotification validates the timeout of a terminate notification, in whole minutes within the range
+// Azure allows.
+func validateTerminateNotification(notification *TerminateNotification, fldPath *field.Path) field.ErrorList {
+	if notification == nil || notification.NotBeforeTimeout == nil {
+		return nil
+	}
+	timeout := notification.NotBeforeTimeout.Duration
+	if timeout < MinTerminateNotificationTimeout || timeout > MaxTerminateNotificationTimeout {
+		return field.ErrorList{field.Invalid(fldPath.Child("notBeforeTimeout"), timeout.String(),
+			fmt.Sprintf("must be between %s and %s", MinTerminateNotificationTimeout, MaxTerminateNotificationTimeout))}
+	}
+	if timeout%time.Minute != 0 {
+		return field.ErrorList{field.Invalid(fldPath.Child("notBeforeTimeout"), timeout.String(), "must be a whole number of minutes")}
+	}
+	return nil
+}
+
+// validatePercent validates an optional percentage, between min and 100.
+func validatePercent(percent *int32, min int32, fldPath *field.Path) field.ErrorList {
+	if percent == nil || (*percent >= min && *percent <= 100) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, *percent, fmt.Sprintf("must be between %d and 100", min))}
+}
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/errors"
@@ -101,6 +102,11 @@ func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(UpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolSpec.
@@ -151,7 +157,7 @@ func (in *AzureMachineTemplate) DeepCopyInto(out *AzureMachineTemplate) {
 		*out = new(apiv1alpha3.Image)
 		(*in).DeepCopyInto(*out)
 	}
-	out.OSDisk = in.OSDisk
+	in.OSDisk.DeepCopyInto(&out.OSDisk)
 	if in.DataDisks != nil {
 		in, out := &in.DataDisks, &out.DataDisks
 		*out = make([]apiv1alpha3.DataDisk, len(*in))
@@ -164,6 +170,16 @@ func (in *AzureMachineTemplate) DeepCopyInto(out *AzureMachineTemplate) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SpotVMOptions != nil {
+		in, out := &in.SpotVMOptions, &out.SpotVMOptions
+		*out = new(apiv1alpha3.SpotVMOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerminateNotification != nil {
+		in, out := &in.TerminateNotification, &out.TerminateNotification
+		*out = new(TerminateNotification)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineTemplate.
@@ -483,6 +499,81 @@ func (in *AzureManagedMachinePoolStatus) DeepCopy() *AzureManagedMachinePoolStat
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpgradePolicy) DeepCopyInto(out *RollingUpgradePolicy) {
+	*out = *in
+	if in.MaxBatchInstancePercent != nil {
+		in, out := &in.MaxBatchInstancePercent, &out.MaxBatchInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnhealthyInstancePercent != nil {
+		in, out := &in.MaxUnhealthyInstancePercent, &out.MaxUnhealthyInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnhealthyUpgradedInstancePercent != nil {
+		in, out := &in.MaxUnhealthyUpgradedInstancePercent, &out.MaxUnhealthyUpgradedInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PauseTimeBetweenBatches != nil {
+		in, out := &in.PauseTimeBetweenBatches, &out.PauseTimeBetweenBatches
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpgradePolicy.
+func (in *RollingUpgradePolicy) DeepCopy() *RollingUpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerminateNotification) DeepCopyInto(out *TerminateNotification) {
+	*out = *in
+	if in.NotBeforeTimeout != nil {
+		in, out := &in.NotBeforeTimeout, &out.NotBeforeTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerminateNotification.
+func (in *TerminateNotification) DeepCopy() *TerminateNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(TerminateNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePolicy) DeepCopyInto(out *UpgradePolicy) {
+	*out = *in
+	if in.RollingUpgradePolicy != nil {
+		in, out := &in.RollingUpgradePolicy, &out.RollingUpgradePolicy
+		*out = new(RollingUpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicy.
+func (in *UpgradePolicy) DeepCopy() *UpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMSS) DeepCopyInto(out *VMSS) {
 	*out = *in
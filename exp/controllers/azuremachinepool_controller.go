@@ -18,7 +18,6 @@ package controllers
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -395,7 +394,9 @@ func (r *AzureMachinePoolReconciler) reconcileTags(ctx context.Context, machineP
 		return err
 	}
 	changed, created, deleted, newAnnotation := controllers.TagsChanged(annotation, additionalTags)
-	if changed {
+	// The enforced tags are checked on every reconcile, as they may have been removed from the VMSS out-of-band.
+	enforcedTags := clusterScope.EnforcedTags()
+	if changed || len(enforcedTags) > 0 {
 		vmssSpec := &scalesets.Spec{
 			Name: machinePoolScope.Name(),
 		}
@@ -405,6 +406,9 @@ func (r *AzureMachinePoolReconciler) reconcileTags(ctx context.Context, machineP
 			return errors.Wrapf(err, "failed to query AzureMachine VMSS")
 		}
 		tags := vm.Tags
+		if tags == nil {
+			tags = make(map[string]*string)
+		}
 		for k, v := range created {
 			tags[k] = to.StringPtr(v)
 		}
@@ -413,15 +417,21 @@ func (r *AzureMachinePoolReconciler) reconcileTags(ctx context.Context, machineP
 			delete(tags, k)
 		}
 
+		if enforced := controllers.EnforceTags(tags, enforcedTags); !changed && !enforced {
+			return nil
+		}
+
 		vm.Tags = tags
 		if err := svc.Client.CreateOrUpdate(ctx, clusterScope.ResourceGroup(), vmssSpec.Name, vm); err != nil {
 			return errors.Wrapf(err, "cannot update VMSS tags")
 		}
 
 		// We also need to update the annotation if anything changed.
-		err = r.updateAnnotationJSON(machinePoolScope.AzureMachinePool, controllers.TagsLastAppliedAnnotation, newAnnotation)
-		if err != nil {
-			return err
+		if changed {
+			err = r.updateAnnotationJSON(machinePoolScope.AzureMachinePool, controllers.TagsLastAppliedAnnotation, newAnnotation)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -490,43 +500,72 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope, cluste
 
 func (s *azureMachinePoolService) CreateOrUpdate(ctx context.Context) (*infrav1exp.VMSS, error) {
 	ampSpec := s.machinePoolScope.AzureMachinePool.Spec
-	var replicas int64
-	if s.machinePoolScope.MachinePool.Spec.Replicas != nil {
-		replicas = int64(to.Int32(s.machinePoolScope.MachinePool.Spec.Replicas))
+	scaleSetSpec := s.machinePoolScope.ScaleSetSpec()
+
+	ppg := s.clusterScope.ProximityPlacementGroupSpec()
+	if err := s.machinePoolScope.ValidateProximityPlacementGroup(ppg); err != nil {
+		return nil, errors.Wrap(err, "invalid proximity placement group")
+	}
+
+	if err := s.machinePoolScope.ValidateDiskEncryptionSet(ctx); err != nil {
+		return nil, errors.Wrap(err, "invalid disk encryption set")
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(ampSpec.Template.SSHPublicKey)
+	if ampSpec.Template.OSDisk.OSType == infrav1.WindowsOS {
+		return nil, errors.New("scale sets of Windows instances are not supported")
+	}
+
+	sshKeyData, err := s.machinePoolScope.SSHPublicKey()
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to base64 decode ssh public key")
+		return nil, errors.Wrap(err, "invalid SSH public key")
 	}
 
-	image, err := getVMImage(s.machinePoolScope)
+	image, err := getVMImage(ctx, s.machinePoolScope)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get VMSS image")
 	}
 
+	osDisk, err := s.machinePoolScope.OSDisk(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid OS disk")
+	}
+
+	if err := s.machinePoolScope.ValidateOSDiskSize(ctx, osDisk, image); err != nil {
+		return nil, errors.Wrap(err, "invalid OS disk")
+	}
+
 	bootstrapData, err := s.machinePoolScope.GetBootstrapData(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrieve bootstrap data")
 	}
 
 	vmssSpec := &scalesets.Spec{
-		Name:                   s.machinePoolScope.Name(),
+		Name:                   scaleSetSpec.Name,
 		ResourceGroup:          s.clusterScope.ResourceGroup(),
 		Location:               s.clusterScope.Location(),
 		ClusterName:            s.clusterScope.ClusterName(),
 		MachinePoolName:        s.machinePoolScope.Name(),
-		Sku:                    ampSpec.Template.VMSize,
-		Capacity:               replicas,
-		SSHKeyData:             string(decoded),
+		Sku:                    scaleSetSpec.Size,
+		Capacity:               scaleSetSpec.Capacity,
+		Zones:                  scaleSetSpec.Zones,
+		SSHKeyData:             sshKeyData,
+		AdminUsername:          s.machinePoolScope.AdminUsername(),
 		Image:                  image,
-		OSDisk:                 ampSpec.Template.OSDisk,
+		OSDisk:                 osDisk,
 		DataDisks:              ampSpec.Template.DataDisks,
 		CustomData:             bootstrapData,
 		AdditionalTags:         s.machinePoolScope.AdditionalTags(),
-		SubnetID:               s.clusterScope.AzureCluster.Spec.NetworkSpec.GetNodeSubnet().ID,
-		PublicLoadBalancerName: s.clusterScope.ClusterName(),
-		AcceleratedNetworking:  ampSpec.Template.AcceleratedNetworking,
+		SubnetID:               scaleSetSpec.SubnetID,
+		PublicLoadBalancerName: scaleSetSpec.PublicLoadBalancerName,
+		NetworkResourceGroup:   s.clusterScope.NetworkResourceGroup(),
+		AcceleratedNetworking:  scaleSetSpec.AcceleratedNetworking,
+		SpotVMOptions:          scaleSetSpec.SpotVMOptions,
+		DiskEncryptionSetID:    s.machinePoolScope.DiskEncryptionSetID(),
+		UpgradePolicy:          ampSpec.UpgradePolicy,
+		TerminateNotification:  ampSpec.Template.TerminateNotification,
+	}
+	if ppg != nil {
+		vmssSpec.ProximityPlacementGroupID = ppg.ID
 	}
 
 	err = s.virtualMachinesScaleSetSvc.Reconcile(ctx, vmssSpec)
@@ -610,11 +649,12 @@ func getMachinePoolByName(ctx context.Context, c client.Client, namespace, name
 	return m, nil
 }
 
-// Pick image from the machine configuration, or use a default one.
-func getVMImage(scope *scope.MachinePoolScope) (*infrav1.Image, error) {
+// Pick image from the machine configuration, then from the default image of the cluster, or use a default one. A
+// shared image gallery image is resolved to an existing version.
+func getVMImage(ctx context.Context, scope *scope.MachinePoolScope) (*infrav1.Image, error) {
 	// Use custom Marketplace image, Image ID or a Shared Image Gallery image if provided
-	if scope.AzureMachinePool.Spec.Template.Image != nil {
-		return scope.AzureMachinePool.Spec.Template.Image, nil
+	if image := scope.Image(); image != nil {
+		return scope.ResolveImage(ctx, image)
 	}
 	scope.Info("No image specified for machine pool, using default", "machinePool", scope.AzureMachinePool.GetName())
 	return azure.GetDefaultUbuntuImage(to.String(scope.MachinePool.Spec.Template.Spec.Version))
@@ -31,6 +31,14 @@ const (
 	// owner: @alexeldeib
 	// alpha: v0.4
 	AKS featuregate.Feature = "AKS"
+
+	// owner: @cnadolny
+	// alpha: v0.4
+	IPv6DualStack featuregate.Feature = "IPv6DualStack"
+
+	// owner: @cnadolny
+	// alpha: v0.4
+	VCPUQuotaCheck featuregate.Feature = "VCPUQuotaCheck"
 )
 
 func init() {
@@ -41,5 +49,7 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPZFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	AKS: {Default: false, PreRelease: featuregate.Alpha},
+	AKS:            {Default: false, PreRelease: featuregate.Alpha},
+	IPv6DualStack:  {Default: false, PreRelease: featuregate.Alpha},
+	VCPUQuotaCheck: {Default: false, PreRelease: featuregate.Alpha},
 }
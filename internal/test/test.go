@@ -18,7 +18,11 @@ package test
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"k8s.io/apimachinery/pkg/util/rand"
 )
 
@@ -26,3 +30,29 @@ import (
 func RandomName(prefix string, len int) string {
 	return fmt.Sprintf("%s-%s", prefix, rand.String(len))
 }
+
+// NewPutFuture returns the future of a create or update operation in progress on the resource, polled at the
+// operation URL.
+func NewPutFuture(resourceURL, operationURL string) (azureautorest.Future, error) {
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{},
+		Request:    httptest.NewRequest(http.MethodPut, resourceURL, nil),
+	}
+	resp.Header.Set("Azure-AsyncOperation", operationURL)
+	return azureautorest.NewFutureFromResponse(resp)
+}
+
+// SetEnv sets an environment variable and returns the function restoring its previous value, so the tests don't leak
+// their environment into each other.
+func SetEnv(key, value string) func() {
+	previous, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
@@ -37,6 +37,7 @@ import (
 
 	infrav1alpha2 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha2"
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-azure/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1alpha3exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 	infrav1controllersexp "sigs.k8s.io/cluster-api-provider-azure/exp/controllers"
@@ -175,6 +176,13 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	identityType, err := scope.GetIdentityType()
+	if err != nil {
+		setupLog.Error(err, "unable to select Azure identity")
+		os.Exit(1)
+	}
+	setupLog.Info("Authenticating to Azure", "identity-type", identityType)
+
 	// Machine and cluster operations can create enough events to trigger the event recorder spam filter
 	// Setting the burst size higher ensures all events will be recorded and submitted to the API
 	broadcaster := cgrecord.NewBroadcasterWithCorrelatorOptions(cgrecord.CorrelatorOptions{